@@ -0,0 +1,48 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/traefik/paerser/cli"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/preflight"
+)
+
+// NewCmd builds a new Preflight command.
+func NewCmd(traefikConfiguration *static.Configuration, loaders []cli.ResourceLoader) *cli.Command {
+	return &cli.Command{
+		Name:          "preflight",
+		Description:   `Checks that the entry points are bindable, the files are readable, the providers are reachable, and the limit of open files is high enough.`,
+		Configuration: traefikConfiguration,
+		Run:           runCmd(traefikConfiguration),
+		Resources:     loaders,
+	}
+}
+
+func runCmd(traefikConfiguration *static.Configuration) func(_ []string) error {
+	return func(_ []string) error {
+		traefikConfiguration.SetEffectiveConfiguration()
+
+		if err := traefikConfiguration.ValidateConfiguration(); err != nil {
+			fmt.Printf("Invalid configuration: %s\n", err)
+			os.Exit(1)
+		}
+
+		results := preflight.Run(context.Background(), *traefikConfiguration)
+		for _, result := range results {
+			if result.Err != nil {
+				fmt.Printf("FAIL: %s: %s\n", result.Check, result.Err)
+			} else {
+				fmt.Printf("OK: %s\n", result.Check)
+			}
+		}
+
+		if results.Err() != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+		return nil
+	}
+}
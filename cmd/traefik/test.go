@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/traefik/paerser/cli"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/cmd"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/provider/file"
+	"github.com/traefik/traefik/v2/pkg/testsuite"
+	"github.com/vulcand/oxy/v2/roundrobin"
+)
+
+// readyTimeout is how long to wait for the in-process Traefik to apply the configuration of a suite.
+const readyTimeout = 30 * time.Second
+
+// newTestCmd builds the test command, which runs a declarative test suite of a routing configuration
+// against an in-process Traefik.
+// It lives in the main package, to start Traefik exactly as the main command does.
+func newTestCmd() *cli.Command {
+	return &cli.Command{
+		Name:        "test",
+		Description: `Runs a test suite (-f suite.yaml) of a routing configuration against an in-process Traefik and stub backends.`,
+		AllowArg:    true,
+		Run:         runTestCmd,
+	}
+}
+
+func runTestCmd(args []string) error {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	suitePath := flags.String("f", "", "Path of the test suite.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *suitePath == "" {
+		return errors.New("missing test suite: -f suite.yaml")
+	}
+
+	results, err := runTestSuite(*suitePath)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			fmt.Printf("FAIL: %s: %s\n", result.Name, result.Err)
+		} else {
+			fmt.Printf("PASS: %s\n", result.Name)
+		}
+	}
+
+	if failed > 0 {
+		fmt.Printf("%d/%d tests failed\n", failed, len(results))
+		os.Exit(1)
+	}
+
+	fmt.Printf("%d tests passed\n", len(results))
+	return nil
+}
+
+func runTestSuite(suitePath string) ([]testsuite.Result, error) {
+	suite, err := testsuite.Load(suitePath)
+	if err != nil {
+		return nil, err
+	}
+	defer suite.Close()
+
+	content, err := suite.DynamicConfiguration()
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "traefik-test-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	configFile := filepath.Join(dir, "dynamic.yml")
+	if err = os.WriteFile(configFile, content, 0o600); err != nil {
+		return nil, err
+	}
+
+	staticConfiguration := &cmd.NewTraefikConfiguration().Configuration
+	staticConfiguration.Global.CheckNewVersion = false
+	staticConfiguration.Providers.ProvidersThrottleDuration = ptypes.Duration(100 * time.Millisecond)
+	staticConfiguration.Providers.File = &file.Provider{Filename: configFile}
+
+	for name, entryPoint := range suite.EntryPoints {
+		ep := &static.EntryPoint{}
+		ep.SetDefaults()
+		ep.Address = entryPoint.Address
+		ep.Transport.LifeCycle.GraceTimeOut = ptypes.Duration(time.Second)
+		staticConfiguration.EntryPoints[name] = ep
+	}
+
+	staticConfiguration.SetEffectiveConfiguration()
+	if err = staticConfiguration.ValidateConfiguration(); err != nil {
+		return nil, err
+	}
+
+	configureLogging(staticConfiguration)
+
+	if err = roundrobin.SetDefaultWeight(0); err != nil {
+		return nil, err
+	}
+
+	ready := make(chan struct{})
+	readyListener := func(conf dynamic.Configuration) {
+		if testsuite.Ready(conf) {
+			select {
+			case <-ready:
+			default:
+				close(ready)
+			}
+		}
+	}
+
+	svr, err := setupServer(staticConfiguration, readyListener)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	svr.Start(ctx)
+	defer func() {
+		cancel()
+		svr.Wait()
+		svr.Close()
+	}()
+
+	select {
+	case <-ready:
+	case <-time.After(readyTimeout):
+		return nil, fmt.Errorf("the configuration of the suite was not applied after %s, check the logs for errors", readyTimeout)
+	}
+
+	return suite.Run(ctx), nil
+}
@@ -22,15 +22,27 @@ import (
 	"github.com/traefik/paerser/cli"
 	"github.com/traefik/traefik/v2/cmd"
 	"github.com/traefik/traefik/v2/cmd/healthcheck"
+	cmdPreflight "github.com/traefik/traefik/v2/cmd/preflight"
 	cmdVersion "github.com/traefik/traefik/v2/cmd/version"
+	"github.com/traefik/traefik/v2/pkg/bandwidth"
 	tcli "github.com/traefik/traefik/v2/pkg/cli"
+	"github.com/traefik/traefik/v2/pkg/clockskew"
 	"github.com/traefik/traefik/v2/pkg/collector"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/configcache"
+	"github.com/traefik/traefik/v2/pkg/connections"
+	"github.com/traefik/traefik/v2/pkg/conntrace"
+	"github.com/traefik/traefik/v2/pkg/dnsresolver"
+	"github.com/traefik/traefik/v2/pkg/egress"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/maintenance"
 	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/overrides"
+	"github.com/traefik/traefik/v2/pkg/preflight"
+	"github.com/traefik/traefik/v2/pkg/probe"
 	"github.com/traefik/traefik/v2/pkg/provider/acme"
 	"github.com/traefik/traefik/v2/pkg/provider/aggregator"
 	"github.com/traefik/traefik/v2/pkg/provider/traefik"
@@ -38,6 +50,8 @@ import (
 	"github.com/traefik/traefik/v2/pkg/server"
 	"github.com/traefik/traefik/v2/pkg/server/middleware"
 	"github.com/traefik/traefik/v2/pkg/server/service"
+	"github.com/traefik/traefik/v2/pkg/snapshot"
+	"github.com/traefik/traefik/v2/pkg/tenant"
 	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
 	"github.com/traefik/traefik/v2/pkg/tracing"
 	"github.com/traefik/traefik/v2/pkg/tracing/jaeger"
@@ -69,6 +83,18 @@ Complete documentation is available at https://traefik.io`,
 		os.Exit(1)
 	}
 
+	err = cmdTraefik.AddCommand(cmdPreflight.NewCmd(&tConfig.Configuration, loaders))
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
+	err = cmdTraefik.AddCommand(newTestCmd())
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
 	err = cmdTraefik.AddCommand(cmdVersion.NewCmd())
 	if err != nil {
 		stdlog.Println(err)
@@ -114,6 +140,12 @@ func runCmd(staticConfiguration *static.Configuration) error {
 
 	stats(staticConfiguration)
 
+	if staticConfiguration.Preflight != nil {
+		if err := preflight.Run(context.Background(), *staticConfiguration).Err(); err != nil {
+			return err
+		}
+	}
+
 	svr, err := setupServer(staticConfiguration)
 	if err != nil {
 		return err
@@ -164,8 +196,13 @@ func runCmd(staticConfiguration *static.Configuration) error {
 	return nil
 }
 
-func setupServer(staticConfiguration *static.Configuration) (*server.Server, error) {
-	providerAggregator := aggregator.NewProviderAggregator(*staticConfiguration.Providers)
+func setupServer(staticConfiguration *static.Configuration, listeners ...func(dynamic.Configuration)) (*server.Server, error) {
+	// Metrics
+
+	metricRegistries := registerMetricClients(staticConfiguration.Metrics)
+	metricsRegistry := metrics.NewMultiRegistry(metricRegistries)
+
+	providerAggregator := aggregator.NewProviderAggregator(*staticConfiguration.Providers, metricsRegistry)
 
 	ctx := context.Background()
 	routinesPool := safe.NewPool(ctx)
@@ -179,6 +216,13 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 	// ACME
 
 	tlsManager := traefiktls.NewManager()
+
+	fips := traefiktls.FIPSBuild || staticConfiguration.Global.FIPS
+	if fips {
+		log.WithoutContext().Infof("FIPS mode enabled (BoringCrypto build: %t)", traefiktls.FIPSBuild)
+		tlsManager.SetFIPS(true)
+	}
+
 	httpChallengeProvider := acme.NewChallengeHTTP()
 
 	tlsChallengeProvider := acme.NewChallengeTLSALPN()
@@ -189,9 +233,93 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 
 	acmeProviders := initACMEProvider(staticConfiguration, &providerAggregator, tlsManager, httpChallengeProvider, tlsChallengeProvider)
 
+	tenantRollups := tenant.NewRollups(metricsRegistry)
+
+	var accountant *bandwidth.Accountant
+	if conf := staticConfiguration.BandwidthAccounting; conf != nil {
+		accountant = bandwidth.NewAccountant(metricsRegistry, time.Duration(conf.Window), conf.Retention)
+
+		if report := conf.Report; report != nil {
+			routinesPool.GoCtx(func(ctx context.Context) {
+				accountant.WriteReports(ctx, report.FilePath, time.Duration(report.Interval), report.Windows, report.Top)
+			})
+		}
+	}
+
+	var serversResolver *dnsresolver.Resolver
+	if conf := staticConfiguration.ServersResolver; conf != nil {
+		serversResolver, err = dnsresolver.New(metricsRegistry, dnsresolver.Options{
+			Servers:      conf.Servers,
+			ResolvConfig: conf.ResolvConfig,
+			Timeout:      time.Duration(conf.Timeout),
+			MinTTL:       time.Duration(conf.MinTTL),
+			MaxTTL:       time.Duration(conf.MaxTTL),
+			NegativeTTL:  time.Duration(conf.NegativeTTL),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating the servers resolver: %w", err)
+		}
+	}
+
+	var egressPolicy *egress.Policy
+	if conf := staticConfiguration.Egress; conf != nil {
+		egressPolicy, err = egress.NewPolicy(metricsRegistry, conf.Allow)
+		if err != nil {
+			return nil, fmt.Errorf("creating the egress policy: %w", err)
+		}
+	}
+
+	var clockSkewChecker *clockskew.Checker
+	if conf := staticConfiguration.ClockSkew; conf != nil {
+		clockSkewChecker = clockskew.NewChecker(metricsRegistry, tlsManager.GetServerCertificates, conf.NTPServer, time.Duration(conf.MaxSkew))
+		routinesPool.GoCtx(func(ctx context.Context) {
+			clockSkewChecker.Run(ctx, time.Duration(conf.CheckInterval))
+		})
+	}
+
+	for name, conf := range staticConfiguration.Probes {
+		runner, err := probe.NewRunner(metricsRegistry, name, *conf, *staticConfiguration.EntryPoints[conf.EntryPoint])
+		if err != nil {
+			return nil, fmt.Errorf("creating the probe %s: %w", name, err)
+		}
+
+		runner.SetFIPS(fips)
+
+		interval := time.Duration(conf.Interval)
+		routinesPool.GoCtx(func(ctx context.Context) {
+			runner.Run(ctx, interval)
+		})
+	}
+
+	var maintenanceFlags *maintenance.Flags
+	if staticConfiguration.API != nil && staticConfiguration.API.Maintenance != nil {
+		conf := staticConfiguration.API.Maintenance
+		maintenanceFlags = maintenance.NewFlags(maintenance.Response{StatusCode: conf.StatusCode, Body: conf.Body})
+	}
+
+	var overridesStore *overrides.Store
+	if staticConfiguration.API != nil && staticConfiguration.API.Overrides {
+		overridesStore = overrides.NewStore()
+	}
+
+	var connTrace *conntrace.Filters
+	if staticConfiguration.API != nil && staticConfiguration.API.ConnTrace {
+		connTrace = conntrace.NewFilters()
+	}
+
+	var connRegistry *connections.Registry
+	if staticConfiguration.API != nil && staticConfiguration.API.Connections {
+		connRegistry = connections.NewRegistry()
+	}
+
+	var snapshotStore *snapshot.Store
+	if staticConfiguration.API != nil && staticConfiguration.API.Snapshots != nil {
+		snapshotStore = snapshot.NewStore(staticConfiguration.API.Snapshots.MaxSnapshots)
+	}
+
 	// Entrypoints
 
-	serverEntryPointsTCP, err := server.NewTCPEntryPoints(staticConfiguration.EntryPoints, staticConfiguration.HostResolver)
+	serverEntryPointsTCP, err := server.NewTCPEntryPoints(staticConfiguration.EntryPoints, staticConfiguration.HostResolver, metricsRegistry)
 	if err != nil {
 		return nil, err
 	}
@@ -234,16 +362,22 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		}
 	}
 
-	// Metrics
-
-	metricRegistries := registerMetricClients(staticConfiguration.Metrics)
-	metricsRegistry := metrics.NewMultiRegistry(metricRegistries)
-
 	// Service manager factory
 
 	roundTripperManager := service.NewRoundTripperManager()
+	roundTripperManager.SetFIPS(fips)
+	roundTripperManager.SetResolver(serversResolver)
+	roundTripperManager.SetEgressPolicy(egressPolicy)
 	acmeHTTPHandler := getHTTPChallengeHandler(acmeProviders, httpChallengeProvider)
-	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, metricsRegistry, roundTripperManager, acmeHTTPHandler)
+	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, metricsRegistry, roundTripperManager, acmeHTTPHandler, service.ManagerFactoryOptions{
+		TenantRollups: tenantRollups,
+		Accountant:    accountant,
+		Maintenance:   maintenanceFlags,
+		Overrides:     overridesStore,
+		Snapshots:     snapshotStore,
+		ConnTrace:     connTrace,
+		Connections:   connRegistry,
+	})
 
 	// Router factory
 
@@ -251,15 +385,33 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 	tracer := setupTracing(staticConfiguration.Tracing)
 
 	chainBuilder := middleware.NewChainBuilder(metricsRegistry, accessLog, tracer)
-	routerFactory := server.NewRouterFactory(*staticConfiguration, managerFactory, tlsManager, chainBuilder, pluginBuilder, metricsRegistry)
+	routerFactory := server.NewRouterFactory(*staticConfiguration, managerFactory, tlsManager, chainBuilder, pluginBuilder, metricsRegistry, server.RouterFactoryOptions{
+		TenantRollups: tenantRollups,
+		Accountant:    accountant,
+		Maintenance:   maintenanceFlags,
+		Overrides:     overridesStore,
+		ConnTrace:     connTrace,
+		Connections:   connRegistry,
+		Resolver:      serversResolver,
+		EgressPolicy:  egressPolicy,
+	})
+	routerFactory.SetFIPS(fips)
 
 	// Watcher
 
+	var providersCache *configcache.Cache
+	if staticConfiguration.Providers != nil && staticConfiguration.Providers.Cache != nil {
+		cacheConfig := staticConfiguration.Providers.Cache
+		providersCache = configcache.New(cacheConfig.FilePath, time.Duration(cacheConfig.StaleTimeout))
+	}
+
 	watcher := server.NewConfigurationWatcher(
 		routinesPool,
 		providerAggregator,
 		getDefaultsEntrypoints(staticConfiguration),
 		"internal",
+		snapshotStore,
+		providersCache,
 	)
 
 	// TLS
@@ -271,6 +423,10 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		for _, certificate := range tlsManager.GetServerCertificates() {
 			appendCertMetric(gauge, certificate)
 		}
+
+		if clockSkewChecker != nil {
+			clockSkewChecker.CheckCertificates(ctx)
+		}
 	})
 
 	// Metrics
@@ -321,6 +477,10 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		}
 	})
 
+	for _, listener := range listeners {
+		watcher.AddListener(listener)
+	}
+
 	return server.NewServer(routinesPool, serverEntryPointsTCP, serverEntryPointsUDP, watcher, chainBuilder, accessLog), nil
 }
 
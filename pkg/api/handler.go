@@ -7,10 +7,17 @@ import (
 	"strings"
 
 	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/bandwidth"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/connections"
+	"github.com/traefik/traefik/v2/pkg/conntrace"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/maintenance"
+	"github.com/traefik/traefik/v2/pkg/overrides"
+	"github.com/traefik/traefik/v2/pkg/snapshot"
+	"github.com/traefik/traefik/v2/pkg/tenant"
 	"github.com/traefik/traefik/v2/pkg/version"
 )
 
@@ -43,6 +50,10 @@ type RunTimeRepresentation struct {
 	TCPServices    map[string]*runtime.TCPServiceInfo    `json:"tcpServices,omitempty"`
 	UDPRouters     map[string]*runtime.UDPRouterInfo     `json:"udpRouters,omitempty"`
 	UDPServices    map[string]*runtime.UDPServiceInfo    `json:"udpServices,omitempty"`
+
+	// EphemeralOverrides are the parameters of the middlewares overridden through the API,
+	// which are not part of their configuration, and are cleared when a new configuration is applied.
+	EphemeralOverrides map[string]overrides.Parameters `json:"ephemeralOverrides,omitempty"`
 }
 
 // Handler serves the configuration and status of Traefik on API endpoints.
@@ -51,12 +62,41 @@ type Handler struct {
 
 	// runtimeConfiguration is the data set used to create all the data representations exposed by the API.
 	runtimeConfiguration *runtime.Configuration
+
+	// tenantRollups holds the traffic statistics aggregated per tenant.
+	tenantRollups *tenant.Rollups
+
+	// accountant holds the bytes transferred by the routers over time windows.
+	accountant *bandwidth.Accountant
+
+	// maintenance holds the routers put in maintenance.
+	maintenance *maintenance.Flags
+
+	// overrides holds the parameters of the middlewares overridden at runtime.
+	overrides *overrides.Store
+
+	// snapshots holds the snapshots of the dynamic configuration, and the snapshot the proxy is pinned to.
+	snapshots *snapshot.Store
+
+	// connTrace holds the client IPs and server names whose TCP connections are traced.
+	connTrace *conntrace.Filters
+
+	// connections holds the open TCP connections, and the configuration generation of their router.
+	connections *connections.Registry
 }
 
 // NewBuilder returns a http.Handler builder based on runtime.Configuration.
-func NewBuilder(staticConfig static.Configuration) func(*runtime.Configuration) http.Handler {
+func NewBuilder(staticConfig static.Configuration, tenantRollups *tenant.Rollups, accountant *bandwidth.Accountant, maintenanceFlags *maintenance.Flags, overridesStore *overrides.Store, snapshotStore *snapshot.Store, connTrace *conntrace.Filters, connRegistry *connections.Registry) func(*runtime.Configuration) http.Handler {
 	return func(configuration *runtime.Configuration) http.Handler {
-		return New(staticConfig, configuration).createRouter()
+		handler := New(staticConfig, configuration)
+		handler.tenantRollups = tenantRollups
+		handler.accountant = accountant
+		handler.maintenance = maintenanceFlags
+		handler.overrides = overridesStore
+		handler.snapshots = snapshotStore
+		handler.connTrace = connTrace
+		handler.connections = connRegistry
+		return handler.createRouter()
 	}
 }
 
@@ -92,6 +132,7 @@ func (h Handler) createRouter() *mux.Router {
 
 	router.Methods(http.MethodGet).Path("/api/http/routers").HandlerFunc(h.getRouters)
 	router.Methods(http.MethodGet).Path("/api/http/routers/{routerID}").HandlerFunc(h.getRouter)
+	router.Methods(http.MethodGet).Path("/api/http/routers/{routerID}/chain").HandlerFunc(h.getRouterChain)
 	router.Methods(http.MethodGet).Path("/api/http/services").HandlerFunc(h.getServices)
 	router.Methods(http.MethodGet).Path("/api/http/services/{serviceID}").HandlerFunc(h.getService)
 	router.Methods(http.MethodGet).Path("/api/http/middlewares").HandlerFunc(h.getMiddlewares)
@@ -99,6 +140,7 @@ func (h Handler) createRouter() *mux.Router {
 
 	router.Methods(http.MethodGet).Path("/api/tcp/routers").HandlerFunc(h.getTCPRouters)
 	router.Methods(http.MethodGet).Path("/api/tcp/routers/{routerID}").HandlerFunc(h.getTCPRouter)
+	router.Methods(http.MethodGet).Path("/api/tcp/routers/{routerID}/chain").HandlerFunc(h.getTCPRouterChain)
 	router.Methods(http.MethodGet).Path("/api/tcp/services").HandlerFunc(h.getTCPServices)
 	router.Methods(http.MethodGet).Path("/api/tcp/services/{serviceID}").HandlerFunc(h.getTCPService)
 	router.Methods(http.MethodGet).Path("/api/tcp/middlewares").HandlerFunc(h.getTCPMiddlewares)
@@ -109,6 +151,44 @@ func (h Handler) createRouter() *mux.Router {
 	router.Methods(http.MethodGet).Path("/api/udp/services").HandlerFunc(h.getUDPServices)
 	router.Methods(http.MethodGet).Path("/api/udp/services/{serviceID}").HandlerFunc(h.getUDPService)
 
+	router.Methods(http.MethodGet).Path("/api/tenants").HandlerFunc(h.getTenants)
+	router.Methods(http.MethodGet).Path("/api/tenants/{tenantID}").HandlerFunc(h.getTenant)
+
+	router.Methods(http.MethodGet).Path("/api/bandwidth").HandlerFunc(h.getBandwidth)
+	router.Methods(http.MethodGet).Path("/api/bandwidth/report").HandlerFunc(h.getBandwidthReport)
+
+	if h.maintenance != nil {
+		router.Methods(http.MethodPut).Path("/api/http/routers/{routerID}/maintenance").HandlerFunc(h.putRouterMaintenance)
+		router.Methods(http.MethodDelete).Path("/api/http/routers/{routerID}/maintenance").HandlerFunc(h.deleteRouterMaintenance)
+		router.Methods(http.MethodPut).Path("/api/tcp/routers/{routerID}/maintenance").HandlerFunc(h.putTCPRouterMaintenance)
+		router.Methods(http.MethodDelete).Path("/api/tcp/routers/{routerID}/maintenance").HandlerFunc(h.deleteTCPRouterMaintenance)
+	}
+
+	if h.overrides != nil {
+		router.Methods(http.MethodPut).Path("/api/http/middlewares/{middlewareID}/overrides").HandlerFunc(h.putMiddlewareOverrides)
+		router.Methods(http.MethodDelete).Path("/api/http/middlewares/{middlewareID}/overrides").HandlerFunc(h.deleteMiddlewareOverrides)
+	}
+
+	if h.connTrace != nil {
+		router.Methods(http.MethodGet).Path("/api/tcp/traces").HandlerFunc(h.getConnTraces)
+		router.Methods(http.MethodPut).Path("/api/tcp/traces/clients/{clientIP}").HandlerFunc(h.putClientConnTrace)
+		router.Methods(http.MethodDelete).Path("/api/tcp/traces/clients/{clientIP}").HandlerFunc(h.deleteClientConnTrace)
+		router.Methods(http.MethodPut).Path("/api/tcp/traces/servernames/{serverName}").HandlerFunc(h.putServerNameConnTrace)
+		router.Methods(http.MethodDelete).Path("/api/tcp/traces/servernames/{serverName}").HandlerFunc(h.deleteServerNameConnTrace)
+	}
+
+	if h.connections != nil {
+		router.Methods(http.MethodGet).Path("/api/tcp/connections").HandlerFunc(h.getTCPConnections)
+	}
+
+	if h.snapshots != nil {
+		router.Methods(http.MethodGet).Path("/api/snapshots").HandlerFunc(h.getSnapshots)
+		router.Methods(http.MethodPost).Path("/api/snapshots").HandlerFunc(h.postSnapshot)
+		router.Methods(http.MethodDelete).Path("/api/snapshots/pin").HandlerFunc(h.deleteSnapshotPin)
+		router.Methods(http.MethodGet).Path("/api/snapshots/{hash}").HandlerFunc(h.getSnapshot)
+		router.Methods(http.MethodPut).Path("/api/snapshots/{hash}/pin").HandlerFunc(h.putSnapshotPin)
+	}
+
 	version.Handler{}.Append(router)
 
 	return router
@@ -132,6 +212,8 @@ func (h Handler) getRuntimeConfiguration(rw http.ResponseWriter, request *http.R
 		TCPServices:    h.runtimeConfiguration.TCPServices,
 		UDPRouters:     h.runtimeConfiguration.UDPRouters,
 		UDPServices:    h.runtimeConfiguration.UDPServices,
+
+		EphemeralOverrides: h.overrides.All(),
 	}
 
 	rw.Header().Set("Content-Type", "application/json")
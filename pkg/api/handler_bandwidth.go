@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/traefik/traefik/v2/pkg/bandwidth"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+const defaultTopTalkers = 10
+
+func (h Handler) getBandwidth(rw http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+
+	results := make([]bandwidth.Record, 0)
+	for _, record := range h.accountant.Records() {
+		if keepBandwidthRecord(record, query.Get("router"), query.Get("service"), query.Get("tenant")) {
+			results = append(results, record)
+		}
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) getBandwidthReport(rw http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	windows, err := intQueryParam(query.Get("windows"), 0)
+	if err != nil {
+		writeError(rw, fmt.Sprintf("invalid windows: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	top, err := intQueryParam(query.Get("top"), defaultTopTalkers)
+	if err != nil {
+		writeError(rw, fmt.Sprintf("invalid top: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	err = json.NewEncoder(rw).Encode(h.accountant.Report(windows, top))
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// intQueryParam parses the value of an integer query parameter, or returns the default value if it is empty.
+func intQueryParam(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	return strconv.Atoi(value)
+}
+
+// keepBandwidthRecord reports whether the record matches the non empty router, service, and tenant filters.
+func keepBandwidthRecord(record bandwidth.Record, router, service, tenant string) bool {
+	return (router == "" || record.Router == router) &&
+		(service == "" || record.Service == service) &&
+		(tenant == "" || record.Tenant == tenant)
+}
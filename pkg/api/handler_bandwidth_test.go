@@ -0,0 +1,169 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/bandwidth"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+func TestHandler_Bandwidth(t *testing.T) {
+	type expected struct {
+		statusCode int
+		nextPage   string
+		routers    []string
+	}
+
+	accountant := bandwidth.NewAccountant(nil, 24*time.Hour, 1)
+	accountant.Observe(bandwidth.Key{Router: "bar@file", Service: "bar@file", Tenant: "acme"}, bandwidth.Usage{WireResponseBytes: 10, LogicalResponseBytes: 30})
+	accountant.Observe(bandwidth.Key{Router: "foo@file", Service: "foo@file", Tenant: "acme"}, bandwidth.Usage{WireResponseBytes: 20, LogicalResponseBytes: 20})
+	accountant.Observe(bandwidth.Key{Router: "foo@file", Service: "foo@file", Tenant: "other"}, bandwidth.Usage{WireResponseBytes: 40, LogicalResponseBytes: 40})
+
+	testCases := []struct {
+		desc       string
+		path       string
+		accountant *bandwidth.Accountant
+		expected   expected
+	}{
+		{
+			desc: "all records, but no accounting",
+			path: "/api/bandwidth",
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				routers:    []string{},
+			},
+		},
+		{
+			desc:       "all records",
+			path:       "/api/bandwidth",
+			accountant: accountant,
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				routers:    []string{"bar@file", "foo@file", "foo@file"},
+			},
+		},
+		{
+			desc:       "records of a tenant",
+			path:       "/api/bandwidth?tenant=acme",
+			accountant: accountant,
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				routers:    []string{"bar@file", "foo@file"},
+			},
+		},
+		{
+			desc:       "records of a router",
+			path:       "/api/bandwidth?router=foo@file",
+			accountant: accountant,
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				routers:    []string{"foo@file", "foo@file"},
+			},
+		},
+		{
+			desc:       "pagination, 1 res per page, want page 2",
+			path:       "/api/bandwidth?page=2&per_page=1",
+			accountant: accountant,
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "3",
+				routers:    []string{"foo@file"},
+			},
+		},
+		{
+			desc:       "pagination, page out of range",
+			path:       "/api/bandwidth?page=5&per_page=1",
+			accountant: accountant,
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, &runtime.Configuration{})
+			handler.accountant = test.accountant
+			server := httptest.NewServer(handler.createRouter())
+
+			resp, err := http.DefaultClient.Get(server.URL + test.path)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			assert.Equal(t, test.expected.nextPage, resp.Header.Get(nextPageHeader))
+
+			require.Equal(t, test.expected.statusCode, resp.StatusCode)
+
+			if test.expected.routers == nil {
+				return
+			}
+
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+			var records []bandwidth.Record
+			err = json.NewDecoder(resp.Body).Decode(&records)
+			require.NoError(t, err)
+
+			routers := make([]string, 0, len(records))
+			for _, record := range records {
+				routers = append(routers, record.Router)
+				assert.Equal(t, 24*time.Hour, record.End.Sub(record.Start))
+			}
+
+			assert.Equal(t, test.expected.routers, routers)
+		})
+	}
+}
+
+func TestHandler_BandwidthReport(t *testing.T) {
+	accountant := bandwidth.NewAccountant(nil, 24*time.Hour, 1)
+	accountant.Observe(bandwidth.Key{Router: "bar@file", Service: "bar@file", Tenant: "acme"}, bandwidth.Usage{WireResponseBytes: 10, LogicalResponseBytes: 30})
+	accountant.Observe(bandwidth.Key{Router: "foo@file", Service: "foo@file", Tenant: "acme"}, bandwidth.Usage{WireResponseBytes: 20, LogicalResponseBytes: 20})
+	accountant.Observe(bandwidth.Key{Router: "foo@file", Service: "foo@file", Tenant: "other"}, bandwidth.Usage{WireResponseBytes: 40, LogicalResponseBytes: 40})
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, &runtime.Configuration{})
+	handler.accountant = accountant
+
+	server := httptest.NewServer(handler.createRouter())
+	defer server.Close()
+
+	resp, err := http.DefaultClient.Get(server.URL + "/api/bandwidth/report?top=2")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var report bandwidth.Report
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	_ = resp.Body.Close()
+
+	assert.Equal(t, int64(70), report.WireBytes)
+	assert.Equal(t, int64(90), report.LogicalBytes)
+	assert.Equal(t, int64(20), report.SavedBytes)
+
+	require.Len(t, report.Routers, 2)
+	assert.Equal(t, "bar@file", report.Routers[0].Router)
+	assert.InDelta(t, 3, report.Routers[0].CompressionRatio, 0.001)
+
+	require.Len(t, report.TopTalkers, 2)
+	assert.Equal(t, "other", report.TopTalkers[0].Tenant)
+	assert.Equal(t, "acme", report.TopTalkers[1].Tenant)
+	assert.Equal(t, "foo@file", report.TopTalkers[1].Router)
+
+	resp, err = http.DefaultClient.Get(server.URL + "/api/bandwidth/report?windows=foo")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/overrides"
+	"github.com/traefik/traefik/v2/pkg/server/provider"
+)
+
+type chainRepresentation struct {
+	Router      string                       `json:"router"`
+	Middlewares []chainElementRepresentation `json:"middlewares"`
+}
+
+// chainElementRepresentation is a middleware of the resolved chain of a router.
+type chainElementRepresentation struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+	// Chain is the name of the Chain middleware the middleware is part of, if any.
+	Chain string `json:"chain,omitempty"`
+	// Settings are the configured settings of the middleware, with the parameters overridden at runtime applied.
+	Settings  *dynamic.Middleware   `json:"settings,omitempty"`
+	Overrides *overrides.Parameters `json:"overrides,omitempty"`
+	Error     string                `json:"error,omitempty"`
+}
+
+type tcpChainRepresentation struct {
+	Router      string                          `json:"router"`
+	Middlewares []tcpChainElementRepresentation `json:"middlewares"`
+}
+
+// tcpChainElementRepresentation is a middleware of the resolved chain of a TCP router.
+type tcpChainElementRepresentation struct {
+	Name     string                 `json:"name"`
+	Type     string                 `json:"type,omitempty"`
+	Settings *dynamic.TCPMiddleware `json:"settings,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+}
+
+func (h Handler) getRouterChain(rw http.ResponseWriter, request *http.Request) {
+	routerID := mux.Vars(request)["routerID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	router, ok := h.runtimeConfiguration.Routers[routerID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("router not found: %s", routerID), http.StatusNotFound)
+		return
+	}
+
+	result := chainRepresentation{
+		Router:      routerID,
+		Middlewares: make([]chainElementRepresentation, 0, len(router.Middlewares)),
+	}
+
+	ctx := provider.AddInContext(request.Context(), routerID)
+	result.Middlewares = h.resolveChain(ctx, router.Middlewares, "", []string{}, result.Middlewares)
+
+	err := json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// resolveChain appends the given middlewares to the chain, in the order they handle the requests,
+// with the middlewares of the Chain middlewares following them.
+// The names are qualified the same way as when the routers are built.
+func (h Handler) resolveChain(ctx context.Context, names []string, parent string, stack []string, chain []chainElementRepresentation) []chainElementRepresentation {
+	for _, name := range names {
+		middlewareName := provider.GetQualifiedName(ctx, name)
+
+		element := chainElementRepresentation{Name: middlewareName, Chain: parent}
+
+		midInf, ok := h.runtimeConfiguration.Middlewares[middlewareName]
+		if !ok || midInf.Middleware == nil {
+			element.Error = fmt.Sprintf("middleware %q does not exist", middlewareName)
+			chain = append(chain, element)
+			continue
+		}
+
+		element.Type = strings.ToLower(extractType(midInf.Middleware))
+		element.Settings = midInf.Middleware.DeepCopy()
+
+		if params, ok := h.overrides.Get(middlewareName); ok {
+			element.Overrides = &params
+			applyOverrides(element.Settings, params)
+		}
+
+		if inSlice(middlewareName, stack) {
+			element.Error = fmt.Sprintf("recursion detected in %s", strings.Join(append(stack, middlewareName), "->"))
+			chain = append(chain, element)
+			continue
+		}
+
+		chain = append(chain, element)
+
+		if midInf.Chain != nil {
+			chainCtx := provider.AddInContext(ctx, middlewareName)
+			chain = h.resolveChain(chainCtx, midInf.Chain.Middlewares, middlewareName, append(stack, middlewareName), chain)
+		}
+	}
+
+	return chain
+}
+
+// applyOverrides sets the parameters overridden at runtime in the given middleware settings,
+// as the middlewares take them into account instead of the configured ones.
+func applyOverrides(settings *dynamic.Middleware, params overrides.Parameters) {
+	if params.RateLimit != nil && settings.RateLimit != nil {
+		settings.RateLimit.Average = params.RateLimit.Average
+		settings.RateLimit.Period = params.RateLimit.Period
+		settings.RateLimit.Burst = params.RateLimit.Burst
+
+		// The rate limiter defaults the overridden period to a second, like the configured one.
+		if settings.RateLimit.Period == 0 {
+			settings.RateLimit.Period = ptypes.Duration(time.Second)
+		}
+	}
+
+	if params.Compress != nil && settings.Compress != nil {
+		settings.Compress.CompressionLevel = params.Compress.CompressionLevel
+	}
+
+	if params.Accounting != nil && settings.Accounting != nil {
+		settings.Accounting.SampleRate = params.Accounting.SampleRate
+	}
+}
+
+func (h Handler) getTCPRouterChain(rw http.ResponseWriter, request *http.Request) {
+	routerID := mux.Vars(request)["routerID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	router, ok := h.runtimeConfiguration.TCPRouters[routerID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("router not found: %s", routerID), http.StatusNotFound)
+		return
+	}
+
+	result := tcpChainRepresentation{
+		Router:      routerID,
+		Middlewares: make([]tcpChainElementRepresentation, 0, len(router.Middlewares)),
+	}
+
+	ctx := provider.AddInContext(request.Context(), routerID)
+	for _, name := range router.Middlewares {
+		middlewareName := provider.GetQualifiedName(ctx, name)
+
+		element := tcpChainElementRepresentation{Name: middlewareName}
+
+		midInf, ok := h.runtimeConfiguration.TCPMiddlewares[middlewareName]
+		if !ok || midInf.TCPMiddleware == nil {
+			element.Error = fmt.Sprintf("middleware %q does not exist", middlewareName)
+		} else {
+			element.Type = strings.ToLower(extractType(midInf.TCPMiddleware))
+			element.Settings = midInf.TCPMiddleware.DeepCopy()
+		}
+
+		result.Middlewares = append(result.Middlewares, element)
+	}
+
+	err := json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func inSlice(element string, stack []string) bool {
+	for _, value := range stack {
+		if value == element {
+			return true
+		}
+	}
+	return false
+}
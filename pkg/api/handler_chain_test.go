@@ -0,0 +1,148 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/overrides"
+)
+
+func TestHandler_RouterChain(t *testing.T) {
+	rtConf := &runtime.Configuration{
+		Routers: map[string]*runtime.RouterInfo{
+			"foo@file":  {Router: &dynamic.Router{Middlewares: []string{"chain", "compress@file", "missing"}}},
+			"loop@file": {Router: &dynamic.Router{Middlewares: []string{"loop"}}},
+		},
+		Middlewares: map[string]*runtime.MiddlewareInfo{
+			"chain@file":     {Middleware: &dynamic.Middleware{Chain: &dynamic.Chain{Middlewares: []string{"ratelimit", "headers@docker"}}}},
+			"ratelimit@file": {Middleware: &dynamic.Middleware{RateLimit: &dynamic.RateLimit{Average: 100, Burst: 10}}},
+			"headers@docker": {Middleware: &dynamic.Middleware{Headers: &dynamic.Headers{CustomRequestHeaders: map[string]string{"X-Foo": "bar"}}}},
+			"compress@file":  {Middleware: &dynamic.Middleware{Compress: &dynamic.Compress{}}},
+			"loop@file":      {Middleware: &dynamic.Middleware{Chain: &dynamic.Chain{Middlewares: []string{"loop"}}}},
+		},
+	}
+
+	store := overrides.NewStore()
+	store.Set("compress@file", overrides.Parameters{Compress: &overrides.Compress{CompressionLevel: 1}})
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf)
+	handler.overrides = store
+
+	server := httptest.NewServer(handler.createRouter())
+	defer server.Close()
+
+	resp := doRequest(t, http.MethodGet, server.URL+"/api/http/routers/foo@file/chain")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var chain chainRepresentation
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&chain))
+	_ = resp.Body.Close()
+
+	expected := chainRepresentation{
+		Router: "foo@file",
+		Middlewares: []chainElementRepresentation{
+			{
+				Name:     "chain@file",
+				Type:     "chain",
+				Settings: rtConf.Middlewares["chain@file"].Middleware,
+			},
+			{
+				Name:     "ratelimit@file",
+				Type:     "ratelimit",
+				Chain:    "chain@file",
+				Settings: rtConf.Middlewares["ratelimit@file"].Middleware,
+			},
+			{
+				Name:     "headers@docker",
+				Type:     "headers",
+				Chain:    "chain@file",
+				Settings: rtConf.Middlewares["headers@docker"].Middleware,
+			},
+			{
+				Name:      "compress@file",
+				Type:      "compress",
+				Settings:  &dynamic.Middleware{Compress: &dynamic.Compress{CompressionLevel: 1}},
+				Overrides: &overrides.Parameters{Compress: &overrides.Compress{CompressionLevel: 1}},
+			},
+			{
+				Name:  "missing@file",
+				Error: `middleware "missing@file" does not exist`,
+			},
+		},
+	}
+	assert.Equal(t, expected, chain)
+
+	// The settings of the runtime configuration are left untouched by the overrides.
+	assert.Equal(t, 0, rtConf.Middlewares["compress@file"].Compress.CompressionLevel)
+
+	resp = doRequest(t, http.MethodGet, server.URL+"/api/http/routers/loop@file/chain")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	chain = chainRepresentation{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&chain))
+	_ = resp.Body.Close()
+
+	require.Len(t, chain.Middlewares, 2)
+	assert.Empty(t, chain.Middlewares[0].Error)
+	assert.Equal(t, "recursion detected in loop@file->loop@file", chain.Middlewares[1].Error)
+
+	resp = doRequest(t, http.MethodGet, server.URL+"/api/http/routers/bar@file/chain")
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestHandler_TCPRouterChain(t *testing.T) {
+	rtConf := &runtime.Configuration{
+		TCPRouters: map[string]*runtime.TCPRouterInfo{
+			"foo@file": {TCPRouter: &dynamic.TCPRouter{Middlewares: []string{"inflight", "whitelist@docker", "missing"}}},
+		},
+		TCPMiddlewares: map[string]*runtime.TCPMiddlewareInfo{
+			"inflight@file":    {TCPMiddleware: &dynamic.TCPMiddleware{InFlightConn: &dynamic.TCPInFlightConn{Amount: 10}}},
+			"whitelist@docker": {TCPMiddleware: &dynamic.TCPMiddleware{IPWhiteList: &dynamic.TCPIPWhiteList{SourceRange: []string{"10.0.0.0/8"}}}},
+		},
+	}
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf)
+
+	server := httptest.NewServer(handler.createRouter())
+	defer server.Close()
+
+	resp := doRequest(t, http.MethodGet, server.URL+"/api/tcp/routers/foo@file/chain")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var chain tcpChainRepresentation
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&chain))
+	_ = resp.Body.Close()
+
+	expected := tcpChainRepresentation{
+		Router: "foo@file",
+		Middlewares: []tcpChainElementRepresentation{
+			{
+				Name:     "inflight@file",
+				Type:     "inflightconn",
+				Settings: rtConf.TCPMiddlewares["inflight@file"].TCPMiddleware,
+			},
+			{
+				Name:     "whitelist@docker",
+				Type:     "ipwhitelist",
+				Settings: rtConf.TCPMiddlewares["whitelist@docker"].TCPMiddleware,
+			},
+			{
+				Name:  "missing@file",
+				Error: `middleware "missing@file" does not exist`,
+			},
+		},
+	}
+	assert.Equal(t, expected, chain)
+
+	resp = doRequest(t, http.MethodGet, server.URL+"/api/tcp/routers/bar@file/chain")
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
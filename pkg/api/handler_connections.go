@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/traefik/traefik/v2/pkg/connections"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+func (h Handler) getTCPConnections(rw http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	var stale *bool
+	if value := query.Get("stale"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(rw, "invalid stale value: "+value, http.StatusBadRequest)
+			return
+		}
+		stale = &parsed
+	}
+
+	results := make([]connections.Connection, 0)
+	for _, conn := range h.connections.List() {
+		if keepConnection(conn, query.Get("router"), stale) {
+			results = append(results, conn)
+		}
+	}
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// keepConnection reports whether the connection matches the non empty router filter, and the stale filter, if any.
+func keepConnection(conn connections.Connection, router string, stale *bool) bool {
+	return (router == "" || conn.Router == router) &&
+		(stale == nil || conn.Stale == *stale)
+}
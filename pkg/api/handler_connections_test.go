@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/connections"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestHandler_TCPConnections(t *testing.T) {
+	registry := connections.NewRegistry()
+	registry.NextGeneration()
+
+	served := make(chan struct{}, 2)
+	release := make(chan struct{})
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		served <- struct{}{}
+		<-release
+	})
+
+	staleHandler := connections.NewHandler(registry, "foo@file", "foo@file", next)
+	registry.NextGeneration()
+	handler := connections.NewHandler(registry, "bar@file", "bar@file", next)
+
+	for _, h := range []tcp.Handler{staleHandler, handler} {
+		_, server := net.Pipe()
+		go h.ServeTCP(fakeConn{Conn: server})
+		<-served
+	}
+	defer close(release)
+
+	api := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, nil)
+	api.connections = registry
+
+	server := httptest.NewServer(api.createRouter())
+	defer server.Close()
+
+	testCases := []struct {
+		desc     string
+		query    string
+		status   int
+		expected []string
+	}{
+		{
+			desc:     "all connections",
+			status:   http.StatusOK,
+			expected: []string{"foo@file", "bar@file"},
+		},
+		{
+			desc:     "stale connections",
+			query:    "?stale=true",
+			status:   http.StatusOK,
+			expected: []string{"foo@file"},
+		},
+		{
+			desc:     "connections of a router",
+			query:    "?router=bar@file",
+			status:   http.StatusOK,
+			expected: []string{"bar@file"},
+		},
+		{
+			desc:   "invalid stale value",
+			query:  "?stale=foo",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			resp, err := http.DefaultClient.Get(server.URL + "/api/tcp/connections" + test.query)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			require.Equal(t, test.status, resp.StatusCode)
+			if test.status != http.StatusOK {
+				return
+			}
+
+			var conns []connections.Connection
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&conns))
+
+			var routers []string
+			for _, conn := range conns {
+				routers = append(routers, conn.Router)
+				assert.Equal(t, conn.Router == "foo@file", conn.Stale)
+			}
+			assert.Equal(t, test.expected, routers)
+		})
+	}
+}
+
+func TestHandler_TCPConnections_disabled(t *testing.T) {
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, nil)
+	server := httptest.NewServer(handler.createRouter())
+	defer server.Close()
+
+	resp := doRequest(t, http.MethodGet, server.URL+"/api/tcp/connections")
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+type fakeConn struct {
+	net.Conn
+}
+
+func (f fakeConn) CloseWrite() error {
+	return nil
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/conntrace"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+type connTracesRepresentation struct {
+	ClientIPs   []string `json:"clientIPs"`
+	ServerNames []string `json:"serverNames"`
+}
+
+func (h Handler) getConnTraces(rw http.ResponseWriter, request *http.Request) {
+	result := connTracesRepresentation{
+		ClientIPs:   h.connTrace.List(conntrace.FilterClientIP),
+		ServerNames: h.connTrace.List(conntrace.FilterServerName),
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) putClientConnTrace(rw http.ResponseWriter, request *http.Request) {
+	h.setClientConnTrace(rw, request, true)
+}
+
+func (h Handler) deleteClientConnTrace(rw http.ResponseWriter, request *http.Request) {
+	h.setClientConnTrace(rw, request, false)
+}
+
+func (h Handler) setClientConnTrace(rw http.ResponseWriter, request *http.Request, enabled bool) {
+	clientIP := net.ParseIP(mux.Vars(request)["clientIP"])
+	if clientIP == nil {
+		rw.Header().Set("Content-Type", "application/json")
+		writeError(rw, fmt.Sprintf("invalid client IP: %s", mux.Vars(request)["clientIP"]), http.StatusBadRequest)
+		return
+	}
+
+	h.connTrace.Set(conntrace.FilterClientIP, clientIP.String(), enabled)
+
+	h.getConnTraces(rw, request)
+}
+
+func (h Handler) putServerNameConnTrace(rw http.ResponseWriter, request *http.Request) {
+	h.connTrace.Set(conntrace.FilterServerName, mux.Vars(request)["serverName"], true)
+
+	h.getConnTraces(rw, request)
+}
+
+func (h Handler) deleteServerNameConnTrace(rw http.ResponseWriter, request *http.Request) {
+	h.connTrace.Set(conntrace.FilterServerName, mux.Vars(request)["serverName"], false)
+
+	h.getConnTraces(rw, request)
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/conntrace"
+)
+
+func TestHandler_ConnTraces(t *testing.T) {
+	filters := conntrace.NewFilters()
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, nil)
+	handler.connTrace = filters
+
+	server := httptest.NewServer(handler.createRouter())
+	defer server.Close()
+
+	resp := doRequest(t, http.MethodPut, server.URL+"/api/tcp/traces/clients/10.0.0.1")
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = doRequest(t, http.MethodPut, server.URL+"/api/tcp/traces/servernames/Foo.localhost")
+	var traces connTracesRepresentation
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&traces))
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Equal(t, connTracesRepresentation{ClientIPs: []string{"10.0.0.1"}, ServerNames: []string{"foo.localhost"}}, traces)
+	assert.True(t, filters.Match("10.0.0.1", ""))
+	assert.True(t, filters.Match("10.0.0.2", "foo.localhost"))
+
+	resp = doRequest(t, http.MethodPut, server.URL+"/api/tcp/traces/clients/foo")
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = doRequest(t, http.MethodDelete, server.URL+"/api/tcp/traces/clients/10.0.0.1")
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = doRequest(t, http.MethodDelete, server.URL+"/api/tcp/traces/servernames/foo.localhost")
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = doRequest(t, http.MethodGet, server.URL+"/api/tcp/traces")
+	traces = connTracesRepresentation{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&traces))
+	_ = resp.Body.Close()
+
+	assert.Empty(t, traces.ClientIPs)
+	assert.Empty(t, traces.ServerNames)
+	assert.False(t, filters.Match("10.0.0.1", "foo.localhost"))
+}
+
+func TestHandler_ConnTraces_disabled(t *testing.T) {
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, nil)
+	server := httptest.NewServer(handler.createRouter())
+	defer server.Close()
+
+	resp := doRequest(t, http.MethodPut, server.URL+"/api/tcp/traces/clients/10.0.0.1")
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
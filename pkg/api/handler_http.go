@@ -11,13 +11,16 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/maintenance"
+	"github.com/traefik/traefik/v2/pkg/overrides"
 	"github.com/traefik/traefik/v2/pkg/tls"
 )
 
 type routerRepresentation struct {
 	*runtime.RouterInfo
-	Name     string `json:"name,omitempty"`
-	Provider string `json:"provider,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Provider    string `json:"provider,omitempty"`
+	Maintenance bool   `json:"maintenance,omitempty"`
 }
 
 func newRouterRepresentation(name string, rt *runtime.RouterInfo) routerRepresentation {
@@ -34,10 +37,11 @@ func newRouterRepresentation(name string, rt *runtime.RouterInfo) routerRepresen
 
 type serviceRepresentation struct {
 	*runtime.ServiceInfo
-	ServerStatus map[string]string `json:"serverStatus,omitempty"`
-	Name         string            `json:"name,omitempty"`
-	Provider     string            `json:"provider,omitempty"`
-	Type         string            `json:"type,omitempty"`
+	ServerStatus map[string]string               `json:"serverStatus,omitempty"`
+	ServerHealth map[string]runtime.ServerHealth `json:"serverHealth,omitempty"`
+	Name         string                          `json:"name,omitempty"`
+	Provider     string                          `json:"provider,omitempty"`
+	Type         string                          `json:"type,omitempty"`
 }
 
 func newServiceRepresentation(name string, si *runtime.ServiceInfo) serviceRepresentation {
@@ -46,15 +50,18 @@ func newServiceRepresentation(name string, si *runtime.ServiceInfo) serviceRepre
 		Name:         name,
 		Provider:     getProviderName(name),
 		ServerStatus: si.GetAllStatus(),
+		ServerHealth: si.GetAllHealth(),
 		Type:         strings.ToLower(extractType(si.Service)),
 	}
 }
 
 type middlewareRepresentation struct {
 	*runtime.MiddlewareInfo
-	Name     string `json:"name,omitempty"`
-	Provider string `json:"provider,omitempty"`
-	Type     string `json:"type,omitempty"`
+	Name      string                  `json:"name,omitempty"`
+	Provider  string                  `json:"provider,omitempty"`
+	Type      string                  `json:"type,omitempty"`
+	Overrides *overrides.Parameters   `json:"overrides,omitempty"`
+	Stats     runtime.MiddlewareStats `json:"stats"`
 }
 
 func newMiddlewareRepresentation(name string, mi *runtime.MiddlewareInfo) middlewareRepresentation {
@@ -63,6 +70,7 @@ func newMiddlewareRepresentation(name string, mi *runtime.MiddlewareInfo) middle
 		Name:           name,
 		Provider:       getProviderName(name),
 		Type:           strings.ToLower(extractType(mi.Middleware)),
+		Stats:          mi.GetStats(),
 	}
 }
 
@@ -73,7 +81,9 @@ func (h Handler) getRouters(rw http.ResponseWriter, request *http.Request) {
 
 	for name, rt := range h.runtimeConfiguration.Routers {
 		if keepRouter(name, rt, criterion) {
-			results = append(results, newRouterRepresentation(name, rt))
+			result := newRouterRepresentation(name, rt)
+			result.Maintenance = h.maintenance.Enabled(maintenance.ProtocolHTTP, name)
+			results = append(results, result)
 		}
 	}
 
@@ -110,6 +120,7 @@ func (h Handler) getRouter(rw http.ResponseWriter, request *http.Request) {
 	}
 
 	result := newRouterRepresentation(routerID, router)
+	result.Maintenance = h.maintenance.Enabled(maintenance.ProtocolHTTP, routerID)
 
 	err := json.NewEncoder(rw).Encode(result)
 	if err != nil {
@@ -177,7 +188,11 @@ func (h Handler) getMiddlewares(rw http.ResponseWriter, request *http.Request) {
 
 	for name, mi := range h.runtimeConfiguration.Middlewares {
 		if keepMiddleware(name, mi, criterion) {
-			results = append(results, newMiddlewareRepresentation(name, mi))
+			result := newMiddlewareRepresentation(name, mi)
+			if params, ok := h.overrides.Get(name); ok {
+				result.Overrides = &params
+			}
+			results = append(results, result)
 		}
 	}
 
@@ -214,6 +229,9 @@ func (h Handler) getMiddleware(rw http.ResponseWriter, request *http.Request) {
 	}
 
 	result := newMiddlewareRepresentation(middlewareID, middleware)
+	if params, ok := h.overrides.Get(middlewareID); ok {
+		result.Overrides = &params
+	}
 
 	err := json.NewEncoder(rw).Encode(result)
 	if err != nil {
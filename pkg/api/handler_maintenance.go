@@ -0,0 +1,53 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/maintenance"
+)
+
+func (h Handler) putRouterMaintenance(rw http.ResponseWriter, request *http.Request) {
+	h.setRouterMaintenance(rw, request, true)
+}
+
+func (h Handler) deleteRouterMaintenance(rw http.ResponseWriter, request *http.Request) {
+	h.setRouterMaintenance(rw, request, false)
+}
+
+func (h Handler) setRouterMaintenance(rw http.ResponseWriter, request *http.Request, enabled bool) {
+	routerID := mux.Vars(request)["routerID"]
+
+	if _, ok := h.runtimeConfiguration.Routers[routerID]; !ok {
+		rw.Header().Set("Content-Type", "application/json")
+		writeError(rw, fmt.Sprintf("router not found: %s", routerID), http.StatusNotFound)
+		return
+	}
+
+	h.maintenance.Set(maintenance.ProtocolHTTP, routerID, enabled)
+
+	h.getRouter(rw, request)
+}
+
+func (h Handler) putTCPRouterMaintenance(rw http.ResponseWriter, request *http.Request) {
+	h.setTCPRouterMaintenance(rw, request, true)
+}
+
+func (h Handler) deleteTCPRouterMaintenance(rw http.ResponseWriter, request *http.Request) {
+	h.setTCPRouterMaintenance(rw, request, false)
+}
+
+func (h Handler) setTCPRouterMaintenance(rw http.ResponseWriter, request *http.Request, enabled bool) {
+	routerID := mux.Vars(request)["routerID"]
+
+	if _, ok := h.runtimeConfiguration.TCPRouters[routerID]; !ok {
+		rw.Header().Set("Content-Type", "application/json")
+		writeError(rw, fmt.Sprintf("router not found: %s", routerID), http.StatusNotFound)
+		return
+	}
+
+	h.maintenance.Set(maintenance.ProtocolTCP, routerID, enabled)
+
+	h.getTCPRouter(rw, request)
+}
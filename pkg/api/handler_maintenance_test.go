@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/maintenance"
+)
+
+func TestHandler_RouterMaintenance(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		protocol string
+		path     string
+	}{
+		{
+			desc:     "HTTP router",
+			protocol: maintenance.ProtocolHTTP,
+			path:     "/api/http/routers/foo@file",
+		},
+		{
+			desc:     "TCP router",
+			protocol: maintenance.ProtocolTCP,
+			path:     "/api/tcp/routers/foo@file",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rtConf := &runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{
+					"foo@file": {Router: &dynamic.Router{EntryPoints: []string{"web"}, Service: "foo-service@file", Rule: "Host(`foo.bar`)"}},
+				},
+				TCPRouters: map[string]*runtime.TCPRouterInfo{
+					"foo@file": {TCPRouter: &dynamic.TCPRouter{EntryPoints: []string{"web"}, Service: "foo-service@file", Rule: "HostSNI(`foo.bar`)"}},
+				},
+			}
+
+			flags := maintenance.NewFlags(maintenance.Response{})
+
+			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf)
+			handler.maintenance = flags
+
+			server := httptest.NewServer(handler.createRouter())
+			defer server.Close()
+
+			resp := doRequest(t, http.MethodPut, server.URL+test.path+"/maintenance")
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.True(t, decodeMaintenance(t, resp))
+			assert.True(t, flags.Enabled(test.protocol, "foo@file"))
+
+			resp = doRequest(t, http.MethodGet, server.URL+test.path)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.True(t, decodeMaintenance(t, resp))
+
+			resp = doRequest(t, http.MethodDelete, server.URL+test.path+"/maintenance")
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.False(t, decodeMaintenance(t, resp))
+			assert.False(t, flags.Enabled(test.protocol, "foo@file"))
+
+			resp = doRequest(t, http.MethodPut, server.URL+test.path[:len(test.path)-len("foo@file")]+"bar@file/maintenance")
+			_ = resp.Body.Close()
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			assert.False(t, flags.Enabled(test.protocol, "bar@file"))
+		})
+	}
+}
+
+func TestHandler_RouterMaintenance_disabled(t *testing.T) {
+	rtConf := &runtime.Configuration{
+		Routers: map[string]*runtime.RouterInfo{
+			"foo@file": {Router: &dynamic.Router{EntryPoints: []string{"web"}, Service: "foo-service@file", Rule: "Host(`foo.bar`)"}},
+		},
+	}
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf)
+	server := httptest.NewServer(handler.createRouter())
+	defer server.Close()
+
+	resp := doRequest(t, http.MethodPut, server.URL+"/api/http/routers/foo@file/maintenance")
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func doRequest(t *testing.T, method, url string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	return resp
+}
+
+func decodeMaintenance(t *testing.T, resp *http.Response) bool {
+	t.Helper()
+
+	defer func() { _ = resp.Body.Close() }()
+
+	var router struct {
+		Maintenance bool `json:"maintenance"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&router))
+
+	return router.Maintenance
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/overrides"
+)
+
+func (h Handler) putMiddlewareOverrides(rw http.ResponseWriter, request *http.Request) {
+	middlewareID := mux.Vars(request)["middlewareID"]
+
+	middleware, ok := h.runtimeConfiguration.Middlewares[middlewareID]
+	if !ok {
+		rw.Header().Set("Content-Type", "application/json")
+		writeError(rw, fmt.Sprintf("middleware not found: %s", middlewareID), http.StatusNotFound)
+		return
+	}
+
+	var params overrides.Parameters
+	if err := json.NewDecoder(request.Body).Decode(&params); err != nil {
+		rw.Header().Set("Content-Type", "application/json")
+		writeError(rw, fmt.Sprintf("invalid overrides: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := checkOverrides(middleware.Middleware, params); err != nil {
+		rw.Header().Set("Content-Type", "application/json")
+		writeError(rw, fmt.Sprintf("invalid overrides: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	h.overrides.Set(middlewareID, params)
+
+	h.getMiddleware(rw, request)
+}
+
+func (h Handler) deleteMiddlewareOverrides(rw http.ResponseWriter, request *http.Request) {
+	middlewareID := mux.Vars(request)["middlewareID"]
+
+	if _, ok := h.runtimeConfiguration.Middlewares[middlewareID]; !ok {
+		rw.Header().Set("Content-Type", "application/json")
+		writeError(rw, fmt.Sprintf("middleware not found: %s", middlewareID), http.StatusNotFound)
+		return
+	}
+
+	h.overrides.Delete(middlewareID)
+
+	h.getMiddleware(rw, request)
+}
+
+// checkOverrides checks that the parameters are valid, and match the type of the middleware.
+func checkOverrides(middleware *dynamic.Middleware, params overrides.Parameters) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	switch {
+	case params.RateLimit != nil && middleware.RateLimit == nil:
+		return errors.New("the rate limit parameters only apply to a RateLimit middleware")
+	case params.Compress != nil && middleware.Compress == nil:
+		return errors.New("the compression parameters only apply to a Compress middleware")
+	case params.Accounting != nil && middleware.Accounting == nil:
+		return errors.New("the sampling parameters only apply to an Accounting middleware")
+	}
+
+	return nil
+}
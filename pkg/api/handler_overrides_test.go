@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/overrides"
+)
+
+func TestHandler_MiddlewareOverrides(t *testing.T) {
+	rtConf := &runtime.Configuration{
+		Middlewares: map[string]*runtime.MiddlewareInfo{
+			"compress@file": {Middleware: &dynamic.Middleware{Compress: &dynamic.Compress{}}},
+		},
+	}
+
+	store := overrides.NewStore()
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf)
+	handler.overrides = store
+
+	server := httptest.NewServer(handler.createRouter())
+	defer server.Close()
+
+	resp := doBodyRequest(t, http.MethodPut, server.URL+"/api/http/middlewares/compress@file/overrides", `{"compress":{"compressionLevel":1}}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var middleware middlewareRepresentation
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&middleware))
+	_ = resp.Body.Close()
+
+	expected := overrides.Parameters{Compress: &overrides.Compress{CompressionLevel: 1}}
+	assert.Equal(t, &expected, middleware.Overrides)
+
+	params, ok := store.Get("compress@file")
+	require.True(t, ok)
+	assert.Equal(t, expected, params)
+
+	// The overrides are marked as ephemeral in the raw data.
+	resp = doRequest(t, http.MethodGet, server.URL+"/api/rawdata")
+	var rawData RunTimeRepresentation
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&rawData))
+	_ = resp.Body.Close()
+	assert.Equal(t, map[string]overrides.Parameters{"compress@file": expected}, rawData.EphemeralOverrides)
+
+	resp = doBodyRequest(t, http.MethodPut, server.URL+"/api/http/middlewares/compress@file/overrides", `{"rateLimit":{"average":100}}`)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = doBodyRequest(t, http.MethodPut, server.URL+"/api/http/middlewares/compress@file/overrides", `{"compress":{"compressionLevel":42}}`)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = doBodyRequest(t, http.MethodPut, server.URL+"/api/http/middlewares/bar@file/overrides", `{"compress":{"compressionLevel":1}}`)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp = doRequest(t, http.MethodDelete, server.URL+"/api/http/middlewares/compress@file/overrides")
+	middleware = middlewareRepresentation{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&middleware))
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, middleware.Overrides)
+
+	_, ok = store.Get("compress@file")
+	assert.False(t, ok)
+}
+
+func TestHandler_MiddlewareOverrides_disabled(t *testing.T) {
+	rtConf := &runtime.Configuration{
+		Middlewares: map[string]*runtime.MiddlewareInfo{
+			"compress@file": {Middleware: &dynamic.Middleware{Compress: &dynamic.Compress{}}},
+		},
+	}
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf)
+	server := httptest.NewServer(handler.createRouter())
+	defer server.Close()
+
+	resp := doBodyRequest(t, http.MethodPut, server.URL+"/api/http/middlewares/compress@file/overrides", `{"compress":{"compressionLevel":1}}`)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func doBodyRequest(t *testing.T, method, url, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	return resp
+}
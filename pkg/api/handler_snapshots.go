@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/snapshot"
+)
+
+type snapshotsRepresentation struct {
+	Current   string          `json:"current,omitempty"`
+	Pinned    string          `json:"pinned,omitempty"`
+	Snapshots []snapshot.Info `json:"snapshots"`
+}
+
+func (h Handler) getSnapshots(rw http.ResponseWriter, request *http.Request) {
+	result := snapshotsRepresentation{
+		Current:   h.snapshots.Current(),
+		Pinned:    h.snapshots.Pinned(),
+		Snapshots: h.snapshots.List(),
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// getSnapshot exports the configuration of a snapshot, in a format that can be imported back.
+func (h Handler) getSnapshot(rw http.ResponseWriter, request *http.Request) {
+	hash := mux.Vars(request)["hash"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	conf, ok := h.snapshots.Get(hash)
+	if !ok {
+		writeError(rw, fmt.Sprintf("snapshot not found: %s", hash), http.StatusNotFound)
+		return
+	}
+
+	err := json.NewEncoder(rw).Encode(conf)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// postSnapshot imports a configuration as a snapshot, which can then be pinned.
+func (h Handler) postSnapshot(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	var conf dynamic.Configuration
+	if err := json.NewDecoder(request.Body).Decode(&conf); err != nil {
+		writeError(rw, fmt.Sprintf("invalid snapshot: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// The configuration listeners expect all the sections of the configuration to be set.
+	if conf.HTTP == nil {
+		conf.HTTP = &dynamic.HTTPConfiguration{}
+	}
+	if conf.TCP == nil {
+		conf.TCP = &dynamic.TCPConfiguration{}
+	}
+	if conf.UDP == nil {
+		conf.UDP = &dynamic.UDPConfiguration{}
+	}
+	if conf.TLS == nil {
+		conf.TLS = &dynamic.TLSConfiguration{}
+	}
+
+	hash, err := h.snapshots.Add(conf, snapshot.SourceImport)
+	if err != nil {
+		writeError(rw, fmt.Sprintf("invalid snapshot: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	rw.WriteHeader(http.StatusCreated)
+
+	err = json.NewEncoder(rw).Encode(struct {
+		Hash string `json:"hash"`
+	}{Hash: hash})
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+	}
+}
+
+func (h Handler) putSnapshotPin(rw http.ResponseWriter, request *http.Request) {
+	hash := mux.Vars(request)["hash"]
+
+	if err := h.snapshots.Pin(hash); err != nil {
+		rw.Header().Set("Content-Type", "application/json")
+
+		if errors.Is(err, snapshot.ErrNotFound) {
+			writeError(rw, fmt.Sprintf("snapshot not found: %s", hash), http.StatusNotFound)
+			return
+		}
+
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.getSnapshots(rw, request)
+}
+
+func (h Handler) deleteSnapshotPin(rw http.ResponseWriter, request *http.Request) {
+	h.snapshots.Unpin()
+
+	h.getSnapshots(rw, request)
+}
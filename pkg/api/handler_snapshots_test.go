@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/snapshot"
+)
+
+func TestHandler_Snapshots(t *testing.T) {
+	store := snapshot.NewStore(10)
+
+	conf := dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"foo@file": {Rule: "Host(`foo.localhost`)", Service: "bar@file"},
+			},
+		},
+		TCP: &dynamic.TCPConfiguration{},
+		UDP: &dynamic.UDPConfiguration{},
+		TLS: &dynamic.TLSConfiguration{},
+	}
+
+	hash, err := store.Add(conf, snapshot.SourceProviders)
+	require.NoError(t, err)
+	store.SetCurrent(hash)
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, nil)
+	handler.snapshots = store
+
+	server := httptest.NewServer(handler.createRouter())
+	defer server.Close()
+
+	// Export.
+	resp := doRequest(t, http.MethodGet, server.URL+"/api/snapshots/"+hash)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	exported, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	resp = doRequest(t, http.MethodGet, server.URL+"/api/snapshots/unknown")
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// Importing an exported snapshot yields the same hash.
+	resp = doBodyRequest(t, http.MethodPost, server.URL+"/api/snapshots", string(exported))
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var imported struct {
+		Hash string `json:"hash"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&imported))
+	_ = resp.Body.Close()
+	assert.Equal(t, hash, imported.Hash)
+
+	// Import of another configuration.
+	resp = doBodyRequest(t, http.MethodPost, server.URL+"/api/snapshots", `{"http":{"routers":{"bar@file":{"rule":"Host(`+"`bar.localhost`"+`)","service":"bar@file"}}}}`)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&imported))
+	_ = resp.Body.Close()
+	assert.NotEqual(t, hash, imported.Hash)
+
+	importedConf, ok := store.Get(imported.Hash)
+	require.True(t, ok)
+	assert.NotNil(t, importedConf.TLS)
+	assert.Contains(t, importedConf.HTTP.Routers, "bar@file")
+
+	resp = doBodyRequest(t, http.MethodPost, server.URL+"/api/snapshots", `{"http":`)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// Pin.
+	resp = doRequest(t, http.MethodPut, server.URL+"/api/snapshots/"+imported.Hash+"/pin")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var snapshots snapshotsRepresentation
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&snapshots))
+	_ = resp.Body.Close()
+
+	assert.Equal(t, hash, snapshots.Current)
+	assert.Equal(t, imported.Hash, snapshots.Pinned)
+	require.Len(t, snapshots.Snapshots, 2)
+	assert.Equal(t, imported.Hash, snapshots.Snapshots[0].Hash)
+	assert.Equal(t, snapshot.SourceImport, snapshots.Snapshots[0].Source)
+
+	resp = doRequest(t, http.MethodPut, server.URL+"/api/snapshots/unknown/pin")
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, imported.Hash, store.Pinned())
+
+	// Unpin.
+	resp = doRequest(t, http.MethodDelete, server.URL+"/api/snapshots/pin")
+	snapshots = snapshotsRepresentation{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&snapshots))
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, snapshots.Pinned)
+	assert.Empty(t, store.Pinned())
+}
+
+func TestHandler_Snapshots_disabled(t *testing.T) {
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, nil)
+	server := httptest.NewServer(handler.createRouter())
+	defer server.Close()
+
+	resp := doRequest(t, http.MethodGet, server.URL+"/api/snapshots")
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
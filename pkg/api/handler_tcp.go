@@ -11,12 +11,14 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/maintenance"
 )
 
 type tcpRouterRepresentation struct {
 	*runtime.TCPRouterInfo
-	Name     string `json:"name,omitempty"`
-	Provider string `json:"provider,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Provider    string `json:"provider,omitempty"`
+	Maintenance bool   `json:"maintenance,omitempty"`
 }
 
 func newTCPRouterRepresentation(name string, rt *runtime.TCPRouterInfo) tcpRouterRepresentation {
@@ -45,9 +47,10 @@ func newTCPServiceRepresentation(name string, si *runtime.TCPServiceInfo) tcpSer
 
 type tcpMiddlewareRepresentation struct {
 	*runtime.TCPMiddlewareInfo
-	Name     string `json:"name,omitempty"`
-	Provider string `json:"provider,omitempty"`
-	Type     string `json:"type,omitempty"`
+	Name     string                  `json:"name,omitempty"`
+	Provider string                  `json:"provider,omitempty"`
+	Type     string                  `json:"type,omitempty"`
+	Stats    runtime.MiddlewareStats `json:"stats"`
 }
 
 func newTCPMiddlewareRepresentation(name string, mi *runtime.TCPMiddlewareInfo) tcpMiddlewareRepresentation {
@@ -56,6 +59,7 @@ func newTCPMiddlewareRepresentation(name string, mi *runtime.TCPMiddlewareInfo)
 		Name:              name,
 		Provider:          getProviderName(name),
 		Type:              strings.ToLower(extractType(mi.TCPMiddleware)),
+		Stats:             mi.GetStats(),
 	}
 }
 
@@ -66,7 +70,9 @@ func (h Handler) getTCPRouters(rw http.ResponseWriter, request *http.Request) {
 
 	for name, rt := range h.runtimeConfiguration.TCPRouters {
 		if keepTCPRouter(name, rt, criterion) {
-			results = append(results, newTCPRouterRepresentation(name, rt))
+			result := newTCPRouterRepresentation(name, rt)
+			result.Maintenance = h.maintenance.Enabled(maintenance.ProtocolTCP, name)
+			results = append(results, result)
 		}
 	}
 
@@ -103,6 +109,7 @@ func (h Handler) getTCPRouter(rw http.ResponseWriter, request *http.Request) {
 	}
 
 	result := newTCPRouterRepresentation(routerID, router)
+	result.Maintenance = h.maintenance.Enabled(maintenance.ProtocolTCP, routerID)
 
 	err := json.NewEncoder(rw).Encode(result)
 	if err != nil {
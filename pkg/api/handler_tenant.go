@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/tenant"
+)
+
+func (h Handler) getTenants(rw http.ResponseWriter, request *http.Request) {
+	results := h.tenantRollups.Summaries()
+	if results == nil {
+		results = []tenant.Summary{}
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) getTenant(rw http.ResponseWriter, request *http.Request) {
+	tenantID := mux.Vars(request)["tenantID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	result, ok := h.tenantRollups.Summary(tenantID)
+	if !ok {
+		writeError(rw, fmt.Sprintf("tenant not found: %s", tenantID), http.StatusNotFound)
+		return
+	}
+
+	err := json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
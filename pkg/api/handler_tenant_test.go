@@ -0,0 +1,132 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/tenant"
+)
+
+func TestHandler_Tenants(t *testing.T) {
+	type expected struct {
+		statusCode int
+		nextPage   string
+		jsonFile   string
+	}
+
+	rollups := tenant.NewRollups(nil)
+	rollups.ObserveRequest("foo", http.StatusOK, 10, 100)
+	rollups.ObserveRequest("foo", http.StatusInternalServerError, 10, 100)
+	rollups.ConnectionOpened("bar")
+	rollups.ConnectionOpened("bar")
+	rollups.ConnectionClosed("bar", 20, 200)
+
+	testCases := []struct {
+		desc     string
+		path     string
+		rollups  *tenant.Rollups
+		expected expected
+	}{
+		{
+			desc:    "all tenants, but no rollups",
+			path:    "/api/tenants",
+			rollups: nil,
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/tenants-empty.json",
+			},
+		},
+		{
+			desc:    "all tenants",
+			path:    "/api/tenants",
+			rollups: rollups,
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/tenants.json",
+			},
+		},
+		{
+			desc:    "all tenants, pagination, 1 res per page, want page 2",
+			path:    "/api/tenants?page=2&per_page=1",
+			rollups: rollups,
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/tenants-page2.json",
+			},
+		},
+		{
+			desc:    "one tenant by id",
+			path:    "/api/tenants/foo",
+			rollups: rollups,
+			expected: expected{
+				statusCode: http.StatusOK,
+				jsonFile:   "testdata/tenant-foo.json",
+			},
+		},
+		{
+			desc:    "one tenant by id, that does not exist",
+			path:    "/api/tenants/baz",
+			rollups: rollups,
+			expected: expected{
+				statusCode: http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, &runtime.Configuration{})
+			handler.tenantRollups = test.rollups
+			server := httptest.NewServer(handler.createRouter())
+
+			resp, err := http.DefaultClient.Get(server.URL + test.path)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected.nextPage, resp.Header.Get(nextPageHeader))
+
+			require.Equal(t, test.expected.statusCode, resp.StatusCode)
+
+			if test.expected.jsonFile == "" {
+				return
+			}
+
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+			contents, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			err = resp.Body.Close()
+			require.NoError(t, err)
+
+			if *updateExpected {
+				var results interface{}
+				err := json.Unmarshal(contents, &results)
+				require.NoError(t, err)
+
+				newJSON, err := json.MarshalIndent(results, "", "\t")
+				require.NoError(t, err)
+
+				err = os.WriteFile(test.expected.jsonFile, newJSON, 0o644)
+				require.NoError(t, err)
+			}
+
+			data, err := os.ReadFile(test.expected.jsonFile)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(data), string(contents))
+		})
+	}
+}
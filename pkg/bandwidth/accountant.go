@@ -0,0 +1,184 @@
+package bandwidth
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+const (
+	directionRequest  = "request"
+	directionResponse = "response"
+
+	kindWire    = "wire"
+	kindLogical = "logical"
+)
+
+// Key identifies what the transferred bytes are accounted to.
+type Key struct {
+	Router  string
+	Service string
+	Tenant  string
+}
+
+// Usage is a number of transferred bytes.
+// The wire bytes are the ones transferred with the clients, once compressed,
+// and the logical bytes are the ones before the compression.
+type Usage struct {
+	WireRequestBytes     int64
+	WireResponseBytes    int64
+	LogicalRequestBytes  int64
+	LogicalResponseBytes int64
+}
+
+func (u *Usage) add(other Usage) {
+	u.WireRequestBytes += other.WireRequestBytes
+	u.WireResponseBytes += other.WireResponseBytes
+	u.LogicalRequestBytes += other.LogicalRequestBytes
+	u.LogicalResponseBytes += other.LogicalResponseBytes
+}
+
+// Record is the usage accounted to a router, service, and tenant during a time window.
+type Record struct {
+	Router               string    `json:"router"`
+	Service              string    `json:"service"`
+	Tenant               string    `json:"tenant,omitempty"`
+	Start                time.Time `json:"start"`
+	End                  time.Time `json:"end"`
+	WireRequestBytes     int64     `json:"wireRequestBytes"`
+	WireResponseBytes    int64     `json:"wireResponseBytes"`
+	LogicalRequestBytes  int64     `json:"logicalRequestBytes"`
+	LogicalResponseBytes int64     `json:"logicalResponseBytes"`
+}
+
+// Accountant accounts the transferred bytes over time windows,
+// and forwards them to the metrics registry.
+type Accountant struct {
+	registry  metrics.Registry
+	window    time.Duration
+	retention int
+
+	mu sync.Mutex
+	// windows holds the usages by the start of their window, as a Unix time in nanoseconds.
+	windows map[int64]map[Key]*Usage
+
+	// now is used to shift the clock in tests.
+	now func() time.Time
+}
+
+// NewAccountant creates a new Accountant,
+// keeping the usages of the last retention windows of the given duration.
+func NewAccountant(registry metrics.Registry, window time.Duration, retention int) *Accountant {
+	if registry == nil {
+		registry = metrics.NewVoidRegistry()
+	}
+
+	if window <= 0 {
+		window = time.Hour
+	}
+
+	if retention <= 0 {
+		retention = 1
+	}
+
+	return &Accountant{
+		registry:  registry,
+		window:    window,
+		retention: retention,
+		windows:   make(map[int64]map[Key]*Usage),
+		now:       time.Now,
+	}
+}
+
+// Observe accounts the usage to the key, in the current window.
+func (a *Accountant) Observe(key Key, usage Usage) {
+	if a == nil {
+		return
+	}
+
+	start := a.now().Truncate(a.window).UnixNano()
+
+	a.mu.Lock()
+	usages, ok := a.windows[start]
+	if !ok {
+		usages = make(map[Key]*Usage)
+		a.windows[start] = usages
+		a.prune(start)
+	}
+
+	u, ok := usages[key]
+	if !ok {
+		u = &Usage{}
+		usages[key] = u
+	}
+	u.add(usage)
+	a.mu.Unlock()
+
+	counter := a.registry.BandwidthBytesCounter()
+	labels := []string{"router", key.Router, "service", key.Service, "tenant", key.Tenant}
+	counter.With(append(labels, "direction", directionRequest, "kind", kindWire)...).Add(float64(usage.WireRequestBytes))
+	counter.With(append(labels, "direction", directionResponse, "kind", kindWire)...).Add(float64(usage.WireResponseBytes))
+	counter.With(append(labels, "direction", directionRequest, "kind", kindLogical)...).Add(float64(usage.LogicalRequestBytes))
+	counter.With(append(labels, "direction", directionResponse, "kind", kindLogical)...).Add(float64(usage.LogicalResponseBytes))
+}
+
+// Records returns the records of the retained windows,
+// sorted by window, then by router, service, and tenant.
+func (a *Accountant) Records() []Record {
+	if a == nil {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.prune(a.now().Truncate(a.window).UnixNano())
+
+	var records []Record
+	for start, usages := range a.windows {
+		startTime := time.Unix(0, start).UTC()
+
+		for key, u := range usages {
+			records = append(records, Record{
+				Router:               key.Router,
+				Service:              key.Service,
+				Tenant:               key.Tenant,
+				Start:                startTime,
+				End:                  startTime.Add(a.window),
+				WireRequestBytes:     u.WireRequestBytes,
+				WireResponseBytes:    u.WireResponseBytes,
+				LogicalRequestBytes:  u.LogicalRequestBytes,
+				LogicalResponseBytes: u.LogicalResponseBytes,
+			})
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].Start.Equal(records[j].Start) {
+			return records[i].Start.Before(records[j].Start)
+		}
+		if records[i].Router != records[j].Router {
+			return records[i].Router < records[j].Router
+		}
+		if records[i].Service != records[j].Service {
+			return records[i].Service < records[j].Service
+		}
+		return records[i].Tenant < records[j].Tenant
+	})
+
+	return records
+}
+
+// prune drops the windows which are out of the retention, given the start of the current one.
+// It must be called with the lock held.
+func (a *Accountant) prune(current int64) {
+	oldest := current - int64(a.retention-1)*a.window.Nanoseconds()
+
+	for start := range a.windows {
+		if start < oldest {
+			delete(a.windows, start)
+		}
+	}
+}
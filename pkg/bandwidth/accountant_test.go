@@ -0,0 +1,123 @@
+package bandwidth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountant_Observe(t *testing.T) {
+	now := time.Date(2023, time.May, 1, 10, 30, 0, 0, time.UTC)
+
+	accountant := NewAccountant(nil, time.Hour, 24)
+	accountant.now = func() time.Time { return now }
+
+	foo := Key{Router: "foo@file", Service: "foo@file", Tenant: "acme"}
+	bar := Key{Router: "bar@file", Service: "bar@file"}
+
+	accountant.Observe(foo, Usage{WireRequestBytes: 10, WireResponseBytes: 100, LogicalRequestBytes: 10, LogicalResponseBytes: 300})
+	accountant.Observe(foo, Usage{WireRequestBytes: 20, WireResponseBytes: 200, LogicalRequestBytes: 20, LogicalResponseBytes: 200})
+	accountant.Observe(bar, Usage{WireRequestBytes: 1, WireResponseBytes: 2, LogicalRequestBytes: 1, LogicalResponseBytes: 2})
+
+	now = now.Add(time.Hour)
+	accountant.Observe(foo, Usage{WireRequestBytes: 5, WireResponseBytes: 50, LogicalRequestBytes: 5, LogicalResponseBytes: 80})
+
+	first := time.Date(2023, time.May, 1, 10, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+
+	expected := []Record{
+		{
+			Router:               "bar@file",
+			Service:              "bar@file",
+			Start:                first,
+			End:                  second,
+			WireRequestBytes:     1,
+			WireResponseBytes:    2,
+			LogicalRequestBytes:  1,
+			LogicalResponseBytes: 2,
+		},
+		{
+			Router:               "foo@file",
+			Service:              "foo@file",
+			Tenant:               "acme",
+			Start:                first,
+			End:                  second,
+			WireRequestBytes:     30,
+			WireResponseBytes:    300,
+			LogicalRequestBytes:  30,
+			LogicalResponseBytes: 500,
+		},
+		{
+			Router:               "foo@file",
+			Service:              "foo@file",
+			Tenant:               "acme",
+			Start:                second,
+			End:                  second.Add(time.Hour),
+			WireRequestBytes:     5,
+			WireResponseBytes:    50,
+			LogicalRequestBytes:  5,
+			LogicalResponseBytes: 80,
+		},
+	}
+
+	assert.Equal(t, expected, accountant.Records())
+}
+
+func TestAccountant_retention(t *testing.T) {
+	now := time.Date(2023, time.May, 1, 10, 0, 0, 0, time.UTC)
+
+	accountant := NewAccountant(nil, time.Minute, 2)
+	accountant.now = func() time.Time { return now }
+
+	key := Key{Router: "foo@file", Service: "foo@file"}
+
+	accountant.Observe(key, Usage{WireRequestBytes: 1})
+
+	now = now.Add(time.Minute)
+	accountant.Observe(key, Usage{WireRequestBytes: 2})
+
+	assert.Len(t, accountant.Records(), 2)
+
+	// The first window is now out of the retention.
+	now = now.Add(time.Minute)
+
+	records := accountant.Records()
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, int64(2), records[0].WireRequestBytes)
+	}
+
+	now = now.Add(time.Hour)
+	assert.Empty(t, accountant.Records())
+}
+
+func TestAccountant_nil(t *testing.T) {
+	var accountant *Accountant
+
+	accountant.Observe(Key{Router: "foo@file"}, Usage{WireRequestBytes: 1})
+	assert.Nil(t, accountant.Records())
+}
+
+func TestLogicalSize_Wrap(t *testing.T) {
+	ctx, size := WithLogicalSize(httptest.NewRequest(http.MethodGet, "http://foo.bar", nil).Context())
+	assert.Same(t, size, LogicalSizeFromContext(ctx))
+
+	_, ok := size.Bytes()
+	assert.False(t, ok)
+
+	recorder := httptest.NewRecorder()
+
+	rw := size.Wrap(recorder)
+	assert.NotSame(t, recorder, rw)
+
+	// Only the first wrapped response writer counts.
+	assert.Same(t, recorder, size.Wrap(recorder))
+
+	_, _ = rw.Write([]byte("traefik"))
+
+	n, ok := size.Bytes()
+	assert.True(t, ok)
+	assert.Equal(t, int64(len("traefik")), n)
+}
@@ -0,0 +1,84 @@
+package bandwidth
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+)
+
+type logicalSizeKey struct{}
+
+// LogicalSize counts the bytes of a response before it is compressed.
+type LogicalSize struct {
+	claimed atomic.Bool
+	bytes   atomic.Int64
+}
+
+// WithLogicalSize returns a context holding a new LogicalSize, along with the LogicalSize.
+func WithLogicalSize(ctx context.Context) (context.Context, *LogicalSize) {
+	size := &LogicalSize{}
+	return context.WithValue(ctx, logicalSizeKey{}, size), size
+}
+
+// LogicalSizeFromContext returns the LogicalSize held by the context, if any.
+func LogicalSizeFromContext(ctx context.Context) *LogicalSize {
+	size, _ := ctx.Value(logicalSizeKey{}).(*LogicalSize)
+	return size
+}
+
+// Wrap returns a response writer counting the bytes written to rw,
+// meant to wrap the response writer of a compressing writer.
+// Only the first wrapped response writer counts the bytes, the other ones are returned as is,
+// for the logical size to be the one before the outermost compression.
+func (s *LogicalSize) Wrap(rw http.ResponseWriter) http.ResponseWriter {
+	if s == nil || !s.claimed.CompareAndSwap(false, true) {
+		return rw
+	}
+
+	return &countingResponseWriter{ResponseWriter: rw, size: s}
+}
+
+// Bytes returns the counted bytes, and whether a response writer counted them.
+func (s *LogicalSize) Bytes() (int64, bool) {
+	if s == nil || !s.claimed.Load() {
+		return 0, false
+	}
+
+	return s.bytes.Load(), true
+}
+
+type countingResponseWriter struct {
+	http.ResponseWriter
+
+	size *LogicalSize
+}
+
+func (c *countingResponseWriter) Write(p []byte) (int, error) {
+	n, err := c.ResponseWriter.Write(p)
+	c.size.bytes.Add(int64(n))
+	return n, err
+}
+
+// Flush sends any buffered data to the client.
+func (c *countingResponseWriter) Flush() {
+	if f, ok := c.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack hijacks the connection.
+func (c *countingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := c.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+
+	return nil, nil, fmt.Errorf("not a hijacker: %T", c.ResponseWriter)
+}
+
+// Unwrap returns the wrapped response writer, for http.ResponseController.
+func (c *countingResponseWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
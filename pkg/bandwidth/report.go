@@ -0,0 +1,206 @@
+package bandwidth
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// Report is the compression effectiveness of the routers, aggregated over the last time windows.
+// The bytes of both directions are summed, and the saved bytes are the logical bytes which were not transferred on the wire.
+type Report struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	ReportUsage
+
+	Routers    []RouterReport `json:"routers"`
+	TopTalkers []TalkerReport `json:"topTalkers"`
+}
+
+// ReportUsage is the aggregated usage of a report.
+type ReportUsage struct {
+	WireBytes    int64 `json:"wireBytes"`
+	LogicalBytes int64 `json:"logicalBytes"`
+	SavedBytes   int64 `json:"savedBytes"`
+	// CompressionRatio is the ratio of the logical bytes to the wire bytes, or 1 if nothing was transferred.
+	CompressionRatio float64 `json:"compressionRatio"`
+	// SavedBytesPerSecond is the bandwidth saved on average over the report time range.
+	SavedBytesPerSecond float64 `json:"savedBytesPerSecond"`
+}
+
+// RouterReport is the usage of a router in a report.
+type RouterReport struct {
+	Router string `json:"router"`
+	ReportUsage
+}
+
+// TalkerReport is the usage of a tenant on a router in a report.
+type TalkerReport struct {
+	Router string `json:"router"`
+	Tenant string `json:"tenant,omitempty"`
+	ReportUsage
+}
+
+// Report returns the report of the given number of last windows, including the current one,
+// or of all the retained windows if windows is not positive,
+// with the top talkers being the given number, or all if top is not positive, of tenants of the routers transferring the most wire bytes.
+func (a *Accountant) Report(windows, top int) Report {
+	if a == nil {
+		return Report{Routers: []RouterReport{}, TopTalkers: []TalkerReport{}}
+	}
+
+	if windows <= 0 || windows > a.retention {
+		windows = a.retention
+	}
+
+	// The report ends now, in the middle of the current window, so that the saved bandwidth is averaged over the elapsed time.
+	end := a.now()
+	start := end.Truncate(a.window).Add(-time.Duration(windows-1) * a.window)
+
+	var total usageSum
+	routers := make(map[string]*usageSum)
+	talkers := make(map[[2]string]*usageSum)
+
+	for _, record := range a.Records() {
+		if record.Start.Before(start) {
+			continue
+		}
+
+		total.add(record)
+
+		if routers[record.Router] == nil {
+			routers[record.Router] = &usageSum{}
+		}
+		routers[record.Router].add(record)
+
+		key := [2]string{record.Router, record.Tenant}
+		if talkers[key] == nil {
+			talkers[key] = &usageSum{}
+		}
+		talkers[key].add(record)
+	}
+
+	duration := end.Sub(start)
+
+	report := Report{
+		Start:       start.UTC(),
+		End:         end.UTC(),
+		ReportUsage: total.usage(duration),
+		Routers:     make([]RouterReport, 0, len(routers)),
+		TopTalkers:  make([]TalkerReport, 0, len(talkers)),
+	}
+
+	for router, sum := range routers {
+		report.Routers = append(report.Routers, RouterReport{Router: router, ReportUsage: sum.usage(duration)})
+	}
+
+	sort.Slice(report.Routers, func(i, j int) bool {
+		return report.Routers[i].Router < report.Routers[j].Router
+	})
+
+	for key, sum := range talkers {
+		report.TopTalkers = append(report.TopTalkers, TalkerReport{Router: key[0], Tenant: key[1], ReportUsage: sum.usage(duration)})
+	}
+
+	sort.Slice(report.TopTalkers, func(i, j int) bool {
+		if report.TopTalkers[i].WireBytes != report.TopTalkers[j].WireBytes {
+			return report.TopTalkers[i].WireBytes > report.TopTalkers[j].WireBytes
+		}
+		if report.TopTalkers[i].Router != report.TopTalkers[j].Router {
+			return report.TopTalkers[i].Router < report.TopTalkers[j].Router
+		}
+		return report.TopTalkers[i].Tenant < report.TopTalkers[j].Tenant
+	})
+
+	if top > 0 && len(report.TopTalkers) > top {
+		report.TopTalkers = report.TopTalkers[:top]
+	}
+
+	return report
+}
+
+// WriteReports writes the report of the given number of last windows to the given file, at each interval,
+// until the context is done.
+func (a *Accountant) WriteReports(ctx context.Context, path string, interval time.Duration, windows, top int) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	logger := log.FromContext(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := writeReport(path, a.Report(windows, top)); err != nil {
+				logger.Errorf("Error while writing the bandwidth report: %v", err)
+			}
+		}
+	}
+}
+
+// writeReport writes the report to a temporary file first, and renames it,
+// so that the readers of the file never see a partial report.
+func writeReport(path string, report Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+type usageSum struct {
+	wire    int64
+	logical int64
+}
+
+func (s *usageSum) add(record Record) {
+	s.wire += record.WireRequestBytes + record.WireResponseBytes
+	s.logical += record.LogicalRequestBytes + record.LogicalResponseBytes
+}
+
+func (s *usageSum) usage(duration time.Duration) ReportUsage {
+	usage := ReportUsage{
+		WireBytes:        s.wire,
+		LogicalBytes:     s.logical,
+		SavedBytes:       s.logical - s.wire,
+		CompressionRatio: 1,
+	}
+
+	if s.wire > 0 {
+		usage.CompressionRatio = float64(s.logical) / float64(s.wire)
+	}
+
+	if duration > 0 {
+		usage.SavedBytesPerSecond = float64(usage.SavedBytes) / duration.Seconds()
+	}
+
+	return usage
+}
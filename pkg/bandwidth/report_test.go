@@ -0,0 +1,105 @@
+package bandwidth
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountant_Report(t *testing.T) {
+	now := time.Date(2023, time.May, 1, 10, 30, 0, 0, time.UTC)
+
+	accountant := NewAccountant(nil, time.Hour, 24)
+	accountant.now = func() time.Time { return now }
+
+	foo := Key{Router: "foo@file", Service: "foo@file", Tenant: "acme"}
+	bar := Key{Router: "bar@file", Service: "bar@file", Tenant: "acme"}
+	baz := Key{Router: "foo@file", Service: "foo@file", Tenant: "other"}
+
+	accountant.Observe(foo, Usage{WireRequestBytes: 100, WireResponseBytes: 100, LogicalRequestBytes: 100, LogicalResponseBytes: 100})
+
+	now = now.Add(time.Hour)
+	accountant.Observe(foo, Usage{WireRequestBytes: 10, WireResponseBytes: 100, LogicalRequestBytes: 10, LogicalResponseBytes: 350})
+	accountant.Observe(bar, Usage{WireResponseBytes: 10, LogicalResponseBytes: 10})
+	accountant.Observe(baz, Usage{WireResponseBytes: 200, LogicalResponseBytes: 1000})
+
+	report := accountant.Report(1, 2)
+
+	assert.Equal(t, time.Date(2023, time.May, 1, 11, 0, 0, 0, time.UTC), report.Start)
+	assert.Equal(t, now, report.End)
+
+	assert.Equal(t, int64(320), report.WireBytes)
+	assert.Equal(t, int64(1370), report.LogicalBytes)
+	assert.Equal(t, int64(1050), report.SavedBytes)
+	assert.InDelta(t, 1370.0/320, report.CompressionRatio, 0.001)
+	// The saved bytes are averaged over the 30 minutes elapsed in the current window.
+	assert.InDelta(t, 1050.0/1800, report.SavedBytesPerSecond, 0.001)
+
+	expectedRouters := []RouterReport{
+		{
+			Router:      "bar@file",
+			ReportUsage: ReportUsage{WireBytes: 10, LogicalBytes: 10, CompressionRatio: 1},
+		},
+		{
+			Router: "foo@file",
+			ReportUsage: ReportUsage{
+				WireBytes:           310,
+				LogicalBytes:        1360,
+				SavedBytes:          1050,
+				CompressionRatio:    1360.0 / 310,
+				SavedBytesPerSecond: 1050.0 / 1800,
+			},
+		},
+	}
+	assert.Equal(t, expectedRouters, report.Routers)
+
+	require.Len(t, report.TopTalkers, 2)
+	assert.Equal(t, "other", report.TopTalkers[0].Tenant)
+	assert.Equal(t, "foo@file", report.TopTalkers[1].Router)
+	assert.Equal(t, "acme", report.TopTalkers[1].Tenant)
+
+	// All the retained windows.
+	report = accountant.Report(0, 0)
+
+	assert.Equal(t, int64(520), report.WireBytes)
+	assert.Len(t, report.TopTalkers, 3)
+}
+
+func TestAccountant_Report_nil(t *testing.T) {
+	var accountant *Accountant
+
+	report := accountant.Report(0, 10)
+	assert.Empty(t, report.Routers)
+	assert.Empty(t, report.TopTalkers)
+}
+
+func TestAccountant_WriteReports(t *testing.T) {
+	accountant := NewAccountant(nil, time.Hour, 24)
+	accountant.Observe(Key{Router: "foo@file", Service: "foo@file"}, Usage{WireResponseBytes: 10, LogicalResponseBytes: 40})
+
+	path := filepath.Join(t.TempDir(), "report.json")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go accountant.WriteReports(ctx, path, 10*time.Millisecond, 0, 10)
+
+	var report Report
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return false
+		}
+
+		return json.Unmarshal(data, &report) == nil
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, int64(30), report.SavedBytes)
+	assert.InDelta(t, 4, report.CompressionRatio, 0.001)
+}
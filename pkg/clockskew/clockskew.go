@@ -0,0 +1,127 @@
+package clockskew
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+// Checker compares the system clock to the validity of the certificates, and optionally to an NTP reference,
+// and alerts through the logs and the metrics when the clock skew would cause TLS handshake failures.
+type Checker struct {
+	registry     metrics.Registry
+	certificates func() []*x509.Certificate
+	ntpServer    string
+	maxSkew      time.Duration
+
+	mu sync.Mutex
+	// offset is the last measured offset of the NTP reference from the system clock.
+	offset time.Duration
+
+	// now is used to shift the clock in tests.
+	now func() time.Time
+}
+
+// NewChecker creates a new Checker of the certificates returned by the given function.
+// The NTP server is optional, and the system clock is only alerted about when it is more than maxSkew off the reference.
+func NewChecker(registry metrics.Registry, certificates func() []*x509.Certificate, ntpServer string, maxSkew time.Duration) *Checker {
+	if registry == nil {
+		registry = metrics.NewVoidRegistry()
+	}
+
+	return &Checker{
+		registry:     registry,
+		certificates: certificates,
+		ntpServer:    ntpServer,
+		maxSkew:      maxSkew,
+		now:          time.Now,
+	}
+}
+
+// Run checks the clock at startup, then at each interval, until the context is done.
+func (c *Checker) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check measures the offset of the system clock from the NTP reference, if any,
+// then checks the certificates against the corrected clock.
+// It returns the descriptions of the problems found, which are also logged.
+func (c *Checker) Check(ctx context.Context) []string {
+	logger := log.FromContext(ctx)
+
+	var problems []string
+
+	if c.ntpServer != "" {
+		offset, err := queryNTP(ctx, c.ntpServer)
+		if err != nil {
+			logger.Warnf("Unable to query the NTP server %s: %v", c.ntpServer, err)
+		} else {
+			c.mu.Lock()
+			c.offset = offset
+			c.mu.Unlock()
+
+			c.registry.ClockSkewGauge().Set(offset.Seconds())
+
+			if offset > c.maxSkew || offset < -c.maxSkew {
+				problem := fmt.Sprintf("the system clock is %s off the NTP server %s", offset, c.ntpServer)
+				logger.Errorf("Clock skew detected: %s", problem)
+				problems = append(problems, problem)
+			}
+		}
+	}
+
+	return append(problems, c.CheckCertificates(ctx)...)
+}
+
+// CheckCertificates checks the certificates against the system clock, corrected by the last measured NTP offset.
+// It returns the descriptions of the problems found, which are also logged.
+func (c *Checker) CheckCertificates(ctx context.Context) []string {
+	logger := log.FromContext(ctx)
+
+	c.mu.Lock()
+	offset := c.offset
+	c.mu.Unlock()
+
+	now := c.now()
+	reference := now.Add(offset)
+
+	var problems []string
+	for _, cert := range c.certificates() {
+		var problem string
+		switch {
+		case now.Before(cert.NotBefore):
+			problem = fmt.Sprintf("certificate %s is not valid before %s according to the system clock, which may be behind", cert.Subject.CommonName, cert.NotBefore)
+		case now.After(cert.NotAfter) && !reference.After(cert.NotAfter):
+			problem = fmt.Sprintf("certificate %s is expired according to the system clock, but not the NTP server %s", cert.Subject.CommonName, c.ntpServer)
+		default:
+			continue
+		}
+
+		logger.Errorf("Clock skew detected: %s", problem)
+		problems = append(problems, problem)
+	}
+
+	c.registry.ClockSkewCertsGauge().Set(float64(len(problems)))
+
+	return problems
+}
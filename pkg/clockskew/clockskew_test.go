@@ -0,0 +1,130 @@
+package clockskew
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker_CheckCertificates(t *testing.T) {
+	now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc             string
+		cert             *x509.Certificate
+		offset           time.Duration
+		expectedProblems int
+	}{
+		{
+			desc: "valid certificate",
+			cert: &x509.Certificate{
+				NotBefore: now.Add(-time.Hour),
+				NotAfter:  now.Add(time.Hour),
+			},
+		},
+		{
+			desc: "certificate not valid yet",
+			cert: &x509.Certificate{
+				NotBefore: now.Add(time.Minute),
+				NotAfter:  now.Add(time.Hour),
+			},
+			expectedProblems: 1,
+		},
+		{
+			desc: "certificate expired according to the system clock only",
+			cert: &x509.Certificate{
+				NotBefore: now.Add(-time.Hour),
+				NotAfter:  now.Add(-time.Minute),
+			},
+			offset:           -2 * time.Minute,
+			expectedProblems: 1,
+		},
+		{
+			desc: "certificate expired",
+			cert: &x509.Certificate{
+				NotBefore: now.Add(-time.Hour),
+				NotAfter:  now.Add(-time.Minute),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			test.cert.Subject = pkix.Name{CommonName: "foo.localhost"}
+
+			checker := NewChecker(nil, func() []*x509.Certificate {
+				return []*x509.Certificate{test.cert}
+			}, "", time.Second)
+			checker.now = func() time.Time { return now }
+			checker.offset = test.offset
+
+			assert.Len(t, checker.CheckCertificates(context.Background()), test.expectedProblems)
+		})
+	}
+}
+
+func TestChecker_Check_ntp(t *testing.T) {
+	server := startNTPServer(t, time.Minute)
+
+	checker := NewChecker(nil, func() []*x509.Certificate { return nil }, server, 10*time.Second)
+
+	problems := checker.Check(context.Background())
+	require.Len(t, problems, 1)
+	assert.InDelta(t, time.Minute, checker.offset, float64(time.Second))
+
+	checker = NewChecker(nil, func() []*x509.Certificate { return nil }, server, 2*time.Minute)
+	assert.Empty(t, checker.Check(context.Background()))
+}
+
+// startNTPServer starts an NTP server whose clock is shifted by the given offset from the system clock.
+func startNTPServer(t *testing.T, offset time.Duration) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	go func() {
+		req := make([]byte, ntpPacketSize)
+		for {
+			_, addr, err := conn.ReadFrom(req)
+			if err != nil {
+				return
+			}
+
+			resp := make([]byte, ntpPacketSize)
+			// Leap indicator 0, version 4, server mode.
+			resp[0] = 0x24
+			// Stratum.
+			resp[1] = 1
+
+			ts := ntpTimestamp(time.Now().Add(offset))
+			copy(resp[32:40], ts)
+			copy(resp[40:48], ts)
+
+			_, _ = conn.WriteTo(resp, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func ntpTimestamp(t time.Time) []byte {
+	d := t.Sub(ntpEpoch)
+
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint32(b[:4], uint32(d/time.Second))
+	binary.BigEndian.PutUint32(b[4:], uint32((uint64(d%time.Second)<<32)/uint64(time.Second)))
+
+	return b
+}
@@ -0,0 +1,81 @@
+package clockskew
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"time"
+)
+
+const (
+	ntpPacketSize = 48
+	ntpTimeout    = 5 * time.Second
+)
+
+// ntpEpoch is the origin of the NTP timestamps.
+var ntpEpoch = time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// queryNTP returns the offset of the clock of the given NTP server from the system clock, with an SNTP (RFC 4330) request.
+func queryNTP(ctx context.Context, server string) (time.Duration, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "123")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ntpTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", server)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return 0, err
+		}
+	}
+
+	req := make([]byte, ntpPacketSize)
+	// Leap indicator 0, version 4, client mode.
+	req[0] = 0x23
+
+	sent := time.Now()
+	if _, err := conn.Write(req); err != nil {
+		return 0, err
+	}
+
+	resp := make([]byte, ntpPacketSize)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return 0, err
+	}
+	received := time.Now()
+
+	if n < ntpPacketSize {
+		return 0, errors.New("short NTP response")
+	}
+
+	if resp[0]&0x7 != 4 {
+		return 0, errors.New("invalid NTP response mode")
+	}
+
+	if resp[1] == 0 {
+		return 0, errors.New("NTP server sent a kiss-of-death response")
+	}
+
+	serverReceived := ntpTime(resp[32:40])
+	serverSent := ntpTime(resp[40:48])
+
+	return (serverReceived.Sub(sent) + serverSent.Sub(received)) / 2, nil
+}
+
+// ntpTime decodes an NTP timestamp.
+func ntpTime(b []byte) time.Time {
+	seconds := binary.BigEndian.Uint32(b[:4])
+	fraction := binary.BigEndian.Uint32(b[4:8])
+
+	return ntpEpoch.Add(time.Duration(seconds)*time.Second + time.Duration((uint64(fraction)*uint64(time.Second))>>32))
+}
@@ -1,6 +1,7 @@
 package dynamic
 
 import (
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/tls"
 )
 
@@ -35,3 +36,13 @@ type TLSConfiguration struct {
 	Options      map[string]tls.Options `json:"options,omitempty" toml:"options,omitempty" yaml:"options,omitempty" export:"true"`
 	Stores       map[string]tls.Store   `json:"stores,omitempty" toml:"stores,omitempty" yaml:"stores,omitempty" export:"true"`
 }
+
+// +k8s:deepcopy-gen=true
+
+// Schedule holds the recurring time windows during which a router or a middleware is enabled.
+// A window opens on every time matching the cron expression, and lasts for the duration.
+type Schedule struct {
+	Cron     string          `json:"cron,omitempty" toml:"cron,omitempty" yaml:"cron,omitempty" export:"true"`
+	Duration ptypes.Duration `json:"duration,omitempty" toml:"duration,omitempty" yaml:"duration,omitempty" export:"true"`
+	TimeZone string          `json:"timeZone,omitempty" toml:"timeZone,omitempty" yaml:"timeZone,omitempty" export:"true"`
+}
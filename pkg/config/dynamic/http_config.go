@@ -32,10 +32,12 @@ type Model struct {
 
 // Service holds a service configuration (can only be of one type at the same time).
 type Service struct {
-	LoadBalancer *ServersLoadBalancer `json:"loadBalancer,omitempty" toml:"loadBalancer,omitempty" yaml:"loadBalancer,omitempty" export:"true"`
-	Weighted     *WeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty" label:"-" export:"true"`
-	Mirroring    *Mirroring           `json:"mirroring,omitempty" toml:"mirroring,omitempty" yaml:"mirroring,omitempty" label:"-" export:"true"`
-	Failover     *Failover            `json:"failover,omitempty" toml:"failover,omitempty" yaml:"failover,omitempty" label:"-" export:"true"`
+	LoadBalancer    *ServersLoadBalancer `json:"loadBalancer,omitempty" toml:"loadBalancer,omitempty" yaml:"loadBalancer,omitempty" export:"true"`
+	Weighted        *WeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty" label:"-" export:"true"`
+	Mirroring       *Mirroring           `json:"mirroring,omitempty" toml:"mirroring,omitempty" yaml:"mirroring,omitempty" label:"-" export:"true"`
+	Failover        *Failover            `json:"failover,omitempty" toml:"failover,omitempty" yaml:"failover,omitempty" label:"-" export:"true"`
+	Subset          *Subset              `json:"subset,omitempty" toml:"subset,omitempty" yaml:"subset,omitempty" label:"-" export:"true"`
+	WebSocketBridge *WebSocketBridge     `json:"webSocketBridge,omitempty" toml:"webSocketBridge,omitempty" yaml:"webSocketBridge,omitempty" label:"-" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -48,7 +50,25 @@ type Router struct {
 	Rule        string           `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty"`
 	Priority    int              `json:"priority,omitempty" toml:"priority,omitempty,omitzero" yaml:"priority,omitempty" export:"true"`
 	TLS         *RouterTLSConfig `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	Tenant      string           `json:"tenant,omitempty" toml:"tenant,omitempty" yaml:"tenant,omitempty" export:"true"`
+	Schedule    *Schedule        `json:"schedule,omitempty" toml:"schedule,omitempty" yaml:"schedule,omitempty" export:"true"`
 	DefaultRule bool             `json:"-" toml:"-" yaml:"-" label:"-" file:"-"`
+
+	// ResponseTimeouts bounds the time taken by the service of the router to respond, on top of the timeouts of the servers transports.
+	ResponseTimeouts *ResponseTimeouts `json:"responseTimeouts,omitempty" toml:"responseTimeouts,omitempty" yaml:"responseTimeouts,omitempty" export:"true"`
+
+	// Tunnel tunnels the CONNECT requests handled by the router into a TCP service, in place of the service of the router.
+	Tunnel *Tunnel `json:"tunnel,omitempty" toml:"tunnel,omitempty" yaml:"tunnel,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Tunnel holds the configuration of the tunnels opened by the CONNECT requests of a router.
+type Tunnel struct {
+	// Service defines the name of the TCP service the tunnels are forwarded to.
+	Service string `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	// Middlewares defines the names of the TCP middlewares the tunnels go through.
+	Middlewares []string `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -87,6 +107,30 @@ type Failover struct {
 
 // +k8s:deepcopy-gen=true
 
+// Subset is a load-balancer of the servers of a load-balancer service matching the given labels.
+type Subset struct {
+	// Service defines the load-balancer service the servers are selected from.
+	Service string `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	// Labels defines the labels the selected servers must all have, with the same values.
+	Labels map[string]string `json:"labels,omitempty" toml:"labels,omitempty" yaml:"labels,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// WebSocketBridge bridges the WebSocket connections to a TCP service,
+// the messages received from the clients being forwarded to the service, and the bytes sent by the service as binary messages.
+type WebSocketBridge struct {
+	// Service defines the TCP service the connections are forwarded to.
+	Service string `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	// Middlewares defines the TCP middlewares the connections go through before reaching the service.
+	Middlewares []string `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
+	// AllowedOrigins defines the origins allowed to open a connection, "*" allowing any origin.
+	// By default, only the same origin as the host of the request is allowed.
+	AllowedOrigins []string `json:"allowedOrigins,omitempty" toml:"allowedOrigins,omitempty" yaml:"allowedOrigins,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // MirrorService holds the MirrorService configuration.
 type MirrorService struct {
 	Name    string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
@@ -105,6 +149,49 @@ type WeightedRoundRobin struct {
 	// load-balancing algorithm. In addition, if the parent of this service also has
 	// HealthCheck enabled, this service reports to its parent any status change.
 	HealthCheck *HealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// Canary enables the progressive delivery of one of the services.
+	Canary *Canary `json:"canary,omitempty" toml:"canary,omitempty" yaml:"canary,omitempty" export:"true"`
+	// Claim defines the JWT claim whose value picks the service of the requests carrying a token,
+	// in proportion to the weights, so that the requests with the same value go to the same service.
+	Claim string `json:"claim,omitempty" toml:"claim,omitempty" yaml:"claim,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Canary holds the progressive delivery configuration of a weighted service.
+// The weight of the canary service is increased stepwise, as long as its responses stay within the thresholds,
+// and is set back to zero as soon as they do not.
+type Canary struct {
+	// Service defines the name of the canary service, among the services of the weighted service.
+	// Its configured weight is ignored.
+	Service string `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	// StepWeight defines the weight of the canary service at the first step, and the weight added at each next step.
+	// Default: 10.
+	StepWeight int `json:"stepWeight,omitempty" toml:"stepWeight,omitempty" yaml:"stepWeight,omitempty" export:"true"`
+	// MaxWeight defines the weight of the canary service once promoted.
+	// Default: 100.
+	MaxWeight int `json:"maxWeight,omitempty" toml:"maxWeight,omitempty" yaml:"maxWeight,omitempty" export:"true"`
+	// Interval defines the duration of a step.
+	// Default: 1m.
+	Interval ptypes.Duration `json:"interval,omitempty" toml:"interval,omitempty" yaml:"interval,omitempty" export:"true"`
+	// MinRequests defines the number of requests the canary service must handle during a step for the step to be analyzed.
+	// A step with fewer requests is extended by another interval.
+	// Default: 10.
+	MinRequests int `json:"minRequests,omitempty" toml:"minRequests,omitempty" yaml:"minRequests,omitempty" export:"true"`
+	// MaxErrorRatio defines the ratio of 5XX responses of the canary service during a step above which it is rolled back.
+	// Default: 0 (not checked).
+	MaxErrorRatio float64 `json:"maxErrorRatio,omitempty" toml:"maxErrorRatio,omitempty" yaml:"maxErrorRatio,omitempty" export:"true"`
+	// MaxLatency defines the average latency of the canary service during a step above which it is rolled back.
+	// Default: 0 (not checked).
+	MaxLatency ptypes.Duration `json:"maxLatency,omitempty" toml:"maxLatency,omitempty" yaml:"maxLatency,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a Canary.
+func (c *Canary) SetDefaults() {
+	c.StepWeight = 10
+	c.MaxWeight = 100
+	c.Interval = ptypes.Duration(time.Minute)
+	c.MinRequests = 10
 }
 
 // +k8s:deepcopy-gen=true
@@ -123,6 +210,62 @@ func (w *WRRService) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// SlowStart holds the slow-start configuration.
+type SlowStart struct {
+	// Window defines the time during which the weight of a newly added, or newly healthy, server
+	// is ramped up to its full value.
+	// Default: 30s.
+	Window ptypes.Duration `json:"window,omitempty" toml:"window,omitempty" yaml:"window,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ServerIdentity holds the configuration of the identity of the servers injected in the responses.
+type ServerIdentity struct {
+	// HeaderName defines the name of the response header carrying the identity of the server.
+	// Default: X-Served-By.
+	HeaderName string `json:"headerName,omitempty" toml:"headerName,omitempty" yaml:"headerName,omitempty" export:"true"`
+	// Hash defines whether the identity is a hash of the server URL, rather than the URL itself,
+	// not to disclose the internal addresses to the clients.
+	Hash bool `json:"hash,omitempty" toml:"hash,omitempty" yaml:"hash,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ConsistentHash holds the consistent hashing configuration.
+// Exactly one of Header, Cookie, and PathSegment defines the hash key.
+type ConsistentHash struct {
+	// Algorithm defines the consistent hashing algorithm: ring (ring hash) or maglev.
+	// Default: ring.
+	Algorithm string `json:"algorithm,omitempty" toml:"algorithm,omitempty" yaml:"algorithm,omitempty" export:"true"`
+	// Header defines the name of the request header holding the hash key.
+	Header string `json:"header,omitempty" toml:"header,omitempty" yaml:"header,omitempty" export:"true"`
+	// Cookie defines the name of the cookie holding the hash key.
+	Cookie string `json:"cookie,omitempty" toml:"cookie,omitempty" yaml:"cookie,omitempty" export:"true"`
+	// PathSegment defines the position, starting at 1, of the request path segment being the hash key.
+	PathSegment int `json:"pathSegment,omitempty" toml:"pathSegment,omitempty" yaml:"pathSegment,omitempty" export:"true"`
+	// LoadFactor bounds the in-flight requests of a server to LoadFactor times the average,
+	// the requests in excess being sent to the next servers for their key.
+	// It must be greater than 1. Default: 0 (unbounded).
+	LoadFactor float64 `json:"loadFactor,omitempty" toml:"loadFactor,omitempty" yaml:"loadFactor,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ZoneAware holds the zone-aware load-balancing configuration.
+type ZoneAware struct {
+	// MinServers defines the number of healthy servers in the local zone
+	// below which the requests are sent to the other zones.
+	// Default: 1.
+	MinServers int `json:"minServers,omitempty" toml:"minServers,omitempty" yaml:"minServers,omitempty" export:"true"`
+	// MaxInFlight defines the average number of in-flight requests per server of the local zone
+	// above which the requests spill over to the other zones.
+	// Default: 0 (no limit).
+	MaxInFlight int64 `json:"maxInFlight,omitempty" toml:"maxInFlight,omitempty" yaml:"maxInFlight,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Sticky holds the sticky configuration.
 type Sticky struct {
 	// Cookie defines the sticky cookie configuration.
@@ -158,6 +301,22 @@ type ServersLoadBalancer struct {
 	PassHostHeader     *bool               `json:"passHostHeader" toml:"passHostHeader" yaml:"passHostHeader" export:"true"`
 	ResponseForwarding *ResponseForwarding `json:"responseForwarding,omitempty" toml:"responseForwarding,omitempty" yaml:"responseForwarding,omitempty" export:"true"`
 	ServersTransport   string              `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
+	// SlowStart ramps up the share of the traffic sent to a newly added, or newly healthy, server.
+	SlowStart *SlowStart `json:"slowStart,omitempty" toml:"slowStart,omitempty" yaml:"slowStart,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// Strategy defines how the servers are picked: wrr (weighted round robin), leastconn (least in-flight requests),
+	// or peakewma (lowest peak EWMA of the latency, multiplied by the in-flight requests).
+	// Default: wrr.
+	Strategy string `json:"strategy,omitempty" toml:"strategy,omitempty" yaml:"strategy,omitempty" export:"true"`
+	// ZoneAware sends the requests to the servers of the zone of the Traefik instance,
+	// and spills them over to the other zones when the local servers are too few or too loaded.
+	ZoneAware *ZoneAware `json:"zoneAware,omitempty" toml:"zoneAware,omitempty" yaml:"zoneAware,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// ConsistentHash picks the server of a request by hashing one of its attributes,
+	// so that a given key is always sent to the same server, as long as it is available.
+	ConsistentHash *ConsistentHash `json:"consistentHash,omitempty" toml:"consistentHash,omitempty" yaml:"consistentHash,omitempty" export:"true"`
+	// ServerIdentity injects the identity of the server which handled a request in the response headers and the access logs.
+	ServerIdentity *ServerIdentity `json:"serverIdentity,omitempty" toml:"serverIdentity,omitempty" yaml:"serverIdentity,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// ResponseTimeouts bounds the time taken by each server to respond, on top of the timeouts of the servers transport.
+	ResponseTimeouts *ResponseTimeouts `json:"responseTimeouts,omitempty" toml:"responseTimeouts,omitempty" yaml:"responseTimeouts,omitempty" export:"true"`
 }
 
 // Mergeable tells if the given service is mergeable.
@@ -197,11 +356,33 @@ type ResponseForwarding struct {
 
 // +k8s:deepcopy-gen=true
 
+// ResponseTimeouts holds the timeouts of the responses of a router or a service.
+// The requests timing out before the response headers are sent get a 504 Gateway Timeout response,
+// and the responses timing out afterwards are aborted.
+type ResponseTimeouts struct {
+	// HeaderTimeout defines how long to wait for the headers of the response, once the request is forwarded.
+	// Zero means no timeout.
+	HeaderTimeout ptypes.Duration `json:"headerTimeout,omitempty" toml:"headerTimeout,omitempty" yaml:"headerTimeout,omitempty" export:"true"`
+	// Timeout defines how long to wait for the complete response, including its body.
+	// Zero means no timeout.
+	Timeout ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Server holds the server configuration.
 type Server struct {
 	URL    string `json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty" label:"-"`
 	Scheme string `json:"-" toml:"-" yaml:"-" file:"-"`
 	Port   string `json:"-" toml:"-" yaml:"-" file:"-"`
+	// Zone is the locality of the server, used by the zone-aware load balancing.
+	Zone string `json:"zone,omitempty" toml:"zone,omitempty" yaml:"zone,omitempty"`
+	// Labels are the labels of the server, used to select the servers of the subset services.
+	Labels map[string]string `json:"labels,omitempty" toml:"labels,omitempty" yaml:"labels,omitempty"`
+	// ServerName is the server name (SNI) used to contact the server, overriding the one of the servers transport.
+	ServerName string `json:"serverName,omitempty" toml:"serverName,omitempty" yaml:"serverName,omitempty"`
+	// Host is the Host header of the requests forwarded to the server, overriding the passHostHeader option.
+	Host string `json:"host,omitempty" toml:"host,omitempty" yaml:"host,omitempty"`
 }
 
 // SetDefaults Default values for a Server.
@@ -249,6 +430,21 @@ type ServersTransport struct {
 	ForwardingTimeouts  *ForwardingTimeouts        `description:"Timeouts for requests forwarded to the backend servers." json:"forwardingTimeouts,omitempty" toml:"forwardingTimeouts,omitempty" yaml:"forwardingTimeouts,omitempty" export:"true"`
 	DisableHTTP2        bool                       `description:"Disable HTTP/2 for connections with backend servers." json:"disableHTTP2,omitempty" toml:"disableHTTP2,omitempty" yaml:"disableHTTP2,omitempty" export:"true"`
 	PeerCertURI         string                     `description:"URI used to match against SAN URI during the peer certificate verification." json:"peerCertURI,omitempty" toml:"peerCertURI,omitempty" yaml:"peerCertURI,omitempty" export:"true"`
+	ConnectionMarking   *ConnectionMarking         `description:"Marks set on the connections to the backend servers." json:"connectionMarking,omitempty" toml:"connectionMarking,omitempty" yaml:"connectionMarking,omitempty" export:"true"`
+	MultipathTCP        bool                       `description:"Opens the connections to the backend servers with Multipath TCP (MPTCP), when supported." json:"multipathTCP,omitempty" toml:"multipathTCP,omitempty" yaml:"multipathTCP,omitempty" export:"true"`
+	CongestionControl   string                     `description:"TCP congestion control algorithm of the connections to the backend servers, such as bbr (Linux only)." json:"congestionControl,omitempty" toml:"congestionControl,omitempty" yaml:"congestionControl,omitempty" export:"true"`
+	DetectH2C           bool                       `description:"Detects whether the backend servers with a plain HTTP URL support h2c, and uses it for them instead of HTTP/1.1." json:"detectH2C,omitempty" toml:"detectH2C,omitempty" yaml:"detectH2C,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ConnectionMarking holds the marks set on the connections to the backend servers,
+// for the policy routing and the QoS of the network to tell their traffic apart.
+// It is only supported on Linux.
+type ConnectionMarking struct {
+	Mark      uint32 `description:"Firewall mark (SO_MARK) set on the connections to the backend servers." json:"mark,omitempty" toml:"mark,omitempty" yaml:"mark,omitempty" export:"true"`
+	DSCP      int    `description:"DSCP, from 0 to 63, set in the TOS field of the IPv4 packets, or the traffic class of the IPv6 packets, sent to the backend servers." json:"dscp,omitempty" toml:"dscp,omitempty" yaml:"dscp,omitempty" export:"true"`
+	FlowLabel uint32 `description:"Flow label, from 1 to 1048575, set on the IPv6 packets sent to the backend servers." json:"flowLabel,omitempty" toml:"flowLabel,omitempty" yaml:"flowLabel,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -12,30 +12,41 @@ import (
 
 // Middleware holds the Middleware configuration.
 type Middleware struct {
-	AddPrefix         *AddPrefix         `json:"addPrefix,omitempty" toml:"addPrefix,omitempty" yaml:"addPrefix,omitempty" export:"true"`
-	StripPrefix       *StripPrefix       `json:"stripPrefix,omitempty" toml:"stripPrefix,omitempty" yaml:"stripPrefix,omitempty" export:"true"`
-	StripPrefixRegex  *StripPrefixRegex  `json:"stripPrefixRegex,omitempty" toml:"stripPrefixRegex,omitempty" yaml:"stripPrefixRegex,omitempty" export:"true"`
-	ReplacePath       *ReplacePath       `json:"replacePath,omitempty" toml:"replacePath,omitempty" yaml:"replacePath,omitempty" export:"true"`
-	ReplacePathRegex  *ReplacePathRegex  `json:"replacePathRegex,omitempty" toml:"replacePathRegex,omitempty" yaml:"replacePathRegex,omitempty" export:"true"`
-	Chain             *Chain             `json:"chain,omitempty" toml:"chain,omitempty" yaml:"chain,omitempty" export:"true"`
-	IPWhiteList       *IPWhiteList       `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty" export:"true"`
-	Headers           *Headers           `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
-	Errors            *ErrorPage         `json:"errors,omitempty" toml:"errors,omitempty" yaml:"errors,omitempty" export:"true"`
-	RateLimit         *RateLimit         `json:"rateLimit,omitempty" toml:"rateLimit,omitempty" yaml:"rateLimit,omitempty" export:"true"`
-	RedirectRegex     *RedirectRegex     `json:"redirectRegex,omitempty" toml:"redirectRegex,omitempty" yaml:"redirectRegex,omitempty" export:"true"`
-	RedirectScheme    *RedirectScheme    `json:"redirectScheme,omitempty" toml:"redirectScheme,omitempty" yaml:"redirectScheme,omitempty" export:"true"`
-	BasicAuth         *BasicAuth         `json:"basicAuth,omitempty" toml:"basicAuth,omitempty" yaml:"basicAuth,omitempty" export:"true"`
-	DigestAuth        *DigestAuth        `json:"digestAuth,omitempty" toml:"digestAuth,omitempty" yaml:"digestAuth,omitempty" export:"true"`
-	ForwardAuth       *ForwardAuth       `json:"forwardAuth,omitempty" toml:"forwardAuth,omitempty" yaml:"forwardAuth,omitempty" export:"true"`
-	InFlightReq       *InFlightReq       `json:"inFlightReq,omitempty" toml:"inFlightReq,omitempty" yaml:"inFlightReq,omitempty" export:"true"`
-	Buffering         *Buffering         `json:"buffering,omitempty" toml:"buffering,omitempty" yaml:"buffering,omitempty" export:"true"`
-	CircuitBreaker    *CircuitBreaker    `json:"circuitBreaker,omitempty" toml:"circuitBreaker,omitempty" yaml:"circuitBreaker,omitempty" export:"true"`
-	Compress          *Compress          `json:"compress,omitempty" toml:"compress,omitempty" yaml:"compress,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
-	PassTLSClientCert *PassTLSClientCert `json:"passTLSClientCert,omitempty" toml:"passTLSClientCert,omitempty" yaml:"passTLSClientCert,omitempty" export:"true"`
-	Retry             *Retry             `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty" export:"true"`
-	ContentType       *ContentType       `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty" export:"true"`
+	AddPrefix           *AddPrefix           `json:"addPrefix,omitempty" toml:"addPrefix,omitempty" yaml:"addPrefix,omitempty" export:"true"`
+	StripPrefix         *StripPrefix         `json:"stripPrefix,omitempty" toml:"stripPrefix,omitempty" yaml:"stripPrefix,omitempty" export:"true"`
+	StripPrefixRegex    *StripPrefixRegex    `json:"stripPrefixRegex,omitempty" toml:"stripPrefixRegex,omitempty" yaml:"stripPrefixRegex,omitempty" export:"true"`
+	ReplacePath         *ReplacePath         `json:"replacePath,omitempty" toml:"replacePath,omitempty" yaml:"replacePath,omitempty" export:"true"`
+	ReplacePathRegex    *ReplacePathRegex    `json:"replacePathRegex,omitempty" toml:"replacePathRegex,omitempty" yaml:"replacePathRegex,omitempty" export:"true"`
+	Chain               *Chain               `json:"chain,omitempty" toml:"chain,omitempty" yaml:"chain,omitempty" export:"true"`
+	IPWhiteList         *IPWhiteList         `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty" export:"true"`
+	Headers             *Headers             `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+	Errors              *ErrorPage           `json:"errors,omitempty" toml:"errors,omitempty" yaml:"errors,omitempty" export:"true"`
+	RateLimit           *RateLimit           `json:"rateLimit,omitempty" toml:"rateLimit,omitempty" yaml:"rateLimit,omitempty" export:"true"`
+	RedirectRegex       *RedirectRegex       `json:"redirectRegex,omitempty" toml:"redirectRegex,omitempty" yaml:"redirectRegex,omitempty" export:"true"`
+	RedirectScheme      *RedirectScheme      `json:"redirectScheme,omitempty" toml:"redirectScheme,omitempty" yaml:"redirectScheme,omitempty" export:"true"`
+	BasicAuth           *BasicAuth           `json:"basicAuth,omitempty" toml:"basicAuth,omitempty" yaml:"basicAuth,omitempty" export:"true"`
+	DigestAuth          *DigestAuth          `json:"digestAuth,omitempty" toml:"digestAuth,omitempty" yaml:"digestAuth,omitempty" export:"true"`
+	ForwardAuth         *ForwardAuth         `json:"forwardAuth,omitempty" toml:"forwardAuth,omitempty" yaml:"forwardAuth,omitempty" export:"true"`
+	InFlightReq         *InFlightReq         `json:"inFlightReq,omitempty" toml:"inFlightReq,omitempty" yaml:"inFlightReq,omitempty" export:"true"`
+	Buffering           *Buffering           `json:"buffering,omitempty" toml:"buffering,omitempty" yaml:"buffering,omitempty" export:"true"`
+	CircuitBreaker      *CircuitBreaker      `json:"circuitBreaker,omitempty" toml:"circuitBreaker,omitempty" yaml:"circuitBreaker,omitempty" export:"true"`
+	Compress            *Compress            `json:"compress,omitempty" toml:"compress,omitempty" yaml:"compress,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	PassTLSClientCert   *PassTLSClientCert   `json:"passTLSClientCert,omitempty" toml:"passTLSClientCert,omitempty" yaml:"passTLSClientCert,omitempty" export:"true"`
+	Retry               *Retry               `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty" export:"true"`
+	ContentType         *ContentType         `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty" export:"true"`
+	Accounting          *Accounting          `json:"accounting,omitempty" toml:"accounting,omitempty" yaml:"accounting,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	Deadline            *Deadline            `json:"deadline,omitempty" toml:"deadline,omitempty" yaml:"deadline,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	AdaptiveConcurrency *AdaptiveConcurrency `json:"adaptiveConcurrency,omitempty" toml:"adaptiveConcurrency,omitempty" yaml:"adaptiveConcurrency,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	PriorityShedding    *PriorityShedding    `json:"priorityShedding,omitempty" toml:"priorityShedding,omitempty" yaml:"priorityShedding,omitempty" export:"true"`
+	Hedging             *Hedging             `json:"hedging,omitempty" toml:"hedging,omitempty" yaml:"hedging,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	JWTClaims           *JWTClaims           `json:"jwtClaims,omitempty" toml:"jwtClaims,omitempty" yaml:"jwtClaims,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	EarlyHints          *EarlyHints          `json:"earlyHints,omitempty" toml:"earlyHints,omitempty" yaml:"earlyHints,omitempty" export:"true"`
+	Idempotency         *Idempotency         `json:"idempotency,omitempty" toml:"idempotency,omitempty" yaml:"idempotency,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 
 	Plugin map[string]PluginConf `json:"plugin,omitempty" toml:"plugin,omitempty" yaml:"plugin,omitempty" export:"true"`
+
+	// Schedule is not a middleware type, it restricts the middleware to its time windows.
+	Schedule *Schedule `json:"schedule,omitempty" toml:"schedule,omitempty" yaml:"schedule,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -53,6 +64,192 @@ type ContentType struct {
 
 // +k8s:deepcopy-gen=true
 
+// Accounting holds the accounting middleware configuration.
+// This middleware reports, for a sampled fraction of requests, the bytes read and written,
+// the compression savings, and the time spent in each of the following middlewares of the chain.
+type Accounting struct {
+	// SampleRate defines the fraction of requests, between 0 and 1, to report on.
+	// Default: 1 (all requests).
+	SampleRate float64 `json:"sampleRate,omitempty" toml:"sampleRate,omitempty" yaml:"sampleRate,omitempty" export:"true"`
+	// Log defines whether to write the report in the logs instead of in the response trailers.
+	Log bool `json:"log,omitempty" toml:"log,omitempty" yaml:"log,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Deadline holds the deadline middleware configuration.
+// This middleware enforces an end-to-end latency budget on requests,
+// propagates the remaining budget to the backend, and aborts the requests exceeding it.
+type Deadline struct {
+	// Timeout defines the budget applied to the requests not carrying one.
+	// Zero means that only the requests carrying a budget are enforced.
+	Timeout ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+	// MaxTimeout defines the maximum budget a request can ask for.
+	// Zero means no maximum.
+	MaxTimeout ptypes.Duration `json:"maxTimeout,omitempty" toml:"maxTimeout,omitempty" yaml:"maxTimeout,omitempty" export:"true"`
+	// Header defines the name of the header carrying the budget, in milliseconds,
+	// read from the requests and propagated to the backend.
+	// Default: X-Deadline.
+	Header string `json:"header,omitempty" toml:"header,omitempty" yaml:"header,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Hedging holds the hedging middleware configuration.
+// This middleware sends a duplicate of the idempotent requests waiting too long for a response,
+// which the load balancer forwards to another server, and uses the first response received.
+type Hedging struct {
+	// Delay defines the time to wait for a response before sending a hedged request.
+	// Default: 100ms.
+	Delay ptypes.Duration `json:"delay,omitempty" toml:"delay,omitempty" yaml:"delay,omitempty" export:"true"`
+	// MaxHedges defines the maximum number of hedged requests sent for a request.
+	// Default: 1.
+	MaxHedges int `json:"maxHedges,omitempty" toml:"maxHedges,omitempty" yaml:"maxHedges,omitempty" export:"true"`
+	// Methods defines the methods of the requests to hedge, which must be idempotent.
+	// Default: GET, HEAD, OPTIONS.
+	Methods []string `json:"methods,omitempty" toml:"methods,omitempty" yaml:"methods,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// JWTClaims holds the JWT claims middleware configuration.
+// This middleware extracts the claims of the JSON Web Token of the requests, without verifying its signature,
+// for the weighted services and the middlewares that follow, and forwards some of them in request headers.
+type JWTClaims struct {
+	// HeaderName defines the name of the header carrying the token, with or without the Bearer authentication scheme.
+	// Default: Authorization.
+	HeaderName string `json:"headerName,omitempty" toml:"headerName,omitempty" yaml:"headerName,omitempty" export:"true"`
+	// ForwardHeaders defines the claims forwarded to the services, keyed by the name of the request header carrying them.
+	ForwardHeaders map[string]string `json:"forwardHeaders,omitempty" toml:"forwardHeaders,omitempty" yaml:"forwardHeaders,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// EarlyHints holds the early hints middleware configuration.
+// This middleware sends a 103 Early Hints response with the configured links before forwarding the GET requests,
+// so that the clients start loading the linked resources while the backend builds the response.
+type EarlyHints struct {
+	// Links defines the values of the Link headers sent in the 103 Early Hints response,
+	// such as `</style.css>; rel=preload; as=style`.
+	Links []string `json:"links,omitempty" toml:"links,omitempty" yaml:"links,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Idempotency holds the idempotency middleware configuration.
+// This middleware guards the at-most-once endpoints against the duplicates of the requests carrying an idempotency key,
+// such as the ones sent again by the clients or by the retry middleware,
+// by replaying the response of the first request, or by rejecting them with a 409 Conflict while it is in progress.
+// The idempotency keys are scoped by client, method, and path,
+// and the reuse of a key for a different request is rejected with a 422 Unprocessable Entity.
+type Idempotency struct {
+	// HeaderName defines the name of the header carrying the idempotency key of the requests.
+	// Default: Idempotency-Key.
+	HeaderName string `json:"headerName,omitempty" toml:"headerName,omitempty" yaml:"headerName,omitempty" export:"true"`
+	// SourceCriterion defines what criterion is used to identify the clients, whose idempotency keys are kept apart.
+	// If several strategies are defined at the same time, an error will be raised.
+	// If none are set, the default is to use the request's remote address field (as an ipStrategy).
+	SourceCriterion *SourceCriterion `json:"sourceCriterion,omitempty" toml:"sourceCriterion,omitempty" yaml:"sourceCriterion,omitempty" export:"true"`
+	// Window defines the time, from the first request, during which its duplicates are guarded against.
+	// Default: 24h.
+	Window ptypes.Duration `json:"window,omitempty" toml:"window,omitempty" yaml:"window,omitempty" export:"true"`
+	// MaxKeys defines the maximum number of idempotency keys remembered in memory, the oldest ones being forgotten first.
+	// Default: 10000.
+	MaxKeys int `json:"maxKeys,omitempty" toml:"maxKeys,omitempty" yaml:"maxKeys,omitempty" export:"true"`
+	// MaxResponseBodyBytes defines the maximum size of the response bodies remembered to be replayed.
+	// The duplicates of the requests with a larger response are rejected with a 409 Conflict.
+	// Default: 1048576.
+	MaxResponseBodyBytes int64 `json:"maxResponseBodyBytes,omitempty" toml:"maxResponseBodyBytes,omitempty" yaml:"maxResponseBodyBytes,omitempty" export:"true"`
+	// MaxRequestBodyBytes defines the maximum size of the request bodies read to tell the requests reusing a key apart.
+	// The requests carrying an idempotency key with a larger body are rejected with a 413 Request Entity Too Large.
+	// Default: 1048576.
+	MaxRequestBodyBytes int64 `json:"maxRequestBodyBytes,omitempty" toml:"maxRequestBodyBytes,omitempty" yaml:"maxRequestBodyBytes,omitempty" export:"true"`
+	// Redis defines the Redis server remembering the idempotency keys instead of the memory,
+	// so that they are shared by the Traefik instances, and kept across the configuration reloads.
+	Redis *IdempotencyRedis `json:"redis,omitempty" toml:"redis,omitempty" yaml:"redis,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// IdempotencyRedis holds the Redis server of the idempotency middleware.
+type IdempotencyRedis struct {
+	// Address defines the address of the Redis server, as host:port.
+	Address string `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	// Username defines the username used to authenticate to the Redis server.
+	Username string `json:"username,omitempty" toml:"username,omitempty" yaml:"username,omitempty" loggable:"false"`
+	// Password defines the password used to authenticate to the Redis server.
+	Password string `json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty" loggable:"false"`
+	// DB defines the Redis database storing the idempotency keys.
+	DB int `json:"db,omitempty" toml:"db,omitempty" yaml:"db,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// AdaptiveConcurrency holds the adaptive concurrency middleware configuration.
+// This middleware limits the number of simultaneous in-flight requests,
+// adjusting the limit to the latency observed on the responses, and sheds the excess requests.
+type AdaptiveConcurrency struct {
+	// Algorithm defines the algorithm adjusting the limit, either aimd or gradient.
+	// Default: aimd.
+	Algorithm string `json:"algorithm,omitempty" toml:"algorithm,omitempty" yaml:"algorithm,omitempty" export:"true"`
+	// InitialLimit defines the limit applied before any latency is observed.
+	// Default: 10.
+	InitialLimit int64 `json:"initialLimit,omitempty" toml:"initialLimit,omitempty" yaml:"initialLimit,omitempty" export:"true"`
+	// MinLimit defines the minimum limit.
+	// Default: 1.
+	MinLimit int64 `json:"minLimit,omitempty" toml:"minLimit,omitempty" yaml:"minLimit,omitempty" export:"true"`
+	// MaxLimit defines the maximum limit.
+	// Default: 1000.
+	MaxLimit int64 `json:"maxLimit,omitempty" toml:"maxLimit,omitempty" yaml:"maxLimit,omitempty" export:"true"`
+	// LatencyThreshold defines, for the aimd algorithm, the latency above which the limit is decreased.
+	// Default: 1s.
+	LatencyThreshold ptypes.Duration `json:"latencyThreshold,omitempty" toml:"latencyThreshold,omitempty" yaml:"latencyThreshold,omitempty" export:"true"`
+	// BackoffRatio defines, for the aimd algorithm, the factor applied to the limit when it is decreased.
+	// Default: 0.9.
+	BackoffRatio float64 `json:"backoffRatio,omitempty" toml:"backoffRatio,omitempty" yaml:"backoffRatio,omitempty" export:"true"`
+	// Tolerance defines, for the gradient algorithm, how much the latency can grow over the long-term latency
+	// before the limit is decreased.
+	// Default: 1.5.
+	Tolerance float64 `json:"tolerance,omitempty" toml:"tolerance,omitempty" yaml:"tolerance,omitempty" export:"true"`
+	// RetryAfter defines the delay sent in the Retry-After header of the shed requests.
+	// Default: 1s.
+	RetryAfter ptypes.Duration `json:"retryAfter,omitempty" toml:"retryAfter,omitempty" yaml:"retryAfter,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// PriorityShedding holds the priority shedding middleware configuration.
+// This middleware classifies the requests into priority tiers,
+// and sheds the lower tiers first when the service is overloaded.
+type PriorityShedding struct {
+	// Tiers defines the priority tiers, by name.
+	// The requests not matching any tier have the lowest priority.
+	Tiers map[string]*PriorityTier `json:"tiers,omitempty" toml:"tiers,omitempty" yaml:"tiers,omitempty" export:"true"`
+	// MaxConcurrency defines the number of simultaneous in-flight requests above which the requests are queued,
+	// and served by decreasing priority.
+	// Zero means no limit.
+	MaxConcurrency int64 `json:"maxConcurrency,omitempty" toml:"maxConcurrency,omitempty" yaml:"maxConcurrency,omitempty" export:"true"`
+	// MaxQueueDelay defines the maximum time a request waits in the queue before being shed.
+	// Default: 1s.
+	MaxQueueDelay ptypes.Duration `json:"maxQueueDelay,omitempty" toml:"maxQueueDelay,omitempty" yaml:"maxQueueDelay,omitempty" export:"true"`
+	// MaxLatency defines the average latency of the responses above which the lower tiers are shed, one at a time.
+	// Zero means that the latency is not watched.
+	MaxLatency ptypes.Duration `json:"maxLatency,omitempty" toml:"maxLatency,omitempty" yaml:"maxLatency,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// PriorityTier holds a priority tier configuration.
+type PriorityTier struct {
+	// Rule defines the rule matching the requests of the tier, with the same syntax as the router rules.
+	Rule string `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty"`
+	// Priority defines the priority of the tier, the higher being the most important.
+	// It must be strictly positive, as the requests not matching any tier have a zero priority.
+	Priority int `json:"priority,omitempty" toml:"priority,omitempty" yaml:"priority,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // AddPrefix holds the add prefix middleware configuration.
 // This middleware updates the path of a request before forwarding it.
 // More info: https://doc.traefik.io/traefik/v2.10/middlewares/http/addprefix/
@@ -355,6 +552,9 @@ type IPStrategy struct {
 	Depth int `json:"depth,omitempty" toml:"depth,omitempty" yaml:"depth,omitempty" export:"true"`
 	// ExcludedIPs configures Traefik to scan the X-Forwarded-For header and select the first IP not in the list.
 	ExcludedIPs []string `json:"excludedIPs,omitempty" toml:"excludedIPs,omitempty" yaml:"excludedIPs,omitempty"`
+	// IPv6Subnet is the prefix length of the IPv6 subnets whose addresses are considered as the same client IP (e.g. 64).
+	// It defaults to 0, which means that each IPv6 address is a distinct client IP.
+	IPv6Subnet int `json:"ipv6Subnet,omitempty" toml:"ipv6Subnet,omitempty" yaml:"ipv6Subnet,omitempty" export:"true"`
 	// TODO(mpl): I think we should make RemoteAddr an explicit field. For one thing, it would yield better documentation.
 }
 
@@ -367,9 +567,14 @@ func (s *IPStrategy) Get() (ip.Strategy, error) {
 		return &ip.RemoteAddrStrategy{}, nil
 	}
 
+	if err := ip.ValidateIPv6Subnet(s.IPv6Subnet); err != nil {
+		return nil, err
+	}
+
 	if s.Depth > 0 {
 		return &ip.DepthStrategy{
-			Depth: s.Depth,
+			Depth:      s.Depth,
+			IPv6Subnet: s.IPv6Subnet,
 		}, nil
 	}
 
@@ -379,11 +584,12 @@ func (s *IPStrategy) Get() (ip.Strategy, error) {
 			return nil, err
 		}
 		return &ip.PoolStrategy{
-			Checker: checker,
+			Checker:    checker,
+			IPv6Subnet: s.IPv6Subnet,
 		}, nil
 	}
 
-	return &ip.RemoteAddrStrategy{}, nil
+	return &ip.RemoteAddrStrategy{IPv6Subnet: s.IPv6Subnet}, nil
 }
 
 // +k8s:deepcopy-gen=true
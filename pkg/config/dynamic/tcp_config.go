@@ -2,7 +2,9 @@ package dynamic
 
 import (
 	"reflect"
+	"time"
 
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/types"
 )
 
@@ -54,6 +56,10 @@ type TCPRouter struct {
 	Rule        string              `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty"`
 	Priority    int                 `json:"priority,omitempty" toml:"priority,omitempty,omitzero" yaml:"priority,omitempty" export:"true"`
 	TLS         *RouterTCPTLSConfig `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	Tenant      string              `json:"tenant,omitempty" toml:"tenant,omitempty" yaml:"tenant,omitempty" export:"true"`
+	DNS         *DNSProtocol        `json:"dns,omitempty" toml:"dns,omitempty" yaml:"dns,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	Schedule    *Schedule           `json:"schedule,omitempty" toml:"schedule,omitempty" yaml:"schedule,omitempty" export:"true"`
+	SSH         *SSHProtocol        `json:"ssh,omitempty" toml:"ssh,omitempty" yaml:"ssh,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -68,6 +74,53 @@ type RouterTCPTLSConfig struct {
 
 // +k8s:deepcopy-gen=true
 
+// DNSProtocol holds the configuration of the DNS protocol-aware mode of a TCP or UDP router,
+// which parses the DNS queries going through the router.
+type DNSProtocol struct {
+	// LogQueries enables the logging of the name and type of the queries.
+	LogQueries bool          `json:"logQueries,omitempty" toml:"logQueries,omitempty" yaml:"logQueries,omitempty" export:"true"`
+	RateLimit  *DNSRateLimit `json:"rateLimit,omitempty" toml:"rateLimit,omitempty" yaml:"rateLimit,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// DNSRateLimit holds the configuration of the rate limiting of the DNS queries, by query name.
+type DNSRateLimit struct {
+	// Average is the maximum rate, by default in queries/s, allowed for a query name.
+	// It defaults to 0, which means no rate limiting.
+	Average int64 `json:"average,omitempty" toml:"average,omitempty" yaml:"average,omitempty" export:"true"`
+
+	// Period, in combination with Average, defines the actual maximum rate, such as:
+	// r = Average / Period. It defaults to a second.
+	Period ptypes.Duration `json:"period,omitempty" toml:"period,omitempty" yaml:"period,omitempty" export:"true"`
+
+	// Burst is the maximum number of queries for a query name allowed to arrive in the same arbitrarily small period of time.
+	// It defaults to 1.
+	Burst int64 `json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a DNSRateLimit.
+func (r *DNSRateLimit) SetDefaults() {
+	r.Burst = 1
+	r.Period = ptypes.Duration(time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
+// SSHProtocol holds the configuration of the SSH protocol-aware mode of a TCP router,
+// which parses the identification string and the key exchange initialization sent by the SSH clients.
+type SSHProtocol struct {
+	// LogClients enables the logging of the software and the key exchange algorithms of the clients.
+	LogClients bool `json:"logClients,omitempty" toml:"logClients,omitempty" yaml:"logClients,omitempty" export:"true"`
+	// ProtocolVersions defines the protocol versions the clients are allowed to use.
+	// It defaults to 2.0, and to 1.99, which is used by the clients also supporting the first version of the protocol.
+	ProtocolVersions []string `json:"protocolVersions,omitempty" toml:"protocolVersions,omitempty" yaml:"protocolVersions,omitempty" export:"true"`
+	// DeniedSoftware defines the regular expressions matching the software versions of the clients denied.
+	DeniedSoftware []string `json:"deniedSoftware,omitempty" toml:"deniedSoftware,omitempty" yaml:"deniedSoftware,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // TCPServersLoadBalancer holds the LoadBalancerService configuration.
 type TCPServersLoadBalancer struct {
 	// TerminationDelay, corresponds to the deadline that the proxy sets, after one
@@ -79,6 +132,21 @@ type TCPServersLoadBalancer struct {
 	ProxyProtocol    *ProxyProtocol `json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 	Servers          []TCPServer    `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
 	SourceIPs        []string       `json:"sourceIPs,omitempty" toml:"sourceIPs,omitempty" yaml:"sourceIPs,omitempty" export:"true"`
+	// SlowStart ramps up the share of the connections sent to a newly added server.
+	SlowStart *SlowStart `json:"slowStart,omitempty" toml:"slowStart,omitempty" yaml:"slowStart,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// Strategy defines how the servers are picked: wrr (weighted round robin), leastconn (least open connections),
+	// or peakewma (lowest peak EWMA of the time to the first byte sent by the server, multiplied by the open connections).
+	// Default: wrr.
+	Strategy string `json:"strategy,omitempty" toml:"strategy,omitempty" yaml:"strategy,omitempty" export:"true"`
+	// FastPath forwards the bytes directly between the client and server TCP connections,
+	// when no middleware transforms or observes them, which lets the kernel forward them on Linux.
+	FastPath bool `json:"fastPath,omitempty" toml:"fastPath,omitempty" yaml:"fastPath,omitempty" export:"true"`
+	// Transparent opens the connections to the servers from the IP address of the client (IP_TRANSPARENT),
+	// for the servers relying on the source IP addresses of the connections. Linux only.
+	Transparent bool `json:"transparent,omitempty" toml:"transparent,omitempty" yaml:"transparent,omitempty" export:"true"`
+	// MultipathTCP opens the connections to the servers with Multipath TCP (MPTCP),
+	// falling back to regular TCP when the kernel or the server does not support it.
+	MultipathTCP bool `json:"multipathTCP,omitempty" toml:"multipathTCP,omitempty" yaml:"multipathTCP,omitempty" export:"true"`
 }
 
 // SetDefaults Default values for a TCPServersLoadBalancer.
@@ -1,11 +1,95 @@
 package dynamic
 
+import (
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
+)
+
 // +k8s:deepcopy-gen=true
 
 // TCPMiddleware holds the TCPMiddleware configuration.
 type TCPMiddleware struct {
-	InFlightConn *TCPInFlightConn `json:"inFlightConn,omitempty" toml:"inFlightConn,omitempty" yaml:"inFlightConn,omitempty" export:"true"`
-	IPWhiteList  *TCPIPWhiteList  `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty" export:"true"`
+	BandwidthLimit *TCPBandwidthLimit `json:"bandwidthLimit,omitempty" toml:"bandwidthLimit,omitempty" yaml:"bandwidthLimit,omitempty" export:"true"`
+	ByteQuota      *TCPByteQuota      `json:"byteQuota,omitempty" toml:"byteQuota,omitempty" yaml:"byteQuota,omitempty" export:"true"`
+	Framing        *TCPFraming        `json:"framing,omitempty" toml:"framing,omitempty" yaml:"framing,omitempty" export:"true"`
+	GeoIP          *TCPGeoIP          `json:"geoIP,omitempty" toml:"geoIP,omitempty" yaml:"geoIP,omitempty" export:"true"`
+	InFlightConn   *TCPInFlightConn   `json:"inFlightConn,omitempty" toml:"inFlightConn,omitempty" yaml:"inFlightConn,omitempty" export:"true"`
+	IPWhiteList    *TCPIPWhiteList    `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty" export:"true"`
+	RateLimit      *TCPRateLimit      `json:"rateLimit,omitempty" toml:"rateLimit,omitempty" yaml:"rateLimit,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPBandwidthLimit holds the TCP BandwidthLimit middleware configuration.
+// This middleware limits the rate, in bytes per second, at which the data is forwarded,
+// independently in each direction.
+type TCPBandwidthLimit struct {
+	// Upstream defines the limits of the data sent by the clients to the service.
+	Upstream *TCPBandwidth `json:"upstream,omitempty" toml:"upstream,omitempty" yaml:"upstream,omitempty" export:"true"`
+	// Downstream defines the limits of the data sent by the service to the clients.
+	Downstream *TCPBandwidth `json:"downstream,omitempty" toml:"downstream,omitempty" yaml:"downstream,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPBandwidth holds the bandwidth limits of a direction of the TCP connections.
+// A zero limit means no limit.
+type TCPBandwidth struct {
+	// PerConnection defines the maximum rate, in bytes per second, of each connection.
+	PerConnection int64 `json:"perConnection,omitempty" toml:"perConnection,omitempty" yaml:"perConnection,omitempty" export:"true"`
+	// PerRouter defines the maximum rate, in bytes per second, shared by all the connections of a router using the middleware.
+	PerRouter int64 `json:"perRouter,omitempty" toml:"perRouter,omitempty" yaml:"perRouter,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPByteQuota holds the TCP ByteQuota middleware configuration.
+// This middleware closes the connections once they have transferred a given amount of data,
+// independently in each direction.
+type TCPByteQuota struct {
+	// Upstream defines the maximum number of bytes sent by the client to the service over a connection.
+	// It defaults to 0, which means no quota.
+	Upstream int64 `json:"upstream,omitempty" toml:"upstream,omitempty" yaml:"upstream,omitempty" export:"true"`
+	// Downstream defines the maximum number of bytes sent by the service to the client over a connection.
+	// It defaults to 0, which means no quota.
+	Downstream int64 `json:"downstream,omitempty" toml:"downstream,omitempty" yaml:"downstream,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPFraming holds the TCP Framing middleware configuration.
+// This middleware forwards the byte stream of the client as length-prefixed frames to the service,
+// and the payloads of the length-prefixed frames of the service as a byte stream to the client.
+type TCPFraming struct {
+	// LengthFieldSize defines the size in bytes of the big-endian length prefix of the frames: 1, 2, 4, or 8.
+	// Default: 4.
+	LengthFieldSize int `json:"lengthFieldSize,omitempty" toml:"lengthFieldSize,omitempty" yaml:"lengthFieldSize,omitempty" export:"true"`
+	// MaxFrameSize defines the maximum size in bytes of the payload of a frame.
+	// The client data is split into frames of at most this size,
+	// and the connection is closed when the service sends a bigger frame.
+	// Default: 1048576, or the maximum length which fits in the length prefix, if lower.
+	MaxFrameSize int64 `json:"maxFrameSize,omitempty" toml:"maxFrameSize,omitempty" yaml:"maxFrameSize,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPGeoIP holds the TCP GeoIP middleware configuration.
+// This middleware accepts/refuses connections based on the country and the autonomous system of the client IP,
+// looked up in MaxMind databases.
+type TCPGeoIP struct {
+	// CountryDatabase defines the path of the MaxMind database of the countries, e.g. GeoLite2-Country.mmdb.
+	CountryDatabase string `json:"countryDatabase,omitempty" toml:"countryDatabase,omitempty" yaml:"countryDatabase,omitempty"`
+	// ASNDatabase defines the path of the MaxMind database of the autonomous systems, e.g. GeoLite2-ASN.mmdb.
+	ASNDatabase string `json:"asnDatabase,omitempty" toml:"asnDatabase,omitempty" yaml:"asnDatabase,omitempty"`
+	// AllowedCountries defines the ISO 3166-1 alpha-2 codes of the allowed countries.
+	AllowedCountries []string `json:"allowedCountries,omitempty" toml:"allowedCountries,omitempty" yaml:"allowedCountries,omitempty" export:"true"`
+	// DeniedCountries defines the ISO 3166-1 alpha-2 codes of the denied countries.
+	DeniedCountries []string `json:"deniedCountries,omitempty" toml:"deniedCountries,omitempty" yaml:"deniedCountries,omitempty" export:"true"`
+	// AllowedASNs defines the numbers of the allowed autonomous systems.
+	AllowedASNs []uint `json:"allowedASNs,omitempty" toml:"allowedASNs,omitempty" yaml:"allowedASNs,omitempty" export:"true"`
+	// DeniedASNs defines the numbers of the denied autonomous systems.
+	DeniedASNs []uint `json:"deniedASNs,omitempty" toml:"deniedASNs,omitempty" yaml:"deniedASNs,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -28,3 +112,33 @@ type TCPIPWhiteList struct {
 	// SourceRange defines the allowed IPs (or ranges of allowed IPs by using CIDR notation).
 	SourceRange []string `json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
 }
+
+// +k8s:deepcopy-gen=true
+
+// TCPRateLimit holds the TCP RateLimit middleware configuration.
+// This middleware limits the rate of the new connections of each client IP,
+// which the InFlightConn middleware does not limit as long as they are short-lived.
+type TCPRateLimit struct {
+	// Average is the maximum rate, by default in connections/s, allowed for a client IP.
+	// It defaults to 0, which means no rate limiting.
+	// The rate is actually defined by dividing Average by Period.
+	Average int64 `json:"average,omitempty" toml:"average,omitempty" yaml:"average,omitempty" export:"true"`
+	// Period, in combination with Average, defines the actual maximum rate, such as:
+	// r = Average / Period. It defaults to a second.
+	Period ptypes.Duration `json:"period,omitempty" toml:"period,omitempty" yaml:"period,omitempty" export:"true"`
+	// Burst is the maximum number of connections allowed to be opened in the same arbitrarily small period of time.
+	// It defaults to 1.
+	Burst int64 `json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty" export:"true"`
+	// TarpitDelay defines how long the connections exceeding the rate are held open, without being read, before being closed.
+	// It defaults to 0, which means that they are closed right away.
+	TarpitDelay ptypes.Duration `json:"tarpitDelay,omitempty" toml:"tarpitDelay,omitempty" yaml:"tarpitDelay,omitempty" export:"true"`
+	// IPv6Subnet is the prefix length of the IPv6 subnets whose addresses are limited as the same client IP (e.g. 64).
+	// It defaults to 0, which means that each IPv6 address is limited separately.
+	IPv6Subnet int `json:"ipv6Subnet,omitempty" toml:"ipv6Subnet,omitempty" yaml:"ipv6Subnet,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a TCPRateLimit.
+func (r *TCPRateLimit) SetDefaults() {
+	r.Burst = 1
+	r.Period = ptypes.Duration(time.Second)
+}
@@ -84,10 +84,12 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware8.headers.stsseconds":                                  "42",
 		"traefik.http.middlewares.Middleware9.ipwhitelist.ipstrategy.depth":                        "42",
 		"traefik.http.middlewares.Middleware9.ipwhitelist.ipstrategy.excludedips":                  "foobar, fiibar",
+		"traefik.http.middlewares.Middleware9.ipwhitelist.ipstrategy.ipv6subnet":                   "42",
 		"traefik.http.middlewares.Middleware9.ipwhitelist.sourcerange":                             "foobar, fiibar",
 		"traefik.http.middlewares.Middleware10.inflightreq.amount":                                 "42",
 		"traefik.http.middlewares.Middleware10.inflightreq.sourcecriterion.ipstrategy.depth":       "42",
 		"traefik.http.middlewares.Middleware10.inflightreq.sourcecriterion.ipstrategy.excludedips": "foobar, fiibar",
+		"traefik.http.middlewares.Middleware10.inflightreq.sourcecriterion.ipstrategy.ipv6subnet":  "42",
 		"traefik.http.middlewares.Middleware10.inflightreq.sourcecriterion.requestheadername":      "foobar",
 		"traefik.http.middlewares.Middleware10.inflightreq.sourcecriterion.requesthost":            "true",
 		"traefik.http.middlewares.Middleware11.passtlsclientcert.info.notafter":                    "true",
@@ -117,6 +119,7 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware12.ratelimit.sourcecriterion.requesthost":              "true",
 		"traefik.http.middlewares.Middleware12.ratelimit.sourcecriterion.ipstrategy.depth":         "42",
 		"traefik.http.middlewares.Middleware12.ratelimit.sourcecriterion.ipstrategy.excludedips":   "foobar, foobar",
+		"traefik.http.middlewares.Middleware12.ratelimit.sourcecriterion.ipstrategy.ipv6subnet":    "42",
 		"traefik.http.middlewares.Middleware13.redirectregex.permanent":                            "true",
 		"traefik.http.middlewares.Middleware13.redirectregex.regex":                                "foobar",
 		"traefik.http.middlewares.Middleware13.redirectregex.replacement":                          "foobar",
@@ -178,26 +181,45 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.services.Service1.loadbalancer.sticky":                           "false",
 		"traefik.http.services.Service1.loadbalancer.sticky.cookie.name":               "fui",
 
-		"traefik.tcp.middlewares.Middleware0.ipwhitelist.sourcerange":      "foobar, fiibar",
-		"traefik.tcp.middlewares.Middleware2.inflightconn.amount":          "42",
-		"traefik.tcp.routers.Router0.rule":                                 "foobar",
-		"traefik.tcp.routers.Router0.priority":                             "42",
-		"traefik.tcp.routers.Router0.entrypoints":                          "foobar, fiibar",
-		"traefik.tcp.routers.Router0.service":                              "foobar",
-		"traefik.tcp.routers.Router0.tls.passthrough":                      "false",
-		"traefik.tcp.routers.Router0.tls.options":                          "foo",
-		"traefik.tcp.routers.Router1.rule":                                 "foobar",
-		"traefik.tcp.routers.Router1.priority":                             "42",
-		"traefik.tcp.routers.Router1.entrypoints":                          "foobar, fiibar",
-		"traefik.tcp.routers.Router1.service":                              "foobar",
-		"traefik.tcp.routers.Router1.tls.options":                          "foo",
-		"traefik.tcp.routers.Router1.tls.passthrough":                      "false",
-		"traefik.tcp.services.Service0.loadbalancer.server.Port":           "42",
-		"traefik.tcp.services.Service0.loadbalancer.TerminationDelay":      "42",
-		"traefik.tcp.services.Service0.loadbalancer.proxyProtocol.version": "42",
-		"traefik.tcp.services.Service1.loadbalancer.server.Port":           "42",
-		"traefik.tcp.services.Service1.loadbalancer.TerminationDelay":      "42",
-		"traefik.tcp.services.Service1.loadbalancer.proxyProtocol":         "true",
+		"traefik.tcp.middlewares.Middleware0.ipwhitelist.sourcerange":                 "foobar, fiibar",
+		"traefik.tcp.middlewares.Middleware2.inflightconn.amount":                     "42",
+		"traefik.tcp.middlewares.Middleware3.framing.lengthfieldsize":                 "42",
+		"traefik.tcp.middlewares.Middleware3.framing.maxframesize":                    "42",
+		"traefik.tcp.middlewares.Middleware4.bandwidthlimit.upstream.perconnection":   "42",
+		"traefik.tcp.middlewares.Middleware4.bandwidthlimit.upstream.perrouter":       "42",
+		"traefik.tcp.middlewares.Middleware4.bandwidthlimit.downstream.perconnection": "42",
+		"traefik.tcp.middlewares.Middleware4.bandwidthlimit.downstream.perrouter":     "42",
+		"traefik.tcp.middlewares.Middleware5.ratelimit.average":                       "42",
+		"traefik.tcp.middlewares.Middleware5.ratelimit.burst":                         "42",
+		"traefik.tcp.middlewares.Middleware5.ratelimit.period":                        "42",
+		"traefik.tcp.middlewares.Middleware5.ratelimit.tarpitdelay":                   "42",
+		"traefik.tcp.middlewares.Middleware5.ratelimit.ipv6subnet":                    "42",
+		"traefik.tcp.middlewares.Middleware6.bytequota.upstream":                      "42",
+		"traefik.tcp.middlewares.Middleware6.bytequota.downstream":                    "42",
+		"traefik.tcp.middlewares.Middleware7.geoip.countrydatabase":                   "foobar",
+		"traefik.tcp.middlewares.Middleware7.geoip.asndatabase":                       "foobar",
+		"traefik.tcp.middlewares.Middleware7.geoip.allowedcountries":                  "foobar, fiibar",
+		"traefik.tcp.middlewares.Middleware7.geoip.deniedcountries":                   "foobar, fiibar",
+		"traefik.tcp.middlewares.Middleware7.geoip.allowedasns":                       "42, 43",
+		"traefik.tcp.middlewares.Middleware7.geoip.deniedasns":                        "42, 43",
+		"traefik.tcp.routers.Router0.rule":                                            "foobar",
+		"traefik.tcp.routers.Router0.priority":                                        "42",
+		"traefik.tcp.routers.Router0.entrypoints":                                     "foobar, fiibar",
+		"traefik.tcp.routers.Router0.service":                                         "foobar",
+		"traefik.tcp.routers.Router0.tls.passthrough":                                 "false",
+		"traefik.tcp.routers.Router0.tls.options":                                     "foo",
+		"traefik.tcp.routers.Router1.rule":                                            "foobar",
+		"traefik.tcp.routers.Router1.priority":                                        "42",
+		"traefik.tcp.routers.Router1.entrypoints":                                     "foobar, fiibar",
+		"traefik.tcp.routers.Router1.service":                                         "foobar",
+		"traefik.tcp.routers.Router1.tls.options":                                     "foo",
+		"traefik.tcp.routers.Router1.tls.passthrough":                                 "false",
+		"traefik.tcp.services.Service0.loadbalancer.server.Port":                      "42",
+		"traefik.tcp.services.Service0.loadbalancer.TerminationDelay":                 "42",
+		"traefik.tcp.services.Service0.loadbalancer.proxyProtocol.version":            "42",
+		"traefik.tcp.services.Service1.loadbalancer.server.Port":                      "42",
+		"traefik.tcp.services.Service1.loadbalancer.TerminationDelay":                 "42",
+		"traefik.tcp.services.Service1.loadbalancer.proxyProtocol":                    "true",
 
 		"traefik.udp.routers.Router0.entrypoints":                "foobar, fiibar",
 		"traefik.udp.routers.Router0.service":                    "foobar",
@@ -251,6 +273,49 @@ func TestDecodeConfiguration(t *testing.T) {
 						Amount: 42,
 					},
 				},
+				"Middleware3": {
+					Framing: &dynamic.TCPFraming{
+						LengthFieldSize: 42,
+						MaxFrameSize:    42,
+					},
+				},
+				"Middleware4": {
+					BandwidthLimit: &dynamic.TCPBandwidthLimit{
+						Upstream: &dynamic.TCPBandwidth{
+							PerConnection: 42,
+							PerRouter:     42,
+						},
+						Downstream: &dynamic.TCPBandwidth{
+							PerConnection: 42,
+							PerRouter:     42,
+						},
+					},
+				},
+				"Middleware5": {
+					RateLimit: &dynamic.TCPRateLimit{
+						Average:     42,
+						Burst:       42,
+						Period:      ptypes.Duration(42 * time.Second),
+						TarpitDelay: ptypes.Duration(42 * time.Second),
+						IPv6Subnet:  42,
+					},
+				},
+				"Middleware6": {
+					ByteQuota: &dynamic.TCPByteQuota{
+						Upstream:   42,
+						Downstream: 42,
+					},
+				},
+				"Middleware7": {
+					GeoIP: &dynamic.TCPGeoIP{
+						CountryDatabase:  "foobar",
+						ASNDatabase:      "foobar",
+						AllowedCountries: []string{"foobar", "fiibar"},
+						DeniedCountries:  []string{"foobar", "fiibar"},
+						AllowedASNs:      []uint{42, 43},
+						DeniedASNs:       []uint{42, 43},
+					},
+				},
 			},
 			Services: map[string]*dynamic.TCPService{
 				"Service0": {
@@ -370,6 +435,7 @@ func TestDecodeConfiguration(t *testing.T) {
 							IPStrategy: &dynamic.IPStrategy{
 								Depth:       42,
 								ExcludedIPs: []string{"foobar", "fiibar"},
+								IPv6Subnet:  42,
 							},
 							RequestHeaderName: "foobar",
 							RequestHost:       true,
@@ -415,6 +481,7 @@ func TestDecodeConfiguration(t *testing.T) {
 							IPStrategy: &dynamic.IPStrategy{
 								Depth:       42,
 								ExcludedIPs: []string{"foobar", "foobar"},
+								IPv6Subnet:  42,
 							},
 							RequestHeaderName: "foobar",
 							RequestHost:       true,
@@ -620,6 +687,7 @@ func TestDecodeConfiguration(t *testing.T) {
 								"foobar",
 								"fiibar",
 							},
+							IPv6Subnet: 42,
 						},
 					},
 				},
@@ -746,6 +814,49 @@ func TestEncodeConfiguration(t *testing.T) {
 						Amount: 42,
 					},
 				},
+				"Middleware3": {
+					Framing: &dynamic.TCPFraming{
+						LengthFieldSize: 42,
+						MaxFrameSize:    42,
+					},
+				},
+				"Middleware4": {
+					BandwidthLimit: &dynamic.TCPBandwidthLimit{
+						Upstream: &dynamic.TCPBandwidth{
+							PerConnection: 42,
+							PerRouter:     42,
+						},
+						Downstream: &dynamic.TCPBandwidth{
+							PerConnection: 42,
+							PerRouter:     42,
+						},
+					},
+				},
+				"Middleware5": {
+					RateLimit: &dynamic.TCPRateLimit{
+						Average:     42,
+						Burst:       42,
+						Period:      ptypes.Duration(42 * time.Second),
+						TarpitDelay: ptypes.Duration(42 * time.Second),
+						IPv6Subnet:  42,
+					},
+				},
+				"Middleware6": {
+					ByteQuota: &dynamic.TCPByteQuota{
+						Upstream:   42,
+						Downstream: 42,
+					},
+				},
+				"Middleware7": {
+					GeoIP: &dynamic.TCPGeoIP{
+						CountryDatabase:  "foobar",
+						ASNDatabase:      "foobar",
+						AllowedCountries: []string{"foobar", "fiibar"},
+						DeniedCountries:  []string{"foobar", "fiibar"},
+						AllowedASNs:      []uint{42, 43},
+						DeniedASNs:       []uint{42, 43},
+					},
+				},
 			},
 			Services: map[string]*dynamic.TCPService{
 				"Service0": {
@@ -863,6 +974,7 @@ func TestEncodeConfiguration(t *testing.T) {
 							IPStrategy: &dynamic.IPStrategy{
 								Depth:       42,
 								ExcludedIPs: []string{"foobar", "fiibar"},
+								IPv6Subnet:  42,
 							},
 							RequestHeaderName: "foobar",
 							RequestHost:       true,
@@ -907,6 +1019,7 @@ func TestEncodeConfiguration(t *testing.T) {
 							IPStrategy: &dynamic.IPStrategy{
 								Depth:       42,
 								ExcludedIPs: []string{"foobar", "foobar"},
+								IPv6Subnet:  42,
 							},
 							RequestHeaderName: "foobar",
 							RequestHost:       true,
@@ -1120,6 +1233,7 @@ func TestEncodeConfiguration(t *testing.T) {
 								"foobar",
 								"fiibar",
 							},
+							IPv6Subnet: 42,
 						},
 					},
 				},
@@ -1263,10 +1377,12 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware8.Headers.STSSeconds":                                  "42",
 		"traefik.HTTP.Middlewares.Middleware9.IPWhiteList.IPStrategy.Depth":                        "42",
 		"traefik.HTTP.Middlewares.Middleware9.IPWhiteList.IPStrategy.ExcludedIPs":                  "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware9.IPWhiteList.IPStrategy.IPv6Subnet":                   "42",
 		"traefik.HTTP.Middlewares.Middleware9.IPWhiteList.SourceRange":                             "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware10.InFlightReq.Amount":                                 "42",
 		"traefik.HTTP.Middlewares.Middleware10.InFlightReq.SourceCriterion.IPStrategy.Depth":       "42",
 		"traefik.HTTP.Middlewares.Middleware10.InFlightReq.SourceCriterion.IPStrategy.ExcludedIPs": "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware10.InFlightReq.SourceCriterion.IPStrategy.IPv6Subnet":  "42",
 		"traefik.HTTP.Middlewares.Middleware10.InFlightReq.SourceCriterion.RequestHeaderName":      "foobar",
 		"traefik.HTTP.Middlewares.Middleware10.InFlightReq.SourceCriterion.RequestHost":            "true",
 		"traefik.HTTP.Middlewares.Middleware11.PassTLSClientCert.Info.NotAfter":                    "true",
@@ -1296,6 +1412,7 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware12.RateLimit.SourceCriterion.RequestHost":              "true",
 		"traefik.HTTP.Middlewares.Middleware12.RateLimit.SourceCriterion.IPStrategy.Depth":         "42",
 		"traefik.HTTP.Middlewares.Middleware12.RateLimit.SourceCriterion.IPStrategy.ExcludedIPs":   "foobar, foobar",
+		"traefik.HTTP.Middlewares.Middleware12.RateLimit.SourceCriterion.IPStrategy.IPv6Subnet":    "42",
 		"traefik.HTTP.Middlewares.Middleware13.RedirectRegex.Regex":                                "foobar",
 		"traefik.HTTP.Middlewares.Middleware13.RedirectRegex.Replacement":                          "foobar",
 		"traefik.HTTP.Middlewares.Middleware13.RedirectRegex.Permanent":                            "true",
@@ -1356,24 +1473,49 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Services.Service1.LoadBalancer.server.Scheme":                    "foobar",
 		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Headers.name0":        "foobar",
 
-		"traefik.TCP.Middlewares.Middleware0.IPWhiteList.SourceRange": "foobar, fiibar",
-		"traefik.TCP.Middlewares.Middleware2.InFlightConn.Amount":     "42",
-		"traefik.TCP.Routers.Router0.Rule":                            "foobar",
-		"traefik.TCP.Routers.Router0.Priority":                        "42",
-		"traefik.TCP.Routers.Router0.EntryPoints":                     "foobar, fiibar",
-		"traefik.TCP.Routers.Router0.Service":                         "foobar",
-		"traefik.TCP.Routers.Router0.TLS.Passthrough":                 "false",
-		"traefik.TCP.Routers.Router0.TLS.Options":                     "foo",
-		"traefik.TCP.Routers.Router1.Rule":                            "foobar",
-		"traefik.TCP.Routers.Router1.Priority":                        "42",
-		"traefik.TCP.Routers.Router1.EntryPoints":                     "foobar, fiibar",
-		"traefik.TCP.Routers.Router1.Service":                         "foobar",
-		"traefik.TCP.Routers.Router1.TLS.Passthrough":                 "false",
-		"traefik.TCP.Routers.Router1.TLS.Options":                     "foo",
-		"traefik.TCP.Services.Service0.LoadBalancer.server.Port":      "42",
-		"traefik.TCP.Services.Service0.LoadBalancer.TerminationDelay": "42",
-		"traefik.TCP.Services.Service1.LoadBalancer.server.Port":      "42",
-		"traefik.TCP.Services.Service1.LoadBalancer.TerminationDelay": "42",
+		"traefik.TCP.Middlewares.Middleware0.IPWhiteList.SourceRange":                 "foobar, fiibar",
+		"traefik.TCP.Middlewares.Middleware2.InFlightConn.Amount":                     "42",
+		"traefik.TCP.Middlewares.Middleware3.Framing.LengthFieldSize":                 "42",
+		"traefik.TCP.Middlewares.Middleware3.Framing.MaxFrameSize":                    "42",
+		"traefik.TCP.Middlewares.Middleware4.BandwidthLimit.Upstream.PerConnection":   "42",
+		"traefik.TCP.Middlewares.Middleware4.BandwidthLimit.Upstream.PerRouter":       "42",
+		"traefik.TCP.Middlewares.Middleware4.BandwidthLimit.Downstream.PerConnection": "42",
+		"traefik.TCP.Middlewares.Middleware4.BandwidthLimit.Downstream.PerRouter":     "42",
+		"traefik.TCP.Middlewares.Middleware5.RateLimit.Average":                       "42",
+		"traefik.TCP.Middlewares.Middleware5.RateLimit.Burst":                         "42",
+		"traefik.TCP.Middlewares.Middleware5.RateLimit.Period":                        "42000000000",
+		"traefik.TCP.Middlewares.Middleware5.RateLimit.TarpitDelay":                   "42000000000",
+		"traefik.TCP.Middlewares.Middleware5.RateLimit.IPv6Subnet":                    "42",
+		"traefik.TCP.Middlewares.Middleware6.ByteQuota.Upstream":                      "42",
+		"traefik.TCP.Middlewares.Middleware6.ByteQuota.Downstream":                    "42",
+		"traefik.TCP.Middlewares.Middleware7.GeoIP.CountryDatabase":                   "foobar",
+		"traefik.TCP.Middlewares.Middleware7.GeoIP.ASNDatabase":                       "foobar",
+		"traefik.TCP.Middlewares.Middleware7.GeoIP.AllowedCountries":                  "foobar, fiibar",
+		"traefik.TCP.Middlewares.Middleware7.GeoIP.DeniedCountries":                   "foobar, fiibar",
+		"traefik.TCP.Middlewares.Middleware7.GeoIP.AllowedASNs":                       "42, 43",
+		"traefik.TCP.Middlewares.Middleware7.GeoIP.DeniedASNs":                        "42, 43",
+		"traefik.TCP.Routers.Router0.Rule":                                            "foobar",
+		"traefik.TCP.Routers.Router0.Priority":                                        "42",
+		"traefik.TCP.Routers.Router0.EntryPoints":                                     "foobar, fiibar",
+		"traefik.TCP.Routers.Router0.Service":                                         "foobar",
+		"traefik.TCP.Routers.Router0.TLS.Passthrough":                                 "false",
+		"traefik.TCP.Routers.Router0.TLS.Options":                                     "foo",
+		"traefik.TCP.Routers.Router1.Rule":                                            "foobar",
+		"traefik.TCP.Routers.Router1.Priority":                                        "42",
+		"traefik.TCP.Routers.Router1.EntryPoints":                                     "foobar, fiibar",
+		"traefik.TCP.Routers.Router1.Service":                                         "foobar",
+		"traefik.TCP.Routers.Router1.TLS.Passthrough":                                 "false",
+		"traefik.TCP.Routers.Router1.TLS.Options":                                     "foo",
+		"traefik.TCP.Services.Service0.LoadBalancer.server.Port":                      "42",
+		"traefik.TCP.Services.Service0.LoadBalancer.TerminationDelay":                 "42",
+		"traefik.TCP.Services.Service0.LoadBalancer.MultipathTCP":                     "false",
+		"traefik.TCP.Services.Service0.LoadBalancer.Transparent":                      "false",
+		"traefik.TCP.Services.Service0.LoadBalancer.FastPath":                         "false",
+		"traefik.TCP.Services.Service1.LoadBalancer.server.Port":                      "42",
+		"traefik.TCP.Services.Service1.LoadBalancer.TerminationDelay":                 "42",
+		"traefik.TCP.Services.Service1.LoadBalancer.MultipathTCP":                     "false",
+		"traefik.TCP.Services.Service1.LoadBalancer.Transparent":                      "false",
+		"traefik.TCP.Services.Service1.LoadBalancer.FastPath":                         "false",
 
 		"traefik.UDP.Routers.Router0.EntryPoints":                "foobar, fiibar",
 		"traefik.UDP.Routers.Router0.Service":                    "foobar",
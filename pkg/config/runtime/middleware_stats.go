@@ -0,0 +1,98 @@
+package runtime
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// MiddlewareStats holds the counters of a middleware, since the last configuration reload.
+type MiddlewareStats struct {
+	// Invocations is the number of requests, or connections, which went through the middleware.
+	Invocations uint64 `json:"invocations"`
+	// Rejections is the number of requests, or connections, which the middleware answered or closed itself,
+	// instead of forwarding them.
+	Rejections uint64 `json:"rejections"`
+	// Errors is the number of requests, or connections, which the middleware failed to handle.
+	Errors uint64 `json:"errors"`
+	// AverageLatency is the average time spent in the middleware itself, excluding the handlers following it.
+	AverageLatency string `json:"averageLatency,omitempty"`
+}
+
+// middlewareCounters counts the requests, or connections, going through a middleware.
+type middlewareCounters struct {
+	invocations atomic.Uint64
+	rejections  atomic.Uint64
+	errors      atomic.Uint64
+	// forwarded is the number of connections forwarded to the handlers following the middleware.
+	forwarded atomic.Uint64
+	// latency is the total time spent in the middleware, in nanoseconds.
+	latency atomic.Uint64
+	// timed is the number of invocations whose latency was measured.
+	timed atomic.Uint64
+}
+
+func (c *middlewareCounters) get() MiddlewareStats {
+	stats := MiddlewareStats{
+		Invocations: c.invocations.Load(),
+		Rejections:  c.rejections.Load(),
+		Errors:      c.errors.Load(),
+	}
+
+	if timed := c.timed.Load(); timed > 0 {
+		stats.AverageLatency = (time.Duration(c.latency.Load() / timed)).String()
+	}
+
+	return stats
+}
+
+// RecordRequest records a request which went through the middleware,
+// whether the middleware rejected it, or failed to handle it, and the time spent in the middleware itself.
+func (m *MiddlewareInfo) RecordRequest(rejected, failed bool, latency time.Duration) {
+	m.stats.invocations.Add(1)
+
+	if failed {
+		m.stats.errors.Add(1)
+	} else if rejected {
+		m.stats.rejections.Add(1)
+	}
+
+	if latency < 0 {
+		latency = 0
+	}
+	m.stats.latency.Add(uint64(latency))
+	m.stats.timed.Add(1)
+}
+
+// GetStats returns the counters of the middleware.
+func (m *MiddlewareInfo) GetStats() MiddlewareStats {
+	return m.stats.get()
+}
+
+// RecordConnection records a connection entering the middleware.
+func (m *TCPMiddlewareInfo) RecordConnection() {
+	m.stats.invocations.Add(1)
+}
+
+// RecordForwardedConnection records a connection forwarded by the middleware to the handlers following it.
+func (m *TCPMiddlewareInfo) RecordForwardedConnection() {
+	m.stats.forwarded.Add(1)
+}
+
+// RecordFailedConnection records a connection which the middleware failed to handle.
+func (m *TCPMiddlewareInfo) RecordFailedConnection() {
+	m.stats.errors.Add(1)
+}
+
+// GetStats returns the counters of the middleware.
+// As the connections are not followed through the middleware, the rejected connections are the ones which were not forwarded,
+// including the ones which are not forwarded yet.
+func (m *TCPMiddlewareInfo) GetStats() MiddlewareStats {
+	stats := m.stats.get()
+
+	handled := m.stats.forwarded.Load() + stats.Errors
+	if stats.Invocations > handled {
+		stats.Rejections = stats.Invocations - handled
+	}
+
+	return stats
+}
@@ -132,6 +132,13 @@ func (c *Configuration) PopulateUsedBy() {
 			c.Middlewares[fullMidName].UsedBy = append(c.Middlewares[fullMidName].UsedBy, routerName)
 		}
 
+		if tunnel := routerInfo.Router.Tunnel; tunnel != nil {
+			tcpServiceName := getQualifiedName(providerName, tunnel.Service)
+			if _, ok := c.TCPServices[tcpServiceName]; ok {
+				c.TCPServices[tcpServiceName].UsedBy = append(c.TCPServices[tcpServiceName].UsedBy, routerName)
+			}
+		}
+
 		serviceName := getQualifiedName(providerName, routerInfo.Router.Service)
 		if _, ok := c.Services[serviceName]; !ok {
 			continue
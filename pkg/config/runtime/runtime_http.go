@@ -6,6 +6,7 @@ import (
 	"slices"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
@@ -108,6 +109,8 @@ type MiddlewareInfo struct {
 	Err    []string `json:"error,omitempty"`
 	Status string   `json:"status,omitempty"`
 	UsedBy []string `json:"usedBy,omitempty"` // list of routers and services using that middleware.
+
+	stats middlewareCounters
 }
 
 // AddError adds err to s.Err, if it does not already exist.
@@ -143,7 +146,19 @@ type ServiceInfo struct {
 	UsedBy []string `json:"usedBy,omitempty"` // list of routers using that service
 
 	serverStatusMu sync.RWMutex
-	serverStatus   map[string]string // keyed by server URL
+	serverStatus   map[string]string       // keyed by server URL
+	serverHealth   map[string]ServerHealth // keyed by server URL
+}
+
+// ServerHealth holds the result of the health checks of a server.
+type ServerHealth struct {
+	Status string `json:"status,omitempty"`
+	// Reason is the reason of the last failed health check, when the server is down.
+	Reason string `json:"reason,omitempty"`
+	// LastCheck is the time of the last health check.
+	LastCheck time.Time `json:"lastCheck"`
+	// Since is the time of the last status change.
+	Since time.Time `json:"since"`
 }
 
 // AddError adds err to s.Err, if it does not already exist.
@@ -179,6 +194,45 @@ func (s *ServiceInfo) UpdateServerStatus(server, status string) {
 	s.serverStatus[server] = status
 }
 
+// UpdateServerHealth records the result of a health check of the server in the ServiceInfo.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) UpdateServerHealth(server, status, reason string, checkedAt time.Time) {
+	s.serverStatusMu.Lock()
+	defer s.serverStatusMu.Unlock()
+
+	if s.serverHealth == nil {
+		s.serverHealth = make(map[string]ServerHealth)
+	}
+
+	health, ok := s.serverHealth[server]
+	if !ok || health.Status != status {
+		health.Since = checkedAt
+	}
+
+	health.Status = status
+	health.Reason = reason
+	health.LastCheck = checkedAt
+
+	s.serverHealth[server] = health
+}
+
+// GetAllHealth returns the health of all the health checked servers in ServiceInfo.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) GetAllHealth() map[string]ServerHealth {
+	s.serverStatusMu.RLock()
+	defer s.serverStatusMu.RUnlock()
+
+	if len(s.serverHealth) == 0 {
+		return nil
+	}
+
+	allHealth := make(map[string]ServerHealth, len(s.serverHealth))
+	for k, v := range s.serverHealth {
+		allHealth[k] = v
+	}
+	return allHealth
+}
+
 // GetAllStatus returns all the statuses of all the servers in ServiceInfo.
 // It is the responsibility of the caller to check that s is not nil.
 func (s *ServiceInfo) GetAllStatus() map[string]string {
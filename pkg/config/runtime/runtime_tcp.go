@@ -115,6 +115,8 @@ type TCPMiddlewareInfo struct {
 	Err    []string `json:"error,omitempty"`
 	Status string   `json:"status,omitempty"`
 	UsedBy []string `json:"usedBy,omitempty"` // list of TCP routers and services using that middleware.
+
+	stats middlewareCounters
 }
 
 // AddError adds err to s.Err, if it does not already exist.
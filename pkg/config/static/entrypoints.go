@@ -7,30 +7,54 @@ import (
 
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/types"
+	"github.com/traefik/traefik/v2/pkg/unixsocket"
 )
 
 // EntryPoint holds the entry point configuration.
 type EntryPoint struct {
-	Address          string                `description:"Entry point address." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
-	Transport        *EntryPointsTransport `description:"Configures communication between clients and Traefik." json:"transport,omitempty" toml:"transport,omitempty" yaml:"transport,omitempty" export:"true"`
-	ProxyProtocol    *ProxyProtocol        `description:"Proxy-Protocol configuration." json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
-	ForwardedHeaders *ForwardedHeaders     `description:"Trust client forwarding headers." json:"forwardedHeaders,omitempty" toml:"forwardedHeaders,omitempty" yaml:"forwardedHeaders,omitempty" export:"true"`
-	HTTP             HTTPConfig            `description:"HTTP configuration." json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty" export:"true"`
-	HTTP2            *HTTP2Config          `description:"HTTP/2 configuration." json:"http2,omitempty" toml:"http2,omitempty" yaml:"http2,omitempty" export:"true"`
-	HTTP3            *HTTP3Config          `description:"HTTP/3 configuration." json:"http3,omitempty" toml:"http3,omitempty" yaml:"http3,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
-	UDP              *UDPConfig            `description:"UDP configuration." json:"udp,omitempty" toml:"udp,omitempty" yaml:"udp,omitempty"`
+	Address           string                `description:"Entry point address." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	Transport         *EntryPointsTransport `description:"Configures communication between clients and Traefik." json:"transport,omitempty" toml:"transport,omitempty" yaml:"transport,omitempty" export:"true"`
+	ProxyProtocol     *ProxyProtocol        `description:"Proxy-Protocol configuration." json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	ForwardedHeaders  *ForwardedHeaders     `description:"Trust client forwarding headers." json:"forwardedHeaders,omitempty" toml:"forwardedHeaders,omitempty" yaml:"forwardedHeaders,omitempty" export:"true"`
+	ClientIP          *ClientIP             `description:"Derivation of the client IP, used by the IP white lists, the rate limits, and the access logs, from the trusted proxies chain." json:"clientIP,omitempty" toml:"clientIP,omitempty" yaml:"clientIP,omitempty" export:"true"`
+	HTTP              HTTPConfig            `description:"HTTP configuration." json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty" export:"true"`
+	HTTP2             *HTTP2Config          `description:"HTTP/2 configuration." json:"http2,omitempty" toml:"http2,omitempty" yaml:"http2,omitempty" export:"true"`
+	HTTP3             *HTTP3Config          `description:"HTTP/3 configuration." json:"http3,omitempty" toml:"http3,omitempty" yaml:"http3,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	UDP               *UDPConfig            `description:"UDP configuration." json:"udp,omitempty" toml:"udp,omitempty" yaml:"udp,omitempty"`
+	Sharding          *Sharding             `description:"Shards the accept work of the entry point across several listeners." json:"sharding,omitempty" toml:"sharding,omitempty" yaml:"sharding,omitempty" export:"true"`
+	UnixSocket        *UnixSocketConfig     `description:"Unix domain socket configuration, for the unix:// addresses." json:"unixSocket,omitempty" toml:"unixSocket,omitempty" yaml:"unixSocket,omitempty" export:"true"`
+	MultipathTCP      bool                  `description:"Accepts Multipath TCP (MPTCP) connections, along with the regular TCP ones." json:"multipathTCP,omitempty" toml:"multipathTCP,omitempty" yaml:"multipathTCP,omitempty" export:"true"`
+	CongestionControl string                `description:"TCP congestion control algorithm of the accepted connections, such as bbr (Linux only)." json:"congestionControl,omitempty" toml:"congestionControl,omitempty" yaml:"congestionControl,omitempty" export:"true"`
+	FairQueueing      *FairQueueing         `description:"Shares the bandwidth of the entry point fairly across its TCP connections." json:"fairQueueing,omitempty" toml:"fairQueueing,omitempty" yaml:"fairQueueing,omitempty" export:"true"`
+	TLSKeyLogFile     string                `description:"Writes the TLS session secrets of the entry point to the given file, in the NSS key log format (debugging only)." json:"tlsKeyLogFile,omitempty" toml:"tlsKeyLogFile,omitempty" yaml:"tlsKeyLogFile,omitempty"`
 }
 
 // GetAddress strips any potential protocol part of the address field of the
 // entry point, in order to return the actual address.
+// For a Unix domain socket, it returns the path of the socket.
 func (ep EntryPoint) GetAddress() string {
+	if path, ok := unixsocket.Path(ep.Address); ok {
+		return path
+	}
+
 	splitN := strings.SplitN(ep.Address, "/", 2)
 	return splitN[0]
 }
 
+// GetUnixSocketPath returns the path of the Unix domain socket of the entry point,
+// and whether its address is a unix:// one.
+func (ep EntryPoint) GetUnixSocketPath() (string, bool) {
+	return unixsocket.Path(ep.Address)
+}
+
 // GetProtocol returns the protocol part of the address field of the entry point.
 // If none is specified, it defaults to "tcp".
+// The entry points listening on a Unix domain socket are "tcp" ones, as they accept stream connections.
 func (ep EntryPoint) GetProtocol() (string, error) {
+	if _, ok := unixsocket.Path(ep.Address); ok {
+		return "tcp", nil
+	}
+
 	splitN := strings.SplitN(ep.Address, "/", 2)
 	if len(splitN) < 2 {
 		return "tcp", nil
@@ -57,10 +81,43 @@ func (ep *EntryPoint) SetDefaults() {
 
 // HTTPConfig is the HTTP configuration of an entry point.
 type HTTPConfig struct {
-	Redirections          *Redirections `description:"Set of redirection" json:"redirections,omitempty" toml:"redirections,omitempty" yaml:"redirections,omitempty" export:"true"`
-	Middlewares           []string      `description:"Default middlewares for the routers linked to the entry point." json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
-	TLS                   *TLSConfig    `description:"Default TLS configuration for the routers linked to the entry point." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
-	EncodeQuerySemicolons bool          `description:"Defines whether request query semicolons should be URLEncoded." json:"encodeQuerySemicolons,omitempty" toml:"encodeQuerySemicolons,omitempty" yaml:"encodeQuerySemicolons,omitempty"`
+	Redirections          *Redirections  `description:"Set of redirection" json:"redirections,omitempty" toml:"redirections,omitempty" yaml:"redirections,omitempty" export:"true"`
+	Middlewares           []string       `description:"Default middlewares for the routers linked to the entry point." json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
+	TLS                   *TLSConfig     `description:"Default TLS configuration for the routers linked to the entry point." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	EncodeQuerySemicolons bool           `description:"Defines whether request query semicolons should be URLEncoded." json:"encodeQuerySemicolons,omitempty" toml:"encodeQuerySemicolons,omitempty" yaml:"encodeQuerySemicolons,omitempty"`
+	StrictParsing         *StrictParsing `description:"Strict parsing of the requests, against request smuggling." json:"strictParsing,omitempty" toml:"strictParsing,omitempty" yaml:"strictParsing,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// Modes of the handling of the duplicated headers.
+const (
+	DuplicateHeadersKeep   = "keep"
+	DuplicateHeadersReject = "reject"
+	DuplicateHeadersMerge  = "merge"
+)
+
+// Modes of the normalization of the request paths.
+const (
+	PathNormalizationNone         = "none"
+	PathNormalizationDotSegments  = "dotSegments"
+	PathNormalizationMergeSlashes = "mergeSlashes"
+	PathNormalizationReject       = "reject"
+)
+
+// StrictParsing is the configuration of the strict parsing of the requests of an entry point,
+// which rejects the requests that the backends could parse differently.
+type StrictParsing struct {
+	RejectAmbiguousFraming  bool   `description:"Rejects the requests with both Content-Length and Transfer-Encoding headers, several or invalid Content-Length headers, or a Transfer-Encoding not ending with chunked." json:"rejectAmbiguousFraming,omitempty" toml:"rejectAmbiguousFraming,omitempty" yaml:"rejectAmbiguousFraming,omitempty" export:"true"`
+	DuplicateHeaders        string `description:"Handling of the duplicated headers: keep, reject (the headers allowing a single value), or merge (also merging the other ones into a single line)." json:"duplicateHeaders,omitempty" toml:"duplicateHeaders,omitempty" yaml:"duplicateHeaders,omitempty" export:"true"`
+	MaxChunkExtensionLength int    `description:"Maximum length of the extensions of each chunk of the request bodies (0 means no limit)." json:"maxChunkExtensionLength,omitempty" toml:"maxChunkExtensionLength,omitempty" yaml:"maxChunkExtensionLength,omitempty" export:"true"`
+	PathNormalization       string `description:"Normalization of the request paths: none, dotSegments, mergeSlashes (also removing the dot segments), or reject (the paths which are not normalized)." json:"pathNormalization,omitempty" toml:"pathNormalization,omitempty" yaml:"pathNormalization,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (s *StrictParsing) SetDefaults() {
+	s.RejectAmbiguousFraming = true
+	s.DuplicateHeaders = DuplicateHeadersKeep
+	s.MaxChunkExtensionLength = 256
+	s.PathNormalization = PathNormalizationNone
 }
 
 // HTTP2Config is the HTTP2 configuration of an entry point.
@@ -107,8 +164,14 @@ type TLSConfig struct {
 
 // ForwardedHeaders Trust client forwarding headers.
 type ForwardedHeaders struct {
-	Insecure   bool     `description:"Trust all forwarded headers." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
-	TrustedIPs []string `description:"Trust only forwarded headers from selected IPs." json:"trustedIPs,omitempty" toml:"trustedIPs,omitempty" yaml:"trustedIPs,omitempty"`
+	Insecure   bool       `description:"Trust all forwarded headers." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
+	TrustedIPs []string   `description:"Trust only forwarded headers from selected IPs." json:"trustedIPs,omitempty" toml:"trustedIPs,omitempty" yaml:"trustedIPs,omitempty"`
+	Forwarded  *Forwarded `description:"Parses and generates the RFC 7239 Forwarded header, in addition to the X-Forwarded ones." json:"forwarded,omitempty" toml:"forwarded,omitempty" yaml:"forwarded,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// Forwarded is the configuration of the RFC 7239 Forwarded header.
+type Forwarded struct {
+	Obfuscate bool `description:"Replaces the addresses of the client and of the entry point by obfuscated identifiers." json:"obfuscate,omitempty" toml:"obfuscate,omitempty" yaml:"obfuscate,omitempty" export:"true"`
 }
 
 // ProxyProtocol contains Proxy-Protocol configuration.
@@ -120,6 +183,19 @@ type ProxyProtocol struct {
 // EntryPoints holds the HTTP entry point list.
 type EntryPoints map[string]*EntryPoint
 
+// ClientIP is the configuration of the derivation of the client IP of the requests,
+// from the chain of the proxies in front of the entry point.
+type ClientIP struct {
+	TrustedProxies []string `description:"Trusted proxies, as IP or CIDR, whose forwarding headers are read." json:"trustedProxies,omitempty" toml:"trustedProxies,omitempty" yaml:"trustedProxies,omitempty"`
+	Hops           int      `description:"Maximum number of proxies in front of the entry point (0 means no limit)." json:"hops,omitempty" toml:"hops,omitempty" yaml:"hops,omitempty" export:"true"`
+	Headers        []string `description:"Forwarding headers read, by precedence, among Forwarded, X-Forwarded-For, and X-Real-IP." json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *ClientIP) SetDefaults() {
+	c.Headers = []string{"Forwarded", "X-Forwarded-For", "X-Real-IP"}
+}
+
 // EntryPointsTransport configures communication between clients and Traefik.
 type EntryPointsTransport struct {
 	LifeCycle          *LifeCycle          `description:"Timeouts influencing the server life cycle." json:"lifeCycle,omitempty" toml:"lifeCycle,omitempty" yaml:"lifeCycle,omitempty" export:"true"`
@@ -134,9 +210,36 @@ func (t *EntryPointsTransport) SetDefaults() {
 	t.RespondingTimeouts.SetDefaults()
 }
 
+// Sharding is the configuration of the sharding of the accept work of a TCP entry point,
+// across several listeners sharing its address, each with its own accept loop.
+type Sharding struct {
+	Shards int `description:"Number of listeners, each with its own accept loop." json:"shards,omitempty" toml:"shards,omitempty" yaml:"shards,omitempty" export:"true"`
+}
+
+// FairQueueing is the configuration of the fair queueing of the bandwidth of a TCP entry point across its connections,
+// which prevents a bulk transfer from starving the interactive connections when the link is saturated.
+type FairQueueing struct {
+	Bandwidth int64 `description:"Bandwidth shared by the connections in each direction, in bytes per second, slightly below the one of the link." json:"bandwidth,omitempty" toml:"bandwidth,omitempty" yaml:"bandwidth,omitempty" export:"true"`
+	Quantum   int   `description:"Number of bytes a connection transfers at once, before the others get their turn." json:"quantum,omitempty" toml:"quantum,omitempty" yaml:"quantum,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (f *FairQueueing) SetDefaults() {
+	f.Quantum = 16 * 1024
+}
+
+// UnixSocketConfig is the configuration of the Unix domain socket of an entry point.
+type UnixSocketConfig struct {
+	Mode  string `description:"File mode of the socket, in octal, such as 0660." json:"mode,omitempty" toml:"mode,omitempty" yaml:"mode,omitempty" export:"true"`
+	User  string `description:"Owner of the socket, as a user name or ID." json:"user,omitempty" toml:"user,omitempty" yaml:"user,omitempty" export:"true"`
+	Group string `description:"Group of the socket, as a group name or ID." json:"group,omitempty" toml:"group,omitempty" yaml:"group,omitempty" export:"true"`
+}
+
 // UDPConfig is the UDP configuration of an entry point.
 type UDPConfig struct {
-	Timeout ptypes.Duration `description:"Timeout defines how long to wait on an idle session before releasing the related resources." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Timeout              ptypes.Duration `description:"Timeout defines how long to wait on an idle session before releasing the related resources." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty"`
+	SourceAddress        string          `description:"Local address (IP, or IP:port) from which the datagrams are sent to the backends." json:"sourceAddress,omitempty" toml:"sourceAddress,omitempty" yaml:"sourceAddress,omitempty"`
+	ReplyFromDestination bool            `description:"Sends the replies to the clients from the address their datagrams were received on." json:"replyFromDestination,omitempty" toml:"replyFromDestination,omitempty" yaml:"replyFromDestination,omitempty"`
 }
 
 // SetDefaults sets the default values.
@@ -62,6 +62,8 @@ type Configuration struct {
 	Global *Global `description:"Global configuration options" json:"global,omitempty" toml:"global,omitempty" yaml:"global,omitempty" export:"true"`
 
 	ServersTransport *ServersTransport `description:"Servers default transport." json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
+	ServersResolver  *ServersResolver  `description:"Look the hostnames of the servers up with an internal DNS resolver and cache." json:"serversResolver,omitempty" toml:"serversResolver,omitempty" yaml:"serversResolver,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Egress           *Egress           `description:"Restrict the destinations the servers are allowed to be dialed at." json:"egress,omitempty" toml:"egress,omitempty" yaml:"egress,omitempty" export:"true"`
 	EntryPoints      EntryPoints       `description:"Entry points definition." json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
 	Providers        *Providers        `description:"Providers configuration." json:"providers,omitempty" toml:"providers,omitempty" yaml:"providers,omitempty" export:"true"`
 
@@ -77,6 +79,16 @@ type Configuration struct {
 
 	CertificatesResolvers map[string]CertificateResolver `description:"Certificates resolvers configuration." json:"certificatesResolvers,omitempty" toml:"certificatesResolvers,omitempty" yaml:"certificatesResolvers,omitempty" export:"true"`
 
+	Locality *Locality `description:"Locality of the Traefik instance, for the zone-aware load balancing." json:"locality,omitempty" toml:"locality,omitempty" yaml:"locality,omitempty" export:"true"`
+
+	Preflight *Preflight `description:"Run the preflight checks before starting." json:"preflight,omitempty" toml:"preflight,omitempty" yaml:"preflight,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+
+	BandwidthAccounting *BandwidthAccounting `description:"Account the bytes transferred by the routers, per service and tenant, over time windows." json:"bandwidthAccounting,omitempty" toml:"bandwidthAccounting,omitempty" yaml:"bandwidthAccounting,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+
+	ClockSkew *ClockSkew `description:"Periodically check the system clock against the certificates validity, and an optional NTP server." json:"clockSkew,omitempty" toml:"clockSkew,omitempty" yaml:"clockSkew,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+
+	Probes map[string]*Probe `description:"Synthetic checks periodically sent through the entry points, to verify the routes end to end." json:"probes,omitempty" toml:"probes,omitempty" yaml:"probes,omitempty" export:"true"`
+
 	// Deprecated.
 	Pilot *Pilot `description:"Traefik Pilot configuration (Deprecated)." json:"pilot,omitempty" toml:"pilot,omitempty" yaml:"pilot,omitempty" export:"true"`
 
@@ -92,6 +104,104 @@ type CertificateResolver struct {
 type Global struct {
 	CheckNewVersion    bool `description:"Periodically check if a new version has been released." json:"checkNewVersion,omitempty" toml:"checkNewVersion,omitempty" yaml:"checkNewVersion,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	SendAnonymousUsage bool `description:"Periodically send anonymous usage statistics. If the option is not specified, it will be enabled by default." json:"sendAnonymousUsage,omitempty" toml:"sendAnonymousUsage,omitempty" yaml:"sendAnonymousUsage,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	FIPS               bool `description:"Restrict the TLS connections to the FIPS-approved parameters, gating the non-compliant TLS options." json:"fips,omitempty" toml:"fips,omitempty" yaml:"fips,omitempty" export:"true"`
+}
+
+// Locality holds the locality of the Traefik instance.
+type Locality struct {
+	Zone string `description:"Zone of the Traefik instance." json:"zone,omitempty" toml:"zone,omitempty" yaml:"zone,omitempty" export:"true"`
+}
+
+// Preflight holds the configuration of the checks run before starting.
+type Preflight struct {
+	MinOpenFiles uint64          `description:"Minimum limit of open files (file descriptors) of the process." json:"minOpenFiles,omitempty" toml:"minOpenFiles,omitempty" yaml:"minOpenFiles,omitempty" export:"true"`
+	Timeout      ptypes.Duration `description:"Timeout of the reachability checks of the providers." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (p *Preflight) SetDefaults() {
+	p.MinOpenFiles = 4096
+	p.Timeout = ptypes.Duration(5 * time.Second)
+}
+
+// BandwidthAccounting holds the configuration of the bandwidth accounting.
+type BandwidthAccounting struct {
+	Window    ptypes.Duration  `description:"Duration of the time windows the bytes are accounted over." json:"window,omitempty" toml:"window,omitempty" yaml:"window,omitempty" export:"true"`
+	Retention int              `description:"Number of time windows kept." json:"retention,omitempty" toml:"retention,omitempty" yaml:"retention,omitempty" export:"true"`
+	Report    *BandwidthReport `description:"Write the compression effectiveness report to a file periodically." json:"report,omitempty" toml:"report,omitempty" yaml:"report,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (b *BandwidthAccounting) SetDefaults() {
+	b.Window = ptypes.Duration(time.Hour)
+	b.Retention = 24
+}
+
+// BandwidthReport holds the configuration of the compression effectiveness report file.
+type BandwidthReport struct {
+	FilePath string          `description:"Path of the file the report is written to." json:"filePath,omitempty" toml:"filePath,omitempty" yaml:"filePath,omitempty" export:"true"`
+	Interval ptypes.Duration `description:"Interval between the writes of the report." json:"interval,omitempty" toml:"interval,omitempty" yaml:"interval,omitempty" export:"true"`
+	Windows  int             `description:"Number of last time windows aggregated in the report, all the kept ones if 0." json:"windows,omitempty" toml:"windows,omitempty" yaml:"windows,omitempty" export:"true"`
+	Top      int             `description:"Number of top talkers listed in the report." json:"top,omitempty" toml:"top,omitempty" yaml:"top,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (b *BandwidthReport) SetDefaults() {
+	b.Interval = ptypes.Duration(time.Hour)
+	b.Top = 10
+}
+
+// ClockSkew holds the configuration of the clock skew checks.
+type ClockSkew struct {
+	CheckInterval ptypes.Duration `description:"Interval between the checks." json:"checkInterval,omitempty" toml:"checkInterval,omitempty" yaml:"checkInterval,omitempty" export:"true"`
+	NTPServer     string          `description:"NTP server the system clock is compared to." json:"ntpServer,omitempty" toml:"ntpServer,omitempty" yaml:"ntpServer,omitempty" export:"true"`
+	MaxSkew       ptypes.Duration `description:"Maximum offset of the system clock from the NTP server before alerting." json:"maxSkew,omitempty" toml:"maxSkew,omitempty" yaml:"maxSkew,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *ClockSkew) SetDefaults() {
+	c.CheckInterval = ptypes.Duration(time.Hour)
+	c.MaxSkew = ptypes.Duration(10 * time.Second)
+}
+
+// Probe holds the configuration of a synthetic probe,
+// whose checks go through the routers, middlewares, and services of an entry point, like the requests of the clients.
+type Probe struct {
+	EntryPoint string          `description:"Entry point the checks are sent to." json:"entryPoint,omitempty" toml:"entryPoint,omitempty" yaml:"entryPoint,omitempty" export:"true"`
+	Interval   ptypes.Duration `description:"Interval between the checks." json:"interval,omitempty" toml:"interval,omitempty" yaml:"interval,omitempty" export:"true"`
+	Timeout    ptypes.Duration `description:"Timeout of a check." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+	HTTP       *HTTPProbe      `description:"Send an HTTP request." json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	TCP        *TCPProbe       `description:"Open a TCP connection." json:"tcp,omitempty" toml:"tcp,omitempty" yaml:"tcp,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (p *Probe) SetDefaults() {
+	p.Interval = ptypes.Duration(30 * time.Second)
+	p.Timeout = ptypes.Duration(5 * time.Second)
+}
+
+// HTTPProbe holds the configuration of the HTTP request of a synthetic probe.
+type HTTPProbe struct {
+	Method  string            `description:"Method of the request." json:"method,omitempty" toml:"method,omitempty" yaml:"method,omitempty" export:"true"`
+	Host    string            `description:"Host of the request, also used as the server name (SNI) over TLS." json:"host,omitempty" toml:"host,omitempty" yaml:"host,omitempty" export:"true"`
+	Path    string            `description:"Path of the request." json:"path,omitempty" toml:"path,omitempty" yaml:"path,omitempty" export:"true"`
+	Headers map[string]string `description:"Headers of the request." json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+	TLS     bool              `description:"Send the request over TLS, without verifying the certificate." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	Status  []string          `description:"Expected status codes of the response, as ranges." json:"status,omitempty" toml:"status,omitempty" yaml:"status,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (p *HTTPProbe) SetDefaults() {
+	p.Method = "GET"
+	p.Path = "/"
+	p.Status = []string{"200-399"}
+}
+
+// TCPProbe holds the configuration of the TCP connection of a synthetic probe.
+type TCPProbe struct {
+	ServerName string `description:"Server name (SNI) of the TLS connection. The connection is not encrypted when empty." json:"serverName,omitempty" toml:"serverName,omitempty" yaml:"serverName,omitempty" export:"true"`
+	Send       string `description:"Data sent once connected." json:"send,omitempty" toml:"send,omitempty" yaml:"send,omitempty" export:"true"`
+	Expect     string `description:"Expected start of the data received once connected." json:"expect,omitempty" toml:"expect,omitempty" yaml:"expect,omitempty" export:"true"`
 }
 
 // ServersTransport options to configure communication between Traefik and the servers.
@@ -110,6 +220,12 @@ type API struct {
 	DisableDashboardAd bool `description:"Disable ad in the dashboard." json:"disableDashboardAd,omitempty" toml:"disableDashboardAd,omitempty" yaml:"disableDashboardAd,omitempty" export:"true"`
 	// TODO: Re-enable statistics
 	// Statistics      *types.Statistics `description:"Enable more detailed statistics." json:"statistics,omitempty" toml:"statistics,omitempty" yaml:"statistics,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+
+	Maintenance *Maintenance `description:"Enable the endpoints putting routers in maintenance." json:"maintenance,omitempty" toml:"maintenance,omitempty" yaml:"maintenance,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Overrides   bool         `description:"Enable the endpoints overriding parameters of the middlewares at runtime." json:"overrides,omitempty" toml:"overrides,omitempty" yaml:"overrides,omitempty" export:"true"`
+	Snapshots   *Snapshots   `description:"Enable the endpoints exporting, importing, and pinning configuration snapshots." json:"snapshots,omitempty" toml:"snapshots,omitempty" yaml:"snapshots,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	ConnTrace   bool         `description:"Enable the endpoints tracing the TCP connections of given client IPs or server names." json:"connTrace,omitempty" toml:"connTrace,omitempty" yaml:"connTrace,omitempty" export:"true"`
+	Connections bool         `description:"Enable the endpoint listing the TCP connections, and whether their router was built from a previous configuration." json:"connections,omitempty" toml:"connections,omitempty" yaml:"connections,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -117,11 +233,34 @@ func (a *API) SetDefaults() {
 	a.Dashboard = true
 }
 
+// Maintenance holds the configuration of the routers put in maintenance through the API.
+type Maintenance struct {
+	StatusCode int    `description:"Status code of the responses to the requests on the HTTP routers in maintenance." json:"statusCode,omitempty" toml:"statusCode,omitempty" yaml:"statusCode,omitempty" export:"true"`
+	Body       string `description:"Body of the responses to the requests on the HTTP routers in maintenance." json:"body,omitempty" toml:"body,omitempty" yaml:"body,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (m *Maintenance) SetDefaults() {
+	m.StatusCode = 503
+	m.Body = "Under maintenance"
+}
+
+// Snapshots holds the configuration of the dynamic configuration snapshots kept for the API.
+type Snapshots struct {
+	MaxSnapshots int `description:"Maximum number of configuration snapshots kept in memory." json:"maxSnapshots,omitempty" toml:"maxSnapshots,omitempty" yaml:"maxSnapshots,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (s *Snapshots) SetDefaults() {
+	s.MaxSnapshots = 10
+}
+
 // RespondingTimeouts contains timeout configurations for incoming requests to the Traefik instance.
 type RespondingTimeouts struct {
-	ReadTimeout  ptypes.Duration `description:"ReadTimeout is the maximum duration for reading the entire request, including the body. If zero, no timeout is set." json:"readTimeout,omitempty" toml:"readTimeout,omitempty" yaml:"readTimeout,omitempty" export:"true"`
-	WriteTimeout ptypes.Duration `description:"WriteTimeout is the maximum duration before timing out writes of the response. If zero, no timeout is set." json:"writeTimeout,omitempty" toml:"writeTimeout,omitempty" yaml:"writeTimeout,omitempty" export:"true"`
-	IdleTimeout  ptypes.Duration `description:"IdleTimeout is the maximum amount duration an idle (keep-alive) connection will remain idle before closing itself. If zero, no timeout is set." json:"idleTimeout,omitempty" toml:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty" export:"true"`
+	ReadTimeout    ptypes.Duration `description:"ReadTimeout is the maximum duration for reading the entire request, including the body. If zero, no timeout is set." json:"readTimeout,omitempty" toml:"readTimeout,omitempty" yaml:"readTimeout,omitempty" export:"true"`
+	WriteTimeout   ptypes.Duration `description:"WriteTimeout is the maximum duration before timing out writes of the response. If zero, no timeout is set." json:"writeTimeout,omitempty" toml:"writeTimeout,omitempty" yaml:"writeTimeout,omitempty" export:"true"`
+	IdleTimeout    ptypes.Duration `description:"IdleTimeout is the maximum amount duration an idle (keep-alive) connection will remain idle before closing itself. If zero, no timeout is set." json:"idleTimeout,omitempty" toml:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty" export:"true"`
+	TCPIdleTimeout ptypes.Duration `description:"TCPIdleTimeout is the maximum duration a connection handled by a TCP router can remain without any byte read or written before it is closed. If zero, no timeout is set." json:"tcpIdleTimeout,omitempty" toml:"tcpIdleTimeout,omitempty" yaml:"tcpIdleTimeout,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -129,6 +268,30 @@ func (a *RespondingTimeouts) SetDefaults() {
 	a.IdleTimeout = ptypes.Duration(DefaultIdleTimeout)
 }
 
+// ServersResolver holds the configuration of the resolver of the hostnames of the servers.
+type ServersResolver struct {
+	Servers      []string        `description:"DNS servers, as host or host:port. Defaults to the servers of resolvConfig." json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" export:"true"`
+	ResolvConfig string          `description:"resolv.conf providing the search domains, and the default DNS servers." json:"resolvConfig,omitempty" toml:"resolvConfig,omitempty" yaml:"resolvConfig,omitempty" export:"true"`
+	Timeout      ptypes.Duration `description:"Timeout of each DNS query." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+	MinTTL       ptypes.Duration `description:"Minimum duration the addresses are cached for, whatever the TTL of their records." json:"minTTL,omitempty" toml:"minTTL,omitempty" yaml:"minTTL,omitempty" export:"true"`
+	MaxTTL       ptypes.Duration `description:"Maximum duration the addresses are cached for, whatever the TTL of their records." json:"maxTTL,omitempty" toml:"maxTTL,omitempty" yaml:"maxTTL,omitempty" export:"true"`
+	NegativeTTL  ptypes.Duration `description:"Duration the failed lookups are cached for." json:"negativeTTL,omitempty" toml:"negativeTTL,omitempty" yaml:"negativeTTL,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (r *ServersResolver) SetDefaults() {
+	r.ResolvConfig = "/etc/resolv.conf"
+	r.Timeout = ptypes.Duration(2 * time.Second)
+	r.MinTTL = ptypes.Duration(5 * time.Second)
+	r.MaxTTL = ptypes.Duration(5 * time.Minute)
+	r.NegativeTTL = ptypes.Duration(5 * time.Second)
+}
+
+// Egress holds the egress policy, restricting the destinations the servers are allowed to be dialed at.
+type Egress struct {
+	Allow []string `description:"Allowed destinations, as CIDR, optionally followed by a port or a range of ports (e.g. 10.0.0.0/8:8000-8999)." json:"allow,omitempty" toml:"allow,omitempty" yaml:"allow,omitempty" export:"true"`
+}
+
 // ForwardingTimeouts contains timeout configurations for forwarding requests to the backend servers.
 type ForwardingTimeouts struct {
 	DialTimeout           ptypes.Duration `description:"The amount of time to wait until a connection to a backend server can be established. If zero, no timeout exists." json:"dialTimeout,omitempty" toml:"dialTimeout,omitempty" yaml:"dialTimeout,omitempty" export:"true"`
@@ -173,7 +336,9 @@ func (t *Tracing) SetDefaults() {
 
 // Providers contains providers configuration.
 type Providers struct {
-	ProvidersThrottleDuration ptypes.Duration `description:"Backends throttle duration: minimum duration between 2 events from providers before applying a new configuration. It avoids unnecessary reloads if multiples events are sent in a short amount of time." json:"providersThrottleDuration,omitempty" toml:"providersThrottleDuration,omitempty" yaml:"providersThrottleDuration,omitempty" export:"true"`
+	ProvidersThrottleDuration ptypes.Duration    `description:"Backends throttle duration: minimum duration between 2 events from providers before applying a new configuration. It avoids unnecessary reloads if multiples events are sent in a short amount of time." json:"providersThrottleDuration,omitempty" toml:"providersThrottleDuration,omitempty" yaml:"providersThrottleDuration,omitempty" export:"true"`
+	Cache                     *ProvidersCache    `description:"Cache the configurations of the providers to a file, served on restart while the providers reconnect." json:"cache,omitempty" toml:"cache,omitempty" yaml:"cache,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Debounce                  *ProvidersDebounce `description:"Debounce the configuration updates of the providers, coalescing their bursts into one update." json:"debounce,omitempty" toml:"debounce,omitempty" yaml:"debounce,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 
 	Docker            *docker.Provider               `description:"Enable Docker backend with default settings." json:"docker,omitempty" toml:"docker,omitempty" yaml:"docker,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	File              *file.Provider                 `description:"Enable File backend with default settings." json:"file,omitempty" toml:"file,omitempty" yaml:"file,omitempty" export:"true"`
@@ -196,6 +361,30 @@ type Providers struct {
 	Plugin map[string]PluginConf `description:"Plugins configuration." json:"plugin,omitempty" toml:"plugin,omitempty" yaml:"plugin,omitempty"`
 }
 
+// ProvidersCache holds the configuration of the cache of the configurations of the providers.
+type ProvidersCache struct {
+	FilePath     string          `description:"File the configurations of the providers are cached to." json:"filePath,omitempty" toml:"filePath,omitempty" yaml:"filePath,omitempty" export:"true"`
+	StaleTimeout ptypes.Duration `description:"Duration the cached configuration of a provider is served for at most, until the provider provides its own." json:"staleTimeout,omitempty" toml:"staleTimeout,omitempty" yaml:"staleTimeout,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *ProvidersCache) SetDefaults() {
+	c.FilePath = "providers-cache.json"
+	c.StaleTimeout = ptypes.Duration(time.Minute)
+}
+
+// ProvidersDebounce holds the configuration of the debouncing of the configuration updates of the providers.
+type ProvidersDebounce struct {
+	Delay        ptypes.Duration `description:"Duration without any new configuration update from a provider, before its last update is applied." json:"delay,omitempty" toml:"delay,omitempty" yaml:"delay,omitempty" export:"true"`
+	MaxStaleness ptypes.Duration `description:"Duration a configuration update from a provider is delayed for at most, while new updates keep coming." json:"maxStaleness,omitempty" toml:"maxStaleness,omitempty" yaml:"maxStaleness,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (d *ProvidersDebounce) SetDefaults() {
+	d.Delay = ptypes.Duration(500 * time.Millisecond)
+	d.MaxStaleness = ptypes.Duration(5 * time.Second)
+}
+
 // SetEffectiveConfiguration adds missing configuration parameters derived from existing ones.
 // It also takes care of maintaining backwards compatibility.
 func (c *Configuration) SetEffectiveConfiguration() {
@@ -262,7 +451,13 @@ func (c *Configuration) SetEffectiveConfiguration() {
 		log.WithoutContext().Debugf("Experimental Kubernetes Gateway provider has been activated")
 		entryPoints := make(map[string]gateway.Entrypoint)
 		for epName, entryPoint := range c.EntryPoints {
-			entryPoints[epName] = gateway.Entrypoint{Address: entryPoint.GetAddress(), HasHTTPTLSConf: entryPoint.HTTP.TLS != nil}
+			protocol, err := entryPoint.GetProtocol()
+			if err != nil {
+				log.WithoutContext().Errorf("Invalid protocol of entryPoint %s: %v", epName, err)
+				continue
+			}
+
+			entryPoints[epName] = gateway.Entrypoint{Address: entryPoint.GetAddress(), HasHTTPTLSConf: entryPoint.HTTP.TLS != nil, Protocol: protocol}
 		}
 
 		c.Providers.KubernetesGateway.EntryPoints = entryPoints
@@ -315,6 +510,20 @@ func (c *Configuration) ValidateConfiguration() error {
 		return fmt.Errorf("Nomad provider cannot have both namespace and namespaces options configured")
 	}
 
+	for name, probe := range c.Probes {
+		if _, ok := c.EntryPoints[probe.EntryPoint]; !ok {
+			return fmt.Errorf("unable to initialize probe %q with the unknown entry point %q", name, probe.EntryPoint)
+		}
+
+		if (probe.HTTP == nil) == (probe.TCP == nil) {
+			return fmt.Errorf("unable to initialize probe %q, exactly one of http and tcp must be defined", name)
+		}
+	}
+
+	if c.BandwidthAccounting != nil && c.BandwidthAccounting.Report != nil && c.BandwidthAccounting.Report.FilePath == "" {
+		return fmt.Errorf("unable to initialize the bandwidth report with no file path")
+	}
+
 	return nil
 }
 
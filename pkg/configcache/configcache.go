@@ -0,0 +1,86 @@
+package configcache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// cacheFile is the content of the cache file.
+type cacheFile struct {
+	SavedAt        time.Time              `json:"savedAt"`
+	Configurations dynamic.Configurations `json:"configurations"`
+}
+
+// Cache persists the configurations of the providers to a file,
+// for a restarting instance to serve them while its providers reconnect.
+type Cache struct {
+	filePath     string
+	staleTimeout time.Duration
+}
+
+// New creates a new Cache persisting the configurations to the given file.
+// The cached configuration of a provider is served for staleTimeout at most, until the provider provides its own.
+func New(filePath string, staleTimeout time.Duration) *Cache {
+	return &Cache{filePath: filePath, staleTimeout: staleTimeout}
+}
+
+// StaleTimeout returns the duration the cached configuration of a provider is served for,
+// until the provider provides its own.
+func (c *Cache) StaleTimeout() time.Duration {
+	return c.staleTimeout
+}
+
+// Load returns the configurations of the providers saved in the cache file, if any.
+func (c *Cache) Load() (dynamic.Configurations, time.Time, error) {
+	data, err := os.ReadFile(c.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("reading configuration cache: %w", err)
+	}
+
+	var file cacheFile
+	if err = json.Unmarshal(data, &file); err != nil {
+		return nil, time.Time{}, fmt.Errorf("decoding configuration cache: %w", err)
+	}
+
+	return file.Configurations, file.SavedAt, nil
+}
+
+// Save saves the given configurations of the providers to the cache file.
+// The file is replaced atomically, so that it is never read partially written.
+func (c *Cache) Save(confs dynamic.Configurations) error {
+	data, err := json.Marshal(cacheFile{SavedAt: time.Now(), Configurations: confs})
+	if err != nil {
+		return fmt.Errorf("encoding configuration cache: %w", err)
+	}
+
+	// The file is only readable by its owner, as the configurations may hold secrets, such as the private keys of the certificates.
+	tmp, err := os.CreateTemp(filepath.Dir(c.filePath), filepath.Base(c.filePath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating configuration cache: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing configuration cache: %w", err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("writing configuration cache: %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), c.filePath); err != nil {
+		return fmt.Errorf("replacing configuration cache: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package configcache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestCache(t *testing.T) {
+	cache := New(filepath.Join(t.TempDir(), "cache.json"), time.Minute)
+
+	confs, _, err := cache.Load()
+	require.NoError(t, err)
+	assert.Empty(t, confs)
+
+	expected := dynamic.Configurations{
+		"file": &dynamic.Configuration{
+			HTTP: &dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{
+					"foo": {
+						Rule:    "Host(`foo.localhost`)",
+						Service: "foo",
+						ResponseTimeouts: &dynamic.ResponseTimeouts{
+							Timeout: ptypes.Duration(10 * time.Second),
+						},
+					},
+				},
+				Services: map[string]*dynamic.Service{
+					"foo": {
+						LoadBalancer: &dynamic.ServersLoadBalancer{
+							Servers: []dynamic.Server{{URL: "http://127.0.0.1:8080"}},
+						},
+					},
+				},
+			},
+			TCP: &dynamic.TCPConfiguration{
+				Routers: map[string]*dynamic.TCPRouter{
+					"bar": {Rule: "HostSNI(`*`)", Service: "bar"},
+				},
+			},
+		},
+	}
+
+	before := time.Now()
+	require.NoError(t, cache.Save(expected))
+
+	info, err := os.Stat(cache.filePath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	confs, savedAt, err := cache.Load()
+	require.NoError(t, err)
+	assert.Equal(t, expected, confs)
+	assert.False(t, savedAt.Before(before.Truncate(time.Second)))
+}
+
+func TestCache_invalid(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "cache.json")
+	require.NoError(t, os.WriteFile(filePath, []byte("{"), 0o600))
+
+	_, _, err := New(filePath, time.Minute).Load()
+	assert.Error(t, err)
+}
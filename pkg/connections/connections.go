@@ -0,0 +1,138 @@
+package connections
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+// Connection is a TCP connection handled by a router.
+type Connection struct {
+	ID         uint64    `json:"id"`
+	Router     string    `json:"router"`
+	Service    string    `json:"service"`
+	ClientAddr string    `json:"clientAddr"`
+	Since      time.Time `json:"since"`
+	// Generation is the generation of the configuration the router handling the connection was built from.
+	Generation uint64 `json:"generation"`
+	// Stale reports whether the router handling the connection was built from a previous configuration.
+	Stale bool `json:"stale"`
+}
+
+// Registry records the TCP connections handled by the routers, and the generation of the configuration
+// their router was built from.
+// As a configuration reload only switches the routers of the new connections,
+// the established connections keep their router, middlewares, and server, until they are closed.
+type Registry struct {
+	mu          sync.Mutex
+	generation  uint64
+	lastID      uint64
+	connections map[uint64]*Connection
+}
+
+// NewRegistry creates a new Registry.
+func NewRegistry() *Registry {
+	return &Registry{connections: make(map[uint64]*Connection)}
+}
+
+// NextGeneration must be called before building the routers of a new configuration.
+func (r *Registry) NextGeneration() {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+}
+
+// Generation returns the generation of the last configuration.
+func (r *Registry) Generation() uint64 {
+	if r == nil {
+		return 0
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.generation
+}
+
+// List returns the open connections, from the oldest to the newest.
+func (r *Registry) List() []Connection {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]Connection, 0, len(r.connections))
+	for _, conn := range r.connections {
+		c := *conn
+		c.Stale = c.Generation < r.generation
+		result = append(result, c)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result
+}
+
+func (r *Registry) add(conn Connection) uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	conn.ID = r.lastID
+	r.connections[conn.ID] = &conn
+
+	return conn.ID
+}
+
+func (r *Registry) remove(id uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.connections, id)
+}
+
+// handler records the connections going through a router in the registry, as long as they are handled.
+type handler struct {
+	registry   *Registry
+	next       tcp.Handler
+	router     string
+	service    string
+	generation uint64
+}
+
+// NewHandler creates a handler recording the connections handled by the given router in the registry,
+// with the generation of the configuration the router is being built from.
+func NewHandler(registry *Registry, routerName, serviceName string, next tcp.Handler) tcp.Handler {
+	return &handler{
+		registry:   registry,
+		next:       next,
+		router:     routerName,
+		service:    serviceName,
+		generation: registry.Generation(),
+	}
+}
+
+// ServeTCP serves the given TCP connection.
+func (h *handler) ServeTCP(conn tcp.WriteCloser) {
+	id := h.registry.add(Connection{
+		Router:     h.router,
+		Service:    h.service,
+		ClientAddr: conn.RemoteAddr().String(),
+		Since:      time.Now(),
+		Generation: h.generation,
+	})
+	defer h.registry.remove(id)
+
+	h.next.ServeTCP(conn)
+}
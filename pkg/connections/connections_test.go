@@ -0,0 +1,82 @@
+package connections
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestHandler(t *testing.T) {
+	registry := NewRegistry()
+	registry.NextGeneration()
+
+	served := make(chan struct{})
+	release := make(chan struct{})
+	handler := NewHandler(registry, "foo@file", "bar@file", tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		close(served)
+		<-release
+	}))
+
+	// The routers of the next configuration are built while the connection is handled.
+	registry.NextGeneration()
+
+	_, server := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		handler.ServeTCP(fakeConn{Conn: server})
+		close(done)
+	}()
+
+	<-served
+
+	conns := registry.List()
+	require.Len(t, conns, 1)
+	assert.Equal(t, "foo@file", conns[0].Router)
+	assert.Equal(t, "bar@file", conns[0].Service)
+	assert.Equal(t, uint64(1), conns[0].Generation)
+	assert.True(t, conns[0].Stale)
+
+	close(release)
+	<-done
+
+	assert.Empty(t, registry.List())
+}
+
+func TestRegistry_List(t *testing.T) {
+	registry := NewRegistry()
+	registry.NextGeneration()
+
+	first := registry.add(Connection{Router: "foo@file", Generation: registry.Generation()})
+	registry.NextGeneration()
+	registry.add(Connection{Router: "foo@file", Generation: registry.Generation()})
+
+	conns := registry.List()
+	require.Len(t, conns, 2)
+	assert.True(t, conns[0].Stale)
+	assert.False(t, conns[1].Stale)
+
+	registry.remove(first)
+
+	conns = registry.List()
+	require.Len(t, conns, 1)
+	assert.Equal(t, uint64(2), conns[0].Generation)
+}
+
+func TestRegistry_nil(t *testing.T) {
+	var registry *Registry
+
+	registry.NextGeneration()
+	assert.Zero(t, registry.Generation())
+	assert.Nil(t, registry.List())
+}
+
+type fakeConn struct {
+	net.Conn
+}
+
+func (f fakeConn) CloseWrite() error {
+	return nil
+}
@@ -0,0 +1,230 @@
+package conntrace
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+// The kinds of filters selecting the traced connections.
+const (
+	FilterClientIP   = "clientIP"
+	FilterServerName = "serverName"
+)
+
+// Filters holds the client IPs and the server names (SNI) whose TCP connections are traced,
+// as set at runtime through the API.
+// The traces are logged at the info level, so that they do not require the debug logs to be enabled globally.
+type Filters struct {
+	mu      sync.RWMutex
+	filters map[string]map[string]struct{}
+	// count is the number of filters set, checked on every connection before taking the lock.
+	count atomic.Int64
+
+	lastID atomic.Uint64
+}
+
+// NewFilters creates a new Filters.
+func NewFilters() *Filters {
+	return &Filters{
+		filters: make(map[string]map[string]struct{}),
+	}
+}
+
+// Set starts or stops tracing the connections matching the given filter.
+func (f *Filters) Set(kind, value string, enabled bool) {
+	if f == nil {
+		return
+	}
+
+	if kind == FilterServerName {
+		value = strings.ToLower(value)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, exists := f.filters[kind][value]
+
+	switch {
+	case enabled && !exists:
+		if f.filters[kind] == nil {
+			f.filters[kind] = make(map[string]struct{})
+		}
+		f.filters[kind][value] = struct{}{}
+		f.count.Add(1)
+
+	case !enabled && exists:
+		delete(f.filters[kind], value)
+		f.count.Add(-1)
+	}
+}
+
+// Match reports whether the connection of the given client IP, with the given server name, is traced.
+func (f *Filters) Match(clientIP, serverName string) bool {
+	if f == nil || f.count.Load() == 0 {
+		return false
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if _, ok := f.filters[FilterClientIP][clientIP]; ok && clientIP != "" {
+		return true
+	}
+
+	_, ok := f.filters[FilterServerName][strings.ToLower(serverName)]
+	return ok && serverName != ""
+}
+
+// List returns the sorted values of the filters of the given kind.
+func (f *Filters) List(kind string) []string {
+	if f == nil {
+		return nil
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	values := make([]string, 0, len(f.filters[kind]))
+	for value := range f.filters[kind] {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	return values
+}
+
+// Trace returns the connection wrapped to be traced if it matches the filters, or the connection itself otherwise.
+func (f *Filters) Trace(conn tcp.WriteCloser, serverName string) tcp.WriteCloser {
+	if f == nil || f.count.Load() == 0 {
+		return conn
+	}
+
+	var clientIP string
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		clientIP = addr.IP.String()
+	} else if host, _, err := net.SplitHostPort(conn.RemoteAddr().String()); err == nil {
+		clientIP = host
+	}
+
+	if !f.Match(clientIP, serverName) {
+		return conn
+	}
+
+	c := &Conn{
+		WriteCloser: conn,
+		logger: log.WithoutContext().WithFields(logrus.Fields{
+			"connID":     f.lastID.Add(1),
+			"clientIP":   clientIP,
+			"serverName": serverName,
+		}),
+	}
+
+	c.logger.Info("Tracing connection")
+
+	return c
+}
+
+// Conn is a traced connection, logging the bytes read from and written to the client,
+// and the flushes of the data to the client.
+type Conn struct {
+	tcp.WriteCloser
+
+	logger logrus.FieldLogger
+
+	read    atomic.Int64
+	written atomic.Int64
+
+	closeOnce sync.Once
+}
+
+func (c *Conn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+	c.read.Add(int64(n))
+	return n, err
+}
+
+func (c *Conn) Write(p []byte) (int, error) {
+	n, err := c.WriteCloser.Write(p)
+	written := c.written.Add(int64(n))
+
+	c.logger.Infof("Flushed %d bytes to the client (%d bytes read, %d bytes written)", n, c.read.Load(), written)
+
+	return n, err
+}
+
+// CloseWrite half-closes the connection to the client.
+func (c *Conn) CloseWrite() error {
+	c.logger.Infof("Closing the connection for writing (%d bytes read, %d bytes written)", c.read.Load(), c.written.Load())
+
+	return c.WriteCloser.CloseWrite()
+}
+
+// Close closes the connection to the client.
+func (c *Conn) Close() error {
+	c.closeOnce.Do(func() {
+		c.logger.Infof("Closing the connection (%d bytes read, %d bytes written)", c.read.Load(), c.written.Load())
+	})
+
+	return c.WriteCloser.Close()
+}
+
+// NetConn returns the client connection.
+func (c *Conn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+// Lookup returns the traced connection wrapped by conn, if any,
+// looking through the wrappers exposing the connection they wrap with a NetConn method, as tls.Conn does.
+func Lookup(conn net.Conn) (*Conn, bool) {
+	for conn != nil {
+		if traced, ok := conn.(*Conn); ok {
+			return traced, true
+		}
+
+		wrapper, ok := conn.(interface{ NetConn() net.Conn })
+		if !ok {
+			break
+		}
+
+		conn = wrapper.NetConn()
+	}
+
+	return nil, false
+}
+
+// WrapConstructor wraps the handlers built by the given constructor, to log when the traced connections enter and leave them.
+func WrapConstructor(name string, constructor tcp.Constructor) tcp.Constructor {
+	return func(next tcp.Handler) (tcp.Handler, error) {
+		handler, err := constructor(next)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewHandler(name, handler), nil
+	}
+}
+
+// NewHandler wraps the given handler, to log when the traced connections enter and leave it.
+func NewHandler(name string, next tcp.Handler) tcp.Handler {
+	return tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		traced, ok := Lookup(conn)
+		if !ok {
+			next.ServeTCP(conn)
+			return
+		}
+
+		traced.logger.Infof("Entering %s (%d bytes read, %d bytes written)", name, traced.read.Load(), traced.written.Load())
+
+		next.ServeTCP(conn)
+
+		traced.logger.Infof("Leaving %s (%d bytes read, %d bytes written)", name, traced.read.Load(), traced.written.Load())
+	})
+}
@@ -0,0 +1,107 @@
+package conntrace
+
+import (
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestFilters(t *testing.T) {
+	filters := NewFilters()
+	assert.False(t, filters.Match("10.0.0.1", "foo.localhost"))
+
+	filters.Set(FilterClientIP, "10.0.0.1", true)
+	filters.Set(FilterServerName, "Foo.localhost", true)
+	filters.Set(FilterServerName, "foo.localhost", true)
+
+	assert.True(t, filters.Match("10.0.0.1", ""))
+	assert.True(t, filters.Match("10.0.0.2", "FOO.localhost"))
+	assert.False(t, filters.Match("10.0.0.2", "bar.localhost"))
+	assert.False(t, filters.Match("", ""))
+
+	assert.Equal(t, []string{"10.0.0.1"}, filters.List(FilterClientIP))
+	assert.Equal(t, []string{"foo.localhost"}, filters.List(FilterServerName))
+
+	filters.Set(FilterClientIP, "10.0.0.1", false)
+	filters.Set(FilterClientIP, "10.0.0.1", false)
+	filters.Set(FilterServerName, "foo.localhost", false)
+
+	assert.False(t, filters.Match("10.0.0.1", "foo.localhost"))
+	assert.Empty(t, filters.List(FilterClientIP))
+	assert.Zero(t, filters.count.Load())
+}
+
+func TestFilters_nil(t *testing.T) {
+	var filters *Filters
+
+	filters.Set(FilterClientIP, "10.0.0.1", true)
+	assert.False(t, filters.Match("10.0.0.1", ""))
+	assert.Nil(t, filters.List(FilterClientIP))
+
+	_, server := net.Pipe()
+	conn := fakeConn{Conn: server}
+	assert.Equal(t, tcp.WriteCloser(conn), filters.Trace(conn, "foo.localhost"))
+}
+
+func TestTrace(t *testing.T) {
+	filters := NewFilters()
+	filters.Set(FilterServerName, "foo.localhost", true)
+
+	client, server := net.Pipe()
+
+	// The connections not matching the filters are not traced.
+	conn := filters.Trace(fakeConn{Conn: server}, "bar.localhost")
+	_, ok := Lookup(conn)
+	assert.False(t, ok)
+
+	conn = filters.Trace(fakeConn{Conn: server}, "foo.localhost")
+
+	var entered bool
+	handler := NewHandler("middleware foo@file", tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		entered = true
+
+		// The traced connection is found through the wrappers.
+		traced, ok := Lookup(conn)
+		require.True(t, ok)
+
+		buf := make([]byte, 5)
+		_, err := io.ReadFull(conn, buf)
+		require.NoError(t, err)
+
+		_, err = conn.Write([]byte("world!"))
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(5), traced.read.Load())
+		assert.Equal(t, int64(6), traced.written.Load())
+
+		require.NoError(t, conn.Close())
+	}))
+
+	go func() {
+		_, _ = client.Write([]byte("hello"))
+		_, _ = io.ReadAll(client)
+	}()
+
+	handler.ServeTCP(&wrapperConn{WriteCloser: conn})
+	assert.True(t, entered)
+}
+
+type wrapperConn struct {
+	tcp.WriteCloser
+}
+
+func (c *wrapperConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+type fakeConn struct {
+	net.Conn
+}
+
+func (f fakeConn) CloseWrite() error {
+	return nil
+}
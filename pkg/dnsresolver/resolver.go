@@ -0,0 +1,330 @@
+package dnsresolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/miekg/dns"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+// Results of the lookups, as recorded by the metrics.
+const (
+	resultHit         = "hit"
+	resultNegativeHit = "negative_hit"
+	resultMiss        = "miss"
+	resultStale       = "stale"
+	resultError       = "error"
+)
+
+// Options configures a Resolver.
+type Options struct {
+	// Servers are the DNS servers, as host or host:port.
+	// When empty, the servers of the ResolvConfig file are used.
+	Servers []string
+	// ResolvConfig is the resolv.conf file providing the search domains, and the default servers.
+	ResolvConfig string
+	// Timeout is the timeout of each DNS query.
+	Timeout time.Duration
+	// MinTTL and MaxTTL clamp the TTL of the records the addresses are cached for.
+	MinTTL time.Duration
+	MaxTTL time.Duration
+	// NegativeTTL is how long the failed lookups are cached for.
+	NegativeTTL time.Duration
+}
+
+// Resolver looks the hostnames of the servers up with its own DNS servers and cache,
+// instead of the resolver of the operating system, which stalls the dials for seconds when a DNS server does not answer.
+// The concurrent lookups of a hostname are done once,
+// and the addresses of a hostname keep being used when their refresh fails.
+type Resolver struct {
+	servers     []string
+	config      *dns.ClientConfig
+	client      *dns.Client
+	minTTL      time.Duration
+	maxTTL      time.Duration
+	negativeTTL time.Duration
+	lookups     gokitmetrics.Counter
+
+	mu      sync.Mutex
+	entries map[string]*entry
+
+	// now is used to shift the clock in tests.
+	now func() time.Time
+}
+
+// entry is the result of the lookup of a hostname.
+type entry struct {
+	// ready is closed once the lookup is done.
+	ready   chan struct{}
+	ips     []net.IP
+	err     error
+	expires time.Time
+}
+
+// New creates a new Resolver.
+func New(registry metrics.Registry, opts Options) (*Resolver, error) {
+	if registry == nil {
+		registry = metrics.NewVoidRegistry()
+	}
+
+	config := &dns.ClientConfig{Ndots: 1, Port: "53"}
+	if opts.ResolvConfig != "" {
+		conf, err := dns.ClientConfigFromFile(opts.ResolvConfig)
+		switch {
+		case err == nil:
+			config = conf
+		case len(opts.Servers) == 0:
+			return nil, fmt.Errorf("reading resolver configuration %s: %w", opts.ResolvConfig, err)
+		}
+	}
+
+	var servers []string
+	for _, server := range opts.Servers {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		servers = append(servers, server)
+	}
+
+	if len(servers) == 0 {
+		for _, server := range config.Servers {
+			servers = append(servers, net.JoinHostPort(server, config.Port))
+		}
+	}
+
+	if len(servers) == 0 {
+		return nil, errors.New("no DNS server configured")
+	}
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+
+	return &Resolver{
+		servers:     servers,
+		config:      config,
+		client:      &dns.Client{Timeout: timeout},
+		minTTL:      opts.MinTTL,
+		maxTTL:      opts.MaxTTL,
+		negativeTTL: opts.NegativeTTL,
+		lookups:     registry.ResolverLookupsCounter(),
+		entries:     make(map[string]*entry),
+		now:         time.Now,
+	}, nil
+}
+
+// DialFunc is a function dialing an address on a network, as net.Dialer.DialContext.
+type DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// DialContext wraps the dial function to look the hostnames up with the resolver,
+// and dial their addresses in turn until one succeeds.
+// A nil resolver leaves the dial function as is.
+func (r *Resolver) DialContext(dial DialFunc) DialFunc {
+	if r == nil {
+		return dial
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || host == "" || net.ParseIP(host) != nil {
+			return dial(ctx, network, addr)
+		}
+
+		ips, err := r.LookupIP(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var dialErr error
+		for _, ip := range ips {
+			if (network == "tcp4" || network == "udp4") && ip.To4() == nil ||
+				(network == "tcp6" || network == "udp6") && ip.To4() != nil {
+				continue
+			}
+
+			conn, err := dial(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			dialErr = err
+		}
+
+		if dialErr == nil {
+			dialErr = fmt.Errorf("no suitable address found for %s", host)
+		}
+
+		return nil, dialErr
+	}
+}
+
+// LookupIP returns the IP addresses of the hostname, from the cache if they did not expire.
+func (r *Resolver) LookupIP(ctx context.Context, host string) ([]net.IP, error) {
+	r.mu.Lock()
+
+	cached, ok := r.entries[host]
+	if ok {
+		select {
+		case <-cached.ready:
+			if r.now().Before(cached.expires) {
+				r.mu.Unlock()
+
+				if cached.err != nil {
+					r.lookups.With("result", resultNegativeHit).Add(1)
+				} else {
+					r.lookups.With("result", resultHit).Add(1)
+				}
+
+				return cached.ips, cached.err
+			}
+		default:
+			// The hostname is being looked up.
+			r.mu.Unlock()
+
+			select {
+			case <-cached.ready:
+				r.lookups.With("result", resultHit).Add(1)
+				return cached.ips, cached.err
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	current := &entry{ready: make(chan struct{})}
+	r.entries[host] = current
+	r.mu.Unlock()
+
+	// The lookup is shared with the concurrent callers, so it is not canceled with the context of the first one.
+	ips, ttl, err := r.resolve(context.WithoutCancel(ctx), host)
+	switch {
+	case err == nil:
+		r.lookups.With("result", resultMiss).Add(1)
+
+		current.ips = ips
+		current.expires = r.now().Add(r.clamp(ttl))
+
+	case cached != nil && cached.err == nil:
+		r.lookups.With("result", resultStale).Add(1)
+		log.FromContext(ctx).Debugf("Using the previous addresses of %s, since its lookup failed: %v", host, err)
+
+		current.ips = cached.ips
+		current.expires = r.now().Add(r.negativeTTL)
+
+	default:
+		r.lookups.With("result", resultError).Add(1)
+
+		current.err = err
+		current.expires = r.now().Add(r.negativeTTL)
+	}
+
+	close(current.ready)
+
+	return current.ips, current.err
+}
+
+// clamp clamps the TTL of the records between the minimum and maximum TTLs.
+func (r *Resolver) clamp(ttl time.Duration) time.Duration {
+	if ttl < r.minTTL {
+		return r.minTTL
+	}
+
+	if r.maxTTL > 0 && ttl > r.maxTTL {
+		return r.maxTTL
+	}
+
+	return ttl
+}
+
+// resolve looks the hostname up, through the search domains,
+// and returns its IP addresses along with the lowest TTL of their records.
+func (r *Resolver) resolve(ctx context.Context, host string) ([]net.IP, time.Duration, error) {
+	var lookupErr error
+	for _, name := range r.config.NameList(host) {
+		ips, ttl, err := r.resolveName(ctx, name)
+		if err != nil {
+			if lookupErr == nil {
+				lookupErr = err
+			}
+			continue
+		}
+
+		if len(ips) > 0 {
+			return ips, ttl, nil
+		}
+	}
+
+	if lookupErr == nil {
+		lookupErr = &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	return nil, 0, lookupErr
+}
+
+// resolveName returns the A and AAAA records of the fully qualified name, along with their lowest TTL.
+func (r *Resolver) resolveName(ctx context.Context, name string) ([]net.IP, time.Duration, error) {
+	var ips []net.IP
+	var ttl time.Duration
+
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		msg := &dns.Msg{}
+		msg.SetQuestion(name, qtype)
+
+		resp, err := r.exchange(ctx, msg)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if resp.Rcode == dns.RcodeNameError {
+			return nil, 0, nil
+		}
+
+		for _, answer := range resp.Answer {
+			var ip net.IP
+			switch rr := answer.(type) {
+			case *dns.A:
+				ip = rr.A
+			case *dns.AAAA:
+				ip = rr.AAAA
+			default:
+				continue
+			}
+
+			recordTTL := time.Duration(answer.Header().Ttl) * time.Second
+			if len(ips) == 0 || recordTTL < ttl {
+				ttl = recordTTL
+			}
+			ips = append(ips, ip)
+		}
+	}
+
+	return ips, ttl, nil
+}
+
+// exchange sends the query to the DNS servers in turn, until one of them answers.
+func (r *Resolver) exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+	var err error
+	for _, server := range r.servers {
+		var resp *dns.Msg
+		resp, _, err = r.client.ExchangeContext(ctx, msg, server)
+		if err != nil {
+			continue
+		}
+
+		if resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError {
+			err = fmt.Errorf("DNS server %s answered %s for %s", server, dns.RcodeToString[resp.Rcode], msg.Question[0].Name)
+			continue
+		}
+
+		return resp, nil
+	}
+
+	return nil, err
+}
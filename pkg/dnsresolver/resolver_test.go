@@ -0,0 +1,141 @@
+package dnsresolver
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_LookupIP(t *testing.T) {
+	var queries atomic.Int64
+	var failing atomic.Bool
+	server := startDNSServer(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		queries.Add(1)
+
+		resp := &dns.Msg{}
+		resp.SetReply(req)
+
+		switch {
+		case failing.Load():
+			resp.Rcode = dns.RcodeServerFailure
+		case req.Question[0].Name == "foo.localhost." && req.Question[0].Qtype == dns.TypeA:
+			resp.Answer = append(resp.Answer, &dns.A{
+				Hdr: dns.RR_Header{Name: req.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 1},
+				A:   net.ParseIP("10.0.0.1"),
+			})
+		case req.Question[0].Name != "foo.localhost.":
+			resp.Rcode = dns.RcodeNameError
+		}
+
+		_ = w.WriteMsg(resp)
+	})
+
+	resolver, err := New(nil, Options{
+		Servers:     []string{server},
+		MinTTL:      10 * time.Second,
+		MaxTTL:      time.Minute,
+		NegativeTTL: 5 * time.Second,
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	resolver.now = func() time.Time { return now }
+
+	ips, err := resolver.LookupIP(context.Background(), "foo.localhost")
+	require.NoError(t, err)
+	assert.Equal(t, []net.IP{net.ParseIP("10.0.0.1").To4()}, ips)
+	assert.Equal(t, int64(2), queries.Load())
+
+	// The TTL of the record is clamped to the minimum TTL.
+	now = now.Add(5 * time.Second)
+	_, err = resolver.LookupIP(context.Background(), "foo.localhost")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), queries.Load())
+
+	// The failed lookups are cached.
+	_, err = resolver.LookupIP(context.Background(), "bar.localhost")
+	var dnsErr *net.DNSError
+	require.ErrorAs(t, err, &dnsErr)
+	assert.True(t, dnsErr.IsNotFound)
+	assert.Equal(t, int64(3), queries.Load())
+
+	_, err = resolver.LookupIP(context.Background(), "bar.localhost")
+	require.Error(t, err)
+	assert.Equal(t, int64(3), queries.Load())
+
+	// The previous addresses are used when the refresh fails.
+	failing.Store(true)
+	now = now.Add(10 * time.Second)
+
+	ips, err = resolver.LookupIP(context.Background(), "foo.localhost")
+	require.NoError(t, err)
+	assert.Equal(t, []net.IP{net.ParseIP("10.0.0.1").To4()}, ips)
+	assert.Equal(t, int64(4), queries.Load())
+}
+
+func TestResolver_DialContext(t *testing.T) {
+	server := startDNSServer(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := &dns.Msg{}
+		resp.SetReply(req)
+
+		if req.Question[0].Qtype == dns.TypeA {
+			resp.Answer = append(resp.Answer, &dns.A{
+				Hdr: dns.RR_Header{Name: req.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+				A:   net.ParseIP("127.0.0.1"),
+			})
+		}
+
+		_ = w.WriteMsg(resp)
+	})
+
+	resolver, err := New(nil, Options{Servers: []string{server}})
+	require.NoError(t, err)
+
+	var dialed []string
+	dial := resolver.DialContext(func(_ context.Context, _, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+		client, _ := net.Pipe()
+		return client, nil
+	})
+
+	_, err = dial(context.Background(), "tcp", "foo.localhost:8080")
+	require.NoError(t, err)
+
+	_, err = dial(context.Background(), "tcp", "10.0.0.1:8080")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"127.0.0.1:8080", "10.0.0.1:8080"}, dialed)
+}
+
+func TestResolver_DialContext_nil(t *testing.T) {
+	var resolver *Resolver
+
+	var dialed string
+	dial := resolver.DialContext(func(_ context.Context, _, addr string) (net.Conn, error) {
+		dialed = addr
+		return nil, nil
+	})
+
+	_, err := dial(context.Background(), "tcp", "foo.localhost:8080")
+	require.NoError(t, err)
+	assert.Equal(t, "foo.localhost:8080", dialed)
+}
+
+func startDNSServer(t *testing.T, handler dns.HandlerFunc) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &dns.Server{PacketConn: conn, Handler: handler}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	return conn.LocalAddr().String()
+}
@@ -0,0 +1,156 @@
+package egress
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"syscall"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/sockopt"
+)
+
+// DeniedError is the error of the dials denied by the egress policy.
+type DeniedError struct {
+	Network string
+	Address string
+}
+
+func (e *DeniedError) Error() string {
+	return fmt.Sprintf("the egress policy denies dialing %s %s", e.Network, e.Address)
+}
+
+// destination is a range of destinations allowed by the policy.
+type destination struct {
+	ipNet   *net.IPNet
+	minPort int
+	maxPort int
+}
+
+// Policy restricts the destinations the servers are allowed to be dialed at.
+type Policy struct {
+	allowed []destination
+	denied  gokitmetrics.Counter
+}
+
+// NewPolicy creates a new Policy allowing the given destinations,
+// as CIDR, optionally followed by a port or a range of ports (e.g. 10.0.0.0/8:8000-8999).
+func NewPolicy(registry metrics.Registry, allowed []string) (*Policy, error) {
+	if registry == nil {
+		registry = metrics.NewVoidRegistry()
+	}
+
+	if len(allowed) == 0 {
+		return nil, errors.New("no allowed destination")
+	}
+
+	policy := &Policy{denied: registry.EgressDeniedCounter()}
+	for _, value := range allowed {
+		dest, err := parseDestination(value)
+		if err != nil {
+			return nil, err
+		}
+
+		policy.allowed = append(policy.allowed, dest)
+	}
+
+	return policy, nil
+}
+
+// parseDestination parses a CIDR, optionally followed by a port or a range of ports.
+func parseDestination(value string) (destination, error) {
+	cidr, ports := value, ""
+
+	slash := strings.Index(value, "/")
+	if slash < 0 {
+		return destination{}, fmt.Errorf("invalid destination %q: a CIDR is expected", value)
+	}
+
+	if colon := strings.Index(value[slash:], ":"); colon >= 0 {
+		cidr, ports = value[:slash+colon], value[slash+colon+1:]
+	}
+
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return destination{}, fmt.Errorf("invalid destination %q: %w", value, err)
+	}
+
+	dest := destination{ipNet: ipNet, minPort: 0, maxPort: 65535}
+	if ports == "" {
+		return dest, nil
+	}
+
+	minPort, maxPort, found := strings.Cut(ports, "-")
+	if !found {
+		maxPort = minPort
+	}
+
+	dest.minPort, err = strconv.Atoi(minPort)
+	if err != nil {
+		return destination{}, fmt.Errorf("invalid destination %q: invalid port %q", value, minPort)
+	}
+
+	dest.maxPort, err = strconv.Atoi(maxPort)
+	if err != nil {
+		return destination{}, fmt.Errorf("invalid destination %q: invalid port %q", value, maxPort)
+	}
+
+	if dest.minPort < 0 || dest.maxPort > 65535 || dest.minPort > dest.maxPort {
+		return destination{}, fmt.Errorf("invalid destination %q: invalid range of ports", value)
+	}
+
+	return dest, nil
+}
+
+// Check returns a DeniedError if the policy does not allow dialing the given IP address and port.
+// The addresses which are not IP ones, such as Unix domain sockets, are not restricted.
+// A nil policy allows every destination.
+func (p *Policy) Check(network, address string) error {
+	if p == nil || strings.HasPrefix(network, "unix") {
+		return nil
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return p.deny(network, address)
+	}
+
+	ip := net.ParseIP(host)
+	portNumber, err := strconv.Atoi(port)
+	if ip == nil || err != nil {
+		return p.deny(network, address)
+	}
+
+	for _, dest := range p.allowed {
+		if dest.ipNet.Contains(ip) && dest.minPort <= portNumber && portNumber <= dest.maxPort {
+			return nil
+		}
+	}
+
+	return p.deny(network, address)
+}
+
+func (p *Policy) deny(network, address string) error {
+	network = strings.TrimRight(network, "46")
+
+	p.denied.With("network", network).Add(1)
+	log.WithoutContext().Warnf("The egress policy denies dialing %s %s", network, address)
+
+	return &DeniedError{Network: network, Address: address}
+}
+
+// Control returns the control function of the dialers, checking the IP address and port they connect to.
+// A nil policy returns a nil control function.
+func (p *Policy) Control() sockopt.ControlFunc {
+	if p == nil {
+		return nil
+	}
+
+	return func(network, address string, _ syscall.RawConn) error {
+		return p.Check(network, address)
+	}
+}
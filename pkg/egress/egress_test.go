@@ -0,0 +1,109 @@
+package egress
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPolicy(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		allowed     []string
+		expectedErr bool
+	}{
+		{
+			desc:    "CIDRs with and without ports",
+			allowed: []string{"10.0.0.0/8", "192.168.1.0/24:443", "fd00::/8:8000-8999"},
+		},
+		{
+			desc:        "no allowed destination",
+			expectedErr: true,
+		},
+		{
+			desc:        "IP address without mask",
+			allowed:     []string{"10.0.0.1"},
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid port",
+			allowed:     []string{"10.0.0.0/8:foo"},
+			expectedErr: true,
+		},
+		{
+			desc:        "inverted range of ports",
+			allowed:     []string{"10.0.0.0/8:443-80"},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewPolicy(nil, test.allowed)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPolicy_Check(t *testing.T) {
+	policy, err := NewPolicy(nil, []string{"10.0.0.0/8", "192.168.1.0/24:443", "fd00::/8:8000-8999"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		network  string
+		address  string
+		expected bool
+	}{
+		{network: "tcp4", address: "10.1.2.3:80", expected: true},
+		{network: "tcp4", address: "192.168.1.10:443", expected: true},
+		{network: "tcp4", address: "192.168.1.10:80"},
+		{network: "tcp4", address: "169.254.169.254:80"},
+		{network: "tcp6", address: "[fd00::1]:8080", expected: true},
+		{network: "tcp6", address: "[fd00::1]:9000"},
+		{network: "udp4", address: "10.0.0.53:53", expected: true},
+		{network: "tcp", address: "foo.localhost:80"},
+		{network: "unix", address: "/var/run/app.sock", expected: true},
+	}
+
+	for _, test := range testCases {
+		err := policy.Check(test.network, test.address)
+		if test.expected {
+			assert.NoError(t, err, test.address)
+			continue
+		}
+
+		var deniedErr *DeniedError
+		assert.ErrorAs(t, err, &deniedErr, test.address)
+	}
+}
+
+func TestPolicy_Control(t *testing.T) {
+	var policy *Policy
+	assert.Nil(t, policy.Control())
+	assert.NoError(t, policy.Check("tcp4", "169.254.169.254:80"))
+
+	policy, err := NewPolicy(nil, []string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	dialer := net.Dialer{Control: policy.Control()}
+	_, err = dialer.Dial("tcp", listener.Addr().String())
+
+	var deniedErr *DeniedError
+	require.True(t, errors.As(err, &deniedErr))
+	assert.Equal(t, "tcp", deniedErr.Network)
+	assert.Equal(t, listener.Addr().String(), deniedErr.Address)
+}
@@ -0,0 +1,102 @@
+package fairqueue
+
+import (
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+// Queue shares the bandwidth of an entry point across its connections, in each direction,
+// so that a bulk transfer cannot starve the interactive connections when the link is saturated.
+type Queue struct {
+	ingress *Scheduler
+	egress  *Scheduler
+}
+
+// New creates a new Queue, sharing the given bandwidth, in bytes per second, in each direction.
+func New(bandwidth int64, quantum int) *Queue {
+	return &Queue{
+		ingress: NewScheduler(bandwidth, quantum),
+		egress:  NewScheduler(bandwidth, quantum),
+	}
+}
+
+// Start starts the scheduling of the connections.
+func (q *Queue) Start() {
+	q.ingress.Start()
+	q.egress.Start()
+}
+
+// Stop stops the scheduling of the connections, failing their pending reads and writes.
+func (q *Queue) Stop() {
+	q.ingress.Stop()
+	q.egress.Stop()
+}
+
+// Wrap returns the connection, with its reads and writes scheduled by the queue.
+func (q *Queue) Wrap(conn tcp.WriteCloser) tcp.WriteCloser {
+	return &Conn{
+		WriteCloser: conn,
+		reads:       q.ingress.NewFlow(),
+		writes:      q.egress.NewFlow(),
+		quantum:     q.egress.quantum,
+	}
+}
+
+// Conn is a connection whose reads and writes are scheduled fairly with the ones of the other connections.
+// It does not implement tcp.Unwrapper, since the fast path would bypass the scheduling.
+type Conn struct {
+	tcp.WriteCloser
+
+	reads   *Flow
+	writes  *Flow
+	quantum int
+}
+
+// Read reads at most one quantum from the connection,
+// and then waits for its turn, which delays the next read.
+func (c *Conn) Read(p []byte) (int, error) {
+	if len(p) > c.quantum {
+		p = p[:c.quantum]
+	}
+
+	n, err := c.WriteCloser.Read(p)
+	if n > 0 {
+		if errWait := c.reads.Wait(n); errWait != nil && err == nil {
+			err = errWait
+		}
+	}
+
+	return n, err
+}
+
+// Write writes to the connection one quantum at a time, each waiting for its turn.
+func (c *Conn) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > c.quantum {
+			chunk = chunk[:c.quantum]
+		}
+
+		if err := c.writes.Wait(len(chunk)); err != nil {
+			return written, err
+		}
+
+		n, err := c.WriteCloser.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+
+		p = p[n:]
+	}
+
+	return written, nil
+}
+
+// Close closes the connection, failing its pending read and write.
+func (c *Conn) Close() error {
+	c.reads.Close()
+	c.writes.Close()
+
+	return c.WriteCloser.Close()
+}
@@ -0,0 +1,128 @@
+package fairqueue
+
+import (
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_next(t *testing.T) {
+	scheduler := NewScheduler(1<<30, 1000)
+
+	bulk := scheduler.NewFlow()
+	interactive := scheduler.NewFlow()
+
+	var bulkRequests []*request
+	for i := 0; i < 3; i++ {
+		bulkRequests = append(bulkRequests, scheduler.push(bulk, 1000))
+	}
+
+	assert.Same(t, bulkRequests[0], scheduler.next())
+
+	// The interactive transfer is granted before the backlog of the bulk flow.
+	interactiveRequest := scheduler.push(interactive, 10)
+
+	assert.Same(t, interactiveRequest, scheduler.next())
+	assert.Same(t, bulkRequests[1], scheduler.next())
+	assert.Same(t, bulkRequests[2], scheduler.next())
+	assert.Nil(t, scheduler.next())
+}
+
+func TestScheduler_share(t *testing.T) {
+	scheduler := NewScheduler(4<<20, 16*1024)
+	scheduler.Start()
+	defer scheduler.Stop()
+
+	var mu sync.Mutex
+	var total int
+	counts := make([]int, 2)
+
+	var wg sync.WaitGroup
+	for i := range counts {
+		i := i
+		flow := scheduler.NewFlow()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				// The flows transfer continuously, one with bigger chunks than the other.
+				if err := flow.Wait(8 * 1024 * (i + 1)); err != nil {
+					return
+				}
+
+				mu.Lock()
+				counts[i] += 8 * 1024 * (i + 1)
+				total += 8 * 1024 * (i + 1)
+				done := total >= 400*1024
+				mu.Unlock()
+
+				if done {
+					flow.Close()
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.InDelta(t, counts[0], counts[1], 32*1024)
+}
+
+func TestConn(t *testing.T) {
+	queue := New(1<<30, 4)
+	queue.Start()
+	defer queue.Stop()
+
+	client, server := net.Pipe()
+	conn := queue.Wrap(fakeConn{Conn: server})
+
+	go func() {
+		_, _ = client.Write([]byte("hello world!"))
+	}()
+
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "hell", string(buf[:n]))
+
+	go func() {
+		_, _ = conn.Write([]byte("hello world!"))
+		_ = conn.Close()
+	}()
+
+	got, err := io.ReadAll(client)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world!", string(got))
+}
+
+func TestConn_close(t *testing.T) {
+	// The queue is not started, so that the writes are never granted.
+	queue := New(1<<30, 4)
+
+	_, server := net.Pipe()
+	conn := queue.Wrap(fakeConn{Conn: server})
+
+	errCh := make(chan error)
+	go func() {
+		_, err := conn.Write([]byte("hello"))
+		errCh <- err
+	}()
+
+	require.NoError(t, conn.Close())
+	assert.ErrorIs(t, <-errCh, net.ErrClosed)
+}
+
+type fakeConn struct {
+	net.Conn
+}
+
+func (f fakeConn) CloseWrite() error {
+	return nil
+}
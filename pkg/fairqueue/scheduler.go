@@ -0,0 +1,208 @@
+package fairqueue
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultQuantum is the default number of bytes a flow transfers at once, before the others get their turn.
+const DefaultQuantum = 16 * 1024
+
+// errStopped is returned by the transfers waiting on a stopped scheduler.
+var errStopped = errors.New("fair queueing scheduler stopped")
+
+// Scheduler shares a bandwidth across flows with fair queueing.
+// Each transfer of a flow is tagged with a virtual start time, which is the finish time of the previous transfer of the flow,
+// or the start time of the transfer being granted if the flow was idle, and the transfers are granted by increasing start time,
+// at the pace of the bandwidth.
+// A flow transferring continuously thus gets its share of the bandwidth,
+// but cannot delay the transfers of the other flows by more than one quantum.
+type Scheduler struct {
+	limiter *rate.Limiter
+	quantum int
+
+	mu      sync.Mutex
+	vtime   float64
+	seq     uint64
+	pending requests
+
+	wakeup chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewScheduler creates a new Scheduler, sharing the given bandwidth, in bytes per second.
+// The transfers are granted by chunks of at most quantum bytes.
+func NewScheduler(bandwidth int64, quantum int) *Scheduler {
+	if quantum <= 0 {
+		quantum = DefaultQuantum
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Scheduler{
+		limiter: rate.NewLimiter(rate.Limit(bandwidth), quantum),
+		quantum: quantum,
+		wakeup:  make(chan struct{}, 1),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Start starts granting the transfers.
+func (s *Scheduler) Start() {
+	go s.run()
+}
+
+// Stop stops granting the transfers, and fails the pending ones.
+func (s *Scheduler) Stop() {
+	s.cancel()
+}
+
+// NewFlow creates a new flow, whose transfers are scheduled fairly with the ones of the other flows.
+func (s *Scheduler) NewFlow() *Flow {
+	return &Flow{
+		scheduler: s,
+		closed:    make(chan struct{}),
+	}
+}
+
+func (s *Scheduler) run() {
+	for {
+		req := s.next()
+		if req == nil {
+			select {
+			case <-s.wakeup:
+				continue
+			case <-s.ctx.Done():
+				return
+			}
+		}
+
+		if req.abandoned.Load() {
+			continue
+		}
+
+		close(req.ready)
+
+		// The next transfer is chosen once this one has gone through the bandwidth,
+		// for the flows it was granted ahead of to have their next transfer pending by then.
+		if err := s.limiter.WaitN(s.ctx, req.size); err != nil {
+			return
+		}
+	}
+}
+
+// push tags and enqueues a transfer of the given flow.
+func (s *Scheduler) push(flow *Flow, size int) *request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := flow.finish
+	if s.vtime > start {
+		start = s.vtime
+	}
+	flow.finish = start + float64(size)
+
+	s.seq++
+	req := &request{
+		size:  size,
+		start: start,
+		seq:   s.seq,
+		ready: make(chan struct{}),
+	}
+	heap.Push(&s.pending, req)
+
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+
+	return req
+}
+
+// next dequeues the pending transfer with the earliest start time, and advances the virtual time to it.
+func (s *Scheduler) next() *request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.pending) == 0 {
+		return nil
+	}
+
+	req := heap.Pop(&s.pending).(*request)
+	s.vtime = req.start
+
+	return req
+}
+
+// Flow is a sequence of transfers, such as the bytes written to a connection.
+type Flow struct {
+	scheduler *Scheduler
+
+	// finish is the virtual finish time of the last transfer of the flow, guarded by the scheduler lock.
+	finish float64
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// Wait waits until the transfer of n bytes is granted.
+// It fails when the flow is closed, or when the scheduler is stopped.
+func (f *Flow) Wait(n int) error {
+	req := f.scheduler.push(f, n)
+
+	select {
+	case <-req.ready:
+		return nil
+	case <-f.closed:
+		req.abandoned.Store(true)
+		return net.ErrClosed
+	case <-f.scheduler.ctx.Done():
+		return errStopped
+	}
+}
+
+// Close closes the flow, failing its pending transfer.
+func (f *Flow) Close() {
+	f.closeOnce.Do(func() { close(f.closed) })
+}
+
+type request struct {
+	size      int
+	start     float64
+	seq       uint64
+	ready     chan struct{}
+	abandoned atomic.Bool
+}
+
+// requests is a min-heap of the requests ordered by start time, and then by arrival.
+type requests []*request
+
+func (r requests) Len() int { return len(r) }
+
+func (r requests) Less(i, j int) bool {
+	if r[i].start != r[j].start {
+		return r[i].start < r[j].start
+	}
+	return r[i].seq < r[j].seq
+}
+
+func (r requests) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+
+func (r *requests) Push(x interface{}) { *r = append(*r, x.(*request)) }
+
+func (r *requests) Pop() interface{} {
+	old := *r
+	n := len(old)
+	req := old[n-1]
+	old[n-1] = nil
+	*r = old[:n-1]
+	return req
+}
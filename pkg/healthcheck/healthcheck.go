@@ -18,6 +18,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/unixsocket"
 	"github.com/vulcand/oxy/v2/roundrobin"
 )
 
@@ -87,7 +88,7 @@ type BackendConfig struct {
 }
 
 func (b *BackendConfig) newRequest(serverURL *url.URL) (*http.Request, error) {
-	u, err := serverURL.Parse(b.Path)
+	u, err := unixsocket.ToHTTP(serverURL).Parse(b.Path)
 	if err != nil {
 		return nil, err
 	}
@@ -173,7 +174,10 @@ func (hc *HealthCheck) checkServersLB(ctx context.Context, backend *BackendConfi
 	for _, disabledURL := range backend.disabledURLs {
 		serverUpMetricValue := float64(0)
 
-		if err := checkHealth(disabledURL.url, backend); err == nil {
+		err := checkHealth(disabledURL.url, backend)
+		reportHealth(backend.LB, disabledURL.url, err)
+
+		if err == nil {
 			logger.Warnf("Health check up: returning to server list. Backend: %q URL: %q Weight: %d",
 				backend.name, disabledURL.url.String(), disabledURL.weight)
 			if err = backend.LB.UpsertServer(disabledURL.url, roundrobin.Weight(disabledURL.weight)); err != nil {
@@ -194,7 +198,10 @@ func (hc *HealthCheck) checkServersLB(ctx context.Context, backend *BackendConfi
 	for _, enabledURL := range enabledURLs {
 		serverUpMetricValue := float64(1)
 
-		if err := checkHealth(enabledURL, backend); err != nil {
+		err := checkHealth(enabledURL, backend)
+		reportHealth(backend.LB, enabledURL, err)
+
+		if err != nil {
 			weight := 1
 			rr, ok := backend.LB.(*roundrobin.RoundRobin)
 			if ok {
@@ -280,6 +287,18 @@ func checkHealth(serverURL *url.URL, backend *BackendConfig) error {
 	return nil
 }
 
+// healthReporter is implemented by the balancers keeping track of the health check results of their servers.
+type healthReporter interface {
+	reportHealth(u *url.URL, err error)
+}
+
+// reportHealth reports the result of the health check of the server to the balancer, if it keeps track of it.
+func reportHealth(lb Balancer, u *url.URL, err error) {
+	if reporter, ok := lb.(healthReporter); ok {
+		reporter.reportHealth(u, err)
+	}
+}
+
 // StatusUpdater should be implemented by a service that, when its status
 // changes (e.g. all if its children are down), needs to propagate upwards (to
 // their parent(s)) that change.
@@ -379,6 +398,20 @@ func (lb *LbStatusUpdater) UpsertServer(u *url.URL, options ...roundrobin.Server
 	return nil
 }
 
+// reportHealth records the result of the health check of the server in the ServiceInfo.
+func (lb *LbStatusUpdater) reportHealth(u *url.URL, err error) {
+	if lb.serviceInfo == nil {
+		return
+	}
+
+	if err != nil {
+		lb.serviceInfo.UpdateServerHealth(u.String(), serverDown, err.Error(), time.Now())
+		return
+	}
+
+	lb.serviceInfo.UpdateServerHealth(u.String(), serverUp, "", time.Now())
+}
+
 // Balancers is a list of Balancers(s) that implements the Balancer interface.
 type Balancers []Balancer
 
@@ -428,6 +461,13 @@ func (b Balancers) UpsertServer(u *url.URL, options ...roundrobin.ServerOption)
 	return nil
 }
 
+// reportHealth reports the result of the health check of the server to all the Balancer.
+func (b Balancers) reportHealth(u *url.URL, err error) {
+	for _, lb := range b {
+		reportHealth(lb, u, err)
+	}
+}
+
 func serverKey(u *url.URL) string {
 	return u.Path + u.Host + u.Scheme
 }
@@ -2,6 +2,7 @@ package healthcheck
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -564,6 +565,36 @@ func TestLBStatusUpdater(t *testing.T) {
 	}
 }
 
+func TestLBStatusUpdater_reportHealth(t *testing.T) {
+	svInfo := &runtime.ServiceInfo{}
+	lbsu := NewLBStatusUpdater(&testLoadBalancer{RWMutex: &sync.RWMutex{}}, svInfo, nil)
+
+	server := testhelpers.MustParseURL("http://foo.com")
+
+	reportHealth(Balancers{lbsu}, server, errors.New("received error status code: 503"))
+
+	health := svInfo.GetAllHealth()
+	require.Contains(t, health, server.String())
+	assert.Equal(t, serverDown, health[server.String()].Status)
+	assert.Equal(t, "received error status code: 503", health[server.String()].Reason)
+
+	since := health[server.String()].Since
+	assert.Equal(t, since, health[server.String()].LastCheck)
+
+	reportHealth(Balancers{lbsu}, server, errors.New("received error status code: 500"))
+
+	health = svInfo.GetAllHealth()
+	assert.Equal(t, "received error status code: 500", health[server.String()].Reason)
+	assert.Equal(t, since, health[server.String()].Since)
+
+	reportHealth(Balancers{lbsu}, server, nil)
+
+	health = svInfo.GetAllHealth()
+	assert.Equal(t, serverUp, health[server.String()].Status)
+	assert.Empty(t, health[server.String()].Reason)
+	assert.Equal(t, health[server.String()].LastCheck, health[server.String()].Since)
+}
+
 func TestNotFollowingRedirects(t *testing.T) {
 	redirectServerCalled := false
 	redirectTestServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
@@ -31,7 +31,7 @@ func NewChecker(trustedIPs []string) (*Checker, error) {
 		if err != nil {
 			return nil, fmt.Errorf("parsing CIDR trusted IPs %s: %w", ipAddr, err)
 		}
-		checker.authorizedIPsNet = append(checker.authorizedIPsNet, ipAddr)
+		checker.authorizedIPsNet = append(checker.authorizedIPsNet, unmapNet(ipAddr))
 	}
 
 	return checker, nil
@@ -90,6 +90,17 @@ func (ip *Checker) ContainsIP(addr net.IP) bool {
 	return false
 }
 
+// unmapNet returns the IPv4 form of the ranges of IPv4-mapped IPv6 addresses (e.g. ::ffff:10.0.0.0/104),
+// as the IPv4-mapped addresses are matched in their IPv4 form.
+func unmapNet(ipNet *net.IPNet) *net.IPNet {
+	ones, bits := ipNet.Mask.Size()
+	if bits != 8*net.IPv6len || ones < 96 || ipNet.IP.To4() == nil {
+		return ipNet
+	}
+
+	return &net.IPNet{IP: ipNet.IP.To4(), Mask: net.CIDRMask(ones-96, 8*net.IPv4len)}
+}
+
 func parseIP(addr string) (net.IP, error) {
 	userIP := net.ParseIP(addr)
 	if userIP == nil {
@@ -185,6 +185,18 @@ func TestContainsIsAllowed(t *testing.T) {
 				"127.0.0.1",
 			},
 		},
+		{
+			desc:       "IPv4 Net with IPv4-mapped clients",
+			trustedIPs: []string{"10.0.0.0/8"},
+			passIPs:    []string{"10.0.0.1", "::ffff:10.0.0.1"},
+			rejectIPs:  []string{"11.0.0.1", "::ffff:11.0.0.1"},
+		},
+		{
+			desc:       "IPv4-mapped IPv6 Net",
+			trustedIPs: []string{"::ffff:10.0.0.0/104"},
+			passIPs:    []string{"10.0.0.1", "::ffff:10.0.0.1"},
+			rejectIPs:  []string{"11.0.0.1", "::ffff:11.0.0.1", "::10.0.0.1"},
+		},
 		{
 			desc:       "multiple IPv4",
 			trustedIPs: []string{"1.2.3.4/24", "8.8.8.8/8"},
@@ -0,0 +1,38 @@
+package ip
+
+import (
+	"fmt"
+	"net"
+)
+
+// ValidateIPv6Subnet checks that the given prefix length is a valid IPv6 subnet to group the addresses by (0 means none).
+func ValidateIPv6Subnet(ipv6Subnet int) error {
+	if ipv6Subnet < 0 || ipv6Subnet > 128 {
+		return fmt.Errorf("invalid IPv6 subnet prefix length %d: it must be between 0 and 128", ipv6Subnet)
+	}
+
+	return nil
+}
+
+// Group returns the address identifying the client of the given IP.
+// The IPv4-mapped IPv6 addresses, as seen on the dual-stack sockets, are returned in their IPv4 form,
+// so that the clients are identified the same way whichever stack they connect with.
+// When a prefix length is given, the IPv6 addresses are returned as the network address of their subnet,
+// so that the clients hopping addresses within their prefix are identified as one.
+// The values which are not IPs are returned as is.
+func Group(addr string, ipv6Subnet int) string {
+	parsed := net.ParseIP(addr)
+	if parsed == nil {
+		return addr
+	}
+
+	if ip4 := parsed.To4(); ip4 != nil {
+		return ip4.String()
+	}
+
+	if ipv6Subnet <= 0 || ipv6Subnet >= 128 {
+		return parsed.String()
+	}
+
+	return parsed.Mask(net.CIDRMask(ipv6Subnet, 128)).String()
+}
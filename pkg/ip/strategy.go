@@ -1,25 +1,55 @@
 package ip
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"net/textproto"
 	"strings"
 )
 
 const (
+	forwarded     = "Forwarded"
 	xForwardedFor = "X-Forwarded-For"
+	xRealIP       = "X-Real-Ip"
 )
 
+type clientIPKey struct{}
+
+// WithClientIP returns a copy of the context, holding the client IP derived by the entry point.
+func WithClientIP(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, clientIP)
+}
+
+// ClientIPFromContext returns the client IP derived by the entry point, if any.
+func ClientIPFromContext(ctx context.Context) (string, bool) {
+	clientIP, ok := ctx.Value(clientIPKey{}).(string)
+	return clientIP, ok
+}
+
 // Strategy a strategy for IP selection.
 type Strategy interface {
 	GetIP(req *http.Request) string
 }
 
-// RemoteAddrStrategy a strategy that always return the remote address.
-type RemoteAddrStrategy struct{}
+// RemoteAddrStrategy a strategy that returns the client IP derived by the entry point, if any, or the remote address.
+type RemoteAddrStrategy struct {
+	// IPv6Subnet is the prefix length of the IPv6 subnets whose addresses are returned as one (0 means none).
+	IPv6Subnet int
+}
 
 // GetIP returns the selected IP.
 func (s *RemoteAddrStrategy) GetIP(req *http.Request) string {
+	if clientIP, ok := ClientIPFromContext(req.Context()); ok {
+		return Group(clientIP, s.IPv6Subnet)
+	}
+
+	return Group(remoteIP(req), s.IPv6Subnet)
+}
+
+func remoteIP(req *http.Request) string {
 	ip, _, err := net.SplitHostPort(req.RemoteAddr)
 	if err != nil {
 		return req.RemoteAddr
@@ -30,6 +60,8 @@ func (s *RemoteAddrStrategy) GetIP(req *http.Request) string {
 // DepthStrategy a strategy based on the depth inside the X-Forwarded-For from right to left.
 type DepthStrategy struct {
 	Depth int
+	// IPv6Subnet is the prefix length of the IPv6 subnets whose addresses are returned as one (0 means none).
+	IPv6Subnet int
 }
 
 // GetIP return the selected IP.
@@ -40,13 +72,15 @@ func (s *DepthStrategy) GetIP(req *http.Request) string {
 	if len(xffs) < s.Depth {
 		return ""
 	}
-	return strings.TrimSpace(xffs[len(xffs)-s.Depth])
+	return Group(strings.TrimSpace(xffs[len(xffs)-s.Depth]), s.IPv6Subnet)
 }
 
 // PoolStrategy is a strategy based on an IP Checker.
 // It allows to check whether addresses are in a given pool of IPs.
 type PoolStrategy struct {
 	Checker *Checker
+	// IPv6Subnet is the prefix length of the IPv6 subnets whose addresses are returned as one (0 means none).
+	IPv6Subnet int
 }
 
 // GetIP checks the list of Forwarded IPs (most recent first) against the
@@ -66,9 +100,141 @@ func (s *PoolStrategy) GetIP(req *http.Request) string {
 			continue
 		}
 		if contain, _ := s.Checker.Contains(xffTrimmed); !contain {
-			return xffTrimmed
+			return Group(xffTrimmed, s.IPv6Subnet)
 		}
 	}
 
 	return ""
 }
+
+// ChainStrategy is a strategy deriving the client IP from the chain of the proxies the request went through.
+// The forwarding headers are only read when the remote address is a trusted proxy,
+// and the chain is then walked from right to left, up to the first address which is not a trusted proxy.
+type ChainStrategy struct {
+	// trustedProxies is nil when every proxy is trusted, up to the number of hops.
+	trustedProxies *Checker
+	hops           int
+	headers        []string
+}
+
+// NewChainStrategy creates a new ChainStrategy, trusting the given proxies, as IP or CIDR,
+// up to the given number of proxies in front of the entry point (0 means no limit),
+// and reading the first forwarding header found among the given ones (Forwarded, X-Forwarded-For, and X-Real-IP).
+func NewChainStrategy(trustedProxies []string, hops int, headers []string) (*ChainStrategy, error) {
+	if len(trustedProxies) == 0 && hops <= 0 {
+		return nil, errors.New("either trusted proxies or a number of hops is required")
+	}
+
+	strategy := &ChainStrategy{hops: hops}
+
+	if len(trustedProxies) > 0 {
+		checker, err := NewChecker(trustedProxies)
+		if err != nil {
+			return nil, err
+		}
+
+		strategy.trustedProxies = checker
+	}
+
+	for _, header := range headers {
+		header = textproto.CanonicalMIMEHeaderKey(header)
+		switch header {
+		case forwarded, xForwardedFor, xRealIP:
+			strategy.headers = append(strategy.headers, header)
+		default:
+			return nil, fmt.Errorf("unsupported forwarding header %q", header)
+		}
+	}
+
+	if len(strategy.headers) == 0 {
+		strategy.headers = []string{forwarded, xForwardedFor, xRealIP}
+	}
+
+	return strategy, nil
+}
+
+// GetIP returns the client IP.
+func (s *ChainStrategy) GetIP(req *http.Request) string {
+	remoteAddr := remoteIP(req)
+	if !s.trusted(remoteAddr) {
+		return remoteAddr
+	}
+
+	var chain []string
+	for _, header := range s.headers {
+		if len(req.Header[header]) == 0 {
+			continue
+		}
+
+		chain = forwardingChain(header, req.Header[header])
+		break
+	}
+
+	// The remote address is the first trusted proxy.
+	clientIP := remoteAddr
+	for i := len(chain) - 1; i >= 0; i-- {
+		clientIP = chain[i]
+
+		if s.hops > 0 && len(chain)-i >= s.hops || !s.trusted(clientIP) {
+			break
+		}
+	}
+
+	return clientIP
+}
+
+func (s *ChainStrategy) trusted(addr string) bool {
+	if s.trustedProxies == nil {
+		return true
+	}
+
+	ok, _ := s.trustedProxies.Contains(addr)
+	return ok
+}
+
+// forwardingChain returns the addresses of the forwarding header, from the client to the last proxy.
+func forwardingChain(header string, values []string) []string {
+	var chain []string
+	for _, value := range values {
+		for _, element := range strings.Split(value, ",") {
+			addr := strings.TrimSpace(element)
+
+			if header == forwarded {
+				addr = forwardedFor(element)
+			}
+
+			if addr == "" {
+				continue
+			}
+
+			chain = append(chain, stripPort(addr))
+		}
+	}
+
+	if header == xRealIP && len(chain) > 1 {
+		chain = chain[len(chain)-1:]
+	}
+
+	return chain
+}
+
+// forwardedFor returns the for parameter of an element of a Forwarded header (RFC 7239).
+func forwardedFor(element string) string {
+	for _, pair := range strings.Split(element, ";") {
+		key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if found && strings.EqualFold(key, "for") {
+			return strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+
+	return ""
+}
+
+// stripPort removes the port, and the brackets of the IPv6 addresses, of a forwarded address.
+func stripPort(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+}
@@ -11,13 +11,42 @@ import (
 
 func TestRemoteAddrStrategy_GetIP(t *testing.T) {
 	testCases := []struct {
-		desc     string
-		expected string
+		desc       string
+		clientIP   string
+		ipv6Subnet int
+		expected   string
 	}{
 		{
 			desc:     "Use RemoteAddr",
 			expected: "192.0.2.1",
 		},
+		{
+			desc:     "Use the client IP derived by the entry point",
+			clientIP: "10.0.0.1",
+			expected: "10.0.0.1",
+		},
+		{
+			desc:     "Use the IPv4 form of an IPv4-mapped client IP",
+			clientIP: "::ffff:10.0.0.1",
+			expected: "10.0.0.1",
+		},
+		{
+			desc:     "Use the IPv6 client IP",
+			clientIP: "2001:db8::1:2:3:4",
+			expected: "2001:db8::1:2:3:4",
+		},
+		{
+			desc:       "Use the IPv6 subnet of the client IP",
+			clientIP:   "2001:db8::1:2:3:4",
+			ipv6Subnet: 64,
+			expected:   "2001:db8::",
+		},
+		{
+			desc:       "Use the IPv4 client IP with an IPv6 subnet",
+			clientIP:   "10.0.0.1",
+			ipv6Subnet: 64,
+			expected:   "10.0.0.1",
+		},
 	}
 
 	for _, test := range testCases {
@@ -25,8 +54,11 @@ func TestRemoteAddrStrategy_GetIP(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			strategy := RemoteAddrStrategy{}
+			strategy := RemoteAddrStrategy{IPv6Subnet: test.ipv6Subnet}
 			req := httptest.NewRequest(http.MethodGet, "http://127.0.0.1", nil)
+			if test.clientIP != "" {
+				req = req.WithContext(WithClientIP(req.Context(), test.clientIP))
+			}
 			actual := strategy.GetIP(req)
 			assert.Equal(t, test.expected, actual)
 		})
@@ -37,6 +69,7 @@ func TestDepthStrategy_GetIP(t *testing.T) {
 	testCases := []struct {
 		desc          string
 		depth         int
+		ipv6Subnet    int
 		xForwardedFor string
 		expected      string
 	}{
@@ -58,6 +91,13 @@ func TestDepthStrategy_GetIP(t *testing.T) {
 			xForwardedFor: "10.0.0.2,10.0.0.1",
 			expected:      "10.0.0.2",
 		},
+		{
+			desc:          "Use depth with an IPv6 subnet",
+			depth:         2,
+			ipv6Subnet:    48,
+			xForwardedFor: "2001:db8:1:2::3,10.0.0.1",
+			expected:      "2001:db8:1::",
+		},
 	}
 
 	for _, test := range testCases {
@@ -65,7 +105,7 @@ func TestDepthStrategy_GetIP(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			strategy := DepthStrategy{Depth: test.depth}
+			strategy := DepthStrategy{Depth: test.depth, IPv6Subnet: test.ipv6Subnet}
 			req := httptest.NewRequest(http.MethodGet, "http://127.0.0.1", nil)
 			req.Header.Set(xForwardedFor, test.xForwardedFor)
 			actual := strategy.GetIP(req)
@@ -124,3 +164,119 @@ func TestTrustedIPsStrategy_GetIP(t *testing.T) {
 		})
 	}
 }
+
+func TestNewChainStrategy(t *testing.T) {
+	_, err := NewChainStrategy(nil, 0, nil)
+	assert.Error(t, err)
+
+	_, err = NewChainStrategy([]string{"foo"}, 0, nil)
+	assert.Error(t, err)
+
+	_, err = NewChainStrategy(nil, 1, []string{"X-Client-IP"})
+	assert.Error(t, err)
+
+	strategy, err := NewChainStrategy([]string{"10.0.0.0/8"}, 0, []string{"x-forwarded-for", "X-Real-IP"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{xForwardedFor, xRealIP}, strategy.headers)
+}
+
+func TestChainStrategy_GetIP(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		trustedProxies []string
+		hops           int
+		headers        []string
+		remoteAddr     string
+		header         http.Header
+		expected       string
+	}{
+		{
+			desc:           "untrusted remote address",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "192.0.2.1:1234",
+			header:         http.Header{xForwardedFor: {"203.0.113.1"}},
+			expected:       "192.0.2.1",
+		},
+		{
+			desc:           "no forwarding header",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.1:1234",
+			expected:       "10.0.0.1",
+		},
+		{
+			desc:           "trusted proxies",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.1:1234",
+			header:         http.Header{xForwardedFor: {"198.51.100.1, 203.0.113.1", "10.0.0.3, 10.0.0.2"}},
+			expected:       "203.0.113.1",
+		},
+		{
+			desc:           "only trusted proxies",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.1:1234",
+			header:         http.Header{xForwardedFor: {"10.0.0.3, 10.0.0.2"}},
+			expected:       "10.0.0.3",
+		},
+		{
+			desc:           "trusted proxies up to the number of hops",
+			trustedProxies: []string{"10.0.0.0/8"},
+			hops:           2,
+			remoteAddr:     "10.0.0.1:1234",
+			header:         http.Header{xForwardedFor: {"203.0.113.1, 10.0.0.3, 10.0.0.2"}},
+			expected:       "10.0.0.3",
+		},
+		{
+			desc:       "number of hops only",
+			hops:       2,
+			remoteAddr: "192.0.2.1:1234",
+			header:     http.Header{xForwardedFor: {"198.51.100.1, 203.0.113.1, 192.0.2.2"}},
+			expected:   "203.0.113.1",
+		},
+		{
+			desc:           "Forwarded header first",
+			trustedProxies: []string{"10.0.0.0/8", "2001:db8::/32"},
+			remoteAddr:     "10.0.0.1:1234",
+			header: http.Header{
+				forwarded:     {`for=198.51.100.1;proto=https, for="[2001:db8::1]:4711";by=10.0.0.1`, "For=203.0.113.1:80"},
+				xForwardedFor: {"192.0.2.1"},
+			},
+			expected: "203.0.113.1",
+		},
+		{
+			desc:           "header precedence",
+			trustedProxies: []string{"10.0.0.0/8"},
+			headers:        []string{xRealIP, xForwardedFor},
+			remoteAddr:     "10.0.0.1:1234",
+			header: http.Header{
+				xForwardedFor: {"192.0.2.1"},
+				xRealIP:       {"203.0.113.1"},
+			},
+			expected: "203.0.113.1",
+		},
+		{
+			desc:           "obfuscated identifier",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.1:1234",
+			header:         http.Header{forwarded: {"for=_hidden, for=10.0.0.2"}},
+			expected:       "_hidden",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			strategy, err := NewChainStrategy(test.trustedProxies, test.hops, test.headers)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://127.0.0.1", nil)
+			req.RemoteAddr = test.remoteAddr
+			for name, values := range test.header {
+				req.Header[name] = values
+			}
+
+			assert.Equal(t, test.expected, strategy.GetIP(req))
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package jwtclaims
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultHeader is the default name of the header carrying the token.
+const DefaultHeader = "Authorization"
+
+const bearerScheme = "bearer "
+
+type claimsKey struct{}
+
+// Claims holds the claims of the payload of a JSON Web Token.
+type Claims map[string]interface{}
+
+// Parse decodes the claims of the payload of the given token.
+// The signature of the token is not verified: the claims must not be trusted for authentication.
+func Parse(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("token must have three parts")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("decoding payload: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	var claims Claims
+	if err := decoder.Decode(&claims); err != nil {
+		return nil, fmt.Errorf("unmarshaling payload: %w", err)
+	}
+
+	return claims, nil
+}
+
+// Token returns the token carried by the given header value, without its Bearer authentication scheme.
+func Token(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) > len(bearerScheme) && strings.EqualFold(value[:len(bearerScheme)], bearerScheme) {
+		return strings.TrimSpace(value[len(bearerScheme):])
+	}
+
+	return value
+}
+
+// WithClaims returns a copy of the given context carrying the given claims.
+func WithClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// FromContext returns the claims carried by the given context, if any.
+func FromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(Claims)
+	return claims, ok
+}
+
+// FromRequest returns the claims of the given request.
+// These are the claims extracted by the JWTClaims middleware when it handled the request,
+// or otherwise the claims of the token of the Authorization header.
+// It returns nil when the request carries no valid token.
+func FromRequest(req *http.Request) Claims {
+	if claims, ok := FromContext(req.Context()); ok {
+		return claims
+	}
+
+	token := Token(req.Header.Get(DefaultHeader))
+	if token == "" {
+		return nil
+	}
+
+	claims, err := Parse(token)
+	if err != nil {
+		return nil
+	}
+
+	return claims
+}
+
+// Values returns the values of the claim with the given name, as strings.
+// The claims nested in objects are named with their dot separated path, such as "org.plan".
+// The values of a claim holding an array are its elements.
+func (c Claims) Values(name string) []string {
+	value, ok := lookup(c, name)
+	if !ok {
+		return nil
+	}
+
+	if elements, ok := value.([]interface{}); ok {
+		var values []string
+		for _, element := range elements {
+			if v, ok := format(element); ok {
+				values = append(values, v)
+			}
+		}
+
+		return values
+	}
+
+	if v, ok := format(value); ok {
+		return []string{v}
+	}
+
+	return nil
+}
+
+// Value returns the first value of the claim with the given name.
+func (c Claims) Value(name string) (string, bool) {
+	values := c.Values(name)
+	if len(values) == 0 {
+		return "", false
+	}
+
+	return values[0], true
+}
+
+func lookup(claims map[string]interface{}, name string) (interface{}, bool) {
+	// Claim names can contain dots, such as the namespaced ones.
+	if value, ok := claims[name]; ok {
+		return value, true
+	}
+
+	for i := strings.Index(name, "."); i >= 0; i = nextDot(name, i) {
+		nested, ok := claims[name[:i]].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if value, ok := lookup(nested, name[i+1:]); ok {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+func nextDot(name string, i int) int {
+	j := strings.Index(name[i+1:], ".")
+	if j < 0 {
+		return -1
+	}
+
+	return i + 1 + j
+}
+
+func format(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,121 @@
+package jwtclaims
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func token(payload string) string {
+	return "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".c2lnbmF0dXJl"
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		token     string
+		expected  Claims
+		expectErr bool
+	}{
+		{
+			desc:     "valid token",
+			token:    token(`{"sub":"foo","plan":"paid"}`),
+			expected: Claims{"sub": "foo", "plan": "paid"},
+		},
+		{
+			desc:     "padded payload",
+			token:    "eyJhbGciOiJIUzI1NiJ9." + base64.URLEncoding.EncodeToString([]byte(`{"sub":"foo"}`)) + ".c2lnbmF0dXJl",
+			expected: Claims{"sub": "foo"},
+		},
+		{
+			desc:      "missing part",
+			token:     "eyJhbGciOiJIUzI1NiJ9.e30",
+			expectErr: true,
+		},
+		{
+			desc:      "invalid encoding",
+			token:     "eyJhbGciOiJIUzI1NiJ9.!!!.c2lnbmF0dXJl",
+			expectErr: true,
+		},
+		{
+			desc:      "invalid JSON",
+			token:     token(`foo`),
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			claims, err := Parse(test.token)
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, claims)
+		})
+	}
+}
+
+func TestToken(t *testing.T) {
+	assert.Equal(t, "foo", Token("Bearer foo"))
+	assert.Equal(t, "foo", Token("bearer  foo "))
+	assert.Equal(t, "foo", Token("foo"))
+	assert.Equal(t, "", Token(""))
+}
+
+func TestClaims_Values(t *testing.T) {
+	claims, err := Parse(token(`{"plan":"paid","seats":42,"admin":true,"roles":["foo","bar"],"org":{"plan":"free","tier":{"id":1}},"https://example.com/plan":"pro"}`))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		expected []string
+	}{
+		{name: "plan", expected: []string{"paid"}},
+		{name: "seats", expected: []string{"42"}},
+		{name: "admin", expected: []string{"true"}},
+		{name: "roles", expected: []string{"foo", "bar"}},
+		{name: "org.plan", expected: []string{"free"}},
+		{name: "org.tier.id", expected: []string{"1"}},
+		{name: "https://example.com/plan", expected: []string{"pro"}},
+		{name: "org", expected: nil},
+		{name: "missing", expected: nil},
+		{name: "plan.missing", expected: nil},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, claims.Values(test.name), test.name)
+	}
+
+	value, ok := claims.Value("roles")
+	assert.True(t, ok)
+	assert.Equal(t, "foo", value)
+
+	_, ok = claims.Value("missing")
+	assert.False(t, ok)
+}
+
+func TestFromRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, FromRequest(req))
+
+	req.Header.Set("Authorization", "Bearer "+token(`{"plan":"paid"}`))
+	assert.Equal(t, Claims{"plan": "paid"}, FromRequest(req))
+
+	// The claims extracted by the middleware prevail.
+	req = req.WithContext(WithClaims(req.Context(), Claims{"plan": "free"}))
+	assert.Equal(t, Claims{"plan": "free"}, FromRequest(req))
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer foo")
+	assert.Nil(t, FromRequest(req))
+}
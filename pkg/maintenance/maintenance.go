@@ -0,0 +1,103 @@
+package maintenance
+
+import (
+	"sort"
+	"sync"
+)
+
+// The protocols of the routers that can be put in maintenance.
+const (
+	ProtocolHTTP = "http"
+	ProtocolTCP  = "tcp"
+)
+
+// Response is the response sent to the requests on an HTTP router in maintenance.
+type Response struct {
+	StatusCode int
+	Body       string
+}
+
+// Flags holds the routers put in maintenance at runtime, through the API.
+// The flags are cleared when a new configuration is applied.
+type Flags struct {
+	response Response
+
+	mu      sync.RWMutex
+	routers map[string]map[string]struct{}
+}
+
+// NewFlags creates a new Flags, with the response sent to the requests on the HTTP routers in maintenance.
+func NewFlags(response Response) *Flags {
+	return &Flags{
+		response: response,
+		routers:  make(map[string]map[string]struct{}),
+	}
+}
+
+// Response returns the response sent to the requests on the HTTP routers in maintenance.
+func (f *Flags) Response() Response {
+	return f.response
+}
+
+// Set puts the given router in maintenance, or takes it out of maintenance.
+func (f *Flags) Set(protocol, router string, enabled bool) {
+	if f == nil {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !enabled {
+		delete(f.routers[protocol], router)
+		return
+	}
+
+	if f.routers[protocol] == nil {
+		f.routers[protocol] = make(map[string]struct{})
+	}
+	f.routers[protocol][router] = struct{}{}
+}
+
+// Enabled reports whether the given router is in maintenance.
+func (f *Flags) Enabled(protocol, router string) bool {
+	if f == nil {
+		return false
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	_, ok := f.routers[protocol][router]
+	return ok
+}
+
+// Routers returns the sorted names of the routers of the given protocol in maintenance.
+func (f *Flags) Routers(protocol string) []string {
+	if f == nil {
+		return nil
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	routers := make([]string, 0, len(f.routers[protocol]))
+	for router := range f.routers[protocol] {
+		routers = append(routers, router)
+	}
+	sort.Strings(routers)
+
+	return routers
+}
+
+// Reset takes all the routers out of maintenance.
+func (f *Flags) Reset() {
+	if f == nil {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.routers = make(map[string]map[string]struct{})
+}
@@ -0,0 +1,38 @@
+package maintenance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlags(t *testing.T) {
+	flags := NewFlags(Response{StatusCode: 503, Body: "Under maintenance"})
+
+	flags.Set(ProtocolHTTP, "foo@file", true)
+	flags.Set(ProtocolHTTP, "bar@file", true)
+	flags.Set(ProtocolTCP, "foo@file", true)
+
+	assert.True(t, flags.Enabled(ProtocolHTTP, "foo@file"))
+	assert.True(t, flags.Enabled(ProtocolTCP, "foo@file"))
+	assert.False(t, flags.Enabled(ProtocolTCP, "bar@file"))
+	assert.Equal(t, []string{"bar@file", "foo@file"}, flags.Routers(ProtocolHTTP))
+
+	flags.Set(ProtocolHTTP, "foo@file", false)
+	assert.False(t, flags.Enabled(ProtocolHTTP, "foo@file"))
+	assert.True(t, flags.Enabled(ProtocolTCP, "foo@file"))
+
+	flags.Reset()
+	assert.Empty(t, flags.Routers(ProtocolHTTP))
+	assert.Empty(t, flags.Routers(ProtocolTCP))
+}
+
+func TestFlags_nil(t *testing.T) {
+	var flags *Flags
+
+	flags.Set(ProtocolHTTP, "foo@file", true)
+	flags.Reset()
+
+	assert.False(t, flags.Enabled(ProtocolHTTP, "foo@file"))
+	assert.Empty(t, flags.Routers(ProtocolHTTP))
+}
@@ -29,6 +29,8 @@ type Registry interface {
 	// TLS
 
 	TLSCertsNotAfterTimestampGauge() metrics.Gauge
+	ClockSkewGauge() metrics.Gauge
+	ClockSkewCertsGauge() metrics.Gauge
 
 	// entry point metrics
 
@@ -38,6 +40,9 @@ type Registry interface {
 	EntryPointOpenConnsGauge() metrics.Gauge
 	EntryPointReqsBytesCounter() metrics.Counter
 	EntryPointRespsBytesCounter() metrics.Counter
+	EntryPointShardConnsCounter() metrics.Counter
+	EntryPointClosedConnsCounter() metrics.Counter
+	EntryPointRejectedReqsCounter() metrics.Counter
 
 	// router metrics
 
@@ -58,6 +63,42 @@ type Registry interface {
 	ServiceServerUpGauge() metrics.Gauge
 	ServiceReqsBytesCounter() metrics.Counter
 	ServiceRespsBytesCounter() metrics.Counter
+
+	// tenant metrics
+
+	TenantReqsCounter() metrics.Counter
+	TenantReqsBytesCounter() metrics.Counter
+	TenantRespsBytesCounter() metrics.Counter
+	TenantOpenConnsGauge() metrics.Gauge
+
+	// bandwidth metrics
+
+	BandwidthBytesCounter() metrics.Counter
+
+	// ssh metrics
+
+	SSHSessionDurationHistogram() ScalableHistogram
+
+	// resolver metrics
+
+	ResolverLookupsCounter() metrics.Counter
+
+	// egress metrics
+
+	EgressDeniedCounter() metrics.Counter
+
+	// provider metrics
+
+	ProviderConfigUpdatesCounter() metrics.Counter
+
+	// probe metrics
+
+	ProbeChecksCounter() metrics.Counter
+	ProbeDurationHistogram() ScalableHistogram
+
+	// geoip metrics
+
+	GeoIPConnsCounter() metrics.Counter
 }
 
 // NewVoidRegistry is a noop implementation of metrics.Registry.
@@ -75,12 +116,17 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var lastConfigReloadSuccessGauge []metrics.Gauge
 	var lastConfigReloadFailureGauge []metrics.Gauge
 	var tlsCertsNotAfterTimestampGauge []metrics.Gauge
+	var clockSkewGauge []metrics.Gauge
+	var clockSkewCertsGauge []metrics.Gauge
 	var entryPointReqsCounter []CounterWithHeaders
 	var entryPointReqsTLSCounter []metrics.Counter
 	var entryPointReqDurationHistogram []ScalableHistogram
 	var entryPointOpenConnsGauge []metrics.Gauge
 	var entryPointReqsBytesCounter []metrics.Counter
 	var entryPointRespsBytesCounter []metrics.Counter
+	var entryPointShardConnsCounter []metrics.Counter
+	var entryPointClosedConnsCounter []metrics.Counter
+	var entryPointRejectedReqsCounter []metrics.Counter
 	var routerReqsCounter []CounterWithHeaders
 	var routerReqsTLSCounter []metrics.Counter
 	var routerReqDurationHistogram []ScalableHistogram
@@ -95,6 +141,18 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var serviceServerUpGauge []metrics.Gauge
 	var serviceReqsBytesCounter []metrics.Counter
 	var serviceRespsBytesCounter []metrics.Counter
+	var tenantReqsCounter []metrics.Counter
+	var tenantReqsBytesCounter []metrics.Counter
+	var tenantRespsBytesCounter []metrics.Counter
+	var tenantOpenConnsGauge []metrics.Gauge
+	var bandwidthBytesCounter []metrics.Counter
+	var sshSessionDurationHistogram []ScalableHistogram
+	var resolverLookupsCounter []metrics.Counter
+	var egressDeniedCounter []metrics.Counter
+	var providerConfigUpdatesCounter []metrics.Counter
+	var probeChecksCounter []metrics.Counter
+	var probeDurationHistogram []ScalableHistogram
+	var geoIPConnsCounter []metrics.Counter
 
 	for _, r := range registries {
 		if r.ConfigReloadsCounter() != nil {
@@ -112,6 +170,12 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.TLSCertsNotAfterTimestampGauge() != nil {
 			tlsCertsNotAfterTimestampGauge = append(tlsCertsNotAfterTimestampGauge, r.TLSCertsNotAfterTimestampGauge())
 		}
+		if r.ClockSkewGauge() != nil {
+			clockSkewGauge = append(clockSkewGauge, r.ClockSkewGauge())
+		}
+		if r.ClockSkewCertsGauge() != nil {
+			clockSkewCertsGauge = append(clockSkewCertsGauge, r.ClockSkewCertsGauge())
+		}
 		if r.EntryPointReqsCounter() != nil {
 			entryPointReqsCounter = append(entryPointReqsCounter, r.EntryPointReqsCounter())
 		}
@@ -130,6 +194,15 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.EntryPointRespsBytesCounter() != nil {
 			entryPointRespsBytesCounter = append(entryPointRespsBytesCounter, r.EntryPointRespsBytesCounter())
 		}
+		if r.EntryPointShardConnsCounter() != nil {
+			entryPointShardConnsCounter = append(entryPointShardConnsCounter, r.EntryPointShardConnsCounter())
+		}
+		if r.EntryPointClosedConnsCounter() != nil {
+			entryPointClosedConnsCounter = append(entryPointClosedConnsCounter, r.EntryPointClosedConnsCounter())
+		}
+		if r.EntryPointRejectedReqsCounter() != nil {
+			entryPointRejectedReqsCounter = append(entryPointRejectedReqsCounter, r.EntryPointRejectedReqsCounter())
+		}
 		if r.RouterReqsCounter() != nil {
 			routerReqsCounter = append(routerReqsCounter, r.RouterReqsCounter())
 		}
@@ -172,6 +245,42 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.ServiceRespsBytesCounter() != nil {
 			serviceRespsBytesCounter = append(serviceRespsBytesCounter, r.ServiceRespsBytesCounter())
 		}
+		if r.TenantReqsCounter() != nil {
+			tenantReqsCounter = append(tenantReqsCounter, r.TenantReqsCounter())
+		}
+		if r.TenantReqsBytesCounter() != nil {
+			tenantReqsBytesCounter = append(tenantReqsBytesCounter, r.TenantReqsBytesCounter())
+		}
+		if r.TenantRespsBytesCounter() != nil {
+			tenantRespsBytesCounter = append(tenantRespsBytesCounter, r.TenantRespsBytesCounter())
+		}
+		if r.TenantOpenConnsGauge() != nil {
+			tenantOpenConnsGauge = append(tenantOpenConnsGauge, r.TenantOpenConnsGauge())
+		}
+		if r.BandwidthBytesCounter() != nil {
+			bandwidthBytesCounter = append(bandwidthBytesCounter, r.BandwidthBytesCounter())
+		}
+		if r.SSHSessionDurationHistogram() != nil {
+			sshSessionDurationHistogram = append(sshSessionDurationHistogram, r.SSHSessionDurationHistogram())
+		}
+		if r.ResolverLookupsCounter() != nil {
+			resolverLookupsCounter = append(resolverLookupsCounter, r.ResolverLookupsCounter())
+		}
+		if r.EgressDeniedCounter() != nil {
+			egressDeniedCounter = append(egressDeniedCounter, r.EgressDeniedCounter())
+		}
+		if r.ProviderConfigUpdatesCounter() != nil {
+			providerConfigUpdatesCounter = append(providerConfigUpdatesCounter, r.ProviderConfigUpdatesCounter())
+		}
+		if r.ProbeChecksCounter() != nil {
+			probeChecksCounter = append(probeChecksCounter, r.ProbeChecksCounter())
+		}
+		if r.ProbeDurationHistogram() != nil {
+			probeDurationHistogram = append(probeDurationHistogram, r.ProbeDurationHistogram())
+		}
+		if r.GeoIPConnsCounter() != nil {
+			geoIPConnsCounter = append(geoIPConnsCounter, r.GeoIPConnsCounter())
+		}
 	}
 
 	return &standardRegistry{
@@ -183,12 +292,17 @@ func NewMultiRegistry(registries []Registry) Registry {
 		lastConfigReloadSuccessGauge:   multi.NewGauge(lastConfigReloadSuccessGauge...),
 		lastConfigReloadFailureGauge:   multi.NewGauge(lastConfigReloadFailureGauge...),
 		tlsCertsNotAfterTimestampGauge: multi.NewGauge(tlsCertsNotAfterTimestampGauge...),
+		clockSkewGauge:                 multi.NewGauge(clockSkewGauge...),
+		clockSkewCertsGauge:            multi.NewGauge(clockSkewCertsGauge...),
 		entryPointReqsCounter:          NewMultiCounterWithHeaders(entryPointReqsCounter...),
 		entryPointReqsTLSCounter:       multi.NewCounter(entryPointReqsTLSCounter...),
 		entryPointReqDurationHistogram: MultiHistogram(entryPointReqDurationHistogram),
 		entryPointOpenConnsGauge:       multi.NewGauge(entryPointOpenConnsGauge...),
 		entryPointReqsBytesCounter:     multi.NewCounter(entryPointReqsBytesCounter...),
 		entryPointRespsBytesCounter:    multi.NewCounter(entryPointRespsBytesCounter...),
+		entryPointShardConnsCounter:    multi.NewCounter(entryPointShardConnsCounter...),
+		entryPointClosedConnsCounter:   multi.NewCounter(entryPointClosedConnsCounter...),
+		entryPointRejectedReqsCounter:  multi.NewCounter(entryPointRejectedReqsCounter...),
 		routerReqsCounter:              NewMultiCounterWithHeaders(routerReqsCounter...),
 		routerReqsTLSCounter:           multi.NewCounter(routerReqsTLSCounter...),
 		routerReqDurationHistogram:     MultiHistogram(routerReqDurationHistogram),
@@ -203,6 +317,18 @@ func NewMultiRegistry(registries []Registry) Registry {
 		serviceServerUpGauge:           multi.NewGauge(serviceServerUpGauge...),
 		serviceReqsBytesCounter:        multi.NewCounter(serviceReqsBytesCounter...),
 		serviceRespsBytesCounter:       multi.NewCounter(serviceRespsBytesCounter...),
+		tenantReqsCounter:              multi.NewCounter(tenantReqsCounter...),
+		tenantReqsBytesCounter:         multi.NewCounter(tenantReqsBytesCounter...),
+		tenantRespsBytesCounter:        multi.NewCounter(tenantRespsBytesCounter...),
+		tenantOpenConnsGauge:           multi.NewGauge(tenantOpenConnsGauge...),
+		bandwidthBytesCounter:          multi.NewCounter(bandwidthBytesCounter...),
+		sshSessionDurationHistogram:    MultiHistogram(sshSessionDurationHistogram),
+		resolverLookupsCounter:         multi.NewCounter(resolverLookupsCounter...),
+		egressDeniedCounter:            multi.NewCounter(egressDeniedCounter...),
+		providerConfigUpdatesCounter:   multi.NewCounter(providerConfigUpdatesCounter...),
+		probeChecksCounter:             multi.NewCounter(probeChecksCounter...),
+		probeDurationHistogram:         MultiHistogram(probeDurationHistogram),
+		geoIPConnsCounter:              multi.NewCounter(geoIPConnsCounter...),
 	}
 }
 
@@ -215,12 +341,17 @@ type standardRegistry struct {
 	lastConfigReloadSuccessGauge   metrics.Gauge
 	lastConfigReloadFailureGauge   metrics.Gauge
 	tlsCertsNotAfterTimestampGauge metrics.Gauge
+	clockSkewGauge                 metrics.Gauge
+	clockSkewCertsGauge            metrics.Gauge
 	entryPointReqsCounter          CounterWithHeaders
 	entryPointReqsTLSCounter       metrics.Counter
 	entryPointReqDurationHistogram ScalableHistogram
 	entryPointOpenConnsGauge       metrics.Gauge
 	entryPointReqsBytesCounter     metrics.Counter
 	entryPointRespsBytesCounter    metrics.Counter
+	entryPointShardConnsCounter    metrics.Counter
+	entryPointClosedConnsCounter   metrics.Counter
+	entryPointRejectedReqsCounter  metrics.Counter
 	routerReqsCounter              CounterWithHeaders
 	routerReqsTLSCounter           metrics.Counter
 	routerReqDurationHistogram     ScalableHistogram
@@ -235,6 +366,18 @@ type standardRegistry struct {
 	serviceServerUpGauge           metrics.Gauge
 	serviceReqsBytesCounter        metrics.Counter
 	serviceRespsBytesCounter       metrics.Counter
+	tenantReqsCounter              metrics.Counter
+	tenantReqsBytesCounter         metrics.Counter
+	tenantRespsBytesCounter        metrics.Counter
+	tenantOpenConnsGauge           metrics.Gauge
+	bandwidthBytesCounter          metrics.Counter
+	sshSessionDurationHistogram    ScalableHistogram
+	resolverLookupsCounter         metrics.Counter
+	egressDeniedCounter            metrics.Counter
+	providerConfigUpdatesCounter   metrics.Counter
+	probeChecksCounter             metrics.Counter
+	probeDurationHistogram         ScalableHistogram
+	geoIPConnsCounter              metrics.Counter
 }
 
 func (r *standardRegistry) IsEpEnabled() bool {
@@ -269,6 +412,14 @@ func (r *standardRegistry) TLSCertsNotAfterTimestampGauge() metrics.Gauge {
 	return r.tlsCertsNotAfterTimestampGauge
 }
 
+func (r *standardRegistry) ClockSkewGauge() metrics.Gauge {
+	return r.clockSkewGauge
+}
+
+func (r *standardRegistry) ClockSkewCertsGauge() metrics.Gauge {
+	return r.clockSkewCertsGauge
+}
+
 func (r *standardRegistry) EntryPointReqsCounter() CounterWithHeaders {
 	return r.entryPointReqsCounter
 }
@@ -293,6 +444,18 @@ func (r *standardRegistry) EntryPointRespsBytesCounter() metrics.Counter {
 	return r.entryPointRespsBytesCounter
 }
 
+func (r *standardRegistry) EntryPointShardConnsCounter() metrics.Counter {
+	return r.entryPointShardConnsCounter
+}
+
+func (r *standardRegistry) EntryPointClosedConnsCounter() metrics.Counter {
+	return r.entryPointClosedConnsCounter
+}
+
+func (r *standardRegistry) EntryPointRejectedReqsCounter() metrics.Counter {
+	return r.entryPointRejectedReqsCounter
+}
+
 func (r *standardRegistry) RouterReqsCounter() CounterWithHeaders {
 	return r.routerReqsCounter
 }
@@ -349,6 +512,54 @@ func (r *standardRegistry) ServiceRespsBytesCounter() metrics.Counter {
 	return r.serviceRespsBytesCounter
 }
 
+func (r *standardRegistry) TenantReqsCounter() metrics.Counter {
+	return r.tenantReqsCounter
+}
+
+func (r *standardRegistry) TenantReqsBytesCounter() metrics.Counter {
+	return r.tenantReqsBytesCounter
+}
+
+func (r *standardRegistry) TenantRespsBytesCounter() metrics.Counter {
+	return r.tenantRespsBytesCounter
+}
+
+func (r *standardRegistry) TenantOpenConnsGauge() metrics.Gauge {
+	return r.tenantOpenConnsGauge
+}
+
+func (r *standardRegistry) BandwidthBytesCounter() metrics.Counter {
+	return r.bandwidthBytesCounter
+}
+
+func (r *standardRegistry) SSHSessionDurationHistogram() ScalableHistogram {
+	return r.sshSessionDurationHistogram
+}
+
+func (r *standardRegistry) ResolverLookupsCounter() metrics.Counter {
+	return r.resolverLookupsCounter
+}
+
+func (r *standardRegistry) EgressDeniedCounter() metrics.Counter {
+	return r.egressDeniedCounter
+}
+
+func (r *standardRegistry) ProviderConfigUpdatesCounter() metrics.Counter {
+	return r.providerConfigUpdatesCounter
+}
+
+func (r *standardRegistry) ProbeChecksCounter() metrics.Counter {
+	return r.probeChecksCounter
+}
+
+func (r *standardRegistry) ProbeDurationHistogram() ScalableHistogram {
+	return r.probeDurationHistogram
+}
+
+func (r *standardRegistry) GeoIPConnsCounter() metrics.Counter {
+	return r.geoIPConnsCounter
+}
+
 // ScalableHistogram is a Histogram with a predefined time unit,
 // used when producing observations without explicitly setting the observed value.
 type ScalableHistogram interface {
@@ -31,14 +31,22 @@ const (
 	metricsTLSPrefix          = MetricNamePrefix + "tls_"
 	tlsCertsNotAfterTimestamp = metricsTLSPrefix + "certs_not_after"
 
+	// clock skew.
+	metricClockSkewPrefix = MetricNamePrefix + "clock_skew_"
+	clockSkewName         = metricClockSkewPrefix + "seconds"
+	clockSkewCertsName    = metricClockSkewPrefix + "certs"
+
 	// entry point.
-	metricEntryPointPrefix        = MetricNamePrefix + "entrypoint_"
-	entryPointReqsTotalName       = metricEntryPointPrefix + "requests_total"
-	entryPointReqsTLSTotalName    = metricEntryPointPrefix + "requests_tls_total"
-	entryPointReqDurationName     = metricEntryPointPrefix + "request_duration_seconds"
-	entryPointOpenConnsName       = metricEntryPointPrefix + "open_connections"
-	entryPointReqsBytesTotalName  = metricEntryPointPrefix + "requests_bytes_total"
-	entryPointRespsBytesTotalName = metricEntryPointPrefix + "responses_bytes_total"
+	metricEntryPointPrefix         = MetricNamePrefix + "entrypoint_"
+	entryPointReqsTotalName        = metricEntryPointPrefix + "requests_total"
+	entryPointReqsTLSTotalName     = metricEntryPointPrefix + "requests_tls_total"
+	entryPointReqDurationName      = metricEntryPointPrefix + "request_duration_seconds"
+	entryPointOpenConnsName        = metricEntryPointPrefix + "open_connections"
+	entryPointReqsBytesTotalName   = metricEntryPointPrefix + "requests_bytes_total"
+	entryPointRespsBytesTotalName  = metricEntryPointPrefix + "responses_bytes_total"
+	entryPointShardConnsTotalName  = metricEntryPointPrefix + "shard_connections_total"
+	entryPointClosedConnsTotalName = metricEntryPointPrefix + "closed_connections_total"
+	entryPointRejectedReqsName     = metricEntryPointPrefix + "requests_rejected_total"
 
 	// router level.
 	metricRouterPrefix        = MetricNamePrefix + "router_"
@@ -59,6 +67,36 @@ const (
 	serviceServerUpName        = metricServicePrefix + "server_up"
 	serviceReqsBytesTotalName  = metricServicePrefix + "requests_bytes_total"
 	serviceRespsBytesTotalName = metricServicePrefix + "responses_bytes_total"
+
+	// tenant level.
+	metricTenantPrefix        = MetricNamePrefix + "tenant_"
+	tenantReqsTotalName       = metricTenantPrefix + "requests_total"
+	tenantOpenConnsName       = metricTenantPrefix + "open_connections"
+	tenantReqsBytesTotalName  = metricTenantPrefix + "requests_bytes_total"
+	tenantRespsBytesTotalName = metricTenantPrefix + "responses_bytes_total"
+
+	// bandwidth level.
+	bandwidthBytesTotalName = MetricNamePrefix + "bandwidth_bytes_total"
+
+	// ssh level.
+	sshSessionDurationName = MetricNamePrefix + "ssh_session_duration_seconds"
+
+	// resolver level.
+	resolverLookupsTotalName = MetricNamePrefix + "resolver_lookups_total"
+
+	// egress level.
+	egressDeniedTotalName = MetricNamePrefix + "egress_denied_total"
+
+	// provider level.
+	providerConfigUpdatesTotalName = MetricNamePrefix + "provider_config_updates_total"
+
+	// probe level.
+	metricProbePrefix    = MetricNamePrefix + "probe_"
+	probeChecksTotalName = metricProbePrefix + "checks_total"
+	probeDurationName    = metricProbePrefix + "duration_seconds"
+
+	// geoip level.
+	geoIPConnsTotalName = MetricNamePrefix + "geoip_connections_total"
 )
 
 // promState holds all metric state internally and acts as the only Collector we register for Prometheus.
@@ -134,6 +172,14 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		Name: tlsCertsNotAfterTimestamp,
 		Help: "Certificate expiration timestamp",
 	}, []string{"cn", "serial", "sans"})
+	clockSkew := newGaugeFrom(stdprometheus.GaugeOpts{
+		Name: clockSkewName,
+		Help: "Offset of the NTP reference from the system clock",
+	}, []string{})
+	clockSkewCerts := newGaugeFrom(stdprometheus.GaugeOpts{
+		Name: clockSkewCertsName,
+		Help: "Number of certificates whose validity is affected by the clock skew",
+	}, []string{})
 
 	promState.vectors = []vector{
 		configReloads.cv,
@@ -141,6 +187,8 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		lastConfigReloadSuccess.gv,
 		lastConfigReloadFailure.gv,
 		tlsCertsNotAfterTimestamp.gv,
+		clockSkew.gv,
+		clockSkewCerts.gv,
 	}
 
 	reg := &standardRegistry{
@@ -152,6 +200,8 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		lastConfigReloadSuccessGauge:   lastConfigReloadSuccess,
 		lastConfigReloadFailureGauge:   lastConfigReloadFailure,
 		tlsCertsNotAfterTimestampGauge: tlsCertsNotAfterTimestamp,
+		clockSkewGauge:                 clockSkew,
+		clockSkewCertsGauge:            clockSkewCerts,
 	}
 
 	if config.AddEntryPointsLabels {
@@ -180,6 +230,18 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: entryPointRespsBytesTotalName,
 			Help: "The total size of responses in bytes handled by an entrypoint, partitioned by status code, protocol, and method.",
 		}, []string{"code", "method", "protocol", "entrypoint"})
+		entryPointShardConnsTotal := newCounterFrom(stdprometheus.CounterOpts{
+			Name: entryPointShardConnsTotalName,
+			Help: "How many connections are accepted by a shard of an entrypoint.",
+		}, []string{"entrypoint", "shard"})
+		entryPointClosedConnsTotal := newCounterFrom(stdprometheus.CounterOpts{
+			Name: entryPointClosedConnsTotalName,
+			Help: "How many TCP connections ended on an entrypoint, partitioned by the reason why they ended, and whether they used Multipath TCP.",
+		}, []string{"entrypoint", "reason", "mptcp"})
+		entryPointRejectedReqsTotal := newCounterFrom(stdprometheus.CounterOpts{
+			Name: entryPointRejectedReqsName,
+			Help: "How many HTTP requests are rejected by the strict parsing of an entrypoint, partitioned by the reason why they are rejected.",
+		}, []string{"entrypoint", "reason"})
 
 		promState.vectors = append(promState.vectors,
 			entryPointReqs.cv,
@@ -188,6 +250,9 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			entryPointOpenConns.gv,
 			entryPointReqsBytesTotal.cv,
 			entryPointRespsBytesTotal.cv,
+			entryPointShardConnsTotal.cv,
+			entryPointClosedConnsTotal.cv,
+			entryPointRejectedReqsTotal.cv,
 		)
 
 		reg.entryPointReqsCounter = entryPointReqs
@@ -196,6 +261,9 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		reg.entryPointOpenConnsGauge = entryPointOpenConns
 		reg.entryPointReqsBytesCounter = entryPointReqsBytesTotal
 		reg.entryPointRespsBytesCounter = entryPointRespsBytesTotal
+		reg.entryPointShardConnsCounter = entryPointShardConnsTotal
+		reg.entryPointClosedConnsCounter = entryPointClosedConnsTotal
+		reg.entryPointRejectedReqsCounter = entryPointRejectedReqsTotal
 	}
 
 	if config.AddRoutersLabels {
@@ -297,6 +365,115 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		reg.serviceRespsBytesCounter = serviceRespsBytesTotal
 	}
 
+	if config.AddTenantsLabels {
+		tenantReqs := newCounterFrom(stdprometheus.CounterOpts{
+			Name: tenantReqsTotalName,
+			Help: "How many HTTP requests are processed for a tenant, partitioned by status code.",
+		}, []string{"code", "tenant"})
+		tenantOpenConns := newGaugeFrom(stdprometheus.GaugeOpts{
+			Name: tenantOpenConnsName,
+			Help: "How many open connections exist for a tenant, partitioned by protocol.",
+		}, []string{"protocol", "tenant"})
+		tenantReqsBytesTotal := newCounterFrom(stdprometheus.CounterOpts{
+			Name: tenantReqsBytesTotalName,
+			Help: "The total size of requests in bytes received for a tenant, partitioned by protocol.",
+		}, []string{"protocol", "tenant"})
+		tenantRespsBytesTotal := newCounterFrom(stdprometheus.CounterOpts{
+			Name: tenantRespsBytesTotalName,
+			Help: "The total size of responses in bytes sent for a tenant, partitioned by protocol.",
+		}, []string{"protocol", "tenant"})
+
+		promState.vectors = append(promState.vectors,
+			tenantReqs.cv,
+			tenantOpenConns.gv,
+			tenantReqsBytesTotal.cv,
+			tenantRespsBytesTotal.cv,
+		)
+
+		reg.tenantReqsCounter = tenantReqs
+		reg.tenantOpenConnsGauge = tenantOpenConns
+		reg.tenantReqsBytesCounter = tenantReqsBytesTotal
+		reg.tenantRespsBytesCounter = tenantRespsBytesTotal
+	}
+
+	// The bandwidth is only observed when the bandwidth accounting is enabled.
+	bandwidthBytesTotal := newCounterFrom(stdprometheus.CounterOpts{
+		Name: bandwidthBytesTotalName,
+		Help: "The total size in bytes transferred through a router, partitioned by service, tenant, direction, and kind (wire or logical).",
+	}, []string{"router", "service", "tenant", "direction", "kind"})
+
+	promState.vectors = append(promState.vectors, bandwidthBytesTotal.cv)
+
+	reg.bandwidthBytesCounter = bandwidthBytesTotal
+
+	// The SSH sessions are only observed on the TCP routers with the SSH protocol-aware mode.
+	sshSessionDurations := newHistogramFrom(stdprometheus.HistogramOpts{
+		Name:    sshSessionDurationName,
+		Help:    "How long the SSH sessions lasted, partitioned by router and source IP.",
+		Buckets: []float64{1, 10, 60, 300, 1800, 3600, 14400},
+	}, []string{"router", "source"})
+
+	promState.vectors = append(promState.vectors, sshSessionDurations.hv)
+
+	reg.sshSessionDurationHistogram, _ = NewHistogramWithScale(sshSessionDurations, time.Second)
+
+	// The lookups are only observed when the servers resolver is enabled.
+	resolverLookupsTotal := newCounterFrom(stdprometheus.CounterOpts{
+		Name: resolverLookupsTotalName,
+		Help: "How many hostname lookups of the servers resolver, partitioned by result (hit, negative_hit, miss, stale, or error).",
+	}, []string{"result"})
+
+	promState.vectors = append(promState.vectors, resolverLookupsTotal.cv)
+
+	reg.resolverLookupsCounter = resolverLookupsTotal
+
+	// The denied dials are only observed when the egress policy is enabled.
+	egressDeniedTotal := newCounterFrom(stdprometheus.CounterOpts{
+		Name: egressDeniedTotalName,
+		Help: "How many dials of the servers the egress policy denied, partitioned by network.",
+	}, []string{"network"})
+
+	promState.vectors = append(promState.vectors, egressDeniedTotal.cv)
+
+	reg.egressDeniedCounter = egressDeniedTotal
+
+	// The configuration updates are only observed when the debouncing of the providers is enabled.
+	providerConfigUpdatesTotal := newCounterFrom(stdprometheus.CounterOpts{
+		Name: providerConfigUpdatesTotalName,
+		Help: "How many configuration updates of the providers were debounced, partitioned by provider and result (applied or coalesced).",
+	}, []string{"provider", "result"})
+
+	promState.vectors = append(promState.vectors, providerConfigUpdatesTotal.cv)
+
+	reg.providerConfigUpdatesCounter = providerConfigUpdatesTotal
+
+	// The checks are only observed when synthetic probes are configured.
+	probeChecksTotal := newCounterFrom(stdprometheus.CounterOpts{
+		Name: probeChecksTotalName,
+		Help: "How many checks of the synthetic probes, partitioned by probe and result (success or failure).",
+	}, []string{"probe", "result"})
+
+	probeDurations := newHistogramFrom(stdprometheus.HistogramOpts{
+		Name:    probeDurationName,
+		Help:    "How long the checks of the synthetic probes took, partitioned by probe and result (success or failure).",
+		Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"probe", "result"})
+
+	promState.vectors = append(promState.vectors, probeChecksTotal.cv, probeDurations.hv)
+
+	reg.probeChecksCounter = probeChecksTotal
+	reg.probeDurationHistogram, _ = NewHistogramWithScale(probeDurations, time.Second)
+
+	// The connections are only observed when GeoIP TCP middlewares are configured.
+	geoIPConnsTotal := newCounterFrom(stdprometheus.CounterOpts{
+		Name: geoIPConnsTotalName,
+		Help: "How many connections the GeoIP TCP middlewares looked up, partitioned by middleware, country, and result (accepted or rejected).",
+	}, []string{"middleware", "country", "result"})
+
+	promState.vectors = append(promState.vectors, geoIPConnsTotal.cv)
+
+	reg.geoIPConnsCounter = geoIPConnsTotal
+
 	return reg
 }
 
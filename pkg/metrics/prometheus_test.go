@@ -96,6 +96,7 @@ func TestPrometheus(t *testing.T) {
 		AddEntryPointsLabels: true,
 		AddRoutersLabels:     true,
 		AddServicesLabels:    true,
+		AddTenantsLabels:     true,
 		HeaderLabels:         map[string]string{"useragent": "User-Agent"},
 	})
 	defer promRegistry.Unregister(promState)
@@ -193,6 +194,33 @@ func TestPrometheus(t *testing.T) {
 		With("service", "service1", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http").
 		Add(1)
 
+	prometheusRegistry.
+		TenantReqsCounter().
+		With("tenant", "acme", "code", strconv.Itoa(http.StatusOK)).
+		Add(1)
+	prometheusRegistry.
+		TenantOpenConnsGauge().
+		With("tenant", "acme", "protocol", "tcp").
+		Set(1)
+	prometheusRegistry.
+		TenantReqsBytesCounter().
+		With("tenant", "acme", "protocol", "http").
+		Add(1)
+	prometheusRegistry.
+		TenantRespsBytesCounter().
+		With("tenant", "acme", "protocol", "http").
+		Add(1)
+
+	prometheusRegistry.
+		BandwidthBytesCounter().
+		With("router", "demo", "service", "service1", "tenant", "acme", "direction", "response", "kind", "logical").
+		Add(1)
+
+	prometheusRegistry.
+		SSHSessionDurationHistogram().
+		With("router", "demo", "source", "10.0.0.1").
+		Observe(60)
+
 	delayForTrackingCompletion()
 
 	metricsFamilies := mustScrape()
@@ -416,6 +444,57 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildCounterAssert(t, serviceRespsBytesTotalName, 1),
 		},
+		{
+			name: tenantReqsTotalName,
+			labels: map[string]string{
+				"code":   "200",
+				"tenant": "acme",
+			},
+			assert: buildCounterAssert(t, tenantReqsTotalName, 1),
+		},
+		{
+			name: tenantOpenConnsName,
+			labels: map[string]string{
+				"protocol": "tcp",
+				"tenant":   "acme",
+			},
+			assert: buildGaugeAssert(t, tenantOpenConnsName, 1),
+		},
+		{
+			name: tenantReqsBytesTotalName,
+			labels: map[string]string{
+				"protocol": "http",
+				"tenant":   "acme",
+			},
+			assert: buildCounterAssert(t, tenantReqsBytesTotalName, 1),
+		},
+		{
+			name: tenantRespsBytesTotalName,
+			labels: map[string]string{
+				"protocol": "http",
+				"tenant":   "acme",
+			},
+			assert: buildCounterAssert(t, tenantRespsBytesTotalName, 1),
+		},
+		{
+			name: bandwidthBytesTotalName,
+			labels: map[string]string{
+				"router":    "demo",
+				"service":   "service1",
+				"tenant":    "acme",
+				"direction": "response",
+				"kind":      "logical",
+			},
+			assert: buildCounterAssert(t, bandwidthBytesTotalName, 1),
+		},
+		{
+			name: sshSessionDurationName,
+			labels: map[string]string{
+				"router": "demo",
+				"source": "10.0.0.1",
+			},
+			assert: buildHistogramAssert(t, sshSessionDurationName, 1),
+		},
 	}
 
 	for _, test := range testCases {
@@ -21,6 +21,8 @@ const (
 	ServiceURL = "ServiceURL"
 	// ServiceAddr is the map key used for the IP:port of the Traefik backend (extracted from BackendURL).
 	ServiceAddr = "ServiceAddr"
+	// ServerIdentity is the map key used for the identity of the server injected in the response, when enabled on the service.
+	ServerIdentity = "ServerIdentity"
 
 	// ClientAddr is the map key used for the remote address in its original form (usually IP:port).
 	ClientAddr = "ClientAddr"
@@ -75,6 +77,15 @@ const (
 	TLSVersion = "TLSVersion"
 	// TLSCipher is the cipher used in the request.
 	TLSCipher = "TLSCipher"
+
+	// PrevHash is the map key used for the hex encoded SHA-256 hash of the previous line, when the integrity is enabled.
+	PrevHash = "PrevHash"
+	// Checkpoint is the map key used for the time of a signed checkpoint.
+	Checkpoint = "Checkpoint"
+	// CheckpointEntries is the map key used for the number of access logs written before a signed checkpoint, since the start.
+	CheckpointEntries = "CheckpointEntries"
+	// CheckpointSignature is the map key used for the base64 encoded Ed25519 signature of a checkpoint.
+	CheckpointSignature = "CheckpointSignature"
 )
 
 // These are written out in the default case when no config is provided to specify keys of interest.
@@ -110,6 +121,7 @@ func init() {
 		allCoreKeys[k] = struct{}{}
 	}
 	allCoreKeys[ServiceAddr] = struct{}{}
+	allCoreKeys[ServerIdentity] = struct{}{}
 	allCoreKeys[ClientAddr] = struct{}{}
 	allCoreKeys[RequestAddr] = struct{}{}
 	allCoreKeys[GzipRatio] = struct{}{}
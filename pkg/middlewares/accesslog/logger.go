@@ -18,6 +18,7 @@ import (
 	"github.com/containous/alice"
 	"github.com/sirupsen/logrus"
 	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/ip"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares/capture"
 	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
@@ -63,6 +64,9 @@ type Handler struct {
 	httpCodeRanges types.HTTPCodeRanges
 	logHandlerChan chan handlerParams
 	wg             sync.WaitGroup
+
+	// stopCheckpoints stops writing the signed checkpoints, when enabled.
+	stopCheckpoints chan struct{}
 }
 
 // WrapHandler Wraps access log handler into an Alice Constructor.
@@ -98,6 +102,15 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 		formatter = new(CommonLogFormatter)
 	}
 
+	if config.Integrity != nil {
+		integrity, err := newIntegrityFormatter(formatter, config.Integrity)
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("error setting up access log integrity: %w", err)
+		}
+		formatter = integrity
+	}
+
 	logger := &logrus.Logger{
 		Out:       file,
 		Formatter: formatter,
@@ -141,6 +154,20 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 		}()
 	}
 
+	if config.Integrity != nil && config.Integrity.KeyFile != "" {
+		interval := time.Duration(config.Integrity.CheckpointInterval)
+		if interval <= 0 {
+			interval = time.Hour
+		}
+
+		logHandler.stopCheckpoints = make(chan struct{})
+		logHandler.wg.Add(1)
+		go func() {
+			defer logHandler.wg.Done()
+			logHandler.writeCheckpoints(interval)
+		}()
+	}
+
 	return logHandler, nil
 }
 
@@ -223,7 +250,9 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http
 	core[ClientAddr] = req.RemoteAddr
 	core[ClientHost], core[ClientPort] = silentSplitHostPort(req.RemoteAddr)
 
-	if forwardedFor := req.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+	if clientIP, ok := ip.ClientIPFromContext(req.Context()); ok {
+		core[ClientHost] = clientIP
+	} else if forwardedFor := req.Header.Get("X-Forwarded-For"); forwardedFor != "" {
 		core[ClientHost] = forwardedFor
 	}
 
@@ -253,8 +282,17 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http
 
 // Close closes the Logger (i.e. the file, drain logHandlerChan, etc).
 func (h *Handler) Close() error {
+	if h.stopCheckpoints != nil {
+		close(h.stopCheckpoints)
+	}
 	close(h.logHandlerChan)
 	h.wg.Wait()
+
+	if h.stopCheckpoints != nil {
+		// The last checkpoint covers all the access logs written before closing.
+		h.writeCheckpoint()
+	}
+
 	return h.file.Close()
 }
 
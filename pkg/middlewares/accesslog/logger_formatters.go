@@ -21,6 +21,16 @@ type CommonLogFormatter struct{}
 func (f *CommonLogFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	b := &bytes.Buffer{}
 
+	if at, ok := entry.Data[Checkpoint].(time.Time); ok {
+		_, err := fmt.Fprintf(b, "checkpoint %s %v %s %s\n",
+			at.Format(time.RFC3339Nano),
+			toLog(entry.Data, CheckpointEntries, defaultValue, false),
+			toLog(entry.Data, PrevHash, defaultValue, false),
+			toLog(entry.Data, CheckpointSignature, defaultValue, false))
+
+		return b.Bytes(), err
+	}
+
 	timestamp := defaultValue
 	if v, ok := entry.Data[StartUTC]; ok {
 		timestamp = v.(time.Time).Format(commonLogTimeFormat)
@@ -33,7 +43,7 @@ func (f *CommonLogFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 		elapsedMillis = v.(time.Duration).Nanoseconds() / 1000000
 	}
 
-	_, err := fmt.Fprintf(b, "%s - %s [%s] \"%s %s %s\" %v %v %s %s %v %s %s %dms\n",
+	_, err := fmt.Fprintf(b, "%s - %s [%s] \"%s %s %s\" %v %v %s %s %v %s %s %dms",
 		toLog(entry.Data, ClientHost, defaultValue, false),
 		toLog(entry.Data, ClientUsername, defaultValue, false),
 		timestamp,
@@ -48,8 +58,17 @@ func (f *CommonLogFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 		toLog(entry.Data, RouterName, `"-"`, true),
 		toLog(entry.Data, ServiceURL, `"-"`, true),
 		elapsedMillis)
+	if err != nil {
+		return nil, err
+	}
+
+	// The hash of the previous line is appended when the integrity is enabled.
+	if prevHash, ok := entry.Data[PrevHash].(string); ok {
+		b.WriteString(" " + prevHash)
+	}
+	b.WriteString("\n")
 
-	return b.Bytes(), err
+	return b.Bytes(), nil
 }
 
 func toLog(fields logrus.Fields, key, defaultValue string, quoted bool) interface{} {
@@ -0,0 +1,131 @@
+package accesslog
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// genesisHash is the previous hash of the first line written since the start.
+var genesisHash = strings.Repeat("0", 2*sha256.Size)
+
+// integrityFormatter chains each line formatted by the wrapped formatter with the hash of the previous line,
+// and signs the checkpoints.
+// It is not safe for concurrent use, and relies on the lock of the logger.
+type integrityFormatter struct {
+	formatter logrus.Formatter
+	key       ed25519.PrivateKey
+
+	prevHash string
+	entries  uint64
+}
+
+func newIntegrityFormatter(formatter logrus.Formatter, config *types.AccessLogIntegrity) (*integrityFormatter, error) {
+	f := &integrityFormatter{
+		formatter: formatter,
+		prevHash:  genesisHash,
+	}
+
+	if config.KeyFile == "" {
+		return f, nil
+	}
+
+	key, err := loadSigningKey(config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error loading the signing key %s: %w", config.KeyFile, err)
+	}
+	f.key = key
+
+	return f, nil
+}
+
+// Format formats the entry with the hash of the previous line, and signs it if it is a checkpoint.
+func (f *integrityFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	entry.Data[PrevHash] = f.prevHash
+
+	at, checkpoint := entry.Data[Checkpoint].(time.Time)
+	if checkpoint {
+		signature := ed25519.Sign(f.key, checkpointMessage(f.prevHash, f.entries, at))
+		entry.Data[CheckpointEntries] = f.entries
+		entry.Data[CheckpointSignature] = base64.StdEncoding.EncodeToString(signature)
+	}
+
+	b, err := f.formatter.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(bytes.TrimSuffix(b, []byte("\n")))
+	f.prevHash = hex.EncodeToString(sum[:])
+
+	if !checkpoint {
+		f.entries++
+	}
+
+	return b, nil
+}
+
+// checkpointMessage returns the message signed by a checkpoint,
+// made of the hash of the previous line, the number of access logs written since the start, and the time of the checkpoint.
+func checkpointMessage(prevHash string, entries uint64, at time.Time) []byte {
+	return []byte(fmt.Sprintf("%s %d %s", prevHash, entries, at.UTC().Format(time.RFC3339Nano)))
+}
+
+func loadSigningKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T, an Ed25519 key is expected", key)
+	}
+
+	return edKey, nil
+}
+
+// writeCheckpoints writes a signed checkpoint at each interval, until the checkpoints are stopped.
+func (h *Handler) writeCheckpoints(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.stopCheckpoints:
+			return
+		case <-ticker.C:
+			h.writeCheckpoint()
+		}
+	}
+}
+
+// writeCheckpoint writes a signed checkpoint of the access logs written so far.
+func (h *Handler) writeCheckpoint() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.logger.WithField(Checkpoint, time.Now().UTC()).Println()
+}
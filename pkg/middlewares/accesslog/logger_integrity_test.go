@@ -0,0 +1,184 @@
+package accesslog
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containous/alice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/middlewares/capture"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestIntegrity_JSON(t *testing.T) {
+	publicKey, keyFile := createSigningKey(t)
+	logFilePath := filepath.Join(t.TempDir(), "access.log")
+
+	writeAccessLogs(t, &types.AccessLog{
+		FilePath: logFilePath,
+		Format:   JSONFormat,
+		Integrity: &types.AccessLogIntegrity{
+			KeyFile:            keyFile,
+			CheckpointInterval: ptypes.Duration(time.Hour),
+		},
+	}, 3)
+
+	lines := readLines(t, logFilePath)
+	// The three access logs, and the checkpoint written when closing.
+	require.Len(t, lines, 4)
+
+	prevHash := genesisHash
+	for _, line := range lines {
+		var data map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &data))
+
+		assert.Equal(t, prevHash, data[PrevHash])
+		prevHash = hashLine(line)
+	}
+
+	var checkpoint map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[3]), &checkpoint))
+
+	assert.Equal(t, float64(3), checkpoint[CheckpointEntries])
+
+	at, err := time.Parse(time.RFC3339Nano, checkpoint[Checkpoint].(string))
+	require.NoError(t, err)
+
+	signature, err := base64.StdEncoding.DecodeString(checkpoint[CheckpointSignature].(string))
+	require.NoError(t, err)
+
+	message := checkpointMessage(hashLine(lines[2]), 3, at)
+	assert.True(t, ed25519.Verify(publicKey, message, signature))
+}
+
+func TestIntegrity_CLF(t *testing.T) {
+	publicKey, keyFile := createSigningKey(t)
+	logFilePath := filepath.Join(t.TempDir(), "access.log")
+
+	writeAccessLogs(t, &types.AccessLog{
+		FilePath: logFilePath,
+		Format:   CommonFormat,
+		Integrity: &types.AccessLogIntegrity{
+			KeyFile:            keyFile,
+			CheckpointInterval: ptypes.Duration(time.Hour),
+		},
+	}, 2)
+
+	lines := readLines(t, logFilePath)
+	require.Len(t, lines, 3)
+
+	// The hash of the previous line is the last field of the access logs.
+	assert.True(t, strings.HasSuffix(lines[0], " "+genesisHash))
+	assert.True(t, strings.HasSuffix(lines[1], " "+hashLine(lines[0])))
+
+	fields := strings.Fields(lines[2])
+	require.Len(t, fields, 5)
+	assert.Equal(t, "checkpoint", fields[0])
+	assert.Equal(t, "2", fields[2])
+	assert.Equal(t, hashLine(lines[1]), fields[3])
+
+	at, err := time.Parse(time.RFC3339Nano, fields[1])
+	require.NoError(t, err)
+
+	signature, err := base64.StdEncoding.DecodeString(fields[4])
+	require.NoError(t, err)
+
+	entries, err := strconv.ParseUint(fields[2], 10, 64)
+	require.NoError(t, err)
+
+	assert.True(t, ed25519.Verify(publicKey, checkpointMessage(fields[3], entries, at), signature))
+}
+
+func TestIntegrity_withoutKey(t *testing.T) {
+	logFilePath := filepath.Join(t.TempDir(), "access.log")
+
+	writeAccessLogs(t, &types.AccessLog{
+		FilePath:  logFilePath,
+		Format:    JSONFormat,
+		Integrity: &types.AccessLogIntegrity{},
+	}, 2)
+
+	// The access logs are chained, but no checkpoint is written.
+	lines := readLines(t, logFilePath)
+	require.Len(t, lines, 2)
+
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &data))
+
+	assert.Equal(t, hashLine(lines[0]), data[PrevHash])
+	assert.NotContains(t, data, Checkpoint)
+}
+
+func TestIntegrity_invalidKey(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(keyFile, []byte("not a key"), 0o600))
+
+	_, err := NewHandler(&types.AccessLog{
+		FilePath:  filepath.Join(t.TempDir(), "access.log"),
+		Integrity: &types.AccessLogIntegrity{KeyFile: keyFile},
+	})
+	assert.Error(t, err)
+}
+
+func createSigningKey(t *testing.T) (ed25519.PublicKey, string) {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "key.pem")
+	err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600)
+	require.NoError(t, err)
+
+	return publicKey, keyFile
+}
+
+// writeAccessLogs writes the given number of access logs, and closes the logger.
+func writeAccessLogs(t *testing.T, config *types.AccessLog, count int) {
+	t.Helper()
+
+	logger, err := NewHandler(config)
+	require.NoError(t, err)
+
+	handler, err := alice.New(capture.Wrap, WrapHandler(logger)).Then(http.HandlerFunc(logWriterTestHandlerFunc))
+	require.NoError(t, err)
+
+	for i := 0; i < count; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/foo", nil))
+	}
+
+	require.NoError(t, logger.Close())
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func hashLine(line string) string {
+	sum := sha256.Sum256([]byte(line))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,167 @@
+package accounting
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/sirupsen/logrus"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/overrides"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const typeName = "Accounting"
+
+// Names of the trailers holding the report.
+const (
+	RequestBytesTrailer       = "X-Accounting-Request-Bytes"
+	ResponseBytesTrailer      = "X-Accounting-Response-Bytes"
+	CompressionSavingsTrailer = "X-Accounting-Compression-Savings"
+	ServerTimingTrailer       = "Server-Timing"
+)
+
+type accounting struct {
+	next       http.Handler
+	name       string
+	sampleRate float64
+	log        bool
+
+	// overrides holds the sample rate set at runtime through the API, which takes precedence over the configured one.
+	overrides *overrides.Store
+}
+
+// New creates a new accounting middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Accounting, name string, overridesStore *overrides.Store) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if config.SampleRate < 0 || config.SampleRate > 1 {
+		return nil, fmt.Errorf("sample rate must be between 0 and 1, got %v", config.SampleRate)
+	}
+
+	sampleRate := config.SampleRate
+	if sampleRate == 0 {
+		sampleRate = 1
+	}
+
+	return &accounting{
+		next:       next,
+		name:       name,
+		sampleRate: sampleRate,
+		log:        config.Log,
+		overrides:  overridesStore,
+	}, nil
+}
+
+func (a *accounting) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return a.name, tracing.SpanKindNoneEnum
+}
+
+func (a *accounting) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	sampleRate := a.sampleRate
+	if params, ok := a.overrides.Get(a.name); ok && params.Accounting != nil {
+		sampleRate = params.Accounting.SampleRate
+	}
+
+	if sampleRate < 1 && rand.Float64() >= sampleRate {
+		a.next.ServeHTTP(rw, req)
+		return
+	}
+
+	rec := &record{}
+	req = req.WithContext(context.WithValue(req.Context(), recordKey, rec))
+
+	var body *countingReader
+	if req.Body != nil && req.Body != http.NoBody {
+		body = &countingReader{ReadCloser: req.Body}
+		req.Body = body
+	}
+
+	if !a.log {
+		for _, trailer := range []string{RequestBytesTrailer, ResponseBytesTrailer, CompressionSavingsTrailer, ServerTimingTrailer} {
+			rw.Header().Add("Trailer", trailer)
+		}
+	}
+
+	crw := newCountingResponseWriter(rw, !a.log)
+	start := time.Now()
+
+	a.next.ServeHTTP(crw, req)
+
+	total := time.Since(start)
+
+	r := report{total: total, upstream: total, responseBytes: crw.size.Load()}
+	if body != nil {
+		r.requestBytes = body.size.Load()
+	}
+
+	rec.done(func(s *span) {
+		r.timings = append(r.timings, timing{name: s.name, self: s.total - s.downstream})
+
+		// The upstream is the handler following the deepest middleware.
+		r.upstream = 0
+		r.compressionSavings = 0
+		if s.called {
+			r.upstream = s.downstream
+			r.compressionSavings = s.downstreamBytes - r.responseBytes
+		}
+	})
+
+	if a.log {
+		logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), a.name, typeName))
+		logger.WithFields(logrus.Fields{
+			"requestBytes":       r.requestBytes,
+			"responseBytes":      r.responseBytes,
+			"compressionSavings": r.compressionSavings,
+			"serverTiming":       r.serverTiming(),
+		}).Info("Request accounting")
+		return
+	}
+
+	rw.Header().Set(RequestBytesTrailer, strconv.FormatInt(r.requestBytes, 10))
+	rw.Header().Set(ResponseBytesTrailer, strconv.FormatInt(r.responseBytes, 10))
+	rw.Header().Set(CompressionSavingsTrailer, strconv.FormatInt(r.compressionSavings, 10))
+	rw.Header().Set(ServerTimingTrailer, r.serverTiming())
+}
+
+type timing struct {
+	name string
+	self time.Duration
+}
+
+type report struct {
+	requestBytes       int64
+	responseBytes      int64
+	compressionSavings int64
+	timings            []timing
+	upstream           time.Duration
+	total              time.Duration
+}
+
+// serverTiming formats the timings following the Server-Timing header syntax.
+// Middleware names are put in the description, as they are not valid metric names.
+func (r report) serverTiming() string {
+	var parts []string
+	for _, t := range r.timings {
+		parts = append(parts, fmt.Sprintf("middleware;desc=%q;dur=%s", t.name, formatDuration(t.self)))
+	}
+
+	parts = append(parts,
+		"upstream;dur="+formatDuration(r.upstream),
+		"total;dur="+formatDuration(r.total),
+	)
+
+	return strings.Join(parts, ", ")
+}
+
+// formatDuration formats the duration in milliseconds.
+func formatDuration(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)
+}
@@ -0,0 +1,118 @@
+package accounting
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containous/alice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/middlewares/compress"
+)
+
+func TestAccounting(t *testing.T) {
+	body := strings.Repeat("traefik", 1000)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.Copy(io.Discard, req.Body)
+
+		rw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		rw.Header().Set("Content-Type", "text/plain")
+		_, _ = rw.Write([]byte(body))
+	})
+
+	accountingCtor := func(next http.Handler) (http.Handler, error) {
+		return New(context.Background(), next, dynamic.Accounting{}, "accounting", nil)
+	}
+	compressCtor := func(next http.Handler) (http.Handler, error) {
+		return compress.New(context.Background(), next, dynamic.Compress{}, "compress", nil)
+	}
+	slowCtor := func(next http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			time.Sleep(10 * time.Millisecond)
+			next.ServeHTTP(rw, req)
+		}), nil
+	}
+
+	handler, err := alice.New(
+		Wrap("accounting", accountingCtor),
+		Wrap("compress", compressCtor),
+		Wrap("slow", slowCtor),
+	).Then(next)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost", strings.NewReader("request"))
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	resp := recorder.Result()
+	assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+	assert.Empty(t, resp.Header.Get("Content-Length"))
+
+	assert.Equal(t, "7", resp.Trailer.Get(RequestBytesTrailer))
+
+	responseBytes, err := strconv.Atoi(resp.Trailer.Get(ResponseBytesTrailer))
+	require.NoError(t, err)
+	assert.Equal(t, recorder.Body.Len(), responseBytes)
+
+	savings, err := strconv.Atoi(resp.Trailer.Get(CompressionSavingsTrailer))
+	require.NoError(t, err)
+	assert.Equal(t, len(body)-responseBytes, savings)
+
+	serverTiming := resp.Trailer.Get(ServerTimingTrailer)
+	assert.Contains(t, serverTiming, `middleware;desc="compress";dur=`)
+	assert.Contains(t, serverTiming, `middleware;desc="slow";dur=1`)
+	assert.Contains(t, serverTiming, "upstream;dur=")
+	assert.Contains(t, serverTiming, "total;dur=")
+	assert.NotContains(t, serverTiming, `desc="accounting"`)
+}
+
+func TestAccounting_shortCircuit(t *testing.T) {
+	accountingCtor := func(next http.Handler) (http.Handler, error) {
+		return New(context.Background(), next, dynamic.Accounting{}, "accounting", nil)
+	}
+	denyCtor := func(next http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusForbidden)
+		}), nil
+	}
+
+	handler, err := alice.New(
+		Wrap("accounting", accountingCtor),
+		Wrap("deny", denyCtor),
+	).Then(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	resp := recorder.Result()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, "0", resp.Trailer.Get(RequestBytesTrailer))
+	assert.Equal(t, "0", resp.Trailer.Get(CompressionSavingsTrailer))
+	assert.Contains(t, resp.Trailer.Get(ServerTimingTrailer), "upstream;dur=0.000")
+}
+
+func TestAccounting_notSampled(t *testing.T) {
+	handler, err := New(context.Background(), http.NotFoundHandler(), dynamic.Accounting{SampleRate: 0.000001}, "accounting", nil)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Empty(t, recorder.Header().Values("Trailer"))
+}
+
+func TestNew_invalidSampleRate(t *testing.T) {
+	_, err := New(context.Background(), http.NotFoundHandler(), dynamic.Accounting{SampleRate: 2}, "accounting", nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,206 @@
+package accounting
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/containous/alice"
+)
+
+type key string
+
+const recordKey key = "accountingRecord"
+
+// record holds the accounting data of a sampled request.
+type record struct {
+	mu    sync.Mutex
+	spans []*span
+}
+
+// span holds the accounting data of a middleware for a given request.
+type span struct {
+	name string
+	// total is the time spent in the middleware, including the downstream handlers.
+	total time.Duration
+	// downstream is the time spent in the handlers following the middleware.
+	downstream time.Duration
+	// downstreamBytes is the number of bytes written by the handlers following the middleware.
+	downstreamBytes int64
+	// called is whether the middleware called the handlers following it.
+	called bool
+}
+
+func fromContext(ctx context.Context) *record {
+	rec, _ := ctx.Value(recordKey).(*record)
+	return rec
+}
+
+func (r *record) enter(name string) *span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := &span{name: name}
+	r.spans = append(r.spans, s)
+
+	return s
+}
+
+// lookup returns the last entered span of the given middleware.
+func (r *record) lookup(name string) *span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := len(r.spans) - 1; i >= 0; i-- {
+		if r.spans[i].name == name {
+			return r.spans[i]
+		}
+	}
+
+	return nil
+}
+
+func (r *record) done(fn func(s *span)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.spans {
+		fn(s)
+	}
+}
+
+// Wrap wraps the given middleware constructor so that the time it spends on the sampled requests,
+// and the bytes its downstream handlers write, are reported by the accounting middleware.
+func Wrap(name string, constructor alice.Constructor) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		handler, err := constructor(&downstreamHandler{name: name, next: next})
+		if err != nil {
+			return nil, err
+		}
+
+		return &timedHandler{name: name, next: handler}, nil
+	}
+}
+
+type timedHandler struct {
+	name string
+	next http.Handler
+}
+
+func (t *timedHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	rec := fromContext(req.Context())
+	if rec == nil {
+		t.next.ServeHTTP(rw, req)
+		return
+	}
+
+	s := rec.enter(t.name)
+	start := time.Now()
+
+	t.next.ServeHTTP(rw, req)
+
+	elapsed := time.Since(start)
+
+	rec.mu.Lock()
+	s.total += elapsed
+	rec.mu.Unlock()
+}
+
+type downstreamHandler struct {
+	name string
+	next http.Handler
+}
+
+func (d *downstreamHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	rec := fromContext(req.Context())
+	if rec == nil {
+		d.next.ServeHTTP(rw, req)
+		return
+	}
+
+	s := rec.lookup(d.name)
+	if s == nil {
+		d.next.ServeHTTP(rw, req)
+		return
+	}
+
+	crw := newCountingResponseWriter(rw, false)
+	start := time.Now()
+
+	d.next.ServeHTTP(crw, req)
+
+	elapsed := time.Since(start)
+
+	rec.mu.Lock()
+	s.called = true
+	s.downstream += elapsed
+	s.downstreamBytes += crw.size.Load()
+	rec.mu.Unlock()
+}
+
+type countingResponseWriter struct {
+	http.ResponseWriter
+	size atomic.Int64
+
+	// withTrailers removes the Content-Length header before writing the headers,
+	// as trailers are only sent with chunked responses on HTTP/1.1.
+	withTrailers bool
+	wroteHeader  bool
+}
+
+func newCountingResponseWriter(rw http.ResponseWriter, withTrailers bool) *countingResponseWriter {
+	return &countingResponseWriter{ResponseWriter: rw, withTrailers: withTrailers}
+}
+
+func (c *countingResponseWriter) WriteHeader(code int) {
+	if c.withTrailers && !c.wroteHeader && code >= http.StatusOK {
+		c.ResponseWriter.Header().Del("Content-Length")
+	}
+
+	if code >= http.StatusOK {
+		c.wroteHeader = true
+	}
+
+	c.ResponseWriter.WriteHeader(code)
+}
+
+func (c *countingResponseWriter) Write(b []byte) (int, error) {
+	if !c.wroteHeader {
+		c.WriteHeader(http.StatusOK)
+	}
+
+	n, err := c.ResponseWriter.Write(b)
+	c.size.Add(int64(n))
+	return n, err
+}
+
+func (c *countingResponseWriter) Flush() {
+	if f, ok := c.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (c *countingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := c.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", c.ResponseWriter)
+	}
+	return h.Hijack()
+}
+
+type countingReader struct {
+	io.ReadCloser
+	size atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.size.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,205 @@
+package adaptiveconcurrency
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const typeName = "AdaptiveConcurrency"
+
+// Names of the algorithms adjusting the limit.
+const (
+	AlgorithmAIMD     = "aimd"
+	AlgorithmGradient = "gradient"
+)
+
+type adaptiveConcurrency struct {
+	next       http.Handler
+	name       string
+	retryAfter time.Duration
+	minLimit   float64
+	maxLimit   float64
+
+	mu        sync.Mutex
+	algorithm limitAlgorithm
+	limit     float64
+	inFlight  int64
+}
+
+// New creates a new adaptive concurrency middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.AdaptiveConcurrency, name string) (http.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	minLimit := config.MinLimit
+	if minLimit == 0 {
+		minLimit = 1
+	}
+
+	maxLimit := config.MaxLimit
+	if maxLimit == 0 {
+		maxLimit = 1000
+	}
+
+	initialLimit := config.InitialLimit
+	if initialLimit == 0 {
+		initialLimit = 10
+	}
+
+	if minLimit < 0 || maxLimit < minLimit {
+		return nil, fmt.Errorf("invalid limits: min %d, max %d", minLimit, maxLimit)
+	}
+
+	if initialLimit < minLimit {
+		initialLimit = minLimit
+	}
+	if initialLimit > maxLimit {
+		initialLimit = maxLimit
+	}
+
+	retryAfter := time.Duration(config.RetryAfter)
+	if retryAfter <= 0 {
+		retryAfter = time.Second
+	}
+
+	var algorithm limitAlgorithm
+	switch config.Algorithm {
+	case "", AlgorithmAIMD:
+		latencyThreshold := time.Duration(config.LatencyThreshold)
+		if latencyThreshold <= 0 {
+			latencyThreshold = time.Second
+		}
+
+		backoffRatio := config.BackoffRatio
+		if backoffRatio == 0 {
+			backoffRatio = 0.9
+		}
+		if backoffRatio <= 0 || backoffRatio >= 1 {
+			return nil, fmt.Errorf("backoff ratio must be between 0 and 1, got %v", backoffRatio)
+		}
+
+		algorithm = &aimd{latencyThreshold: latencyThreshold, backoffRatio: backoffRatio}
+
+	case AlgorithmGradient:
+		tolerance := config.Tolerance
+		if tolerance == 0 {
+			tolerance = 1.5
+		}
+		if tolerance < 1 {
+			return nil, fmt.Errorf("tolerance must be greater than or equal to 1, got %v", tolerance)
+		}
+
+		algorithm = &gradient{tolerance: tolerance}
+
+	default:
+		return nil, fmt.Errorf("unknown algorithm %q", config.Algorithm)
+	}
+
+	return &adaptiveConcurrency{
+		next:       next,
+		name:       name,
+		retryAfter: retryAfter,
+		minLimit:   float64(minLimit),
+		maxLimit:   float64(maxLimit),
+		algorithm:  algorithm,
+		limit:      float64(initialLimit),
+	}, nil
+}
+
+func (a *adaptiveConcurrency) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return a.name, tracing.SpanKindNoneEnum
+}
+
+func (a *adaptiveConcurrency) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	inFlight, ok := a.acquire()
+	if !ok {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), a.name, typeName)).Debug("Concurrency limit reached, shedding the request")
+
+		rw.Header().Set("Retry-After", fmt.Sprintf("%.0f", math.Ceil(a.retryAfter.Seconds())))
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	srw := &statusResponseWriter{ResponseWriter: rw, status: http.StatusOK}
+	start := time.Now()
+
+	a.next.ServeHTTP(srw, req)
+
+	// Requests canceled by the clients do not tell anything about the backend.
+	canceled := req.Context().Err() != nil
+	a.release(inFlight, time.Since(start), srw.status >= http.StatusInternalServerError, canceled)
+}
+
+// acquire reserves a slot for a request,
+// and returns the number of in-flight requests, including this one.
+func (a *adaptiveConcurrency) acquire() (int64, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if float64(a.inFlight) >= math.Floor(a.limit) {
+		return 0, false
+	}
+
+	a.inFlight++
+	return a.inFlight, true
+}
+
+func (a *adaptiveConcurrency) release(inFlight int64, latency time.Duration, failed, canceled bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.inFlight--
+
+	if canceled {
+		return
+	}
+
+	limit := a.algorithm.update(a.limit, inFlight, latency, failed)
+	a.limit = math.Max(a.minLimit, math.Min(a.maxLimit, limit))
+}
+
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (s *statusResponseWriter) WriteHeader(code int) {
+	if !s.wroteHeader && code >= http.StatusOK {
+		s.status = code
+		s.wroteHeader = true
+	}
+
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusResponseWriter) Write(b []byte) (int, error) {
+	s.wroteHeader = true
+	return s.ResponseWriter.Write(b)
+}
+
+func (s *statusResponseWriter) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (s *statusResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", s.ResponseWriter)
+	}
+	return h.Hijack()
+}
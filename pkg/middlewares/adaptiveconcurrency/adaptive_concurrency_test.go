@@ -0,0 +1,152 @@
+package adaptiveconcurrency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestAdaptiveConcurrency_shedding(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 3)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		started <- struct{}{}
+		<-release
+	})
+
+	handler, err := New(context.Background(), next, dynamic.AdaptiveConcurrency{
+		InitialLimit: 2,
+		RetryAfter:   ptypes.Duration(1500 * time.Millisecond),
+	}, "adaptive")
+	require.NoError(t, err)
+
+	var done sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+		}()
+	}
+
+	<-started
+	<-started
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "2", recorder.Header().Get("Retry-After"))
+
+	close(release)
+	done.Wait()
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestAdaptiveConcurrency_limit(t *testing.T) {
+	var status int
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(status)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.AdaptiveConcurrency{
+		InitialLimit: 2,
+		MinLimit:     1,
+		MaxLimit:     3,
+		BackoffRatio: 0.5,
+	}, "adaptive")
+	require.NoError(t, err)
+
+	a := handler.(*adaptiveConcurrency)
+
+	status = http.StatusOK
+	for i := 0; i < 5; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	}
+
+	assert.Equal(t, float64(3), a.limit)
+
+	status = http.StatusBadGateway
+	for i := 0; i < 5; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	}
+
+	assert.Equal(t, float64(1), a.limit)
+}
+
+func TestAIMD(t *testing.T) {
+	algorithm := &aimd{latencyThreshold: 100 * time.Millisecond, backoffRatio: 0.5}
+
+	assert.Equal(t, float64(11), algorithm.update(10, 5, 10*time.Millisecond, false))
+	assert.Equal(t, float64(10), algorithm.update(10, 1, 10*time.Millisecond, false))
+	assert.Equal(t, float64(5), algorithm.update(10, 5, time.Second, false))
+	assert.Equal(t, float64(5), algorithm.update(10, 5, 10*time.Millisecond, true))
+}
+
+func TestGradient(t *testing.T) {
+	algorithm := &gradient{tolerance: 1.5}
+
+	limit := float64(10)
+	for i := 0; i < 10; i++ {
+		limit = algorithm.update(limit, int64(limit), 10*time.Millisecond, false)
+	}
+
+	assert.Greater(t, limit, float64(10))
+
+	grown := limit
+	for i := 0; i < 10; i++ {
+		limit = algorithm.update(limit, int64(limit), 100*time.Millisecond, false)
+	}
+
+	assert.Less(t, limit, grown)
+
+	// The limit does not change when it is not used.
+	assert.Equal(t, limit, algorithm.update(limit, 1, time.Second, false))
+}
+
+func TestNew_invalidConfig(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.AdaptiveConcurrency
+	}{
+		{
+			desc:   "unknown algorithm",
+			config: dynamic.AdaptiveConcurrency{Algorithm: "foo"},
+		},
+		{
+			desc:   "max limit lower than min limit",
+			config: dynamic.AdaptiveConcurrency{MinLimit: 10, MaxLimit: 5},
+		},
+		{
+			desc:   "invalid backoff ratio",
+			config: dynamic.AdaptiveConcurrency{BackoffRatio: 2},
+		},
+		{
+			desc:   "invalid tolerance",
+			config: dynamic.AdaptiveConcurrency{Algorithm: AlgorithmGradient, Tolerance: 0.5},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.config, "adaptive")
+			require.Error(t, err)
+		})
+	}
+}
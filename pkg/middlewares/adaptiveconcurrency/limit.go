@@ -0,0 +1,83 @@
+package adaptiveconcurrency
+
+import (
+	"math"
+	"time"
+)
+
+// limitAlgorithm computes the new concurrency limit from the outcome of a request.
+// It is not safe for concurrent use.
+type limitAlgorithm interface {
+	// update returns the new limit, given the current one, the number of in-flight requests when the request started,
+	// the latency of the request, and whether it failed.
+	update(limit float64, inFlight int64, latency time.Duration, failed bool) float64
+}
+
+// aimd increases the limit additively while the latency stays below a threshold,
+// and decreases it multiplicatively as soon as it goes above it, or a request fails.
+type aimd struct {
+	latencyThreshold time.Duration
+	backoffRatio     float64
+}
+
+func (a *aimd) update(limit float64, inFlight int64, latency time.Duration, failed bool) float64 {
+	if failed || latency > a.latencyThreshold {
+		return limit * a.backoffRatio
+	}
+
+	// The limit is only increased when it is actually used,
+	// otherwise it would grow indefinitely under a low load.
+	if float64(inFlight)*2 >= limit {
+		return limit + 1
+	}
+
+	return limit
+}
+
+const (
+	// gradientLongWindow is the number of samples the long-term latency is averaged on.
+	gradientLongWindow = 100
+	// gradientSmoothing is the factor applied to the change of the limit.
+	gradientSmoothing = 0.2
+	// gradientMin is the minimum gradient, bounding how fast the limit is decreased.
+	gradientMin = 0.5
+)
+
+// gradient adjusts the limit with the ratio between the long-term latency and the latency of the request:
+// the limit decreases as the latency grows above the long-term one, as it reveals requests queueing in the backend.
+type gradient struct {
+	tolerance float64
+	longRTT   float64
+}
+
+func (g *gradient) update(limit float64, inFlight int64, latency time.Duration, failed bool) float64 {
+	rtt := float64(latency)
+
+	if g.longRTT == 0 {
+		g.longRTT = rtt
+	} else {
+		factor := 2. / (gradientLongWindow + 1)
+		g.longRTT = g.longRTT*(1-factor) + rtt*factor
+	}
+
+	// The long-term latency quickly recovers after a latency spike,
+	// to avoid keeping the limit low once the backend is back to normal.
+	if g.longRTT/rtt > 2 {
+		g.longRTT *= 0.95
+	}
+
+	// The latency of a backend that is not loaded does not tell anything about its limit.
+	if !failed && float64(inFlight)*2 < limit {
+		return limit
+	}
+
+	grad := gradientMin
+	if !failed && rtt > 0 {
+		grad = math.Max(gradientMin, math.Min(1, g.tolerance*g.longRTT/rtt))
+	}
+
+	// The square root of the limit allows some queueing, which lets the limit grow.
+	newLimit := limit*grad + math.Sqrt(limit)
+
+	return limit*(1-gradientSmoothing) + newLimit*gradientSmoothing
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
@@ -16,6 +17,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
 	"github.com/traefik/traefik/v2/pkg/middlewares/connectionheader"
+	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
 	"github.com/traefik/traefik/v2/pkg/tracing"
 	"github.com/vulcand/oxy/v2/forward"
 	"github.com/vulcand/oxy/v2/utils"
@@ -51,7 +53,8 @@ type forwardAuth struct {
 }
 
 // NewForward creates a forward auth middleware.
-func NewForward(ctx context.Context, next http.Handler, config dynamic.ForwardAuth, name string) (http.Handler, error) {
+// With fips, the TLS connections to the authentication server are restricted to the FIPS-approved parameters.
+func NewForward(ctx context.Context, next http.Handler, config dynamic.ForwardAuth, name string, fips bool) (http.Handler, error) {
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, forwardedTypeName)).Debug("Creating middleware")
 
 	fa := &forwardAuth{
@@ -71,10 +74,18 @@ func NewForward(ctx context.Context, next http.Handler, config dynamic.ForwardAu
 		Timeout: 30 * time.Second,
 	}
 
-	if config.TLS != nil {
-		tlsConfig, err := config.TLS.CreateTLSConfig(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("unable to create client TLS configuration: %w", err)
+	if config.TLS != nil || fips {
+		tlsConfig := &tls.Config{}
+		if config.TLS != nil {
+			var err error
+			tlsConfig, err = config.TLS.CreateTLSConfig(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("unable to create client TLS configuration: %w", err)
+			}
+		}
+
+		if fips {
+			traefiktls.RestrictToFIPS(tlsConfig)
 		}
 
 		tr := http.DefaultTransport.(*http.Transport).Clone()
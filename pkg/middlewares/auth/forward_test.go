@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,6 +17,7 @@ import (
 	tracingMiddleware "github.com/traefik/traefik/v2/pkg/middlewares/tracing"
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
 	"github.com/traefik/traefik/v2/pkg/tracing"
+	"github.com/traefik/traefik/v2/pkg/types"
 	"github.com/vulcand/oxy/v2/forward"
 )
 
@@ -32,7 +34,7 @@ func TestForwardAuthFail(t *testing.T) {
 
 	middleware, err := NewForward(context.Background(), next, dynamic.ForwardAuth{
 		Address: server.URL,
-	}, "authTest")
+	}, "authTest", false)
 	require.NoError(t, err)
 
 	ts := httptest.NewServer(middleware)
@@ -77,7 +79,7 @@ func TestForwardAuthSuccess(t *testing.T) {
 		AuthResponseHeaders:      []string{"X-Auth-User", "X-Auth-Group"},
 		AuthResponseHeadersRegex: "^Foo-",
 	}
-	middleware, err := NewForward(context.Background(), next, auth, "authTest")
+	middleware, err := NewForward(context.Background(), next, auth, "authTest", false)
 	require.NoError(t, err)
 
 	ts := httptest.NewServer(middleware)
@@ -98,6 +100,56 @@ func TestForwardAuthSuccess(t *testing.T) {
 	assert.Equal(t, "traefik\n", string(body))
 }
 
+func TestForwardAuthFIPS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "Success")
+	}))
+	// The authentication server only accepts a cipher suite which is not FIPS-approved.
+	server.TLS = &tls.Config{
+		MaxVersion:   tls.VersionTLS12,
+		CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305},
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	testCases := []struct {
+		desc           string
+		fips           bool
+		expectedStatus int
+	}{
+		{
+			desc:           "without FIPS",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "with FIPS",
+			fips:           true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, "traefik")
+			})
+
+			auth := dynamic.ForwardAuth{
+				Address: server.URL,
+				TLS:     &types.ClientTLS{InsecureSkipVerify: true},
+			}
+			middleware, err := NewForward(context.Background(), next, auth, "authTest", test.fips)
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+			middleware.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://foo", nil))
+
+			assert.Equal(t, test.expectedStatus, rw.Code)
+		})
+	}
+}
+
 func TestForwardAuthRedirect(t *testing.T) {
 	authTs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "http://example.com/redirect-test", http.StatusFound)
@@ -110,7 +162,7 @@ func TestForwardAuthRedirect(t *testing.T) {
 
 	auth := dynamic.ForwardAuth{Address: authTs.URL}
 
-	authMiddleware, err := NewForward(context.Background(), next, auth, "authTest")
+	authMiddleware, err := NewForward(context.Background(), next, auth, "authTest", false)
 	require.NoError(t, err)
 
 	ts := httptest.NewServer(authMiddleware)
@@ -161,7 +213,7 @@ func TestForwardAuthRemoveHopByHopHeaders(t *testing.T) {
 
 	auth := dynamic.ForwardAuth{Address: authTs.URL}
 
-	authMiddleware, err := NewForward(context.Background(), next, auth, "authTest")
+	authMiddleware, err := NewForward(context.Background(), next, auth, "authTest", false)
 	require.NoError(t, err)
 
 	ts := httptest.NewServer(authMiddleware)
@@ -207,7 +259,7 @@ func TestForwardAuthFailResponseHeaders(t *testing.T) {
 	auth := dynamic.ForwardAuth{
 		Address: authTs.URL,
 	}
-	authMiddleware, err := NewForward(context.Background(), next, auth, "authTest")
+	authMiddleware, err := NewForward(context.Background(), next, auth, "authTest", false)
 	require.NoError(t, err)
 
 	ts := httptest.NewServer(authMiddleware)
@@ -469,7 +521,7 @@ func TestForwardAuthUsesTracing(t *testing.T) {
 
 	tr, _ := tracing.NewTracing("testApp", 100, &mockBackend{tracer})
 
-	next, err := NewForward(context.Background(), next, auth, "authTest")
+	next, err := NewForward(context.Background(), next, auth, "authTest", false)
 	require.NoError(t, err)
 
 	next = tracingMiddleware.NewEntryPoint(context.Background(), tr, "tracingTest", next)
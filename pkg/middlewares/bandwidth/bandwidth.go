@@ -0,0 +1,88 @@
+package bandwidth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/containous/alice"
+	"github.com/traefik/traefik/v2/pkg/bandwidth"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/capture"
+)
+
+const (
+	typeName   = "Bandwidth"
+	nameRouter = "bandwidth-router"
+)
+
+type bandwidthMiddleware struct {
+	next       http.Handler
+	captured   http.Handler
+	accountant *bandwidth.Accountant
+	key        bandwidth.Key
+}
+
+// New creates a new middleware accounting the bytes transferred by a router.
+func New(ctx context.Context, next http.Handler, accountant *bandwidth.Accountant, key bandwidth.Key) http.Handler {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, nameRouter, typeName)).Debug("Creating middleware")
+
+	m := &bandwidthMiddleware{
+		next:       next,
+		accountant: accountant,
+		key:        key,
+	}
+
+	// capture.Wrap never returns an error.
+	m.captured, _ = capture.Wrap(http.HandlerFunc(m.serveCaptured))
+
+	return m
+}
+
+// WrapRouterHandler Wraps bytes accounting to alice.Constructor.
+func WrapRouterHandler(ctx context.Context, accountant *bandwidth.Accountant, key bandwidth.Key) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return New(ctx, next, accountant, key), nil
+	}
+}
+
+func (m *bandwidthMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The capture middleware is only added at the entry point level when metrics or access logs are enabled.
+	if _, err := capture.FromContext(req.Context()); err != nil {
+		m.captured.ServeHTTP(rw, req)
+		return
+	}
+
+	m.serveCaptured(rw, req)
+}
+
+func (m *bandwidthMiddleware) serveCaptured(rw http.ResponseWriter, req *http.Request) {
+	capt, err := capture.FromContext(req.Context())
+	if err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), nameRouter, typeName)).WithError(err).Errorf("Could not get Capture")
+		m.next.ServeHTTP(rw, req)
+		return
+	}
+
+	next := m.next
+	if capt.NeedsReset(rw) {
+		next = capt.Reset(m.next)
+	}
+
+	ctx, logicalSize := bandwidth.WithLogicalSize(req.Context())
+
+	next.ServeHTTP(rw, req.WithContext(ctx))
+
+	// The requests are not decompressed, and the responses are only compressed by the compress middleware.
+	usage := bandwidth.Usage{
+		WireRequestBytes:     capt.RequestSize(),
+		WireResponseBytes:    capt.ResponseSize(),
+		LogicalRequestBytes:  capt.RequestSize(),
+		LogicalResponseBytes: capt.ResponseSize(),
+	}
+	if n, ok := logicalSize.Bytes(); ok {
+		usage.LogicalResponseBytes = n
+	}
+
+	m.accountant.Observe(m.key, usage)
+}
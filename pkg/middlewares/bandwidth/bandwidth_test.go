@@ -0,0 +1,92 @@
+package bandwidth
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/bandwidth"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/middlewares/capture"
+	"github.com/traefik/traefik/v2/pkg/middlewares/compress"
+)
+
+func TestBandwidthMiddleware(t *testing.T) {
+	body := strings.Repeat("traefik", 1000)
+
+	testCases := []struct {
+		desc           string
+		withCapture    bool
+		acceptEncoding string
+		compressed     bool
+	}{
+		{
+			desc: "without capture",
+		},
+		{
+			desc:        "with capture at the entry point",
+			withCapture: true,
+		},
+		{
+			desc:           "compressed response",
+			acceptEncoding: "gzip",
+			compressed:     true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			accountant := bandwidth.NewAccountant(nil, 0, 0)
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				_, _ = io.Copy(io.Discard, req.Body)
+				rw.Header().Set("Content-Type", "text/plain")
+				_, _ = rw.Write([]byte(body))
+			})
+
+			compressHandler, err := compress.New(context.Background(), next, dynamic.Compress{}, "compress", nil)
+			require.NoError(t, err)
+
+			key := bandwidth.Key{Router: "foo@file", Service: "foo@file", Tenant: "acme"}
+
+			var handler http.Handler = New(context.Background(), compressHandler, accountant, key)
+			if test.withCapture {
+				handler, err = capture.Wrap(handler)
+				require.NoError(t, err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "http://foo.bar", strings.NewReader("request"))
+			req.Header.Set("Accept-Encoding", test.acceptEncoding)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			records := accountant.Records()
+			require.Len(t, records, 1)
+
+			record := records[0]
+			assert.Equal(t, "foo@file", record.Router)
+			assert.Equal(t, "foo@file", record.Service)
+			assert.Equal(t, "acme", record.Tenant)
+			assert.Equal(t, int64(len("request")), record.WireRequestBytes)
+			assert.Equal(t, int64(len("request")), record.LogicalRequestBytes)
+			assert.Equal(t, int64(recorder.Body.Len()), record.WireResponseBytes)
+			assert.Equal(t, int64(len(body)), record.LogicalResponseBytes)
+
+			if test.compressed {
+				assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+				assert.Less(t, record.WireResponseBytes, record.LogicalResponseBytes)
+			} else {
+				assert.Equal(t, record.LogicalResponseBytes, record.WireResponseBytes)
+			}
+		})
+	}
+}
@@ -9,9 +9,11 @@ import (
 
 	"github.com/klauspost/compress/gzhttp"
 	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/bandwidth"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/overrides"
 	"github.com/traefik/traefik/v2/pkg/tracing"
 )
 
@@ -26,10 +28,13 @@ type compress struct {
 	excludes         []string
 	minSize          int
 	compressionLevel int
+
+	// overrides holds the compression level set at runtime through the API, which takes precedence over the configured one.
+	overrides *overrides.Store
 }
 
 // New creates a new compress middleware.
-func New(ctx context.Context, next http.Handler, conf dynamic.Compress, name string) (http.Handler, error) {
+func New(ctx context.Context, next http.Handler, conf dynamic.Compress, name string, overridesStore *overrides.Store) (http.Handler, error) {
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
 
 	excludes := []string{"application/grpc"}
@@ -52,7 +57,7 @@ func New(ctx context.Context, next http.Handler, conf dynamic.Compress, name str
 		compressionLevel = conf.CompressionLevel
 	}
 
-	return &compress{next: next, name: name, excludes: excludes, minSize: minSize, compressionLevel: compressionLevel}, nil
+	return &compress{next: next, name: name, excludes: excludes, minSize: minSize, compressionLevel: compressionLevel, overrides: overridesStore}, nil
 }
 
 func (c *compress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
@@ -64,8 +69,13 @@ func (c *compress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	if slices.Contains(c.excludes, mediaType) {
 		c.next.ServeHTTP(rw, req)
 	} else {
+		compressionLevel := c.compressionLevel
+		if params, ok := c.overrides.Get(c.name); ok && params.Compress != nil {
+			compressionLevel = params.Compress.CompressionLevel
+		}
+
 		ctx := middlewares.GetLoggerCtx(req.Context(), c.name, typeName)
-		c.gzipHandler(ctx).ServeHTTP(rw, req)
+		c.gzipHandler(ctx, compressionLevel, bandwidth.LogicalSizeFromContext(req.Context())).ServeHTTP(rw, req)
 	}
 }
 
@@ -73,14 +83,22 @@ func (c *compress) GetTracingInformation() (string, ext.SpanKindEnum) {
 	return c.name, tracing.SpanKindNoneEnum
 }
 
-func (c *compress) gzipHandler(ctx context.Context) http.Handler {
+// gzipHandler returns the compressing handler, with the given compression level,
+// counting the bytes written before the compression in logicalSize, when not nil.
+func (c *compress) gzipHandler(ctx context.Context, compressionLevel int, logicalSize *bandwidth.LogicalSize) http.Handler {
 	wrapper, err := gzhttp.NewWrapper(
 		gzhttp.ExceptContentTypes(c.excludes),
-		gzhttp.CompressionLevel(c.compressionLevel),
+		gzhttp.CompressionLevel(compressionLevel),
 		gzhttp.MinSize(c.minSize))
 	if err != nil {
 		log.FromContext(ctx).Error(err)
 	}
 
-	return wrapper(c.next)
+	if logicalSize == nil {
+		return wrapper(c.next)
+	}
+
+	return wrapper(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		c.next.ServeHTTP(logicalSize.Wrap(rw), req)
+	}))
 }
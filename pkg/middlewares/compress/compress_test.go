@@ -34,7 +34,7 @@ func TestShouldCompressWhenNoContentEncodingHeader(t *testing.T) {
 		_, err := rw.Write(baseBody)
 		assert.NoError(t, err)
 	})
-	handler, err := New(context.Background(), next, dynamic.Compress{}, "testing")
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "testing", nil)
 	require.NoError(t, err)
 
 	rw := httptest.NewRecorder()
@@ -61,7 +61,7 @@ func TestShouldNotCompressWhenContentEncodingHeader(t *testing.T) {
 			http.Error(rw, err.Error(), http.StatusInternalServerError)
 		}
 	})
-	handler, err := New(context.Background(), next, dynamic.Compress{}, "testing")
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "testing", nil)
 	require.NoError(t, err)
 
 	rw := httptest.NewRecorder()
@@ -83,7 +83,7 @@ func TestShouldNotCompressWhenNoAcceptEncodingHeader(t *testing.T) {
 			http.Error(rw, err.Error(), http.StatusInternalServerError)
 		}
 	})
-	handler, err := New(context.Background(), next, dynamic.Compress{}, "testing")
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "testing", nil)
 	require.NoError(t, err)
 
 	rw := httptest.NewRecorder()
@@ -145,7 +145,7 @@ func TestShouldNotCompressWhenSpecificContentType(t *testing.T) {
 				}
 			})
 
-			handler, err := New(context.Background(), next, test.conf, "test")
+			handler, err := New(context.Background(), next, test.conf, "test", nil)
 			require.NoError(t, err)
 
 			rw := httptest.NewRecorder()
@@ -195,7 +195,7 @@ func TestIntegrationShouldNotCompress(t *testing.T) {
 
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
-			compress, err := New(context.Background(), test.handler, dynamic.Compress{}, "testing")
+			compress, err := New(context.Background(), test.handler, dynamic.Compress{}, "testing", nil)
 			require.NoError(t, err)
 
 			ts := httptest.NewServer(compress)
@@ -230,7 +230,7 @@ func TestShouldWriteHeaderWhenFlush(t *testing.T) {
 			http.Error(rw, err.Error(), http.StatusInternalServerError)
 		}
 	})
-	handler, err := New(context.Background(), next, dynamic.Compress{}, "testing")
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "testing", nil)
 	require.NoError(t, err)
 
 	ts := httptest.NewServer(handler)
@@ -281,7 +281,7 @@ func TestIntegrationShouldCompress(t *testing.T) {
 
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
-			compress, err := New(context.Background(), test.handler, dynamic.Compress{}, "testing")
+			compress, err := New(context.Background(), test.handler, dynamic.Compress{}, "testing", nil)
 			require.NoError(t, err)
 
 			ts := httptest.NewServer(compress)
@@ -340,7 +340,7 @@ func TestMinResponseBodyBytes(t *testing.T) {
 				}
 			})
 
-			handler, err := New(context.Background(), next, dynamic.Compress{MinResponseBodyBytes: test.minResponseBodyBytes}, "testing")
+			handler, err := New(context.Background(), next, dynamic.Compress{MinResponseBodyBytes: test.minResponseBodyBytes}, "testing", nil)
 			require.NoError(t, err)
 
 			rw := httptest.NewRecorder()
@@ -376,7 +376,7 @@ func Test1xxResponses(t *testing.T) {
 		}
 	})
 
-	compress, err := New(context.Background(), next, dynamic.Compress{MinResponseBodyBytes: 1024}, "testing")
+	compress, err := New(context.Background(), next, dynamic.Compress{MinResponseBodyBytes: 1024}, "testing", nil)
 	require.NoError(t, err)
 
 	server := httptest.NewServer(compress)
@@ -481,7 +481,7 @@ func BenchmarkCompress(b *testing.B) {
 				_, err := rw.Write(baseBody)
 				assert.NoError(b, err)
 			})
-			handler, _ := New(context.Background(), next, dynamic.Compress{}, "testing")
+			handler, _ := New(context.Background(), next, dynamic.Compress{}, "testing", nil)
 
 			req, _ := http.NewRequest(http.MethodGet, "/whatever", nil)
 			req.Header.Set("Accept-Encoding", "gzip")
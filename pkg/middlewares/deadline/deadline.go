@@ -0,0 +1,224 @@
+package deadline
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "Deadline"
+
+	// DefaultHeader is the default name of the header carrying the budget.
+	DefaultHeader = "X-Deadline"
+
+	grpcTimeoutHeader = "Grpc-Timeout"
+)
+
+type deadline struct {
+	next       http.Handler
+	name       string
+	timeout    time.Duration
+	maxTimeout time.Duration
+	header     string
+}
+
+// New creates a new deadline middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Deadline, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if config.Timeout < 0 {
+		return nil, fmt.Errorf("timeout must be positive, got %s", time.Duration(config.Timeout))
+	}
+
+	if config.MaxTimeout < 0 {
+		return nil, fmt.Errorf("max timeout must be positive, got %s", time.Duration(config.MaxTimeout))
+	}
+
+	header := config.Header
+	if header == "" {
+		header = DefaultHeader
+	}
+
+	return &deadline{
+		next:       next,
+		name:       name,
+		timeout:    time.Duration(config.Timeout),
+		maxTimeout: time.Duration(config.MaxTimeout),
+		header:     header,
+	}, nil
+}
+
+func (d *deadline) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return d.name, tracing.SpanKindNoneEnum
+}
+
+func (d *deadline) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), d.name, typeName))
+
+	budget, found := d.requestBudget(req)
+	if !found {
+		budget = d.timeout
+		if budget == 0 {
+			d.next.ServeHTTP(rw, req)
+			return
+		}
+	}
+
+	if d.maxTimeout > 0 && budget > d.maxTimeout {
+		budget = d.maxTimeout
+	}
+
+	// The budget is already exhausted, there is no point in forwarding the request.
+	if budget <= 0 {
+		logger.Debug("Latency budget exhausted before forwarding the request")
+		rw.WriteHeader(http.StatusGatewayTimeout)
+		_, _ = rw.Write([]byte(http.StatusText(http.StatusGatewayTimeout)))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), budget)
+	defer cancel()
+
+	req.Header.Set(d.header, strconv.FormatInt(budget.Milliseconds(), 10))
+	if isGRPC(req) {
+		req.Header.Set(grpcTimeoutHeader, encodeGRPCTimeout(budget))
+	}
+
+	drw := &responseWriter{ResponseWriter: rw}
+	d.next.ServeHTTP(drw, req.WithContext(ctx))
+
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return
+	}
+
+	logger.Debugf("Latency budget of %s exceeded", budget)
+
+	if !drw.wroteHeader {
+		rw.WriteHeader(http.StatusGatewayTimeout)
+		_, _ = rw.Write([]byte(http.StatusText(http.StatusGatewayTimeout)))
+	}
+}
+
+// requestBudget returns the budget carried by the request, if any.
+func (d *deadline) requestBudget(req *http.Request) (time.Duration, bool) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), d.name, typeName))
+
+	if value := req.Header.Get(d.header); value != "" {
+		ms, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err == nil {
+			return time.Duration(ms) * time.Millisecond, true
+		}
+
+		logger.Debugf("Invalid latency budget in header %s: %q", d.header, value)
+	}
+
+	if value := req.Header.Get(grpcTimeoutHeader); value != "" && isGRPC(req) {
+		timeout, err := decodeGRPCTimeout(value)
+		if err == nil {
+			return timeout, true
+		}
+
+		logger.Debugf("Invalid latency budget in header %s: %v", grpcTimeoutHeader, err)
+	}
+
+	return 0, false
+}
+
+func isGRPC(req *http.Request) bool {
+	return strings.HasPrefix(req.Header.Get("Content-Type"), "application/grpc")
+}
+
+var grpcTimeoutUnits = []struct {
+	unit     byte
+	duration time.Duration
+}{
+	{unit: 'n', duration: time.Nanosecond},
+	{unit: 'u', duration: time.Microsecond},
+	{unit: 'm', duration: time.Millisecond},
+	{unit: 'S', duration: time.Second},
+	{unit: 'M', duration: time.Minute},
+	{unit: 'H', duration: time.Hour},
+}
+
+// grpcTimeoutMaxValue is the maximum value of a grpc-timeout header, which is at most 8 digits long.
+const grpcTimeoutMaxValue = 1e8 - 1
+
+// encodeGRPCTimeout encodes the timeout following the grpc-timeout header syntax,
+// using the most precise unit holding the timeout in 8 digits.
+func encodeGRPCTimeout(timeout time.Duration) string {
+	for _, u := range grpcTimeoutUnits {
+		// The timeout is rounded up, as the backend should not give up before the deadline.
+		value := (timeout + u.duration - 1) / u.duration
+		if value <= grpcTimeoutMaxValue {
+			return strconv.FormatInt(int64(value), 10) + string(u.unit)
+		}
+	}
+
+	return strconv.Itoa(grpcTimeoutMaxValue) + "H"
+}
+
+// decodeGRPCTimeout decodes a grpc-timeout header value.
+func decodeGRPCTimeout(value string) (time.Duration, error) {
+	if len(value) < 2 || len(value) > 9 {
+		return 0, fmt.Errorf("invalid grpc-timeout %q", value)
+	}
+
+	amount, err := strconv.ParseInt(value[:len(value)-1], 10, 64)
+	if err != nil || amount < 0 {
+		return 0, fmt.Errorf("invalid grpc-timeout %q", value)
+	}
+
+	for _, u := range grpcTimeoutUnits {
+		if u.unit == value[len(value)-1] {
+			return time.Duration(amount) * u.duration, nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid grpc-timeout unit in %q", value)
+}
+
+// responseWriter tracks whether the response headers have been written.
+type responseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (r *responseWriter) WriteHeader(code int) {
+	if code >= http.StatusOK {
+		r.wroteHeader = true
+	}
+
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseWriter) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseWriter) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", r.ResponseWriter)
+	}
+	return h.Hijack()
+}
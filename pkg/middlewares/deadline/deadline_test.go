@@ -0,0 +1,165 @@
+package deadline
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestDeadline(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          dynamic.Deadline
+		reqHeaders      map[string]string
+		handlerDelay    time.Duration
+		expectedCode    int
+		expectedHeaders map[string]string
+		expectedCalled  bool
+	}{
+		{
+			desc:           "no budget",
+			expectedCode:   http.StatusOK,
+			expectedCalled: true,
+		},
+		{
+			desc:            "budget from config",
+			config:          dynamic.Deadline{Timeout: ptypes.Duration(time.Second)},
+			expectedCode:    http.StatusOK,
+			expectedHeaders: map[string]string{DefaultHeader: "1000"},
+			expectedCalled:  true,
+		},
+		{
+			desc:            "budget from header",
+			config:          dynamic.Deadline{Timeout: ptypes.Duration(time.Second)},
+			reqHeaders:      map[string]string{DefaultHeader: "200"},
+			expectedCode:    http.StatusOK,
+			expectedHeaders: map[string]string{DefaultHeader: "200"},
+			expectedCalled:  true,
+		},
+		{
+			desc:            "budget from custom header",
+			config:          dynamic.Deadline{Header: "X-Budget"},
+			reqHeaders:      map[string]string{"X-Budget": "200"},
+			expectedCode:    http.StatusOK,
+			expectedHeaders: map[string]string{"X-Budget": "200"},
+			expectedCalled:  true,
+		},
+		{
+			desc:            "budget from header capped",
+			config:          dynamic.Deadline{MaxTimeout: ptypes.Duration(100 * time.Millisecond)},
+			reqHeaders:      map[string]string{DefaultHeader: "200"},
+			expectedCode:    http.StatusOK,
+			expectedHeaders: map[string]string{DefaultHeader: "100"},
+			expectedCalled:  true,
+		},
+		{
+			desc:            "invalid budget in header",
+			config:          dynamic.Deadline{Timeout: ptypes.Duration(time.Second)},
+			reqHeaders:      map[string]string{DefaultHeader: "foo"},
+			expectedCode:    http.StatusOK,
+			expectedHeaders: map[string]string{DefaultHeader: "1000"},
+			expectedCalled:  true,
+		},
+		{
+			desc:            "budget from grpc-timeout",
+			reqHeaders:      map[string]string{"Content-Type": "application/grpc", grpcTimeoutHeader: "2S"},
+			expectedCode:    http.StatusOK,
+			expectedHeaders: map[string]string{DefaultHeader: "2000", grpcTimeoutHeader: "2000000u"},
+			expectedCalled:  true,
+		},
+		{
+			desc:           "grpc-timeout ignored on non gRPC requests",
+			reqHeaders:     map[string]string{grpcTimeoutHeader: "2S"},
+			expectedCode:   http.StatusOK,
+			expectedCalled: true,
+		},
+		{
+			desc:         "budget exhausted",
+			reqHeaders:   map[string]string{DefaultHeader: "0"},
+			expectedCode: http.StatusGatewayTimeout,
+		},
+		{
+			desc:           "budget exceeded",
+			config:         dynamic.Deadline{Timeout: ptypes.Duration(10 * time.Millisecond)},
+			handlerDelay:   time.Second,
+			expectedCode:   http.StatusGatewayTimeout,
+			expectedCalled: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var called bool
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				called = true
+
+				for name, value := range test.expectedHeaders {
+					assert.Equal(t, value, req.Header.Get(name))
+				}
+
+				if test.handlerDelay > 0 {
+					select {
+					case <-req.Context().Done():
+						return
+					case <-time.After(test.handlerDelay):
+					}
+				}
+
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			handler, err := New(context.Background(), next, test.config, "deadline")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			for name, value := range test.reqHeaders {
+				req.Header.Set(name, value)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, test.expectedCalled, called)
+		})
+	}
+}
+
+func TestNew_invalidTimeout(t *testing.T) {
+	_, err := New(context.Background(), http.NotFoundHandler(), dynamic.Deadline{Timeout: ptypes.Duration(-time.Second)}, "deadline")
+	require.Error(t, err)
+}
+
+func TestGRPCTimeout(t *testing.T) {
+	testCases := []struct {
+		timeout  time.Duration
+		expected string
+	}{
+		{timeout: 10 * time.Nanosecond, expected: "10n"},
+		{timeout: 2 * time.Second, expected: "2000000u"},
+		{timeout: 2 * time.Minute, expected: "120000m"},
+		{timeout: 48 * time.Hour, expected: "172800S"},
+	}
+
+	for _, test := range testCases {
+		value := encodeGRPCTimeout(test.timeout)
+		assert.Equal(t, test.expected, value)
+
+		timeout, err := decodeGRPCTimeout(value)
+		require.NoError(t, err)
+		assert.Equal(t, test.timeout, timeout)
+	}
+
+	_, err := decodeGRPCTimeout("10x")
+	assert.Error(t, err)
+}
@@ -0,0 +1,156 @@
+// Package dnsquery implements the DNS protocol-aware mode of the TCP and UDP routers,
+// which logs and rate limits the DNS queries by query name.
+package dnsquery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/mailgun/ttlmap"
+	"github.com/miekg/dns"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"golang.org/x/time/rate"
+)
+
+const (
+	typeName = "DNSQuery"
+	maxNames = 65536
+)
+
+// inspector parses the DNS queries, to log and rate limit them.
+type inspector struct {
+	logger     log.Logger
+	logQueries bool
+	limiter    *limiter
+}
+
+func newInspector(ctx context.Context, config dynamic.DNSProtocol) (*inspector, error) {
+	var lim *limiter
+	if config.RateLimit != nil && config.RateLimit.Average > 0 {
+		var err error
+		lim, err = newLimiter(*config.RateLimit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &inspector{
+		logger:     log.FromContext(ctx),
+		logQueries: config.LogQueries,
+		limiter:    lim,
+	}, nil
+}
+
+// inspect tells whether the given DNS message can be forwarded to the backend.
+// When it cannot, the response to send back to the client is returned, if any.
+func (i *inspector) inspect(client net.Addr, msg []byte) ([]byte, bool) {
+	var query dns.Msg
+	if err := query.Unpack(msg); err != nil {
+		i.logger.Debugf("Forwarding unparsable DNS message from %s: %v", client, err)
+		return nil, true
+	}
+
+	if query.Response || len(query.Question) == 0 {
+		return nil, true
+	}
+
+	question := query.Question[0]
+	name := strings.ToLower(question.Name)
+
+	limited := i.limiter != nil && !i.limiter.allow(name)
+
+	if i.logQueries {
+		i.logger.
+			WithField("client", client.String()).
+			WithField("qname", name).
+			WithField("qtype", typeString(question.Qtype)).
+			WithField("rateLimited", limited).
+			Info("DNS query")
+	}
+
+	if !limited {
+		return nil, true
+	}
+
+	response, err := new(dns.Msg).SetRcode(&query, dns.RcodeRefused).Pack()
+	if err != nil {
+		i.logger.Debugf("Dropping rate limited DNS query from %s: %v", client, err)
+		return nil, false
+	}
+
+	return response, false
+}
+
+func typeString(qtype uint16) string {
+	if s, ok := dns.TypeToString[qtype]; ok {
+		return s
+	}
+	return fmt.Sprintf("TYPE%d", qtype)
+}
+
+// limiter rate limits the queries with a set of token buckets, one for each query name.
+type limiter struct {
+	rate  rate.Limit
+	burst int
+	// ttl is the number of seconds after which an unused bucket is discarded.
+	ttl     int
+	buckets *ttlmap.TtlMap
+}
+
+func newLimiter(config dynamic.DNSRateLimit) (*limiter, error) {
+	period := time.Duration(config.Period)
+	if period < 0 {
+		return nil, fmt.Errorf("negative value not valid for period: %v", period)
+	}
+	if period == 0 {
+		period = time.Second
+	}
+
+	burst := config.Burst
+	if burst < 1 {
+		burst = 1
+	}
+
+	buckets, err := ttlmap.NewConcurrent(maxNames)
+	if err != nil {
+		return nil, err
+	}
+
+	rtl := float64(config.Average*int64(time.Second)) / float64(period)
+
+	// As for the HTTP rate limiter, the ttl is inversely proportional to the rate for the low rates.
+	ttl := 1
+	if rtl >= 1 {
+		ttl++
+	} else {
+		ttl += int(1 / rtl)
+	}
+
+	return &limiter{
+		rate:    rate.Limit(rtl),
+		burst:   int(burst),
+		ttl:     ttl,
+		buckets: buckets,
+	}, nil
+}
+
+// allow tells whether a query for the given name can be forwarded.
+func (l *limiter) allow(name string) bool {
+	var bucket *rate.Limiter
+	if value, exists := l.buckets.Get(name); exists {
+		bucket = value.(*rate.Limiter)
+	} else {
+		bucket = rate.NewLimiter(l.rate, l.burst)
+	}
+
+	// The bucket is set even when it exists, to push back its expiry.
+	if err := l.buckets.Set(name, bucket, l.ttl); err != nil {
+		return true
+	}
+
+	return bucket.Allow()
+}
@@ -0,0 +1,77 @@
+package dnsquery
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+var client = &net.UDPAddr{IP: net.ParseIP("192.0.2.1"), Port: 53}
+
+func TestInspector_inspect(t *testing.T) {
+	insp, err := newInspector(context.Background(), dynamic.DNSProtocol{
+		LogQueries: true,
+		RateLimit:  &dynamic.DNSRateLimit{Average: 1, Period: ptypes.Duration(time.Hour), Burst: 2},
+	})
+	require.NoError(t, err)
+
+	// The names are rate limited case-insensitively.
+	for _, name := range []string{"example.com.", "EXAMPLE.com."} {
+		response, ok := insp.inspect(client, newQuery(t, name, dns.TypeA))
+		assert.True(t, ok)
+		assert.Nil(t, response)
+	}
+
+	response, ok := insp.inspect(client, newQuery(t, "Example.com.", dns.TypeAAAA))
+	assert.False(t, ok)
+	require.NotNil(t, response)
+
+	var msg dns.Msg
+	require.NoError(t, msg.Unpack(response))
+	assert.True(t, msg.Response)
+	assert.Equal(t, dns.RcodeRefused, msg.Rcode)
+	assert.Equal(t, uint16(42), msg.Id)
+
+	// The other names have their own bucket.
+	_, ok = insp.inspect(client, newQuery(t, "example.org.", dns.TypeA))
+	assert.True(t, ok)
+}
+
+func TestInspector_inspect_notQuery(t *testing.T) {
+	insp, err := newInspector(context.Background(), dynamic.DNSProtocol{
+		RateLimit: &dynamic.DNSRateLimit{Average: 1, Period: ptypes.Duration(time.Hour)},
+	})
+	require.NoError(t, err)
+
+	// The unparsable messages are forwarded, and not rate limited.
+	for i := 0; i < 3; i++ {
+		_, ok := insp.inspect(client, []byte("foo"))
+		assert.True(t, ok)
+	}
+}
+
+func TestNewInspector_invalid(t *testing.T) {
+	_, err := newInspector(context.Background(), dynamic.DNSProtocol{
+		RateLimit: &dynamic.DNSRateLimit{Average: 1, Period: ptypes.Duration(-time.Second)},
+	})
+	assert.Error(t, err)
+}
+
+func newQuery(t *testing.T, name string, qtype uint16) []byte {
+	t.Helper()
+
+	query := new(dns.Msg).SetQuestion(name, qtype)
+	query.Id = 42
+
+	msg, err := query.Pack()
+	require.NoError(t, err)
+
+	return msg
+}
@@ -0,0 +1,124 @@
+package dnsquery
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+const nameTCPRouter = "dns-tcp-router"
+
+type tcpHandler struct {
+	next      tcp.Handler
+	inspector *inspector
+}
+
+// NewTCP creates a new handler inspecting the DNS queries sent over the TCP connections.
+func NewTCP(ctx context.Context, next tcp.Handler, config dynamic.DNSProtocol) (tcp.Handler, error) {
+	ctx = middlewares.GetLoggerCtx(ctx, nameTCPRouter, typeName)
+	log.FromContext(ctx).Debug("Creating middleware")
+
+	insp, err := newInspector(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tcpHandler{next: next, inspector: insp}, nil
+}
+
+// WrapTCPRouterHandler Wraps the DNS query inspection to tcp.Constructor.
+func WrapTCPRouterHandler(ctx context.Context, config dynamic.DNSProtocol) tcp.Constructor {
+	return func(next tcp.Handler) (tcp.Handler, error) {
+		return NewTCP(ctx, next, config)
+	}
+}
+
+// ServeTCP serves the given TCP connection.
+func (h *tcpHandler) ServeTCP(conn tcp.WriteCloser) {
+	h.next.ServeTCP(&tcpConn{WriteCloser: conn, inspector: h.inspector})
+}
+
+// tcpConn reads the DNS messages sent by the client one at a time,
+// each of them being prefixed by its length, to inspect them before they are forwarded.
+type tcpConn struct {
+	tcp.WriteCloser
+
+	inspector *inspector
+
+	// pending is the part of the current message not read yet.
+	pending []byte
+
+	// writeMu serializes the responses to the rate limited queries with the responses of the backend.
+	writeMu sync.Mutex
+}
+
+func (c *tcpConn) Read(p []byte) (int, error) {
+	for len(c.pending) == 0 {
+		frame, err := c.readFrame()
+		if err != nil {
+			return 0, err
+		}
+
+		response, ok := c.inspector.inspect(c.RemoteAddr(), frame[2:])
+		if ok {
+			c.pending = frame
+			break
+		}
+
+		if response == nil {
+			continue
+		}
+
+		out := make([]byte, 2+len(response))
+		binary.BigEndian.PutUint16(out, uint16(len(response)))
+		copy(out[2:], response)
+
+		if _, err := c.Write(out); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+
+	return n, nil
+}
+
+func (c *tcpConn) Write(p []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	return c.WriteCloser.Write(p)
+}
+
+// NetConn returns the client connection.
+func (c *tcpConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+// readFrame reads a DNS message, with its length prefix.
+func (c *tcpConn) readFrame() ([]byte, error) {
+	var length [2]byte
+	if _, err := io.ReadFull(c.WriteCloser, length[:]); err != nil {
+		return nil, err
+	}
+
+	frame := make([]byte, 2+int(binary.BigEndian.Uint16(length[:])))
+	copy(frame, length[:])
+
+	if _, err := io.ReadFull(c.WriteCloser, frame[2:]); err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	return frame, nil
+}
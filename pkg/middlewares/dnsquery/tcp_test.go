@@ -0,0 +1,82 @@
+package dnsquery
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestTCPHandler(t *testing.T) {
+	forwarded := make(chan []byte, 2)
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		data, err := io.ReadAll(conn)
+		require.NoError(t, err)
+		forwarded <- data
+	})
+
+	handler, err := NewTCP(context.Background(), next, dynamic.DNSProtocol{
+		RateLimit: &dynamic.DNSRateLimit{Average: 1, Period: ptypes.Duration(time.Hour)},
+	})
+	require.NoError(t, err)
+
+	server, client := net.Pipe()
+
+	allowed := frame(newQuery(t, "example.com.", dns.TypeA))
+	limited := frame(newQuery(t, "example.com.", dns.TypeA))
+
+	responses := make(chan []byte, 1)
+	go func() {
+		_, _ = client.Write(append(allowed, limited...))
+
+		response, err := readFrame(client)
+		require.NoError(t, err)
+		responses <- response
+
+		_ = client.Close()
+	}()
+
+	handler.ServeTCP(fakeConn{Conn: server})
+
+	// Only the first query is forwarded, the second one being answered right away.
+	assert.Equal(t, allowed, <-forwarded)
+
+	var msg dns.Msg
+	require.NoError(t, msg.Unpack(<-responses))
+	assert.Equal(t, dns.RcodeRefused, msg.Rcode)
+}
+
+func frame(msg []byte) []byte {
+	out := make([]byte, 2+len(msg))
+	binary.BigEndian.PutUint16(out, uint16(len(msg)))
+	copy(out[2:], msg)
+	return out
+}
+
+func readFrame(r io.Reader) ([]byte, error) {
+	var length [2]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, err
+	}
+
+	msg := make([]byte, binary.BigEndian.Uint16(length[:]))
+	_, err := io.ReadFull(r, msg)
+	return msg, err
+}
+
+type fakeConn struct {
+	net.Conn
+}
+
+func (c fakeConn) CloseWrite() error {
+	return nil
+}
@@ -0,0 +1,46 @@
+package dnsquery
+
+import (
+	"context"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/udp"
+)
+
+const nameUDPRouter = "dns-udp-router"
+
+type udpHandler struct {
+	next      udp.Handler
+	inspector *inspector
+}
+
+// NewUDP creates a new handler inspecting the DNS queries sent in the UDP datagrams.
+func NewUDP(ctx context.Context, next udp.Handler, config dynamic.DNSProtocol) (udp.Handler, error) {
+	ctx = middlewares.GetLoggerCtx(ctx, nameUDPRouter, typeName)
+	log.FromContext(ctx).Debug("Creating middleware")
+
+	insp, err := newInspector(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &udpHandler{next: next, inspector: insp}, nil
+}
+
+// ServeUDP serves the given UDP session.
+func (h *udpHandler) ServeUDP(conn *udp.Conn) {
+	conn.SetReadFilter(func(datagram []byte) bool {
+		response, ok := h.inspector.inspect(conn.RemoteAddr(), datagram)
+		if !ok && response != nil {
+			if _, err := conn.Write(response); err != nil {
+				h.inspector.logger.Debugf("Error while responding to a rate limited DNS query: %v", err)
+			}
+		}
+
+		return ok
+	})
+
+	h.next.ServeUDP(conn)
+}
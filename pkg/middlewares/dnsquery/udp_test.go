@@ -0,0 +1,68 @@
+package dnsquery
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/udp"
+)
+
+func TestUDPHandler(t *testing.T) {
+	addr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ln, err := udp.Listen("udp", addr, 3*time.Second)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	// The backend echoes the forwarded queries.
+	next := udp.HandlerFunc(func(conn *udp.Conn) {
+		buf := make([]byte, 512)
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				return
+			}
+
+			_, _ = conn.Write(buf[:n])
+		}
+	})
+
+	handler, err := NewUDP(context.Background(), next, dynamic.DNSProtocol{
+		RateLimit: &dynamic.DNSRateLimit{Average: 1, Period: ptypes.Duration(time.Hour)},
+	})
+	require.NoError(t, err)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		handler.ServeUDP(conn)
+	}()
+
+	client, err := net.Dial("udp", ln.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	buf := make([]byte, 512)
+	for _, expected := range []int{dns.RcodeSuccess, dns.RcodeRefused} {
+		_, err = client.Write(newQuery(t, "example.com.", dns.TypeA))
+		require.NoError(t, err)
+
+		require.NoError(t, client.SetReadDeadline(time.Now().Add(time.Second)))
+		n, err := client.Read(buf)
+		require.NoError(t, err)
+
+		var msg dns.Msg
+		require.NoError(t, msg.Unpack(buf[:n]))
+		assert.Equal(t, expected, msg.Rcode)
+	}
+}
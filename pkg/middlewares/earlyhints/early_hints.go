@@ -0,0 +1,72 @@
+package earlyhints
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const typeName = "EarlyHints"
+
+type earlyHints struct {
+	next  http.Handler
+	name  string
+	links []string
+}
+
+// New creates a new early hints middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.EarlyHints, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if len(config.Links) == 0 {
+		return nil, errors.New("links cannot be empty")
+	}
+
+	for _, link := range config.Links {
+		if !strings.HasPrefix(strings.TrimSpace(link), "<") {
+			return nil, fmt.Errorf("link %q must start with a URI reference between angle brackets", link)
+		}
+	}
+
+	return &earlyHints{
+		next:  next,
+		name:  name,
+		links: config.Links,
+	}, nil
+}
+
+func (e *earlyHints) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return e.name, tracing.SpanKindNoneEnum
+}
+
+func (e *earlyHints) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The informational responses must not be sent to the HTTP/1.0 clients (RFC 7231, section 6.2).
+	if req.Method != http.MethodGet || !req.ProtoAtLeast(1, 1) {
+		e.next.ServeHTTP(rw, req)
+		return
+	}
+
+	header := rw.Header()
+	links := header["Link"]
+
+	header["Link"] = append(append([]string(nil), links...), e.links...)
+	rw.WriteHeader(http.StatusEarlyHints)
+
+	// The headers are not cleared by the informational responses,
+	// so the links are removed for the final response to only carry the ones of the backend.
+	if links == nil {
+		delete(header, "Link")
+	} else {
+		header["Link"] = links
+	}
+
+	e.next.ServeHTTP(rw, req)
+}
@@ -0,0 +1,85 @@
+package earlyhints
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	_, err := New(context.Background(), next, dynamic.EarlyHints{}, "test")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), next, dynamic.EarlyHints{Links: []string{"/style.css; rel=preload"}}, "test")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), next, dynamic.EarlyHints{Links: []string{"</style.css>; rel=preload; as=style"}}, "test")
+	assert.NoError(t, err)
+}
+
+func TestEarlyHints(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		method        string
+		expectedHints [][]string
+	}{
+		{
+			desc:          "GET request",
+			method:        http.MethodGet,
+			expectedHints: [][]string{{"</style.css>; rel=preload; as=style", "</script.js>; rel=preload; as=script"}},
+		},
+		{
+			desc:   "POST request",
+			method: http.MethodPost,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Add("Link", "</font.woff2>; rel=preload; as=font")
+				_, _ = rw.Write([]byte("Hello"))
+			})
+
+			handler, err := New(context.Background(), next, dynamic.EarlyHints{
+				Links: []string{"</style.css>; rel=preload; as=style", "</script.js>; rel=preload; as=script"},
+			}, "test")
+			require.NoError(t, err)
+
+			server := httptest.NewServer(handler)
+			t.Cleanup(server.Close)
+
+			var hints [][]string
+			trace := &httptrace.ClientTrace{
+				Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+					assert.Equal(t, http.StatusEarlyHints, code)
+					hints = append(hints, header["Link"])
+					return nil
+				},
+			}
+
+			req, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), test.method, server.URL, nil)
+			require.NoError(t, err)
+
+			res, err := server.Client().Do(req)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = res.Body.Close() })
+
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, test.expectedHints, hints)
+			assert.Equal(t, []string{"</font.woff2>; rel=preload; as=font"}, res.Header["Link"])
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package forwardedheaders
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const forwarded = "Forwarded"
+
+// forwardedElement is an element of a Forwarded header (RFC 7239),
+// holding the parameters set by one of the proxies, keyed by their lower case names.
+type forwardedElement map[string]string
+
+// parseForwarded parses the elements of the Forwarded header values, ignoring the malformed parameters.
+func parseForwarded(values []string) []forwardedElement {
+	var elements []forwardedElement
+	for _, value := range values {
+		for _, raw := range splitQuoted(value, ',') {
+			element := forwardedElement{}
+			for _, pair := range splitQuoted(raw, ';') {
+				key, val, found := strings.Cut(strings.TrimSpace(pair), "=")
+				if !found || key == "" {
+					continue
+				}
+
+				element[strings.ToLower(key)] = unquote(strings.TrimSpace(val))
+			}
+
+			if len(element) > 0 {
+				elements = append(elements, element)
+			}
+		}
+	}
+
+	return elements
+}
+
+// splitQuoted splits the value around the separator, except within the quoted strings.
+func splitQuoted(value string, sep byte) []string {
+	var parts []string
+	var quoted, escaped bool
+
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case escaped:
+			escaped = false
+		case quoted && c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case !quoted && c == sep:
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, value[start:])
+}
+
+func unquote(value string) string {
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return value
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(value)-1; i++ {
+		if value[i] == '\\' && i+1 < len(value)-1 {
+			i++
+		}
+		b.WriteByte(value[i])
+	}
+
+	return b.String()
+}
+
+// quote returns the value as a token, or as a quoted string when it holds other characters than the token ones,
+// such as the colons of the IPv6 addresses and of the ports.
+func quote(value string) string {
+	for i := 0; i < len(value); i++ {
+		if !isTokenChar(value[i]) {
+			return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+		}
+	}
+
+	return value
+}
+
+func isTokenChar(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0
+}
+
+// forwardedNode returns the node identifying the address in a Forwarded header, with the IPv6 addresses between brackets.
+func forwardedNode(host, port string) string {
+	if port != "" {
+		return net.JoinHostPort(host, port)
+	}
+
+	if strings.Contains(host, ":") {
+		return "[" + host + "]"
+	}
+
+	return host
+}
+
+// obfuscator derives the obfuscated identifiers (RFC 7239, section 6.3) of the addresses,
+// which are stable for each address during the lifetime of the process, but do not reveal it.
+type obfuscator struct {
+	key []byte
+}
+
+func newObfuscator() (*obfuscator, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	return &obfuscator{key: key}, nil
+}
+
+func (o *obfuscator) identifier(addr string) string {
+	mac := hmac.New(sha256.New, o.key)
+	mac.Write([]byte(addr))
+
+	return "_" + hex.EncodeToString(mac.Sum(nil)[:6])
+}
+
+// parseForwardedHeader fills the X-Forwarded-For, X-Forwarded-Proto, and X-Forwarded-Host headers which are missing,
+// from the Forwarded header, so that the backends which only read them get the same information.
+// Only the addresses of the for parameters are kept in X-Forwarded-For, without the obfuscated identifiers.
+func parseForwardedHeader(req *http.Request) {
+	elements := parseForwarded(unsafeHeader(req.Header).Values(forwarded))
+	if len(elements) == 0 {
+		return
+	}
+
+	if len(unsafeHeader(req.Header).Values(xForwardedFor)) == 0 {
+		var addrs []string
+		for _, element := range elements {
+			addr := element["for"]
+			if host, _, err := net.SplitHostPort(addr); err == nil {
+				addr = host
+			}
+			addr = strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+
+			if net.ParseIP(addr) != nil {
+				addrs = append(addrs, addr)
+			}
+		}
+
+		if len(addrs) > 0 {
+			unsafeHeader(req.Header).Set(xForwardedFor, strings.Join(addrs, ", "))
+		}
+	}
+
+	// The first element is set by the proxy closest to the client, which saw the original request.
+	if proto := elements[0]["proto"]; proto != "" && unsafeHeader(req.Header).Get(xForwardedProto) == "" {
+		unsafeHeader(req.Header).Set(xForwardedProto, proto)
+	}
+
+	if host := elements[0]["host"]; host != "" && unsafeHeader(req.Header).Get(xForwardedHost) == "" {
+		unsafeHeader(req.Header).Set(xForwardedHost, host)
+	}
+}
+
+// appendForwardedHeader appends the element describing the request, as received by the entry point, to the Forwarded header.
+func (x *XForwarded) appendForwardedHeader(req *http.Request) {
+	var params []string
+
+	if localAddr, ok := req.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+		by := localAddr.String()
+		if host, port, err := net.SplitHostPort(by); err == nil {
+			by = forwardedNode(removeIPv6Zone(host), port)
+		}
+
+		if x.obfuscator != nil {
+			by = x.obfuscator.identifier(by)
+		}
+
+		params = append(params, "by="+quote(by))
+	}
+
+	if clientIP, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		clientIP = removeIPv6Zone(clientIP)
+
+		forAddr := forwardedNode(clientIP, "")
+		if x.obfuscator != nil {
+			forAddr = x.obfuscator.identifier(clientIP)
+		}
+
+		params = append(params, "for="+quote(forAddr))
+	}
+
+	if req.Host != "" {
+		params = append(params, "host="+quote(req.Host))
+	}
+
+	proto := "http"
+	if req.TLS != nil {
+		proto = "https"
+	}
+	params = append(params, "proto="+proto)
+
+	element := strings.Join(params, ";")
+
+	if values := unsafeHeader(req.Header).Values(forwarded); len(values) > 0 {
+		element = strings.Join(values, ", ") + ", " + element
+	}
+
+	unsafeHeader(req.Header).Set(forwarded, element)
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/ip"
 )
 
@@ -41,16 +42,20 @@ var xHeaders = []string{
 // and other relevant headers for a reverse-proxy.
 // Unless insecure is set,
 // it first removes all the existing values for those headers if the remote address is not one of the trusted ones.
+// When enabled, it also parses and generates the Forwarded header (RFC 7239).
 type XForwarded struct {
 	insecure   bool
 	trustedIps []string
 	ipChecker  *ip.Checker
 	next       http.Handler
 	hostname   string
+	forwarded  bool
+	obfuscator *obfuscator
 }
 
 // NewXForwarded creates a new XForwarded.
-func NewXForwarded(insecure bool, trustedIps []string, next http.Handler) (*XForwarded, error) {
+// A nil forwarded configuration leaves the Forwarded header as is.
+func NewXForwarded(insecure bool, trustedIps []string, forwarded *static.Forwarded, next http.Handler) (*XForwarded, error) {
 	var ipChecker *ip.Checker
 	if len(trustedIps) > 0 {
 		var err error
@@ -65,12 +70,22 @@ func NewXForwarded(insecure bool, trustedIps []string, next http.Handler) (*XFor
 		hostname = "localhost"
 	}
 
+	var obf *obfuscator
+	if forwarded != nil && forwarded.Obfuscate {
+		obf, err = newObfuscator()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &XForwarded{
 		insecure:   insecure,
 		trustedIps: trustedIps,
 		ipChecker:  ipChecker,
 		next:       next,
 		hostname:   hostname,
+		forwarded:  forwarded != nil,
+		obfuscator: obf,
 	}, nil
 }
 
@@ -185,10 +200,22 @@ func (x *XForwarded) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		for _, h := range xHeaders {
 			unsafeHeader(r.Header).Del(h)
 		}
+
+		if x.forwarded {
+			unsafeHeader(r.Header).Del(forwarded)
+		}
+	}
+
+	if x.forwarded {
+		parseForwardedHeader(r)
 	}
 
 	x.rewrite(r)
 
+	if x.forwarded {
+		x.appendForwardedHeader(r)
+	}
+
 	x.next.ServeHTTP(w, r)
 }
 
@@ -0,0 +1,170 @@
+package forwardedheaders
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+func Test_parseForwarded(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		values   []string
+		expected []forwardedElement
+	}{
+		{
+			desc:   "single element",
+			values: []string{"for=192.0.2.60;proto=http;by=203.0.113.43"},
+			expected: []forwardedElement{
+				{"for": "192.0.2.60", "proto": "http", "by": "203.0.113.43"},
+			},
+		},
+		{
+			desc:   "several elements and values",
+			values: []string{`for=192.0.2.43, For="[2001:db8:cafe::17]:4711"`, "for=_hidden;host=example.com"},
+			expected: []forwardedElement{
+				{"for": "192.0.2.43"},
+				{"for": "[2001:db8:cafe::17]:4711"},
+				{"for": "_hidden", "host": "example.com"},
+			},
+		},
+		{
+			desc:   "quoted separators",
+			values: []string{`for="a,b;c\"d";proto=https`},
+			expected: []forwardedElement{
+				{"for": `a,b;c"d`, "proto": "https"},
+			},
+		},
+		{
+			desc:   "malformed parameters",
+			values: []string{"foo, ;=bar, for=192.0.2.43"},
+			expected: []forwardedElement{
+				{"for": "192.0.2.43"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, parseForwarded(test.values))
+		})
+	}
+}
+
+func TestServeHTTP_forwarded(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		insecure          bool
+		forwarded         *static.Forwarded
+		remoteAddr        string
+		tls               bool
+		incomingForwarded []string
+		expectedForwarded string
+		expectedXFF       string
+		expectedXFProto   string
+		expectedXFHost    string
+	}{
+		{
+			desc:              "disabled",
+			insecure:          false,
+			remoteAddr:        "10.0.1.101:80",
+			incomingForwarded: []string{"for=192.0.2.43"},
+			expectedForwarded: "for=192.0.2.43",
+			expectedXFProto:   "http",
+			expectedXFHost:    "foo.com",
+		},
+		{
+			desc:              "untrusted incoming Forwarded header",
+			forwarded:         &static.Forwarded{},
+			remoteAddr:        "10.0.1.101:80",
+			incomingForwarded: []string{"for=192.0.2.43;proto=https"},
+			expectedForwarded: `by="127.0.0.1:8000";for=10.0.1.101;host=foo.com;proto=http`,
+			expectedXFProto:   "http",
+			expectedXFHost:    "foo.com",
+		},
+		{
+			desc:              "trusted incoming Forwarded header",
+			insecure:          true,
+			forwarded:         &static.Forwarded{},
+			remoteAddr:        "10.0.1.101:80",
+			tls:               true,
+			incomingForwarded: []string{`for=192.0.2.43;proto=http;host=bar.com, for="[2001:db8:cafe::17]:4711"`, "for=_hidden"},
+			expectedForwarded: `for=192.0.2.43;proto=http;host=bar.com, for="[2001:db8:cafe::17]:4711", for=_hidden, by="127.0.0.1:8000";for=10.0.1.101;host=foo.com;proto=https`,
+			expectedXFF:       "192.0.2.43, 2001:db8:cafe::17",
+			expectedXFProto:   "http",
+			expectedXFHost:    "bar.com",
+		},
+		{
+			desc:              "IPv6 client",
+			forwarded:         &static.Forwarded{},
+			remoteAddr:        "[2001:db8::1]:4711",
+			expectedForwarded: `by="127.0.0.1:8000";for="[2001:db8::1]";host=foo.com;proto=http`,
+			expectedXFProto:   "http",
+			expectedXFHost:    "foo.com",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo.com/", nil)
+			req.RemoteAddr = test.remoteAddr
+			req = req.WithContext(context.WithValue(req.Context(), http.LocalAddrContextKey, &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 8000}))
+
+			if test.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+
+			for _, value := range test.incomingForwarded {
+				req.Header.Add(forwarded, value)
+			}
+
+			m, err := NewXForwarded(test.insecure, nil, test.forwarded, http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+			require.NoError(t, err)
+
+			m.ServeHTTP(nil, req)
+
+			assert.Equal(t, test.expectedForwarded, req.Header.Get(forwarded))
+			assert.Equal(t, test.expectedXFF, req.Header.Get(xForwardedFor))
+			assert.Equal(t, test.expectedXFProto, req.Header.Get(xForwardedProto))
+			assert.Equal(t, test.expectedXFHost, req.Header.Get(xForwardedHost))
+		})
+	}
+}
+
+func TestServeHTTP_forwardedObfuscated(t *testing.T) {
+	m, err := NewXForwarded(false, nil, &static.Forwarded{Obfuscate: true}, http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+	require.NoError(t, err)
+
+	element := regexp.MustCompile(`^by=_[0-9a-f]{12};for=(_[0-9a-f]{12});host=foo.com;proto=http$`)
+
+	var identifiers []string
+	for _, remoteAddr := range []string{"10.0.1.101:80", "10.0.1.101:81", "10.0.1.102:80"} {
+		req := httptest.NewRequest(http.MethodGet, "http://foo.com/", nil)
+		req.RemoteAddr = remoteAddr
+		req = req.WithContext(context.WithValue(req.Context(), http.LocalAddrContextKey, &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 8000}))
+
+		m.ServeHTTP(nil, req)
+
+		matches := element.FindStringSubmatch(req.Header.Get(forwarded))
+		require.Len(t, matches, 2, req.Header.Get(forwarded))
+		identifiers = append(identifiers, matches[1])
+	}
+
+	// The identifiers are stable for each client, whatever its port.
+	assert.Equal(t, identifiers[0], identifiers[1])
+	assert.NotEqual(t, identifiers[0], identifiers[2])
+}
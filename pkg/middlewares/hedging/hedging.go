@@ -0,0 +1,287 @@
+package hedging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/middlewares/capture"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "Hedging"
+
+	// DefaultDelay is the default time to wait for a response before sending a hedged request.
+	DefaultDelay = 100 * time.Millisecond
+)
+
+// DefaultMethods are the default methods of the requests to hedge.
+var DefaultMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions}
+
+type hedging struct {
+	next      http.Handler
+	hedge     http.Handler
+	name      string
+	delay     time.Duration
+	maxHedges int
+	methods   map[string]struct{}
+}
+
+// New creates a new hedging middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Hedging, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if config.Delay < 0 {
+		return nil, fmt.Errorf("delay must be positive, got %s", time.Duration(config.Delay))
+	}
+
+	if config.MaxHedges < 0 {
+		return nil, fmt.Errorf("max hedges must be positive, got %d", config.MaxHedges)
+	}
+
+	delay := time.Duration(config.Delay)
+	if delay == 0 {
+		delay = DefaultDelay
+	}
+
+	maxHedges := config.MaxHedges
+	if maxHedges == 0 {
+		maxHedges = 1
+	}
+
+	methods := config.Methods
+	if len(methods) == 0 {
+		methods = DefaultMethods
+	}
+
+	methodSet := make(map[string]struct{}, len(methods))
+	for _, method := range methods {
+		methodSet[strings.ToUpper(method)] = struct{}{}
+	}
+
+	// The hedged requests get their own capture, as they run concurrently with the original request.
+	hedge, err := capture.Wrap(next)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hedging{
+		next:      next,
+		hedge:     hedge,
+		name:      name,
+		delay:     delay,
+		maxHedges: maxHedges,
+		methods:   methodSet,
+	}, nil
+}
+
+func (h *hedging) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return h.name, tracing.SpanKindNoneEnum
+}
+
+func (h *hedging) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !h.hedgeable(req) {
+		h.next.ServeHTTP(rw, req)
+		return
+	}
+
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), h.name, typeName))
+
+	results := make(chan *responseRecorder, h.maxHedges+1)
+	var cancels []context.CancelFunc
+	pending := 0
+
+	send := func() {
+		ctx, cancel := context.WithCancel(req.Context())
+		cancels = append(cancels, cancel)
+		pending++
+
+		next := h.next
+		if len(cancels) > 1 {
+			// The hedged requests must not contribute to the access log of the original request,
+			// as it would result in unguarded concurrent writes on its datatable.
+			ctx = context.WithValue(ctx, accesslog.DataTableKey, nil)
+			next = h.hedge
+		}
+
+		outReq := req.Clone(ctx)
+		recorder := newResponseRecorder()
+
+		go func() {
+			defer func() {
+				if err := recover(); err != nil {
+					if !errors.Is(asError(err), http.ErrAbortHandler) {
+						logger.Errorf("Recovered from panic in hedged request: %v", err)
+					}
+					recorder.aborted = true
+				}
+
+				results <- recorder
+			}()
+
+			next.ServeHTTP(recorder, outReq)
+		}()
+	}
+
+	// Cancels the requests still in flight, and waits for them,
+	// so that none of them outlives the original request.
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+
+		for ; pending > 0; pending-- {
+			<-results
+		}
+	}()
+
+	send()
+
+	timer := time.NewTimer(h.delay)
+	defer timer.Stop()
+
+	var fallback *responseRecorder
+	for {
+		select {
+		case recorder := <-results:
+			pending--
+
+			if recorder.succeeded() {
+				recorder.writeTo(rw)
+				return
+			}
+
+			if fallback == nil || fallback.aborted {
+				fallback = recorder
+			}
+
+			// A failed request is hedged right away.
+			if len(cancels) <= h.maxHedges {
+				logger.Debugf("Sending hedged request %d after a failed response", len(cancels))
+				send()
+				timer.Reset(h.delay)
+				continue
+			}
+
+			if pending == 0 {
+				if fallback.aborted {
+					rw.WriteHeader(http.StatusBadGateway)
+					_, _ = rw.Write([]byte(http.StatusText(http.StatusBadGateway)))
+					return
+				}
+
+				fallback.writeTo(rw)
+				return
+			}
+
+		case <-timer.C:
+			if len(cancels) <= h.maxHedges {
+				logger.Debugf("Sending hedged request %d after %s without response", len(cancels), h.delay)
+				send()
+				timer.Reset(h.delay)
+			}
+
+		case <-req.Context().Done():
+			return
+		}
+	}
+}
+
+// hedgeable tells whether the request can be sent several times.
+func (h *hedging) hedgeable(req *http.Request) bool {
+	if _, ok := h.methods[req.Method]; !ok {
+		return false
+	}
+
+	// The requests with a body cannot be replayed without buffering it.
+	if req.Body != nil && req.Body != http.NoBody && req.ContentLength != 0 {
+		return false
+	}
+
+	// The upgraded connections cannot be replayed.
+	return req.Header.Get("Upgrade") == ""
+}
+
+func asError(v interface{}) error {
+	err, ok := v.(error)
+	if !ok {
+		return nil
+	}
+	return err
+}
+
+// responseRecorder holds a response until it is known whether it is the one sent to the client.
+type responseRecorder struct {
+	header http.Header
+	// headerSnapshot holds the headers at the time they were written,
+	// the headers set afterwards being trailers.
+	headerSnapshot http.Header
+	code           int
+	body           bytes.Buffer
+	aborted        bool
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{header: make(http.Header)}
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	// The informational responses are not forwarded.
+	if r.headerSnapshot != nil || code < http.StatusOK {
+		return
+	}
+
+	r.code = code
+	r.headerSnapshot = r.header.Clone()
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.headerSnapshot == nil {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	return r.body.Write(b)
+}
+
+// Flush is a no-op, as the response is sent once complete.
+func (r *responseRecorder) Flush() {}
+
+// succeeded tells whether the response can be sent to the client,
+// instead of waiting for the response of a hedged request.
+func (r *responseRecorder) succeeded() bool {
+	return !r.aborted && r.code < http.StatusInternalServerError
+}
+
+func (r *responseRecorder) writeTo(rw http.ResponseWriter) {
+	if r.headerSnapshot == nil {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	for key, values := range r.headerSnapshot {
+		rw.Header()[key] = values
+	}
+
+	rw.WriteHeader(r.code)
+	_, _ = rw.Write(r.body.Bytes())
+
+	// The headers set after the response headers are the trailers.
+	for key, values := range r.header {
+		if _, ok := r.headerSnapshot[key]; !ok {
+			rw.Header()[key] = values
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package hedging
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// attempt is the behavior of the backend for a given attempt.
+type attempt struct {
+	delay time.Duration
+	code  int
+}
+
+func TestHedging(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		config           dynamic.Hedging
+		method           string
+		body             string
+		attempts         []attempt
+		expectedCode     int
+		expectedAttempt  string
+		expectedAttempts int64
+	}{
+		{
+			desc:             "fast response",
+			attempts:         []attempt{{code: http.StatusOK}},
+			expectedCode:     http.StatusOK,
+			expectedAttempt:  "1",
+			expectedAttempts: 1,
+		},
+		{
+			desc:             "slow response hedged",
+			config:           dynamic.Hedging{Delay: ptypes.Duration(10 * time.Millisecond)},
+			attempts:         []attempt{{delay: time.Second, code: http.StatusOK}, {code: http.StatusOK}},
+			expectedCode:     http.StatusOK,
+			expectedAttempt:  "2",
+			expectedAttempts: 2,
+		},
+		{
+			desc:   "max hedges",
+			config: dynamic.Hedging{Delay: ptypes.Duration(10 * time.Millisecond), MaxHedges: 2},
+			attempts: []attempt{
+				{delay: time.Second, code: http.StatusOK},
+				{delay: time.Second, code: http.StatusOK},
+				{code: http.StatusOK},
+			},
+			expectedCode:     http.StatusOK,
+			expectedAttempt:  "3",
+			expectedAttempts: 3,
+		},
+		{
+			desc:             "failed response hedged right away",
+			config:           dynamic.Hedging{Delay: ptypes.Duration(time.Hour)},
+			attempts:         []attempt{{code: http.StatusBadGateway}, {code: http.StatusOK}},
+			expectedCode:     http.StatusOK,
+			expectedAttempt:  "2",
+			expectedAttempts: 2,
+		},
+		{
+			desc:             "all responses failed",
+			attempts:         []attempt{{code: http.StatusServiceUnavailable}, {code: http.StatusBadGateway}},
+			expectedCode:     http.StatusServiceUnavailable,
+			expectedAttempt:  "1",
+			expectedAttempts: 2,
+		},
+		{
+			desc:             "non idempotent method",
+			config:           dynamic.Hedging{Delay: ptypes.Duration(10 * time.Millisecond)},
+			method:           http.MethodPost,
+			attempts:         []attempt{{delay: 50 * time.Millisecond, code: http.StatusOK}},
+			expectedCode:     http.StatusOK,
+			expectedAttempt:  "1",
+			expectedAttempts: 1,
+		},
+		{
+			desc:             "custom methods",
+			config:           dynamic.Hedging{Delay: ptypes.Duration(10 * time.Millisecond), Methods: []string{"post"}},
+			method:           http.MethodPost,
+			attempts:         []attempt{{delay: time.Second, code: http.StatusOK}, {code: http.StatusOK}},
+			expectedCode:     http.StatusOK,
+			expectedAttempt:  "2",
+			expectedAttempts: 2,
+		},
+		{
+			desc:             "request with a body",
+			config:           dynamic.Hedging{Delay: ptypes.Duration(10 * time.Millisecond), Methods: []string{http.MethodPost}},
+			method:           http.MethodPost,
+			body:             "foo",
+			attempts:         []attempt{{delay: 50 * time.Millisecond, code: http.StatusOK}},
+			expectedCode:     http.StatusOK,
+			expectedAttempt:  "1",
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts int64
+			var canceled int64
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				n := atomic.AddInt64(&attempts, 1)
+				behavior := test.attempts[n-1]
+
+				select {
+				case <-time.After(behavior.delay):
+				case <-req.Context().Done():
+					atomic.AddInt64(&canceled, 1)
+					return
+				}
+
+				rw.Header().Set("X-Attempt", strconv.FormatInt(n, 10))
+				rw.WriteHeader(behavior.code)
+			})
+
+			handler, err := New(context.Background(), next, test.config, "test")
+			require.NoError(t, err)
+
+			method := test.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			var req *http.Request
+			if test.body != "" {
+				req = httptest.NewRequest(method, "/", strings.NewReader(test.body))
+			} else {
+				req = httptest.NewRequest(method, "/", nil)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, test.expectedAttempt, recorder.Header().Get("X-Attempt"))
+			assert.Equal(t, test.expectedAttempts, atomic.LoadInt64(&attempts))
+
+			// The requests still in flight are canceled.
+			var expectedCanceled int64
+			for _, behavior := range test.attempts[:test.expectedAttempts] {
+				if behavior.delay >= time.Second {
+					expectedCanceled++
+				}
+			}
+			assert.Equal(t, expectedCanceled, atomic.LoadInt64(&canceled))
+		})
+	}
+}
+
+func TestHedging_trailers(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Trailer", "X-Trailer")
+		rw.Header().Set("X-Header", "foo")
+		_, _ = rw.Write([]byte("body"))
+		rw.Header().Set("X-Trailer", "bar")
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Hedging{}, "test")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, "body", string(body))
+	assert.Equal(t, "foo", resp.Header.Get("X-Header"))
+	assert.Equal(t, "bar", resp.Trailer.Get("X-Trailer"))
+}
+
+func TestNew_invalid(t *testing.T) {
+	_, err := New(context.Background(), http.NotFoundHandler(), dynamic.Hedging{Delay: ptypes.Duration(-time.Second)}, "test")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), http.NotFoundHandler(), dynamic.Hedging{MaxHedges: -1}, "test")
+	assert.Error(t, err)
+}
@@ -0,0 +1,323 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+	"github.com/vulcand/oxy/v2/utils"
+)
+
+const (
+	typeName = "Idempotency"
+
+	// DefaultHeaderName is the default name of the header carrying the idempotency key of the requests.
+	DefaultHeaderName = "Idempotency-Key"
+
+	// DefaultWindow is the default time during which the duplicates of a request are guarded against.
+	DefaultWindow = 24 * time.Hour
+
+	// DefaultMaxKeys is the default maximum number of idempotency keys remembered in memory.
+	DefaultMaxKeys = 10000
+
+	// DefaultMaxResponseBodyBytes is the default maximum size of the response bodies remembered to be replayed.
+	DefaultMaxResponseBodyBytes = 1024 * 1024
+
+	// DefaultMaxRequestBodyBytes is the default maximum size of the request bodies read to tell the requests reusing a key apart.
+	DefaultMaxRequestBodyBytes = 1024 * 1024
+
+	// replayedHeader is the header set on the replayed responses.
+	replayedHeader = "Idempotent-Replayed"
+)
+
+type idempotency struct {
+	next                 http.Handler
+	name                 string
+	headerName           string
+	sourceExtractor      utils.SourceExtractor
+	maxResponseBodyBytes int64
+	maxRequestBodyBytes  int64
+	store                store
+}
+
+// New creates a new idempotency middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Idempotency, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if config.Window < 0 {
+		return nil, fmt.Errorf("window must be positive, got %s", time.Duration(config.Window))
+	}
+
+	if config.MaxKeys < 0 {
+		return nil, fmt.Errorf("max keys must be positive, got %d", config.MaxKeys)
+	}
+
+	if config.MaxResponseBodyBytes < 0 {
+		return nil, fmt.Errorf("max response body bytes must be positive, got %d", config.MaxResponseBodyBytes)
+	}
+
+	if config.MaxRequestBodyBytes < 0 {
+		return nil, fmt.Errorf("max request body bytes must be positive, got %d", config.MaxRequestBodyBytes)
+	}
+
+	sourceExtractor, err := middlewares.GetSourceExtractor(middlewares.GetLoggerCtx(ctx, name, typeName), config.SourceCriterion)
+	if err != nil {
+		return nil, err
+	}
+
+	headerName := config.HeaderName
+	if headerName == "" {
+		headerName = DefaultHeaderName
+	}
+
+	window := time.Duration(config.Window)
+	if window == 0 {
+		window = DefaultWindow
+	}
+
+	maxKeys := config.MaxKeys
+	if maxKeys == 0 {
+		maxKeys = DefaultMaxKeys
+	}
+
+	maxResponseBodyBytes := config.MaxResponseBodyBytes
+	if maxResponseBodyBytes == 0 {
+		maxResponseBodyBytes = DefaultMaxResponseBodyBytes
+	}
+
+	maxRequestBodyBytes := config.MaxRequestBodyBytes
+	if maxRequestBodyBytes == 0 {
+		maxRequestBodyBytes = DefaultMaxRequestBodyBytes
+	}
+
+	var s store = newMemoryStore(window, maxKeys)
+	if config.Redis != nil {
+		if config.Redis.Address == "" {
+			return nil, errors.New("redis address cannot be empty")
+		}
+
+		s = newRedisStore(*config.Redis, name, window)
+	}
+
+	return &idempotency{
+		next:                 next,
+		name:                 name,
+		headerName:           headerName,
+		sourceExtractor:      sourceExtractor,
+		maxResponseBodyBytes: maxResponseBodyBytes,
+		maxRequestBodyBytes:  maxRequestBodyBytes,
+		store:                s,
+	}, nil
+}
+
+func (i *idempotency) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return i.name, tracing.SpanKindNoneEnum
+}
+
+func (i *idempotency) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	idempotencyKey := req.Header.Get(i.headerName)
+	if idempotencyKey == "" {
+		i.next.ServeHTTP(rw, req)
+		return
+	}
+
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), i.name, typeName))
+
+	source, _, err := i.sourceExtractor.Extract(req)
+	if err != nil {
+		logger.Errorf("Unable to extract source of request: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	fingerprint, err := i.fingerprint(rw, req)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			logger.Debugf("Rejecting request with idempotency key %q: %v", idempotencyKey, err)
+			http.Error(rw, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		logger.Debugf("Unable to read body of the request with idempotency key %q: %v", idempotencyKey, err)
+		http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	key := storeKey(source, req.Method, req.URL.Path, idempotencyKey)
+
+	e, err := i.store.reserve(req.Context(), key, fingerprint)
+	if err != nil {
+		logger.Errorf("Unable to reserve idempotency key: %v", err)
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	if e != nil {
+		if e.Fingerprint != fingerprint {
+			logger.Debugf("Rejecting request reusing idempotency key %q for a different request", idempotencyKey)
+			http.Error(rw, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+			return
+		}
+
+		if e.InProgress || e.Truncated {
+			logger.Debugf("Rejecting duplicate request with idempotency key %q", idempotencyKey)
+			http.Error(rw, http.StatusText(http.StatusConflict), http.StatusConflict)
+			return
+		}
+
+		logger.Debugf("Replaying response of the request with idempotency key %q", idempotencyKey)
+
+		for name, values := range e.Header {
+			rw.Header()[name] = values
+		}
+		rw.Header().Set(replayedHeader, "true")
+		rw.WriteHeader(e.StatusCode)
+		_, _ = rw.Write(e.Body)
+		return
+	}
+
+	recorder := &responseRecorder{ctx: req.Context(), rw: rw, maxBodyBytes: i.maxResponseBodyBytes}
+
+	completed := false
+	defer func() {
+		if completed {
+			return
+		}
+
+		// The request did not get a response, because the next handler panicked,
+		// or because the client went away before it, so that it can be sent again.
+		// The context of the request may be canceled already.
+		if err := i.store.release(context.WithoutCancel(req.Context()), key); err != nil {
+			logger.Errorf("Unable to release idempotency key: %v", err)
+		}
+	}()
+
+	i.next.ServeHTTP(recorder, req)
+
+	// An empty response is sent when the next handler writes nothing.
+	if recorder.code == 0 {
+		recorder.WriteHeader(http.StatusOK)
+	}
+
+	if recorder.canceled {
+		return
+	}
+
+	completed = true
+
+	err = i.store.complete(context.WithoutCancel(req.Context()), key, &entry{
+		Fingerprint: fingerprint,
+		Truncated:   recorder.truncated,
+		StatusCode:  recorder.code,
+		Header:      recorder.header,
+		Body:        recorder.body.Bytes(),
+	})
+	if err != nil {
+		logger.Errorf("Unable to remember response of idempotency key: %v", err)
+	}
+}
+
+// fingerprint reads the body of the request, which is replaced for the next handler,
+// and returns the hash of its query, content type, and body,
+// which tells apart the requests reusing an idempotency key.
+func (i *idempotency) fingerprint(rw http.ResponseWriter, req *http.Request) (string, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(http.MaxBytesReader(rw, req.Body, i.maxRequestBodyBytes))
+		if err != nil {
+			return "", err
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	return hash(req.URL.RawQuery, req.Header.Get("Content-Type"), string(body)), nil
+}
+
+// storeKey returns the key under which the idempotency key of a request is remembered,
+// which is scoped by client, method, and path.
+func storeKey(source, method, path, idempotencyKey string) string {
+	return hash(source, method, path, idempotencyKey)
+}
+
+func hash(values ...string) string {
+	h := sha256.New()
+	for _, value := range values {
+		_, _ = io.WriteString(h, value)
+		_, _ = h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder records the response of the request which reserved an idempotency key, while writing it.
+type responseRecorder struct {
+	ctx          context.Context
+	rw           http.ResponseWriter
+	maxBodyBytes int64
+
+	code      int
+	header    http.Header
+	body      bytes.Buffer
+	truncated bool
+	// canceled tells whether the request was canceled before the response,
+	// which is then the error response written by the reverse proxy, rather than the one of the backend.
+	canceled bool
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.rw.Header()
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.code != 0 {
+		return
+	}
+
+	// The informational responses are forwarded, but not remembered.
+	if code >= http.StatusContinue && code < http.StatusOK {
+		r.rw.WriteHeader(code)
+		return
+	}
+
+	r.code = code
+	r.header = r.rw.Header().Clone()
+	r.canceled = r.ctx.Err() != nil
+	r.rw.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.code == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if !r.truncated {
+		if int64(r.body.Len()+len(b)) > r.maxBodyBytes {
+			r.truncated = true
+			r.body = bytes.Buffer{}
+		} else {
+			r.body.Write(b)
+		}
+	}
+
+	return r.rw.Write(b)
+}
+
+func (r *responseRecorder) Flush() {
+	if f, ok := r.rw.(http.Flusher); ok {
+		f.Flush()
+	}
+}
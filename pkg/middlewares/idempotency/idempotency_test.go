@@ -0,0 +1,331 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	_, err := New(context.Background(), next, dynamic.Idempotency{Window: -1}, "test")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), next, dynamic.Idempotency{MaxKeys: -1}, "test")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), next, dynamic.Idempotency{MaxResponseBodyBytes: -1}, "test")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), next, dynamic.Idempotency{MaxRequestBodyBytes: -1}, "test")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), next, dynamic.Idempotency{Redis: &dynamic.IdempotencyRedis{}}, "test")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), next, dynamic.Idempotency{
+		SourceCriterion: &dynamic.SourceCriterion{RequestHeaderName: "X-Client", RequestHost: true},
+	}, "test")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), next, dynamic.Idempotency{}, "test")
+	assert.NoError(t, err)
+}
+
+func TestIdempotency(t *testing.T) {
+	var calls atomic.Int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls.Add(1)
+		rw.Header().Set("X-Payment", "42")
+		rw.WriteHeader(http.StatusCreated)
+		_, _ = rw.Write([]byte("paid"))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Idempotency{}, "test")
+	require.NoError(t, err)
+
+	send := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/pay", nil)
+		if key != "" {
+			req.Header.Set(DefaultHeaderName, key)
+		}
+
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+
+		return rw
+	}
+
+	rw := send("foo")
+	assert.Equal(t, http.StatusCreated, rw.Code)
+	assert.Equal(t, "paid", rw.Body.String())
+	assert.Empty(t, rw.Header().Get(replayedHeader))
+
+	rw = send("foo")
+	assert.Equal(t, http.StatusCreated, rw.Code)
+	assert.Equal(t, "paid", rw.Body.String())
+	assert.Equal(t, "42", rw.Header().Get("X-Payment"))
+	assert.Equal(t, "true", rw.Header().Get(replayedHeader))
+	assert.Equal(t, int32(1), calls.Load())
+
+	send("bar")
+	assert.Equal(t, int32(2), calls.Load())
+
+	// The requests without an idempotency key are not guarded.
+	send("")
+	send("")
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestIdempotency_scope(t *testing.T) {
+	var calls atomic.Int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls.Add(1)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Idempotency{
+		SourceCriterion: &dynamic.SourceCriterion{RequestHeaderName: "X-Client"},
+	}, "test")
+	require.NoError(t, err)
+
+	send := func(client, method, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		req.Header.Set(DefaultHeaderName, "foo")
+		req.Header.Set("X-Client", client)
+
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+
+		return rw
+	}
+
+	send("alice", http.MethodPost, "/pay")
+	assert.Equal(t, int32(1), calls.Load())
+
+	rw := send("alice", http.MethodPost, "/pay")
+	assert.Equal(t, "true", rw.Header().Get(replayedHeader))
+	assert.Equal(t, int32(1), calls.Load())
+
+	// The same key is kept apart for another client, method, or path.
+	for _, test := range []struct{ client, method, target string }{
+		{client: "bob", method: http.MethodPost, target: "/pay"},
+		{client: "alice", method: http.MethodPut, target: "/pay"},
+		{client: "alice", method: http.MethodPost, target: "/refund"},
+	} {
+		rw = send(test.client, test.method, test.target)
+		assert.Empty(t, rw.Header().Get(replayedHeader))
+	}
+
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestIdempotency_mismatch(t *testing.T) {
+	var calls atomic.Int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls.Add(1)
+
+		// The body is still readable by the next handler.
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		_, _ = rw.Write(body)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Idempotency{MaxRequestBodyBytes: 8}, "test")
+	require.NoError(t, err)
+
+	send := func(target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set(DefaultHeaderName, "foo")
+
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+
+		return rw
+	}
+
+	rw := send("/pay", "42")
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "42", rw.Body.String())
+
+	rw = send("/pay", "42")
+	assert.Equal(t, "42", rw.Body.String())
+	assert.Equal(t, "true", rw.Header().Get(replayedHeader))
+
+	// The key is reused for a different request.
+	rw = send("/pay", "43")
+	assert.Equal(t, http.StatusUnprocessableEntity, rw.Code)
+
+	rw = send("/pay?amount=42", "42")
+	assert.Equal(t, http.StatusUnprocessableEntity, rw.Code)
+
+	rw = send("/large", strings.Repeat("a", 9))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rw.Code)
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestIdempotency_inProgress(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		close(started)
+		<-release
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Idempotency{}, "test")
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(DefaultHeaderName, "foo")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+
+	<-started
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set(DefaultHeaderName, "foo")
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusConflict, rw.Code)
+
+	close(release)
+	<-done
+}
+
+func TestIdempotency_truncated(t *testing.T) {
+	var calls atomic.Int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls.Add(1)
+		_, _ = rw.Write([]byte(strings.Repeat("a", 8)))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Idempotency{MaxResponseBodyBytes: 4}, "test")
+	require.NoError(t, err)
+
+	for _, expectedCode := range []int{http.StatusOK, http.StatusConflict} {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(DefaultHeaderName, "foo")
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+
+		assert.Equal(t, expectedCode, rw.Code)
+	}
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestIdempotency_canceled(t *testing.T) {
+	var calls atomic.Int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls.Add(1)
+		if req.Context().Err() != nil {
+			rw.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Idempotency{}, "test")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil).WithContext(ctx)
+	req.Header.Set(DefaultHeaderName, "foo")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// The key is released, as the client went away before the response.
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set(DefaultHeaderName, "foo")
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Empty(t, rw.Header().Get(replayedHeader))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestIdempotency_window(t *testing.T) {
+	var calls atomic.Int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls.Add(1)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Idempotency{Window: ptypes.Duration(50 * time.Millisecond)}, "test")
+	require.NoError(t, err)
+
+	send := func() {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(DefaultHeaderName, "foo")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	send()
+	send()
+	assert.Equal(t, int32(1), calls.Load())
+
+	time.Sleep(100 * time.Millisecond)
+
+	send()
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestMemoryStore_maxKeys(t *testing.T) {
+	s := newMemoryStore(time.Hour, 2)
+
+	for _, key := range []string{"foo", "bar", "baz"} {
+		e, err := s.reserve(context.Background(), key, "")
+		require.NoError(t, err)
+		assert.Nil(t, e)
+	}
+
+	// The oldest key was forgotten, to make room for the newest one.
+	e, err := s.reserve(context.Background(), "foo", "")
+	require.NoError(t, err)
+	assert.Nil(t, e)
+
+	e, err = s.reserve(context.Background(), "baz", "")
+	require.NoError(t, err)
+	assert.NotNil(t, e)
+}
+
+func TestRedisClientRelease(t *testing.T) {
+	config := dynamic.IdempotencyRedis{Address: "127.0.0.1:0", DB: 42}
+
+	client := acquireRedisClient(config)
+	assert.Same(t, client, acquireRedisClient(config))
+
+	releaseRedisClient(config)
+	assert.NotErrorIs(t, client.Ping(context.Background()).Err(), redis.ErrClosed)
+
+	releaseRedisClient(config)
+	assert.ErrorIs(t, client.Ping(context.Background()).Err(), redis.ErrClosed)
+
+	// The client of a store is released once the store is garbage collected.
+	client = newRedisStore(config, "test", time.Minute).client
+	assert.Eventually(t, func() bool {
+		runtime.GC()
+		return errors.Is(client.Ping(context.Background()).Err(), redis.ErrClosed)
+	}, 5*time.Second, 10*time.Millisecond)
+}
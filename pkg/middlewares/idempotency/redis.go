@@ -0,0 +1,150 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+const redisKeyPrefix = "traefik:idempotency:"
+
+var (
+	redisClientsMu sync.Mutex
+	// redisClients are shared by the stores using the same Redis server,
+	// and each of them is closed once the last of its stores is released.
+	redisClients = make(map[dynamic.IdempotencyRedis]*sharedRedisClient)
+)
+
+type sharedRedisClient struct {
+	client *redis.Client
+	refs   int
+}
+
+// acquireRedisClient returns the client of the given Redis server, which must be released once no longer used.
+func acquireRedisClient(config dynamic.IdempotencyRedis) *redis.Client {
+	redisClientsMu.Lock()
+	defer redisClientsMu.Unlock()
+
+	shared, ok := redisClients[config]
+	if !ok {
+		shared = &sharedRedisClient{
+			client: redis.NewClient(&redis.Options{
+				Addr:     config.Address,
+				Username: config.Username,
+				Password: config.Password,
+				DB:       config.DB,
+			}),
+		}
+		redisClients[config] = shared
+	}
+
+	shared.refs++
+
+	return shared.client
+}
+
+// releaseRedisClient releases the client of the given Redis server, and closes it if it is no longer used.
+func releaseRedisClient(config dynamic.IdempotencyRedis) {
+	redisClientsMu.Lock()
+	defer redisClientsMu.Unlock()
+
+	shared, ok := redisClients[config]
+	if !ok {
+		return
+	}
+
+	shared.refs--
+	if shared.refs > 0 {
+		return
+	}
+
+	delete(redisClients, config)
+	_ = shared.client.Close()
+}
+
+// redisStore is a store remembering the keys in a Redis server, where they expire at the end of the window.
+type redisStore struct {
+	config dynamic.IdempotencyRedis
+	client *redis.Client
+	prefix string
+	window time.Duration
+}
+
+func newRedisStore(config dynamic.IdempotencyRedis, name string, window time.Duration) *redisStore {
+	s := &redisStore{
+		config: config,
+		client: acquireRedisClient(config),
+		prefix: redisKeyPrefix + name + ":",
+		window: window,
+	}
+
+	// The middlewares are dropped without notice when the configuration is reloaded,
+	// so the client is released once the store of a dropped middleware is garbage collected.
+	runtime.SetFinalizer(s, func(s *redisStore) { releaseRedisClient(s.config) })
+
+	return s
+}
+
+func (s *redisStore) reserve(ctx context.Context, key, fingerprint string) (*entry, error) {
+	reservation, err := json.Marshal(entry{InProgress: true, Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		reserved, err := s.client.SetNX(ctx, s.prefix+key, reservation, s.window).Result()
+		if err != nil {
+			return nil, fmt.Errorf("reserving idempotency key: %w", err)
+		}
+
+		if reserved {
+			return nil, nil
+		}
+
+		value, err := s.client.Get(ctx, s.prefix+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			// The key expired, or was released, in the meantime.
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getting idempotency key: %w", err)
+		}
+
+		var e entry
+		if err = json.Unmarshal(value, &e); err != nil {
+			return nil, fmt.Errorf("decoding idempotency key: %w", err)
+		}
+
+		return &e, nil
+	}
+}
+
+func (s *redisStore) complete(ctx context.Context, key string, e *entry) error {
+	value, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	// The key keeps expiring at the end of the window started by its reservation.
+	// It is not set again if it expired in the meantime.
+	if err = s.client.SetXX(ctx, s.prefix+key, value, redis.KeepTTL).Err(); err != nil {
+		return fmt.Errorf("completing idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (s *redisStore) release(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, s.prefix+key).Err(); err != nil {
+		return fmt.Errorf("releasing idempotency key: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package idempotency
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// entry is the state of the request which reserved an idempotency key.
+type entry struct {
+	// InProgress tells whether the request is still in progress.
+	InProgress bool `json:"inProgress,omitempty"`
+	// Fingerprint is the hash of the request, which tells apart the requests reusing the key.
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Truncated tells whether the response body was too large to be remembered, in which case the response cannot be replayed.
+	Truncated  bool        `json:"truncated,omitempty"`
+	StatusCode int         `json:"statusCode,omitempty"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       []byte      `json:"body,omitempty"`
+}
+
+// store remembers the idempotency keys, for the duration of the window, from their reservation.
+type store interface {
+	// reserve reserves the key for a request with the given fingerprint, and returns nil,
+	// or returns the entry of the request which reserved it first.
+	reserve(ctx context.Context, key, fingerprint string) (*entry, error)
+	// complete remembers the response of the request which reserved the key.
+	complete(ctx context.Context, key string, e *entry) error
+	// release forgets the key, for the request to be sent again.
+	release(ctx context.Context, key string) error
+}
+
+type memoryEntry struct {
+	key       string
+	entry     *entry
+	expiresAt time.Time
+}
+
+// memoryStore is a store remembering a bounded number of keys in memory, forgetting the oldest ones first.
+type memoryStore struct {
+	window  time.Duration
+	maxKeys int
+
+	mu sync.Mutex
+	// entries are ordered by reservation, and thus by expiration, the oldest first.
+	entries *list.List
+	keys    map[string]*list.Element
+}
+
+func newMemoryStore(window time.Duration, maxKeys int) *memoryStore {
+	return &memoryStore{
+		window:  window,
+		maxKeys: maxKeys,
+		entries: list.New(),
+		keys:    make(map[string]*list.Element),
+	}
+}
+
+func (s *memoryStore) reserve(_ context.Context, key, fingerprint string) (*entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.removeExpired(now)
+
+	if elt, ok := s.keys[key]; ok {
+		return elt.Value.(*memoryEntry).entry, nil
+	}
+
+	for s.entries.Len() >= s.maxKeys {
+		s.remove(s.entries.Front())
+	}
+
+	s.keys[key] = s.entries.PushBack(&memoryEntry{
+		key:       key,
+		entry:     &entry{InProgress: true, Fingerprint: fingerprint},
+		expiresAt: now.Add(s.window),
+	})
+
+	return nil, nil
+}
+
+func (s *memoryStore) complete(_ context.Context, key string, e *entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The key may have been forgotten in the meantime, to make room for the newer ones.
+	if elt, ok := s.keys[key]; ok {
+		elt.Value.(*memoryEntry).entry = e
+	}
+
+	return nil
+}
+
+func (s *memoryStore) release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elt, ok := s.keys[key]; ok {
+		s.remove(elt)
+	}
+
+	return nil
+}
+
+func (s *memoryStore) removeExpired(now time.Time) {
+	for elt := s.entries.Front(); elt != nil && !now.Before(elt.Value.(*memoryEntry).expiresAt); elt = s.entries.Front() {
+		s.remove(elt)
+	}
+}
+
+func (s *memoryStore) remove(elt *list.Element) {
+	delete(s.keys, elt.Value.(*memoryEntry).key)
+	s.entries.Remove(elt)
+}
@@ -0,0 +1,77 @@
+package jwtclaims
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/jwtclaims"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const typeName = "JWTClaims"
+
+type jwtClaimsMiddleware struct {
+	next           http.Handler
+	name           string
+	headerName     string
+	forwardHeaders map[string]string
+}
+
+// New creates a new middleware extracting the claims of the JSON Web Token of the requests.
+func New(ctx context.Context, next http.Handler, config dynamic.JWTClaims, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	headerName := config.HeaderName
+	if headerName == "" {
+		headerName = jwtclaims.DefaultHeader
+	}
+
+	forwardHeaders := make(map[string]string, len(config.ForwardHeaders))
+	for header, claim := range config.ForwardHeaders {
+		forwardHeaders[http.CanonicalHeaderKey(header)] = claim
+	}
+
+	return &jwtClaimsMiddleware{
+		next:           next,
+		name:           name,
+		headerName:     headerName,
+		forwardHeaders: forwardHeaders,
+	}, nil
+}
+
+func (m *jwtClaimsMiddleware) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return m.name, tracing.SpanKindNoneEnum
+}
+
+func (m *jwtClaimsMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The forwarded headers sent by the client are never trusted.
+	for header := range m.forwardHeaders {
+		req.Header.Del(header)
+	}
+
+	token := jwtclaims.Token(req.Header.Get(m.headerName))
+	if token == "" {
+		m.next.ServeHTTP(rw, req)
+		return
+	}
+
+	claims, err := jwtclaims.Parse(token)
+	if err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), m.name, typeName)).Debugf("Invalid token: %v", err)
+		m.next.ServeHTTP(rw, req)
+		return
+	}
+
+	for header, claim := range m.forwardHeaders {
+		if values := claims.Values(claim); len(values) > 0 {
+			req.Header.Set(header, strings.Join(values, ","))
+		}
+	}
+
+	m.next.ServeHTTP(rw, req.WithContext(jwtclaims.WithClaims(req.Context(), claims)))
+}
@@ -0,0 +1,98 @@
+package jwtclaims
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/jwtclaims"
+)
+
+func token(payload string) string {
+	return "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".c2lnbmF0dXJl"
+}
+
+func TestJWTClaims(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          dynamic.JWTClaims
+		headers         map[string]string
+		expectedClaims  jwtclaims.Claims
+		expectedHeaders map[string]string
+	}{
+		{
+			desc:           "bearer token",
+			headers:        map[string]string{"Authorization": "Bearer " + token(`{"plan":"paid"}`)},
+			expectedClaims: jwtclaims.Claims{"plan": "paid"},
+		},
+		{
+			desc:           "token in a custom header",
+			config:         dynamic.JWTClaims{HeaderName: "X-Token"},
+			headers:        map[string]string{"X-Token": token(`{"plan":"paid"}`)},
+			expectedClaims: jwtclaims.Claims{"plan": "paid"},
+		},
+		{
+			desc: "forwarded claims",
+			config: dynamic.JWTClaims{ForwardHeaders: map[string]string{
+				"x-plan":  "plan",
+				"X-Roles": "roles",
+				"X-Org":   "org.id",
+			}},
+			headers:        map[string]string{"Authorization": "Bearer " + token(`{"plan":"paid","roles":["foo","bar"]}`), "X-Org": "spoofed"},
+			expectedClaims: jwtclaims.Claims{"plan": "paid", "roles": []interface{}{"foo", "bar"}},
+			expectedHeaders: map[string]string{
+				"X-Plan":  "paid",
+				"X-Roles": "foo,bar",
+				"X-Org":   "",
+			},
+		},
+		{
+			desc:            "without token",
+			config:          dynamic.JWTClaims{ForwardHeaders: map[string]string{"X-Plan": "plan"}},
+			headers:         map[string]string{"X-Plan": "spoofed"},
+			expectedHeaders: map[string]string{"X-Plan": ""},
+		},
+		{
+			desc:            "invalid token",
+			config:          dynamic.JWTClaims{ForwardHeaders: map[string]string{"X-Plan": "plan"}},
+			headers:         map[string]string{"Authorization": "Bearer foo", "X-Plan": "spoofed"},
+			expectedHeaders: map[string]string{"X-Plan": ""},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				claims  jwtclaims.Claims
+				headers http.Header
+			)
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				claims, _ = jwtclaims.FromContext(req.Context())
+				headers = req.Header
+			})
+
+			handler, err := New(context.Background(), next, test.config, "jwtClaims")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, test.expectedClaims, claims)
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, headers.Get(name), name)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package maintenance
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/containous/alice"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/maintenance"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+)
+
+const (
+	typeName   = "Maintenance"
+	nameRouter = "maintenance-router"
+)
+
+type maintenanceMiddleware struct {
+	next       http.Handler
+	flags      *maintenance.Flags
+	routerName string
+}
+
+// New creates a new middleware sending the maintenance response to the requests on a router in maintenance.
+func New(ctx context.Context, next http.Handler, flags *maintenance.Flags, routerName string) http.Handler {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, nameRouter, typeName)).Debug("Creating middleware")
+
+	return &maintenanceMiddleware{
+		next:       next,
+		flags:      flags,
+		routerName: routerName,
+	}
+}
+
+// WrapRouterHandler Wraps maintenance to alice.Constructor.
+func WrapRouterHandler(ctx context.Context, flags *maintenance.Flags, routerName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return New(ctx, next, flags, routerName), nil
+	}
+}
+
+func (m *maintenanceMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !m.flags.Enabled(maintenance.ProtocolHTTP, m.routerName) {
+		m.next.ServeHTTP(rw, req)
+		return
+	}
+
+	response := m.flags.Response()
+
+	rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	rw.WriteHeader(response.StatusCode)
+
+	if _, err := rw.Write([]byte(response.Body)); err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), nameRouter, typeName)).Debugf("Error while writing the maintenance response: %v", err)
+	}
+}
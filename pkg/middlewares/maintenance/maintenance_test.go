@@ -0,0 +1,44 @@
+package maintenance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/traefik/traefik/v2/pkg/maintenance"
+)
+
+func TestMaintenanceMiddleware(t *testing.T) {
+	flags := maintenance.NewFlags(maintenance.Response{StatusCode: http.StatusServiceUnavailable, Body: "Under maintenance"})
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte("next"))
+	})
+
+	handler := New(context.Background(), next, flags, "foo@file")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.bar", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "next", recorder.Body.String())
+
+	flags.Set(maintenance.ProtocolHTTP, "foo@file", true)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.bar", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "Under maintenance", recorder.Body.String())
+
+	// The TCP router with the same name is not in maintenance.
+	flags.Reset()
+	flags.Set(maintenance.ProtocolTCP, "foo@file", true)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.bar", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,231 @@
+package priorityshedding
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	httpmuxer "github.com/traefik/traefik/v2/pkg/muxer/http"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "PriorityShedding"
+
+	// latencySmoothing is the factor applied to the latency of each response in the average latency.
+	latencySmoothing = 0.1
+	// adjustPeriod is the minimum period between two changes of the shed level.
+	adjustPeriod = time.Second
+	// recoveryRatio is the ratio of the maximum latency the average latency must go below for the shed level to decrease.
+	recoveryRatio = 0.8
+)
+
+type priorityShedding struct {
+	next           http.Handler
+	name           string
+	muxer          *httpmuxer.Muxer
+	levels         int
+	maxConcurrency int64
+	maxQueueDelay  time.Duration
+	maxLatency     time.Duration
+
+	mu       sync.Mutex
+	inFlight int64
+	queue    queue
+	seq      uint64
+	// latency is the average latency of the responses.
+	latency float64
+	// shedLevel is the level below which the requests are shed, because of the latency.
+	shedLevel  int
+	lastAdjust time.Time
+}
+
+// New creates a new priority shedding middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.PriorityShedding, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if config.MaxConcurrency < 0 {
+		return nil, fmt.Errorf("max concurrency must be positive, got %d", config.MaxConcurrency)
+	}
+
+	maxQueueDelay := time.Duration(config.MaxQueueDelay)
+	if maxQueueDelay <= 0 {
+		maxQueueDelay = time.Second
+	}
+
+	// The levels are the indexes of the distinct priorities, in increasing order,
+	// the zero level being the one of the requests not matching any tier.
+	priorities := map[int]struct{}{}
+	for tierName, tier := range config.Tiers {
+		if tier == nil || tier.Rule == "" {
+			return nil, fmt.Errorf("tier %s: rule is required", tierName)
+		}
+		if tier.Priority <= 0 {
+			return nil, fmt.Errorf("tier %s: priority must be strictly positive, got %d", tierName, tier.Priority)
+		}
+		priorities[tier.Priority] = struct{}{}
+	}
+
+	var sorted []int
+	for priority := range priorities {
+		sorted = append(sorted, priority)
+	}
+	sort.Ints(sorted)
+
+	levels := map[int]int{}
+	for i, priority := range sorted {
+		levels[priority] = i + 1
+	}
+
+	p := &priorityShedding{
+		next:           next,
+		name:           name,
+		levels:         len(sorted) + 1,
+		maxConcurrency: config.MaxConcurrency,
+		maxQueueDelay:  maxQueueDelay,
+		maxLatency:     time.Duration(config.MaxLatency),
+	}
+
+	muxer, err := httpmuxer.NewMuxer()
+	if err != nil {
+		return nil, err
+	}
+
+	for tierName, tier := range config.Tiers {
+		level := levels[tier.Priority]
+
+		// The priority of the tier is used as the route priority, so that the most important tier wins on overlapping rules.
+		err := muxer.AddRoute(tier.Rule, tier.Priority, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			p.serve(rw, req, level)
+		}))
+		if err != nil {
+			return nil, fmt.Errorf("tier %s: %w", tierName, err)
+		}
+	}
+
+	muxer.NotFoundHandler = http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		p.serve(rw, req, 0)
+	})
+
+	p.muxer = muxer
+
+	return p, nil
+}
+
+func (p *priorityShedding) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return p.name, tracing.SpanKindNoneEnum
+}
+
+func (p *priorityShedding) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	p.muxer.ServeHTTP(rw, req)
+}
+
+func (p *priorityShedding) serve(rw http.ResponseWriter, req *http.Request, level int) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), p.name, typeName))
+
+	if !p.acquire(req.Context(), level) {
+		logger.Debugf("Service overloaded, shedding request of level %d", level)
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	start := time.Now()
+	p.next.ServeHTTP(rw, req)
+	p.release(time.Since(start))
+}
+
+// acquire reserves a slot for a request of the given level,
+// waiting in the queue when the maximum concurrency is reached.
+func (p *priorityShedding) acquire(ctx context.Context, level int) bool {
+	p.mu.Lock()
+
+	if level < p.shedLevel {
+		p.mu.Unlock()
+		return false
+	}
+
+	if p.maxConcurrency == 0 || p.inFlight < p.maxConcurrency {
+		p.inFlight++
+		p.mu.Unlock()
+		return true
+	}
+
+	p.seq++
+	w := &waiter{level: level, seq: p.seq, ready: make(chan struct{})}
+	heap.Push(&p.queue, w)
+	p.mu.Unlock()
+
+	timer := time.NewTimer(p.maxQueueDelay)
+	defer timer.Stop()
+
+	select {
+	case <-w.ready:
+		return true
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if w.index >= 0 {
+		heap.Remove(&p.queue, w.index)
+		return false
+	}
+
+	// The slot was handed over while giving up, it is now released.
+	p.releaseSlot()
+	return false
+}
+
+func (p *priorityShedding) release(latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.releaseSlot()
+
+	if p.maxLatency == 0 {
+		return
+	}
+
+	if p.latency == 0 {
+		p.latency = float64(latency)
+	} else {
+		p.latency = p.latency*(1-latencySmoothing) + float64(latency)*latencySmoothing
+	}
+
+	if time.Since(p.lastAdjust) < adjustPeriod {
+		return
+	}
+
+	switch {
+	// The most important tier is never shed because of the latency.
+	case p.latency > float64(p.maxLatency) && p.shedLevel < p.levels-1:
+		p.shedLevel++
+		p.lastAdjust = time.Now()
+
+	case p.latency < float64(p.maxLatency)*recoveryRatio && p.shedLevel > 0:
+		p.shedLevel--
+		p.lastAdjust = time.Now()
+	}
+}
+
+// releaseSlot hands the slot over to the most important waiter, if any.
+// It must be called with the lock held.
+func (p *priorityShedding) releaseSlot() {
+	if p.queue.Len() == 0 {
+		p.inFlight--
+		return
+	}
+
+	w := heap.Pop(&p.queue).(*waiter)
+	close(w.ready)
+}
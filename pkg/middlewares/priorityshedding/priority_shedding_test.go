@@ -0,0 +1,173 @@
+package priorityshedding
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestPriorityShedding_queue(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan string, 10)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		started <- req.URL.Path
+		if req.URL.Path == "/blocking" {
+			<-release
+		}
+	})
+
+	handler, err := New(context.Background(), next, dynamic.PriorityShedding{
+		Tiers: map[string]*dynamic.PriorityTier{
+			"checkout": {Rule: "PathPrefix(`/checkout`)", Priority: 10},
+		},
+		MaxConcurrency: 1,
+		MaxQueueDelay:  ptypes.Duration(time.Minute),
+	}, "shedding")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	serve := func(path string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil))
+		}()
+	}
+
+	serve("/blocking")
+	assert.Equal(t, "/blocking", <-started)
+
+	serve("/beacon")
+	waitQueued(t, handler.(*priorityShedding), 1)
+
+	serve("/checkout")
+	waitQueued(t, handler.(*priorityShedding), 2)
+
+	close(release)
+
+	// The checkout request is served before the beacon one, although it arrived later.
+	assert.Equal(t, "/checkout", <-started)
+	assert.Equal(t, "/beacon", <-started)
+
+	wg.Wait()
+}
+
+func TestPriorityShedding_queueDelay(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		started <- struct{}{}
+		<-release
+	})
+
+	handler, err := New(context.Background(), next, dynamic.PriorityShedding{
+		MaxConcurrency: 1,
+		MaxQueueDelay:  ptypes.Duration(10 * time.Millisecond),
+	}, "shedding")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	}()
+	<-started
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int64(0), handler.(*priorityShedding).inFlight)
+}
+
+func TestPriorityShedding_latency(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.PriorityShedding{
+		Tiers: map[string]*dynamic.PriorityTier{
+			"checkout": {Rule: "PathPrefix(`/checkout`)", Priority: 10},
+			"api":      {Rule: "PathPrefix(`/api`)", Priority: 5},
+		},
+		MaxLatency: ptypes.Duration(time.Millisecond),
+	}, "shedding")
+	require.NoError(t, err)
+
+	p := handler.(*priorityShedding)
+
+	serve := func(path string) int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil))
+		return recorder.Code
+	}
+
+	// Raises the shed level twice.
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusOK, serve("/checkout"))
+		p.mu.Lock()
+		p.lastAdjust = time.Time{}
+		p.mu.Unlock()
+	}
+	assert.Equal(t, http.StatusOK, serve("/checkout"))
+
+	assert.Equal(t, 2, p.shedLevel)
+	assert.Equal(t, http.StatusServiceUnavailable, serve("/beacon"))
+	assert.Equal(t, http.StatusServiceUnavailable, serve("/api"))
+	assert.Equal(t, http.StatusOK, serve("/checkout"))
+}
+
+func TestNew_invalidTier(t *testing.T) {
+	testCases := []struct {
+		desc string
+		tier *dynamic.PriorityTier
+	}{
+		{
+			desc: "no rule",
+			tier: &dynamic.PriorityTier{Priority: 1},
+		},
+		{
+			desc: "invalid rule",
+			tier: &dynamic.PriorityTier{Rule: "Foo(`bar`)", Priority: 1},
+		},
+		{
+			desc: "zero priority",
+			tier: &dynamic.PriorityTier{Rule: "Path(`/foo`)"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := dynamic.PriorityShedding{Tiers: map[string]*dynamic.PriorityTier{"foo": test.tier}}
+			_, err := New(context.Background(), http.NotFoundHandler(), config, "shedding")
+			require.Error(t, err)
+		})
+	}
+}
+
+func waitQueued(t *testing.T, p *priorityShedding, n int) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.queue.Len() == n
+	}, time.Second, time.Millisecond)
+}
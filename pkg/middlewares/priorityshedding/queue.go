@@ -0,0 +1,45 @@
+package priorityshedding
+
+// waiter is a request waiting for a slot.
+type waiter struct {
+	level int
+	seq   uint64
+	ready chan struct{}
+	// index is the index of the waiter in the queue, or -1 once it has been removed from it.
+	index int
+}
+
+// queue is a priority queue of waiters, ordered by decreasing level, then by arrival.
+// It implements heap.Interface.
+type queue []*waiter
+
+func (q queue) Len() int { return len(q) }
+
+func (q queue) Less(i, j int) bool {
+	if q[i].level != q[j].level {
+		return q[i].level > q[j].level
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q queue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *queue) Push(x interface{}) {
+	w := x.(*waiter)
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+
+func (q *queue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*q = old[:n-1]
+	return w
+}
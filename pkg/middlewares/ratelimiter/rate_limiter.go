@@ -13,6 +13,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/overrides"
 	"github.com/traefik/traefik/v2/pkg/tracing"
 	"github.com/vulcand/oxy/v2/utils"
 	"golang.org/x/time/rate"
@@ -41,10 +42,13 @@ type rateLimiter struct {
 	next          http.Handler
 
 	buckets *ttlmap.TtlMap // actual buckets, keyed by source.
+
+	// overrides holds the rate set at runtime through the API, which takes precedence over the configured one.
+	overrides *overrides.Store
 }
 
 // New returns a rate limiter middleware.
-func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, name string) (http.Handler, error) {
+func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, name string, overridesStore *overrides.Store) (http.Handler, error) {
 	ctxLog := log.With(ctx, log.Str(log.MiddlewareName, name), log.Str(log.MiddlewareType, typeName))
 	log.FromContext(ctxLog).Debug("Creating middleware")
 
@@ -66,36 +70,12 @@ func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, name
 		return nil, err
 	}
 
-	burst := config.Burst
-	if burst < 1 {
-		burst = 1
-	}
-
 	period := time.Duration(config.Period)
 	if period < 0 {
 		return nil, fmt.Errorf("negative value not valid for period: %v", period)
 	}
-	if period == 0 {
-		period = time.Second
-	}
-
-	// Initialized at rate.Inf to enforce no rate limiting when config.Average == 0
-	rtl := float64(rate.Inf)
-	// No need to set any particular value for maxDelay as the reservation's delay
-	// will be <= 0 in the Inf case (i.e. the average == 0 case).
-	var maxDelay time.Duration
 
-	if config.Average > 0 {
-		rtl = float64(config.Average*int64(time.Second)) / float64(period)
-		// maxDelay does not scale well for rates below 1,
-		// so we just cap it to the corresponding value, i.e. 0.5s, in order to keep the effective rate predictable.
-		// One alternative would be to switch to a no-reservation mode (Allow() method) whenever we are in such a low rate regime.
-		if rtl < 1 {
-			maxDelay = 500 * time.Millisecond
-		} else {
-			maxDelay = time.Second / (time.Duration(rtl) * 2)
-		}
-	}
+	rtl, burst, maxDelay := limits(config.Average, period, config.Burst)
 
 	// Make the ttl inversely proportional to how often a rate limiter is supposed to see any activity (when maxed out),
 	// for low rate limiters.
@@ -117,9 +97,42 @@ func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, name
 		sourceMatcher: sourceMatcher,
 		buckets:       buckets,
 		ttl:           ttl,
+		overrides:     overridesStore,
 	}, nil
 }
 
+// limits returns the rate, the burst, and the maximum delay of the token buckets,
+// from the average number of requests allowed per period, and the burst.
+func limits(average int64, period time.Duration, burst int64) (float64, int64, time.Duration) {
+	if burst < 1 {
+		burst = 1
+	}
+
+	if period == 0 {
+		period = time.Second
+	}
+
+	// Initialized at rate.Inf to enforce no rate limiting when average == 0
+	rtl := float64(rate.Inf)
+	// No need to set any particular value for maxDelay as the reservation's delay
+	// will be <= 0 in the Inf case (i.e. the average == 0 case).
+	var maxDelay time.Duration
+
+	if average > 0 {
+		rtl = float64(average*int64(time.Second)) / float64(period)
+		// maxDelay does not scale well for rates below 1,
+		// so we just cap it to the corresponding value, i.e. 0.5s, in order to keep the effective rate predictable.
+		// One alternative would be to switch to a no-reservation mode (Allow() method) whenever we are in such a low rate regime.
+		if rtl < 1 {
+			maxDelay = 500 * time.Millisecond
+		} else {
+			maxDelay = time.Second / (time.Duration(rtl) * 2)
+		}
+	}
+
+	return rtl, burst, maxDelay
+}
+
 func (rl *rateLimiter) GetTracingInformation() (string, ext.SpanKindEnum) {
 	return rl.name, tracing.SpanKindNoneEnum
 }
@@ -139,11 +152,24 @@ func (rl *rateLimiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		logger.Infof("ignoring token bucket amount > 1: %d", amount)
 	}
 
+	limit, burst, maxDelay := rl.rate, rl.burst, rl.maxDelay
+	if params, ok := rl.overrides.Get(rl.name); ok && params.RateLimit != nil {
+		var rtl float64
+		rtl, burst, maxDelay = limits(params.RateLimit.Average, time.Duration(params.RateLimit.Period), params.RateLimit.Burst)
+		limit = rate.Limit(rtl)
+	}
+
 	var bucket *rate.Limiter
 	if rlSource, exists := rl.buckets.Get(source); exists {
 		bucket = rlSource.(*rate.Limiter)
+
+		// Applies the rate overridden, or restored, since the bucket was created.
+		if bucket.Limit() != limit || bucket.Burst() != int(burst) {
+			bucket.SetLimit(limit)
+			bucket.SetBurst(int(burst))
+		}
 	} else {
-		bucket = rate.NewLimiter(rl.rate, int(rl.burst))
+		bucket = rate.NewLimiter(limit, int(burst))
 	}
 
 	// We Set even in the case where the source already exists,
@@ -162,7 +188,7 @@ func (rl *rateLimiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	delay := res.Delay()
-	if delay > rl.maxDelay {
+	if delay > maxDelay {
 		res.Cancel()
 		rl.serveDelayError(ctx, w, delay)
 		return
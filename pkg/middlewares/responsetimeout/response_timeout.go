@@ -0,0 +1,204 @@
+package responsetimeout
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+)
+
+const typeName = "ResponseTimeout"
+
+// Kinds of timeouts, as reported in the body of the 504 Gateway Timeout responses.
+const (
+	kindHeader   = "header"
+	kindResponse = "response"
+)
+
+type responseTimeout struct {
+	next          http.Handler
+	name          string
+	headerTimeout time.Duration
+	timeout       time.Duration
+}
+
+// New creates a new handler bounding the time taken by the next handler to respond.
+// The requests whose response headers are not sent in time get a 504 Gateway Timeout response, with a JSON body,
+// and the responses whose body is not sent in time are aborted.
+func New(ctx context.Context, next http.Handler, config dynamic.ResponseTimeouts, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if config.HeaderTimeout < 0 {
+		return nil, fmt.Errorf("header timeout must be positive, got %s", time.Duration(config.HeaderTimeout))
+	}
+
+	if config.Timeout < 0 {
+		return nil, fmt.Errorf("timeout must be positive, got %s", time.Duration(config.Timeout))
+	}
+
+	if config.HeaderTimeout == 0 && config.Timeout == 0 {
+		return next, nil
+	}
+
+	return &responseTimeout{
+		next:          next,
+		name:          name,
+		headerTimeout: time.Duration(config.HeaderTimeout),
+		timeout:       time.Duration(config.Timeout),
+	}, nil
+}
+
+func (r *responseTimeout) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	trw := &responseWriter{rw: rw, cancel: cancel}
+
+	if r.headerTimeout > 0 {
+		trw.headerTimer = time.AfterFunc(r.headerTimeout, func() { trw.expire(kindHeader, r.headerTimeout) })
+		defer trw.headerTimer.Stop()
+	}
+
+	if r.timeout > 0 {
+		trw.responseTimer = time.AfterFunc(r.timeout, func() { trw.expire(kindResponse, r.timeout) })
+		defer trw.responseTimer.Stop()
+	}
+
+	r.next.ServeHTTP(trw, req.WithContext(ctx))
+
+	kind, limit := trw.expired()
+	if kind == "" {
+		return
+	}
+
+	log.FromContext(middlewares.GetLoggerCtx(req.Context(), r.name, typeName)).Debugf("Response %s timeout of %s exceeded", kind, limit)
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusGatewayTimeout)
+
+	err := json.NewEncoder(rw).Encode(timeoutBody{
+		Status:  http.StatusGatewayTimeout,
+		Error:   http.StatusText(http.StatusGatewayTimeout),
+		Timeout: kind,
+		Limit:   limit.String(),
+	})
+	if err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), r.name, typeName)).Debugf("Error while writing the timeout response: %v", err)
+	}
+}
+
+// timeoutBody is the body of the 504 Gateway Timeout responses.
+type timeoutBody struct {
+	Status  int    `json:"status"`
+	Error   string `json:"error"`
+	Timeout string `json:"timeout"`
+	Limit   string `json:"limit"`
+}
+
+// responseWriter cancels the request when a timeout expires,
+// and discards what the next handler writes afterwards, unless the response headers were already sent.
+type responseWriter struct {
+	rw            http.ResponseWriter
+	cancel        context.CancelFunc
+	headerTimer   *time.Timer
+	responseTimer *time.Timer
+
+	mu          sync.Mutex
+	wroteHeader bool
+	kind        string
+	limit       time.Duration
+}
+
+func (w *responseWriter) expire(kind string, limit time.Duration) {
+	w.mu.Lock()
+	if !w.wroteHeader && w.kind == "" {
+		w.kind = kind
+		w.limit = limit
+	}
+	w.mu.Unlock()
+
+	w.cancel()
+}
+
+// expired returns the kind and the limit of the timeout which expired before the response headers were sent, if any.
+func (w *responseWriter) expired() (string, time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.kind, w.limit
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.kind != "" || w.wroteHeader {
+		return
+	}
+
+	if code >= http.StatusOK {
+		w.wroteHeader = true
+		if w.headerTimer != nil {
+			w.headerTimer.Stop()
+		}
+	}
+
+	w.rw.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+
+	w.mu.Lock()
+	timedOut := w.kind != ""
+	w.mu.Unlock()
+
+	if timedOut {
+		return 0, errors.New("response timed out")
+	}
+
+	return w.rw.Write(b)
+}
+
+func (w *responseWriter) Flush() {
+	w.mu.Lock()
+	timedOut := w.kind != ""
+	w.mu.Unlock()
+
+	if timedOut {
+		return
+	}
+
+	if f, ok := w.rw.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", w.rw)
+	}
+
+	// The upgraded connections are not bound by the timeouts anymore.
+	for _, timer := range []*time.Timer{w.headerTimer, w.responseTimer} {
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+
+	return h.Hijack()
+}
@@ -0,0 +1,119 @@
+package responsetimeout
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	_, err := New(context.Background(), next, dynamic.ResponseTimeouts{HeaderTimeout: -1}, "test")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), next, dynamic.ResponseTimeouts{Timeout: -1}, "test")
+	assert.Error(t, err)
+
+	handler, err := New(context.Background(), next, dynamic.ResponseTimeouts{}, "test")
+	require.NoError(t, err)
+	assert.IsType(t, next, handler)
+}
+
+func TestResponseTimeout(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		config       dynamic.ResponseTimeouts
+		headerDelay  time.Duration
+		bodyDelay    time.Duration
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			desc:         "in time",
+			config:       dynamic.ResponseTimeouts{HeaderTimeout: ptypes.Duration(time.Second), Timeout: ptypes.Duration(time.Second)},
+			expectedCode: http.StatusOK,
+			expectedBody: "foobar",
+		},
+		{
+			desc:         "header timeout",
+			config:       dynamic.ResponseTimeouts{HeaderTimeout: ptypes.Duration(10 * time.Millisecond), Timeout: ptypes.Duration(time.Second)},
+			headerDelay:  time.Second,
+			expectedCode: http.StatusGatewayTimeout,
+			expectedBody: `{"status":504,"error":"Gateway Timeout","timeout":"header","limit":"10ms"}` + "\n",
+		},
+		{
+			desc:         "response timeout before the headers",
+			config:       dynamic.ResponseTimeouts{Timeout: ptypes.Duration(10 * time.Millisecond)},
+			headerDelay:  time.Second,
+			expectedCode: http.StatusGatewayTimeout,
+			expectedBody: `{"status":504,"error":"Gateway Timeout","timeout":"response","limit":"10ms"}` + "\n",
+		},
+		{
+			desc:         "slow body within the response timeout",
+			config:       dynamic.ResponseTimeouts{HeaderTimeout: ptypes.Duration(10 * time.Millisecond), Timeout: ptypes.Duration(time.Second)},
+			bodyDelay:    50 * time.Millisecond,
+			expectedCode: http.StatusOK,
+			expectedBody: "foobar",
+		},
+		{
+			desc:         "response timeout after the headers",
+			config:       dynamic.ResponseTimeouts{Timeout: ptypes.Duration(10 * time.Millisecond)},
+			bodyDelay:    time.Second,
+			expectedCode: http.StatusOK,
+			expectedBody: "foo",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// The next handler stands for the reverse proxy, which gives up and writes an error when the request is canceled.
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if !wait(req.Context(), test.headerDelay) {
+					rw.WriteHeader(http.StatusBadGateway)
+					return
+				}
+
+				rw.WriteHeader(http.StatusOK)
+				_, _ = rw.Write([]byte("foo"))
+
+				if wait(req.Context(), test.bodyDelay) {
+					_, _ = rw.Write([]byte("bar"))
+				}
+			})
+
+			handler, err := New(context.Background(), next, test.config, "test")
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, test.expectedCode, rw.Code)
+			assert.Equal(t, test.expectedBody, rw.Body.String())
+		})
+	}
+}
+
+// wait waits for the delay, and returns false if the context is canceled first.
+func wait(ctx context.Context, delay time.Duration) bool {
+	if delay == 0 {
+		return true
+	}
+
+	select {
+	case <-time.After(delay):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,68 @@
+package serveridentity
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/vulcand/oxy/v2/roundrobin/stickycookie"
+)
+
+const (
+	typeName = "ServerIdentity"
+
+	// DefaultHeader is the default name of the response header carrying the identity of the server.
+	DefaultHeader = "X-Served-By"
+)
+
+type serverIdentity struct {
+	next     http.Handler
+	header   string
+	identity func(u *url.URL) string
+}
+
+// New creates a new middleware injecting the identity of the server which handles a request in the response headers,
+// and in the access logs.
+// It must be placed after the load balancer, which sets the request URL to the one of the picked server.
+func New(ctx context.Context, next http.Handler, config dynamic.ServerIdentity, name string) http.Handler {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	header := config.HeaderName
+	if header == "" {
+		header = DefaultHeader
+	}
+
+	identity := func(u *url.URL) string {
+		return (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String()
+	}
+
+	if config.Hash {
+		// The hash is the same as the one of the hashed sticky cookie values.
+		hash := &stickycookie.HashValue{}
+		identity = func(u *url.URL) string {
+			return hash.Get(&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path})
+		}
+	}
+
+	return &serverIdentity{
+		next:     next,
+		header:   header,
+		identity: identity,
+	}
+}
+
+func (s *serverIdentity) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	identity := s.identity(req.URL)
+
+	rw.Header().Set(s.header, identity)
+
+	if logData := accesslog.GetLogData(req); logData != nil {
+		logData.Core[accesslog.ServerIdentity] = identity
+	}
+
+	s.next.ServeHTTP(rw, req)
+}
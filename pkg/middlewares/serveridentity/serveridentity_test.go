@@ -0,0 +1,72 @@
+package serveridentity
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/vulcand/oxy/v2/roundrobin/stickycookie"
+)
+
+func TestServerIdentity(t *testing.T) {
+	serverURL, err := url.Parse("http://10.0.0.1:8080")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc           string
+		config         dynamic.ServerIdentity
+		expectedHeader string
+		expectedValue  string
+	}{
+		{
+			desc:           "plain identity",
+			expectedHeader: DefaultHeader,
+			expectedValue:  "http://10.0.0.1:8080",
+		},
+		{
+			desc:           "hashed identity",
+			config:         dynamic.ServerIdentity{Hash: true},
+			expectedHeader: DefaultHeader,
+			expectedValue:  (&stickycookie.HashValue{}).Get(serverURL),
+		},
+		{
+			desc:           "custom header",
+			config:         dynamic.ServerIdentity{HeaderName: "X-Backend"},
+			expectedHeader: "X-Backend",
+			expectedValue:  "http://10.0.0.1:8080",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			handler := New(context.Background(), next, test.config, "server-identity")
+
+			logData := &accesslog.LogData{Core: accesslog.CoreLogData{}}
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo.bar/baz", nil)
+			req = req.WithContext(context.WithValue(req.Context(), accesslog.DataTableKey, logData))
+
+			// The load balancer sets the request URL to the one of the picked server.
+			req.URL = serverURL
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedValue, recorder.Header().Get(test.expectedHeader))
+			assert.Equal(t, test.expectedValue, logData.Core[accesslog.ServerIdentity])
+		})
+	}
+}
@@ -0,0 +1,401 @@
+// Package sshsession implements the SSH protocol-aware mode of the TCP routers,
+// which inspects the beginning of the SSH sessions to log the clients, enforce the protocol-version policy,
+// and measure the duration of the sessions.
+package sshsession
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+const (
+	typeName      = "SSHSession"
+	nameTCPRouter = "ssh-tcp-router"
+
+	// maxIdentificationLength is the maximum length of the identification string, CR LF included (RFC 4253, section 4.2).
+	maxIdentificationLength = 255
+	// maxPacketLength is the maximum length of the key exchange initialization packet inspected.
+	maxPacketLength = 35000
+
+	msgKexInit = 20
+)
+
+var defaultProtocolVersions = []string{"2.0", "1.99"}
+
+type handler struct {
+	next tcp.Handler
+
+	logger           log.Logger
+	logClients       bool
+	protocolVersions []string
+	deniedSoftware   []*regexp.Regexp
+
+	routerName      string
+	sessionDuration metrics.ScalableHistogram
+
+	// now is used to shift the clock in tests.
+	now func() time.Time
+}
+
+// New creates a new handler inspecting the SSH sessions going through the given router.
+func New(ctx context.Context, next tcp.Handler, config dynamic.SSHProtocol, routerName string, metricsRegistry metrics.Registry) (tcp.Handler, error) {
+	ctx = middlewares.GetLoggerCtx(ctx, nameTCPRouter, typeName)
+	logger := log.FromContext(ctx)
+	logger.Debug("Creating middleware")
+
+	protocolVersions := config.ProtocolVersions
+	if len(protocolVersions) == 0 {
+		protocolVersions = defaultProtocolVersions
+	}
+
+	var deniedSoftware []*regexp.Regexp
+	for _, expr := range config.DeniedSoftware {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("compiling the denied SSH software expression %q: %w", expr, err)
+		}
+
+		deniedSoftware = append(deniedSoftware, re)
+	}
+
+	h := &handler{
+		next:             next,
+		logger:           logger,
+		logClients:       config.LogClients,
+		protocolVersions: protocolVersions,
+		deniedSoftware:   deniedSoftware,
+		routerName:       routerName,
+		now:              time.Now,
+	}
+
+	if metricsRegistry != nil {
+		h.sessionDuration = metricsRegistry.SSHSessionDurationHistogram()
+	}
+
+	return h, nil
+}
+
+// WrapTCPRouterHandler Wraps the SSH session inspection to tcp.Constructor.
+func WrapTCPRouterHandler(ctx context.Context, config dynamic.SSHProtocol, routerName string, metricsRegistry metrics.Registry) tcp.Constructor {
+	return func(next tcp.Handler) (tcp.Handler, error) {
+		return New(ctx, next, config, routerName, metricsRegistry)
+	}
+}
+
+// ServeTCP serves the given TCP connection.
+func (h *handler) ServeTCP(conn tcp.WriteCloser) {
+	start := h.now()
+
+	c := &sshConn{WriteCloser: conn, handler: h}
+	h.next.ServeTCP(c)
+
+	if c.ident == nil || c.denied || h.sessionDuration == nil {
+		return
+	}
+
+	h.sessionDuration.
+		With("router", h.routerName, "source", sourceIP(conn.RemoteAddr())).
+		Observe(h.now().Sub(start).Seconds())
+}
+
+// identification is the identification string sent by a client.
+type identification struct {
+	protoVersion string
+	software     string
+	comments     string
+}
+
+// parseIdentification parses the identification string sent by a client, without its line ending.
+func parseIdentification(line string) (*identification, error) {
+	rest, ok := strings.CutPrefix(line, "SSH-")
+	if !ok {
+		return nil, errors.New("invalid SSH identification string")
+	}
+
+	protoVersion, rest, ok := strings.Cut(rest, "-")
+	if !ok || protoVersion == "" {
+		return nil, errors.New("invalid SSH identification string")
+	}
+
+	software, comments, _ := strings.Cut(rest, " ")
+
+	return &identification{protoVersion: protoVersion, software: software, comments: comments}, nil
+}
+
+// check tells why the given client is denied, if it is.
+func (h *handler) check(ident *identification) error {
+	allowed := false
+	for _, version := range h.protocolVersions {
+		if version == ident.protoVersion {
+			allowed = true
+			break
+		}
+	}
+
+	if !allowed {
+		return fmt.Errorf("SSH protocol version %q not allowed", ident.protoVersion)
+	}
+
+	for _, re := range h.deniedSoftware {
+		if re.MatchString(ident.software) {
+			return fmt.Errorf("SSH client software %q denied", ident.software)
+		}
+	}
+
+	return nil
+}
+
+// kexInit holds the algorithms of the key exchange initialization of a client.
+type kexInit struct {
+	kexAlgorithms string
+	encryption    string
+	mac           string
+	compression   string
+}
+
+// hassh returns the HASSH fingerprint of the client, the MD5 hash of its preferred algorithms.
+func (k *kexInit) hassh() string {
+	sum := md5.Sum([]byte(strings.Join([]string{k.kexAlgorithms, k.encryption, k.mac, k.compression}, ";")))
+	return hex.EncodeToString(sum[:])
+}
+
+// parseKexInit parses the key exchange initialization packet sent by a client,
+// without its length prefix (RFC 4253, sections 6 and 7.1).
+func parseKexInit(packet []byte) (*kexInit, error) {
+	if len(packet) < 1 || int(packet[0]) >= len(packet) {
+		return nil, errors.New("invalid SSH packet padding")
+	}
+
+	payload := packet[1 : len(packet)-int(packet[0])]
+	if len(payload) < 17 || payload[0] != msgKexInit {
+		return nil, errors.New("not an SSH key exchange initialization")
+	}
+
+	// Skips the message type and the cookie.
+	payload = payload[17:]
+
+	// The name-lists of the kex, host key, encryption, MAC and compression algorithms, client to server first.
+	var lists [8]string
+	for i := range lists {
+		if len(payload) < 4 {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		length := binary.BigEndian.Uint32(payload)
+		if uint64(length) > uint64(len(payload)-4) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		lists[i] = string(payload[4 : 4+length])
+		payload = payload[4+length:]
+	}
+
+	return &kexInit{
+		kexAlgorithms: lists[0],
+		encryption:    lists[2],
+		mac:           lists[4],
+		compression:   lists[6],
+	}, nil
+}
+
+// sshConn inspects the identification string and the key exchange initialization sent by an SSH client.
+// The identification string is held until it is complete, to enforce the policy before it reaches the backend,
+// while the key exchange initialization is only observed.
+type sshConn struct {
+	tcp.WriteCloser
+
+	handler *handler
+
+	ident  *identification
+	denied bool
+
+	// pending is the data read ahead with the identification string, not forwarded yet.
+	pending []byte
+
+	// packet is the part of the key exchange initialization observed so far.
+	packet  []byte
+	kexDone bool
+}
+
+func (c *sshConn) Read(p []byte) (int, error) {
+	if c.ident == nil {
+		if err := c.readIdentification(); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(c.pending) > 0 {
+		n := copy(p, c.pending)
+		c.pending = c.pending[n:]
+		return n, nil
+	}
+
+	n, err := c.WriteCloser.Read(p)
+	c.observe(p[:n])
+
+	return n, err
+}
+
+// NetConn returns the client connection.
+func (c *sshConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+// readIdentification reads the identification string, and enforces the policy.
+// When the client is denied, its connection is closed, and the end of the stream is returned.
+func (c *sshConn) readIdentification() error {
+	buf := make([]byte, 0, maxIdentificationLength)
+
+	for {
+		if i := bytes.IndexByte(buf, '\n'); i >= 0 {
+			c.pending = buf
+			c.observe(buf[i+1:])
+			return c.identify(strings.TrimSuffix(string(buf[:i]), "\r"))
+		}
+
+		if len(buf) == cap(buf) {
+			return c.deny(errors.New("SSH identification string too long"))
+		}
+
+		n, err := c.WriteCloser.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+
+		if err != nil {
+			// The error is returned again by the next read, once the identification string is forwarded.
+			if bytes.IndexByte(buf, '\n') >= 0 {
+				continue
+			}
+
+			if errors.Is(err, io.EOF) && len(buf) > 0 {
+				return io.ErrUnexpectedEOF
+			}
+
+			return err
+		}
+	}
+}
+
+func (c *sshConn) identify(line string) error {
+	ident, err := parseIdentification(line)
+	if err != nil {
+		return c.deny(err)
+	}
+
+	c.ident = ident
+
+	if err := c.handler.check(ident); err != nil {
+		return c.deny(err)
+	}
+
+	return nil
+}
+
+func (c *sshConn) deny(reason error) error {
+	c.denied = true
+
+	c.handler.logger.Debugf("Denying the SSH client %s: %v", c.RemoteAddr(), reason)
+
+	if c.handler.logClients {
+		c.logClient(nil, reason)
+	}
+
+	_ = c.WriteCloser.Close()
+
+	return io.EOF
+}
+
+// observe collects the bytes of the key exchange initialization sent by the client,
+// which is the first binary packet following the identification string.
+func (c *sshConn) observe(data []byte) {
+	if c.kexDone || len(data) == 0 {
+		return
+	}
+
+	c.packet = append(c.packet, data...)
+	if len(c.packet) < 4 {
+		return
+	}
+
+	length := binary.BigEndian.Uint32(c.packet)
+	if length > maxPacketLength {
+		c.endObservation(nil, errors.New("SSH packet too long"))
+		return
+	}
+
+	if uint32(len(c.packet)-4) < length {
+		return
+	}
+
+	kex, err := parseKexInit(c.packet[4 : 4+length])
+	c.endObservation(kex, err)
+}
+
+func (c *sshConn) endObservation(kex *kexInit, err error) {
+	c.kexDone = true
+	c.packet = nil
+
+	if err != nil {
+		c.handler.logger.Debugf("Unable to parse the SSH key exchange initialization from %s: %v", c.RemoteAddr(), err)
+	}
+
+	if c.handler.logClients {
+		c.logClient(kex, nil)
+	}
+}
+
+// logClient logs the identification and the key exchange algorithms of the client.
+func (c *sshConn) logClient(kex *kexInit, denied error) {
+	logger := c.handler.logger.WithField("client", c.RemoteAddr().String())
+
+	if c.ident != nil {
+		logger = logger.
+			WithField("protocolVersion", c.ident.protoVersion).
+			WithField("software", c.ident.software)
+
+		if c.ident.comments != "" {
+			logger = logger.WithField("comments", c.ident.comments)
+		}
+	}
+
+	if kex != nil {
+		logger = logger.
+			WithField("kexAlgorithms", kex.kexAlgorithms).
+			WithField("hassh", kex.hassh())
+	}
+
+	if denied != nil {
+		logger = logger.WithField("denied", denied.Error())
+	}
+
+	logger.Info("SSH client")
+}
+
+// sourceIP returns the IP of the given address.
+func sourceIP(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+
+	return host
+}
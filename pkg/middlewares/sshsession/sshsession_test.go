@@ -0,0 +1,253 @@
+package sshsession
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestParseIdentification(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		line      string
+		expected  *identification
+		expectErr bool
+	}{
+		{
+			desc:     "software with comments",
+			line:     "SSH-2.0-OpenSSH_9.3p1 Ubuntu-1ubuntu3",
+			expected: &identification{protoVersion: "2.0", software: "OpenSSH_9.3p1", comments: "Ubuntu-1ubuntu3"},
+		},
+		{
+			desc:     "software without comments",
+			line:     "SSH-1.99-PuTTY_Release_0.78",
+			expected: &identification{protoVersion: "1.99", software: "PuTTY_Release_0.78"},
+		},
+		{
+			desc:      "not an identification string",
+			line:      "GET / HTTP/1.1",
+			expectErr: true,
+		},
+		{
+			desc:      "missing software",
+			line:      "SSH-2.0",
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ident, err := parseIdentification(test.line)
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, ident)
+		})
+	}
+}
+
+func TestParseKexInit(t *testing.T) {
+	packet := kexInitPacket()
+
+	kex, err := parseKexInit(packet[4:])
+	require.NoError(t, err)
+
+	assert.Equal(t, "curve25519-sha256,diffie-hellman-group14-sha256", kex.kexAlgorithms)
+	assert.Equal(t, "aes128-ctr", kex.encryption)
+	assert.Equal(t, "hmac-sha2-256", kex.mac)
+	assert.Equal(t, "none", kex.compression)
+	assert.Equal(t, "fccfa11c7d95c9b773dd0ba461c6a7ef", kex.hassh())
+
+	_, err = parseKexInit(packet[4:20])
+	assert.Error(t, err)
+}
+
+func TestHandler(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		config            dynamic.SSHProtocol
+		identification    string
+		expectedForwarded bool
+	}{
+		{
+			desc:              "default protocol versions",
+			identification:    "SSH-2.0-OpenSSH_9.3\r\n",
+			expectedForwarded: true,
+		},
+		{
+			desc:              "compatibility protocol version",
+			identification:    "SSH-1.99-OpenSSH_9.3\r\n",
+			expectedForwarded: true,
+		},
+		{
+			desc:              "protocol version not allowed",
+			identification:    "SSH-1.5-OpenSSH_9.3\r\n",
+			expectedForwarded: false,
+		},
+		{
+			desc:              "protocol version not allowed by the configuration",
+			config:            dynamic.SSHProtocol{ProtocolVersions: []string{"2.0"}},
+			identification:    "SSH-1.99-OpenSSH_9.3\r\n",
+			expectedForwarded: false,
+		},
+		{
+			desc:              "denied software",
+			config:            dynamic.SSHProtocol{DeniedSoftware: []string{`^PuTTY_Release_0\.[0-6]`}},
+			identification:    "SSH-2.0-PuTTY_Release_0.62\r\n",
+			expectedForwarded: false,
+		},
+		{
+			desc:              "software not denied",
+			config:            dynamic.SSHProtocol{DeniedSoftware: []string{`^PuTTY_Release_0\.[0-6]`}},
+			identification:    "SSH-2.0-PuTTY_Release_0.78\r\n",
+			expectedForwarded: true,
+		},
+		{
+			desc:              "line feed only",
+			identification:    "SSH-2.0-OpenSSH_9.3\n",
+			expectedForwarded: true,
+		},
+		{
+			desc:              "not SSH",
+			identification:    "GET / HTTP/1.1\r\n",
+			expectedForwarded: false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			forwarded := make(chan []byte, 1)
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+				data, _ := io.ReadAll(conn)
+				forwarded <- data
+			})
+
+			handler, err := New(context.Background(), next, test.config, "ssh@file", metrics.NewVoidRegistry())
+			require.NoError(t, err)
+
+			sent := append([]byte(test.identification), kexInitPacket()...)
+
+			server, client := net.Pipe()
+			go func() {
+				_, _ = client.Write(sent)
+				_ = client.Close()
+			}()
+
+			handler.ServeTCP(fakeConn{Conn: server})
+
+			if test.expectedForwarded {
+				assert.Equal(t, sent, <-forwarded)
+				return
+			}
+
+			assert.Empty(t, <-forwarded)
+		})
+	}
+}
+
+func TestHandler_sessionDuration(t *testing.T) {
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		_, _ = io.ReadAll(conn)
+	})
+
+	h, err := New(context.Background(), next, dynamic.SSHProtocol{}, "ssh@file", nil)
+	require.NoError(t, err)
+
+	histogram := &histogramMock{}
+	handler := h.(*handler)
+	handler.sessionDuration = histogram
+
+	now := time.Now()
+	handler.now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+
+	serve := func(data string) {
+		server, client := net.Pipe()
+		go func() {
+			_, _ = client.Write([]byte(data))
+			_ = client.Close()
+		}()
+
+		handler.ServeTCP(fakeConn{Conn: server})
+	}
+
+	serve("SSH-2.0-OpenSSH_9.3\r\n")
+	serve("SSH-1.0-OpenSSH_9.3\r\n")
+
+	// Only the accepted session is observed.
+	assert.Equal(t, []float64{60}, histogram.observed)
+	assert.Equal(t, []string{"router", "ssh@file", "source", "pipe"}, histogram.labels)
+}
+
+// kexInitPacket returns a key exchange initialization packet, with its length prefix.
+func kexInitPacket() []byte {
+	payload := []byte{msgKexInit}
+	payload = append(payload, make([]byte, 16)...)
+
+	lists := []string{
+		"curve25519-sha256,diffie-hellman-group14-sha256",
+		"ssh-ed25519",
+		"aes128-ctr", "aes128-ctr",
+		"hmac-sha2-256", "hmac-sha2-256",
+		"none", "none",
+		"", "",
+	}
+	for _, list := range lists {
+		payload = binary.BigEndian.AppendUint32(payload, uint32(len(list)))
+		payload = append(payload, list...)
+	}
+
+	// The first kex packet follows flag, and the reserved field.
+	payload = append(payload, 0, 0, 0, 0, 0)
+
+	padding := 4
+	packet := binary.BigEndian.AppendUint32(nil, uint32(1+len(payload)+padding))
+	packet = append(packet, byte(padding))
+	packet = append(packet, payload...)
+
+	return append(packet, make([]byte, padding)...)
+}
+
+type fakeConn struct {
+	net.Conn
+}
+
+func (c fakeConn) CloseWrite() error {
+	return nil
+}
+
+type histogramMock struct {
+	labels   []string
+	observed []float64
+}
+
+func (h *histogramMock) With(labelValues ...string) metrics.ScalableHistogram {
+	h.labels = labelValues
+	return h
+}
+
+func (h *histogramMock) Observe(v float64) {
+	h.observed = append(h.observed, v)
+}
+
+func (h *histogramMock) ObserveFromStart(start time.Time) {}
@@ -0,0 +1,82 @@
+package tcpbandwidth
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+
+	"github.com/traefik/traefik/v2/pkg/bandwidth"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+const (
+	typeName   = "BandwidthTCP"
+	nameRouter = "bandwidth-tcp-router"
+)
+
+type bandwidthMiddleware struct {
+	next       tcp.Handler
+	accountant *bandwidth.Accountant
+	key        bandwidth.Key
+}
+
+// New creates a new middleware accounting the bytes transferred by a TCP router.
+func New(ctx context.Context, next tcp.Handler, accountant *bandwidth.Accountant, key bandwidth.Key) tcp.Handler {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, nameRouter, typeName)).Debug("Creating middleware")
+
+	return &bandwidthMiddleware{
+		next:       next,
+		accountant: accountant,
+		key:        key,
+	}
+}
+
+// WrapRouterHandler Wraps bytes accounting to tcp.Constructor.
+func WrapRouterHandler(ctx context.Context, accountant *bandwidth.Accountant, key bandwidth.Key) tcp.Constructor {
+	return func(next tcp.Handler) (tcp.Handler, error) {
+		return New(ctx, next, accountant, key), nil
+	}
+}
+
+// ServeTCP serves the given TCP connection.
+func (m *bandwidthMiddleware) ServeTCP(conn tcp.WriteCloser) {
+	counter := &countingConn{WriteCloser: conn}
+
+	m.next.ServeTCP(counter)
+
+	// The TCP connections are not compressed, so their logical bytes are their wire bytes.
+	read, written := counter.read.Load(), counter.written.Load()
+	m.accountant.Observe(m.key, bandwidth.Usage{
+		WireRequestBytes:     read,
+		WireResponseBytes:    written,
+		LogicalRequestBytes:  read,
+		LogicalResponseBytes: written,
+	})
+}
+
+// countingConn counts the bytes read from and written to the client.
+type countingConn struct {
+	tcp.WriteCloser
+
+	read    atomic.Int64
+	written atomic.Int64
+}
+
+func (c *countingConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+	c.read.Add(int64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(p []byte) (int, error) {
+	n, err := c.WriteCloser.Write(p)
+	c.written.Add(int64(n))
+	return n, err
+}
+
+// NetConn returns the client connection.
+func (c *countingConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
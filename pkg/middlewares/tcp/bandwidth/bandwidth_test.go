@@ -0,0 +1,56 @@
+package tcpbandwidth
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/bandwidth"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestBandwidthMiddleware_ServeTCP(t *testing.T) {
+	accountant := bandwidth.NewAccountant(nil, 0, 0)
+
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		buf := make([]byte, 4)
+		_, err := io.ReadFull(conn, buf)
+		require.NoError(t, err)
+
+		_, err = conn.Write([]byte("pong!"))
+		require.NoError(t, err)
+	})
+
+	key := bandwidth.Key{Router: "foo@file", Service: "foo@file", Tenant: "acme"}
+	middleware := New(context.Background(), next, accountant, key)
+
+	server, client := net.Pipe()
+	go func() {
+		_, _ = client.Write([]byte("ping"))
+		_, _ = io.ReadAll(client)
+	}()
+
+	middleware.ServeTCP(fakeConn{Conn: server})
+	_ = server.Close()
+
+	records := accountant.Records()
+	require.Len(t, records, 1)
+
+	assert.Equal(t, "foo@file", records[0].Router)
+	assert.Equal(t, "acme", records[0].Tenant)
+	assert.Equal(t, int64(4), records[0].WireRequestBytes)
+	assert.Equal(t, int64(5), records[0].WireResponseBytes)
+	assert.Equal(t, int64(4), records[0].LogicalRequestBytes)
+	assert.Equal(t, int64(5), records[0].LogicalResponseBytes)
+}
+
+type fakeConn struct {
+	net.Conn
+}
+
+func (c fakeConn) CloseWrite() error {
+	return nil
+}
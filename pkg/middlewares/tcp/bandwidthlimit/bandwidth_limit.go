@@ -0,0 +1,183 @@
+package tcpbandwidthlimit
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+	"golang.org/x/time/rate"
+)
+
+const typeName = "BandwidthLimitTCP"
+
+type bandwidthLimit struct {
+	next tcp.Handler
+
+	upstreamPerConnection   int64
+	downstreamPerConnection int64
+
+	// upstreamRouter and downstreamRouter are shared by all the connections of the router, if set.
+	upstreamRouter   *rate.Limiter
+	downstreamRouter *rate.Limiter
+}
+
+// New creates a middleware limiting the bandwidth of the connections, and of the router, in each direction.
+// The limits are token buckets, allowing bursts of up to one second of data.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPBandwidthLimit, name string) (tcp.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	var upstream, downstream dynamic.TCPBandwidth
+	if config.Upstream != nil {
+		upstream = *config.Upstream
+	}
+	if config.Downstream != nil {
+		downstream = *config.Downstream
+	}
+
+	for _, limit := range []int64{upstream.PerConnection, upstream.PerRouter, downstream.PerConnection, downstream.PerRouter} {
+		if limit < 0 {
+			return nil, fmt.Errorf("invalid bandwidth limit %d: must be positive", limit)
+		}
+	}
+
+	return &bandwidthLimit{
+		next:                    next,
+		upstreamPerConnection:   upstream.PerConnection,
+		downstreamPerConnection: downstream.PerConnection,
+		upstreamRouter:          newLimiter(upstream.PerRouter),
+		downstreamRouter:        newLimiter(downstream.PerRouter),
+	}, nil
+}
+
+// ServeTCP serves the given TCP connection.
+func (b *bandwidthLimit) ServeTCP(conn tcp.WriteCloser) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b.next.ServeTCP(&limitedConn{
+		WriteCloser: conn,
+		ctx:         ctx,
+		cancel:      cancel,
+		upstream:    limiters(newLimiter(b.upstreamPerConnection), b.upstreamRouter),
+		downstream:  limiters(newLimiter(b.downstreamPerConnection), b.downstreamRouter),
+	})
+}
+
+// limitedConn delays the data read from and written to the client, so that it does not exceed the limits.
+type limitedConn struct {
+	tcp.WriteCloser
+
+	// ctx is canceled when the connection is closed, to release the reads and writes waiting for the limits.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	upstream   []*rate.Limiter
+	downstream []*rate.Limiter
+}
+
+// Read reads at most the burst of the upstream limits, and waits for the limits to allow the data read.
+func (c *limitedConn) Read(p []byte) (int, error) {
+	if len(c.upstream) == 0 {
+		return c.WriteCloser.Read(p)
+	}
+
+	if size := chunkSize(c.upstream); len(p) > size {
+		p = p[:size]
+	}
+
+	n, err := c.WriteCloser.Read(p)
+	if n > 0 {
+		// The wait only fails when the connection is closed, in which case the data is not forwarded anyway.
+		_ = waitN(c.ctx, c.upstream, n)
+	}
+
+	return n, err
+}
+
+// Write writes the data in chunks of at most the burst of the downstream limits, as the limits allow them.
+func (c *limitedConn) Write(p []byte) (int, error) {
+	if len(c.downstream) == 0 {
+		return c.WriteCloser.Write(p)
+	}
+
+	size := chunkSize(c.downstream)
+
+	var written int
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > size {
+			chunk = chunk[:size]
+		}
+
+		if err := waitN(c.ctx, c.downstream, len(chunk)); err != nil {
+			return written, err
+		}
+
+		n, err := c.WriteCloser.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+
+		p = p[len(chunk):]
+	}
+
+	return written, nil
+}
+
+// Close closes the connection, and releases the reads and writes waiting for the limits.
+func (c *limitedConn) Close() error {
+	err := c.WriteCloser.Close()
+	c.cancel()
+	return err
+}
+
+// NetConn returns the client connection.
+func (c *limitedConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+// newLimiter returns a token bucket allowing the given bytes per second, with a burst of one second of data,
+// or nil if there is no limit.
+func newLimiter(bytesPerSecond int64) *rate.Limiter {
+	if bytesPerSecond == 0 {
+		return nil
+	}
+
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), int(bytesPerSecond))
+}
+
+func limiters(candidates ...*rate.Limiter) []*rate.Limiter {
+	var result []*rate.Limiter
+	for _, limiter := range candidates {
+		if limiter != nil {
+			result = append(result, limiter)
+		}
+	}
+	return result
+}
+
+// chunkSize returns the lowest burst of the given limiters, which is the most data they can allow at once.
+func chunkSize(limiters []*rate.Limiter) int {
+	size := limiters[0].Burst()
+	for _, limiter := range limiters[1:] {
+		if limiter.Burst() < size {
+			size = limiter.Burst()
+		}
+	}
+	return size
+}
+
+func waitN(ctx context.Context, limiters []*rate.Limiter, n int) error {
+	for _, limiter := range limiters {
+		if err := limiter.WaitN(ctx, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
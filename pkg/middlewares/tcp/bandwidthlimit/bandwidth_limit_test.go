@@ -0,0 +1,146 @@
+package tcpbandwidthlimit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		config    dynamic.TCPBandwidthLimit
+		expectErr bool
+	}{
+		{
+			desc: "no limit",
+		},
+		{
+			desc: "limits",
+			config: dynamic.TCPBandwidthLimit{
+				Upstream:   &dynamic.TCPBandwidth{PerConnection: 1000},
+				Downstream: &dynamic.TCPBandwidth{PerConnection: 1000, PerRouter: 10000},
+			},
+		},
+		{
+			desc:      "negative limit",
+			config:    dynamic.TCPBandwidthLimit{Downstream: &dynamic.TCPBandwidth{PerRouter: -1}},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), nil, test.config, "foo")
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBandwidthLimit_ServeTCP_downstream(t *testing.T) {
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		n, err := conn.Write(make([]byte, 1500))
+		require.NoError(t, err)
+		assert.Equal(t, 1500, n)
+	})
+
+	middleware, err := New(context.Background(), next, dynamic.TCPBandwidthLimit{Downstream: &dynamic.TCPBandwidth{PerConnection: 1000}}, "foo")
+	require.NoError(t, err)
+
+	conn := &fakeConn{reader: bytes.NewReader(nil)}
+
+	start := time.Now()
+	middleware.ServeTCP(conn)
+
+	// The first second of data is allowed at once, and the rest half a second later.
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+	assert.Equal(t, []int{1000, 500}, conn.writes)
+}
+
+func TestBandwidthLimit_ServeTCP_upstreamPerRouter(t *testing.T) {
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		_, err := io.ReadAll(conn)
+		require.NoError(t, err)
+	})
+
+	middleware, err := New(context.Background(), next, dynamic.TCPBandwidthLimit{Upstream: &dynamic.TCPBandwidth{PerRouter: 1000}}, "foo")
+	require.NoError(t, err)
+
+	start := time.Now()
+	middleware.ServeTCP(&fakeConn{reader: bytes.NewReader(make([]byte, 600))})
+	assert.Less(t, time.Since(start), 300*time.Millisecond)
+
+	// The limit of the router is shared with the previous connection.
+	start = time.Now()
+	middleware.ServeTCP(&fakeConn{reader: bytes.NewReader(make([]byte, 600))})
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestBandwidthLimit_ServeTCP_close(t *testing.T) {
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		_, err := conn.Write(make([]byte, 10))
+		require.NoError(t, err)
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			_ = conn.Close()
+		}()
+
+		// Closing the connection releases the write waiting for the limit.
+		_, err = conn.Write(make([]byte, 10))
+		assert.Error(t, err)
+	})
+
+	middleware, err := New(context.Background(), next, dynamic.TCPBandwidthLimit{Downstream: &dynamic.TCPBandwidth{PerConnection: 10}}, "foo")
+	require.NoError(t, err)
+
+	conn := &fakeConn{reader: bytes.NewReader(nil)}
+
+	start := time.Now()
+	middleware.ServeTCP(conn)
+
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.True(t, conn.closed)
+}
+
+type fakeConn struct {
+	net.Conn
+
+	reader io.Reader
+	writes []int
+	closed bool
+}
+
+func (f *fakeConn) Read(p []byte) (int, error) {
+	return f.reader.Read(p)
+}
+
+func (f *fakeConn) Write(p []byte) (int, error) {
+	f.writes = append(f.writes, len(p))
+	return len(p), nil
+}
+
+func (f *fakeConn) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeConn) CloseWrite() error {
+	return nil
+}
@@ -0,0 +1,145 @@
+package tcpbytequota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+const typeName = "ByteQuotaTCP"
+
+var errQuotaExceeded = errors.New("byte quota exceeded")
+
+type byteQuota struct {
+	name string
+	next tcp.Handler
+
+	upstream   int64
+	downstream int64
+}
+
+// New creates a middleware closing the connections once they have transferred their quota of data in either direction.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPByteQuota, name string) (tcp.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	for _, quota := range []int64{config.Upstream, config.Downstream} {
+		if quota < 0 {
+			return nil, fmt.Errorf("invalid byte quota %d: must be positive", quota)
+		}
+	}
+
+	return &byteQuota{
+		name:       name,
+		next:       next,
+		upstream:   config.Upstream,
+		downstream: config.Downstream,
+	}, nil
+}
+
+// ServeTCP serves the given TCP connection.
+func (q *byteQuota) ServeTCP(conn tcp.WriteCloser) {
+	if q.upstream == 0 && q.downstream == 0 {
+		q.next.ServeTCP(conn)
+		return
+	}
+
+	q.next.ServeTCP(&quotaConn{
+		WriteCloser: conn,
+		name:        q.name,
+		upstream:    q.upstream,
+		downstream:  q.downstream,
+	})
+}
+
+// quotaConn closes the connection once the data read from, or written to, the client reaches the quota of its direction.
+type quotaConn struct {
+	tcp.WriteCloser
+
+	name string
+
+	upstream   int64
+	downstream int64
+
+	// read and written are the bytes transferred so far,
+	// which are only updated by the reads and the writes respectively, and therefore need no lock.
+	read    int64
+	written int64
+}
+
+// Read reads at most the remaining upstream quota, and closes the connection once it is reached.
+func (c *quotaConn) Read(p []byte) (int, error) {
+	if c.upstream == 0 {
+		return c.WriteCloser.Read(p)
+	}
+
+	remaining := c.upstream - c.read
+	if remaining <= 0 {
+		return 0, errQuotaExceeded
+	}
+
+	if int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := c.WriteCloser.Read(p)
+	c.read += int64(n)
+
+	if c.read >= c.upstream {
+		c.exceed("upstream")
+	}
+
+	return n, err
+}
+
+// Write writes at most the remaining downstream quota, and closes the connection once it is reached.
+func (c *quotaConn) Write(p []byte) (int, error) {
+	if c.downstream == 0 {
+		return c.WriteCloser.Write(p)
+	}
+
+	remaining := c.downstream - c.written
+	if remaining <= 0 {
+		return 0, errQuotaExceeded
+	}
+
+	chunk := p
+	if int64(len(chunk)) > remaining {
+		chunk = chunk[:remaining]
+	}
+
+	n, err := c.WriteCloser.Write(chunk)
+	c.written += int64(n)
+	if err != nil {
+		return n, err
+	}
+
+	if c.written >= c.downstream {
+		c.exceed("downstream")
+
+		if n < len(p) {
+			return n, errQuotaExceeded
+		}
+	}
+
+	return n, nil
+}
+
+// NetConn returns the client connection.
+func (c *quotaConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+func (c *quotaConn) exceed(direction string) {
+	ctx := middlewares.GetLoggerCtx(context.Background(), c.name, typeName)
+	log.FromContext(ctx).Debugf("Connection from %s reached its %s byte quota, closing the connection", c.RemoteAddr(), direction)
+
+	tcp.SetCloseReason(c.WriteCloser, tcp.CloseReasonPolicy)
+	_ = c.WriteCloser.Close()
+}
@@ -0,0 +1,141 @@
+package tcpbytequota
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		config    dynamic.TCPByteQuota
+		expectErr bool
+	}{
+		{
+			desc: "no quota",
+		},
+		{
+			desc:   "quotas",
+			config: dynamic.TCPByteQuota{Upstream: 1000, Downstream: 10000},
+		},
+		{
+			desc:      "negative quota",
+			config:    dynamic.TCPByteQuota{Downstream: -1},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), nil, test.config, "foo")
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestByteQuota_ServeTCP_upstream(t *testing.T) {
+	var read []byte
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		var err error
+		read, err = io.ReadAll(conn)
+		assert.ErrorIs(t, err, errQuotaExceeded)
+	})
+
+	middleware, err := New(context.Background(), next, dynamic.TCPByteQuota{Upstream: 1000}, "foo")
+	require.NoError(t, err)
+
+	conn := &fakeConn{reader: bytes.NewReader(make([]byte, 1500))}
+	middleware.ServeTCP(conn)
+
+	assert.Len(t, read, 1000)
+	assert.True(t, conn.closed)
+}
+
+func TestByteQuota_ServeTCP_downstream(t *testing.T) {
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		n, err := conn.Write(make([]byte, 600))
+		require.NoError(t, err)
+		assert.Equal(t, 600, n)
+
+		n, err = conn.Write(make([]byte, 600))
+		assert.ErrorIs(t, err, errQuotaExceeded)
+		assert.Equal(t, 400, n)
+
+		_, err = conn.Write(make([]byte, 600))
+		assert.ErrorIs(t, err, errQuotaExceeded)
+	})
+
+	middleware, err := New(context.Background(), next, dynamic.TCPByteQuota{Downstream: 1000}, "foo")
+	require.NoError(t, err)
+
+	conn := &fakeConn{reader: bytes.NewReader(nil)}
+	middleware.ServeTCP(conn)
+
+	assert.Equal(t, []int{600, 400}, conn.writes)
+	assert.True(t, conn.closed)
+}
+
+func TestByteQuota_ServeTCP_underQuota(t *testing.T) {
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		_, err := io.ReadAll(conn)
+		require.NoError(t, err)
+
+		_, err = conn.Write(make([]byte, 500))
+		require.NoError(t, err)
+	})
+
+	middleware, err := New(context.Background(), next, dynamic.TCPByteQuota{Upstream: 1000, Downstream: 1000}, "foo")
+	require.NoError(t, err)
+
+	conn := &fakeConn{reader: bytes.NewReader(make([]byte, 500))}
+	middleware.ServeTCP(conn)
+
+	assert.Equal(t, []int{500}, conn.writes)
+	assert.False(t, conn.closed)
+}
+
+type fakeConn struct {
+	net.Conn
+
+	reader io.Reader
+	writes []int
+	closed bool
+}
+
+func (f *fakeConn) Read(p []byte) (int, error) {
+	return f.reader.Read(p)
+}
+
+func (f *fakeConn) Write(p []byte) (int, error) {
+	f.writes = append(f.writes, len(p))
+	return len(p), nil
+}
+
+func (f *fakeConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 9000}
+}
+
+func (f *fakeConn) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeConn) CloseWrite() error {
+	return nil
+}
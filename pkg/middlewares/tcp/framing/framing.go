@@ -0,0 +1,204 @@
+package tcpframing
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+const (
+	typeName = "FramingTCP"
+
+	defaultLengthFieldSize = 4
+	defaultMaxFrameSize    = 1 << 20
+
+	// readBufferSize is the maximum size of the payload of the frames built from a single read of the client data.
+	readBufferSize = 32 * 1024
+)
+
+type framing struct {
+	name            string
+	next            tcp.Handler
+	lengthFieldSize int
+	maxFrameSize    int64
+}
+
+// New creates a middleware framing the byte stream of the client for the service, and unframing the responses of the service.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPFraming, name string) (tcp.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	lengthFieldSize := config.LengthFieldSize
+	if lengthFieldSize == 0 {
+		lengthFieldSize = defaultLengthFieldSize
+	}
+
+	switch lengthFieldSize {
+	case 1, 2, 4, 8:
+	default:
+		return nil, fmt.Errorf("invalid length field size %d: must be 1, 2, 4, or 8", lengthFieldSize)
+	}
+
+	maxFrameSize := config.MaxFrameSize
+	if maxFrameSize == 0 {
+		maxFrameSize = defaultMaxFrameSize
+		if limit := maxLength(lengthFieldSize); limit < defaultMaxFrameSize {
+			maxFrameSize = int64(limit)
+		}
+	}
+
+	if maxFrameSize < 0 {
+		return nil, fmt.Errorf("invalid max frame size %d: must be positive", maxFrameSize)
+	}
+
+	if limit := maxLength(lengthFieldSize); uint64(maxFrameSize) > limit {
+		return nil, fmt.Errorf("invalid max frame size %d: must be at most %d with a length field size of %d", maxFrameSize, limit, lengthFieldSize)
+	}
+
+	return &framing{
+		name:            name,
+		next:            next,
+		lengthFieldSize: lengthFieldSize,
+		maxFrameSize:    maxFrameSize,
+	}, nil
+}
+
+// ServeTCP serves the given TCP connection.
+func (f *framing) ServeTCP(conn tcp.WriteCloser) {
+	bufferSize := int64(readBufferSize)
+	if f.maxFrameSize < bufferSize {
+		bufferSize = f.maxFrameSize
+	}
+
+	f.next.ServeTCP(&framedConn{
+		WriteCloser:     conn,
+		name:            f.name,
+		lengthFieldSize: f.lengthFieldSize,
+		maxFrameSize:    f.maxFrameSize,
+		readBuffer:      make([]byte, bufferSize),
+	})
+}
+
+// framedConn frames the data read from the client, and unframes the data written to the client.
+type framedConn struct {
+	tcp.WriteCloser
+
+	name            string
+	lengthFieldSize int
+	maxFrameSize    int64
+
+	// readBuffer receives the client data, and pendingRead holds the part of the last frame not read yet.
+	readBuffer  []byte
+	pendingRead []byte
+
+	// pendingWrite holds the data written by the service which does not make a complete frame yet.
+	writeMu      sync.Mutex
+	pendingWrite []byte
+}
+
+// Read reads the next frames, made of the data of the client.
+func (c *framedConn) Read(p []byte) (int, error) {
+	if len(c.pendingRead) == 0 {
+		n, err := c.WriteCloser.Read(c.readBuffer)
+		if n == 0 {
+			return 0, err
+		}
+
+		frame := make([]byte, c.lengthFieldSize+n)
+		putLength(frame[:c.lengthFieldSize], uint64(n))
+		copy(frame[c.lengthFieldSize:], c.readBuffer[:n])
+		c.pendingRead = frame
+	}
+
+	n := copy(p, c.pendingRead)
+	c.pendingRead = c.pendingRead[n:]
+
+	return n, nil
+}
+
+// Write writes the payloads of the complete frames of the given data to the client,
+// and keeps the rest for the next writes.
+func (c *framedConn) Write(p []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.pendingWrite = append(c.pendingWrite, p...)
+
+	var consumed int
+	for len(c.pendingWrite)-consumed >= c.lengthFieldSize {
+		frame := c.pendingWrite[consumed:]
+
+		length := getLength(frame[:c.lengthFieldSize])
+		if length > uint64(c.maxFrameSize) {
+			log.FromContext(middlewares.GetLoggerCtx(context.Background(), c.name, typeName)).
+				Errorf("Closing connection: frame of %d bytes exceeds the max frame size of %d bytes", length, c.maxFrameSize)
+			tcp.SetCloseReason(c.WriteCloser, tcp.CloseReasonPolicy)
+			_ = c.WriteCloser.Close()
+			return 0, errors.New("frame exceeds the max frame size")
+		}
+
+		frameSize := c.lengthFieldSize + int(length)
+		if len(frame) < frameSize {
+			break
+		}
+
+		if _, err := c.WriteCloser.Write(frame[c.lengthFieldSize:frameSize]); err != nil {
+			return 0, err
+		}
+
+		consumed += frameSize
+	}
+
+	// The incomplete frame is moved to the start of the buffer, which is reused by the next writes.
+	c.pendingWrite = append(c.pendingWrite[:0], c.pendingWrite[consumed:]...)
+
+	return len(p), nil
+}
+
+// NetConn returns the client connection.
+func (c *framedConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+// maxLength returns the maximum length which fits in a length field of the given size.
+func maxLength(lengthFieldSize int) uint64 {
+	if lengthFieldSize == 8 {
+		return 1<<63 - 1
+	}
+
+	return 1<<(8*lengthFieldSize) - 1
+}
+
+func putLength(b []byte, length uint64) {
+	switch len(b) {
+	case 1:
+		b[0] = byte(length)
+	case 2:
+		binary.BigEndian.PutUint16(b, uint16(length))
+	case 4:
+		binary.BigEndian.PutUint32(b, uint32(length))
+	case 8:
+		binary.BigEndian.PutUint64(b, length)
+	}
+}
+
+func getLength(b []byte) uint64 {
+	switch len(b) {
+	case 1:
+		return uint64(b[0])
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b))
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b))
+	default:
+		return binary.BigEndian.Uint64(b)
+	}
+}
@@ -0,0 +1,127 @@
+package tcpframing
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		config    dynamic.TCPFraming
+		expectErr bool
+	}{
+		{
+			desc: "default values",
+		},
+		{
+			desc:   "default max frame size capped by the length field size",
+			config: dynamic.TCPFraming{LengthFieldSize: 1},
+		},
+		{
+			desc:      "invalid length field size",
+			config:    dynamic.TCPFraming{LengthFieldSize: 3},
+			expectErr: true,
+		},
+		{
+			desc:      "max frame size too big for the length field size",
+			config:    dynamic.TCPFraming{LengthFieldSize: 2, MaxFrameSize: 1 << 16},
+			expectErr: true,
+		},
+		{
+			desc:      "negative max frame size",
+			config:    dynamic.TCPFraming{MaxFrameSize: -1},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), nil, test.config, "foo")
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestFraming_ServeTCP(t *testing.T) {
+	var received []byte
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		var err error
+		received, err = io.ReadAll(conn)
+		require.NoError(t, err)
+
+		// The frames of the service are written in pieces.
+		_, err = conn.Write([]byte{0, 5, 'h', 'e'})
+		require.NoError(t, err)
+		_, err = conn.Write([]byte{'l', 'l', 'o', 0, 0, 0, 1, '!'})
+		require.NoError(t, err)
+	})
+
+	middleware, err := New(context.Background(), next, dynamic.TCPFraming{LengthFieldSize: 2, MaxFrameSize: 5}, "foo")
+	require.NoError(t, err)
+
+	conn := &fakeConn{reader: bytes.NewReader([]byte("hello world"))}
+	middleware.ServeTCP(conn)
+
+	// The client data is split into frames of at most the max frame size.
+	assert.Equal(t, []byte{0, 5, 'h', 'e', 'l', 'l', 'o', 0, 5, ' ', 'w', 'o', 'r', 'l', 0, 1, 'd'}, received)
+	assert.Equal(t, "hello!", conn.written.String())
+	assert.False(t, conn.closed)
+}
+
+func TestFraming_ServeTCP_frameTooBig(t *testing.T) {
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		_, err := conn.Write([]byte{0, 0, 0, 42})
+		assert.Error(t, err)
+	})
+
+	middleware, err := New(context.Background(), next, dynamic.TCPFraming{MaxFrameSize: 10}, "foo")
+	require.NoError(t, err)
+
+	conn := &fakeConn{reader: bytes.NewReader(nil)}
+	middleware.ServeTCP(conn)
+
+	assert.Empty(t, conn.written.Bytes())
+	assert.True(t, conn.closed)
+}
+
+type fakeConn struct {
+	net.Conn
+
+	reader  io.Reader
+	written bytes.Buffer
+	closed  bool
+}
+
+func (f *fakeConn) Read(p []byte) (int, error) {
+	return f.reader.Read(p)
+}
+
+func (f *fakeConn) Write(p []byte) (int, error) {
+	return f.written.Write(p)
+}
+
+func (f *fakeConn) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeConn) CloseWrite() error {
+	return nil
+}
@@ -0,0 +1,256 @@
+package tcpgeoip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+const (
+	typeName       = "GeoIPTCP"
+	unknownCountry = "unknown"
+)
+
+// database looks the IPs up in a MaxMind database.
+type database interface {
+	Lookup(ip net.IP, result interface{}) error
+}
+
+type countryRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+}
+
+type asnRecord struct {
+	AutonomousSystemNumber uint `maxminddb:"autonomous_system_number"`
+}
+
+// geoIP is a middleware that accepts or refuses connections based on the country and the autonomous system of the client IP.
+type geoIP struct {
+	name string
+	next tcp.Handler
+
+	countries database
+	asns      database
+
+	allowedCountries map[string]struct{}
+	deniedCountries  map[string]struct{}
+	allowedASNs      map[uint]struct{}
+	deniedASNs       map[uint]struct{}
+
+	conns gokitmetrics.Counter
+}
+
+// New builds a new TCP GeoIP middleware.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPGeoIP, name string, registry metrics.Registry) (tcp.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	if registry == nil {
+		registry = metrics.NewVoidRegistry()
+	}
+
+	if config.CountryDatabase == "" && config.ASNDatabase == "" {
+		return nil, errors.New("no database defined")
+	}
+
+	if config.CountryDatabase == "" && (len(config.AllowedCountries) > 0 || len(config.DeniedCountries) > 0) {
+		return nil, errors.New("countries defined without a country database")
+	}
+
+	if config.ASNDatabase == "" && (len(config.AllowedASNs) > 0 || len(config.DeniedASNs) > 0) {
+		return nil, errors.New("autonomous systems defined without an ASN database")
+	}
+
+	g := &geoIP{
+		name:             name,
+		next:             next,
+		allowedCountries: countrySet(config.AllowedCountries),
+		deniedCountries:  countrySet(config.DeniedCountries),
+		allowedASNs:      asnSet(config.AllowedASNs),
+		deniedASNs:       asnSet(config.DeniedASNs),
+		conns:            registry.GeoIPConnsCounter(),
+	}
+
+	if config.CountryDatabase != "" {
+		reader, err := openDatabase(config.CountryDatabase)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open the country database: %w", err)
+		}
+		g.countries = reader
+	}
+
+	if config.ASNDatabase != "" {
+		reader, err := openDatabase(config.ASNDatabase)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open the ASN database: %w", err)
+		}
+		g.asns = reader
+	}
+
+	return g, nil
+}
+
+// ServeTCP serves the given TCP connection.
+func (g *geoIP) ServeTCP(conn tcp.WriteCloser) {
+	ctx := middlewares.GetLoggerCtx(context.Background(), g.name, typeName)
+	logger := log.FromContext(ctx)
+
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		logger.Errorf("Cannot parse IP from remote addr: %v", err)
+		conn.Close()
+		return
+	}
+
+	country, asn := g.lookup(ctx, net.ParseIP(host))
+
+	metricCountry := country
+	if metricCountry == "" {
+		metricCountry = unknownCountry
+	}
+
+	if !g.allowed(country, asn) {
+		logger.Debugf("Connection from %s rejected (country: %q, ASN: %d)", host, country, asn)
+		g.conns.With("middleware", g.name, "country", metricCountry, "result", "rejected").Add(1)
+
+		tcp.SetCloseReason(conn, tcp.CloseReasonPolicy)
+		conn.Close()
+		return
+	}
+
+	logger.Debugf("Connection from %s accepted (country: %q, ASN: %d)", host, country, asn)
+	g.conns.With("middleware", g.name, "country", metricCountry, "result", "accepted").Add(1)
+
+	g.next.ServeTCP(conn)
+}
+
+// lookup returns the country code and the autonomous system number of the given IP,
+// which are empty if the IP is not found in the databases.
+func (g *geoIP) lookup(ctx context.Context, ip net.IP) (string, uint) {
+	if ip == nil {
+		return "", 0
+	}
+
+	var country string
+	if g.countries != nil {
+		var record countryRecord
+		if err := g.countries.Lookup(ip, &record); err != nil {
+			log.FromContext(ctx).Errorf("Cannot look %s up in the country database: %v", ip, err)
+		}
+		country = strings.ToUpper(record.Country.ISOCode)
+	}
+
+	var asn uint
+	if g.asns != nil {
+		var record asnRecord
+		if err := g.asns.Lookup(ip, &record); err != nil {
+			log.FromContext(ctx).Errorf("Cannot look %s up in the ASN database: %v", ip, err)
+		}
+		asn = record.AutonomousSystemNumber
+	}
+
+	return country, asn
+}
+
+// allowed reports whether a connection from the given country and autonomous system is allowed.
+// The denied countries and autonomous systems are refused first,
+// and, when allowed ones are defined, the connections are only accepted if they match any of them.
+func (g *geoIP) allowed(country string, asn uint) bool {
+	if _, ok := g.deniedCountries[country]; ok && country != "" {
+		return false
+	}
+
+	if _, ok := g.deniedASNs[asn]; ok && asn != 0 {
+		return false
+	}
+
+	if len(g.allowedCountries) == 0 && len(g.allowedASNs) == 0 {
+		return true
+	}
+
+	if _, ok := g.allowedCountries[country]; ok && country != "" {
+		return true
+	}
+
+	if _, ok := g.allowedASNs[asn]; ok && asn != 0 {
+		return true
+	}
+
+	return false
+}
+
+func countrySet(countries []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(countries))
+	for _, country := range countries {
+		set[strings.ToUpper(strings.TrimSpace(country))] = struct{}{}
+	}
+	return set
+}
+
+func asnSet(asns []uint) map[uint]struct{} {
+	set := make(map[uint]struct{}, len(asns))
+	for _, asn := range asns {
+		set[asn] = struct{}{}
+	}
+	return set
+}
+
+// cachedDatabase is a database loaded in memory, along with the state of its file when it was loaded.
+type cachedDatabase struct {
+	reader  *maxminddb.Reader
+	modTime time.Time
+	size    int64
+}
+
+var (
+	databasesMu sync.Mutex
+	// databases holds the loaded databases by path,
+	// so that the middlewares created again at each configuration reload share them,
+	// until their files are updated.
+	databases = make(map[string]cachedDatabase)
+)
+
+func openDatabase(path string) (*maxminddb.Reader, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	databasesMu.Lock()
+	defer databasesMu.Unlock()
+
+	if cached, ok := databases[path]; ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.reader, nil
+	}
+
+	// The database is loaded in memory rather than mapped,
+	// so that the readers of the previous versions of the file do not need to be closed.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := maxminddb.FromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	databases[path] = cachedDatabase{reader: reader, modTime: info.ModTime(), size: info.Size()}
+
+	return reader, nil
+}
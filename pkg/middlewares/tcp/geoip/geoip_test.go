@@ -0,0 +1,235 @@
+package tcpgeoip
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestNew(t *testing.T) {
+	invalidDatabase := filepath.Join(t.TempDir(), "invalid.mmdb")
+	require.NoError(t, os.WriteFile(invalidDatabase, []byte("not a database"), 0o600))
+
+	testCases := []struct {
+		desc   string
+		config dynamic.TCPGeoIP
+	}{
+		{
+			desc: "no database",
+		},
+		{
+			desc: "countries without a country database",
+			config: dynamic.TCPGeoIP{
+				ASNDatabase:      invalidDatabase,
+				AllowedCountries: []string{"FR"},
+			},
+		},
+		{
+			desc: "autonomous systems without an ASN database",
+			config: dynamic.TCPGeoIP{
+				CountryDatabase: invalidDatabase,
+				DeniedASNs:      []uint{64496},
+			},
+		},
+		{
+			desc: "missing database",
+			config: dynamic.TCPGeoIP{
+				CountryDatabase: filepath.Join(t.TempDir(), "missing.mmdb"),
+			},
+		},
+		{
+			desc: "invalid database",
+			config: dynamic.TCPGeoIP{
+				CountryDatabase: invalidDatabase,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), nil, test.config, "foo", nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestGeoIP_ServeTCP(t *testing.T) {
+	records := map[string]fakeRecord{
+		"10.0.0.1": {country: "FR", asn: 64496},
+		"10.0.0.2": {country: "DE", asn: 64497},
+		"10.0.0.3": {country: "US", asn: 64498},
+		"10.0.0.4": {country: "US", asn: 64499},
+	}
+
+	testCases := []struct {
+		desc     string
+		config   dynamic.TCPGeoIP
+		accepted map[string]bool
+	}{
+		{
+			desc: "no lists",
+			accepted: map[string]bool{
+				"10.0.0.1": true,
+				"10.0.0.5": true,
+			},
+		},
+		{
+			desc: "allowed countries",
+			config: dynamic.TCPGeoIP{
+				AllowedCountries: []string{"fr", "DE"},
+			},
+			accepted: map[string]bool{
+				"10.0.0.1": true,
+				"10.0.0.2": true,
+				"10.0.0.3": false,
+				"10.0.0.5": false,
+			},
+		},
+		{
+			desc: "denied countries",
+			config: dynamic.TCPGeoIP{
+				DeniedCountries: []string{"US"},
+			},
+			accepted: map[string]bool{
+				"10.0.0.1": true,
+				"10.0.0.3": false,
+				"10.0.0.5": true,
+			},
+		},
+		{
+			desc: "allowed countries or autonomous systems",
+			config: dynamic.TCPGeoIP{
+				AllowedCountries: []string{"FR"},
+				AllowedASNs:      []uint{64499},
+			},
+			accepted: map[string]bool{
+				"10.0.0.1": true,
+				"10.0.0.3": false,
+				"10.0.0.4": true,
+			},
+		},
+		{
+			desc: "denied autonomous systems take precedence",
+			config: dynamic.TCPGeoIP{
+				AllowedCountries: []string{"US"},
+				DeniedASNs:       []uint{64498},
+			},
+			accepted: map[string]bool{
+				"10.0.0.3": false,
+				"10.0.0.4": true,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			for ip, expected := range test.accepted {
+				var served bool
+				next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+					served = true
+				})
+
+				conns := &collectingCounter{}
+
+				g := &geoIP{
+					name:             "foo",
+					next:             next,
+					countries:        fakeDatabase(records),
+					asns:             fakeDatabase(records),
+					allowedCountries: countrySet(test.config.AllowedCountries),
+					deniedCountries:  countrySet(test.config.DeniedCountries),
+					allowedASNs:      asnSet(test.config.AllowedASNs),
+					deniedASNs:       asnSet(test.config.DeniedASNs),
+					conns:            conns,
+				}
+
+				conn := &fakeConn{ip: ip}
+				g.ServeTCP(conn)
+
+				assert.Equal(t, expected, served, ip)
+				assert.Equal(t, !expected, conn.closed, ip)
+
+				result := "accepted"
+				if !expected {
+					result = "rejected"
+				}
+
+				country := records[ip].country
+				if country == "" {
+					country = unknownCountry
+				}
+
+				assert.Equal(t, []string{"middleware", "foo", "country", country, "result", result}, conns.labels, ip)
+			}
+		})
+	}
+}
+
+type fakeRecord struct {
+	country string
+	asn     uint
+}
+
+// fakeDatabase is a database of records by IP.
+type fakeDatabase map[string]fakeRecord
+
+func (d fakeDatabase) Lookup(ip net.IP, result interface{}) error {
+	record, ok := d[ip.String()]
+	if !ok {
+		return nil
+	}
+
+	switch r := result.(type) {
+	case *countryRecord:
+		r.Country.ISOCode = record.country
+	case *asnRecord:
+		r.AutonomousSystemNumber = record.asn
+	}
+
+	return nil
+}
+
+type collectingCounter struct {
+	labels []string
+}
+
+func (c *collectingCounter) With(labelValues ...string) gokitmetrics.Counter {
+	c.labels = labelValues
+	return c
+}
+
+func (c *collectingCounter) Add(float64) {}
+
+type fakeConn struct {
+	net.Conn
+
+	ip     string
+	closed bool
+}
+
+func (c *fakeConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.ParseIP(c.ip), Port: 9000}
+}
+
+func (c *fakeConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func (c *fakeConn) CloseWrite() error {
+	return nil
+}
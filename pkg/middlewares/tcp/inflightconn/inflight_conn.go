@@ -51,6 +51,7 @@ func (i *inFlightConn) ServeTCP(conn tcp.WriteCloser) {
 
 	if err = i.increment(ip); err != nil {
 		logger.Errorf("Connection rejected: %v", err)
+		tcp.SetCloseReason(conn, tcp.CloseReasonPolicy)
 		conn.Close()
 		return
 	}
@@ -55,6 +55,7 @@ func (wl *ipWhiteLister) ServeTCP(conn tcp.WriteCloser) {
 	err := wl.whiteLister.IsAuthorized(addr)
 	if err != nil {
 		logger.Errorf("Connection from %s rejected: %v", addr, err)
+		tcp.SetCloseReason(conn, tcp.CloseReasonPolicy)
 		conn.Close()
 		return
 	}
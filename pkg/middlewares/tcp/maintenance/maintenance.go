@@ -0,0 +1,53 @@
+package tcpmaintenance
+
+import (
+	"context"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/maintenance"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+const (
+	typeName   = "MaintenanceTCP"
+	nameRouter = "maintenance-tcp-router"
+)
+
+type maintenanceMiddleware struct {
+	next       tcp.Handler
+	flags      *maintenance.Flags
+	routerName string
+}
+
+// New creates a new middleware refusing the connections on a TCP router in maintenance.
+func New(ctx context.Context, next tcp.Handler, flags *maintenance.Flags, routerName string) tcp.Handler {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, nameRouter, typeName)).Debug("Creating middleware")
+
+	return &maintenanceMiddleware{
+		next:       next,
+		flags:      flags,
+		routerName: routerName,
+	}
+}
+
+// WrapRouterHandler Wraps maintenance to tcp.Constructor.
+func WrapRouterHandler(ctx context.Context, flags *maintenance.Flags, routerName string) tcp.Constructor {
+	return func(next tcp.Handler) (tcp.Handler, error) {
+		return New(ctx, next, flags, routerName), nil
+	}
+}
+
+// ServeTCP serves the given TCP connection.
+func (m *maintenanceMiddleware) ServeTCP(conn tcp.WriteCloser) {
+	if !m.flags.Enabled(maintenance.ProtocolTCP, m.routerName) {
+		m.next.ServeTCP(conn)
+		return
+	}
+
+	log.WithoutContext().Debugf("Refusing connection from %s on router %s in maintenance", conn.RemoteAddr(), m.routerName)
+
+	if err := conn.Close(); err != nil {
+		log.WithoutContext().Debugf("Error while closing the connection on router %s in maintenance: %v", m.routerName, err)
+	}
+}
@@ -0,0 +1,50 @@
+package tcpmaintenance
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/maintenance"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestMaintenanceMiddleware_ServeTCP(t *testing.T) {
+	flags := maintenance.NewFlags(maintenance.Response{})
+
+	var served int
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		served++
+		_ = conn.Close()
+	})
+
+	middleware := New(context.Background(), next, flags, "foo@file")
+
+	server, client := net.Pipe()
+	middleware.ServeTCP(fakeConn{Conn: server})
+	_ = client.Close()
+
+	assert.Equal(t, 1, served)
+
+	flags.Set(maintenance.ProtocolTCP, "foo@file", true)
+
+	server, client = net.Pipe()
+	middleware.ServeTCP(fakeConn{Conn: server})
+
+	assert.Equal(t, 1, served)
+
+	// The connection is closed.
+	_, err := client.Read(make([]byte, 1))
+	require.ErrorIs(t, err, io.EOF)
+}
+
+type fakeConn struct {
+	net.Conn
+}
+
+func (c fakeConn) CloseWrite() error {
+	return nil
+}
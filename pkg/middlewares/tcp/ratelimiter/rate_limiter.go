@@ -0,0 +1,140 @@
+package tcpratelimiter
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/mailgun/ttlmap"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/ip"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+	"golang.org/x/time/rate"
+)
+
+const (
+	typeName   = "RateLimiterTCP"
+	maxSources = 65536
+)
+
+// rateLimiter limits the rate of the new connections with a token bucket for each client IP.
+type rateLimiter struct {
+	name        string
+	next        tcp.Handler
+	rate        rate.Limit // conns/s
+	burst       int
+	tarpitDelay time.Duration
+	ipv6Subnet  int
+
+	// ttl is the number of seconds after which the bucket of an inactive client IP is dropped.
+	ttl     int
+	buckets *ttlmap.TtlMap // actual buckets, keyed by client IP, or IPv6 subnet.
+}
+
+// New creates a middleware limiting the rate of the new connections of each client IP.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPRateLimit, name string) (tcp.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	period := time.Duration(config.Period)
+	if period < 0 {
+		return nil, fmt.Errorf("negative value not valid for period: %v", period)
+	}
+	if period == 0 {
+		period = time.Second
+	}
+
+	if config.TarpitDelay < 0 {
+		return nil, fmt.Errorf("negative value not valid for tarpitDelay: %v", time.Duration(config.TarpitDelay))
+	}
+
+	if err := ip.ValidateIPv6Subnet(config.IPv6Subnet); err != nil {
+		return nil, err
+	}
+
+	burst := config.Burst
+	if burst < 1 {
+		burst = 1
+	}
+
+	// Initialized at rate.Inf to enforce no rate limiting when average == 0.
+	rtl := rate.Inf
+	if config.Average > 0 {
+		rtl = rate.Limit(float64(config.Average*int64(time.Second)) / float64(period))
+	}
+
+	// Like the HTTP rate limiter, the ttl is inversely proportional to the rate for the low rates,
+	// so that the buckets are not dropped before they are refilled.
+	ttl := 1
+	if rtl >= 1 {
+		ttl++
+	} else if rtl > 0 {
+		ttl += int(1 / rtl)
+	}
+
+	buckets, err := ttlmap.NewConcurrent(maxSources)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rateLimiter{
+		name:        name,
+		next:        next,
+		rate:        rtl,
+		burst:       int(burst),
+		tarpitDelay: time.Duration(config.TarpitDelay),
+		ipv6Subnet:  config.IPv6Subnet,
+		ttl:         ttl,
+		buckets:     buckets,
+	}, nil
+}
+
+// ServeTCP serves the given TCP connection.
+func (rl *rateLimiter) ServeTCP(conn tcp.WriteCloser) {
+	ctx := middlewares.GetLoggerCtx(context.Background(), rl.name, typeName)
+	logger := log.FromContext(ctx)
+
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		logger.Errorf("Cannot parse IP from remote addr: %v", err)
+		conn.Close()
+		return
+	}
+
+	source := ip.Group(host, rl.ipv6Subnet)
+
+	var bucket *rate.Limiter
+	if rlSource, exists := rl.buckets.Get(source); exists {
+		bucket = rlSource.(*rate.Limiter)
+	} else {
+		bucket = rate.NewLimiter(rl.rate, rl.burst)
+	}
+
+	// We Set even in the case where the source already exists,
+	// because we want to update the expiryTime everytime we get the source,
+	// as the expiryTime is supposed to reflect the activity (or lack thereof) on that source.
+	if err := rl.buckets.Set(source, bucket, rl.ttl); err != nil {
+		logger.Errorf("Could not insert/update bucket: %v", err)
+		conn.Close()
+		return
+	}
+
+	if bucket.Allow() {
+		rl.next.ServeTCP(conn)
+		return
+	}
+
+	tcp.SetCloseReason(conn, tcp.CloseReasonPolicy)
+
+	if rl.tarpitDelay > 0 {
+		logger.Debugf("Connection rate exceeded for %s, holding the connection for %s", source, rl.tarpitDelay)
+		time.Sleep(rl.tarpitDelay)
+	} else {
+		logger.Debugf("Connection rate exceeded for %s, closing the connection", source)
+	}
+
+	conn.Close()
+}
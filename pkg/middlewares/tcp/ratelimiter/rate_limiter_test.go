@@ -0,0 +1,172 @@
+package tcpratelimiter
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		config    dynamic.TCPRateLimit
+		expectErr bool
+	}{
+		{
+			desc: "no rate limiting",
+		},
+		{
+			desc:   "rate limiting",
+			config: dynamic.TCPRateLimit{Average: 10, Burst: 5, Period: ptypes.Duration(time.Minute)},
+		},
+		{
+			desc:      "negative period",
+			config:    dynamic.TCPRateLimit{Average: 10, Period: ptypes.Duration(-time.Second)},
+			expectErr: true,
+		},
+		{
+			desc:      "negative tarpit delay",
+			config:    dynamic.TCPRateLimit{Average: 10, TarpitDelay: ptypes.Duration(-time.Second)},
+			expectErr: true,
+		},
+		{
+			desc:      "invalid IPv6 subnet",
+			config:    dynamic.TCPRateLimit{Average: 10, IPv6Subnet: 129},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), nil, test.config, "foo")
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRateLimiter_ServeTCP(t *testing.T) {
+	var served int
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		served++
+	})
+
+	middleware, err := New(context.Background(), next, dynamic.TCPRateLimit{Average: 1, Period: ptypes.Duration(time.Hour), Burst: 2}, "foo")
+	require.NoError(t, err)
+
+	// The burst is allowed.
+	for i := 0; i < 2; i++ {
+		conn := &fakeConn{addr: "127.0.0.1:9000"}
+		middleware.ServeTCP(conn)
+		assert.False(t, conn.closed)
+	}
+	assert.Equal(t, 2, served)
+
+	// The connections exceeding the rate are closed.
+	conn := &fakeConn{addr: "127.0.0.1:9001"}
+	middleware.ServeTCP(conn)
+	assert.True(t, conn.closed)
+	assert.Equal(t, 2, served)
+
+	// The other client IPs have their own rate.
+	conn = &fakeConn{addr: "127.0.0.2:9000"}
+	middleware.ServeTCP(conn)
+	assert.False(t, conn.closed)
+	assert.Equal(t, 3, served)
+}
+
+func TestRateLimiter_ServeTCP_ipv6Subnet(t *testing.T) {
+	var served int
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		served++
+	})
+
+	config := dynamic.TCPRateLimit{Average: 1, Period: ptypes.Duration(time.Hour), Burst: 2, IPv6Subnet: 64}
+	middleware, err := New(context.Background(), next, config, "foo")
+	require.NoError(t, err)
+
+	// The addresses of the same /64 share the rate.
+	for _, addr := range []string{"[2001:db8::1]:9000", "[2001:db8::ffff:2]:9000", "[2001:db8::3]:9000"} {
+		middleware.ServeTCP(&fakeConn{addr: addr})
+	}
+	assert.Equal(t, 2, served)
+
+	// The other subnets have their own rate.
+	conn := &fakeConn{addr: "[2001:db8:0:1::1]:9000"}
+	middleware.ServeTCP(conn)
+	assert.False(t, conn.closed)
+	assert.Equal(t, 3, served)
+
+	// The IPv4-mapped addresses share the rate of their IPv4 address.
+	middleware.ServeTCP(&fakeConn{addr: "127.0.0.1:9000"})
+	middleware.ServeTCP(&fakeConn{addr: "[::ffff:127.0.0.1]:9000"})
+	conn = &fakeConn{addr: "127.0.0.1:9000"}
+	middleware.ServeTCP(conn)
+	assert.True(t, conn.closed)
+	assert.Equal(t, 5, served)
+}
+
+func TestRateLimiter_ServeTCP_tarpit(t *testing.T) {
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {})
+
+	config := dynamic.TCPRateLimit{Average: 1, Period: ptypes.Duration(time.Hour), Burst: 1, TarpitDelay: ptypes.Duration(100 * time.Millisecond)}
+	middleware, err := New(context.Background(), next, config, "foo")
+	require.NoError(t, err)
+
+	middleware.ServeTCP(&fakeConn{addr: "127.0.0.1:9000"})
+
+	// The connection exceeding the rate is held open before being closed.
+	conn := &fakeConn{addr: "127.0.0.1:9000"}
+
+	start := time.Now()
+	middleware.ServeTCP(conn)
+
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	assert.True(t, conn.closed)
+}
+
+type fakeConn struct {
+	net.Conn
+
+	addr   string
+	closed bool
+}
+
+func (c *fakeConn) RemoteAddr() net.Addr {
+	return fakeAddr{addr: c.addr}
+}
+
+func (c *fakeConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func (c *fakeConn) CloseWrite() error {
+	return nil
+}
+
+type fakeAddr struct {
+	addr string
+}
+
+func (a fakeAddr) Network() string {
+	return "tcp"
+}
+
+func (a fakeAddr) String() string {
+	return a.addr
+}
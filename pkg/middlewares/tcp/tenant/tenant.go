@@ -0,0 +1,78 @@
+package tcptenant
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+	"github.com/traefik/traefik/v2/pkg/tenant"
+)
+
+const (
+	typeName   = "TenantTCP"
+	nameRouter = "tenant-tcp-router"
+)
+
+type tenantMiddleware struct {
+	next    tcp.Handler
+	rollups *tenant.Rollups
+	tenant  string
+}
+
+// New creates a new middleware accounting the connections handled by a TCP router to its tenant.
+func New(ctx context.Context, next tcp.Handler, rollups *tenant.Rollups, tenantName string) tcp.Handler {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, nameRouter, typeName)).Debug("Creating middleware")
+
+	return &tenantMiddleware{
+		next:    next,
+		rollups: rollups,
+		tenant:  tenantName,
+	}
+}
+
+// WrapRouterHandler Wraps tenant accounting to tcp.Constructor.
+func WrapRouterHandler(ctx context.Context, rollups *tenant.Rollups, tenantName string) tcp.Constructor {
+	return func(next tcp.Handler) (tcp.Handler, error) {
+		return New(ctx, next, rollups, tenantName), nil
+	}
+}
+
+// ServeTCP serves the given TCP connection.
+func (m *tenantMiddleware) ServeTCP(conn tcp.WriteCloser) {
+	m.rollups.ConnectionOpened(m.tenant)
+
+	counter := &countingConn{WriteCloser: conn}
+	defer func() {
+		m.rollups.ConnectionClosed(m.tenant, counter.read.Load(), counter.written.Load())
+	}()
+
+	m.next.ServeTCP(counter)
+}
+
+// countingConn counts the bytes read from and written to the client.
+type countingConn struct {
+	tcp.WriteCloser
+
+	read    atomic.Int64
+	written atomic.Int64
+}
+
+func (c *countingConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+	c.read.Add(int64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(p []byte) (int, error) {
+	n, err := c.WriteCloser.Write(p)
+	c.written.Add(int64(n))
+	return n, err
+}
+
+// NetConn returns the client connection.
+func (c *countingConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
@@ -0,0 +1,59 @@
+package tcptenant
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+	"github.com/traefik/traefik/v2/pkg/tenant"
+)
+
+func TestTenantMiddleware_ServeTCP(t *testing.T) {
+	rollups := tenant.NewRollups(nil)
+
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		summary, ok := rollups.Summary("acme")
+		require.True(t, ok)
+		assert.Equal(t, int64(1), summary.ActiveConnections)
+
+		buf := make([]byte, 4)
+		_, err := io.ReadFull(conn, buf)
+		require.NoError(t, err)
+
+		_, err = conn.Write([]byte("pong!"))
+		require.NoError(t, err)
+	})
+
+	middleware := New(context.Background(), next, rollups, "acme")
+
+	server, client := net.Pipe()
+	go func() {
+		_, _ = client.Write([]byte("ping"))
+		_, _ = io.ReadAll(client)
+	}()
+
+	middleware.ServeTCP(fakeConn{Conn: server})
+	_ = server.Close()
+
+	summary, ok := rollups.Summary("acme")
+	require.True(t, ok)
+
+	assert.Equal(t, tenant.Summary{
+		Name:             "acme",
+		RequestBytes:     4,
+		ResponseBytes:    5,
+		TotalConnections: 1,
+	}, summary)
+}
+
+type fakeConn struct {
+	net.Conn
+}
+
+func (c fakeConn) CloseWrite() error {
+	return nil
+}
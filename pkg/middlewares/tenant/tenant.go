@@ -0,0 +1,75 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/containous/alice"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/capture"
+	"github.com/traefik/traefik/v2/pkg/tenant"
+)
+
+const (
+	typeName   = "Tenant"
+	nameRouter = "tenant-router"
+)
+
+type tenantMiddleware struct {
+	next     http.Handler
+	captured http.Handler
+	rollups  *tenant.Rollups
+	tenant   string
+}
+
+// New creates a new middleware accounting the requests handled by a router to its tenant.
+func New(ctx context.Context, next http.Handler, rollups *tenant.Rollups, tenantName string) http.Handler {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, nameRouter, typeName)).Debug("Creating middleware")
+
+	m := &tenantMiddleware{
+		next:    next,
+		rollups: rollups,
+		tenant:  tenantName,
+	}
+
+	// capture.Wrap never returns an error.
+	m.captured, _ = capture.Wrap(http.HandlerFunc(m.serveCaptured))
+
+	return m
+}
+
+// WrapRouterHandler Wraps tenant accounting to alice.Constructor.
+func WrapRouterHandler(ctx context.Context, rollups *tenant.Rollups, tenantName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return New(ctx, next, rollups, tenantName), nil
+	}
+}
+
+func (m *tenantMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The capture middleware is only added at the entry point level when metrics or access logs are enabled.
+	if _, err := capture.FromContext(req.Context()); err != nil {
+		m.captured.ServeHTTP(rw, req)
+		return
+	}
+
+	m.serveCaptured(rw, req)
+}
+
+func (m *tenantMiddleware) serveCaptured(rw http.ResponseWriter, req *http.Request) {
+	capt, err := capture.FromContext(req.Context())
+	if err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), nameRouter, typeName)).WithError(err).Errorf("Could not get Capture")
+		m.next.ServeHTTP(rw, req)
+		return
+	}
+
+	next := m.next
+	if capt.NeedsReset(rw) {
+		next = capt.Reset(m.next)
+	}
+
+	next.ServeHTTP(rw, req)
+
+	m.rollups.ObserveRequest(m.tenant, capt.StatusCode(), capt.RequestSize(), capt.ResponseSize())
+}
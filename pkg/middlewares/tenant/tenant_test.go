@@ -0,0 +1,71 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/middlewares/capture"
+	"github.com/traefik/traefik/v2/pkg/tenant"
+)
+
+func TestTenantMiddleware(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		withCapture   bool
+		statusCode    int
+		expectedError uint64
+	}{
+		{
+			desc:       "without capture",
+			statusCode: http.StatusOK,
+		},
+		{
+			desc:        "with capture at the entry point",
+			withCapture: true,
+			statusCode:  http.StatusOK,
+		},
+		{
+			desc:          "server error",
+			statusCode:    http.StatusServiceUnavailable,
+			expectedError: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rollups := tenant.NewRollups(nil)
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				_, _ = req.Body.Read(make([]byte, 64))
+				rw.WriteHeader(test.statusCode)
+				_, _ = rw.Write([]byte("response"))
+			})
+
+			var handler http.Handler = New(context.Background(), next, rollups, "acme")
+			if test.withCapture {
+				var err error
+				handler, err = capture.Wrap(handler)
+				require.NoError(t, err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "http://foo.bar", strings.NewReader("request"))
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			summary, ok := rollups.Summary("acme")
+			require.True(t, ok)
+
+			assert.Equal(t, uint64(1), summary.Requests)
+			assert.Equal(t, test.expectedError, summary.ServerErrors)
+			assert.Equal(t, int64(len("request")), summary.RequestBytes)
+			assert.Equal(t, int64(len("response")), summary.ResponseBytes)
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/traefik/traefik/v2/pkg/ip"
+	"github.com/traefik/traefik/v2/pkg/jwtclaims"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
 	"github.com/traefik/traefik/v2/pkg/rules"
@@ -27,6 +28,7 @@ var httpFuncs = map[string]func(*mux.Route, ...string) error{
 	"Headers":       headers,
 	"HeadersRegexp": headersRegexp,
 	"Query":         query,
+	"JWTClaim":      jwtClaim,
 }
 
 // Muxer handles routing with rules.
@@ -54,7 +56,8 @@ func NewMuxer() (*Muxer, error) {
 }
 
 // AddRoute add a new route to the router.
-func (r *Muxer) AddRoute(rule string, priority int, handler http.Handler) error {
+// The route only matches when all the conditions are true, such as during the time windows of a schedule.
+func (r *Muxer) AddRoute(rule string, priority int, handler http.Handler, conditions ...func() bool) error {
 	parse, err := r.parser.Parse(rule)
 	if err != nil {
 		return fmt.Errorf("error while parsing rule %s: %w", rule, err)
@@ -77,6 +80,13 @@ func (r *Muxer) AddRoute(rule string, priority int, handler http.Handler) error
 		return err
 	}
 
+	for _, condition := range conditions {
+		condition := condition
+		route.MatcherFunc(func(*http.Request, *mux.RouteMatch) bool {
+			return condition()
+		})
+	}
+
 	return nil
 }
 
@@ -245,6 +255,28 @@ func query(route *mux.Route, query ...string) error {
 	return route.GetError()
 }
 
+func jwtClaim(route *mux.Route, values ...string) error {
+	if len(values) < 2 {
+		return fmt.Errorf("\"JWTClaim\" matcher needs a claim name and at least one value, got %d values", len(values))
+	}
+
+	name, expected := values[0], values[1:]
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		for _, value := range jwtclaims.FromRequest(req).Values(name) {
+			for _, exp := range expected {
+				if value == exp {
+					return true
+				}
+			}
+		}
+
+		return false
+	})
+
+	return nil
+}
+
 func addRuleOnRouter(router *mux.Router, rule *rules.Tree) error {
 	switch rule.Matcher {
 	case "and":
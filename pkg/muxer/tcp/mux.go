@@ -61,9 +61,14 @@ type ConnData struct {
 
 // NewConnData builds a connData struct from the given parameters.
 func NewConnData(serverName string, conn tcp.WriteCloser, alpnProtos []string) (ConnData, error) {
-	remoteIP, _, err := net.SplitHostPort(conn.RemoteAddr().String())
-	if err != nil {
-		return ConnData{}, fmt.Errorf("error while parsing remote address %q: %w", conn.RemoteAddr().String(), err)
+	// The connections accepted on a Unix domain socket have no remote IP, which no ClientIP rule matches.
+	var remoteIP string
+	if _, ok := conn.RemoteAddr().(*net.UnixAddr); !ok {
+		var err error
+		remoteIP, _, err = net.SplitHostPort(conn.RemoteAddr().String())
+		if err != nil {
+			return ConnData{}, fmt.Errorf("error while parsing remote address %q: %w", conn.RemoteAddr().String(), err)
+		}
 	}
 
 	// as per https://datatracker.ietf.org/doc/html/rfc6066:
@@ -113,7 +118,8 @@ func (m Muxer) Match(meta ConnData) (tcp.Handler, bool) {
 
 // AddRoute adds a new route, associated to the given handler, at the given
 // priority, to the muxer.
-func (m *Muxer) AddRoute(rule string, priority int, handler tcp.Handler) error {
+// The route only matches when all the conditions are true, such as during the time windows of a schedule.
+func (m *Muxer) AddRoute(rule string, priority int, handler tcp.Handler, conditions ...func() bool) error {
 	parse, err := m.parser.Parse(rule)
 	if err != nil {
 		return fmt.Errorf("error while parsing rule %s: %w", rule, err)
@@ -132,6 +138,18 @@ func (m *Muxer) AddRoute(rule string, priority int, handler tcp.Handler) error {
 		return err
 	}
 
+	for _, condition := range conditions {
+		condition := condition
+		ruleMatchers := matchers
+		matchers = matchersTree{
+			operator: "and",
+			left:     &ruleMatchers,
+			right: &matchersTree{matcher: func(ConnData) bool {
+				return condition()
+			}},
+		}
+	}
+
 	var catchAll bool
 	if ruleTree.RuleLeft == nil && ruleTree.RuleRight == nil && len(ruleTree.Value) == 1 {
 		catchAll = ruleTree.Value[0] == "*" && strings.EqualFold(ruleTree.Matcher, "HostSNI")
@@ -679,6 +679,48 @@ func Test_HostSNICatchAll(t *testing.T) {
 	}
 }
 
+func Test_addTCPRouteConditions(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		conditions []func() bool
+		matching   bool
+	}{
+		{
+			desc:     "without conditions",
+			matching: true,
+		},
+		{
+			desc:       "with true conditions",
+			conditions: []func() bool{func() bool { return true }, func() bool { return true }},
+			matching:   true,
+		},
+		{
+			desc:       "with a false condition",
+			conditions: []func() bool{func() bool { return true }, func() bool { return false }},
+			matching:   false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute("HostSNI(`foobar`)", 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}), test.conditions...)
+			require.NoError(t, err)
+
+			handler, _ := muxer.Match(ConnData{
+				serverName: "foobar",
+			})
+			assert.Equal(t, test.matching, handler != nil)
+		})
+	}
+}
+
 func Test_HostSNI(t *testing.T) {
 	testCases := []struct {
 		desc       string
@@ -0,0 +1,150 @@
+package overrides
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	ptypes "github.com/traefik/paerser/types"
+)
+
+// Parameters are the parameters of a middleware overridden at runtime, through the API.
+// Only the parameters matching the type of the middleware can be set.
+type Parameters struct {
+	RateLimit  *RateLimit  `json:"rateLimit,omitempty"`
+	Compress   *Compress   `json:"compress,omitempty"`
+	Accounting *Accounting `json:"accounting,omitempty"`
+}
+
+// RateLimit overrides the rate of a RateLimit middleware.
+type RateLimit struct {
+	Average int64           `json:"average"`
+	Period  ptypes.Duration `json:"period,omitempty"`
+	Burst   int64           `json:"burst"`
+}
+
+// Compress overrides the compression level of a Compress middleware.
+type Compress struct {
+	CompressionLevel int `json:"compressionLevel"`
+}
+
+// Accounting overrides the sample rate of an Accounting middleware.
+type Accounting struct {
+	SampleRate float64 `json:"sampleRate"`
+}
+
+// Validate checks that exactly one kind of parameters is set, with valid values.
+func (p Parameters) Validate() error {
+	var count int
+
+	if p.RateLimit != nil {
+		count++
+		if p.RateLimit.Average < 0 || p.RateLimit.Burst < 0 || p.RateLimit.Period < 0 {
+			return errors.New("the rate limit average, period, and burst must not be negative")
+		}
+	}
+
+	if p.Compress != nil {
+		count++
+		if p.Compress.CompressionLevel < 1 || p.Compress.CompressionLevel > 9 {
+			return fmt.Errorf("the compression level must be between 1 and 9, got %d", p.Compress.CompressionLevel)
+		}
+	}
+
+	if p.Accounting != nil {
+		count++
+		if p.Accounting.SampleRate <= 0 || p.Accounting.SampleRate > 1 {
+			return fmt.Errorf("the sample rate must be greater than 0, and at most 1, got %v", p.Accounting.SampleRate)
+		}
+	}
+
+	if count != 1 {
+		return errors.New("exactly one of rateLimit, compress, or accounting must be set")
+	}
+
+	return nil
+}
+
+// Store holds the parameters of the middlewares overridden through the API.
+// The overrides are ephemeral: they are cleared when a new configuration is applied.
+type Store struct {
+	mu          sync.RWMutex
+	middlewares map[string]Parameters
+}
+
+// NewStore creates a new Store.
+func NewStore() *Store {
+	return &Store{
+		middlewares: make(map[string]Parameters),
+	}
+}
+
+// Set overrides the parameters of the given middleware.
+func (s *Store) Set(middleware string, params Parameters) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.middlewares[middleware] = params
+}
+
+// Delete removes the overrides of the given middleware.
+func (s *Store) Delete(middleware string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.middlewares, middleware)
+}
+
+// Get returns the overridden parameters of the given middleware, if any.
+func (s *Store) Get(middleware string) (Parameters, bool) {
+	if s == nil {
+		return Parameters{}, false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	params, ok := s.middlewares[middleware]
+	return params, ok
+}
+
+// All returns the overridden parameters of all the middlewares, keyed by middleware name.
+func (s *Store) All() map[string]Parameters {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.middlewares) == 0 {
+		return nil
+	}
+
+	all := make(map[string]Parameters, len(s.middlewares))
+	for name, params := range s.middlewares {
+		all[name] = params
+	}
+
+	return all
+}
+
+// Reset removes all the overrides.
+func (s *Store) Reset() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.middlewares = make(map[string]Parameters)
+}
@@ -0,0 +1,109 @@
+package overrides
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store := NewStore()
+	assert.Nil(t, store.All())
+
+	params := Parameters{Compress: &Compress{CompressionLevel: 1}}
+	store.Set("foo@file", params)
+
+	got, ok := store.Get("foo@file")
+	assert.True(t, ok)
+	assert.Equal(t, params, got)
+
+	_, ok = store.Get("bar@file")
+	assert.False(t, ok)
+
+	assert.Equal(t, map[string]Parameters{"foo@file": params}, store.All())
+
+	store.Delete("foo@file")
+	_, ok = store.Get("foo@file")
+	assert.False(t, ok)
+
+	store.Set("foo@file", params)
+	store.Reset()
+	assert.Nil(t, store.All())
+}
+
+func TestStore_nil(t *testing.T) {
+	var store *Store
+
+	store.Set("foo@file", Parameters{})
+	store.Delete("foo@file")
+	store.Reset()
+
+	_, ok := store.Get("foo@file")
+	assert.False(t, ok)
+	assert.Nil(t, store.All())
+}
+
+func TestParameters_Validate(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		params      Parameters
+		expectedErr bool
+	}{
+		{
+			desc:   "rate limit",
+			params: Parameters{RateLimit: &RateLimit{Average: 100, Burst: 200}},
+		},
+		{
+			desc:        "negative rate limit",
+			params:      Parameters{RateLimit: &RateLimit{Average: -1}},
+			expectedErr: true,
+		},
+		{
+			desc:   "compression level",
+			params: Parameters{Compress: &Compress{CompressionLevel: 9}},
+		},
+		{
+			desc:        "compression level out of range",
+			params:      Parameters{Compress: &Compress{CompressionLevel: 10}},
+			expectedErr: true,
+		},
+		{
+			desc:        "default compression level",
+			params:      Parameters{Compress: &Compress{}},
+			expectedErr: true,
+		},
+		{
+			desc:   "sample rate",
+			params: Parameters{Accounting: &Accounting{SampleRate: 0.1}},
+		},
+		{
+			desc:        "zero sample rate",
+			params:      Parameters{Accounting: &Accounting{}},
+			expectedErr: true,
+		},
+		{
+			desc:        "no parameters",
+			expectedErr: true,
+		},
+		{
+			desc:        "several kinds of parameters",
+			params:      Parameters{Compress: &Compress{}, Accounting: &Accounting{SampleRate: 1}},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := test.params.Validate()
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !windows
+// +build !windows
+
+package preflight
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+func checkOpenFiles(minOpenFiles uint64) error {
+	var limit unix.Rlimit
+	if err := unix.Getrlimit(unix.RLIMIT_NOFILE, &limit); err != nil {
+		return err
+	}
+
+	if uint64(limit.Cur) < minOpenFiles {
+		return fmt.Errorf("the limit of open files is %d, lower than %d (raise it with ulimit -n, or LimitNOFILE in the systemd unit)", limit.Cur, minOpenFiles)
+	}
+
+	return nil
+}
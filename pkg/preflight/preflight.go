@@ -0,0 +1,363 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
+	"github.com/traefik/traefik/v2/pkg/types"
+	"github.com/traefik/traefik/v2/pkg/unixsocket"
+)
+
+// Result is the outcome of a preflight check.
+type Result struct {
+	Check string
+	Err   error
+}
+
+// Results are the outcomes of the preflight checks.
+type Results []Result
+
+// Err returns an error listing the failed checks, or nil if all of them passed.
+func (r Results) Err() error {
+	var failed []string
+	for _, result := range r {
+		if result.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", result.Check, result.Err))
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("preflight checks failed:\n\t%s", strings.Join(failed, "\n\t"))
+}
+
+// Run runs the preflight checks of the given static configuration:
+// the entry points addresses are bindable, the certificate and configuration files are readable,
+// the providers endpoints are reachable, and the limit of open files is high enough.
+func Run(ctx context.Context, staticConfiguration static.Configuration) Results {
+	conf := staticConfiguration.Preflight
+	if conf == nil {
+		conf = &static.Preflight{}
+		conf.SetDefaults()
+	}
+
+	var results Results
+	results = append(results, checkEntryPoints(staticConfiguration.EntryPoints)...)
+	results = append(results, checkFiles(staticConfiguration)...)
+	results = append(results, checkProviders(ctx, staticConfiguration.Providers, time.Duration(conf.Timeout))...)
+	results = append(results, Result{Check: "open files limit", Err: checkOpenFiles(conf.MinOpenFiles)})
+
+	return results
+}
+
+func checkEntryPoints(entryPoints static.EntryPoints) Results {
+	var names []string
+	for name := range entryPoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var results Results
+	for _, name := range names {
+		results = append(results, Result{
+			Check: fmt.Sprintf("entry point %s", name),
+			Err:   checkBindable(entryPoints[name]),
+		})
+	}
+
+	return results
+}
+
+func checkBindable(entryPoint *static.EntryPoint) error {
+	protocol, err := entryPoint.GetProtocol()
+	if err != nil {
+		return err
+	}
+
+	if path, ok := entryPoint.GetUnixSocketPath(); ok {
+		return checkUnixSocketBindable(path)
+	}
+
+	var closer interface{ Close() error }
+	if protocol == "udp" {
+		closer, err = net.ListenPacket("udp", entryPoint.GetAddress())
+	} else {
+		closer, err = net.Listen("tcp", entryPoint.GetAddress())
+	}
+
+	switch {
+	case errors.Is(err, syscall.EADDRINUSE):
+		return fmt.Errorf("%w (another process is already listening on %s)", err, entryPoint.GetAddress())
+	case errors.Is(err, syscall.EACCES):
+		return fmt.Errorf("%w (the ports below 1024 require root or the CAP_NET_BIND_SERVICE capability)", err)
+	case err != nil:
+		return err
+	}
+
+	return closer.Close()
+}
+
+// checkUnixSocketBindable checks that the Unix domain socket can be listened on,
+// removing the stale socket left by a previous process, as the entry point would.
+func checkUnixSocketBindable(path string) error {
+	if err := unixsocket.RemoveStale(path); err != nil {
+		return err
+	}
+
+	// The socket is removed when the listener is closed.
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+
+	return listener.Close()
+}
+
+func checkFiles(staticConfiguration static.Configuration) Results {
+	var results Results
+
+	if staticConfiguration.ServersTransport != nil {
+		for _, rootCA := range staticConfiguration.ServersTransport.RootCAs {
+			results = append(results, checkFileOrContent("serversTransport root CA", rootCA.String()))
+		}
+	}
+
+	if providers := staticConfiguration.Providers; providers != nil {
+		if providers.File != nil {
+			if providers.File.Filename != "" {
+				results = append(results, checkReadable("file provider", providers.File.Filename))
+			}
+			if providers.File.Directory != "" {
+				results = append(results, checkReadable("file provider", providers.File.Directory))
+			}
+		}
+
+		if providers.Docker != nil {
+			results = append(results, checkClientTLS("docker provider", providers.Docker.TLS)...)
+		}
+		if providers.Consul != nil {
+			results = append(results, checkClientTLS("consul provider", providers.Consul.TLS)...)
+		}
+		if providers.Etcd != nil {
+			results = append(results, checkClientTLS("etcd provider", providers.Etcd.TLS)...)
+		}
+		if providers.Redis != nil {
+			results = append(results, checkClientTLS("redis provider", providers.Redis.TLS)...)
+		}
+		if providers.HTTP != nil {
+			results = append(results, checkClientTLS("http provider", providers.HTTP.TLS)...)
+		}
+	}
+
+	var resolvers []string
+	for name := range staticConfiguration.CertificatesResolvers {
+		resolvers = append(resolvers, name)
+	}
+	sort.Strings(resolvers)
+
+	for _, name := range resolvers {
+		resolver := staticConfiguration.CertificatesResolvers[name]
+		if resolver.ACME == nil || resolver.ACME.Storage == "" {
+			continue
+		}
+
+		results = append(results, Result{
+			Check: fmt.Sprintf("certificates resolver %s storage %s", name, resolver.ACME.Storage),
+			Err:   checkWritable(resolver.ACME.Storage),
+		})
+	}
+
+	return results
+}
+
+func checkClientTLS(name string, clientTLS *types.ClientTLS) Results {
+	if clientTLS == nil {
+		return nil
+	}
+
+	var results Results
+	for _, fileOrContent := range []string{clientTLS.CA, clientTLS.Cert, clientTLS.Key} {
+		if fileOrContent != "" {
+			results = append(results, checkFileOrContent(name+" TLS", fileOrContent))
+		}
+	}
+
+	return results
+}
+
+// checkFileOrContent checks that a certificate, given either as a path or as its PEM content, is readable.
+func checkFileOrContent(name, fileOrContent string) Result {
+	if strings.Contains(fileOrContent, "-----BEGIN") {
+		return Result{Check: name + " (inline content)"}
+	}
+
+	return checkReadable(name, fileOrContent)
+}
+
+func checkReadable(name, path string) Result {
+	result := Result{Check: fmt.Sprintf("%s %s", name, path)}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	if info.IsDir() {
+		_, result.Err = os.ReadDir(path)
+		return result
+	}
+
+	_, result.Err = traefiktls.FileOrContent(path).Read()
+	return result
+}
+
+// checkWritable checks that the given file can be created or updated.
+func checkWritable(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		file, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		return file.Close()
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".preflight-")
+	if err != nil {
+		return err
+	}
+
+	_ = file.Close()
+	return os.Remove(file.Name())
+}
+
+func checkProviders(ctx context.Context, providers *static.Providers, timeout time.Duration) Results {
+	if providers == nil {
+		return nil
+	}
+
+	var endpoints []struct{ name, endpoint string }
+	add := func(name string, values ...string) {
+		for _, value := range values {
+			if value != "" {
+				endpoints = append(endpoints, struct{ name, endpoint string }{name: name, endpoint: value})
+			}
+		}
+	}
+
+	if providers.Docker != nil {
+		add("docker provider", providers.Docker.Endpoint)
+	}
+	if providers.Marathon != nil {
+		add("marathon provider", strings.Split(providers.Marathon.Endpoint, ",")...)
+	}
+	if providers.KubernetesIngress != nil {
+		add("kubernetesIngress provider", providers.KubernetesIngress.Endpoint)
+	}
+	if providers.KubernetesCRD != nil {
+		add("kubernetesCRD provider", providers.KubernetesCRD.Endpoint)
+	}
+	if providers.KubernetesGateway != nil {
+		add("kubernetesGateway provider", providers.KubernetesGateway.Endpoint)
+	}
+	if providers.ConsulCatalog != nil && providers.ConsulCatalog.Endpoint != nil {
+		add("consulCatalog provider", providers.ConsulCatalog.Endpoint.Address)
+	}
+	if providers.Nomad != nil && providers.Nomad.Endpoint != nil {
+		add("nomad provider", providers.Nomad.Endpoint.Address)
+	}
+	if providers.Consul != nil {
+		add("consul provider", providers.Consul.Endpoints...)
+	}
+	if providers.Etcd != nil {
+		add("etcd provider", providers.Etcd.Endpoints...)
+	}
+	if providers.ZooKeeper != nil {
+		add("zooKeeper provider", providers.ZooKeeper.Endpoints...)
+	}
+	if providers.Redis != nil {
+		add("redis provider", providers.Redis.Endpoints...)
+	}
+	if providers.HTTP != nil {
+		add("http provider", providers.HTTP.Endpoint)
+	}
+
+	var results Results
+	for _, e := range endpoints {
+		results = append(results, Result{
+			Check: fmt.Sprintf("%s %s", e.name, e.endpoint),
+			Err:   checkReachable(ctx, e.endpoint, timeout),
+		})
+	}
+
+	return results
+}
+
+// checkReachable checks that a connection can be opened to the given endpoint,
+// which is either an URL or an address.
+func checkReachable(ctx context.Context, endpoint string, timeout time.Duration) error {
+	network, address, err := parseEndpoint(endpoint)
+	if err != nil {
+		return err
+	}
+
+	if network == "npipe" {
+		// The named pipes cannot be dialed with the standard library.
+		return nil
+	}
+
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return fmt.Errorf("%w (check the endpoint and that the provider is up and reachable from this host)", err)
+	}
+
+	return conn.Close()
+}
+
+func parseEndpoint(endpoint string) (string, string, error) {
+	if !strings.Contains(endpoint, "://") {
+		if _, _, err := net.SplitHostPort(endpoint); err != nil {
+			return "", "", fmt.Errorf("invalid endpoint: %w", err)
+		}
+		return "tcp", endpoint, nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid endpoint: %w", err)
+	}
+
+	if u.Scheme == "unix" || u.Scheme == "npipe" {
+		return u.Scheme, u.Path, nil
+	}
+
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "https":
+			port = "443"
+		case "http":
+			port = "80"
+		case "ssh":
+			port = "22"
+		default:
+			return "", "", fmt.Errorf("invalid endpoint: missing port in %s", endpoint)
+		}
+	}
+
+	return "tcp", net.JoinHostPort(u.Hostname(), port), nil
+}
@@ -0,0 +1,139 @@
+package preflight
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/provider/file"
+	"github.com/traefik/traefik/v2/pkg/provider/kv"
+	"github.com/traefik/traefik/v2/pkg/provider/kv/etcd"
+)
+
+func TestRun(t *testing.T) {
+	used, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = used.Close() })
+
+	provider, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = provider.Close() })
+
+	unreachable, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, unreachable.Close())
+
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "dynamic.yml")
+	require.NoError(t, os.WriteFile(configFile, []byte("{}"), 0o600))
+
+	staticConfiguration := static.Configuration{
+		EntryPoints: static.EntryPoints{
+			"free": {Address: "127.0.0.1:0"},
+			"udp":  {Address: "127.0.0.1:0/udp"},
+			"used": {Address: used.Addr().String()},
+		},
+		Providers: &static.Providers{
+			File: &file.Provider{Filename: configFile},
+			Etcd: &etcd.Provider{Provider: kv.Provider{Endpoints: []string{provider.Addr().String(), unreachable.Addr().String()}}},
+		},
+		Preflight: &static.Preflight{Timeout: ptypes.Duration(time.Second)},
+	}
+
+	results := Run(context.Background(), staticConfiguration)
+
+	failed := map[string]bool{}
+	for _, result := range results {
+		failed[result.Check] = result.Err != nil
+	}
+
+	assert.Equal(t, map[string]bool{
+		"entry point free":                             false,
+		"entry point udp":                              false,
+		"entry point used":                             true,
+		"file provider " + configFile:                  false,
+		"etcd provider " + provider.Addr().String():    false,
+		"etcd provider " + unreachable.Addr().String(): true,
+		"open files limit":                             false,
+	}, failed)
+
+	err = results.Err()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "entry point used")
+	assert.Contains(t, err.Error(), "etcd provider "+unreachable.Addr().String())
+}
+
+func TestResults_Err(t *testing.T) {
+	assert.NoError(t, Results{{Check: "foo"}}.Err())
+	assert.NoError(t, Results{}.Err())
+}
+
+func TestCheckFileOrContent(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte("ca"), 0o600))
+
+	assert.NoError(t, checkFileOrContent("ca", caFile).Err)
+	assert.NoError(t, checkFileOrContent("ca", "-----BEGIN CERTIFICATE-----\nfoo\n-----END CERTIFICATE-----").Err)
+	assert.Error(t, checkFileOrContent("ca", filepath.Join(dir, "missing.pem")).Err)
+}
+
+func TestCheckWritable(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NoError(t, checkWritable(filepath.Join(dir, "acme.json")))
+	assert.Error(t, checkWritable(filepath.Join(dir, "missing", "acme.json")))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestParseEndpoint(t *testing.T) {
+	testCases := []struct {
+		endpoint        string
+		expectedNetwork string
+		expectedAddress string
+		expectedErr     bool
+	}{
+		{endpoint: "127.0.0.1:8500", expectedNetwork: "tcp", expectedAddress: "127.0.0.1:8500"},
+		{endpoint: "unix:///var/run/docker.sock", expectedNetwork: "unix", expectedAddress: "/var/run/docker.sock"},
+		{endpoint: "tcp://127.0.0.1:2375", expectedNetwork: "tcp", expectedAddress: "127.0.0.1:2375"},
+		{endpoint: "https://kubernetes.local", expectedNetwork: "tcp", expectedAddress: "kubernetes.local:443"},
+		{endpoint: "http://127.0.0.1:4646", expectedNetwork: "tcp", expectedAddress: "127.0.0.1:4646"},
+		{endpoint: "tcp://127.0.0.1", expectedErr: true},
+		{endpoint: "localhost", expectedErr: true},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.endpoint, func(t *testing.T) {
+			t.Parallel()
+
+			network, address, err := parseEndpoint(test.endpoint)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedNetwork, network)
+			assert.Equal(t, test.expectedAddress, address)
+		})
+	}
+}
+
+func TestCheckReachable_timeout(t *testing.T) {
+	// 192.0.2.0/24 is reserved for documentation (RFC 5737), and is not routed.
+	start := time.Now()
+	err := checkReachable(context.Background(), "192.0.2.1:80", 100*time.Millisecond)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,276 @@
+package probe
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// Runner periodically sends the checks of a synthetic probe to an entry point,
+// so that they go through its routers, middlewares, and services, like the requests of the clients,
+// and reports their results through the logs and the metrics.
+type Runner struct {
+	name     string
+	registry metrics.Registry
+	timeout  time.Duration
+	check    func(ctx context.Context) error
+
+	// tlsConfig is the TLS configuration shared by the checks, which clone it before use.
+	tlsConfig *tls.Config
+}
+
+// NewRunner creates a new Runner of the given probe, sending its checks to the given entry point.
+func NewRunner(registry metrics.Registry, name string, conf static.Probe, entryPoint static.EntryPoint) (*Runner, error) {
+	if registry == nil {
+		registry = metrics.NewVoidRegistry()
+	}
+
+	protocol, err := entryPoint.GetProtocol()
+	if err != nil {
+		return nil, err
+	}
+
+	if protocol != "tcp" {
+		return nil, fmt.Errorf("the entry point of the probe must be a TCP one: %s", protocol)
+	}
+
+	network, address, err := target(entryPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	dial := func(ctx context.Context) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	r := &Runner{
+		name:     name,
+		registry: registry,
+		timeout:  time.Duration(conf.Timeout),
+		tlsConfig: &tls.Config{
+			// The probe checks the routes, not the certificates, which may not be issued for the loopback address.
+			InsecureSkipVerify: true,
+		},
+	}
+
+	switch {
+	case conf.HTTP != nil:
+		// The requests sent to a Unix domain socket have no address to default their host to.
+		defaultHost := address
+		if network == "unix" {
+			defaultHost = "localhost"
+		}
+
+		r.check, err = httpCheck(*conf.HTTP, defaultHost, dial, r.tlsConfig)
+	case conf.TCP != nil:
+		r.check = tcpCheck(*conf.TCP, dial, r.tlsConfig)
+	default:
+		err = errors.New("exactly one of http and tcp must be defined")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// SetFIPS enables the FIPS mode, restricting the TLS connections of the checks to the FIPS-approved parameters.
+// It must be called before the checks are sent.
+func (r *Runner) SetFIPS(enabled bool) {
+	if enabled {
+		traefiktls.RestrictToFIPS(r.tlsConfig)
+	}
+}
+
+// Run sends a check at each interval, until the context is done.
+// The first check is sent after the first interval, so that the entry points are started in the meantime.
+func (r *Runner) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = r.Check(ctx)
+		}
+	}
+}
+
+// Check sends a check, and returns why it failed, if it did.
+func (r *Runner) Check(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithField(log.ProbeName, r.name)
+
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := r.check(ctx)
+
+	result := "success"
+	if err != nil {
+		result = "failure"
+		logger.Warnf("Probe check failed: %v", err)
+	} else {
+		logger.Debugf("Probe check succeeded in %s", time.Since(start))
+	}
+
+	r.registry.ProbeChecksCounter().With("probe", r.name, "result", result).Add(1)
+	r.registry.ProbeDurationHistogram().With("probe", r.name, "result", result).ObserveFromStart(start)
+
+	return err
+}
+
+// httpCheck returns a check sending the configured HTTP request, on a new connection each time,
+// and expecting a response with one of the configured status codes.
+func httpCheck(conf static.HTTPProbe, defaultHost string, dial func(ctx context.Context) (net.Conn, error), tlsConfig *tls.Config) (func(ctx context.Context) error, error) {
+	status, err := types.NewHTTPCodeRanges(conf.Status)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the expected status codes: %w", err)
+	}
+
+	host := conf.Host
+	if host == "" {
+		host = defaultHost
+	}
+
+	scheme := "http"
+	if conf.TLS {
+		scheme = "https"
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dial(ctx)
+			},
+			// The server name defaults to the host of the request, and the configuration is cloned for each connection.
+			TLSClientConfig:   tlsConfig,
+			DisableKeepAlives: true,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	url := scheme + "://" + host + conf.Path
+
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, conf.Method, url, http.NoBody)
+		if err != nil {
+			return err
+		}
+
+		for name, value := range conf.Headers {
+			req.Header.Set(name, value)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+
+		if !status.Contains(resp.StatusCode) {
+			return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+
+		return nil
+	}, nil
+}
+
+// tcpCheck returns a check opening a TCP connection, over TLS if a server name is configured,
+// sending the configured data, and expecting the response to start with the configured data.
+func tcpCheck(conf static.TCPProbe, dial func(ctx context.Context) (net.Conn, error), tlsConfig *tls.Config) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		conn, err := dial(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = conn.Close() }()
+
+		if deadline, ok := ctx.Deadline(); ok {
+			if err = conn.SetDeadline(deadline); err != nil {
+				return err
+			}
+		}
+
+		if conf.ServerName != "" {
+			config := tlsConfig.Clone()
+			config.ServerName = conf.ServerName
+
+			tlsConn := tls.Client(conn, config)
+			if err = tlsConn.HandshakeContext(ctx); err != nil {
+				return fmt.Errorf("TLS handshake: %w", err)
+			}
+			conn = tlsConn
+		}
+
+		if conf.Send != "" {
+			if _, err = conn.Write([]byte(conf.Send)); err != nil {
+				return fmt.Errorf("sending data: %w", err)
+			}
+		}
+
+		if conf.Expect == "" {
+			return nil
+		}
+
+		received := make([]byte, len(conf.Expect))
+		n, err := io.ReadFull(conn, received)
+		if err != nil {
+			return fmt.Errorf("receiving data, after %q: %w", received[:n], err)
+		}
+
+		if !bytes.Equal(received, []byte(conf.Expect)) {
+			return fmt.Errorf("unexpected data received: %q", received)
+		}
+
+		return nil
+	}
+}
+
+// target returns the network and the address the checks are sent to, for the given entry point.
+// The entry points listening on all the interfaces are reached on the loopback address.
+func target(entryPoint static.EntryPoint) (string, string, error) {
+	if path, ok := entryPoint.GetUnixSocketPath(); ok {
+		return "unix", path, nil
+	}
+
+	host, port, err := net.SplitHostPort(entryPoint.GetAddress())
+	if err != nil {
+		return "", "", fmt.Errorf("parsing the entry point address: %w", err)
+	}
+
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		host = "127.0.0.1"
+		if ip != nil && ip.To4() == nil {
+			host = "::1"
+		}
+	}
+
+	return "tcp", net.JoinHostPort(host, port), nil
+}
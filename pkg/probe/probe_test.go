@@ -0,0 +1,258 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+func TestRunner_Check_http(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Host != "foo.localhost" || req.Header.Get("X-Probe") != "true" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if req.URL.Path == "/redirect" {
+			http.Redirect(rw, req, "/", http.StatusFound)
+			return
+		}
+
+		rw.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	testCases := []struct {
+		desc      string
+		host      string
+		path      string
+		status    []string
+		expectErr bool
+	}{
+		{
+			desc: "expected status",
+			host: "foo.localhost",
+			path: "/",
+		},
+		{
+			desc: "redirect not followed",
+			host: "foo.localhost",
+			path: "/redirect",
+		},
+		{
+			desc:      "unexpected status",
+			host:      "bar.localhost",
+			path:      "/",
+			expectErr: true,
+		},
+		{
+			desc:   "custom status",
+			host:   "bar.localhost",
+			path:   "/",
+			status: []string{"404"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conf := static.HTTPProbe{}
+			conf.SetDefaults()
+			conf.Host = test.host
+			conf.Path = test.path
+			conf.Headers = map[string]string{"X-Probe": "true"}
+			if test.status != nil {
+				conf.Status = test.status
+			}
+
+			runner, err := NewRunner(nil, "foo", static.Probe{HTTP: &conf}, static.EntryPoint{Address: server.Listener.Addr().String()})
+			require.NoError(t, err)
+
+			err = runner.Check(context.Background())
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRunner_Check_tcp(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer func() { _ = conn.Close() }()
+
+				buf := make([]byte, 4)
+				if _, err := conn.Read(buf); err != nil {
+					return
+				}
+
+				_, _ = conn.Write([]byte("PONG\r\n"))
+			}()
+		}
+	}()
+
+	testCases := []struct {
+		desc      string
+		expect    string
+		expectErr bool
+	}{
+		{
+			desc:   "expected data",
+			expect: "PONG",
+		},
+		{
+			desc:      "unexpected data",
+			expect:    "PANG",
+			expectErr: true,
+		},
+		{
+			desc:      "connection closed before the expected data",
+			expect:    "PONG\r\nPONG",
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conf := static.Probe{TCP: &static.TCPProbe{Send: "PING", Expect: test.expect}}
+			conf.SetDefaults()
+
+			runner, err := NewRunner(nil, "foo", conf, static.EntryPoint{Address: listener.Addr().String()})
+			require.NoError(t, err)
+
+			err = runner.Check(context.Background())
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRunner_Check_tcp_FIPS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	// The server only accepts a cipher suite which is not FIPS-approved.
+	server.TLS = &tls.Config{
+		MaxVersion:   tls.VersionTLS12,
+		CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305},
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	testCases := []struct {
+		desc      string
+		fips      bool
+		expectErr bool
+	}{
+		{
+			desc: "without FIPS",
+		},
+		{
+			desc:      "with FIPS",
+			fips:      true,
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conf := static.Probe{TCP: &static.TCPProbe{ServerName: "foo.localhost"}}
+			conf.SetDefaults()
+
+			runner, err := NewRunner(nil, "foo", conf, static.EntryPoint{Address: server.Listener.Addr().String()})
+			require.NoError(t, err)
+
+			runner.SetFIPS(test.fips)
+
+			err = runner.Check(context.Background())
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNewRunner_udp(t *testing.T) {
+	_, err := NewRunner(nil, "foo", static.Probe{TCP: &static.TCPProbe{}}, static.EntryPoint{Address: ":53/udp"})
+	assert.Error(t, err)
+}
+
+func Test_target(t *testing.T) {
+	testCases := []struct {
+		address         string
+		expectedNetwork string
+		expectedAddress string
+	}{
+		{
+			address:         ":80",
+			expectedNetwork: "tcp",
+			expectedAddress: "127.0.0.1:80",
+		},
+		{
+			address:         "0.0.0.0:80/tcp",
+			expectedNetwork: "tcp",
+			expectedAddress: "127.0.0.1:80",
+		},
+		{
+			address:         "[::]:80",
+			expectedNetwork: "tcp",
+			expectedAddress: "[::1]:80",
+		},
+		{
+			address:         "10.0.0.1:80",
+			expectedNetwork: "tcp",
+			expectedAddress: "10.0.0.1:80",
+		},
+		{
+			address:         "unix:///run/traefik.sock",
+			expectedNetwork: "unix",
+			expectedAddress: "/run/traefik.sock",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.address, func(t *testing.T) {
+			t.Parallel()
+
+			network, address, err := target(static.EntryPoint{Address: test.address})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedNetwork, network)
+			assert.Equal(t, test.expectedAddress, address)
+		})
+	}
+}
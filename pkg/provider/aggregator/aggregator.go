@@ -7,6 +7,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/provider/file"
 	"github.com/traefik/traefik/v2/pkg/provider/traefik"
@@ -28,16 +29,21 @@ type throttled interface {
 // maybeThrottledProvide returns the Provide method of the given provider,
 // potentially augmented with some throttling depending on whether and how the
 // provider implements the throttled interface.
-func maybeThrottledProvide(prd provider.Provider, defaultDuration time.Duration) func(chan<- dynamic.Message, *safe.Pool) error {
+// The configuration updates of the providers which do not implement the throttled interface
+// are also debounced, if a debouncer is given.
+func maybeThrottledProvide(prd provider.Provider, defaultDuration time.Duration, debounce *debouncer) func(chan<- dynamic.Message, *safe.Pool) error {
 	providerThrottleDuration := defaultDuration
+	provide := prd.Provide
 	if throttled, ok := prd.(throttled); ok {
 		// per-provider throttling
 		providerThrottleDuration = throttled.ThrottleDuration()
+	} else if debounce != nil {
+		provide = debounce.wrap(provide)
 	}
 
 	if providerThrottleDuration == 0 {
 		// throttling disabled
-		return prd.Provide
+		return provide
 	}
 
 	return func(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
@@ -54,7 +60,7 @@ func maybeThrottledProvide(prd provider.Provider, defaultDuration time.Duration)
 			}
 		})
 
-		return prd.Provide(rc.in(), pool)
+		return provide(rc.in(), pool)
 	}
 }
 
@@ -64,14 +70,23 @@ type ProviderAggregator struct {
 	fileProvider              provider.Provider
 	providers                 []provider.Provider
 	providersThrottleDuration time.Duration
+	debounce                  *debouncer
 }
 
 // NewProviderAggregator returns an aggregate of all the providers configured in the static configuration.
-func NewProviderAggregator(conf static.Providers) ProviderAggregator {
+func NewProviderAggregator(conf static.Providers, metricsRegistry metrics.Registry) ProviderAggregator {
 	p := ProviderAggregator{
 		providersThrottleDuration: time.Duration(conf.ProvidersThrottleDuration),
 	}
 
+	if conf.Debounce != nil {
+		p.debounce = &debouncer{
+			delay:          time.Duration(conf.Debounce.Delay),
+			maxStaleness:   time.Duration(conf.Debounce.MaxStaleness),
+			updatesCounter: metricsRegistry.ProviderConfigUpdatesCounter(),
+		}
+	}
+
 	if conf.File != nil {
 		p.quietAddProvider(conf.File)
 	}
@@ -207,7 +222,7 @@ func (p ProviderAggregator) launchProvider(configurationChan chan<- dynamic.Mess
 	log.WithoutContext().Infof("Starting provider %T", prd)
 	log.WithoutContext().Debugf("%T provider configuration: %s", prd, jsonConf)
 
-	if err := maybeThrottledProvide(prd, p.providersThrottleDuration)(configurationChan, pool); err != nil {
+	if err := maybeThrottledProvide(prd, p.providersThrottleDuration, p.debounce)(configurationChan, pool); err != nil {
 		log.WithoutContext().Errorf("Cannot start the provider %T: %v", prd, err)
 		return
 	}
@@ -0,0 +1,104 @@
+package aggregator
+
+import (
+	"context"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/safe"
+)
+
+// debouncer coalesces the bursts of configuration updates of a provider into one,
+// so that, e.g., the endpoints churn of a Kubernetes cluster does not trigger a configuration reload for each update.
+// An update is forwarded once the provider has not sent any new one for delay,
+// or once it has been delayed for maxStaleness, while new updates keep coming.
+type debouncer struct {
+	delay          time.Duration
+	maxStaleness   time.Duration
+	updatesCounter gokitmetrics.Counter
+}
+
+// wrap returns the given Provide method, with its configuration updates debounced.
+func (d debouncer) wrap(provide func(chan<- dynamic.Message, *safe.Pool) error) func(chan<- dynamic.Message, *safe.Pool) error {
+	return func(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
+		updates := make(chan dynamic.Message)
+		pool.GoCtx(func(ctx context.Context) {
+			d.run(ctx, updates, configurationChan)
+		})
+
+		return provide(updates, pool)
+	}
+}
+
+func (d debouncer) run(ctx context.Context, updates <-chan dynamic.Message, configurationChan chan<- dynamic.Message) {
+	var pending *dynamic.Message
+
+	quiet := time.NewTimer(d.delay)
+	stopTimer(quiet)
+	stale := time.NewTimer(d.maxStaleness)
+	stopTimer(stale)
+
+	flush := func() bool {
+		stopTimer(quiet)
+		stopTimer(stale)
+
+		msg := *pending
+		pending = nil
+
+		select {
+		case <-ctx.Done():
+			return false
+		case configurationChan <- msg:
+			d.updatesCounter.With("provider", msg.ProviderName, "result", "applied").Add(1)
+			return true
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case msg := <-updates:
+			// The pending update of another provider, if any, is not superseded by this one.
+			if pending != nil && pending.ProviderName != msg.ProviderName && !flush() {
+				return
+			}
+
+			if pending != nil {
+				log.WithoutContext().WithField(log.ProviderName, msg.ProviderName).Debug("Coalescing the configuration update with the pending one.")
+				d.updatesCounter.With("provider", pending.ProviderName, "result", "coalesced").Add(1)
+			} else {
+				stale.Reset(d.maxStaleness)
+			}
+
+			pending = &msg
+
+			stopTimer(quiet)
+			quiet.Reset(d.delay)
+
+		case <-quiet.C:
+			if !flush() {
+				return
+			}
+
+		case <-stale.C:
+			if !flush() {
+				return
+			}
+		}
+	}
+}
+
+// stopTimer stops the given timer, and drains its channel if it already fired,
+// so that it can be reset.
+func stopTimer(timer *time.Timer) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package aggregator
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/safe"
+)
+
+func TestDebouncer(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		delay             time.Duration
+		maxStaleness      time.Duration
+		interval          time.Duration
+		updates           int
+		expected          []string
+		expectedApplied   float64
+		expectedCoalesced float64
+	}{
+		{
+			desc:              "burst coalesced into the last update",
+			delay:             50 * time.Millisecond,
+			maxStaleness:      time.Second,
+			updates:           5,
+			expected:          []string{"update-4"},
+			expectedApplied:   1,
+			expectedCoalesced: 4,
+		},
+		{
+			desc:              "updates delayed for max staleness at most",
+			delay:             100 * time.Millisecond,
+			maxStaleness:      175 * time.Millisecond,
+			interval:          50 * time.Millisecond,
+			updates:           10,
+			expectedApplied:   3,
+			expectedCoalesced: 7,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			counter := &updatesCounter{mu: &sync.Mutex{}, values: map[string]float64{}}
+			d := debouncer{delay: test.delay, maxStaleness: test.maxStaleness, updatesCounter: counter}
+
+			pool := safe.NewPool(context.Background())
+			t.Cleanup(pool.Stop)
+
+			configurationChan := make(chan dynamic.Message, test.updates)
+			err := d.wrap(func(updates chan<- dynamic.Message, _ *safe.Pool) error {
+				for i := 0; i < test.updates; i++ {
+					updates <- dynamic.Message{ProviderName: "mock", Configuration: &dynamic.Configuration{
+						HTTP: &dynamic.HTTPConfiguration{Routers: map[string]*dynamic.Router{"update-" + string(rune('0'+i)): {}}},
+					}}
+					time.Sleep(test.interval)
+				}
+				return nil
+			})(configurationChan, pool)
+			require.NoError(t, err)
+
+			time.Sleep(test.delay + 100*time.Millisecond)
+
+			var received []string
+			for len(configurationChan) > 0 {
+				msg := <-configurationChan
+				for name := range msg.Configuration.HTTP.Routers {
+					received = append(received, name)
+				}
+			}
+
+			if test.expected != nil {
+				assert.Equal(t, test.expected, received)
+			}
+			assert.Len(t, received, int(test.expectedApplied))
+
+			counter.mu.Lock()
+			defer counter.mu.Unlock()
+
+			assert.Equal(t, test.expectedApplied, counter.values["mock/applied"])
+			assert.Equal(t, test.expectedCoalesced, counter.values["mock/coalesced"])
+		})
+	}
+}
+
+func TestDebouncer_otherProvider(t *testing.T) {
+	counter := &updatesCounter{mu: &sync.Mutex{}, values: map[string]float64{}}
+	d := debouncer{delay: 50 * time.Millisecond, maxStaleness: time.Second, updatesCounter: counter}
+
+	pool := safe.NewPool(context.Background())
+	t.Cleanup(pool.Stop)
+
+	configurationChan := make(chan dynamic.Message, 2)
+	err := d.wrap(func(updates chan<- dynamic.Message, _ *safe.Pool) error {
+		updates <- dynamic.Message{ProviderName: "foo", Configuration: &dynamic.Configuration{}}
+		updates <- dynamic.Message{ProviderName: "bar", Configuration: &dynamic.Configuration{}}
+		return nil
+	})(configurationChan, pool)
+	require.NoError(t, err)
+
+	// The pending update of foo is applied as soon as bar sends its own.
+	msg := <-configurationChan
+	assert.Equal(t, "foo", msg.ProviderName)
+
+	msg = <-configurationChan
+	assert.Equal(t, "bar", msg.ProviderName)
+
+	assert.Eventually(t, func() bool {
+		counter.mu.Lock()
+		defer counter.mu.Unlock()
+
+		return counter.values["foo/applied"] == 1 && counter.values["bar/applied"] == 1 && len(counter.values) == 2
+	}, time.Second, 10*time.Millisecond)
+}
+
+// updatesCounter records the counts by provider and result.
+type updatesCounter struct {
+	mu     *sync.Mutex
+	values map[string]float64
+	labels []string
+}
+
+func (c *updatesCounter) With(labelValues ...string) metrics.Counter {
+	var labels []string
+	for i := 1; i < len(labelValues); i += 2 {
+		labels = append(labels, labelValues[i])
+	}
+
+	return &updatesCounter{mu: c.mu, values: c.values, labels: labels}
+}
+
+func (c *updatesCounter) Add(delta float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values[strings.Join(c.labels, "/")] += delta
+}
@@ -34,6 +34,7 @@ type itemData struct {
 	ID         string
 	Node       string
 	Datacenter string
+	Zone       string
 	Name       string
 	Namespace  string
 	Address    string
@@ -335,6 +336,7 @@ func (p *Provider) getConsulServicesData(ctx context.Context) ([]itemData, error
 				ID:         consulService.Service.ID,
 				Node:       consulService.Node.Node,
 				Datacenter: consulService.Node.Datacenter,
+				Zone:       serviceZone(consulService.Service),
 				Namespace:  namespace,
 				Name:       name,
 				Address:    address,
@@ -358,6 +360,15 @@ func (p *Provider) getConsulServicesData(ctx context.Context) ([]itemData, error
 	return data, nil
 }
 
+// serviceZone returns the zone of the given service, defined by its locality.
+func serviceZone(service *api.AgentService) string {
+	if service.Locality == nil {
+		return ""
+	}
+
+	return service.Locality.Zone
+}
+
 func (p *Provider) fetchService(ctx context.Context, name string, connectEnabled bool) ([]*api.ServiceEntry, map[string]string, error) {
 	var tagFilter string
 	if !p.ExposedByDefault {
@@ -95,6 +95,12 @@ func taskSlot(slot int) func(*swarm.Task) {
 	}
 }
 
+func taskNodeID(nodeID string) func(*swarm.Task) {
+	return func(task *swarm.Task) {
+		task.NodeID = nodeID
+	}
+}
+
 func taskNetworkAttachment(id, name, driver string, addresses []string) func(*swarm.Task) {
 	return func(task *swarm.Task) {
 		task.NetworksAttachments = append(task.NetworksAttachments, swarm.NetworkAttachment{
@@ -272,6 +272,10 @@ func (p *Provider) addServer(ctx context.Context, container dockerData, loadBala
 	loadBalancer.Servers[0].URL = fmt.Sprintf("%s://%s", loadBalancer.Servers[0].Scheme, net.JoinHostPort(ip, port))
 	loadBalancer.Servers[0].Scheme = ""
 
+	if loadBalancer.Servers[0].Zone == "" {
+		loadBalancer.Servers[0].Zone = container.Zone
+	}
+
 	return nil
 }
 
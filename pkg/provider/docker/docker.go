@@ -39,6 +39,9 @@ const (
 
 	// SwarmAPIVersion is a constant holding the version of the Provider API traefik will use.
 	SwarmAPIVersion = "1.24"
+
+	// zoneNodeLabel is the label of the swarm nodes holding the zone of their tasks.
+	zoneNodeLabel = "zone"
 )
 
 // DefaultTemplateRule The default template for the default rule.
@@ -93,6 +96,7 @@ type dockerData struct {
 	NetworkSettings networkSettings
 	Health          string
 	Node            *dockertypes.ContainerNode
+	Zone            string
 	ExtraConf       configuration
 }
 
@@ -460,6 +464,11 @@ func (p *Provider) listServices(ctx context.Context, dockerClient client.APIClie
 		networkMap[network.ID] = &networkToAdd
 	}
 
+	nodeZones, nodeErr := listNodeZones(ctx, dockerClient)
+	if nodeErr != nil {
+		logger.Debugf("Failed to list the nodes, the tasks have no zone: %v", nodeErr)
+	}
+
 	var dockerDataList []dockerData
 	var dockerDataListTasks []dockerData
 
@@ -476,7 +485,7 @@ func (p *Provider) listServices(ctx context.Context, dockerClient client.APIClie
 			}
 		} else {
 			isGlobalSvc := service.Spec.Mode.Global != nil
-			dockerDataListTasks, err = listTasks(ctx, dockerClient, service.ID, dData, networkMap, isGlobalSvc)
+			dockerDataListTasks, err = listTasks(ctx, dockerClient, service.ID, dData, networkMap, nodeZones, isGlobalSvc)
 			if err != nil {
 				logger.Warn(err)
 			} else {
@@ -534,8 +543,25 @@ func (p *Provider) parseService(ctx context.Context, service swarmtypes.Service,
 	return dData, nil
 }
 
+// listNodeZones returns the zones of the swarm nodes, defined by their zone label, indexed by node ID.
+func listNodeZones(ctx context.Context, dockerClient client.APIClient) (map[string]string, error) {
+	nodes, err := dockerClient.NodeList(ctx, dockertypes.NodeListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	nodeZones := make(map[string]string)
+	for _, node := range nodes {
+		if zone := node.Spec.Labels[zoneNodeLabel]; zone != "" {
+			nodeZones[node.ID] = zone
+		}
+	}
+
+	return nodeZones, nil
+}
+
 func listTasks(ctx context.Context, dockerClient client.APIClient, serviceID string,
-	serviceDockerData dockerData, networkMap map[string]*dockertypes.NetworkResource, isGlobalSvc bool,
+	serviceDockerData dockerData, networkMap map[string]*dockertypes.NetworkResource, nodeZones map[string]string, isGlobalSvc bool,
 ) ([]dockerData, error) {
 	serviceIDFilter := filters.NewArgs()
 	serviceIDFilter.Add("service", serviceID)
@@ -552,6 +578,7 @@ func listTasks(ctx context.Context, dockerClient client.APIClient, serviceID str
 			continue
 		}
 		dData := parseTasks(ctx, task, serviceDockerData, networkMap, isGlobalSvc)
+		dData.Zone = nodeZones[task.NodeID]
 		if len(dData.NetworkSettings.Networks) > 0 {
 			dockerDataList = append(dockerDataList, dData)
 		}
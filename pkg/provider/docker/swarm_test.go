@@ -34,7 +34,9 @@ func TestListTasks(t *testing.T) {
 		service       swarm.Service
 		tasks         []swarm.Task
 		isGlobalSVC   bool
+		nodeZones     map[string]string
 		expectedTasks []string
+		expectedZones []string
 		networks      map[string]*dockertypes.NetworkResource
 	}{
 		{
@@ -70,6 +72,37 @@ func TestListTasks(t *testing.T) {
 				"container.1",
 				"container.4",
 			},
+			expectedZones: []string{"", ""},
+			networks: map[string]*dockertypes.NetworkResource{
+				"1": {
+					Name: "foo",
+				},
+			},
+		},
+		{
+			service: swarmService(serviceName("container")),
+			tasks: []swarm.Task{
+				swarmTask("id1",
+					taskSlot(1),
+					taskNodeID("node1"),
+					taskNetworkAttachment("1", "network1", "overlay", []string{"127.0.0.1"}),
+					taskStatus(taskState(swarm.TaskStateRunning)),
+				),
+				swarmTask("id2",
+					taskSlot(2),
+					taskNodeID("node2"),
+					taskNetworkAttachment("1", "network1", "overlay", []string{"127.0.0.2"}),
+					taskStatus(taskState(swarm.TaskStateRunning)),
+				),
+			},
+			nodeZones: map[string]string{
+				"node1": "eu-west-1a",
+			},
+			expectedTasks: []string{
+				"container.1",
+				"container.2",
+			},
+			expectedZones: []string{"eu-west-1a", ""},
 			networks: map[string]*dockertypes.NetworkResource{
 				"1": {
 					Name: "foo",
@@ -88,7 +121,7 @@ func TestListTasks(t *testing.T) {
 			require.NoError(t, err)
 
 			dockerClient := &fakeTasksClient{tasks: test.tasks}
-			taskDockerData, _ := listTasks(context.Background(), dockerClient, test.service.ID, dockerData, test.networks, test.isGlobalSVC)
+			taskDockerData, _ := listTasks(context.Background(), dockerClient, test.service.ID, dockerData, test.networks, test.nodeZones, test.isGlobalSVC)
 
 			if len(test.expectedTasks) != len(taskDockerData) {
 				t.Errorf("expected tasks %v, got %v", spew.Sdump(test.expectedTasks), spew.Sdump(taskDockerData))
@@ -99,6 +132,10 @@ func TestListTasks(t *testing.T) {
 					t.Errorf("expect task id %v, got %v", taskID, taskDockerData[i].Name)
 				}
 			}
+
+			for i, zone := range test.expectedZones {
+				assert.Equal(t, zone, taskDockerData[i].Zone)
+			}
 		})
 	}
 }
@@ -109,6 +146,7 @@ type fakeServicesClient struct {
 	networks      []dockertypes.NetworkResource
 	services      []swarm.Service
 	tasks         []swarm.Task
+	nodes         []swarm.Node
 	err           error
 }
 
@@ -128,6 +166,10 @@ func (c *fakeServicesClient) TaskList(ctx context.Context, options dockertypes.T
 	return c.tasks, c.err
 }
 
+func (c *fakeServicesClient) NodeList(ctx context.Context, options dockertypes.NodeListOptions) ([]swarm.Node, error) {
+	return c.nodes, c.err
+}
+
 func TestListServices(t *testing.T) {
 	testCases := []struct {
 		desc             string
@@ -293,6 +335,20 @@ func TestListServices(t *testing.T) {
 	}
 }
 
+func TestListNodeZones(t *testing.T) {
+	dockerClient := &fakeServicesClient{
+		nodes: []swarm.Node{
+			{ID: "node1", Spec: swarm.NodeSpec{Annotations: swarm.Annotations{Labels: map[string]string{"zone": "eu-west-1a"}}}},
+			{ID: "node2", Spec: swarm.NodeSpec{Annotations: swarm.Annotations{Labels: map[string]string{"foo": "bar"}}}},
+		},
+	}
+
+	nodeZones, err := listNodeZones(context.Background(), dockerClient)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"node1": "eu-west-1a"}, nodeZones)
+}
+
 func TestSwarmTaskParsing(t *testing.T) {
 	testCases := []struct {
 		service     swarm.Service
@@ -54,6 +54,12 @@ func mHealthStatus(status string) func(*machine) {
 	}
 }
 
+func mAvailabilityZone(zone string) func(*machine) {
+	return func(m *machine) {
+		m.availabilityZone = zone
+	}
+}
+
 func mPorts(opts ...func(*portMapping)) func(*machine) {
 	return func(m *machine) {
 		for _, opt := range opts {
@@ -275,6 +275,10 @@ func (p *Provider) addServer(instance ecsInstance, loadBalancer *dynamic.Servers
 	loadBalancer.Servers[0].URL = fmt.Sprintf("%s://%s", loadBalancer.Servers[0].Scheme, net.JoinHostPort(ip, port))
 	loadBalancer.Servers[0].Scheme = ""
 
+	if loadBalancer.Servers[0].Zone == "" {
+		loadBalancer.Servers[0].Zone = instance.machine.availabilityZone
+	}
+
 	return nil
 }
 
@@ -57,10 +57,11 @@ type portMapping struct {
 }
 
 type machine struct {
-	state        string
-	privateIP    string
-	ports        []portMapping
-	healthStatus string
+	state            string
+	privateIP        string
+	ports            []portMapping
+	healthStatus     string
+	availabilityZone string
 }
 
 type awsClient struct {
@@ -336,10 +337,11 @@ func (p *Provider) listInstances(ctx context.Context, client *awsClient) ([]ecsI
 						}
 					}
 					mach = &machine{
-						privateIP:    aws.StringValue(container.NetworkInterfaces[0].PrivateIpv4Address),
-						ports:        ports,
-						state:        aws.StringValue(task.LastStatus),
-						healthStatus: aws.StringValue(task.HealthStatus),
+						privateIP:        aws.StringValue(container.NetworkInterfaces[0].PrivateIpv4Address),
+						ports:            ports,
+						state:            aws.StringValue(task.LastStatus),
+						healthStatus:     aws.StringValue(task.HealthStatus),
+						availabilityZone: aws.StringValue(task.AvailabilityZone),
 					}
 				} else {
 					miContainerInstance := miInstances[aws.StringValue(task.ContainerInstanceArn)]
@@ -367,9 +369,10 @@ func (p *Provider) listInstances(ctx context.Context, client *awsClient) ([]ecsI
 					}
 
 					mach = &machine{
-						privateIP: privateIPAddress,
-						ports:     ports,
-						state:     stateName,
+						privateIP:        privateIPAddress,
+						ports:            ports,
+						state:            stateName,
+						availabilityZone: aws.StringValue(task.AvailabilityZone),
 					}
 				}
 
@@ -44,6 +44,7 @@ type Client interface {
 	GetService(namespace, name string) (*corev1.Service, bool, error)
 	GetSecret(namespace, name string) (*corev1.Secret, bool, error)
 	GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error)
+	GetNode(name string) (*corev1.Node, bool, error)
 }
 
 // TODO: add tests for the clientWrapper (and its methods) itself.
@@ -54,8 +55,10 @@ type clientWrapper struct {
 	factoriesCrd    map[string]traefikinformers.SharedInformerFactory
 	factoriesKube   map[string]kinformers.SharedInformerFactory
 	factoriesSecret map[string]kinformers.SharedInformerFactory
+	factoryNodes    kinformers.SharedInformerFactory
 
 	labelSelector string
+	watchNodes    bool
 
 	isNamespaceAll    bool
 	watchedNamespaces []string
@@ -226,12 +229,31 @@ func (c *clientWrapper) WatchAll(namespaces []string, stopCh <-chan struct{}) (<
 		c.factoriesSecret[ns] = factorySecret
 	}
 
+	if c.watchNodes {
+		// The nodes are not namespaced, so they are watched by a single informer.
+		c.factoryNodes = kinformers.NewSharedInformerFactoryWithOptions(c.csKube, resyncPeriod)
+		_, err := c.factoryNodes.Core().V1().Nodes().Informer().AddEventHandler(eventHandler)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	for _, ns := range namespaces {
 		c.factoriesCrd[ns].Start(stopCh)
 		c.factoriesKube[ns].Start(stopCh)
 		c.factoriesSecret[ns].Start(stopCh)
 	}
 
+	if c.factoryNodes != nil {
+		c.factoryNodes.Start(stopCh)
+
+		for t, ok := range c.factoryNodes.WaitForCacheSync(stopCh) {
+			if !ok {
+				return nil, fmt.Errorf("timed out waiting for controller caches to sync %s", t.String())
+			}
+		}
+	}
+
 	for _, ns := range namespaces {
 		for t, ok := range c.factoriesCrd[ns].WaitForCacheSync(stopCh) {
 			if !ok {
@@ -422,6 +444,17 @@ func (c *clientWrapper) GetEndpoints(namespace, name string) (*corev1.Endpoints,
 	return endpoint, exist, err
 }
 
+// GetNode returns the named node, if the nodes are watched.
+func (c *clientWrapper) GetNode(name string) (*corev1.Node, bool, error) {
+	if c.factoryNodes == nil {
+		return nil, false, nil
+	}
+
+	node, err := c.factoryNodes.Core().V1().Nodes().Lister().Get(name)
+	exist, err := translateNotFoundError(err)
+	return node, exist, err
+}
+
 // GetSecret returns the named secret from the given namespace.
 func (c *clientWrapper) GetSecret(namespace, name string) (*corev1.Secret, bool, error) {
 	if !c.isWatchedNamespace(namespace) {
@@ -57,6 +57,7 @@ type Provider struct {
 	IngressClass              string          `description:"Value of kubernetes.io/ingress.class annotation to watch for." json:"ingressClass,omitempty" toml:"ingressClass,omitempty" yaml:"ingressClass,omitempty" export:"true"`
 	ThrottleDuration          ptypes.Duration `description:"Ingress refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty" export:"true"`
 	AllowEmptyServices        bool            `description:"Allow the creation of services without endpoints." json:"allowEmptyServices,omitempty" toml:"allowEmptyServices,omitempty" yaml:"allowEmptyServices,omitempty" export:"true"`
+	NodeZones                 bool            `description:"Set the zone of the servers from the topology.kubernetes.io/zone label of their nodes." json:"nodeZones,omitempty" toml:"nodeZones,omitempty" yaml:"nodeZones,omitempty" export:"true"`
 
 	lastConfiguration safe.Safe
 
@@ -108,6 +109,7 @@ func (p *Provider) newK8sClient(ctx context.Context) (*clientWrapper, error) {
 	}
 
 	client.labelSelector = p.LabelSelector
+	client.watchNodes = p.NodeZones
 	return client, nil
 }
 
@@ -311,8 +313,13 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 		id := provider.Normalize(makeID(middlewareTCP.Namespace, middlewareTCP.Name))
 
 		conf.TCP.Middlewares[id] = &dynamic.TCPMiddleware{
-			InFlightConn: middlewareTCP.Spec.InFlightConn,
-			IPWhiteList:  middlewareTCP.Spec.IPWhiteList,
+			BandwidthLimit: middlewareTCP.Spec.BandwidthLimit,
+			ByteQuota:      middlewareTCP.Spec.ByteQuota,
+			Framing:        middlewareTCP.Spec.Framing,
+			GeoIP:          middlewareTCP.Spec.GeoIP,
+			InFlightConn:   middlewareTCP.Spec.InFlightConn,
+			IPWhiteList:    middlewareTCP.Spec.IPWhiteList,
+			RateLimit:      middlewareTCP.Spec.RateLimit,
 		}
 	}
 
@@ -321,6 +328,7 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 		allowCrossNamespace:       p.AllowCrossNamespace,
 		allowExternalNameServices: p.AllowExternalNameServices,
 		allowEmptyServices:        p.AllowEmptyServices,
+		nodeZones:                 p.NodeZones,
 	}
 
 	for _, service := range client.GetTraefikServices() {
@@ -625,6 +633,7 @@ func (p *Provider) createErrorPageMiddleware(client Client, namespace string, er
 		allowCrossNamespace:       p.AllowCrossNamespace,
 		allowExternalNameServices: p.AllowExternalNameServices,
 		allowEmptyServices:        p.AllowEmptyServices,
+		nodeZones:                 p.NodeZones,
 	}
 
 	balancerServerHTTP, err := cb.buildServersLB(namespace, errorPage.Service.LoadBalancerSpec)
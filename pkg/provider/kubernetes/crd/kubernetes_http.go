@@ -55,6 +55,7 @@ func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Cli
 			allowCrossNamespace:       p.AllowCrossNamespace,
 			allowExternalNameServices: p.AllowExternalNameServices,
 			allowEmptyServices:        p.AllowEmptyServices,
+			nodeZones:                 p.NodeZones,
 		}
 
 		for _, route := range ingressRoute.Spec.Routes {
@@ -201,6 +202,7 @@ type configBuilder struct {
 	allowCrossNamespace       bool
 	allowExternalNameServices bool
 	allowEmptyServices        bool
+	nodeZones                 bool
 }
 
 // buildTraefikService creates the configuration for the traefik service defined in tService,
@@ -436,7 +438,8 @@ func (c configBuilder) loadServers(parentNamespace string, svc traefikv1alpha1.L
 			hostPort := net.JoinHostPort(addr.IP, strconv.Itoa(int(port)))
 
 			servers = append(servers, dynamic.Server{
-				URL: fmt.Sprintf("%s://%s", protocol, hostPort),
+				URL:  fmt.Sprintf("%s://%s", protocol, hostPort),
+				Zone: c.nodeZone(addr.NodeName),
 			})
 		}
 	}
@@ -444,6 +447,21 @@ func (c configBuilder) loadServers(parentNamespace string, svc traefikv1alpha1.L
 	return servers, nil
 }
 
+// nodeZone returns the zone of the given node, read from its topology.kubernetes.io/zone label,
+// or an empty zone if the zones of the nodes are not used.
+func (c configBuilder) nodeZone(nodeName *string) string {
+	if !c.nodeZones || nodeName == nil {
+		return ""
+	}
+
+	node, exists, err := c.client.GetNode(*nodeName)
+	if err != nil || !exists {
+		return ""
+	}
+
+	return node.Labels[corev1.LabelTopologyZone]
+}
+
 // nameAndService returns the name that should be used for the svc service in the generated config.
 // In addition, if the service is a Kubernetes one,
 // it generates and returns the configuration part for such a service,
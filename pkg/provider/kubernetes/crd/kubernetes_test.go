@@ -6494,6 +6494,75 @@ func TestNativeLB(t *testing.T) {
 	}
 }
 
+func TestNodeZones(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		nodeZones bool
+		expected  []dynamic.Server
+	}{
+		{
+			desc: "Node zones disabled",
+			expected: []dynamic.Server{
+				{URL: "http://10.10.0.1:80"},
+				{URL: "http://10.10.0.2:80"},
+				{URL: "http://10.10.0.3:80"},
+			},
+		},
+		{
+			desc:      "Node zones enabled",
+			nodeZones: true,
+			expected: []dynamic.Server{
+				{URL: "http://10.10.0.1:80", Zone: "eu-west-1a"},
+				{URL: "http://10.10.0.2:80"},
+				{URL: "http://10.10.0.3:80"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			yamlContent, err := os.ReadFile(filepath.FromSlash("./fixtures/with_node_zones.yml"))
+			require.NoError(t, err)
+
+			var k8sObjects []runtime.Object
+			var crdObjects []runtime.Object
+			for _, obj := range k8s.MustParseYaml(yamlContent) {
+				switch o := obj.(type) {
+				case *corev1.Service, *corev1.Endpoints, *corev1.Node:
+					k8sObjects = append(k8sObjects, o)
+				case *traefikv1alpha1.IngressRoute:
+					crdObjects = append(crdObjects, o)
+				}
+			}
+
+			kubeClient := kubefake.NewSimpleClientset(k8sObjects...)
+			crdClient := traefikcrdfake.NewSimpleClientset(crdObjects...)
+
+			client := newClientImpl(kubeClient, crdClient)
+			client.watchNodes = test.nodeZones
+
+			stopCh := make(chan struct{})
+			t.Cleanup(func() { close(stopCh) })
+
+			eventCh, err := client.WatchAll([]string{"default"}, stopCh)
+			require.NoError(t, err)
+
+			// just wait for the first event
+			<-eventCh
+
+			p := Provider{NodeZones: test.nodeZones}
+
+			conf := p.loadConfigurationFromCRD(context.Background(), client)
+			require.Contains(t, conf.HTTP.Services, "default-test-route-6f97418635c7e18853da")
+			assert.Equal(t, test.expected, conf.HTTP.Services["default-test-route-6f97418635c7e18853da"].LoadBalancer.Servers)
+		})
+	}
+}
+
 func TestCreateBasicAuthCredentials(t *testing.T) {
 	var k8sObjects []runtime.Object
 	yamlContent, err := os.ReadFile(filepath.FromSlash("./fixtures/basic_auth_secrets.yml"))
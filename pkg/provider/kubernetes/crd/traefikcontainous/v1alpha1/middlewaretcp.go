@@ -23,10 +23,20 @@ type MiddlewareTCP struct {
 
 // MiddlewareTCPSpec defines the desired state of a MiddlewareTCP.
 type MiddlewareTCPSpec struct {
+	// BandwidthLimit defines the BandwidthLimit middleware configuration.
+	BandwidthLimit *dynamic.TCPBandwidthLimit `json:"bandwidthLimit,omitempty"`
+	// ByteQuota defines the ByteQuota middleware configuration.
+	ByteQuota *dynamic.TCPByteQuota `json:"byteQuota,omitempty"`
+	// Framing defines the Framing middleware configuration.
+	Framing *dynamic.TCPFraming `json:"framing,omitempty"`
+	// GeoIP defines the GeoIP middleware configuration.
+	GeoIP *dynamic.TCPGeoIP `json:"geoIP,omitempty"`
 	// InFlightConn defines the InFlightConn middleware configuration.
 	InFlightConn *dynamic.TCPInFlightConn `json:"inFlightConn,omitempty"`
 	// IPWhiteList defines the IPWhiteList middleware configuration.
 	IPWhiteList *dynamic.TCPIPWhiteList `json:"ipWhiteList,omitempty"`
+	// RateLimit defines the RateLimit middleware configuration.
+	RateLimit *dynamic.TCPRateLimit `json:"rateLimit,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
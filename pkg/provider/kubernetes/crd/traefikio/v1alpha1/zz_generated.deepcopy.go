@@ -852,6 +852,26 @@ func (in *MiddlewareTCPList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MiddlewareTCPSpec) DeepCopyInto(out *MiddlewareTCPSpec) {
 	*out = *in
+	if in.BandwidthLimit != nil {
+		in, out := &in.BandwidthLimit, &out.BandwidthLimit
+		*out = new(dynamic.TCPBandwidthLimit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ByteQuota != nil {
+		in, out := &in.ByteQuota, &out.ByteQuota
+		*out = new(dynamic.TCPByteQuota)
+		**out = **in
+	}
+	if in.Framing != nil {
+		in, out := &in.Framing, &out.Framing
+		*out = new(dynamic.TCPFraming)
+		**out = **in
+	}
+	if in.GeoIP != nil {
+		in, out := &in.GeoIP, &out.GeoIP
+		*out = new(dynamic.TCPGeoIP)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.InFlightConn != nil {
 		in, out := &in.InFlightConn, &out.InFlightConn
 		*out = new(dynamic.TCPInFlightConn)
@@ -862,6 +882,11 @@ func (in *MiddlewareTCPSpec) DeepCopyInto(out *MiddlewareTCPSpec) {
 		*out = new(dynamic.TCPIPWhiteList)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(dynamic.TCPRateLimit)
+		**out = **in
+	}
 	return
 }
 
@@ -57,6 +57,7 @@ type Client interface {
 	GetHTTPRoutes(namespaces []string) ([]*gatev1alpha2.HTTPRoute, error)
 	GetTCPRoutes(namespaces []string) ([]*gatev1alpha2.TCPRoute, error)
 	GetTLSRoutes(namespaces []string) ([]*gatev1alpha2.TLSRoute, error)
+	GetUDPRoutes(namespaces []string) ([]*gatev1alpha2.UDPRoute, error)
 	GetService(namespace, name string) (*corev1.Service, bool, error)
 	GetSecret(namespace, name string) (*corev1.Secret, bool, error)
 	GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error)
@@ -203,6 +204,10 @@ func (c *clientWrapper) WatchAll(namespaces []string, stopCh <-chan struct{}) (<
 		if err != nil {
 			return nil, err
 		}
+		_, err = factoryGateway.Gateway().V1alpha2().UDPRoutes().Informer().AddEventHandler(eventHandler)
+		if err != nil {
+			return nil, err
+		}
 
 		factoryKube := kinformers.NewSharedInformerFactoryWithOptions(c.csKube, resyncPeriod, kinformers.WithNamespace(ns))
 		_, err = factoryKube.Core().V1().Services().Informer().AddEventHandler(eventHandler)
@@ -356,6 +361,29 @@ func (c *clientWrapper) GetTLSRoutes(namespaces []string) ([]*gatev1alpha2.TLSRo
 	return tlsRoutes, nil
 }
 
+func (c *clientWrapper) GetUDPRoutes(namespaces []string) ([]*gatev1alpha2.UDPRoute, error) {
+	var udpRoutes []*gatev1alpha2.UDPRoute
+	for _, namespace := range namespaces {
+		if !c.isWatchedNamespace(namespace) {
+			log.WithoutContext().Warnf("Failed to get UDPRoutes: %q is not within watched namespaces", namespace)
+			continue
+		}
+
+		routes, err := c.factoriesGateway[c.lookupNamespace(namespace)].Gateway().V1alpha2().UDPRoutes().Lister().UDPRoutes(namespace).List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+
+		if len(routes) == 0 {
+			log.WithoutContext().Debugf("No UDPRoutes found in namespace %q", namespace)
+			continue
+		}
+
+		udpRoutes = append(udpRoutes, routes...)
+	}
+	return udpRoutes, nil
+}
+
 func (c *clientWrapper) GetGateways() []*gatev1alpha2.Gateway {
 	var result []*gatev1alpha2.Gateway
 
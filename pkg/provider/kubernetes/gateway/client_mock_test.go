@@ -38,6 +38,7 @@ type clientMock struct {
 	httpRoutes     []*gatev1alpha2.HTTPRoute
 	tcpRoutes      []*gatev1alpha2.TCPRoute
 	tlsRoutes      []*gatev1alpha2.TLSRoute
+	udpRoutes      []*gatev1alpha2.UDPRoute
 
 	watchChan chan interface{}
 }
@@ -72,6 +73,8 @@ func newClientMock(paths ...string) clientMock {
 				c.tcpRoutes = append(c.tcpRoutes, o)
 			case *gatev1alpha2.TLSRoute:
 				c.tlsRoutes = append(c.tlsRoutes, o)
+			case *gatev1alpha2.UDPRoute:
+				c.udpRoutes = append(c.udpRoutes, o)
 			default:
 				panic(fmt.Sprintf("Unknown runtime object %+v %T", o, o))
 			}
@@ -184,6 +187,18 @@ func (c clientMock) GetTLSRoutes(namespaces []string) ([]*gatev1alpha2.TLSRoute,
 	return tlsRoutes, nil
 }
 
+func (c clientMock) GetUDPRoutes(namespaces []string) ([]*gatev1alpha2.UDPRoute, error) {
+	var udpRoutes []*gatev1alpha2.UDPRoute
+	for _, namespace := range namespaces {
+		for _, udpRoute := range c.udpRoutes {
+			if inNamespace(udpRoute.ObjectMeta, namespace) {
+				udpRoutes = append(udpRoutes, udpRoute)
+			}
+		}
+	}
+	return udpRoutes, nil
+}
+
 func (c clientMock) GetService(namespace, name string) (*corev1.Service, bool, error) {
 	if c.apiServiceError != nil {
 		return nil, false, c.apiServiceError
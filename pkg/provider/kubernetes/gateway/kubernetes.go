@@ -41,6 +41,14 @@ const (
 	kindHTTPRoute      = "HTTPRoute"
 	kindTCPRoute       = "TCPRoute"
 	kindTLSRoute       = "TLSRoute"
+	kindUDPRoute       = "UDPRoute"
+
+	// annotationMiddlewares references the Traefik TCP middlewares attached to the routers of a TCPRoute or TLSRoute,
+	// as a comma-separated list of MiddlewareTCP names, in the namespace of the route.
+	annotationMiddlewares = "traefik.io/middlewares"
+
+	// crdProviderName is the name of the provider of the MiddlewareTCP resources.
+	crdProviderName = "kubernetescrd"
 )
 
 // Provider holds configurations of the provider.
@@ -77,6 +85,8 @@ func (p *Provider) applyRouterTransform(ctx context.Context, rt *dynamic.Router,
 type Entrypoint struct {
 	Address        string
 	HasHTTPTLSConf bool
+	// Protocol is the transport protocol of the entry point, udp or tcp, which is the default.
+	Protocol string
 }
 
 func (p *Provider) newK8sClient(ctx context.Context) (*clientWrapper, error) {
@@ -389,13 +399,13 @@ func (p *Provider) fillGatewayConf(ctx context.Context, client Client, gateway *
 			continue
 		}
 
-		if (listener.Protocol == gatev1alpha2.HTTPProtocolType || listener.Protocol == gatev1alpha2.TCPProtocolType) && listener.TLS != nil {
+		if (listener.Protocol == gatev1alpha2.HTTPProtocolType || listener.Protocol == gatev1alpha2.TCPProtocolType || listener.Protocol == gatev1alpha2.UDPProtocolType) && listener.TLS != nil {
 			listenerStatuses[i].Conditions = append(listenerStatuses[i].Conditions, metav1.Condition{
 				Type:               string(gatev1alpha2.ListenerConditionDetached),
 				Status:             metav1.ConditionTrue,
 				LastTransitionTime: metav1.Now(),
 				Reason:             "InvalidTLSConfiguration", // TODO check the spec if a proper reason is introduced at some point
-				Message:            "TLS configuration must no be defined when using HTTP or TCP protocol",
+				Message:            "TLS configuration must no be defined when using HTTP, TCP or UDP protocol",
 			})
 
 			continue
@@ -518,6 +528,8 @@ func (p *Provider) fillGatewayConf(ctx context.Context, client Client, gateway *
 				listenerStatuses[i].Conditions = append(listenerStatuses[i].Conditions, gatewayTCPRouteToTCPConf(ctx, ep, listener, gateway, client, conf)...)
 			case kindTLSRoute:
 				listenerStatuses[i].Conditions = append(listenerStatuses[i].Conditions, gatewayTLSRouteToTCPConf(ctx, ep, listener, gateway, client, conf)...)
+			case kindUDPRoute:
+				listenerStatuses[i].Conditions = append(listenerStatuses[i].Conditions, gatewayUDPRouteToUDPConf(ctx, ep, listener, gateway, client, conf)...)
 			}
 		}
 	}
@@ -592,6 +604,11 @@ func (p *Provider) entryPointName(port gatev1alpha2.PortNumber, protocol gatev1a
 	portStr := strconv.FormatInt(int64(port), 10)
 
 	for name, entryPoint := range p.EntryPoints {
+		// The UDP listeners are bound to the UDP entryPoints, and the other ones to the TCP entryPoints.
+		if (protocol == gatev1alpha2.UDPProtocolType) != (entryPoint.Protocol == "udp") {
+			continue
+		}
+
 		if strings.HasSuffix(entryPoint.Address, ":"+portStr) {
 			// If the protocol is HTTP the entryPoint must have no TLS conf
 			// Not relevant for gatev1alpha2.TLSProtocolType && gatev1alpha2.TCPProtocolType
@@ -613,6 +630,9 @@ func supportedRouteKinds(protocol gatev1alpha2.ProtocolType) ([]gatev1alpha2.Rou
 	case gatev1alpha2.TCPProtocolType:
 		return []gatev1alpha2.RouteGroupKind{{Kind: kindTCPRoute, Group: &group}}, nil
 
+	case gatev1alpha2.UDPProtocolType:
+		return []gatev1alpha2.RouteGroupKind{{Kind: kindUDPRoute, Group: &group}}, nil
+
 	case gatev1alpha2.HTTPProtocolType, gatev1alpha2.HTTPSProtocolType:
 		return []gatev1alpha2.RouteGroupKind{{Kind: kindHTTPRoute, Group: &group}}, nil
 
@@ -860,6 +880,7 @@ func gatewayTCPRouteToTCPConf(ctx context.Context, ep string, listener gatev1alp
 		router := dynamic.TCPRouter{
 			Rule:        "HostSNI(`*`)", // Gateway listener hostname not available in TCP
 			EntryPoints: []string{ep},
+			Middlewares: routeMiddlewares(route.ObjectMeta),
 		}
 
 		if listener.Protocol == gatev1alpha2.TLSProtocolType && listener.TLS != nil {
@@ -1011,6 +1032,7 @@ func gatewayTLSRouteToTCPConf(ctx context.Context, ep string, listener gatev1alp
 		router := dynamic.TCPRouter{
 			Rule:        rule,
 			EntryPoints: []string{ep},
+			Middlewares: routeMiddlewares(route.ObjectMeta),
 			TLS: &dynamic.RouterTCPTLSConfig{
 				Passthrough: listener.TLS.Mode != nil && *listener.TLS.Mode == gatev1alpha2.TLSModePassthrough,
 			},
@@ -1093,6 +1115,157 @@ func gatewayTLSRouteToTCPConf(ctx context.Context, ep string, listener gatev1alp
 	return conditions
 }
 
+func gatewayUDPRouteToUDPConf(ctx context.Context, ep string, listener gatev1alpha2.Listener, gateway *gatev1alpha2.Gateway, client Client, conf *dynamic.Configuration) []metav1.Condition {
+	if listener.AllowedRoutes == nil {
+		// Should not happen due to validation.
+		return nil
+	}
+
+	namespaces, err := getRouteBindingSelectorNamespace(client, gateway.Namespace, listener.AllowedRoutes.Namespaces)
+	if err != nil {
+		// update "ResolvedRefs" status true with "InvalidRoutesRef" reason
+		return []metav1.Condition{{
+			Type:               string(gatev1alpha2.ListenerConditionResolvedRefs),
+			Status:             metav1.ConditionFalse,
+			LastTransitionTime: metav1.Now(),
+			Reason:             "InvalidRouteNamespacesSelector", // TODO should never happen as the selector is validated by Kubernetes
+			Message:            fmt.Sprintf("Invalid route namespaces selector: %v", err),
+		}}
+	}
+
+	routes, err := client.GetUDPRoutes(namespaces)
+	if err != nil {
+		// update "ResolvedRefs" status true with "InvalidRoutesRef" reason
+		return []metav1.Condition{{
+			Type:               string(gatev1alpha2.ListenerConditionResolvedRefs),
+			Status:             metav1.ConditionFalse,
+			LastTransitionTime: metav1.Now(),
+			Reason:             string(gatev1alpha2.ListenerReasonRefNotPermitted),
+			Message:            fmt.Sprintf("Cannot fetch UDPRoutes: %v", err),
+		}}
+	}
+
+	if len(routes) == 0 {
+		log.FromContext(ctx).Debugf("No UDPRoutes found")
+		return nil
+	}
+
+	var conditions []metav1.Condition
+	for _, route := range routes {
+		if !shouldAttach(gateway, listener, route.Namespace, route.Spec.CommonRouteSpec) {
+			continue
+		}
+
+		if _, ok := route.Annotations[annotationMiddlewares]; ok {
+			log.FromContext(ctx).Warnf("Ignoring the middlewares of UDPRoute %s/%s: the UDP routers do not support middlewares", route.Namespace, route.Name)
+		}
+
+		router := dynamic.UDPRouter{
+			EntryPoints: []string{ep},
+		}
+
+		// Adding the gateway desc and the entryPoint desc prevents overlapping of routers build from the same routes.
+		routerName := route.Name + "-" + gateway.Name + "-" + ep
+		routerKey, err := makeRouterKey("", makeID(route.Namespace, routerName))
+		if err != nil {
+			// update "ResolvedRefs" status true with "DroppedRoutes" reason
+			conditions = append(conditions, metav1.Condition{
+				Type:               string(gatev1alpha2.ListenerConditionResolvedRefs),
+				Status:             metav1.ConditionFalse,
+				LastTransitionTime: metav1.Now(),
+				Reason:             "InvalidRouterKey", // Should never happen
+				Message:            fmt.Sprintf("Skipping UDPRoute %s: cannot make router's key: %v", route.Name, err),
+			})
+
+			// TODO update the RouteStatus condition / deduplicate conditions on listener
+			continue
+		}
+
+		routerKey = provider.Normalize(routerKey)
+
+		var ruleServiceNames []string
+		for i, rule := range route.Spec.Rules {
+			if rule.BackendRefs == nil {
+				// Should not happen due to validation.
+				// https://github.com/kubernetes-sigs/gateway-api/blob/v0.4.0/apis/v1alpha2/udproute_types.go#L76
+				continue
+			}
+
+			wrrService, subServices, err := loadUDPServices(client, route.Namespace, rule.BackendRefs)
+			if err != nil {
+				// update "ResolvedRefs" status true with "DroppedRoutes" reason
+				conditions = append(conditions, metav1.Condition{
+					Type:               string(gatev1alpha2.ListenerConditionResolvedRefs),
+					Status:             metav1.ConditionFalse,
+					LastTransitionTime: metav1.Now(),
+					Reason:             "InvalidBackendRefs", // TODO check the spec if a proper reason is introduced at some point
+					Message:            fmt.Sprintf("Cannot load UDPRoute service %s/%s: %v", route.Namespace, route.Name, err),
+				})
+
+				// TODO update the RouteStatus condition / deduplicate conditions on listener
+				continue
+			}
+
+			for svcName, svc := range subServices {
+				conf.UDP.Services[svcName] = svc
+			}
+
+			serviceName := fmt.Sprintf("%s-wrr-%d", routerKey, i)
+			conf.UDP.Services[serviceName] = wrrService
+
+			ruleServiceNames = append(ruleServiceNames, serviceName)
+		}
+
+		if len(ruleServiceNames) == 0 {
+			continue
+		}
+
+		if len(ruleServiceNames) == 1 {
+			router.Service = ruleServiceNames[0]
+			conf.UDP.Routers[routerKey] = &router
+			continue
+		}
+
+		routeServiceKey := routerKey + "-wrr"
+		routeService := &dynamic.UDPService{Weighted: &dynamic.UDPWeightedRoundRobin{}}
+
+		for _, name := range ruleServiceNames {
+			service := dynamic.UDPWRRService{Name: name}
+			service.SetDefaults()
+
+			routeService.Weighted.Services = append(routeService.Weighted.Services, service)
+		}
+
+		conf.UDP.Services[routeServiceKey] = routeService
+
+		router.Service = routeServiceKey
+		conf.UDP.Routers[routerKey] = &router
+	}
+
+	return conditions
+}
+
+// routeMiddlewares returns the qualified names of the TCP middlewares referenced by the annotation of the route,
+// which are MiddlewareTCP resources in the namespace of the route, provided by the Kubernetes CRD provider.
+func routeMiddlewares(meta metav1.ObjectMeta) []string {
+	value, ok := meta.Annotations[annotationMiddlewares]
+	if !ok {
+		return nil
+	}
+
+	var middlewares []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		middlewares = append(middlewares, provider.Normalize(makeID(meta.Namespace, name))+"@"+crdProviderName)
+	}
+
+	return middlewares
+}
+
 // Because of Kubernetes validation we admit that the given Hostnames are valid.
 // https://github.com/kubernetes-sigs/gateway-api/blob/ff9883da4cad8554cd300394f725ab3a27502785/apis/v1alpha2/shared_types.go#L252
 func matchingHostnames(listener gatev1alpha2.Listener, hostnames []gatev1alpha2.Hostname) []gatev1alpha2.Hostname {
@@ -1677,6 +1850,124 @@ func loadTCPServices(client Client, namespace string, backendRefs []gatev1alpha2
 	return wrrSvc, services, nil
 }
 
+func loadUDPServices(client Client, namespace string, backendRefs []gatev1alpha2.BackendRef) (*dynamic.UDPService, map[string]*dynamic.UDPService, error) {
+	services := map[string]*dynamic.UDPService{}
+
+	wrrSvc := &dynamic.UDPService{
+		Weighted: &dynamic.UDPWeightedRoundRobin{
+			Services: []dynamic.UDPWRRService{},
+		},
+	}
+
+	for _, backendRef := range backendRefs {
+		if backendRef.Group == nil || backendRef.Kind == nil {
+			// Should not happen as this is validated by kubernetes
+			continue
+		}
+
+		if isInternalService(backendRef) {
+			return nil, nil, fmt.Errorf("traefik internal service %s is not allowed in a WRR loadbalancer", backendRef.Name)
+		}
+
+		weight := int(pointer.Int32Deref(backendRef.Weight, 1))
+
+		if isTraefikService(backendRef) {
+			wrrSvc.Weighted.Services = append(wrrSvc.Weighted.Services, dynamic.UDPWRRService{Name: string(backendRef.Name), Weight: &weight})
+			continue
+		}
+
+		if *backendRef.Group != "" && *backendRef.Group != "core" && *backendRef.Kind != "Service" {
+			return nil, nil, fmt.Errorf("unsupported BackendRef %s/%s/%s", *backendRef.Group, *backendRef.Kind, backendRef.Name)
+		}
+
+		svc := dynamic.UDPService{
+			LoadBalancer: &dynamic.UDPServersLoadBalancer{},
+		}
+
+		service, exists, err := client.GetService(namespace, string(backendRef.Name))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !exists {
+			return nil, nil, errors.New("service not found")
+		}
+
+		if len(service.Spec.Ports) > 1 && backendRef.Port == nil {
+			// If the port is unspecified and the backend is a Service
+			// object consisting of multiple port definitions, the route
+			// must be dropped from the Gateway. The controller should
+			// raise the "ResolvedRefs" condition on the Gateway with the
+			// "DroppedRoutes" reason. The gateway status for this route
+			// should be updated with a condition that describes the error
+			// more specifically.
+			log.WithoutContext().Errorf("A multiple ports Kubernetes Service cannot be used if unspecified backendRef.Port")
+			continue
+		}
+
+		var portSpec corev1.ServicePort
+		var match bool
+
+		for _, p := range service.Spec.Ports {
+			if backendRef.Port == nil || p.Port == int32(*backendRef.Port) {
+				portSpec = p
+				match = true
+				break
+			}
+		}
+
+		if !match {
+			return nil, nil, errors.New("service port not found")
+		}
+
+		endpoints, endpointsExists, endpointsErr := client.GetEndpoints(namespace, string(backendRef.Name))
+		if endpointsErr != nil {
+			return nil, nil, endpointsErr
+		}
+
+		if !endpointsExists {
+			return nil, nil, errors.New("endpoints not found")
+		}
+
+		if len(endpoints.Subsets) == 0 {
+			return nil, nil, errors.New("subset not found")
+		}
+
+		var port int32
+		var portStr string
+		for _, subset := range endpoints.Subsets {
+			for _, p := range subset.Ports {
+				if portSpec.Name == p.Name {
+					port = p.Port
+					break
+				}
+			}
+
+			if port == 0 {
+				return nil, nil, errors.New("cannot define a port")
+			}
+
+			portStr = strconv.FormatInt(int64(port), 10)
+			for _, addr := range subset.Addresses {
+				svc.LoadBalancer.Servers = append(svc.LoadBalancer.Servers, dynamic.UDPServer{
+					Address: net.JoinHostPort(addr.IP, portStr),
+				})
+			}
+		}
+
+		serviceName := provider.Normalize(makeID(service.Namespace, service.Name) + "-" + portStr)
+		services[serviceName] = &svc
+
+		wrrSvc.Weighted.Services = append(wrrSvc.Weighted.Services, dynamic.UDPWRRService{Name: serviceName, Weight: &weight})
+	}
+
+	if len(wrrSvc.Weighted.Services) == 0 {
+		return nil, nil, errors.New("no service has been created")
+	}
+
+	return wrrSvc, services, nil
+}
+
 func getProtocol(portSpec corev1.ServicePort) string {
 	protocol := "http"
 	if portSpec.Port == 443 || strings.HasPrefix(portSpec.Name, "https") {
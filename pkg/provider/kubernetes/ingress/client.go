@@ -41,6 +41,7 @@ type Client interface {
 	GetService(namespace, name string) (*corev1.Service, bool, error)
 	GetSecret(namespace, name string) (*corev1.Secret, bool, error)
 	GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error)
+	GetNode(name string) (*corev1.Node, bool, error)
 	UpdateIngressStatus(ing *netv1.Ingress, ingStatus []netv1.IngressLoadBalancerIngress) error
 	GetServerVersion() *version.Version
 }
@@ -51,7 +52,9 @@ type clientWrapper struct {
 	factoriesSecret      map[string]kinformers.SharedInformerFactory
 	factoriesIngress     map[string]kinformers.SharedInformerFactory
 	clusterFactory       kinformers.SharedInformerFactory
+	factoryNodes         kinformers.SharedInformerFactory
 	ingressLabelSelector string
+	watchNodes           bool
 	isNamespaceAll       bool
 	watchedNamespaces    []string
 	serverVersion        *version.Version
@@ -249,6 +252,23 @@ func (c *clientWrapper) WatchAll(namespaces []string, stopCh <-chan struct{}) (<
 		}
 	}
 
+	if c.watchNodes {
+		c.factoryNodes = kinformers.NewSharedInformerFactoryWithOptions(c.clientset, resyncPeriod)
+
+		_, err = c.factoryNodes.Core().V1().Nodes().Informer().AddEventHandler(eventHandler)
+		if err != nil {
+			return nil, err
+		}
+
+		c.factoryNodes.Start(stopCh)
+
+		for typ, ok := range c.factoryNodes.WaitForCacheSync(stopCh) {
+			if !ok {
+				return nil, fmt.Errorf("timed out waiting for controller caches to sync %s", typ)
+			}
+		}
+	}
+
 	return eventCh, nil
 }
 
@@ -454,6 +474,17 @@ func (c *clientWrapper) GetEndpoints(namespace, name string) (*corev1.Endpoints,
 	return endpoint, exist, err
 }
 
+// GetNode returns the named node, if the nodes are watched.
+func (c *clientWrapper) GetNode(name string) (*corev1.Node, bool, error) {
+	if c.factoryNodes == nil {
+		return nil, false, nil
+	}
+
+	node, err := c.factoryNodes.Core().V1().Nodes().Lister().Get(name)
+	exist, err := translateNotFoundError(err)
+	return node, exist, err
+}
+
 // GetSecret returns the named secret from the given namespace.
 func (c *clientWrapper) GetSecret(namespace, name string) (*corev1.Secret, bool, error) {
 	if !c.isWatchedNamespace(namespace) {
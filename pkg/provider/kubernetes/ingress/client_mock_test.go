@@ -18,6 +18,7 @@ type clientMock struct {
 	services       []*corev1.Service
 	secrets        []*corev1.Secret
 	endpoints      []*corev1.Endpoints
+	nodes          []*corev1.Node
 	ingressClasses []*netv1.IngressClass
 
 	serverVersion *version.Version
@@ -50,6 +51,8 @@ func newClientMock(serverVersion string, paths ...string) clientMock {
 				c.secrets = append(c.secrets, o)
 			case *corev1.Endpoints:
 				c.endpoints = append(c.endpoints, o)
+			case *corev1.Node:
+				c.nodes = append(c.nodes, o)
 			case *netv1beta1.Ingress:
 				ing, err := convert[netv1.Ingress](o)
 				if err != nil {
@@ -111,6 +114,16 @@ func (c clientMock) GetEndpoints(namespace, name string) (*corev1.Endpoints, boo
 	return &corev1.Endpoints{}, false, nil
 }
 
+func (c clientMock) GetNode(name string) (*corev1.Node, bool, error) {
+	for _, node := range c.nodes {
+		if node.Name == name {
+			return node, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
 func (c clientMock) GetSecret(namespace, name string) (*corev1.Secret, bool, error) {
 	if c.apiSecretError != nil {
 		return nil, false, c.apiSecretError
@@ -47,6 +47,7 @@ type Provider struct {
 	ThrottleDuration          ptypes.Duration  `description:"Ingress refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty" export:"true"`
 	AllowEmptyServices        bool             `description:"Allow creation of services without endpoints." json:"allowEmptyServices,omitempty" toml:"allowEmptyServices,omitempty" yaml:"allowEmptyServices,omitempty" export:"true"`
 	AllowExternalNameServices bool             `description:"Allow ExternalName services." json:"allowExternalNameServices,omitempty" toml:"allowExternalNameServices,omitempty" yaml:"allowExternalNameServices,omitempty" export:"true"`
+	NodeZones                 bool             `description:"Set the zone of the servers from the topology.kubernetes.io/zone label of their nodes." json:"nodeZones,omitempty" toml:"nodeZones,omitempty" yaml:"nodeZones,omitempty" export:"true"`
 
 	lastConfiguration safe.Safe
 
@@ -108,6 +109,7 @@ func (p *Provider) newK8sClient(ctx context.Context) (*clientWrapper, error) {
 	}
 
 	cl.ingressLabelSelector = p.LabelSelector
+	cl.watchNodes = p.NodeZones
 	return cl, nil
 }
 
@@ -634,7 +636,8 @@ func (p *Provider) loadService(client Client, namespace string, backend netv1.In
 			hostPort := net.JoinHostPort(addr.IP, strconv.Itoa(int(port)))
 
 			svc.LoadBalancer.Servers = append(svc.LoadBalancer.Servers, dynamic.Server{
-				URL: fmt.Sprintf("%s://%s", protocol, hostPort),
+				URL:  fmt.Sprintf("%s://%s", protocol, hostPort),
+				Zone: p.nodeZone(client, addr.NodeName),
 			})
 		}
 	}
@@ -642,6 +645,21 @@ func (p *Provider) loadService(client Client, namespace string, backend netv1.In
 	return svc, nil
 }
 
+// nodeZone returns the zone of the given node, read from its topology.kubernetes.io/zone label,
+// or an empty zone if the zones of the nodes are not used.
+func (p *Provider) nodeZone(client Client, nodeName *string) string {
+	if !p.NodeZones || nodeName == nil {
+		return ""
+	}
+
+	node, exists, err := client.GetNode(*nodeName)
+	if err != nil || !exists {
+		return ""
+	}
+
+	return node.Labels[corev1.LabelTopologyZone]
+}
+
 func getNativeServiceAddress(service corev1.Service, svcPort corev1.ServicePort) (string, error) {
 	if service.Spec.ClusterIP == "None" {
 		return "", fmt.Errorf("no clusterIP on headless service: %s/%s", service.Namespace, service.Name)
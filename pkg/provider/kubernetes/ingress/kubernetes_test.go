@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/tls"
@@ -1870,6 +1871,55 @@ func TestLoadConfigurationFromIngressesWithNativeLB(t *testing.T) {
 	}
 }
 
+func TestLoadConfigurationFromIngressesWithNodeZones(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		nodeZones bool
+		expected  []dynamic.Server
+	}{
+		{
+			desc: "Node zones disabled",
+			expected: []dynamic.Server{
+				{URL: "http://10.10.0.1:8080"},
+				{URL: "http://10.10.0.2:8080"},
+				{URL: "http://10.10.0.3:8080"},
+				{URL: "http://10.10.0.4:8080"},
+			},
+		},
+		{
+			desc:      "Node zones enabled",
+			nodeZones: true,
+			expected: []dynamic.Server{
+				{URL: "http://10.10.0.1:8080", Zone: "eu-west-1a"},
+				{URL: "http://10.10.0.2:8080", Zone: "eu-west-1b"},
+				{URL: "http://10.10.0.3:8080"},
+				{URL: "http://10.10.0.4:8080"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var paths []string
+			for _, suffix := range []string{"_ingress", "_endpoint", "_service", "_node"} {
+				paths = append(paths, generateTestFilename(suffix, "Ingress with node zones"))
+			}
+
+			clientMock := newClientMock("v1.17", paths...)
+
+			p := Provider{NodeZones: test.nodeZones}
+			conf := p.loadConfigurationFromIngresses(context.Background(), clientMock)
+
+			require.Contains(t, conf.HTTP.Services, "testing-service1-80")
+			assert.Equal(t, test.expected, conf.HTTP.Services["testing-service1-80"].LoadBalancer.Servers)
+		})
+	}
+}
+
 func generateTestFilename(suffix, desc string) string {
 	return filepath.Join("fixtures", strings.ReplaceAll(desc, " ", "-")+suffix+".yml")
 }
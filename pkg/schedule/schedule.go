@@ -0,0 +1,67 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/cronexpr"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// Schedule is a set of recurring time windows,
+// each one opening on a time matching a cron expression, and lasting for a duration.
+type Schedule struct {
+	expression *cronexpr.Expression
+	duration   time.Duration
+	location   *time.Location
+
+	// now is used to shift the clock in tests.
+	now func() time.Time
+}
+
+// New creates a new Schedule from its configuration.
+func New(config dynamic.Schedule) (*Schedule, error) {
+	if config.Cron == "" {
+		return nil, errors.New("the cron expression of the schedule is missing")
+	}
+
+	expression, err := cronexpr.Parse(config.Cron)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the cron expression of the schedule %q: %w", config.Cron, err)
+	}
+
+	duration := time.Duration(config.Duration)
+	if duration <= 0 {
+		return nil, fmt.Errorf("the duration of the schedule must be positive, got %s", duration)
+	}
+
+	location := time.UTC
+	if config.TimeZone != "" {
+		location, err = time.LoadLocation(config.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("loading the time zone of the schedule: %w", err)
+		}
+	}
+
+	return &Schedule{
+		expression: expression,
+		duration:   duration,
+		location:   location,
+		now:        time.Now,
+	}, nil
+}
+
+// Active reports whether the given time is in one of the time windows of the schedule.
+func (s *Schedule) Active(t time.Time) bool {
+	// The window the time is in, if any, is the one opening on the first time matching the expression,
+	// after the duration of a window before.
+	start := s.expression.Next(t.In(s.location).Add(-s.duration))
+
+	return !start.IsZero() && !start.After(t)
+}
+
+// ActiveNow reports whether the current time is in one of the time windows of the schedule.
+func (s *Schedule) ActiveNow() bool {
+	return s.Active(s.now())
+}
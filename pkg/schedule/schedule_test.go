@@ -0,0 +1,143 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		config    dynamic.Schedule
+		expectErr bool
+	}{
+		{
+			desc:   "valid schedule",
+			config: dynamic.Schedule{Cron: "0 22 * * *", Duration: ptypes.Duration(8 * time.Hour), TimeZone: "Europe/Paris"},
+		},
+		{
+			desc:      "missing cron expression",
+			config:    dynamic.Schedule{Duration: ptypes.Duration(time.Hour)},
+			expectErr: true,
+		},
+		{
+			desc:      "invalid cron expression",
+			config:    dynamic.Schedule{Cron: "foo", Duration: ptypes.Duration(time.Hour)},
+			expectErr: true,
+		},
+		{
+			desc:      "missing duration",
+			config:    dynamic.Schedule{Cron: "0 22 * * *"},
+			expectErr: true,
+		},
+		{
+			desc:      "unknown time zone",
+			config:    dynamic.Schedule{Cron: "0 22 * * *", Duration: ptypes.Duration(time.Hour), TimeZone: "Foo/Bar"},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(test.config)
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSchedule_Active(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		config   dynamic.Schedule
+		time     time.Time
+		expected bool
+	}{
+		{
+			desc:     "before the window",
+			config:   dynamic.Schedule{Cron: "0 22 * * *", Duration: ptypes.Duration(8 * time.Hour)},
+			time:     time.Date(2023, time.May, 1, 21, 59, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			desc:     "at the opening of the window",
+			config:   dynamic.Schedule{Cron: "0 22 * * *", Duration: ptypes.Duration(8 * time.Hour)},
+			time:     time.Date(2023, time.May, 1, 22, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			desc:     "in the window, the next day",
+			config:   dynamic.Schedule{Cron: "0 22 * * *", Duration: ptypes.Duration(8 * time.Hour)},
+			time:     time.Date(2023, time.May, 2, 5, 59, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			desc:     "after the window",
+			config:   dynamic.Schedule{Cron: "0 22 * * *", Duration: ptypes.Duration(8 * time.Hour)},
+			time:     time.Date(2023, time.May, 2, 6, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			desc:     "in the window of the time zone",
+			config:   dynamic.Schedule{Cron: "0 22 * * *", Duration: ptypes.Duration(time.Hour), TimeZone: "Europe/Paris"},
+			time:     time.Date(2023, time.May, 1, 22, 30, 0, 0, paris),
+			expected: true,
+		},
+		{
+			desc:     "outside the window of the time zone",
+			config:   dynamic.Schedule{Cron: "0 22 * * *", Duration: ptypes.Duration(time.Hour), TimeZone: "Europe/Paris"},
+			time:     time.Date(2023, time.May, 1, 22, 30, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			desc:     "on a day of the week",
+			config:   dynamic.Schedule{Cron: "0 0 * * SAT", Duration: ptypes.Duration(48 * time.Hour)},
+			time:     time.Date(2023, time.May, 7, 12, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			desc:     "outside a day of the week",
+			config:   dynamic.Schedule{Cron: "0 0 * * SAT", Duration: ptypes.Duration(48 * time.Hour)},
+			time:     time.Date(2023, time.May, 8, 12, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			sched, err := New(test.config)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, sched.Active(test.time))
+		})
+	}
+}
+
+func TestSchedule_ActiveNow(t *testing.T) {
+	sched, err := New(dynamic.Schedule{Cron: "0 22 * * *", Duration: ptypes.Duration(time.Hour)})
+	require.NoError(t, err)
+
+	sched.now = func() time.Time { return time.Date(2023, time.May, 1, 22, 30, 0, 0, time.UTC) }
+	assert.True(t, sched.ActiveNow())
+
+	sched.now = func() time.Time { return time.Date(2023, time.May, 1, 23, 30, 0, 0, time.UTC) }
+	assert.False(t, sched.ActiveNow())
+}
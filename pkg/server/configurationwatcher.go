@@ -4,12 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"reflect"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/configcache"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/snapshot"
 	"github.com/traefik/traefik/v2/pkg/tls"
 )
 
@@ -27,6 +30,12 @@ type ConfigurationWatcher struct {
 	configurationListeners []func(dynamic.Configuration)
 
 	routinesPool *safe.Pool
+
+	// snapshots holds the snapshots of the applied configurations, and the snapshot the proxy is pinned to.
+	snapshots *snapshot.Store
+
+	// cache persists the configurations of the providers, served on restart while the providers reconnect.
+	cache *configcache.Cache
 }
 
 // NewConfigurationWatcher creates a new ConfigurationWatcher.
@@ -35,6 +44,8 @@ func NewConfigurationWatcher(
 	pvd provider.Provider,
 	defaultEntryPoints []string,
 	requiredProvider string,
+	snapshots *snapshot.Store,
+	cache *configcache.Cache,
 ) *ConfigurationWatcher {
 	return &ConfigurationWatcher{
 		providerAggregator:  pvd,
@@ -43,6 +54,8 @@ func NewConfigurationWatcher(
 		routinesPool:        routinesPool,
 		defaultEntryPoints:  defaultEntryPoints,
 		requiredProvider:    requiredProvider,
+		snapshots:           snapshots,
+		cache:               cache,
 	}
 }
 
@@ -88,13 +101,33 @@ func (c *ConfigurationWatcher) startProviderAggregator() {
 // (throttleAndApplyConfigurations) via a RingChannel, which ensures that we can
 // constantly send in a non-blocking way to the throttling goroutine the last
 // global state we are aware of.
+// The cached configurations of the providers are sent first, if any,
+// and the cached configuration of a provider is replaced as soon as the provider provides its own.
 func (c *ConfigurationWatcher) receiveConfigurations(ctx context.Context) {
 	newConfigurations := make(dynamic.Configurations)
 	var output chan dynamic.Configurations
+
+	// cached holds the names of the providers whose cached configuration is still served.
+	cached := c.loadCachedConfigurations(newConfigurations)
+	var staleTimeout <-chan time.Time
+	if len(cached) > 0 {
+		output = c.newConfigs
+
+		timer := time.NewTimer(c.cache.StaleTimeout())
+		defer timer.Stop()
+		staleTimeout = timer.C
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case <-staleTimeout:
+			staleTimeout = nil
+			if dropStaleConfigurations(newConfigurations, cached) {
+				output = c.newConfigs
+			}
+			cached = nil
 		// DeepCopy is necessary because newConfigurations gets modified later by the consumer of c.newConfigs
 		case output <- newConfigurations.DeepCopy():
 			output = nil
@@ -103,6 +136,12 @@ func (c *ConfigurationWatcher) receiveConfigurations(ctx context.Context) {
 			select {
 			case <-ctx.Done():
 				return
+			case <-staleTimeout:
+				staleTimeout = nil
+				if dropStaleConfigurations(newConfigurations, cached) {
+					output = c.newConfigs
+				}
+				cached = nil
 			case configMsg, ok := <-c.allProvidersConfigs:
 				if !ok {
 					return
@@ -110,6 +149,14 @@ func (c *ConfigurationWatcher) receiveConfigurations(ctx context.Context) {
 
 				logger := log.WithoutContext().WithField(log.ProviderName, configMsg.ProviderName)
 
+				// The cached configuration of the provider is dropped, even if the provider provides an empty one.
+				if _, ok := cached[configMsg.ProviderName]; ok {
+					logger.Debug("Replacing the cached configuration of the provider.")
+					delete(cached, configMsg.ProviderName)
+					delete(newConfigurations, configMsg.ProviderName)
+					output = c.newConfigs
+				}
+
 				if configMsg.Configuration == nil {
 					logger.Debug("Skipping nil configuration.")
 					continue
@@ -145,12 +192,45 @@ func (c *ConfigurationWatcher) receiveConfigurations(ctx context.Context) {
 // as a provider change occurs. If the new set is different from the previous set
 // that had been applied, the new set is applied, and we sleep for a while before
 // listening on the channel again.
+// While the proxy is pinned to a snapshot, the new sets are recorded as snapshots, but not applied.
 func (c *ConfigurationWatcher) applyConfigurations(ctx context.Context) {
 	var lastConfigurations dynamic.Configurations
+
+	// latest is the last configuration built from the providers, applied when the proxy is unpinned.
+	var latest *dynamic.Configuration
+	var latestHash string
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case <-c.snapshots.Changes():
+			hash := c.snapshots.Pinned()
+
+			var conf dynamic.Configuration
+			if hash != "" {
+				pinnedConf, ok := c.snapshots.Get(hash)
+				if !ok {
+					continue
+				}
+				conf = pinnedConf
+			} else {
+				if latest == nil {
+					continue
+				}
+				hash = latestHash
+				conf = *latest.DeepCopy()
+			}
+
+			if hash == c.snapshots.Current() {
+				continue
+			}
+
+			log.WithoutContext().Infof("Applying the configuration snapshot %s", hash)
+
+			c.notifyListeners(conf)
+			c.snapshots.SetCurrent(hash)
+
 		case newConfigs, ok := <-c.newConfigs:
 			if !ok {
 				return
@@ -168,15 +248,84 @@ func (c *ConfigurationWatcher) applyConfigurations(ctx context.Context) {
 			conf := mergeConfiguration(newConfigs.DeepCopy(), c.defaultEntryPoints)
 			conf = applyModel(conf)
 
-			for _, listener := range c.configurationListeners {
-				listener(conf)
+			lastConfigurations = newConfigs
+
+			if c.cache != nil {
+				if err := c.cache.Save(newConfigs); err != nil {
+					log.WithoutContext().Errorf("Could not cache the configuration: %v", err)
+				}
 			}
 
-			lastConfigurations = newConfigs
+			if c.snapshots != nil {
+				hash, err := c.snapshots.Add(conf, snapshot.SourceProviders)
+				if err != nil {
+					log.WithoutContext().Errorf("Could not snapshot the configuration: %v", err)
+				}
+
+				latest = conf.DeepCopy()
+				latestHash = hash
+
+				if pinned := c.snapshots.Pinned(); pinned != "" {
+					log.WithoutContext().Infof("Configuration pinned to the snapshot %s, skipping the configuration %s", pinned, hash)
+					continue
+				}
+
+				c.snapshots.SetCurrent(hash)
+			}
+
+			c.notifyListeners(conf)
 		}
 	}
 }
 
+// loadCachedConfigurations adds the cached configurations of the providers to the given configurations,
+// and returns the names of the providers.
+func (c *ConfigurationWatcher) loadCachedConfigurations(configurations dynamic.Configurations) map[string]struct{} {
+	if c.cache == nil {
+		return nil
+	}
+
+	logger := log.WithoutContext()
+
+	cachedConfigurations, savedAt, err := c.cache.Load()
+	if err != nil {
+		logger.Errorf("Could not load the cached configuration: %v", err)
+		return nil
+	}
+
+	if len(cachedConfigurations) == 0 {
+		return nil
+	}
+
+	logger.Infof("Serving the configuration cached %s ago, while the providers reconnect", time.Since(savedAt).Round(time.Second))
+
+	cached := make(map[string]struct{}, len(cachedConfigurations))
+	for providerName, conf := range cachedConfigurations {
+		configurations[providerName] = conf
+		cached[providerName] = struct{}{}
+	}
+
+	return cached
+}
+
+// dropStaleConfigurations removes the cached configurations of the providers which did not provide their own in time,
+// and returns whether any was removed.
+func dropStaleConfigurations(configurations dynamic.Configurations, cached map[string]struct{}) bool {
+	for providerName := range cached {
+		log.WithoutContext().WithField(log.ProviderName, providerName).
+			Warn("Dropping the cached configuration of the provider, which did not provide its own in time.")
+		delete(configurations, providerName)
+	}
+
+	return len(cached) > 0
+}
+
+func (c *ConfigurationWatcher) notifyListeners(conf dynamic.Configuration) {
+	for _, listener := range c.configurationListeners {
+		listener(conf)
+	}
+}
+
 func logConfiguration(logger log.Logger, configMsg dynamic.Message) {
 	if log.GetLevel() != logrus.DebugLevel {
 		return
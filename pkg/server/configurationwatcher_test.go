@@ -3,15 +3,19 @@ package server
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/configcache"
 	"github.com/traefik/traefik/v2/pkg/provider/aggregator"
 	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/snapshot"
 	th "github.com/traefik/traefik/v2/pkg/testhelpers"
 	"github.com/traefik/traefik/v2/pkg/tls"
 )
@@ -19,6 +23,7 @@ import (
 type mockProvider struct {
 	messages         []dynamic.Message
 	wait             time.Duration
+	start            chan struct{}
 	first            chan struct{}
 	throttleDuration time.Duration
 }
@@ -33,6 +38,10 @@ func (p *mockProvider) Provide(configurationChan chan<- dynamic.Message, pool *s
 		return fmt.Errorf("no messages available")
 	}
 
+	if p.start != nil {
+		<-p.start
+	}
+
 	configurationChan <- p.messages[0]
 
 	if p.first != nil {
@@ -74,7 +83,7 @@ func TestNewConfigurationWatcher(t *testing.T) {
 		}},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{}, "", nil, nil)
 
 	run := make(chan struct{})
 
@@ -142,7 +151,7 @@ func TestWaitForRequiredProvider(t *testing.T) {
 		Configuration: config,
 	})
 
-	watcher := NewConfigurationWatcher(routinesPool, pvdAggregator, []string{}, "required")
+	watcher := NewConfigurationWatcher(routinesPool, pvdAggregator, []string{}, "required", nil, nil)
 
 	publishedConfigCount := 0
 	watcher.AddListener(func(_ dynamic.Configuration) {
@@ -178,7 +187,7 @@ func TestIgnoreTransientConfiguration(t *testing.T) {
 		),
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, &mockProvider{}, []string{"defaultEP"}, "")
+	watcher := NewConfigurationWatcher(routinesPool, &mockProvider{}, []string{"defaultEP"}, "", nil, nil)
 
 	publishedConfigCount := 0
 	var lastConfig dynamic.Configuration
@@ -267,7 +276,7 @@ func TestListenProvidersThrottleProviderConfigReload(t *testing.T) {
 	err := providerAggregator.AddProvider(pvd)
 	assert.NoError(t, err)
 
-	watcher := NewConfigurationWatcher(routinesPool, providerAggregator, []string{}, "")
+	watcher := NewConfigurationWatcher(routinesPool, providerAggregator, []string{}, "", nil, nil)
 
 	publishedConfigCount := 0
 	watcher.AddListener(func(_ dynamic.Configuration) {
@@ -295,7 +304,7 @@ func TestListenProvidersSkipsEmptyConfigs(t *testing.T) {
 		messages: []dynamic.Message{{ProviderName: "mock"}},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{}, "", nil, nil)
 	watcher.AddListener(func(_ dynamic.Configuration) {
 		t.Error("An empty configuration was published but it should not")
 	})
@@ -326,7 +335,7 @@ func TestListenProvidersSkipsSameConfigurationForProvider(t *testing.T) {
 		messages: []dynamic.Message{message, message},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{}, "", nil, nil)
 
 	var configurationReloads int
 	watcher.AddListener(func(_ dynamic.Configuration) {
@@ -370,7 +379,7 @@ func TestListenProvidersDoesNotSkipFlappingConfiguration(t *testing.T) {
 		},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{"defaultEP"}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{"defaultEP"}, "", nil, nil)
 
 	var lastConfig dynamic.Configuration
 	watcher.AddListener(func(conf dynamic.Configuration) {
@@ -449,7 +458,7 @@ func TestListenProvidersIgnoreSameConfig(t *testing.T) {
 	err := providerAggregator.AddProvider(pvd)
 	assert.NoError(t, err)
 
-	watcher := NewConfigurationWatcher(routinesPool, providerAggregator, []string{"defaultEP"}, "")
+	watcher := NewConfigurationWatcher(routinesPool, providerAggregator, []string{"defaultEP"}, "", nil, nil)
 
 	var configurationReloads int
 	var lastConfig dynamic.Configuration
@@ -505,7 +514,7 @@ func TestListenProvidersIgnoreSameConfig(t *testing.T) {
 func TestApplyConfigUnderStress(t *testing.T) {
 	routinesPool := safe.NewPool(context.Background())
 
-	watcher := NewConfigurationWatcher(routinesPool, &mockProvider{}, []string{"defaultEP"}, "")
+	watcher := NewConfigurationWatcher(routinesPool, &mockProvider{}, []string{"defaultEP"}, "", nil, nil)
 
 	routinesPool.GoCtx(func(ctx context.Context) {
 		i := 0
@@ -591,7 +600,7 @@ func TestListenProvidersIgnoreIntermediateConfigs(t *testing.T) {
 	err := providerAggregator.AddProvider(pvd)
 	assert.NoError(t, err)
 
-	watcher := NewConfigurationWatcher(routinesPool, providerAggregator, []string{"defaultEP"}, "")
+	watcher := NewConfigurationWatcher(routinesPool, providerAggregator, []string{"defaultEP"}, "", nil, nil)
 
 	var configurationReloads int
 	var lastConfig dynamic.Configuration
@@ -653,7 +662,7 @@ func TestListenProvidersPublishesConfigForEachProvider(t *testing.T) {
 		},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{"defaultEP"}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{"defaultEP"}, "", nil, nil)
 
 	var publishedProviderConfig dynamic.Configuration
 
@@ -726,7 +735,7 @@ func TestPublishConfigUpdatedByProvider(t *testing.T) {
 		},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{}, "", nil, nil)
 
 	publishedConfigCount := 0
 	watcher.AddListener(func(configuration dynamic.Configuration) {
@@ -776,7 +785,7 @@ func TestPublishConfigUpdatedByConfigWatcherListener(t *testing.T) {
 		},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{}, "", nil, nil)
 
 	publishedConfigCount := 0
 	watcher.AddListener(func(configuration dynamic.Configuration) {
@@ -797,3 +806,130 @@ func TestPublishConfigUpdatedByConfigWatcherListener(t *testing.T) {
 
 	assert.Equal(t, 1, publishedConfigCount)
 }
+
+func TestConfigurationWatcher_snapshotPinning(t *testing.T) {
+	routinesPool := safe.NewPool(context.Background())
+
+	first := make(chan struct{})
+	pvd := &mockProvider{
+		first: first,
+		messages: []dynamic.Message{
+			{
+				ProviderName: "mock",
+				Configuration: &dynamic.Configuration{
+					HTTP: th.BuildConfiguration(th.WithRouters(th.WithRouter("foo"))),
+				},
+			},
+			{
+				ProviderName: "mock",
+				Configuration: &dynamic.Configuration{
+					HTTP: th.BuildConfiguration(th.WithRouters(th.WithRouter("bar"))),
+				},
+			},
+		},
+	}
+
+	snapshots := snapshot.NewStore(10)
+	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{}, "", snapshots, nil)
+
+	published := make(chan dynamic.Configuration, 10)
+	watcher.AddListener(func(conf dynamic.Configuration) {
+		published <- conf
+	})
+
+	watcher.Start()
+
+	t.Cleanup(watcher.Stop)
+	t.Cleanup(routinesPool.Stop)
+
+	conf := <-published
+	assert.Contains(t, conf.HTTP.Routers, "foo@mock")
+
+	fooHash := snapshots.Current()
+	require.NotEmpty(t, fooHash)
+	require.NoError(t, snapshots.Pin(fooHash))
+
+	// The configuration of the provider is recorded, but not applied while pinned.
+	close(first)
+	require.Eventually(t, func() bool { return len(snapshots.List()) == 2 }, time.Second, 10*time.Millisecond)
+
+	select {
+	case conf = <-published:
+		t.Fatalf("unexpected configuration applied while pinned: %v", conf.HTTP.Routers)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	assert.Equal(t, fooHash, snapshots.Current())
+
+	// Unpinning applies the last configuration of the providers.
+	snapshots.Unpin()
+	conf = <-published
+	assert.Contains(t, conf.HTTP.Routers, "bar@mock")
+	assert.NotEqual(t, fooHash, snapshots.Current())
+
+	// Pinning applies the configuration of the snapshot.
+	require.NoError(t, snapshots.Pin(fooHash))
+	conf = <-published
+	assert.Contains(t, conf.HTTP.Routers, "foo@mock")
+	assert.Equal(t, fooHash, snapshots.Current())
+}
+
+func TestConfigurationWatcher_cache(t *testing.T) {
+	routinesPool := safe.NewPool(context.Background())
+
+	cache := configcache.New(filepath.Join(t.TempDir(), "cache.json"), 200*time.Millisecond)
+	err := cache.Save(dynamic.Configurations{
+		"mock": &dynamic.Configuration{
+			HTTP: th.BuildConfiguration(th.WithRouters(th.WithRouter("cached"))),
+		},
+		"gone": &dynamic.Configuration{
+			HTTP: th.BuildConfiguration(th.WithRouters(th.WithRouter("gone"))),
+		},
+	})
+	require.NoError(t, err)
+
+	start := make(chan struct{})
+	pvd := &mockProvider{
+		start: start,
+		messages: []dynamic.Message{{
+			ProviderName: "mock",
+			Configuration: &dynamic.Configuration{
+				HTTP: th.BuildConfiguration(th.WithRouters(th.WithRouter("foo"))),
+			},
+		}},
+	}
+
+	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{}, "", nil, cache)
+
+	published := make(chan dynamic.Configuration, 10)
+	watcher.AddListener(func(conf dynamic.Configuration) {
+		published <- conf
+	})
+
+	watcher.Start()
+
+	t.Cleanup(watcher.Stop)
+	t.Cleanup(routinesPool.Stop)
+
+	// The cached configurations are served before the providers provide theirs.
+	conf := <-published
+	assert.Contains(t, conf.HTTP.Routers, "cached@mock")
+	assert.Contains(t, conf.HTTP.Routers, "gone@gone")
+
+	// The cached configuration of a provider is replaced by its own.
+	close(start)
+	conf = <-published
+	assert.Contains(t, conf.HTTP.Routers, "foo@mock")
+	assert.NotContains(t, conf.HTTP.Routers, "cached@mock")
+	assert.Contains(t, conf.HTTP.Routers, "gone@gone")
+
+	// The cached configuration of a provider which does not provide its own is dropped after the stale timeout.
+	conf = <-published
+	assert.Contains(t, conf.HTTP.Routers, "foo@mock")
+	assert.NotContains(t, conf.HTTP.Routers, "gone@gone")
+
+	confs, _, err := cache.Load()
+	require.NoError(t, err)
+	assert.Contains(t, confs, "mock")
+	assert.NotContains(t, confs, "gone")
+}
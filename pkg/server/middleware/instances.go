@@ -0,0 +1,303 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/containous/alice"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+type instancePathKeyType struct{}
+
+var instancePathKey instancePathKeyType
+
+// WithRouterName returns a context in which the middlewares are built for the given router,
+// so that their instances can be reused by the next configurations.
+func WithRouterName(ctx context.Context, routerName string) context.Context {
+	return context.WithValue(ctx, instancePathKey, []string{routerName})
+}
+
+// withInstancePath returns a context in which the middlewares are built
+// for the given middleware, at the given position of its chain, and the path of this middleware instance.
+// The path is nil if the middlewares are not built for a router.
+func withInstancePath(ctx context.Context, middlewareName string, position int) (context.Context, []string) {
+	path, ok := ctx.Value(instancePathKey).([]string)
+	if !ok {
+		return ctx, nil
+	}
+
+	// The path is copied, as the same parent path is shared by the middlewares of a chain.
+	newPath := make([]string, len(path), len(path)+1)
+	copy(newPath, path)
+	newPath = append(newPath, middlewareName+"#"+strconv.Itoa(position))
+
+	return context.WithValue(ctx, instancePathKey, newPath), newPath
+}
+
+// Instances holds the instances of the middlewares built for the routers, across the configuration reloads,
+// so that a middleware whose settings did not change keeps its state, e.g. the requests counted by a rate limiter.
+// It holds the handlers of the routers as well, so that only the routers whose configuration changed are rebuilt.
+// A nil Instances never reuses an instance.
+type Instances struct {
+	mu         sync.Mutex
+	generation uint64
+	instances  map[string]*instance
+	routers    map[string]*routerInstance
+	// building holds the parts of the routers being built.
+	building map[string]*routerParts
+	// pending holds the instances reused by the configuration being built,
+	// which forward to the handlers of this configuration once it is committed.
+	pending []pendingInstance
+}
+
+type instance struct {
+	config     *dynamic.Middleware
+	handler    http.Handler
+	next       *swappableHandler
+	generation uint64
+}
+
+// routerInstance is the handler of a router, from its middlewares down to its service, excluded.
+type routerInstance struct {
+	config     *routerConfig
+	handler    http.Handler
+	next       *swappableHandler
+	generation uint64
+	parts      *routerParts
+}
+
+// routerParts are the middleware instances and the statistics handlers built for a router,
+// which are kept along with the router when it is reused.
+type routerParts struct {
+	keys  []string
+	stats []middlewareStats
+}
+
+type middlewareStats struct {
+	middlewareName string
+	handler        *statsHandler
+}
+
+type pendingInstance struct {
+	swappable *swappableHandler
+	next      http.Handler
+}
+
+// NewInstances creates a new Instances.
+func NewInstances() *Instances {
+	return &Instances{
+		instances: make(map[string]*instance),
+		routers:   make(map[string]*routerInstance),
+		building:  make(map[string]*routerParts),
+	}
+}
+
+// NextGeneration must be called before building the middlewares of a new configuration.
+// It forgets the instances that were not part of the previous configuration.
+func (i *Instances) NextGeneration() {
+	if i == nil {
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for key, inst := range i.instances {
+		if inst.generation < i.generation {
+			delete(i.instances, key)
+		}
+	}
+
+	for name, inst := range i.routers {
+		if inst.generation < i.generation {
+			delete(i.routers, name)
+		}
+	}
+
+	i.building = make(map[string]*routerParts)
+	i.pending = nil
+	i.generation++
+}
+
+// Commit must be called once the middlewares of a new configuration are built.
+// The reused instances forward to the handlers of the new configuration from then on.
+func (i *Instances) Commit() {
+	if i == nil {
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, p := range i.pending {
+		p.swappable.set(p.next)
+	}
+
+	i.pending = nil
+}
+
+// wrap returns the given middleware constructor, reusing the instance built by a previous configuration
+// for the same path, if the settings of the middleware did not change.
+func (i *Instances) wrap(path []string, config *dynamic.Middleware, constructor alice.Constructor) alice.Constructor {
+	if i == nil || path == nil || !isReusable(config) {
+		return constructor
+	}
+
+	key := strings.Join(path, "|")
+
+	return func(next http.Handler) (http.Handler, error) {
+		if handler, ok := i.reuse(path[0], key, config, next); ok {
+			return handler, nil
+		}
+
+		swappable := &swappableHandler{}
+		swappable.set(next)
+
+		handler, err := constructor(swappable)
+		if err != nil {
+			return nil, err
+		}
+
+		i.mu.Lock()
+		defer i.mu.Unlock()
+
+		i.instances[key] = &instance{
+			config:     config.DeepCopy(),
+			handler:    handler,
+			next:       swappable,
+			generation: i.generation,
+		}
+
+		if parts, ok := i.building[path[0]]; ok {
+			parts.keys = append(parts.keys, key)
+		}
+
+		return handler, nil
+	}
+}
+
+// reuse returns the instance built by a previous configuration for the given key, if its settings did not change.
+func (i *Instances) reuse(routerName, key string, config *dynamic.Middleware, next http.Handler) (http.Handler, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	inst, ok := i.instances[key]
+	if !ok || !reflect.DeepEqual(inst.config, config) {
+		return nil, false
+	}
+
+	inst.generation = i.generation
+	i.pending = append(i.pending, pendingInstance{swappable: inst.next, next: next})
+
+	if parts, ok := i.building[routerName]; ok {
+		parts.keys = append(parts.keys, key)
+	}
+
+	return inst.handler, true
+}
+
+// addStats records the statistics handler built for the middleware at the given path, along with its router.
+func (i *Instances) addStats(path []string, middlewareName string, handler http.Handler) {
+	stats, ok := handler.(*statsHandler)
+	if i == nil || path == nil || !ok {
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if parts, ok := i.building[path[0]]; ok {
+		parts.stats = append(parts.stats, middlewareStats{middlewareName: middlewareName, handler: stats})
+	}
+}
+
+// reuseRouter returns the handler built by a previous configuration for the given router, if its configuration did not change,
+// along with the statistics handlers of its middlewares.
+// The handler forwards to next once the configuration is committed.
+func (i *Instances) reuseRouter(routerName string, config *routerConfig, next http.Handler) (http.Handler, []middlewareStats, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	inst, ok := i.routers[routerName]
+	if !ok || !reflect.DeepEqual(inst.config, config) {
+		return nil, nil, false
+	}
+
+	inst.generation = i.generation
+	i.pending = append(i.pending, pendingInstance{swappable: inst.next, next: next})
+
+	// The middleware instances of the router are kept along with it.
+	for _, key := range inst.parts.keys {
+		if middlewareInst, ok := i.instances[key]; ok {
+			middlewareInst.generation = i.generation
+		}
+	}
+
+	return inst.handler, inst.parts.stats, true
+}
+
+// buildRouter builds the handler of the given router with the given constructor, and keeps it for the next configurations.
+func (i *Instances) buildRouter(routerName string, config *routerConfig, next http.Handler, constructor alice.Constructor) (http.Handler, error) {
+	swappable := &swappableHandler{}
+	swappable.set(next)
+
+	parts := &routerParts{}
+
+	i.mu.Lock()
+	i.building[routerName] = parts
+	i.mu.Unlock()
+
+	handler, err := constructor(swappable)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	delete(i.building, routerName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	i.routers[routerName] = &routerInstance{
+		config:     config,
+		handler:    handler,
+		next:       swappable,
+		generation: i.generation,
+		parts:      parts,
+	}
+
+	return handler, nil
+}
+
+// routerConfig is the configuration a router handler is built from:
+// the router, with the qualified names of its middlewares, and the settings of its middlewares, including the ones of its chains.
+type routerConfig struct {
+	router      *dynamic.Router
+	middlewares map[string]*dynamic.Middleware
+}
+
+// isReusable returns whether the instance of the given middleware only depends on its settings.
+// The chain middlewares are rebuilt, as their middlewares are reused on their own,
+// and so are the errors middlewares, as they forward to the handler of a service of the configuration.
+func isReusable(config *dynamic.Middleware) bool {
+	return config.Chain == nil && config.Errors == nil
+}
+
+// swappableHandler forwards to a handler which can be replaced while serving.
+type swappableHandler struct {
+	handler atomic.Value
+}
+
+func (s *swappableHandler) set(handler http.Handler) {
+	s.handler.Store(&handler)
+}
+
+func (s *swappableHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	(*s.handler.Load().(*http.Handler)).ServeHTTP(rw, req)
+}
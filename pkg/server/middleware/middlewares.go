@@ -9,7 +9,10 @@ import (
 	"strings"
 
 	"github.com/containous/alice"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accounting"
+	"github.com/traefik/traefik/v2/pkg/middlewares/adaptiveconcurrency"
 	"github.com/traefik/traefik/v2/pkg/middlewares/addprefix"
 	"github.com/traefik/traefik/v2/pkg/middlewares/auth"
 	"github.com/traefik/traefik/v2/pkg/middlewares/buffering"
@@ -17,10 +20,16 @@ import (
 	"github.com/traefik/traefik/v2/pkg/middlewares/circuitbreaker"
 	"github.com/traefik/traefik/v2/pkg/middlewares/compress"
 	"github.com/traefik/traefik/v2/pkg/middlewares/customerrors"
+	"github.com/traefik/traefik/v2/pkg/middlewares/deadline"
+	"github.com/traefik/traefik/v2/pkg/middlewares/earlyhints"
 	"github.com/traefik/traefik/v2/pkg/middlewares/headers"
+	"github.com/traefik/traefik/v2/pkg/middlewares/hedging"
+	"github.com/traefik/traefik/v2/pkg/middlewares/idempotency"
 	"github.com/traefik/traefik/v2/pkg/middlewares/inflightreq"
 	"github.com/traefik/traefik/v2/pkg/middlewares/ipwhitelist"
+	"github.com/traefik/traefik/v2/pkg/middlewares/jwtclaims"
 	"github.com/traefik/traefik/v2/pkg/middlewares/passtlsclientcert"
+	"github.com/traefik/traefik/v2/pkg/middlewares/priorityshedding"
 	"github.com/traefik/traefik/v2/pkg/middlewares/ratelimiter"
 	"github.com/traefik/traefik/v2/pkg/middlewares/redirect"
 	"github.com/traefik/traefik/v2/pkg/middlewares/replacepath"
@@ -29,6 +38,8 @@ import (
 	"github.com/traefik/traefik/v2/pkg/middlewares/stripprefix"
 	"github.com/traefik/traefik/v2/pkg/middlewares/stripprefixregex"
 	"github.com/traefik/traefik/v2/pkg/middlewares/tracing"
+	"github.com/traefik/traefik/v2/pkg/overrides"
+	"github.com/traefik/traefik/v2/pkg/schedule"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 )
 
@@ -43,6 +54,11 @@ type Builder struct {
 	configs        map[string]*runtime.MiddlewareInfo
 	pluginBuilder  PluginsBuilder
 	serviceBuilder serviceBuilder
+	overrides      *overrides.Store
+	instances      *Instances
+
+	// fips restricts the TLS connections of the middlewares to the FIPS-approved parameters.
+	fips bool
 }
 
 type serviceBuilder interface {
@@ -50,15 +66,26 @@ type serviceBuilder interface {
 }
 
 // NewBuilder creates a new Builder.
-func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, pluginBuilder PluginsBuilder) *Builder {
-	return &Builder{configs: configs, serviceBuilder: serviceBuilder, pluginBuilder: pluginBuilder}
+// The parameters of the middlewares overridden at runtime, through the API, are looked up in overridesStore.
+// The instances of the middlewares built for the routers are reused from the previous configurations through instances, if any.
+func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, pluginBuilder PluginsBuilder, overridesStore *overrides.Store, instances *Instances) *Builder {
+	return &Builder{configs: configs, serviceBuilder: serviceBuilder, pluginBuilder: pluginBuilder, overrides: overridesStore, instances: instances}
+}
+
+// SetFIPS enables the FIPS mode, restricting the TLS connections of the middlewares,
+// such as the ones to the ForwardAuth servers, to the FIPS-approved parameters.
+func (b *Builder) SetFIPS(enabled bool) {
+	b.fips = enabled
 }
 
 // BuildChain creates a middleware chain.
 func (b *Builder) BuildChain(ctx context.Context, middlewares []string) *alice.Chain {
+	accounted := b.hasAccounting(ctx, middlewares)
+
 	chain := alice.New()
-	for _, name := range middlewares {
+	for i, name := range middlewares {
 		middlewareName := provider.GetQualifiedName(ctx, name)
+		position := i
 
 		chain = chain.Append(func(next http.Handler) (http.Handler, error) {
 			constructorContext := provider.AddInContext(ctx, middlewareName)
@@ -72,24 +99,114 @@ func (b *Builder) BuildChain(ctx context.Context, middlewares []string) *alice.C
 				return nil, err
 			}
 
+			constructorContext, instancePath := withInstancePath(constructorContext, middlewareName, position)
+
 			constructor, err := b.buildConstructor(constructorContext, middlewareName)
 			if err != nil {
 				b.configs[middlewareName].AddError(err, true)
 				return nil, err
 			}
 
+			constructor = b.instances.wrap(instancePath, b.configs[middlewareName].Middleware, constructor)
+
+			if accounted {
+				constructor = accounting.Wrap(middlewareName, constructor)
+			}
+
+			constructor = withStats(b.configs[middlewareName], constructor)
+
 			handler, err := constructor(next)
 			if err != nil {
 				b.configs[middlewareName].AddError(err, true)
 				return nil, err
 			}
 
+			b.instances.addStats(instancePath, middlewareName, handler)
+
 			return handler, nil
 		})
 	}
 	return &chain
 }
 
+// BuildRouter builds the handler of the given router down to next, its service, with the given constructor.
+// The handler built by a previous configuration is reused instead, if the router and its middlewares did not change,
+// in which case it forwards to next once the configuration is committed.
+func (b *Builder) BuildRouter(ctx context.Context, routerName string, router *dynamic.Router, next http.Handler, constructor alice.Constructor) (http.Handler, error) {
+	if b.instances == nil {
+		return constructor(next)
+	}
+
+	config := &routerConfig{router: router.DeepCopy(), middlewares: make(map[string]*dynamic.Middleware)}
+	config.router.Middlewares = qualifiedNames(ctx, router.Middlewares)
+
+	if !b.collectMiddlewares(ctx, config.router.Middlewares, config.middlewares) {
+		return constructor(next)
+	}
+
+	if handler, stats, ok := b.instances.reuseRouter(routerName, config, next); ok {
+		// The middlewares of the reused router report their statistics in the new configuration.
+		for _, s := range stats {
+			s.handler.info.Store(b.configs[s.middlewareName])
+		}
+
+		return handler, nil
+	}
+
+	return b.instances.buildRouter(routerName, config, next, constructor)
+}
+
+// collectMiddlewares collects the settings of the given middlewares, including the ones of their chains,
+// and returns whether they can be reused.
+func (b *Builder) collectMiddlewares(ctx context.Context, names []string, middlewares map[string]*dynamic.Middleware) bool {
+	for _, name := range names {
+		middlewareName := provider.GetQualifiedName(ctx, name)
+		if _, ok := middlewares[middlewareName]; ok {
+			continue
+		}
+
+		midInf, ok := b.configs[middlewareName]
+		if !ok || midInf.Middleware == nil || midInf.Errors != nil {
+			return false
+		}
+
+		config := midInf.Middleware.DeepCopy()
+		middlewares[middlewareName] = config
+
+		if config.Chain != nil {
+			chainCtx := provider.AddInContext(ctx, middlewareName)
+			config.Chain.Middlewares = qualifiedNames(chainCtx, config.Chain.Middlewares)
+
+			if !b.collectMiddlewares(chainCtx, config.Chain.Middlewares, middlewares) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func qualifiedNames(ctx context.Context, names []string) []string {
+	var qualified []string
+	for _, name := range names {
+		qualified = append(qualified, provider.GetQualifiedName(ctx, name))
+	}
+
+	return qualified
+}
+
+// hasAccounting returns whether one of the given middlewares is an accounting middleware,
+// in which case the time spent in each middleware of the chain has to be measured.
+func (b *Builder) hasAccounting(ctx context.Context, middlewares []string) bool {
+	for _, name := range middlewares {
+		midInf, ok := b.configs[provider.GetQualifiedName(ctx, name)]
+		if ok && midInf.Middleware != nil && midInf.Accounting != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func checkRecursion(ctx context.Context, middlewareName string) (context.Context, error) {
 	currentStack, ok := ctx.Value(middlewareStackKey).([]string)
 	if !ok {
@@ -111,8 +228,28 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 	var middleware alice.Constructor
 	badConf := errors.New("cannot create middleware: multi-types middleware not supported, consider declaring two different pieces of middleware instead")
 
+	// Accounting
+	if config.Accounting != nil {
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return accounting.New(ctx, next, *config.Accounting, middlewareName, b.overrides)
+		}
+	}
+
+	// AdaptiveConcurrency
+	if config.AdaptiveConcurrency != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return adaptiveconcurrency.New(ctx, next, *config.AdaptiveConcurrency, middlewareName)
+		}
+	}
+
 	// AddPrefix
 	if config.AddPrefix != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
 		middleware = func(next http.Handler) (http.Handler, error) {
 			return addprefix.New(ctx, next, *config.AddPrefix, middlewareName)
 		}
@@ -170,7 +307,7 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 			return nil, badConf
 		}
 		middleware = func(next http.Handler) (http.Handler, error) {
-			return compress.New(ctx, next, *config.Compress, middlewareName)
+			return compress.New(ctx, next, *config.Compress, middlewareName, b.overrides)
 		}
 	}
 
@@ -199,6 +336,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// Deadline
+	if config.Deadline != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return deadline.New(ctx, next, *config.Deadline, middlewareName)
+		}
+	}
+
 	// DigestAuth
 	if config.DigestAuth != nil {
 		if middleware != nil {
@@ -209,13 +356,23 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// EarlyHints
+	if config.EarlyHints != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return earlyhints.New(ctx, next, *config.EarlyHints, middlewareName)
+		}
+	}
+
 	// ForwardAuth
 	if config.ForwardAuth != nil {
 		if middleware != nil {
 			return nil, badConf
 		}
 		middleware = func(next http.Handler) (http.Handler, error) {
-			return auth.NewForward(ctx, next, *config.ForwardAuth, middlewareName)
+			return auth.NewForward(ctx, next, *config.ForwardAuth, middlewareName, b.fips)
 		}
 	}
 
@@ -229,6 +386,36 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// Hedging
+	if config.Hedging != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return hedging.New(ctx, next, *config.Hedging, middlewareName)
+		}
+	}
+
+	// Idempotency
+	if config.Idempotency != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return idempotency.New(ctx, next, *config.Idempotency, middlewareName)
+		}
+	}
+
+	// JWTClaims
+	if config.JWTClaims != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return jwtclaims.New(ctx, next, *config.JWTClaims, middlewareName)
+		}
+	}
+
 	// IPWhiteList
 	if config.IPWhiteList != nil {
 		if middleware != nil {
@@ -259,13 +446,23 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// PriorityShedding
+	if config.PriorityShedding != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return priorityshedding.New(ctx, next, *config.PriorityShedding, middlewareName)
+		}
+	}
+
 	// RateLimit
 	if config.RateLimit != nil {
 		if middleware != nil {
 			return nil, badConf
 		}
 		middleware = func(next http.Handler) (http.Handler, error) {
-			return ratelimiter.New(ctx, next, *config.RateLimit, middlewareName)
+			return ratelimiter.New(ctx, next, *config.RateLimit, middlewareName, b.overrides)
 		}
 	}
 
@@ -365,9 +562,38 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		return nil, fmt.Errorf("invalid middleware %q configuration: invalid middleware type or middleware does not exist", middlewareName)
 	}
 
+	if config.Schedule != nil {
+		sched, err := schedule.New(*config.Schedule)
+		if err != nil {
+			return nil, err
+		}
+
+		return scheduled(sched, tracing.Wrap(ctx, middleware)), nil
+	}
+
 	return tracing.Wrap(ctx, middleware), nil
 }
 
+// scheduled wraps the middleware constructor, for the requests to only go through the middleware during the time windows of the schedule,
+// and to go directly to the next handler otherwise.
+func scheduled(sched *schedule.Schedule, constructor alice.Constructor) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		handler, err := constructor(next)
+		if err != nil {
+			return nil, err
+		}
+
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if sched.ActiveNow() {
+				handler.ServeHTTP(rw, req)
+				return
+			}
+
+			next.ServeHTTP(rw, req)
+		}), nil
+	}
+}
+
 func inSlice(element string, stack []string) bool {
 	for _, value := range stack {
 		if value == element {
@@ -6,9 +6,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
@@ -18,7 +20,7 @@ func TestBuilder_BuildChainNilConfig(t *testing.T) {
 	testConfig := map[string]*runtime.MiddlewareInfo{
 		"empty": {},
 	}
-	middlewaresBuilder := NewBuilder(testConfig, nil, nil)
+	middlewaresBuilder := NewBuilder(testConfig, nil, nil, nil, nil)
 
 	chain := middlewaresBuilder.BuildChain(context.Background(), []string{"empty"})
 	_, err := chain.Then(nil)
@@ -29,7 +31,7 @@ func TestBuilder_BuildChainNonExistentChain(t *testing.T) {
 	testConfig := map[string]*runtime.MiddlewareInfo{
 		"foobar": {},
 	}
-	middlewaresBuilder := NewBuilder(testConfig, nil, nil)
+	middlewaresBuilder := NewBuilder(testConfig, nil, nil, nil, nil)
 
 	chain := middlewaresBuilder.BuildChain(context.Background(), []string{"empty"})
 	_, err := chain.Then(nil)
@@ -270,7 +272,7 @@ func TestBuilder_BuildChainWithContext(t *testing.T) {
 					Middlewares: test.configuration,
 				},
 			})
-			builder := NewBuilder(rtConf.Middlewares, nil, nil)
+			builder := NewBuilder(rtConf.Middlewares, nil, nil, nil, nil)
 
 			result := builder.BuildChain(ctx, test.buildChain)
 
@@ -292,6 +294,236 @@ func TestBuilder_BuildChainWithContext(t *testing.T) {
 	}
 }
 
+func TestBuilder_BuildChainStats(t *testing.T) {
+	rtConf := runtime.NewConfig(dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Middlewares: map[string]*dynamic.Middleware{
+				"headers": {
+					Headers: &dynamic.Headers{
+						CustomRequestHeaders: map[string]string{"X-Foo": "bar"},
+					},
+				},
+				"whitelist": {
+					IPWhiteList: &dynamic.IPWhiteList{
+						SourceRange: []string{"10.0.0.1"},
+					},
+				},
+			},
+		},
+	})
+	builder := NewBuilder(rtConf.Middlewares, nil, nil, nil, nil)
+
+	handler, err := builder.BuildChain(context.Background(), []string{"headers", "whitelist"}).
+		Then(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusInternalServerError)
+		}))
+	require.NoError(t, err)
+
+	for _, remoteAddr := range []string{"10.0.0.1:1234", "10.0.0.2:1234", "10.0.0.3:1234"} {
+		req := httptest.NewRequest(http.MethodGet, "http://foo/", nil)
+		req.RemoteAddr = remoteAddr
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	stats := rtConf.Middlewares["headers"].GetStats()
+	assert.Equal(t, uint64(3), stats.Invocations)
+	assert.Equal(t, uint64(0), stats.Rejections)
+	// The errors of the handlers following the middleware are not counted as its own.
+	assert.Equal(t, uint64(0), stats.Errors)
+	assert.NotEmpty(t, stats.AverageLatency)
+
+	stats = rtConf.Middlewares["whitelist"].GetStats()
+	assert.Equal(t, uint64(3), stats.Invocations)
+	assert.Equal(t, uint64(2), stats.Rejections)
+	assert.Equal(t, uint64(0), stats.Errors)
+}
+
+func TestBuilder_BuildChainInstances(t *testing.T) {
+	newConfig := func(average int64) *runtime.Configuration {
+		return runtime.NewConfig(dynamic.Configuration{
+			HTTP: &dynamic.HTTPConfiguration{
+				Middlewares: map[string]*dynamic.Middleware{
+					"ratelimit": {
+						RateLimit: &dynamic.RateLimit{Average: average, Period: ptypes.Duration(time.Hour)},
+					},
+				},
+			},
+		})
+	}
+
+	serve := func(handler http.Handler) *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://foo/", nil))
+		return rw
+	}
+
+	build := func(instances *Instances, rtConf *runtime.Configuration, name string) http.Handler {
+		t.Helper()
+
+		instances.NextGeneration()
+
+		ctx := WithRouterName(context.Background(), "router")
+		handler, err := NewBuilder(rtConf.Middlewares, nil, nil, nil, instances).BuildChain(ctx, []string{"ratelimit"}).
+			Then(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("X-Generation", name)
+			}))
+		require.NoError(t, err)
+
+		return handler
+	}
+
+	instances := NewInstances()
+
+	first := build(instances, newConfig(1), "first")
+	instances.Commit()
+	assert.Equal(t, http.StatusOK, serve(first).Code)
+
+	// The rate limiter is reused, as its settings did not change, and keeps its state.
+	second := build(instances, newConfig(1), "second")
+	instances.Commit()
+	assert.Equal(t, http.StatusTooManyRequests, serve(second).Code)
+
+	// The rate limiter is rebuilt, as its settings changed.
+	third := build(instances, newConfig(2), "third")
+	instances.Commit()
+
+	rw := serve(third)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "third", rw.Header().Get("X-Generation"))
+
+	// Without the router, the middlewares are not reused.
+	handler, err := NewBuilder(newConfig(2).Middlewares, nil, nil, nil, instances).BuildChain(context.Background(), []string{"ratelimit"}).
+		Then(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, serve(handler).Code)
+}
+
+func TestInstances_Commit(t *testing.T) {
+	rtConf := runtime.NewConfig(dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Middlewares: map[string]*dynamic.Middleware{
+				"headers": {
+					Headers: &dynamic.Headers{CustomResponseHeaders: map[string]string{"X-Foo": "bar"}},
+				},
+			},
+		},
+	})
+
+	instances := NewInstances()
+	ctx := WithRouterName(context.Background(), "router")
+
+	build := func(name string) http.Handler {
+		t.Helper()
+
+		instances.NextGeneration()
+
+		handler, err := NewBuilder(rtConf.Middlewares, nil, nil, nil, instances).BuildChain(ctx, []string{"headers"}).
+			Then(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("X-Generation", name)
+			}))
+		require.NoError(t, err)
+
+		return handler
+	}
+
+	first := build("first")
+	instances.Commit()
+
+	second := build("second")
+
+	rw := httptest.NewRecorder()
+	second.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://foo/", nil))
+	assert.Equal(t, "first", rw.Header().Get("X-Generation"))
+
+	instances.Commit()
+
+	for _, handler := range []http.Handler{first, second} {
+		rw = httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://foo/", nil))
+		assert.Equal(t, "second", rw.Header().Get("X-Generation"))
+	}
+}
+
+func TestBuilder_BuildRouter(t *testing.T) {
+	newConfig := func(average int64, header string) *runtime.Configuration {
+		return runtime.NewConfig(dynamic.Configuration{
+			HTTP: &dynamic.HTTPConfiguration{
+				Middlewares: map[string]*dynamic.Middleware{
+					"ratelimit": {
+						RateLimit: &dynamic.RateLimit{Average: average, Burst: 2, Period: ptypes.Duration(time.Hour)},
+					},
+					"chain": {
+						Chain: &dynamic.Chain{Middlewares: []string{"headers"}},
+					},
+					"headers": {
+						Headers: &dynamic.Headers{CustomResponseHeaders: map[string]string{"X-Foo": header}},
+					},
+				},
+			},
+		})
+	}
+
+	serve := func(handler http.Handler) *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://foo/", nil))
+		return rw
+	}
+
+	instances := NewInstances()
+
+	var builds int
+	build := func(rtConf *runtime.Configuration, rule, name string) http.Handler {
+		t.Helper()
+
+		instances.NextGeneration()
+
+		router := &dynamic.Router{Rule: rule, Service: "service", Middlewares: []string{"ratelimit", "chain"}}
+		service := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("X-Generation", name)
+			rw.WriteHeader(http.StatusOK)
+		})
+
+		builder := NewBuilder(rtConf.Middlewares, nil, nil, nil, instances)
+		handler, err := builder.BuildRouter(context.Background(), "router", router, service, func(next http.Handler) (http.Handler, error) {
+			builds++
+			return builder.BuildChain(WithRouterName(context.Background(), "router"), router.Middlewares).Then(next)
+		})
+		require.NoError(t, err)
+
+		instances.Commit()
+
+		return handler
+	}
+
+	first := build(newConfig(1, "bar"), "Host(`foo`)", "first")
+	assert.Equal(t, http.StatusOK, serve(first).Code)
+
+	// The router is reused as is, as neither the router nor its middlewares changed,
+	// and forwards to the service of the new configuration.
+	rtConf := newConfig(1, "bar")
+	second := build(rtConf, "Host(`foo`)", "second")
+	assert.Equal(t, 1, builds)
+
+	rw := serve(second)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "second", rw.Header().Get("X-Generation"))
+	assert.Equal(t, uint64(1), rtConf.Middlewares["ratelimit"].GetStats().Invocations)
+
+	// The router is rebuilt, as its rule changed, while its rate limiter is reused.
+	third := build(newConfig(1, "bar"), "Host(`bar`)", "third")
+	assert.Equal(t, 2, builds)
+	assert.Equal(t, http.StatusTooManyRequests, serve(third).Code)
+
+	// The router is rebuilt, as a middleware of its chain changed.
+	fourth := build(newConfig(2, "baz"), "Host(`bar`)", "fourth")
+	assert.Equal(t, 3, builds)
+
+	rw = serve(fourth)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "baz", rw.Header().Get("X-Foo"))
+	assert.Equal(t, "fourth", rw.Header().Get("X-Generation"))
+}
+
 func TestBuilder_buildConstructor(t *testing.T) {
 	testConfig := map[string]*dynamic.Middleware{
 		"cb-empty": {
@@ -329,7 +561,7 @@ func TestBuilder_buildConstructor(t *testing.T) {
 			Middlewares: testConfig,
 		},
 	})
-	middlewaresBuilder := NewBuilder(rtConf.Middlewares, nil, nil)
+	middlewaresBuilder := NewBuilder(rtConf.Middlewares, nil, nil, nil, nil)
 
 	testCases := []struct {
 		desc          string
@@ -382,3 +614,67 @@ func TestBuilder_buildConstructor(t *testing.T) {
 		})
 	}
 }
+
+func TestBuilder_buildConstructorSchedule(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		schedule       *dynamic.Schedule
+		expectedError  bool
+		expectedPrefix string
+	}{
+		{
+			desc:           "without schedule",
+			expectedPrefix: "/foo",
+		},
+		{
+			desc:           "during a time window",
+			schedule:       &dynamic.Schedule{Cron: "* * * * *", Duration: ptypes.Duration(time.Hour)},
+			expectedPrefix: "/foo",
+		},
+		{
+			desc:     "outside the time windows",
+			schedule: &dynamic.Schedule{Cron: "0 0 1 1 * 2000", Duration: ptypes.Duration(time.Hour)},
+		},
+		{
+			desc:          "invalid schedule",
+			schedule:      &dynamic.Schedule{Cron: "foo", Duration: ptypes.Duration(time.Hour)},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rtConf := runtime.NewConfig(dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Middlewares: map[string]*dynamic.Middleware{
+						"ap-foo": {
+							AddPrefix: &dynamic.AddPrefix{Prefix: "/foo"},
+							Schedule:  test.schedule,
+						},
+					},
+				},
+			})
+			middlewaresBuilder := NewBuilder(rtConf.Middlewares, nil, nil, nil, nil)
+
+			constructor, err := middlewaresBuilder.buildConstructor(context.Background(), "ap-foo")
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var path string
+			handler, err := constructor(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+				path = req.URL.Path
+			}))
+			require.NoError(t, err)
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://foo.bar/", nil))
+
+			assert.Equal(t, test.expectedPrefix+"/", path)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/containous/alice"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+)
+
+// requestStats holds the state of a request going through a middleware counted by a statsHandler.
+type requestStats struct {
+	forwarded atomic.Bool
+	// downstream is the time spent in the handlers following the middleware, in nanoseconds.
+	downstream atomic.Int64
+}
+
+// requestStatsKey is the key of the state of a request in its context, for a given statsHandler,
+// as the same request can go several times through the same middleware.
+type requestStatsKey struct {
+	handler *statsHandler
+}
+
+func contextWithRequestStats(ctx context.Context, handler *statsHandler, stats *requestStats) context.Context {
+	return context.WithValue(ctx, requestStatsKey{handler: handler}, stats)
+}
+
+func requestStatsFromContext(ctx context.Context, handler *statsHandler) *requestStats {
+	stats, _ := ctx.Value(requestStatsKey{handler: handler}).(*requestStats)
+	return stats
+}
+
+// withStats wraps the given middleware constructor so that the requests going through the middleware
+// are counted in the given middleware information.
+func withStats(info *runtime.MiddlewareInfo, constructor alice.Constructor) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		s := &statsHandler{}
+		s.info.Store(info)
+
+		handler, err := constructor(&statsDownstreamHandler{parent: s, next: next})
+		if err != nil {
+			return nil, err
+		}
+
+		s.next = handler
+
+		return s, nil
+	}
+}
+
+type statsHandler struct {
+	// info is replaced by the one of the new configuration when the router of the middleware is reused.
+	info atomic.Pointer[runtime.MiddlewareInfo]
+	next http.Handler
+}
+
+func (s *statsHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	stats := &requestStats{}
+	recorder := newStatusRecorder(rw)
+	start := time.Now()
+
+	defer func() {
+		latency := time.Since(start) - time.Duration(stats.downstream.Load())
+		forwarded := stats.forwarded.Load()
+
+		if r := recover(); r != nil {
+			s.info.Load().RecordRequest(!forwarded, true, latency)
+			panic(r)
+		}
+
+		code := recorder.getCode()
+		s.info.Load().RecordRequest(!forwarded, !forwarded && code >= http.StatusInternalServerError, latency)
+	}()
+
+	s.next.ServeHTTP(recorder, req.WithContext(contextWithRequestStats(req.Context(), s, stats)))
+}
+
+type statsDownstreamHandler struct {
+	parent *statsHandler
+	next   http.Handler
+}
+
+func (d *statsDownstreamHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	stats := requestStatsFromContext(req.Context(), d.parent)
+	if stats == nil {
+		d.next.ServeHTTP(rw, req)
+		return
+	}
+
+	stats.forwarded.Store(true)
+	start := time.Now()
+
+	defer func() {
+		stats.downstream.Add(int64(time.Since(start)))
+	}()
+
+	d.next.ServeHTTP(rw, req)
+}
+
+type statusRecorder interface {
+	http.ResponseWriter
+	http.Flusher
+	getCode() int
+}
+
+func newStatusRecorder(rw http.ResponseWriter) statusRecorder {
+	rec := &statusResponseWriter{ResponseWriter: rw}
+	if _, ok := rw.(http.CloseNotifier); !ok {
+		return rec
+	}
+	return &statusResponseWriterWithCloseNotify{rec}
+}
+
+// statusResponseWriter captures the status code of the response.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	code atomic.Int32
+}
+
+type statusResponseWriterWithCloseNotify struct {
+	*statusResponseWriter
+}
+
+// CloseNotify returns a channel that receives at most a
+// single value (true) when the client connection has gone away.
+func (s *statusResponseWriterWithCloseNotify) CloseNotify() <-chan bool {
+	return s.ResponseWriter.(http.CloseNotifier).CloseNotify()
+}
+
+func (s *statusResponseWriter) getCode() int {
+	if code := s.code.Load(); code != 0 {
+		return int(code)
+	}
+	return http.StatusOK
+}
+
+func (s *statusResponseWriter) WriteHeader(code int) {
+	if code >= http.StatusOK {
+		s.code.CompareAndSwap(0, int32(code))
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusResponseWriter) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (s *statusResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", s.ResponseWriter)
+	}
+	return h.Hijack()
+}
@@ -6,8 +6,15 @@ import (
 	"strings"
 
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/conntrace"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	tcpbandwidthlimit "github.com/traefik/traefik/v2/pkg/middlewares/tcp/bandwidthlimit"
+	tcpbytequota "github.com/traefik/traefik/v2/pkg/middlewares/tcp/bytequota"
+	tcpframing "github.com/traefik/traefik/v2/pkg/middlewares/tcp/framing"
+	tcpgeoip "github.com/traefik/traefik/v2/pkg/middlewares/tcp/geoip"
 	inflightconn "github.com/traefik/traefik/v2/pkg/middlewares/tcp/inflightconn"
 	ipwhitelist "github.com/traefik/traefik/v2/pkg/middlewares/tcp/ipwhitelist"
+	tcpratelimiter "github.com/traefik/traefik/v2/pkg/middlewares/tcp/ratelimiter"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	"github.com/traefik/traefik/v2/pkg/tcp"
 )
@@ -20,12 +27,14 @@ const (
 
 // Builder the middleware builder.
 type Builder struct {
-	configs map[string]*runtime.TCPMiddlewareInfo
+	configs         map[string]*runtime.TCPMiddlewareInfo
+	connTrace       *conntrace.Filters
+	metricsRegistry metrics.Registry
 }
 
 // NewBuilder creates a new Builder.
-func NewBuilder(configs map[string]*runtime.TCPMiddlewareInfo) *Builder {
-	return &Builder{configs: configs}
+func NewBuilder(configs map[string]*runtime.TCPMiddlewareInfo, connTrace *conntrace.Filters, metricsRegistry metrics.Registry) *Builder {
+	return &Builder{configs: configs, connTrace: connTrace, metricsRegistry: metricsRegistry}
 }
 
 // BuildChain creates a middleware chain.
@@ -53,12 +62,16 @@ func (b *Builder) BuildChain(ctx context.Context, middlewares []string) *tcp.Cha
 				return nil, err
 			}
 
-			handler, err := constructor(next)
+			handler, err := withStats(b.configs[middlewareName], constructor)(next)
 			if err != nil {
 				b.configs[middlewareName].AddError(err, true)
 				return nil, err
 			}
 
+			if b.connTrace != nil {
+				return conntrace.NewHandler("middleware "+middlewareName, handler), nil
+			}
+
 			return handler, nil
 		})
 	}
@@ -87,6 +100,34 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 
 	var middleware tcp.Constructor
 
+	// BandwidthLimit
+	if config.BandwidthLimit != nil {
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return tcpbandwidthlimit.New(ctx, next, *config.BandwidthLimit, middlewareName)
+		}
+	}
+
+	// ByteQuota
+	if config.ByteQuota != nil {
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return tcpbytequota.New(ctx, next, *config.ByteQuota, middlewareName)
+		}
+	}
+
+	// Framing
+	if config.Framing != nil {
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return tcpframing.New(ctx, next, *config.Framing, middlewareName)
+		}
+	}
+
+	// GeoIP
+	if config.GeoIP != nil {
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return tcpgeoip.New(ctx, next, *config.GeoIP, middlewareName, b.metricsRegistry)
+		}
+	}
+
 	// InFlightConn
 	if config.InFlightConn != nil {
 		middleware = func(next tcp.Handler) (tcp.Handler, error) {
@@ -101,6 +142,13 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// RateLimit
+	if config.RateLimit != nil {
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return tcpratelimiter.New(ctx, next, *config.RateLimit, middlewareName)
+		}
+	}
+
 	if middleware == nil {
 		return nil, fmt.Errorf("invalid middleware %q configuration: invalid middleware type or middleware does not exist", middlewareName)
 	}
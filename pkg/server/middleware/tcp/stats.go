@@ -0,0 +1,40 @@
+package tcpmiddleware
+
+import (
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+// withStats wraps the given middleware constructor so that the connections going through the middleware
+// are counted in the given middleware information.
+func withStats(info *runtime.TCPMiddlewareInfo, constructor tcp.Constructor) tcp.Constructor {
+	return func(next tcp.Handler) (tcp.Handler, error) {
+		handler, err := constructor(tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+			info.RecordForwardedConnection()
+			next.ServeTCP(conn)
+		}))
+		if err != nil {
+			return nil, err
+		}
+
+		return &statsHandler{info: info, next: handler}, nil
+	}
+}
+
+type statsHandler struct {
+	info *runtime.TCPMiddlewareInfo
+	next tcp.Handler
+}
+
+func (s *statsHandler) ServeTCP(conn tcp.WriteCloser) {
+	s.info.RecordConnection()
+
+	defer func() {
+		if r := recover(); r != nil {
+			s.info.RecordFailedConnection()
+			panic(r)
+		}
+	}()
+
+	s.next.ServeTCP(conn)
+}
@@ -7,22 +7,33 @@ import (
 	"net/http"
 
 	"github.com/containous/alice"
+	"github.com/traefik/traefik/v2/pkg/bandwidth"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/maintenance"
 	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	bandwidthmiddleware "github.com/traefik/traefik/v2/pkg/middlewares/bandwidth"
 	"github.com/traefik/traefik/v2/pkg/middlewares/denyrouterrecursion"
+	maintenancemiddleware "github.com/traefik/traefik/v2/pkg/middlewares/maintenance"
 	metricsMiddle "github.com/traefik/traefik/v2/pkg/middlewares/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/recovery"
+	"github.com/traefik/traefik/v2/pkg/middlewares/responsetimeout"
+	tenantmiddleware "github.com/traefik/traefik/v2/pkg/middlewares/tenant"
 	"github.com/traefik/traefik/v2/pkg/middlewares/tracing"
 	httpmuxer "github.com/traefik/traefik/v2/pkg/muxer/http"
+	"github.com/traefik/traefik/v2/pkg/schedule"
 	"github.com/traefik/traefik/v2/pkg/server/middleware"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+	"github.com/traefik/traefik/v2/pkg/tenant"
 	"github.com/traefik/traefik/v2/pkg/tls"
 )
 
 type middlewareBuilder interface {
 	BuildChain(ctx context.Context, names []string) *alice.Chain
+	BuildRouter(ctx context.Context, routerName string, router *dynamic.Router, next http.Handler, constructor alice.Constructor) (http.Handler, error)
 }
 
 type serviceManager interface {
@@ -30,6 +41,14 @@ type serviceManager interface {
 	LaunchHealthCheck()
 }
 
+type tcpMiddlewareBuilder interface {
+	BuildChain(ctx context.Context, names []string) *tcp.Chain
+}
+
+type tcpServiceManager interface {
+	BuildTCP(rootCtx context.Context, serviceName string) (tcp.Handler, error)
+}
+
 // Manager A route/router manager.
 type Manager struct {
 	routerHandlers     map[string]http.Handler
@@ -39,10 +58,31 @@ type Manager struct {
 	chainBuilder       *middleware.ChainBuilder
 	conf               *runtime.Configuration
 	tlsManager         *tls.Manager
+	tenantRollups      *tenant.Rollups
+	accountant         *bandwidth.Accountant
+	maintenance        *maintenance.Flags
+
+	// The TCP services and middlewares, into which the routers with a tunnel forward the CONNECT requests.
+	tcpServiceManager     tcpServiceManager
+	tcpMiddlewaresBuilder tcpMiddlewareBuilder
+}
+
+// ManagerOptions holds the optional dependencies of a Manager.
+type ManagerOptions struct {
+	// TenantRollups, if any, aggregates the traffic statistics of the routers with a tenant.
+	TenantRollups *tenant.Rollups
+	// Accountant, if any, accounts the bytes transferred by the routers.
+	Accountant *bandwidth.Accountant
+	// Maintenance, if any, holds the routers put in maintenance through the API.
+	Maintenance *maintenance.Flags
+	// TCPServiceManager and TCPMiddlewaresBuilder build the TCP services and middlewares,
+	// into which the routers with a tunnel forward the CONNECT requests.
+	TCPServiceManager     tcpServiceManager
+	TCPMiddlewaresBuilder tcpMiddlewareBuilder
 }
 
 // NewManager creates a new Manager.
-func NewManager(conf *runtime.Configuration, serviceManager serviceManager, middlewaresBuilder middlewareBuilder, chainBuilder *middleware.ChainBuilder, metricsRegistry metrics.Registry, tlsManager *tls.Manager) *Manager {
+func NewManager(conf *runtime.Configuration, serviceManager serviceManager, middlewaresBuilder middlewareBuilder, chainBuilder *middleware.ChainBuilder, metricsRegistry metrics.Registry, tlsManager *tls.Manager, opts ManagerOptions) *Manager {
 	return &Manager{
 		routerHandlers:     make(map[string]http.Handler),
 		serviceManager:     serviceManager,
@@ -51,6 +91,12 @@ func NewManager(conf *runtime.Configuration, serviceManager serviceManager, midd
 		chainBuilder:       chainBuilder,
 		conf:               conf,
 		tlsManager:         tlsManager,
+		tenantRollups:      opts.TenantRollups,
+		accountant:         opts.Accountant,
+		maintenance:        opts.Maintenance,
+
+		tcpServiceManager:     opts.TCPServiceManager,
+		tcpMiddlewaresBuilder: opts.TCPMiddlewaresBuilder,
 	}
 }
 
@@ -116,6 +162,18 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 		ctxRouter := log.With(provider.AddInContext(ctx, routerName), log.Str(log.RouterName, routerName))
 		logger := log.FromContext(ctxRouter)
 
+		var conditions []func() bool
+		if routerConfig.Schedule != nil {
+			sched, err := schedule.New(*routerConfig.Schedule)
+			if err != nil {
+				routerConfig.AddError(err, true)
+				logger.Error(err)
+				continue
+			}
+
+			conditions = append(conditions, sched.ActiveNow)
+		}
+
 		handler, err := m.buildRouterHandler(ctxRouter, routerName, routerConfig)
 		if err != nil {
 			routerConfig.AddError(err, true)
@@ -123,7 +181,7 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			continue
 		}
 
-		err = muxer.AddRoute(routerConfig.Rule, routerConfig.Priority, handler)
+		err = muxer.AddRoute(routerConfig.Rule, routerConfig.Priority, handler, conditions...)
 		if err != nil {
 			routerConfig.AddError(err, true)
 			logger.Error(err)
@@ -182,32 +240,105 @@ func (m *Manager) buildHTTPHandler(ctx context.Context, router *runtime.RouterIn
 	}
 	router.Middlewares = qualifiedNames
 
-	if router.Service == "" {
-		return nil, errors.New("the service is missing on the router")
-	}
+	serviceName := router.Service
 
-	sHandler, err := m.serviceManager.BuildHTTP(ctx, router.Service)
-	if err != nil {
-		return nil, err
+	var sHandler http.Handler
+	if router.Tunnel != nil {
+		var err error
+		sHandler, err = m.buildTunnelHandler(ctx, router.Tunnel)
+		if err != nil {
+			return nil, err
+		}
+
+		serviceName = router.Tunnel.Service
+	} else {
+		if router.Service == "" {
+			return nil, errors.New("the service is missing on the router")
+		}
+
+		var err error
+		sHandler, err = m.serviceManager.BuildHTTP(ctx, router.Service)
+		if err != nil {
+			return nil, err
+		}
+
+		if router.ResponseTimeouts != nil {
+			sHandler, err = responsetimeout.New(ctx, sHandler, *router.ResponseTimeouts, routerName)
+			if err != nil {
+				return nil, fmt.Errorf("response timeouts: %w", err)
+			}
+		}
 	}
 
-	mHandler := m.middlewaresBuilder.BuildChain(ctx, router.Middlewares)
+	// The handler of the router is reused from the previous configuration if the router and its middlewares did not change,
+	// and only forwards to the service handler of the new configuration.
+	return m.middlewaresBuilder.BuildRouter(ctx, routerName, router.Router, sHandler, func(next http.Handler) (http.Handler, error) {
+		return m.buildRouterChain(ctx, router, routerName, serviceName).Then(next)
+	})
+}
+
+// buildRouterChain builds the chain of the router, from its own middlewares down to the service, excluded.
+func (m *Manager) buildRouterChain(ctx context.Context, router *runtime.RouterInfo, routerName, serviceName string) alice.Chain {
+	mHandler := m.middlewaresBuilder.BuildChain(middleware.WithRouterName(ctx, routerName), router.Middlewares)
 
 	tHandler := func(next http.Handler) (http.Handler, error) {
-		return tracing.NewForwarder(ctx, routerName, router.Service, next), nil
+		return tracing.NewForwarder(ctx, routerName, serviceName, next), nil
 	}
 
 	chain := alice.New()
 
 	if m.metricsRegistry != nil && m.metricsRegistry.IsRouterEnabled() {
-		chain = chain.Append(metricsMiddle.WrapRouterHandler(ctx, m.metricsRegistry, routerName, provider.GetQualifiedName(ctx, router.Service)))
+		chain = chain.Append(metricsMiddle.WrapRouterHandler(ctx, m.metricsRegistry, routerName, provider.GetQualifiedName(ctx, serviceName)))
+	}
+
+	if m.tenantRollups != nil && router.Tenant != "" {
+		chain = chain.Append(tenantmiddleware.WrapRouterHandler(ctx, m.tenantRollups, router.Tenant))
+	}
+
+	if m.accountant != nil {
+		key := bandwidth.Key{Router: routerName, Service: provider.GetQualifiedName(ctx, serviceName), Tenant: router.Tenant}
+		chain = chain.Append(bandwidthmiddleware.WrapRouterHandler(ctx, m.accountant, key))
+	}
+
+	if m.maintenance != nil {
+		chain = chain.Append(maintenancemiddleware.WrapRouterHandler(ctx, m.maintenance, routerName))
 	}
 
 	if router.DefaultRule {
 		chain = chain.Append(denyrouterrecursion.WrapHandler(routerName))
 	}
 
-	return chain.Extend(*mHandler).Append(tHandler).Then(sHandler)
+	return chain.Extend(*mHandler).Append(tHandler)
+}
+
+// buildTunnelHandler builds the handler tunneling the CONNECT requests into the TCP service of the tunnel,
+// through its TCP middlewares.
+func (m *Manager) buildTunnelHandler(ctx context.Context, tunnel *dynamic.Tunnel) (http.Handler, error) {
+	if tunnel.Service == "" {
+		return nil, errors.New("the service is missing on the tunnel")
+	}
+
+	if m.tcpServiceManager == nil || m.tcpMiddlewaresBuilder == nil {
+		return nil, errors.New("the tunnels are not supported")
+	}
+
+	var qualifiedNames []string
+	for _, name := range tunnel.Middlewares {
+		qualifiedNames = append(qualifiedNames, provider.GetQualifiedName(ctx, name))
+	}
+	tunnel.Middlewares = qualifiedNames
+
+	sHandler, err := m.tcpServiceManager.BuildTCP(ctx, tunnel.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	handler, err := m.tcpMiddlewaresBuilder.BuildChain(ctx, tunnel.Middlewares).Then(sHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return tcp.NewConnectHandler(handler), nil
 }
 
 // BuildDefaultHTTPRouter creates a default HTTP router.
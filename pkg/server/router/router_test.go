@@ -1,8 +1,10 @@
 package router
 
 import (
+	"bufio"
 	"context"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -18,7 +20,9 @@ import (
 	"github.com/traefik/traefik/v2/pkg/middlewares/capture"
 	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
 	"github.com/traefik/traefik/v2/pkg/server/middleware"
+	tcpmiddleware "github.com/traefik/traefik/v2/pkg/server/middleware/tcp"
 	"github.com/traefik/traefik/v2/pkg/server/service"
+	"github.com/traefik/traefik/v2/pkg/tcp"
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
 	"github.com/traefik/traefik/v2/pkg/tls"
 	"github.com/traefik/traefik/v2/pkg/types"
@@ -315,12 +319,12 @@ func TestRouterManager_Get(t *testing.T) {
 
 			roundTripperManager := service.NewRoundTripperManager()
 			roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
-			serviceManager := service.NewManager(rtConf.Services, nil, nil, roundTripperManager)
-			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
+			serviceManager := service.NewManager(rtConf.Services, nil, nil, roundTripperManager, nil, nil, "", nil, nil)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil, nil)
 			chainBuilder := middleware.NewChainBuilder(nil, nil, nil)
 			tlsManager := tls.NewManager()
 
-			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry(), tlsManager)
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry(), tlsManager, ManagerOptions{})
 
 			handlers := routerManager.BuildHandlers(context.Background(), test.entryPoints, false)
 
@@ -422,12 +426,12 @@ func TestAccessLog(t *testing.T) {
 
 			roundTripperManager := service.NewRoundTripperManager()
 			roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
-			serviceManager := service.NewManager(rtConf.Services, nil, nil, roundTripperManager)
-			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
+			serviceManager := service.NewManager(rtConf.Services, nil, nil, roundTripperManager, nil, nil, "", nil, nil)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil, nil)
 			chainBuilder := middleware.NewChainBuilder(nil, nil, nil)
 			tlsManager := tls.NewManager()
 
-			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry(), tlsManager)
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry(), tlsManager, ManagerOptions{})
 
 			handlers := routerManager.BuildHandlers(context.Background(), test.entryPoints, false)
 
@@ -784,13 +788,13 @@ func TestRuntimeConfiguration(t *testing.T) {
 
 			roundTripperManager := service.NewRoundTripperManager()
 			roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
-			serviceManager := service.NewManager(rtConf.Services, nil, nil, roundTripperManager)
-			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
+			serviceManager := service.NewManager(rtConf.Services, nil, nil, roundTripperManager, nil, nil, "", nil, nil)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil, nil)
 			chainBuilder := middleware.NewChainBuilder(nil, nil, nil)
 			tlsManager := tls.NewManager()
 			tlsManager.UpdateConfigs(context.Background(), nil, test.tlsOptions, nil)
 
-			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry(), tlsManager)
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry(), tlsManager, ManagerOptions{})
 
 			_ = routerManager.BuildHandlers(context.Background(), entryPoints, false)
 			_ = routerManager.BuildHandlers(context.Background(), entryPoints, true)
@@ -862,12 +866,12 @@ func TestProviderOnMiddlewares(t *testing.T) {
 
 	roundTripperManager := service.NewRoundTripperManager()
 	roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
-	serviceManager := service.NewManager(rtConf.Services, nil, nil, roundTripperManager)
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
+	serviceManager := service.NewManager(rtConf.Services, nil, nil, roundTripperManager, nil, nil, "", nil, nil)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil, nil)
 	chainBuilder := middleware.NewChainBuilder(nil, nil, nil)
 	tlsManager := tls.NewManager()
 
-	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry(), tlsManager)
+	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry(), tlsManager, ManagerOptions{})
 
 	_ = routerManager.BuildHandlers(context.Background(), entryPoints, false)
 
@@ -877,6 +881,95 @@ func TestProviderOnMiddlewares(t *testing.T) {
 	assert.Equal(t, []string{"m1@docker", "m2@docker", "m1@file"}, rtConf.Middlewares["chain@docker"].Chain.Middlewares)
 }
 
+type echoTCPServiceManager struct{}
+
+func (echoTCPServiceManager) BuildTCP(_ context.Context, _ string) (tcp.Handler, error) {
+	return tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		defer conn.Close()
+
+		_, _ = io.Copy(conn, conn)
+	}), nil
+}
+
+func TestRouterManager_tunnel(t *testing.T) {
+	entryPoints := []string{"web"}
+
+	rtConf := runtime.NewConfig(dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"tunnel@file": {
+					EntryPoints: []string{"web"},
+					Rule:        "Method(`CONNECT`)",
+					Tunnel:      &dynamic.Tunnel{Service: "echo"},
+				},
+			},
+		},
+	})
+
+	serviceManager := service.NewManager(rtConf.Services, nil, nil, service.NewRoundTripperManager(), nil, nil, "", nil, nil)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil, nil)
+	chainBuilder := middleware.NewChainBuilder(nil, nil, nil)
+	tcpMiddlewaresBuilder := tcpmiddleware.NewBuilder(rtConf.TCPMiddlewares, nil, nil)
+
+	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry(), tls.NewManager(), ManagerOptions{
+		TCPServiceManager:     echoTCPServiceManager{},
+		TCPMiddlewaresBuilder: tcpMiddlewaresBuilder,
+	})
+
+	handlers := routerManager.BuildHandlers(context.Background(), entryPoints, false)
+	require.Empty(t, rtConf.Routers["tunnel@file"].Err)
+
+	server := httptest.NewServer(handlers["web"])
+	t.Cleanup(server.Close)
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_, err = conn.Write([]byte("CONNECT backend:5432 HTTP/1.1\r\nHost: backend:5432\r\n\r\n"))
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(conn)
+
+	resp, err := http.ReadResponse(reader, &http.Request{Method: http.MethodConnect})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(reader, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+}
+
+func TestRouterManager_tunnelNotSupported(t *testing.T) {
+	entryPoints := []string{"web"}
+
+	rtConf := runtime.NewConfig(dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"tunnel@file": {
+					EntryPoints: []string{"web"},
+					Rule:        "Method(`CONNECT`)",
+					Tunnel:      &dynamic.Tunnel{Service: "echo"},
+				},
+			},
+		},
+	})
+
+	serviceManager := service.NewManager(rtConf.Services, nil, nil, service.NewRoundTripperManager(), nil, nil, "", nil, nil)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil, nil)
+	chainBuilder := middleware.NewChainBuilder(nil, nil, nil)
+
+	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry(), tls.NewManager(), ManagerOptions{})
+
+	_ = routerManager.BuildHandlers(context.Background(), entryPoints, false)
+
+	assert.Equal(t, []string{"the tunnels are not supported"}, rtConf.Routers["tunnel@file"].Err)
+}
+
 type staticRoundTripperGetter struct {
 	res *http.Response
 }
@@ -885,6 +978,10 @@ func (s staticRoundTripperGetter) Get(name string) (http.RoundTripper, error) {
 	return &staticTransport{res: s.res}, nil
 }
 
+func (s staticRoundTripperGetter) GetWithServerName(name, serverName string) (http.RoundTripper, error) {
+	return &staticTransport{res: s.res}, nil
+}
+
 type staticTransport struct {
 	res *http.Response
 }
@@ -931,12 +1028,12 @@ func BenchmarkRouterServe(b *testing.B) {
 		},
 	})
 
-	serviceManager := service.NewManager(rtConf.Services, nil, nil, staticRoundTripperGetter{res})
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
+	serviceManager := service.NewManager(rtConf.Services, nil, nil, staticRoundTripperGetter{res}, nil, nil, "", nil, nil)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil, nil)
 	chainBuilder := middleware.NewChainBuilder(nil, nil, nil)
 	tlsManager := tls.NewManager()
 
-	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry(), tlsManager)
+	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry(), tlsManager, ManagerOptions{})
 
 	handlers := routerManager.BuildHandlers(context.Background(), entryPoints, false)
 
@@ -974,7 +1071,7 @@ func BenchmarkService(b *testing.B) {
 		},
 	})
 
-	serviceManager := service.NewManager(rtConf.Services, nil, nil, staticRoundTripperGetter{res})
+	serviceManager := service.NewManager(rtConf.Services, nil, nil, staticRoundTripperGetter{res}, nil, nil, "", nil, nil)
 	w := httptest.NewRecorder()
 	req := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/", nil)
 
@@ -7,14 +7,26 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/traefik/traefik/v2/pkg/bandwidth"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/connections"
+	"github.com/traefik/traefik/v2/pkg/conntrace"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/maintenance"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/dnsquery"
 	"github.com/traefik/traefik/v2/pkg/middlewares/snicheck"
+	"github.com/traefik/traefik/v2/pkg/middlewares/sshsession"
+	tcpbandwidth "github.com/traefik/traefik/v2/pkg/middlewares/tcp/bandwidth"
+	tcpmaintenance "github.com/traefik/traefik/v2/pkg/middlewares/tcp/maintenance"
+	tcptenant "github.com/traefik/traefik/v2/pkg/middlewares/tcp/tenant"
 	httpmuxer "github.com/traefik/traefik/v2/pkg/muxer/http"
 	tcpmuxer "github.com/traefik/traefik/v2/pkg/muxer/tcp"
+	"github.com/traefik/traefik/v2/pkg/schedule"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	tcpservice "github.com/traefik/traefik/v2/pkg/server/service/tcp"
 	"github.com/traefik/traefik/v2/pkg/tcp"
+	"github.com/traefik/traefik/v2/pkg/tenant"
 	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
 )
 
@@ -22,6 +34,22 @@ type middlewareBuilder interface {
 	BuildChain(ctx context.Context, names []string) *tcp.Chain
 }
 
+// ManagerOptions holds the optional dependencies of a Manager.
+type ManagerOptions struct {
+	// TenantRollups, if any, aggregates the traffic statistics of the routers with a tenant.
+	TenantRollups *tenant.Rollups
+	// Accountant, if any, accounts the bytes transferred by the routers.
+	Accountant *bandwidth.Accountant
+	// Maintenance, if any, holds the routers put in maintenance through the API.
+	Maintenance *maintenance.Flags
+	// MetricsRegistry, if any, records the metrics of the routers.
+	MetricsRegistry metrics.Registry
+	// ConnTrace, if any, selects the connections traced through the API.
+	ConnTrace *conntrace.Filters
+	// Connections, if any, registers the connections of the routers, for the API.
+	Connections *connections.Registry
+}
+
 // NewManager Creates a new Manager.
 func NewManager(conf *runtime.Configuration,
 	serviceManager *tcpservice.Manager,
@@ -29,6 +57,7 @@ func NewManager(conf *runtime.Configuration,
 	httpHandlers map[string]http.Handler,
 	httpsHandlers map[string]http.Handler,
 	tlsManager *traefiktls.Manager,
+	opts ManagerOptions,
 ) *Manager {
 	return &Manager{
 		serviceManager:     serviceManager,
@@ -36,6 +65,12 @@ func NewManager(conf *runtime.Configuration,
 		httpHandlers:       httpHandlers,
 		httpsHandlers:      httpsHandlers,
 		tlsManager:         tlsManager,
+		tenantRollups:      opts.TenantRollups,
+		accountant:         opts.Accountant,
+		maintenance:        opts.Maintenance,
+		metricsRegistry:    opts.MetricsRegistry,
+		connTrace:          opts.ConnTrace,
+		connections:        opts.Connections,
 		conf:               conf,
 	}
 }
@@ -47,6 +82,12 @@ type Manager struct {
 	httpHandlers       map[string]http.Handler
 	httpsHandlers      map[string]http.Handler
 	tlsManager         *traefiktls.Manager
+	tenantRollups      *tenant.Rollups
+	accountant         *bandwidth.Accountant
+	maintenance        *maintenance.Flags
+	metricsRegistry    metrics.Registry
+	connTrace          *conntrace.Filters
+	connections        *connections.Registry
 	conf               *runtime.Configuration
 }
 
@@ -102,6 +143,7 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 	}
 
 	router.SetHTTPHandler(handlerHTTP)
+	router.SetConnTrace(m.connTrace)
 
 	// Even though the error is seemingly ignored (aside from logging it),
 	// we actually rely later on the fact that a tls config is nil (which happens when an error is returned) to take special steps
@@ -284,6 +326,18 @@ func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtim
 			continue
 		}
 
+		var conditions []func() bool
+		if routerConfig.Schedule != nil {
+			sched, err := schedule.New(*routerConfig.Schedule)
+			if err != nil {
+				routerConfig.AddError(err, true)
+				logger.Error(err)
+				continue
+			}
+
+			conditions = append(conditions, sched.ActiveNow)
+		}
+
 		// HostSNI Rule, but TLS not set on the router, which is an error.
 		// However, we allow the HostSNI(*) exception.
 		if len(domains) > 0 && routerConfig.TLS == nil && domains[0] != "*" {
@@ -294,7 +348,7 @@ func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtim
 
 		var handler tcp.Handler
 		if routerConfig.TLS == nil || routerConfig.TLS.Passthrough {
-			handler, err = m.buildTCPHandler(ctxRouter, routerConfig)
+			handler, err = m.buildTCPHandler(ctxRouter, routerConfig, routerName)
 			if err != nil {
 				routerConfig.AddError(err, true)
 				logger.Error(err)
@@ -304,7 +358,7 @@ func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtim
 
 		if routerConfig.TLS == nil {
 			logger.Debugf("Adding route for %q", routerConfig.Rule)
-			if err := router.AddRoute(routerConfig.Rule, routerConfig.Priority, handler); err != nil {
+			if err := router.AddRoute(routerConfig.Rule, routerConfig.Priority, handler, conditions...); err != nil {
 				routerConfig.AddError(err, true)
 				logger.Error(err)
 			}
@@ -313,7 +367,7 @@ func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtim
 
 		if routerConfig.TLS.Passthrough {
 			logger.Debugf("Adding Passthrough route for %q", routerConfig.Rule)
-			if err := router.muxerTCPTLS.AddRoute(routerConfig.Rule, routerConfig.Priority, handler); err != nil {
+			if err := router.muxerTCPTLS.AddRoute(routerConfig.Rule, routerConfig.Priority, handler, conditions...); err != nil {
 				routerConfig.AddError(err, true)
 				logger.Error(err)
 			}
@@ -367,7 +421,7 @@ func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtim
 		// This seems to be the case so far with the existing matchers (HostSNI, and ClientIP), so it's all good.
 		// Otherwise, we would have to do as for HTTPS, i.e. disallow different TLS configs for the same HostSNIs.
 
-		handler, err = m.buildTCPHandler(ctxRouter, routerConfig)
+		handler, err = m.buildTCPHandler(ctxRouter, routerConfig, routerName)
 		if err != nil {
 			routerConfig.AddError(err, true)
 			logger.Error(err)
@@ -378,10 +432,11 @@ func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtim
 			Next:   handler,
 			Config: tlsConf,
 		}
+		router.tcpTLSConfigs = append(router.tcpTLSConfigs, tlsConf)
 
 		logger.Debugf("Adding TLS route for %q", routerConfig.Rule)
 
-		err = router.muxerTCPTLS.AddRoute(routerConfig.Rule, routerConfig.Priority, handler)
+		err = router.muxerTCPTLS.AddRoute(routerConfig.Rule, routerConfig.Priority, handler, conditions...)
 		if err != nil {
 			routerConfig.AddError(err, true)
 			logger.Error(err)
@@ -389,7 +444,7 @@ func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtim
 	}
 }
 
-func (m *Manager) buildTCPHandler(ctx context.Context, router *runtime.TCPRouterInfo) (tcp.Handler, error) {
+func (m *Manager) buildTCPHandler(ctx context.Context, router *runtime.TCPRouterInfo, routerName string) (tcp.Handler, error) {
 	var qualifiedNames []string
 	for _, name := range router.Middlewares {
 		qualifiedNames = append(qualifiedNames, provider.GetQualifiedName(ctx, name))
@@ -400,6 +455,18 @@ func (m *Manager) buildTCPHandler(ctx context.Context, router *runtime.TCPRouter
 		return nil, errors.New("the service is missing on the router")
 	}
 
+	if router.DNS != nil && router.TLS != nil && router.TLS.Passthrough {
+		return nil, errors.New("the DNS queries cannot be inspected on a router with TLS passthrough")
+	}
+
+	if router.SSH != nil && router.TLS != nil && router.TLS.Passthrough {
+		return nil, errors.New("the SSH sessions cannot be inspected on a router with TLS passthrough")
+	}
+
+	if router.DNS != nil && router.SSH != nil {
+		return nil, errors.New("the DNS and SSH protocol-aware modes cannot be both enabled on a router")
+	}
+
 	sHandler, err := m.serviceManager.BuildTCP(ctx, router.Service)
 	if err != nil {
 		return nil, err
@@ -407,5 +474,51 @@ func (m *Manager) buildTCPHandler(ctx context.Context, router *runtime.TCPRouter
 
 	mHandler := m.middlewaresBuilder.BuildChain(ctx, router.Middlewares)
 
-	return tcp.NewChain().Extend(*mHandler).Then(sHandler)
+	// trace logs when the traced connections enter and leave the handlers built by the constructor.
+	trace := func(name string, constructor tcp.Constructor) tcp.Constructor {
+		if m.connTrace == nil {
+			return constructor
+		}
+		return conntrace.WrapConstructor(name, constructor)
+	}
+
+	chain := tcp.NewChain()
+	if m.connections != nil {
+		chain = chain.Append(func(next tcp.Handler) (tcp.Handler, error) {
+			return connections.NewHandler(m.connections, routerName, provider.GetQualifiedName(ctx, router.Service), next), nil
+		})
+	}
+
+	if m.connTrace != nil {
+		chain = chain.Append(func(next tcp.Handler) (tcp.Handler, error) {
+			return conntrace.NewHandler("router "+routerName, next), nil
+		})
+	}
+
+	if m.tenantRollups != nil && router.Tenant != "" {
+		chain = chain.Append(trace("tenant accounting", tcptenant.WrapRouterHandler(ctx, m.tenantRollups, router.Tenant)))
+	}
+
+	if m.accountant != nil {
+		key := bandwidth.Key{Router: routerName, Service: provider.GetQualifiedName(ctx, router.Service), Tenant: router.Tenant}
+		chain = chain.Append(trace("bandwidth accounting", tcpbandwidth.WrapRouterHandler(ctx, m.accountant, key)))
+	}
+
+	if m.maintenance != nil {
+		chain = chain.Append(trace("maintenance", tcpmaintenance.WrapRouterHandler(ctx, m.maintenance, routerName)))
+	}
+
+	if router.DNS != nil {
+		chain = chain.Append(trace("DNS inspection", dnsquery.WrapTCPRouterHandler(ctx, *router.DNS)))
+	}
+
+	if router.SSH != nil {
+		chain = chain.Append(trace("SSH inspection", sshsession.WrapTCPRouterHandler(ctx, *router.SSH, routerName, m.metricsRegistry)))
+	}
+
+	if m.connTrace != nil {
+		sHandler = conntrace.NewHandler("service "+provider.GetQualifiedName(ctx, router.Service), sHandler)
+	}
+
+	return chain.Extend(*mHandler).Then(sHandler)
 }
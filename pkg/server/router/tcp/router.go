@@ -8,8 +8,10 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"syscall"
 	"time"
 
+	"github.com/traefik/traefik/v2/pkg/conntrace"
 	"github.com/traefik/traefik/v2/pkg/log"
 	tcpmuxer "github.com/traefik/traefik/v2/pkg/muxer/tcp"
 	"github.com/traefik/traefik/v2/pkg/tcp"
@@ -37,11 +39,19 @@ type Router struct {
 	httpHandler  http.Handler
 	httpsHandler http.Handler
 
+	// tcpIdleTimeout is the idle timeout of the connections handed to the TCP handlers.
+	tcpIdleTimeout time.Duration
+
+	// connTrace selects the connections handed to the TCP handlers which are traced.
+	connTrace *conntrace.Filters
+
 	// TLS configs.
 	httpsTLSConfig *tls.Config // default TLS config
 	// hostHTTPTLSConfig contains TLS configs keyed by SNI.
 	// A nil config is the hint to set up a brokenTLSRouter.
 	hostHTTPTLSConfig map[string]*tls.Config // TLS configs keyed by SNI
+	// tcpTLSConfigs contains the TLS configs of the TLS TCP routes.
+	tcpTLSConfigs []*tls.Config
 }
 
 // NewRouter returns a new TCP router.
@@ -90,6 +100,7 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 		connData, err := tcpmuxer.NewConnData("", conn, nil)
 		if err != nil {
 			log.WithoutContext().Errorf("Error while reading TCP connection data: %v", err)
+			tcp.SetCloseReason(conn, tcp.CloseReasonError)
 			conn.Close()
 			return
 		}
@@ -98,7 +109,7 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 		// If there is a handler matching the connection metadata,
 		// we let it handle the connection.
 		if handler != nil {
-			handler.ServeTCP(conn)
+			handler.ServeTCP(r.connTrace.Trace(tcp.WithIdleTimeout(conn, r.tcpIdleTimeout), ""))
 			return
 		}
 		// Otherwise, we keep going because:
@@ -111,6 +122,7 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 	br := bufio.NewReader(conn)
 	hello, err := clientHelloInfo(br)
 	if err != nil {
+		tcp.SetCloseReason(conn, clientHelloCloseReason(err))
 		conn.Close()
 		return
 	}
@@ -129,6 +141,7 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 	connData, err := tcpmuxer.NewConnData(hello.serverName, conn, hello.protos)
 	if err != nil {
 		log.WithoutContext().Errorf("Error while reading TCP connection data: %v", err)
+		tcp.SetCloseReason(conn, tcp.CloseReasonError)
 		conn.Close()
 		return
 	}
@@ -137,10 +150,11 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 		handler, _ := r.muxerTCP.Match(connData)
 		switch {
 		case handler != nil:
-			handler.ServeTCP(r.GetConn(conn, hello.peeked))
+			handler.ServeTCP(r.connTrace.Trace(tcp.WithIdleTimeout(r.GetConn(conn, hello.peeked), r.tcpIdleTimeout), hello.serverName))
 		case r.httpForwarder != nil:
 			r.httpForwarder.ServeTCP(r.GetConn(conn, hello.peeked))
 		default:
+			tcp.SetCloseReason(conn, tcp.CloseReasonNoRoute)
 			conn.Close()
 		}
 		return
@@ -163,7 +177,7 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 	// Contains also TCP TLS passthrough routes.
 	handlerTCPTLS, catchAllTCPTLS := r.muxerTCPTLS.Match(connData)
 	if handlerTCPTLS != nil && !catchAllTCPTLS {
-		handlerTCPTLS.ServeTCP(r.GetConn(conn, hello.peeked))
+		handlerTCPTLS.ServeTCP(r.connTrace.Trace(tcp.WithIdleTimeout(r.GetConn(conn, hello.peeked), r.tcpIdleTimeout), hello.serverName))
 		return
 	}
 
@@ -177,7 +191,7 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 
 	// Fallback on TCP TLS catchAll.
 	if handlerTCPTLS != nil {
-		handlerTCPTLS.ServeTCP(r.GetConn(conn, hello.peeked))
+		handlerTCPTLS.ServeTCP(r.connTrace.Trace(tcp.WithIdleTimeout(r.GetConn(conn, hello.peeked), r.tcpIdleTimeout), hello.serverName))
 		return
 	}
 
@@ -187,12 +201,13 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 		return
 	}
 
+	tcp.SetCloseReason(conn, tcp.CloseReasonNoRoute)
 	conn.Close()
 }
 
 // AddRoute defines a handler for the given rule.
-func (r *Router) AddRoute(rule string, priority int, target tcp.Handler) error {
-	return r.muxerTCP.AddRoute(rule, priority, target)
+func (r *Router) AddRoute(rule string, priority int, target tcp.Handler, conditions ...func() bool) error {
+	return r.muxerTCP.AddRoute(rule, priority, target, conditions...)
 }
 
 // AddHTTPTLSConfig defines a handler for a given sniHost and sets the matching tlsConfig.
@@ -279,6 +294,35 @@ func (r *Router) SetHTTPHandler(handler http.Handler) {
 	r.httpHandler = handler
 }
 
+// SetConnTrace sets the filters selecting the connections handed to the TCP handlers which are traced.
+func (r *Router) SetConnTrace(connTrace *conntrace.Filters) {
+	r.connTrace = connTrace
+}
+
+// SetTCPIdleTimeout sets the idle timeout of the connections handed to the TCP handlers.
+func (r *Router) SetTCPIdleTimeout(timeout time.Duration) {
+	r.tcpIdleTimeout = timeout
+}
+
+// SetTLSKeyLogWriter sets the writer of the TLS session secrets, in the NSS key log format,
+// on all the TLS configs of the router.
+// It must be called before the router handles any connection.
+func (r *Router) SetTLSKeyLogWriter(w io.Writer) {
+	if r.httpsTLSConfig != nil {
+		r.httpsTLSConfig.KeyLogWriter = w
+	}
+
+	for _, config := range r.hostHTTPTLSConfig {
+		if config != nil {
+			config.KeyLogWriter = w
+		}
+	}
+
+	for _, config := range r.tcpTLSConfigs {
+		config.KeyLogWriter = w
+	}
+}
+
 // SetHTTPSHandler attaches https handlers on the router.
 func (r *Router) SetHTTPSHandler(handler http.Handler, config *tls.Config) {
 	r.httpsHandler = handler
@@ -311,6 +355,35 @@ func (c *Conn) Read(p []byte) (n int, err error) {
 	return c.WriteCloser.Read(p)
 }
 
+// Unwrap returns the underlying connection, along with the peeked bytes not consumed yet,
+// which are handed over to the caller.
+func (c *Conn) Unwrap() (tcp.WriteCloser, []byte) {
+	peeked := c.Peeked
+	c.Peeked = nil
+
+	return c.WriteCloser, peeked
+}
+
+// NetConn returns the underlying connection.
+func (c *Conn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+// clientHelloCloseReason returns the reason why the connection ended before its first byte could be read.
+func clientHelloCloseReason(err error) tcp.CloseReason {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, io.EOF):
+		return tcp.CloseReasonClientEOF
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return tcp.CloseReasonTimeout
+	case errors.Is(err, syscall.ECONNRESET):
+		return tcp.CloseReasonClientReset
+	default:
+		return tcp.CloseReasonError
+	}
+}
+
 type clientHello struct {
 	serverName string   // SNI server name
 	protos     []string // ALPN protocols list
@@ -162,7 +162,7 @@ func Test_Routing(t *testing.T) {
 		},
 	}
 
-	serviceManager := tcp.NewManager(conf)
+	serviceManager := tcp.NewManager(conf, nil, nil, nil)
 
 	// Creates the tlsManager and defines the TLS 1.0 and 1.2 TLSOptions.
 	tlsManager := traefiktls.NewManager()
@@ -183,10 +183,10 @@ func Test_Routing(t *testing.T) {
 		},
 		[]*traefiktls.CertAndStores{})
 
-	middlewaresBuilder := tcpmiddleware.NewBuilder(conf.TCPMiddlewares)
+	middlewaresBuilder := tcpmiddleware.NewBuilder(conf.TCPMiddlewares, nil, nil)
 
 	manager := NewManager(conf, serviceManager, middlewaresBuilder,
-		nil, nil, tlsManager)
+		nil, nil, tlsManager, ManagerOptions{})
 
 	type checkCase struct {
 		checkRouter
@@ -922,3 +922,26 @@ func checkHTTPSTLS10(addr string, timeout time.Duration) error {
 func checkHTTPSTLS12(addr string, timeout time.Duration) error {
 	return checkHTTPS(addr, timeout, tls.VersionTLS12)
 }
+
+func TestRouter_SetTLSKeyLogWriter(t *testing.T) {
+	router, err := NewRouter()
+	require.NoError(t, err)
+
+	httpsTLSConfig := &tls.Config{}
+	router.SetHTTPSHandler(http.NotFoundHandler(), httpsTLSConfig)
+
+	hostTLSConfig := &tls.Config{}
+	router.AddHTTPTLSConfig("foo.localhost", hostTLSConfig)
+	router.AddHTTPTLSConfig("broken.localhost", nil)
+
+	tcpTLSConfig := &tls.Config{}
+	router.tcpTLSConfigs = append(router.tcpTLSConfigs, tcpTLSConfig)
+
+	var keyLog bytes.Buffer
+	router.SetTLSKeyLogWriter(&keyLog)
+
+	assert.Same(t, &keyLog, httpsTLSConfig.KeyLogWriter)
+	assert.Same(t, &keyLog, hostTLSConfig.KeyLogWriter)
+	assert.Same(t, &keyLog, tcpTLSConfig.KeyLogWriter)
+	assert.Nil(t, router.hostHTTPTLSConfig["broken.localhost"])
+}
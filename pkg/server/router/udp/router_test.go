@@ -117,7 +117,7 @@ func TestRuntimeConfiguration(t *testing.T) {
 				UDPServices: test.serviceConfig,
 				UDPRouters:  test.routerConfig,
 			}
-			serviceManager := udp.NewManager(conf)
+			serviceManager := udp.NewManager(conf, nil)
 			routerManager := NewManager(conf, serviceManager)
 
 			_ = routerManager.BuildHandlers(context.Background(), entryPoints)
@@ -3,18 +3,27 @@ package server
 import (
 	"context"
 
+	"github.com/traefik/traefik/v2/pkg/bandwidth"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/connections"
+	"github.com/traefik/traefik/v2/pkg/conntrace"
+	"github.com/traefik/traefik/v2/pkg/dnsresolver"
+	"github.com/traefik/traefik/v2/pkg/egress"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/maintenance"
 	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/overrides"
 	"github.com/traefik/traefik/v2/pkg/server/middleware"
 	tcpmiddleware "github.com/traefik/traefik/v2/pkg/server/middleware/tcp"
 	"github.com/traefik/traefik/v2/pkg/server/router"
 	tcprouter "github.com/traefik/traefik/v2/pkg/server/router/tcp"
 	udprouter "github.com/traefik/traefik/v2/pkg/server/router/udp"
 	"github.com/traefik/traefik/v2/pkg/server/service"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/slowstart"
 	"github.com/traefik/traefik/v2/pkg/server/service/tcp"
 	"github.com/traefik/traefik/v2/pkg/server/service/udp"
+	"github.com/traefik/traefik/v2/pkg/tenant"
 	"github.com/traefik/traefik/v2/pkg/tls"
 	udptypes "github.com/traefik/traefik/v2/pkg/udp"
 )
@@ -29,13 +38,50 @@ type RouterFactory struct {
 
 	pluginBuilder middleware.PluginsBuilder
 
-	chainBuilder *middleware.ChainBuilder
-	tlsManager   *tls.Manager
+	chainBuilder  *middleware.ChainBuilder
+	tlsManager    *tls.Manager
+	tenantRollups *tenant.Rollups
+	accountant    *bandwidth.Accountant
+	maintenance   *maintenance.Flags
+	overrides     *overrides.Store
+	connTrace     *conntrace.Filters
+	connections   *connections.Registry
+	resolver      *dnsresolver.Resolver
+	egressPolicy  *egress.Policy
+
+	// tcpSlowStart records when the TCP servers were first seen, across the configurations.
+	tcpSlowStart *slowstart.Tracker
+
+	// middlewareInstances holds the instances of the middlewares of the routers, across the configurations.
+	middlewareInstances *middleware.Instances
+
+	// fips restricts the TLS connections of the middlewares to the FIPS-approved parameters.
+	fips bool
+}
+
+// RouterFactoryOptions holds the optional dependencies of a RouterFactory.
+type RouterFactoryOptions struct {
+	// TenantRollups, if any, aggregates the traffic statistics of the routers with a tenant.
+	TenantRollups *tenant.Rollups
+	// Accountant, if any, accounts the bytes transferred by the routers.
+	Accountant *bandwidth.Accountant
+	// Maintenance, if any, holds the routers put in maintenance through the API.
+	Maintenance *maintenance.Flags
+	// Overrides, if any, holds the parameters of the middlewares overridden through the API.
+	Overrides *overrides.Store
+	// ConnTrace, if any, selects the TCP connections traced through the API.
+	ConnTrace *conntrace.Filters
+	// Connections, if any, registers the TCP connections of the routers, for the API.
+	Connections *connections.Registry
+	// Resolver, if any, looks the hostnames of the TCP servers up instead of the resolver of the operating system.
+	Resolver *dnsresolver.Resolver
+	// EgressPolicy, if any, restricts the destinations the TCP servers are dialed at.
+	EgressPolicy *egress.Policy
 }
 
 // NewRouterFactory creates a new RouterFactory.
 func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *service.ManagerFactory, tlsManager *tls.Manager,
-	chainBuilder *middleware.ChainBuilder, pluginBuilder middleware.PluginsBuilder, metricsRegistry metrics.Registry,
+	chainBuilder *middleware.ChainBuilder, pluginBuilder middleware.PluginsBuilder, metricsRegistry metrics.Registry, opts RouterFactoryOptions,
 ) *RouterFactory {
 	var entryPointsTCP, entryPointsUDP []string
 	for name, cfg := range staticConfiguration.EntryPoints {
@@ -60,35 +106,82 @@ func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *
 		tlsManager:      tlsManager,
 		chainBuilder:    chainBuilder,
 		pluginBuilder:   pluginBuilder,
+		tenantRollups:   opts.TenantRollups,
+		accountant:      opts.Accountant,
+		maintenance:     opts.Maintenance,
+		overrides:       opts.Overrides,
+		connTrace:       opts.ConnTrace,
+		connections:     opts.Connections,
+		resolver:        opts.Resolver,
+		egressPolicy:    opts.EgressPolicy,
+		tcpSlowStart:    slowstart.NewTracker(),
+
+		middlewareInstances: middleware.NewInstances(),
 	}
 }
 
+// SetFIPS enables the FIPS mode, restricting the TLS connections of the middlewares to the FIPS-approved parameters.
+// It applies to the routers created by the next calls to CreateRouters.
+func (f *RouterFactory) SetFIPS(enabled bool) {
+	f.fips = enabled
+}
+
 // CreateRouters creates new TCPRouters and UDPRouters.
 func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string]*tcprouter.Router, map[string]udptypes.Handler) {
 	ctx := context.Background()
 
+	// The routers put in maintenance through the API are back in service with the new configuration.
+	f.maintenance.Reset()
+
+	// The middleware parameters overridden through the API are ephemeral, and give way to the new configuration.
+	f.overrides.Reset()
+
+	// The connections established with the routers of the previous configurations are reported as stale from now on.
+	f.connections.NextGeneration()
+
+	// The TCP services and middlewares are built first,
+	// as the HTTP routers with a tunnel and the WebSocket bridge services forward into them.
+	f.tcpSlowStart.NextGeneration()
+	svcTCPManager := tcp.NewManager(rtConf, f.tcpSlowStart, f.resolver, f.egressPolicy)
+
+	middlewaresTCPBuilder := tcpmiddleware.NewBuilder(rtConf.TCPMiddlewares, f.connTrace, f.metricsRegistry)
+
 	// HTTP
-	serviceManager := f.managerFactory.Build(rtConf)
+	serviceManager := f.managerFactory.Build(rtConf, svcTCPManager, middlewaresTCPBuilder)
 
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, f.pluginBuilder)
+	f.middlewareInstances.NextGeneration()
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, f.pluginBuilder, f.overrides, f.middlewareInstances)
+	middlewaresBuilder.SetFIPS(f.fips)
 
-	routerManager := router.NewManager(rtConf, serviceManager, middlewaresBuilder, f.chainBuilder, f.metricsRegistry, f.tlsManager)
+	routerManager := router.NewManager(rtConf, serviceManager, middlewaresBuilder, f.chainBuilder, f.metricsRegistry, f.tlsManager, router.ManagerOptions{
+		TenantRollups:         f.tenantRollups,
+		Accountant:            f.accountant,
+		Maintenance:           f.maintenance,
+		TCPServiceManager:     svcTCPManager,
+		TCPMiddlewaresBuilder: middlewaresTCPBuilder,
+	})
 
 	handlersNonTLS := routerManager.BuildHandlers(ctx, f.entryPointsTCP, false)
 	handlersTLS := routerManager.BuildHandlers(ctx, f.entryPointsTCP, true)
 
 	serviceManager.LaunchHealthCheck()
 
-	// TCP
-	svcTCPManager := tcp.NewManager(rtConf)
-
-	middlewaresTCPBuilder := tcpmiddleware.NewBuilder(rtConf.TCPMiddlewares)
+	// The reused middlewares forward to the handlers of the new configuration from now on.
+	f.middlewareInstances.Commit()
 
-	rtTCPManager := tcprouter.NewManager(rtConf, svcTCPManager, middlewaresTCPBuilder, handlersNonTLS, handlersTLS, f.tlsManager)
+	// TCP
+	rtTCPManager := tcprouter.NewManager(rtConf, svcTCPManager, middlewaresTCPBuilder, handlersNonTLS, handlersTLS, f.tlsManager, tcprouter.ManagerOptions{
+		TenantRollups:   f.tenantRollups,
+		Accountant:      f.accountant,
+		Maintenance:     f.maintenance,
+		MetricsRegistry: f.metricsRegistry,
+		ConnTrace:       f.connTrace,
+		Connections:     f.connections,
+	})
 	routersTCP := rtTCPManager.BuildHandlers(ctx, f.entryPointsTCP)
 
 	// UDP
-	svcUDPManager := udp.NewManager(rtConf)
+	svcUDPManager := udp.NewManager(rtConf, f.egressPolicy)
 	rtUDPManager := udprouter.NewManager(rtConf, svcUDPManager)
 	routersUDP := rtUDPManager.BuildHandlers(ctx, f.entryPointsUDP)
 
@@ -9,23 +9,30 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/containous/alice"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
 	"github.com/pires/go-proxyproto"
 	"github.com/sirupsen/logrus"
 	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/fairqueue"
 	"github.com/traefik/traefik/v2/pkg/ip"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
 	"github.com/traefik/traefik/v2/pkg/middlewares/forwardedheaders"
 	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/server/router"
 	tcprouter "github.com/traefik/traefik/v2/pkg/server/router/tcp"
+	"github.com/traefik/traefik/v2/pkg/sockopt"
+	"github.com/traefik/traefik/v2/pkg/strictparsing"
 	"github.com/traefik/traefik/v2/pkg/tcp"
 	"github.com/traefik/traefik/v2/pkg/types"
 	"golang.org/x/net/http2"
@@ -38,6 +45,9 @@ type httpForwarder struct {
 	net.Listener
 	connChan chan net.Conn
 	errChan  chan error
+
+	// strictParser inspects the framing of the requests of the connections, when the strict parsing is enabled.
+	strictParser *strictparsing.Parser
 }
 
 func newHTTPForwarder(ln net.Listener) *httpForwarder {
@@ -50,7 +60,7 @@ func newHTTPForwarder(ln net.Listener) *httpForwarder {
 
 // ServeTCP uses the connection to serve it later in "Accept".
 func (h *httpForwarder) ServeTCP(conn tcp.WriteCloser) {
-	h.connChan <- conn
+	h.connChan <- h.strictParser.WrapConn(conn)
 }
 
 // Accept retrieves a served connection in ServeTCP.
@@ -67,7 +77,7 @@ func (h *httpForwarder) Accept() (net.Conn, error) {
 type TCPEntryPoints map[string]*TCPEntryPoint
 
 // NewTCPEntryPoints creates a new TCPEntryPoints.
-func NewTCPEntryPoints(entryPointsConfig static.EntryPoints, hostResolverConfig *types.HostResolverConfig) (TCPEntryPoints, error) {
+func NewTCPEntryPoints(entryPointsConfig static.EntryPoints, hostResolverConfig *types.HostResolverConfig, metricsRegistry metrics.Registry) (TCPEntryPoints, error) {
 	serverEntryPointsTCP := make(TCPEntryPoints)
 	for entryPointName, config := range entryPointsConfig {
 		protocol, err := config.GetProtocol()
@@ -81,7 +91,11 @@ func NewTCPEntryPoints(entryPointsConfig static.EntryPoints, hostResolverConfig
 
 		ctx := log.With(context.Background(), log.Str(log.EntryPointName, entryPointName))
 
-		serverEntryPointsTCP[entryPointName], err = NewTCPEntryPoint(ctx, config, hostResolverConfig)
+		shardConnsCounter := metricsRegistry.EntryPointShardConnsCounter().With("entrypoint", entryPointName)
+		closedConnsCounter := metricsRegistry.EntryPointClosedConnsCounter().With("entrypoint", entryPointName)
+		rejectedReqsCounter := metricsRegistry.EntryPointRejectedReqsCounter().With("entrypoint", entryPointName)
+
+		serverEntryPointsTCP[entryPointName], err = NewTCPEntryPoint(ctx, config, hostResolverConfig, shardConnsCounter, closedConnsCounter, rejectedReqsCounter)
 		if err != nil {
 			return nil, fmt.Errorf("error while building entryPoint %s: %w", entryPointName, err)
 		}
@@ -130,17 +144,35 @@ type TCPEntryPoint struct {
 	switcher               *tcp.HandlerSwitcher
 	transportConfiguration *static.EntryPointsTransport
 	tracker                *connectionTracker
+	closedConnsCounter     gokitmetrics.Counter
 	httpServer             *httpServer
 	httpsServer            *httpServer
 
 	http3Server *http3server
+
+	// fairQueue schedules the reads and writes of the connections, when the fair queueing is enabled.
+	fairQueue *fairqueue.Queue
+
+	// keyLogFile receives the TLS session secrets of the entry point, when the key logging is enabled.
+	keyLogFile *os.File
+
+	// connsCtx is the parent of the contexts of the connections, canceled when the shutdown grace period is over.
+	connsCtx    context.Context
+	cancelConns context.CancelFunc
 }
 
 // NewTCPEntryPoint creates a new TCPEntryPoint.
-func NewTCPEntryPoint(ctx context.Context, configuration *static.EntryPoint, hostResolverConfig *types.HostResolverConfig) (*TCPEntryPoint, error) {
+// The shardConnsCounter counts the connections accepted by each shard, when the entry point is sharded,
+// the closedConnsCounter counts the connections which ended for a recorded reason, and whether they used Multipath TCP,
+// and the rejectedReqsCounter counts the requests rejected by the strict parsing, by reason.
+func NewTCPEntryPoint(ctx context.Context, configuration *static.EntryPoint, hostResolverConfig *types.HostResolverConfig, shardConnsCounter, closedConnsCounter, rejectedReqsCounter gokitmetrics.Counter) (*TCPEntryPoint, error) {
 	tracker := newConnectionTracker()
 
-	listener, err := buildListener(ctx, configuration)
+	if closedConnsCounter == nil {
+		closedConnsCounter = discard.NewCounter()
+	}
+
+	listener, err := buildListener(ctx, configuration, shardConnsCounter)
 	if err != nil {
 		return nil, fmt.Errorf("error preparing server: %w", err)
 	}
@@ -149,14 +181,22 @@ func NewTCPEntryPoint(ctx context.Context, configuration *static.EntryPoint, hos
 
 	reqDecorator := requestdecorator.New(hostResolverConfig)
 
-	httpServer, err := createHTTPServer(ctx, listener, configuration, true, reqDecorator)
+	var strictParser *strictparsing.Parser
+	if configuration.HTTP.StrictParsing != nil {
+		strictParser, err = strictparsing.New(configuration.HTTP.StrictParsing, rejectedReqsCounter)
+		if err != nil {
+			return nil, fmt.Errorf("strict parsing: %w", err)
+		}
+	}
+
+	httpServer, err := createHTTPServer(ctx, listener, configuration, true, reqDecorator, strictParser)
 	if err != nil {
 		return nil, fmt.Errorf("error preparing http server: %w", err)
 	}
 
 	rt.SetHTTPForwarder(httpServer.Forwarder)
 
-	httpsServer, err := createHTTPServer(ctx, listener, configuration, false, reqDecorator)
+	httpsServer, err := createHTTPServer(ctx, listener, configuration, false, reqDecorator, strictParser)
 	if err != nil {
 		return nil, fmt.Errorf("error preparing https server: %w", err)
 	}
@@ -171,14 +211,41 @@ func NewTCPEntryPoint(ctx context.Context, configuration *static.EntryPoint, hos
 	tcpSwitcher := &tcp.HandlerSwitcher{}
 	tcpSwitcher.Switch(rt)
 
+	var fairQueue *fairqueue.Queue
+	if configuration.FairQueueing != nil {
+		if configuration.FairQueueing.Bandwidth <= 0 {
+			return nil, errors.New("fair queueing: the bandwidth must be greater than zero")
+		}
+
+		fairQueue = fairqueue.New(configuration.FairQueueing.Bandwidth, configuration.FairQueueing.Quantum)
+	}
+
+	var keyLogFile *os.File
+	if configuration.TLSKeyLogFile != "" {
+		keyLogFile, err = os.OpenFile(configuration.TLSKeyLogFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("opening TLS key log file: %w", err)
+		}
+
+		log.FromContext(ctx).Warnf("TLS key logging is enabled: the session secrets of the entry point are written to %s, "+
+			"which allows anyone reading it to decrypt the captured traffic. Never enable it in production.", configuration.TLSKeyLogFile)
+	}
+
+	connsCtx, cancelConns := context.WithCancel(context.Background())
+
 	return &TCPEntryPoint{
 		listener:               listener,
 		switcher:               tcpSwitcher,
 		transportConfiguration: configuration.Transport,
 		tracker:                tracker,
+		connsCtx:               connsCtx,
+		cancelConns:            cancelConns,
+		closedConnsCounter:     closedConnsCounter,
 		httpServer:             httpServer,
 		httpsServer:            httpsServer,
 		http3Server:            h3Server,
+		fairQueue:              fairQueue,
+		keyLogFile:             keyLogFile,
 	}, nil
 }
 
@@ -191,8 +258,39 @@ func (e *TCPEntryPoint) Start(ctx context.Context) {
 		go func() { _ = e.http3Server.Start() }()
 	}
 
+	if e.fairQueue != nil {
+		e.fairQueue.Start()
+	}
+
+	listeners := []net.Listener{e.listener}
+	if sharded, ok := e.listener.(*shardedListener); ok {
+		listeners = sharded.shards
+	}
+
+	errCh := make(chan error, len(listeners))
+	for _, listener := range listeners {
+		listener := listener
+		go func() { errCh <- e.serve(logger, listener) }()
+	}
+
+	err := <-errCh
+	if len(listeners) > 1 {
+		// Stops the accept loops of the other shards.
+		_ = e.listener.Close()
+		for i := 1; i < len(listeners); i++ {
+			<-errCh
+		}
+	}
+
+	e.httpServer.Forwarder.errChan <- err
+	e.httpsServer.Forwarder.errChan <- err
+}
+
+// serve accepts the connections of the listener until it fails,
+// and returns the error which stopped it.
+func (e *TCPEntryPoint) serve(logger log.Logger, listener net.Listener) error {
 	for {
-		conn, err := e.listener.Accept()
+		conn, err := listener.Accept()
 		if err != nil {
 			logger.Error(err)
 
@@ -206,10 +304,7 @@ func (e *TCPEntryPoint) Start(ctx context.Context) {
 				continue
 			}
 
-			e.httpServer.Forwarder.errChan <- err
-			e.httpsServer.Forwarder.errChan <- err
-
-			return
+			return err
 		}
 
 		writeCloser, err := writeCloser(conn)
@@ -217,6 +312,12 @@ func (e *TCPEntryPoint) Start(ctx context.Context) {
 			panic(err)
 		}
 
+		if e.fairQueue != nil {
+			writeCloser = e.fairQueue.Wrap(writeCloser)
+		}
+
+		mptcp := strconv.FormatBool(isMultipathTCP(conn))
+
 		safe.Go(func() {
 			// Enforce read/write deadlines at the connection level,
 			// because when we're peeking the first byte to determine whether we are doing TLS,
@@ -235,7 +336,10 @@ func (e *TCPEntryPoint) Start(ctx context.Context) {
 				}
 			}
 
-			e.switcher.ServeTCP(newTrackedConnection(writeCloser, e.tracker))
+			e.switcher.ServeTCP(newTrackedConnection(e.connsCtx, writeCloser, e.tracker, func(reason tcp.CloseReason) {
+				logger.Debugf("Connection from %s closed: %s (multipath TCP: %s)", writeCloser.RemoteAddr(), reason, mptcp)
+				e.closedConnsCounter.With("reason", string(reason), "mptcp", mptcp).Add(1)
+			}))
 		})
 	}
 }
@@ -301,18 +405,34 @@ func (e *TCPEntryPoint) Shutdown(ctx context.Context) {
 			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 				logger.Debugf("Server failed to shutdown before deadline because: %s", err)
 			}
+			e.cancelConns()
 			e.tracker.Close()
 		}()
 	}
 
 	wg.Wait()
 	cancel()
+
+	if e.fairQueue != nil {
+		e.fairQueue.Stop()
+	}
+
+	if e.keyLogFile != nil {
+		if err := e.keyLogFile.Close(); err != nil {
+			logger.Errorf("Error while closing TLS key log file: %v", err)
+		}
+	}
 }
 
 // SwitchRouter switches the TCP router handler.
 func (e *TCPEntryPoint) SwitchRouter(rt *tcprouter.Router) {
+	rt.SetTCPIdleTimeout(time.Duration(e.transportConfiguration.RespondingTimeouts.TCPIdleTimeout))
 	rt.SetHTTPForwarder(e.httpServer.Forwarder)
 
+	if e.keyLogFile != nil {
+		rt.SetTLSKeyLogWriter(e.keyLogFile)
+	}
+
 	httpHandler := rt.GetHTTPHandler()
 	if httpHandler == nil {
 		httpHandler = router.BuildDefaultHTTPRouter()
@@ -352,6 +472,10 @@ func (c *writeCloserWrapper) CloseWrite() error {
 func writeCloser(conn net.Conn) (tcp.WriteCloser, error) {
 	switch typedConn := conn.(type) {
 	case *proxyproto.Conn:
+		if underlying, ok := typedConn.UnixConn(); ok {
+			return &writeCloserWrapper{writeCloser: underlying, Conn: typedConn}, nil
+		}
+
 		underlying, ok := typedConn.TCPConn()
 		if !ok {
 			return nil, fmt.Errorf("underlying connection is not a tcp connection")
@@ -359,11 +483,28 @@ func writeCloser(conn net.Conn) (tcp.WriteCloser, error) {
 		return &writeCloserWrapper{writeCloser: underlying, Conn: typedConn}, nil
 	case *net.TCPConn:
 		return typedConn, nil
+	case *net.UnixConn:
+		return typedConn, nil
 	default:
 		return nil, fmt.Errorf("unknown connection type %T", typedConn)
 	}
 }
 
+// isMultipathTCP reports whether the accepted connection uses Multipath TCP.
+func isMultipathTCP(conn net.Conn) bool {
+	if proxyConn, ok := conn.(*proxyproto.Conn); ok {
+		conn = proxyConn.Raw()
+	}
+
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return false
+	}
+
+	mptcp, err := tcpConn.MultipathTCP()
+	return err == nil && mptcp
+}
+
 // tcpKeepAliveListener sets TCP keep-alive timeouts on accepted
 // connections.
 type tcpKeepAliveListener struct {
@@ -422,19 +563,64 @@ func buildProxyProtocolListener(ctx context.Context, entryPoint *static.EntryPoi
 	return proxyListener, nil
 }
 
-func buildListener(ctx context.Context, entryPoint *static.EntryPoint) (net.Listener, error) {
-	listener, err := net.Listen("tcp", entryPoint.GetAddress())
-	if err != nil {
-		return nil, fmt.Errorf("error opening listener: %w", err)
-	}
+func buildListener(ctx context.Context, entryPoint *static.EntryPoint, shardConnsCounter gokitmetrics.Counter) (net.Listener, error) {
+	var listener net.Listener
+	if path, ok := entryPoint.GetUnixSocketPath(); ok {
+		if entryPoint.Sharding != nil {
+			return nil, errors.New("sharding is not supported on a Unix domain socket")
+		}
+
+		ln, err := buildUnixListener(path, entryPoint.UnixSocket)
+		if err != nil {
+			return nil, fmt.Errorf("error opening listener: %w", err)
+		}
+
+		listener = ln
+	} else if entryPoint.Sharding != nil {
+		shardedListener, err := buildShardedListener(ctx, entryPoint, shardConnsCounter)
+		if err != nil {
+			return nil, fmt.Errorf("error opening sharded listener: %w", err)
+		}
 
-	listener = tcpKeepAliveListener{listener.(*net.TCPListener)}
+		listener = shardedListener
+	} else {
+		var lc net.ListenConfig
+		if entryPoint.MultipathTCP {
+			lc.SetMultipathTCP(true)
+		}
+		if entryPoint.CongestionControl != "" {
+			lc.Control = sockopt.CongestionControl(entryPoint.CongestionControl)
+		}
 
-	if entryPoint.ProxyProtocol != nil {
-		listener, err = buildProxyProtocolListener(ctx, entryPoint, listener)
+		ln, err := lc.Listen(ctx, "tcp", entryPoint.GetAddress())
 		if err != nil {
-			return nil, fmt.Errorf("error creating proxy protocol listener: %w", err)
+			return nil, fmt.Errorf("error opening listener: %w", err)
 		}
+
+		listener = tcpKeepAliveListener{ln.(*net.TCPListener)}
+	}
+
+	if entryPoint.ProxyProtocol == nil {
+		return listener, nil
+	}
+
+	if sharded, ok := listener.(*shardedListener); ok {
+		for i, shard := range sharded.shards {
+			proxyListener, err := buildProxyProtocolListener(ctx, entryPoint, shard)
+			if err != nil {
+				_ = sharded.Close()
+				return nil, fmt.Errorf("error creating proxy protocol listener: %w", err)
+			}
+
+			sharded.shards[i] = proxyListener
+		}
+
+		return sharded, nil
+	}
+
+	listener, err := buildProxyProtocolListener(ctx, entryPoint, listener)
+	if err != nil {
+		return nil, fmt.Errorf("error creating proxy protocol listener: %w", err)
 	}
 	return listener, nil
 }
@@ -514,7 +700,7 @@ type httpServer struct {
 	Switcher  *middlewares.HTTPHandlerSwitcher
 }
 
-func createHTTPServer(ctx context.Context, ln net.Listener, configuration *static.EntryPoint, withH2c bool, reqDecorator *requestdecorator.RequestDecorator) (*httpServer, error) {
+func createHTTPServer(ctx context.Context, ln net.Listener, configuration *static.EntryPoint, withH2c bool, reqDecorator *requestdecorator.RequestDecorator, strictParser *strictparsing.Parser) (*httpServer, error) {
 	if configuration.HTTP2.MaxConcurrentStreams < 0 {
 		return nil, errors.New("max concurrent streams value must be greater than or equal to zero")
 	}
@@ -530,11 +716,21 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 	handler, err = forwardedheaders.NewXForwarded(
 		configuration.ForwardedHeaders.Insecure,
 		configuration.ForwardedHeaders.TrustedIPs,
+		configuration.ForwardedHeaders.Forwarded,
 		next)
 	if err != nil {
 		return nil, err
 	}
 
+	if configuration.ClientIP != nil {
+		strategy, err := ip.NewChainStrategy(configuration.ClientIP.TrustedProxies, configuration.ClientIP.Hops, configuration.ClientIP.Headers)
+		if err != nil {
+			return nil, fmt.Errorf("client IP: %w", err)
+		}
+
+		handler = deriveClientIP(strategy, handler)
+	}
+
 	handler = denyFragment(handler)
 	if configuration.HTTP.EncodeQuerySemicolons {
 		handler = encodeQuerySemicolons(handler)
@@ -548,6 +744,8 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 		})
 	}
 
+	handler = strictParser.WrapHandler(handler)
+
 	serverHTTP := &http.Server{
 		Handler:      handler,
 		ErrorLog:     httpServerLogger,
@@ -556,6 +754,10 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 		IdleTimeout:  time.Duration(configuration.Transport.RespondingTimeouts.IdleTimeout),
 	}
 
+	if strictParser != nil {
+		serverHTTP.ConnContext = strictparsing.ConnContext
+	}
+
 	// ConfigureServer configures HTTP/2 with the MaxConcurrentStreams option for the given server.
 	// Also keeping behavior the same as
 	// https://cs.opensource.google/go/go/+/refs/tags/go1.17.7:src/net/http/server.go;l=3262
@@ -571,6 +773,7 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 	}
 
 	listener := newHTTPForwarder(ln)
+	listener.strictParser = strictParser
 	go func() {
 		err := serverHTTP.Serve(listener)
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -584,24 +787,73 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 	}, nil
 }
 
-func newTrackedConnection(conn tcp.WriteCloser, tracker *connectionTracker) *trackedConnection {
+func newTrackedConnection(ctx context.Context, conn tcp.WriteCloser, tracker *connectionTracker, onClose func(reason tcp.CloseReason)) *trackedConnection {
 	tracker.AddConnection(conn)
+
+	ctx, cancel := context.WithCancel(ctx)
 	return &trackedConnection{
 		WriteCloser: conn,
 		tracker:     tracker,
+		ctx:         ctx,
+		cancel:      cancel,
+		onClose:     onClose,
 	}
 }
 
 type trackedConnection struct {
 	tracker *connectionTracker
 	tcp.WriteCloser
+
+	// ctx is done when the connection is closed, or when the connections of the entry point are canceled.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// onClose is called once, on the first Close, with the reason why the connection ended, if one was recorded.
+	onClose   func(reason tcp.CloseReason)
+	closeOnce sync.Once
+
+	reasonMu sync.Mutex
+	reason   tcp.CloseReason
+}
+
+// SetCloseReason records the reason why the connection ended, unless a reason has already been recorded.
+func (t *trackedConnection) SetCloseReason(reason tcp.CloseReason) {
+	t.reasonMu.Lock()
+	defer t.reasonMu.Unlock()
+
+	if t.reason == "" {
+		t.reason = reason
+	}
+}
+
+// Context returns the context of the connection, which is done when the connection is closed,
+// or when the connections of the entry point are canceled, at the end of the shutdown grace period.
+func (t *trackedConnection) Context() context.Context {
+	return t.ctx
 }
 
 func (t *trackedConnection) Close() error {
 	t.tracker.RemoveConnection(t.WriteCloser)
+	t.cancel()
+
+	t.closeOnce.Do(func() {
+		t.reasonMu.Lock()
+		reason := t.reason
+		t.reasonMu.Unlock()
+
+		if reason != "" && t.onClose != nil {
+			t.onClose(reason)
+		}
+	})
+
 	return t.WriteCloser.Close()
 }
 
+// Unwrap returns the tracked connection, as the tracking only needs to know when the connection is closed.
+func (t *trackedConnection) Unwrap() (tcp.WriteCloser, []byte) {
+	return t.WriteCloser, nil
+}
+
 // This function is inspired by http.AllowQuerySemicolons.
 func encodeQuerySemicolons(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
@@ -622,6 +874,14 @@ func encodeQuerySemicolons(h http.Handler) http.Handler {
 	})
 }
 
+// deriveClientIP stores the client IP, derived from the chain of the trusted proxies, in the context of the requests,
+// before the forwarding headers are rewritten.
+func deriveClientIP(strategy *ip.ChainStrategy, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		h.ServeHTTP(rw, req.WithContext(ip.WithClientIP(req.Context(), strategy.GetIP(req))))
+	})
+}
+
 // When go receives an HTTP request, it assumes the absence of fragment URL.
 // However, it is still possible to send a fragment in the request.
 // In this case, Traefik will encode the '#' character, altering the request's intended meaning.
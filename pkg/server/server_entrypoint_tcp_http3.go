@@ -33,6 +33,10 @@ func newHTTP3Server(ctx context.Context, configuration *static.EntryPoint, https
 		return nil, errors.New("advertised port must be greater than or equal to zero")
 	}
 
+	if _, ok := configuration.GetUnixSocketPath(); ok {
+		return nil, errors.New("HTTP/3 is not supported on a Unix domain socket")
+	}
+
 	conn, err := net.ListenPacket("udp", configuration.GetAddress())
 	if err != nil {
 		return nil, fmt.Errorf("starting listener: %w", err)
@@ -0,0 +1,23 @@
+//go:build !windows
+// +build !windows
+
+package server
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// reusePort enables SO_REUSEPORT on a listening socket, for the shards to share the same address.
+func reusePort(_, _ string, c syscall.RawConn) error {
+	var errOpt error
+	err := c.Control(func(fd uintptr) {
+		errOpt = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+	})
+	if err != nil {
+		return err
+	}
+
+	return errOpt
+}
@@ -0,0 +1,14 @@
+//go:build windows
+// +build windows
+
+package server
+
+import (
+	"errors"
+	"syscall"
+)
+
+// reusePort fails, as Windows does not spread the connections among the sockets sharing an address.
+func reusePort(_, _ string, _ syscall.RawConn) error {
+	return errors.New("sharding is not supported on Windows")
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/sockopt"
+)
+
+var errShardedAccept = errors.New("the connections of a sharded listener are accepted by each of its shards")
+
+// shardedListener is a listener made of several listeners sharing the same address,
+// the kernel spreading the incoming connections among them.
+// The entry point runs a separate accept loop on each shard, instead of accepting the connections of the listener itself.
+type shardedListener struct {
+	shards []net.Listener
+
+	closeOnce sync.Once
+}
+
+func buildShardedListener(ctx context.Context, entryPoint *static.EntryPoint, shardConnsCounter gokitmetrics.Counter) (*shardedListener, error) {
+	sharding := entryPoint.Sharding
+	if sharding.Shards < 1 {
+		return nil, fmt.Errorf("invalid number of shards: %d", sharding.Shards)
+	}
+
+	lc := net.ListenConfig{Control: reusePort}
+	if entryPoint.CongestionControl != "" {
+		lc.Control = sockopt.Chain(reusePort, sockopt.CongestionControl(entryPoint.CongestionControl))
+	}
+	if entryPoint.MultipathTCP {
+		lc.SetMultipathTCP(true)
+	}
+
+	l := &shardedListener{}
+
+	address := entryPoint.GetAddress()
+	for i := 0; i < sharding.Shards; i++ {
+		listener, err := lc.Listen(ctx, "tcp", address)
+		if err != nil {
+			_ = l.Close()
+			return nil, err
+		}
+
+		l.shards = append(l.shards, countingListener{
+			Listener: tcpKeepAliveListener{listener.(*net.TCPListener)},
+			counter:  shardConnsCounter.With("shard", strconv.Itoa(i)),
+		})
+
+		// The next shards share the port of the first one, when it is picked by the system.
+		address = listener.Addr().String()
+	}
+
+	log.FromContext(ctx).Infof("Accepting the connections with %d shards", sharding.Shards)
+
+	return l, nil
+}
+
+// Accept fails, as the connections are accepted by each of the shards.
+func (l *shardedListener) Accept() (net.Conn, error) {
+	return nil, errShardedAccept
+}
+
+// Close closes all the shards.
+func (l *shardedListener) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		for _, shard := range l.shards {
+			if errClose := shard.Close(); errClose != nil && err == nil {
+				err = errClose
+			}
+		}
+	})
+
+	return err
+}
+
+// Addr returns the address shared by the shards.
+func (l *shardedListener) Addr() net.Addr {
+	return l.shards[0].Addr()
+}
+
+// countingListener counts the connections it accepts.
+type countingListener struct {
+	net.Listener
+
+	counter gokitmetrics.Counter
+}
+
+func (l countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.counter.Add(1)
+	}
+
+	return conn, err
+}
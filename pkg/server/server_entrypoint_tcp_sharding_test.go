@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"net"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+func TestShardedListener(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sharding is not supported on Windows")
+	}
+
+	counter := &shardCounter{mu: &sync.Mutex{}, values: map[string]float64{}}
+
+	ln, err := buildShardedListener(context.Background(), &static.EntryPoint{
+		Address:  "127.0.0.1:0",
+		Sharding: &static.Sharding{Shards: 2},
+	}, counter)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	require.Len(t, ln.shards, 2)
+	assert.Equal(t, ln.shards[0].Addr(), ln.shards[1].Addr())
+
+	_, err = ln.Accept()
+	assert.ErrorIs(t, err, errShardedAccept)
+
+	accepted := make(chan net.Conn)
+	for _, shard := range ln.shards {
+		shard := shard
+		go func() {
+			for {
+				conn, err := shard.Accept()
+				if err != nil {
+					return
+				}
+				accepted <- conn
+			}
+		}()
+	}
+
+	for i := 0; i < 4; i++ {
+		client, err := net.Dial("tcp", ln.Addr().String())
+		require.NoError(t, err)
+
+		conn := <-accepted
+		assert.IsType(t, &net.TCPConn{}, conn)
+
+		_ = conn.Close()
+		_ = client.Close()
+	}
+
+	counter.mu.Lock()
+	assert.Equal(t, float64(4), counter.values["0"]+counter.values["1"])
+	counter.mu.Unlock()
+
+	require.NoError(t, ln.Close())
+
+	for _, shard := range ln.shards {
+		_, err = shard.Accept()
+		assert.ErrorIs(t, err, net.ErrClosed)
+	}
+}
+
+func TestBuildShardedListener_invalid(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		sharding static.Sharding
+	}{
+		{
+			desc:     "no shards",
+			sharding: static.Sharding{},
+		},
+		{
+			desc:     "negative shards",
+			sharding: static.Sharding{Shards: -1},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			sharding := test.sharding
+			_, err := buildShardedListener(context.Background(), &static.EntryPoint{
+				Address:  "127.0.0.1:0",
+				Sharding: &sharding,
+			}, generic.NewCounter("shard_connections"))
+			assert.Error(t, err)
+		})
+	}
+}
+
+// shardCounter records the counts by shard.
+type shardCounter struct {
+	mu     *sync.Mutex
+	values map[string]float64
+	shard  string
+}
+
+func (c *shardCounter) With(labelValues ...string) metrics.Counter {
+	shard := c.shard
+	for i := 0; i+1 < len(labelValues); i += 2 {
+		if labelValues[i] == "shard" {
+			shard = labelValues[i+1]
+		}
+	}
+
+	return &shardCounter{mu: c.mu, values: c.values, shard: shard}
+}
+
+func (c *shardCounter) Add(delta float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values[c.shard] += delta
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -79,7 +81,7 @@ func testShutdown(t *testing.T, router *tcprouter.Router) {
 		Transport:        epConfig,
 		ForwardedHeaders: &static.ForwardedHeaders{},
 		HTTP2:            &static.HTTP2Config{},
-	}, nil)
+	}, nil, nil, nil, nil)
 	require.NoError(t, err)
 
 	conn, err := startEntrypoint(entryPoint, router)
@@ -164,7 +166,7 @@ func TestReadTimeoutWithoutFirstByte(t *testing.T) {
 		Transport:        epConfig,
 		ForwardedHeaders: &static.ForwardedHeaders{},
 		HTTP2:            &static.HTTP2Config{},
-	}, nil)
+	}, nil, nil, nil, nil)
 	require.NoError(t, err)
 
 	router := &tcprouter.Router{}
@@ -201,7 +203,7 @@ func TestReadTimeoutWithFirstByte(t *testing.T) {
 		Transport:        epConfig,
 		ForwardedHeaders: &static.ForwardedHeaders{},
 		HTTP2:            &static.HTTP2Config{},
-	}, nil)
+	}, nil, nil, nil, nil)
 	require.NoError(t, err)
 
 	router := &tcprouter.Router{}
@@ -230,3 +232,147 @@ func TestReadTimeoutWithFirstByte(t *testing.T) {
 		t.Error("Timeout while read")
 	}
 }
+
+type pipeWriteCloser struct {
+	net.Conn
+}
+
+func (p pipeWriteCloser) CloseWrite() error {
+	return nil
+}
+
+func TestTrackedConnection_closeReason(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		reasons  []tcp.CloseReason
+		expected []tcp.CloseReason
+	}{
+		{
+			desc: "no reason",
+		},
+		{
+			desc:     "one reason",
+			reasons:  []tcp.CloseReason{tcp.CloseReasonBackendEOF},
+			expected: []tcp.CloseReason{tcp.CloseReasonBackendEOF},
+		},
+		{
+			desc:     "first reason wins",
+			reasons:  []tcp.CloseReason{tcp.CloseReasonPolicy, tcp.CloseReasonClientReset},
+			expected: []tcp.CloseReason{tcp.CloseReasonPolicy},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			client, server := net.Pipe()
+			t.Cleanup(func() { _ = client.Close() })
+
+			var reported []tcp.CloseReason
+			conn := newTrackedConnection(context.Background(), pipeWriteCloser{Conn: server}, newConnectionTracker(), func(reason tcp.CloseReason) {
+				reported = append(reported, reason)
+			})
+
+			// The reasons are recorded through the wrappers of the connection, as the router does.
+			wrapped := &tcprouter.Conn{WriteCloser: conn}
+			for _, reason := range test.reasons {
+				tcp.SetCloseReason(wrapped, reason)
+			}
+
+			require.NoError(t, conn.Close())
+			_ = conn.Close()
+
+			assert.Equal(t, test.expected, reported)
+		})
+	}
+}
+
+func TestUnixSocketEntryPoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "web.sock")
+
+	epConfig := &static.EntryPointsTransport{}
+	epConfig.SetDefaults()
+
+	entryPoint, err := NewTCPEntryPoint(context.Background(), &static.EntryPoint{
+		Address:          "unix://" + path,
+		Transport:        epConfig,
+		ForwardedHeaders: &static.ForwardedHeaders{},
+		HTTP2:            &static.HTTP2Config{},
+		UnixSocket:       &static.UnixSocketConfig{Mode: "0600"},
+	}, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	router := &tcprouter.Router{}
+	router.SetHTTPHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	go entryPoint.Start(context.Background())
+	entryPoint.SwitchRouter(router)
+	t.Cleanup(func() { entryPoint.Shutdown(context.Background()) })
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", path)
+		},
+	}}
+
+	resp, err := client.Get("http://localhost/")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestBuildListener_multipathTCP(t *testing.T) {
+	if enabled, err := os.ReadFile("/proc/sys/net/mptcp/enabled"); err != nil || strings.TrimSpace(string(enabled)) != "1" {
+		t.Skip("Multipath TCP is not enabled in the kernel")
+	}
+
+	testCases := []struct {
+		desc         string
+		multipathTCP bool
+	}{
+		{
+			desc:         "Multipath TCP enabled",
+			multipathTCP: true,
+		},
+		{
+			desc: "Multipath TCP disabled",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			listener, err := buildListener(context.Background(), &static.EntryPoint{
+				Address:      "127.0.0.1:0",
+				MultipathTCP: test.multipathTCP,
+			}, nil)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = listener.Close() })
+
+			dialer := net.Dialer{}
+			dialer.SetMultipathTCP(true)
+
+			conn, err := dialer.Dial("tcp", listener.Addr().String())
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			accepted, err := listener.Accept()
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = accepted.Close() })
+
+			assert.Equal(t, test.multipathTCP, isMultipathTCP(accepted))
+		})
+	}
+}
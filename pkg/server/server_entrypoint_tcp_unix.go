@@ -0,0 +1,101 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"strconv"
+
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/unixsocket"
+)
+
+// buildUnixListener listens on the Unix domain socket at the given path,
+// after removing the stale socket left by a previous process, if any,
+// and applies the file mode and the ownership of the configuration to the socket.
+func buildUnixListener(path string, config *static.UnixSocketConfig) (net.Listener, error) {
+	if err := unixsocket.RemoveStale(path); err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if config == nil {
+		return listener, nil
+	}
+
+	if err = applyUnixSocketConfig(path, config); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	return listener, nil
+}
+
+func applyUnixSocketConfig(path string, config *static.UnixSocketConfig) error {
+	if config.Mode != "" {
+		mode, err := strconv.ParseUint(config.Mode, 8, 32)
+		if err != nil {
+			return fmt.Errorf("invalid socket mode %q: %w", config.Mode, err)
+		}
+
+		if err = os.Chmod(path, os.FileMode(mode)); err != nil {
+			return err
+		}
+	}
+
+	if config.User == "" && config.Group == "" {
+		return nil
+	}
+
+	// -1 leaves the owner, or the group, unchanged.
+	uid, gid := -1, -1
+
+	if config.User != "" {
+		var err error
+		uid, err = lookupID(config.User, func(name string) (string, error) {
+			u, err := user.Lookup(name)
+			if err != nil {
+				return "", err
+			}
+			return u.Uid, nil
+		})
+		if err != nil {
+			return fmt.Errorf("invalid socket user %q: %w", config.User, err)
+		}
+	}
+
+	if config.Group != "" {
+		var err error
+		gid, err = lookupID(config.Group, func(name string) (string, error) {
+			g, err := user.LookupGroup(name)
+			if err != nil {
+				return "", err
+			}
+			return g.Gid, nil
+		})
+		if err != nil {
+			return fmt.Errorf("invalid socket group %q: %w", config.Group, err)
+		}
+	}
+
+	return os.Chown(path, uid, gid)
+}
+
+// lookupID returns the numeric ID of a user, or a group, given either as an ID, or as a name looked up with lookup.
+func lookupID(nameOrID string, lookup func(name string) (string, error)) (int, error) {
+	if id, err := strconv.Atoi(nameOrID); err == nil {
+		return id, nil
+	}
+
+	id, err := lookup(nameOrID)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(id)
+}
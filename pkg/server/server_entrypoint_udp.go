@@ -90,7 +90,15 @@ func NewUDPEntryPoint(cfg *static.EntryPoint) (*UDPEntryPoint, error) {
 		return nil, err
 	}
 
-	listener, err := udp.Listen("udp", addr, time.Duration(cfg.UDP.Timeout))
+	opts := udp.ListenOptions{ReplyFromDestination: cfg.UDP.ReplyFromDestination}
+	if cfg.UDP.SourceAddress != "" {
+		opts.SourceAddr, err = parseSourceAddress(cfg.UDP.SourceAddress)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	listener, err := udp.ListenWithOptions("udp", addr, time.Duration(cfg.UDP.Timeout), opts)
 	if err != nil {
 		return nil, err
 	}
@@ -134,3 +142,17 @@ func (ep *UDPEntryPoint) Shutdown(ctx context.Context) {
 func (ep *UDPEntryPoint) Switch(handler udp.Handler) {
 	ep.switcher.Switch(handler)
 }
+
+// parseSourceAddress parses the source address of a UDP entry point, which is an IP with an optional port.
+func parseSourceAddress(address string) (*net.UDPAddr, error) {
+	if ip := net.ParseIP(address); ip != nil {
+		return &net.UDPAddr{IP: ip}, nil
+	}
+
+	addr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source address %q: %w", address, err)
+	}
+
+	return addr, nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/unixsocket"
+	"golang.org/x/net/http2"
+)
+
+const (
+	// h2cDetectionInterval is the time for which the support of h2c by a server is remembered, before it is probed again.
+	h2cDetectionInterval = 5 * time.Minute
+
+	// h2cProbeTimeout is the time given to a server to answer the h2c connection preface.
+	h2cProbeTimeout = 5 * time.Second
+)
+
+type h2cSupport struct {
+	supported bool
+	probing   bool
+	expiresAt time.Time
+}
+
+// h2cDetector probes whether the servers support h2c with prior knowledge, and remembers it.
+type h2cDetector struct {
+	dialContext unixsocket.DialFunc
+
+	mu      sync.Mutex
+	servers map[string]*h2cSupport // Keyed by server address.
+}
+
+func newH2CDetector(dialContext unixsocket.DialFunc) *h2cDetector {
+	return &h2cDetector{
+		dialContext: dialContext,
+		servers:     make(map[string]*h2cSupport),
+	}
+}
+
+// supports reports whether the server at the given address is known to support h2c.
+// The server is probed in the background the first time, and once its support is not remembered anymore,
+// and it is considered not to support h2c in the meantime.
+func (d *h2cDetector) supports(addr string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	server, ok := d.servers[addr]
+	if !ok {
+		server = &h2cSupport{}
+		d.servers[addr] = server
+	}
+
+	if !server.probing && !time.Now().Before(server.expiresAt) {
+		server.probing = true
+		safe.Go(func() { d.probe(addr) })
+	}
+
+	return server.supported
+}
+
+// forget forgets that the server at the given address supports h2c, so that it is probed again,
+// for instance when a request sent over h2c failed.
+func (d *h2cDetector) forget(addr string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if server, ok := d.servers[addr]; ok && !server.probing {
+		server.supported = false
+		server.expiresAt = time.Time{}
+	}
+}
+
+func (d *h2cDetector) probe(addr string) {
+	supported := d.probeH2C(addr)
+
+	log.WithoutContext().Debugf("Server %s supports h2c: %t", addr, supported)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.servers[addr] = &h2cSupport{
+		supported: supported,
+		expiresAt: time.Now().Add(h2cDetectionInterval),
+	}
+}
+
+// probeH2C sends the HTTP/2 connection preface to the server at the given address,
+// which supports h2c if it answers with its SETTINGS frame.
+func (d *h2cDetector) probeH2C(addr string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), h2cProbeTimeout)
+	defer cancel()
+
+	conn, err := d.dialContext(ctx, "tcp", addr)
+	if err != nil {
+		log.WithoutContext().Debugf("Unable to probe h2c support of server %s: %v", addr, err)
+		return false
+	}
+	defer func() { _ = conn.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if _, err = io.WriteString(conn, http2.ClientPreface); err != nil {
+		return false
+	}
+
+	framer := http2.NewFramer(conn, conn)
+	if err = framer.WriteSettings(); err != nil {
+		return false
+	}
+
+	// The HTTP/1.1 servers answer with an error response, which is not a valid frame.
+	frame, err := framer.ReadFrame()
+	if err != nil {
+		return false
+	}
+
+	_, ok := frame.(*http2.SettingsFrame)
+	return ok
+}
+
+// h2cAddr returns the address of the server of a plain HTTP URL, with the default port if it has none.
+func h2cAddr(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+
+	return net.JoinHostPort(u.Hostname(), "80")
+}
@@ -0,0 +1,324 @@
+package canary
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// Default values of the canary configuration.
+const (
+	DefaultStepWeight  = 10
+	DefaultMaxWeight   = 100
+	DefaultInterval    = time.Minute
+	DefaultMinRequests = 10
+)
+
+// State is the state of a rollout.
+type State string
+
+// The states of a rollout.
+const (
+	StateProgressing State = "progressing"
+	StatePromoted    State = "promoted"
+	StateRolledBack  State = "rolledBack"
+)
+
+// WeightSetter sets the weight of the child services of a weighted load balancer.
+type WeightSetter interface {
+	SetWeight(name string, weight int)
+}
+
+// Controller drives the rollouts of the canary services of the weighted services,
+// across the configuration reloads, so that a rollout goes on where it was when the configuration changes.
+type Controller struct {
+	mu         sync.Mutex
+	generation uint64
+	rollouts   map[string]*Rollout
+}
+
+// NewController creates a new Controller.
+func NewController() *Controller {
+	return &Controller{
+		rollouts: make(map[string]*Rollout),
+	}
+}
+
+// NextGeneration must be called before building the weighted services of a new configuration.
+// It stops the rollouts that were not part of the previous configuration.
+func (c *Controller) NextGeneration() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, rollout := range c.rollouts {
+		if rollout.generation < c.generation {
+			rollout.stop()
+			delete(c.rollouts, name)
+			continue
+		}
+
+		rollout.detach()
+	}
+
+	c.generation++
+}
+
+// Rollout returns the rollout of the canary service of the given weighted service.
+// A new rollout is started when the service has none, or when its canary configuration has changed.
+func (c *Controller) Rollout(serviceName string, config dynamic.Canary) *Rollout {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	config = withDefaults(config)
+
+	rollout, ok := c.rollouts[serviceName]
+	if !ok || rollout.config != config {
+		if ok {
+			rollout.stop()
+		}
+
+		rollout = newRollout(serviceName, config)
+		rollout.start()
+		c.rollouts[serviceName] = rollout
+	}
+
+	rollout.generation = c.generation
+
+	return rollout
+}
+
+func withDefaults(config dynamic.Canary) dynamic.Canary {
+	if config.StepWeight <= 0 {
+		config.StepWeight = DefaultStepWeight
+	}
+
+	if config.MaxWeight <= 0 {
+		config.MaxWeight = DefaultMaxWeight
+	}
+
+	if config.Interval <= 0 {
+		config.Interval = ptypes.Duration(DefaultInterval)
+	}
+
+	if config.MinRequests <= 0 {
+		config.MinRequests = DefaultMinRequests
+	}
+
+	return config
+}
+
+// Rollout increases stepwise the weight of a canary service,
+// while analyzing its responses at each step, and sets it back to zero as soon as they exceed the thresholds.
+type Rollout struct {
+	serviceName string
+	config      dynamic.Canary
+	generation  uint64
+
+	mu        sync.Mutex
+	state     State
+	weight    int
+	balancers []WeightSetter
+	timer     *time.Timer
+	stopped   bool
+
+	// The responses of the canary service during the current step.
+	requests int
+	errors   int
+	latency  time.Duration
+
+	// now is used to shift the clock in tests.
+	now func() time.Time
+}
+
+func newRollout(serviceName string, config dynamic.Canary) *Rollout {
+	weight := config.StepWeight
+	if weight > config.MaxWeight {
+		weight = config.MaxWeight
+	}
+
+	return &Rollout{
+		serviceName: serviceName,
+		config:      config,
+		state:       StateProgressing,
+		weight:      weight,
+		now:         time.Now,
+	}
+}
+
+// Weight returns the current weight of the canary service.
+func (r *Rollout) Weight() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.weight
+}
+
+// State returns the current state of the rollout.
+func (r *Rollout) State() State {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.state
+}
+
+// Attach registers a load balancer whose canary service weight is set at each step.
+func (r *Rollout) Attach(balancer WeightSetter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.balancers = append(r.balancers, balancer)
+}
+
+// Wrap records the responses of the canary service handler, for the analysis of the steps.
+func (r *Rollout) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+
+		start := r.now()
+		next.ServeHTTP(recorder, req)
+		latency := r.now().Sub(start)
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.requests++
+		r.latency += latency
+		if recorder.status >= http.StatusInternalServerError {
+			r.errors++
+		}
+	})
+}
+
+// detach forgets the load balancers of the previous configuration.
+func (r *Rollout) detach() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.balancers = nil
+}
+
+func (r *Rollout) start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.schedule()
+}
+
+func (r *Rollout) stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stopped = true
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+}
+
+// schedule schedules the analysis of the current step.
+// It must be called with the lock held.
+func (r *Rollout) schedule() {
+	r.timer = time.AfterFunc(time.Duration(r.config.Interval), r.step)
+}
+
+// step analyzes the responses of the current step, and then either promotes the canary service to the next step,
+// or rolls it back.
+func (r *Rollout) step() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopped || r.state != StateProgressing {
+		return
+	}
+
+	logger := log.WithoutContext().WithField(log.ServiceName, r.serviceName)
+
+	requests, errors, latency := r.requests, r.errors, r.latency
+	r.requests, r.errors, r.latency = 0, 0, 0
+
+	if requests < r.config.MinRequests {
+		logger.Debugf("Extending the step of the canary service %s: %d requests out of %d", r.config.Service, requests, r.config.MinRequests)
+		r.schedule()
+		return
+	}
+
+	if reason := r.analyze(requests, errors, latency); reason != "" {
+		logger.Warnf("Rolling back the canary service %s: %s", r.config.Service, reason)
+		r.state = StateRolledBack
+		r.setWeight(0)
+		return
+	}
+
+	weight := r.weight + r.config.StepWeight
+	if weight >= r.config.MaxWeight {
+		logger.Infof("Promoting the canary service %s", r.config.Service)
+		r.state = StatePromoted
+		r.setWeight(r.config.MaxWeight)
+		return
+	}
+
+	logger.Debugf("Increasing the weight of the canary service %s to %d", r.config.Service, weight)
+	r.setWeight(weight)
+	r.schedule()
+}
+
+// analyze returns why the responses of a step exceed the thresholds, if they do.
+func (r *Rollout) analyze(requests, errors int, latency time.Duration) string {
+	if r.config.MaxErrorRatio > 0 {
+		ratio := float64(errors) / float64(requests)
+		if ratio > r.config.MaxErrorRatio {
+			return fmt.Sprintf("error ratio %.3f above %.3f", ratio, r.config.MaxErrorRatio)
+		}
+	}
+
+	if r.config.MaxLatency > 0 {
+		average := latency / time.Duration(requests)
+		if average > time.Duration(r.config.MaxLatency) {
+			return fmt.Sprintf("average latency %s above %s", average, time.Duration(r.config.MaxLatency))
+		}
+	}
+
+	return ""
+}
+
+// setWeight sets the weight of the canary service on all the load balancers.
+// It must be called with the lock held.
+func (r *Rollout) setWeight(weight int) {
+	r.weight = weight
+
+	for _, balancer := range r.balancers {
+		balancer.SetWeight(r.config.Service, weight)
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Flush sends any buffered data to the client.
+func (s *statusRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack hijacks the connection.
+func (s *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := s.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+
+	return nil, nil, fmt.Errorf("not a hijacker: %T", s.ResponseWriter)
+}
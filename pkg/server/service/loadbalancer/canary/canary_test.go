@@ -0,0 +1,143 @@
+package canary
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+type weights map[string]int
+
+func (w weights) SetWeight(name string, weight int) {
+	w[name] = weight
+}
+
+func TestRollout_step(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          dynamic.Canary
+		latency         time.Duration
+		statuses        []int
+		expectedState   State
+		expectedWeights []int
+	}{
+		{
+			desc:            "promoted stepwise",
+			config:          dynamic.Canary{Service: "canary", StepWeight: 20, MaxWeight: 50, MinRequests: 2, MaxErrorRatio: 0.1},
+			statuses:        []int{http.StatusOK, http.StatusOK},
+			expectedState:   StatePromoted,
+			expectedWeights: []int{40, 50, 50},
+		},
+		{
+			desc:            "rolled back on errors",
+			config:          dynamic.Canary{Service: "canary", StepWeight: 20, MaxWeight: 50, MinRequests: 2, MaxErrorRatio: 0.1},
+			statuses:        []int{http.StatusOK, http.StatusBadGateway},
+			expectedState:   StateRolledBack,
+			expectedWeights: []int{0, 0, 0},
+		},
+		{
+			desc:            "client errors are not errors",
+			config:          dynamic.Canary{Service: "canary", StepWeight: 20, MaxWeight: 50, MinRequests: 2, MaxErrorRatio: 0.1},
+			statuses:        []int{http.StatusOK, http.StatusNotFound},
+			expectedState:   StatePromoted,
+			expectedWeights: []int{40, 50, 50},
+		},
+		{
+			desc:            "rolled back on latency",
+			config:          dynamic.Canary{Service: "canary", StepWeight: 20, MaxWeight: 50, MinRequests: 2, MaxLatency: ptypes.Duration(time.Second)},
+			latency:         2 * time.Second,
+			statuses:        []int{http.StatusOK, http.StatusOK},
+			expectedState:   StateRolledBack,
+			expectedWeights: []int{0, 0, 0},
+		},
+		{
+			desc:            "extended without enough requests",
+			config:          dynamic.Canary{Service: "canary", StepWeight: 20, MaxWeight: 50, MinRequests: 3, MaxErrorRatio: 0.1},
+			statuses:        []int{http.StatusOK, http.StatusOK},
+			expectedState:   StateProgressing,
+			expectedWeights: []int{20, 20, 20},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rollout := newRollout("foo@file", withDefaults(test.config))
+			assert.Equal(t, 20, rollout.Weight())
+
+			now := time.Now()
+			rollout.now = func() time.Time {
+				now = now.Add(test.latency)
+				return now
+			}
+
+			balancer := weights{}
+			rollout.Attach(balancer)
+
+			var status int
+			handler := rollout.Wrap(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(status)
+			}))
+
+			var got []int
+			for range test.expectedWeights {
+				for _, status = range test.statuses {
+					handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+				}
+
+				rollout.step()
+				got = append(got, rollout.Weight())
+			}
+			rollout.stop()
+
+			assert.Equal(t, test.expectedWeights, got)
+			assert.Equal(t, test.expectedState, rollout.State())
+
+			if test.expectedState != StateProgressing {
+				assert.Equal(t, rollout.Weight(), balancer["canary"])
+			}
+		})
+	}
+}
+
+func TestController(t *testing.T) {
+	controller := NewController()
+
+	config := dynamic.Canary{Service: "canary", Interval: ptypes.Duration(time.Hour)}
+
+	controller.NextGeneration()
+	rollout := controller.Rollout("foo@file", config)
+	bar := controller.Rollout("bar@file", config)
+
+	rollout.Attach(weights{})
+	rollout.mu.Lock()
+	rollout.weight = 30
+	rollout.mu.Unlock()
+
+	// The rollout goes on with the same configuration.
+	controller.NextGeneration()
+	assert.Same(t, rollout, controller.Rollout("foo@file", config))
+	assert.Equal(t, 30, rollout.Weight())
+	assert.Empty(t, rollout.balancers)
+
+	// The rollout starts over with a new configuration.
+	config.StepWeight = 5
+	restarted := controller.Rollout("foo@file", config)
+	assert.NotSame(t, rollout, restarted)
+	assert.Equal(t, 5, restarted.Weight())
+	assert.True(t, rollout.stopped)
+
+	// The rollout of a service removed from the configuration is stopped.
+	controller.NextGeneration()
+	assert.True(t, bar.stopped)
+	assert.NotContains(t, controller.rollouts, "bar@file")
+
+	restarted.stop()
+}
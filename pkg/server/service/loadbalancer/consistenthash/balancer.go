@@ -0,0 +1,251 @@
+package consistenthash
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/vulcand/oxy/v2/roundrobin"
+)
+
+// Consistent hashing algorithms.
+const (
+	Ring   = "ring"
+	Maglev = "maglev"
+)
+
+// Balancer is an HTTP load balancer picking the server of a request by hashing one of its attributes.
+// The servers are held by a round-robin load balancer,
+// so that the health check manages them as usual.
+type Balancer struct {
+	*roundrobin.RoundRobin
+
+	next       http.Handler
+	key        func(req *http.Request) string
+	newTable   func(servers []string) table
+	loadFactor float64
+
+	mu sync.Mutex
+	// dirty tells whether the servers changed since the table was built.
+	dirty    bool
+	servers  []*url.URL
+	table    table
+	inFlight map[string]int64
+	total    int64
+}
+
+// NewBalancer creates a new Balancer forwarding the requests to next.
+func NewBalancer(next http.Handler, config dynamic.ConsistentHash) (*Balancer, error) {
+	key, err := keyFunc(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var newTable func(servers []string) table
+	switch config.Algorithm {
+	case "", Ring:
+		newTable = func(servers []string) table { return newRing(servers) }
+	case Maglev:
+		newTable = func(servers []string) table { return newMaglev(servers) }
+	default:
+		return nil, fmt.Errorf("unknown consistent hashing algorithm: %q", config.Algorithm)
+	}
+
+	if config.LoadFactor != 0 && config.LoadFactor <= 1 {
+		return nil, fmt.Errorf("the load factor must be greater than 1: %v", config.LoadFactor)
+	}
+
+	lb, err := roundrobin.New(next)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Balancer{
+		RoundRobin: lb,
+		next:       next,
+		key:        key,
+		newTable:   newTable,
+		loadFactor: config.LoadFactor,
+		dirty:      true,
+		inFlight:   make(map[string]int64),
+	}, nil
+}
+
+func (b *Balancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	target := b.acquire(hash(b.key(req)))
+	if target == nil {
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	// Makes a shallow copy of the request before changing anything, to avoid side effects.
+	newReq := *req
+	newReq.URL = target
+
+	defer b.release(target)
+
+	b.next.ServeHTTP(rw, &newReq)
+}
+
+// UpsertServer adds the given server to the load balancer.
+func (b *Balancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.dirty = true
+
+	return b.RoundRobin.UpsertServer(u, options...)
+}
+
+// RemoveServer removes the given server from the load balancer.
+func (b *Balancer) RemoveServer(u *url.URL) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.dirty = true
+
+	return b.RoundRobin.RemoveServer(u)
+}
+
+// acquire picks the server for the given hash, and accounts the request to it.
+func (b *Balancer) acquire(h uint64) *url.URL {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.dirty {
+		b.build()
+	}
+
+	if len(b.servers) == 0 {
+		return nil
+	}
+
+	var capacity int64
+	if b.loadFactor > 0 {
+		capacity = int64(math.Ceil(b.loadFactor * float64(b.total+1) / float64(len(b.servers))))
+	}
+
+	picked := -1
+	b.table.lookup(h, func(server int) bool {
+		if picked == -1 {
+			picked = server
+		}
+
+		if capacity == 0 || b.inFlight[b.servers[server].String()] < capacity {
+			picked = server
+			return true
+		}
+
+		return false
+	})
+
+	if picked == -1 {
+		return nil
+	}
+
+	u := b.servers[picked]
+	b.inFlight[u.String()]++
+	b.total++
+
+	return u
+}
+
+// release accounts the end of a request to its server.
+func (b *Balancer) release(u *url.URL) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := u.String()
+
+	b.inFlight[key]--
+	if b.inFlight[key] <= 0 {
+		delete(b.inFlight, key)
+	}
+	b.total--
+}
+
+// build builds the table of the current servers.
+// The servers are sorted, so that all the Traefik instances build the same table.
+// It must be called with the lock held.
+func (b *Balancer) build() {
+	b.servers = b.servers[:0]
+	for _, u := range b.RoundRobin.Servers() {
+		if weight, _ := b.ServerWeight(u); weight > 0 {
+			b.servers = append(b.servers, u)
+		}
+	}
+
+	sort.Slice(b.servers, func(i, j int) bool {
+		return b.servers[i].String() < b.servers[j].String()
+	})
+
+	names := make([]string, len(b.servers))
+	for i, u := range b.servers {
+		names[i] = u.String()
+	}
+
+	b.table = b.newTable(names)
+	b.dirty = false
+}
+
+// keyFunc returns the function extracting the hash key of a request.
+// When the key is missing from a request, the client IP is used instead.
+func keyFunc(config dynamic.ConsistentHash) (func(req *http.Request) string, error) {
+	var sources int
+	for _, set := range []bool{config.Header != "", config.Cookie != "", config.PathSegment != 0} {
+		if set {
+			sources++
+		}
+	}
+
+	if sources != 1 {
+		return nil, errors.New("exactly one of header, cookie, and pathSegment must be defined as the hash key")
+	}
+
+	if config.PathSegment < 0 {
+		return nil, fmt.Errorf("the path segment must be positive: %d", config.PathSegment)
+	}
+
+	var key func(req *http.Request) string
+	switch {
+	case config.Header != "":
+		key = func(req *http.Request) string {
+			return req.Header.Get(config.Header)
+		}
+	case config.Cookie != "":
+		key = func(req *http.Request) string {
+			cookie, err := req.Cookie(config.Cookie)
+			if err != nil {
+				return ""
+			}
+			return cookie.Value
+		}
+	default:
+		key = func(req *http.Request) string {
+			segments := strings.Split(strings.TrimPrefix(req.URL.Path, "/"), "/")
+			if len(segments) < config.PathSegment {
+				return ""
+			}
+			return segments[config.PathSegment-1]
+		}
+	}
+
+	return func(req *http.Request) string {
+		if k := key(req); k != "" {
+			return k
+		}
+
+		clientIP, _, err := net.SplitHostPort(req.RemoteAddr)
+		if err != nil {
+			return req.RemoteAddr
+		}
+		return clientIP
+	}, nil
+}
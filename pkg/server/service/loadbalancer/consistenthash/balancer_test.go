@@ -0,0 +1,268 @@
+package consistenthash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNewBalancer_validation(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   dynamic.ConsistentHash
+		expected bool
+	}{
+		{
+			desc:     "header",
+			config:   dynamic.ConsistentHash{Header: "X-Key"},
+			expected: true,
+		},
+		{
+			desc:     "maglev",
+			config:   dynamic.ConsistentHash{Algorithm: Maglev, Cookie: "key"},
+			expected: true,
+		},
+		{
+			desc:     "bounded load",
+			config:   dynamic.ConsistentHash{PathSegment: 1, LoadFactor: 1.25},
+			expected: true,
+		},
+		{
+			desc: "no key",
+		},
+		{
+			desc:   "several keys",
+			config: dynamic.ConsistentHash{Header: "X-Key", Cookie: "key"},
+		},
+		{
+			desc:   "negative path segment",
+			config: dynamic.ConsistentHash{PathSegment: -1},
+		},
+		{
+			desc:   "unknown algorithm",
+			config: dynamic.ConsistentHash{Algorithm: "foo", Header: "X-Key"},
+		},
+		{
+			desc:   "load factor too low",
+			config: dynamic.ConsistentHash{Header: "X-Key", LoadFactor: 1},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewBalancer(http.NotFoundHandler(), test.config)
+			if test.expected {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestKeyFunc(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   dynamic.ConsistentHash
+		request  func(req *http.Request)
+		expected string
+	}{
+		{
+			desc:   "header",
+			config: dynamic.ConsistentHash{Header: "X-Key"},
+			request: func(req *http.Request) {
+				req.Header.Set("X-Key", "foo")
+			},
+			expected: "foo",
+		},
+		{
+			desc:   "cookie",
+			config: dynamic.ConsistentHash{Cookie: "key"},
+			request: func(req *http.Request) {
+				req.AddCookie(&http.Cookie{Name: "key", Value: "foo"})
+			},
+			expected: "foo",
+		},
+		{
+			desc:   "path segment",
+			config: dynamic.ConsistentHash{PathSegment: 2},
+			request: func(req *http.Request) {
+				req.URL.Path = "/shards/foo/bar"
+			},
+			expected: "foo",
+		},
+		{
+			desc:     "missing header",
+			config:   dynamic.ConsistentHash{Header: "X-Key"},
+			expected: "192.0.2.1",
+		},
+		{
+			desc:   "missing path segment",
+			config: dynamic.ConsistentHash{PathSegment: 4},
+			request: func(req *http.Request) {
+				req.URL.Path = "/shards/foo/bar"
+			},
+			expected: "192.0.2.1",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := keyFunc(test.config)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.request != nil {
+				test.request(req)
+			}
+
+			assert.Equal(t, test.expected, key(req))
+		})
+	}
+}
+
+func TestBalancer(t *testing.T) {
+	testCases := []struct {
+		algorithm string
+		// maxMoved is the maximum number of keys of the remaining servers moving when a server is removed.
+		maxMoved int
+	}{
+		{
+			algorithm: Ring,
+		},
+		{
+			// Maglev only minimizes the disruption.
+			algorithm: Maglev,
+			maxMoved:  10,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.algorithm, func(t *testing.T) {
+			t.Parallel()
+
+			balancer := newTestBalancer(t, dynamic.ConsistentHash{Algorithm: test.algorithm, Header: "X-Key"}, 4)
+
+			servers := make(map[string]string)
+			used := make(map[string]struct{})
+			for i := 0; i < 100; i++ {
+				key := fmt.Sprintf("key-%d", i)
+				server := serve(balancer, key)
+				servers[key] = server
+				used[server] = struct{}{}
+
+				// The same key always goes to the same server.
+				assert.Equal(t, server, serve(balancer, key))
+			}
+
+			assert.Len(t, used, 4)
+
+			// Removing a server mostly moves its own keys.
+			u, err := url.Parse("http://server-0")
+			require.NoError(t, err)
+			require.NoError(t, balancer.RemoveServer(u))
+
+			var moved int
+			for key, server := range servers {
+				if server == "server-0" {
+					assert.NotEqual(t, server, serve(balancer, key))
+					continue
+				}
+
+				if server != serve(balancer, key) {
+					moved++
+				}
+			}
+
+			assert.LessOrEqual(t, moved, test.maxMoved)
+		})
+	}
+}
+
+func TestBalancer_sameTableForSameServers(t *testing.T) {
+	for _, algorithm := range []string{Ring, Maglev} {
+		algorithm := algorithm
+		t.Run(algorithm, func(t *testing.T) {
+			t.Parallel()
+
+			first := newTestBalancer(t, dynamic.ConsistentHash{Algorithm: algorithm, Header: "X-Key"}, 4)
+			second := newTestBalancer(t, dynamic.ConsistentHash{Algorithm: algorithm, Header: "X-Key"}, 4)
+
+			for i := 0; i < 100; i++ {
+				key := fmt.Sprintf("key-%d", i)
+				assert.Equal(t, serve(first, key), serve(second, key))
+			}
+		})
+	}
+}
+
+func TestBalancer_boundedLoad(t *testing.T) {
+	balancer := newTestBalancer(t, dynamic.ConsistentHash{Header: "X-Key", LoadFactor: 1.5}, 2)
+
+	h := hash("foo")
+
+	// With a load factor of 1.5 and 2 servers, a server takes at most 3 of 4 in-flight requests.
+	first := balancer.acquire(h)
+	require.NotNil(t, first)
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, first, balancer.acquire(h))
+	}
+
+	second := balancer.acquire(h)
+	require.NotNil(t, second)
+	assert.NotEqual(t, first, second)
+
+	balancer.release(first)
+	assert.Equal(t, first, balancer.acquire(h))
+}
+
+func TestBalancer_noServers(t *testing.T) {
+	balancer := newTestBalancer(t, dynamic.ConsistentHash{Header: "X-Key"}, 0)
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func newTestBalancer(t *testing.T, config dynamic.ConsistentHash, servers int) *Balancer {
+	t.Helper()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", req.URL.Host)
+	})
+
+	balancer, err := NewBalancer(next, config)
+	require.NoError(t, err)
+
+	for i := 0; i < servers; i++ {
+		u, err := url.Parse(fmt.Sprintf("http://server-%d", i))
+		require.NoError(t, err)
+		require.NoError(t, balancer.UpsertServer(u))
+	}
+
+	return balancer
+}
+
+func serve(balancer *Balancer, key string) string {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Key", key)
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, req)
+
+	return recorder.Header().Get("server")
+}
@@ -0,0 +1,165 @@
+package consistenthash
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// ringReplicas is the number of points of a server on the ring.
+const ringReplicas = 100
+
+// maglevSize is the size of the Maglev lookup table.
+// It must be a prime number, much larger than the number of servers.
+const maglevSize = 65537
+
+// table maps the hashes of the keys to the servers, given by their index.
+type table interface {
+	// lookup calls fn with the servers for the given hash, by order of preference,
+	// until it returns true, or until all the servers have been browsed.
+	lookup(hash uint64, fn func(server int) bool)
+}
+
+// hash returns the hash of the given key.
+// It has to be stable, so that the Traefik instances agree on the servers of the keys.
+func hash(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+
+	return mix(h.Sum64())
+}
+
+// mix spreads the bits of the FNV hashes of close keys, as the FNV hashes of short keys are not well distributed.
+func mix(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+
+	return h
+}
+
+type point struct {
+	hash   uint64
+	server int
+}
+
+// ring is a ring hash table: each server owns several points on a ring,
+// and a key belongs to the server owning the first point after it on the ring.
+type ring struct {
+	points  []point
+	servers int
+}
+
+func newRing(servers []string) *ring {
+	r := &ring{
+		points:  make([]point, 0, len(servers)*ringReplicas),
+		servers: len(servers),
+	}
+
+	for i, server := range servers {
+		for j := 0; j < ringReplicas; j++ {
+			r.points = append(r.points, point{hash: hash(server + "-" + strconv.Itoa(j)), server: i})
+		}
+	}
+
+	sort.Slice(r.points, func(i, j int) bool {
+		return r.points[i].hash < r.points[j].hash
+	})
+
+	return r
+}
+
+func (r *ring) lookup(hash uint64, fn func(server int) bool) {
+	if len(r.points) == 0 {
+		return
+	}
+
+	start := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i].hash >= hash
+	})
+
+	visited := make(map[int]struct{}, r.servers)
+	for i := 0; i < len(r.points) && len(visited) < r.servers; i++ {
+		server := r.points[(start+i)%len(r.points)].server
+		if _, ok := visited[server]; ok {
+			continue
+		}
+		visited[server] = struct{}{}
+
+		if fn(server) {
+			return
+		}
+	}
+}
+
+// maglev is a Maglev lookup table: the entries of the table are evenly shared by the servers,
+// each server filling the entries following its own permutation of the table.
+type maglev struct {
+	entries []int
+	servers int
+}
+
+func newMaglev(servers []string) *maglev {
+	m := &maglev{
+		entries: make([]int, maglevSize),
+		servers: len(servers),
+	}
+
+	if len(servers) == 0 {
+		return m
+	}
+
+	offsets := make([]uint64, len(servers))
+	skips := make([]uint64, len(servers))
+	for i, server := range servers {
+		offsets[i] = hash(server+"-offset") % maglevSize
+		skips[i] = hash(server+"-skip")%(maglevSize-1) + 1
+	}
+
+	for i := range m.entries {
+		m.entries[i] = -1
+	}
+
+	next := make([]uint64, len(servers))
+	filled := 0
+	for {
+		for i := range servers {
+			entry := (offsets[i] + next[i]*skips[i]) % maglevSize
+			for m.entries[entry] >= 0 {
+				next[i]++
+				entry = (offsets[i] + next[i]*skips[i]) % maglevSize
+			}
+
+			m.entries[entry] = i
+			next[i]++
+
+			filled++
+			if filled == maglevSize {
+				return m
+			}
+		}
+	}
+}
+
+func (m *maglev) lookup(hash uint64, fn func(server int) bool) {
+	if m.servers == 0 {
+		return
+	}
+
+	start := hash % maglevSize
+
+	visited := make(map[int]struct{}, m.servers)
+	for i := uint64(0); i < maglevSize && len(visited) < m.servers; i++ {
+		server := m.entries[(start+i)%maglevSize]
+		if _, ok := visited[server]; ok {
+			continue
+		}
+		visited[server] = struct{}{}
+
+		if fn(server) {
+			return
+		}
+	}
+}
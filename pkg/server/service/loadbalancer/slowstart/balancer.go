@@ -0,0 +1,135 @@
+package slowstart
+
+import (
+	"math"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/healthcheck"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/vulcand/oxy/v2/roundrobin"
+)
+
+// DefaultWindow is the default ramp-up window.
+const DefaultWindow = 30 * time.Second
+
+// Steps is the number of steps of a ramp-up.
+// The weight of a server is Steps once ramped up.
+const Steps = 10
+
+// Weight returns the weight, between 1 and Steps, of a server seen since the given time.
+func Weight(since time.Time, window time.Duration, now time.Time) int {
+	elapsed := now.Sub(since)
+	if window <= 0 || elapsed >= window {
+		return Steps
+	}
+
+	weight := int(math.Ceil(Steps * float64(elapsed) / float64(window)))
+	if weight < 1 {
+		return 1
+	}
+
+	return weight
+}
+
+// Balancer ramps up the weight of the servers of a load balancer,
+// when they are added by a new configuration, or when they become healthy again.
+type Balancer struct {
+	healthcheck.BalancerHandler
+
+	window time.Duration
+	since  func(u *url.URL) time.Time
+	now    func() time.Time
+
+	mu sync.Mutex
+	// ramps holds the timers of the servers being ramped up, by URL.
+	ramps map[string]*time.Timer
+	// removed holds the servers removed from the load balancer, by URL.
+	removed map[string]struct{}
+}
+
+// NewBalancer creates a new Balancer ramping up the servers of the given load balancer over the given window.
+// The since function returns the time a server was first seen.
+func NewBalancer(lb healthcheck.BalancerHandler, window time.Duration, since func(u *url.URL) time.Time) *Balancer {
+	return &Balancer{
+		BalancerHandler: lb,
+		window:          window,
+		since:           since,
+		now:             time.Now,
+		ramps:           make(map[string]*time.Timer),
+		removed:         make(map[string]struct{}),
+	}
+}
+
+// UpsertServer adds the given server to the load balancer, with a weight depending on its ramp-up.
+func (b *Balancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := u.String()
+
+	since := b.now()
+	if _, ok := b.removed[key]; !ok {
+		since = b.since(u)
+	}
+	delete(b.removed, key)
+
+	return b.upsert(u, since, options...)
+}
+
+// RemoveServer removes the given server from the load balancer, and stops its ramp-up.
+func (b *Balancer) RemoveServer(u *url.URL) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := u.String()
+
+	if timer, ok := b.ramps[key]; ok {
+		timer.Stop()
+		delete(b.ramps, key)
+	}
+	b.removed[key] = struct{}{}
+
+	return b.BalancerHandler.RemoveServer(u)
+}
+
+// upsert sets the weight of the server, and schedules its next increase.
+// It must be called with the lock held.
+func (b *Balancer) upsert(u *url.URL, since time.Time, options ...roundrobin.ServerOption) error {
+	key := u.String()
+
+	weight := Weight(since, b.window, b.now())
+
+	// The weight is set last, to override the one given by the caller.
+	if err := b.BalancerHandler.UpsertServer(u, append(options, roundrobin.Weight(weight))...); err != nil {
+		return err
+	}
+
+	if timer, ok := b.ramps[key]; ok {
+		timer.Stop()
+		delete(b.ramps, key)
+	}
+
+	if weight >= Steps {
+		return nil
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(b.window/Steps, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		// The server has been removed, or upserted again, in the meantime.
+		if b.ramps[key] != timer {
+			return
+		}
+
+		if err := b.upsert(u, since); err != nil {
+			log.WithoutContext().Errorf("Error while ramping up server %s: %v", key, err)
+		}
+	})
+	b.ramps[key] = timer
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package slowstart
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/v2/roundrobin"
+)
+
+func TestTracker(t *testing.T) {
+	now := time.Now()
+
+	tracker := NewTracker()
+	tracker.now = func() time.Time { return now }
+
+	tracker.NextGeneration()
+	assert.Equal(t, now, tracker.Since("foo", "a"))
+	assert.Equal(t, now, tracker.Since("foo", "b"))
+
+	later := now.Add(time.Minute)
+	tracker.now = func() time.Time { return later }
+
+	// b is not part of the second configuration.
+	tracker.NextGeneration()
+	assert.Equal(t, now, tracker.Since("foo", "a"))
+	assert.Equal(t, later, tracker.Since("bar", "a"))
+
+	// b is forgotten, and seen again.
+	tracker.NextGeneration()
+	assert.Equal(t, now, tracker.Since("foo", "a"))
+	assert.Equal(t, later, tracker.Since("foo", "b"))
+
+	var nilTracker *Tracker
+	nilTracker.NextGeneration()
+	assert.True(t, nilTracker.Since("foo", "a").IsZero())
+}
+
+func TestWeight(t *testing.T) {
+	now := time.Now()
+
+	assert.Equal(t, 1, Weight(now, 10*time.Second, now))
+	assert.Equal(t, 3, Weight(now.Add(-2500*time.Millisecond), 10*time.Second, now))
+	assert.Equal(t, Steps, Weight(now.Add(-10*time.Second), 10*time.Second, now))
+	assert.Equal(t, Steps, Weight(time.Time{}, 10*time.Second, now))
+	assert.Equal(t, Steps, Weight(now, 0, now))
+}
+
+func TestBalancer(t *testing.T) {
+	lb, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	now := time.Now()
+	oldServer := mustParseURL(t, "http://old")
+	newServer := mustParseURL(t, "http://new")
+
+	balancer := NewBalancer(lb, time.Hour, func(u *url.URL) time.Time {
+		if u.String() == newServer.String() {
+			return now.Add(-30 * time.Minute)
+		}
+		return time.Time{}
+	})
+	balancer.now = func() time.Time { return now }
+
+	require.NoError(t, balancer.UpsertServer(oldServer, roundrobin.Weight(1)))
+	require.NoError(t, balancer.UpsertServer(newServer, roundrobin.Weight(1)))
+
+	assertWeight(t, lb, oldServer, Steps)
+	assertWeight(t, lb, newServer, Steps/2)
+
+	// The server becomes healthy again, after being removed by the health check.
+	require.NoError(t, balancer.RemoveServer(oldServer))
+	require.NoError(t, balancer.UpsertServer(oldServer, roundrobin.Weight(1)))
+
+	assertWeight(t, lb, oldServer, 1)
+}
+
+func TestBalancer_rampUp(t *testing.T) {
+	lb, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	server := mustParseURL(t, "http://server")
+
+	balancer := NewBalancer(lb, 50*time.Millisecond, func(u *url.URL) time.Time {
+		return time.Now()
+	})
+
+	require.NoError(t, balancer.UpsertServer(server))
+	assertWeight(t, lb, server, 1)
+
+	assert.Eventually(t, func() bool {
+		weight, _ := lb.ServerWeight(server)
+		return weight == Steps
+	}, time.Second, 10*time.Millisecond)
+}
+
+func assertWeight(t *testing.T, lb *roundrobin.RoundRobin, u *url.URL, expected int) {
+	t.Helper()
+
+	weight, ok := lb.ServerWeight(u)
+	require.True(t, ok)
+	assert.Equal(t, expected, weight)
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+
+	return u
+}
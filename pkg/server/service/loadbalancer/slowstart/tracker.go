@@ -0,0 +1,70 @@
+package slowstart
+
+import (
+	"sync"
+	"time"
+)
+
+// Tracker records when the servers of the load balancers were first seen,
+// across the configuration reloads, so that only the servers added by a new configuration are ramped up.
+// A nil Tracker considers all the servers as seen for ever.
+type Tracker struct {
+	mu         sync.Mutex
+	generation uint64
+	servers    map[string]*seen
+	now        func() time.Time
+}
+
+type seen struct {
+	since      time.Time
+	generation uint64
+}
+
+// NewTracker creates a new Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		servers: make(map[string]*seen),
+		now:     time.Now,
+	}
+}
+
+// NextGeneration must be called before building the load balancers of a new configuration.
+// It forgets the servers that were not part of the previous configuration.
+func (t *Tracker) NextGeneration() {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key, s := range t.servers {
+		if s.generation < t.generation {
+			delete(t.servers, key)
+		}
+	}
+
+	t.generation++
+}
+
+// Since returns the time the given server of the given service was first seen.
+func (t *Tracker) Since(serviceName, server string) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := serviceName + "|" + server
+
+	s, ok := t.servers[key]
+	if !ok {
+		s = &seen{since: t.now()}
+		t.servers[key] = s
+	}
+
+	s.generation = t.generation
+
+	return s.since
+}
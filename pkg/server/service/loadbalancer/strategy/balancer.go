@@ -0,0 +1,147 @@
+package strategy
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/vulcand/oxy/v2/roundrobin"
+)
+
+// Balancer is an HTTP load balancer picking the servers according to a strategy.
+// The servers and their weights are held by a round-robin load balancer,
+// so that the health check and the slow start manage them as usual.
+type Balancer struct {
+	*roundrobin.RoundRobin
+
+	next     http.Handler
+	strategy string
+	sticky   *roundrobin.StickySession
+	now      func() time.Time
+
+	mu     sync.Mutex
+	stats  map[string]*stats
+	offset int
+}
+
+// NewBalancer creates a new Balancer forwarding the requests to next, with the given strategy.
+// The sticky session is optional.
+func NewBalancer(next http.Handler, strategy string, sticky *roundrobin.StickySession) (*Balancer, error) {
+	if err := Check(strategy); err != nil {
+		return nil, err
+	}
+
+	if strategy == "" || strategy == WRR {
+		return nil, errors.New("the weighted round-robin strategy is handled by the round-robin load balancer")
+	}
+
+	lb, err := roundrobin.New(next)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Balancer{
+		RoundRobin: lb,
+		next:       next,
+		strategy:   strategy,
+		sticky:     sticky,
+		now:        time.Now,
+		stats:      make(map[string]*stats),
+	}, nil
+}
+
+func (b *Balancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// Makes a shallow copy of the request before changing anything, to avoid side effects.
+	newReq := *req
+
+	var stuck *url.URL
+	if b.sticky != nil {
+		cookieURL, present, err := b.sticky.GetBackend(&newReq, b.Servers())
+		if err != nil {
+			log.FromContext(req.Context()).Warnf("Error using server from cookie: %v", err)
+		}
+
+		if present {
+			stuck = cookieURL
+		}
+	}
+
+	target, s := b.acquire(stuck)
+	if target == nil {
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	if b.sticky != nil && stuck == nil {
+		b.sticky.StickBackend(target, rw)
+	}
+
+	newReq.URL = target
+
+	start := b.now()
+	b.next.ServeHTTP(rw, &newReq)
+	b.release(s, b.now().Sub(start))
+}
+
+// RemoveServer removes the given server from the load balancer, and forgets its load.
+func (b *Balancer) RemoveServer(u *url.URL) error {
+	b.mu.Lock()
+	delete(b.stats, u.String())
+	b.mu.Unlock()
+
+	return b.RoundRobin.RemoveServer(u)
+}
+
+// acquire picks the server of the request, unless it is stuck to one, and accounts the request to it.
+func (b *Balancer) acquire(stuck *url.URL) (*url.URL, *stats) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if stuck != nil {
+		s := b.serverStats(stuck.String())
+		s.inFlight++
+		return stuck, s
+	}
+
+	servers := b.RoundRobin.Servers()
+	candidates := make([]*stats, len(servers))
+	weights := make([]int, len(servers))
+	for i, u := range servers {
+		candidates[i] = b.serverStats(u.String())
+		weights[i], _ = b.ServerWeight(u)
+	}
+
+	index := pick(b.strategy, candidates, weights, b.offset)
+	if index < 0 {
+		return nil, nil
+	}
+	b.offset++
+
+	candidates[index].inFlight++
+
+	return servers[index], candidates[index]
+}
+
+// release accounts the end of a request to its server.
+func (b *Balancer) release(s *stats, latency time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s.inFlight--
+	s.observe(latency, b.now())
+}
+
+// serverStats returns the load of the given server.
+// It must be called with the lock held.
+func (b *Balancer) serverStats(key string) *stats {
+	s, ok := b.stats[key]
+	if !ok {
+		s = &stats{}
+		b.stats[key] = s
+	}
+
+	return s
+}
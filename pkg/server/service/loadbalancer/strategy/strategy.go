@@ -0,0 +1,94 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Load-balancing strategies.
+const (
+	// WRR is the weighted round-robin strategy.
+	WRR = "wrr"
+	// LeastConn picks the server with the least in-flight requests or connections, relatively to its weight.
+	LeastConn = "leastconn"
+	// PeakEWMA picks the server with the lowest peak exponentially-weighted moving average of its latency,
+	// multiplied by its in-flight requests or connections, relatively to its weight.
+	PeakEWMA = "peakewma"
+)
+
+// decay is the time constant of the moving average of the latency.
+const decay = 10 * time.Second
+
+// penalty is the latency assumed for a server which is still waiting for its first response.
+const penalty = float64(time.Second)
+
+// Check returns an error if the given strategy is not supported.
+func Check(strategy string) error {
+	switch strategy {
+	case "", WRR, LeastConn, PeakEWMA:
+		return nil
+	default:
+		return fmt.Errorf("unknown load-balancing strategy: %q", strategy)
+	}
+}
+
+// stats holds the load of a server.
+type stats struct {
+	inFlight int64
+	// latency is the peak EWMA of the latency, in nanoseconds.
+	latency  float64
+	observed time.Time
+}
+
+// cost returns the cost of sending one more request or connection to the server.
+func (s *stats) cost(strategy string, weight int) float64 {
+	load := float64(s.inFlight + 1)
+
+	if strategy == PeakEWMA {
+		latency := s.latency
+		if latency == 0 && s.inFlight > 0 {
+			latency = penalty
+		}
+		load *= latency
+	}
+
+	return load / float64(weight)
+}
+
+// observe records the latency of a response of the server.
+// A latency higher than the average replaces it, so that a degrading server is quickly avoided.
+func (s *stats) observe(latency time.Duration, now time.Time) {
+	rtt := float64(latency)
+
+	if s.observed.IsZero() || rtt > s.latency {
+		s.latency = rtt
+	} else {
+		w := math.Exp(-float64(now.Sub(s.observed)) / float64(decay))
+		s.latency = s.latency*w + rtt*(1-w)
+	}
+
+	s.observed = now
+}
+
+// pick returns the index of the least costly server, or -1 if there is none.
+// The servers are browsed from the given offset, so that the ties are spread over the servers.
+func pick(strategy string, servers []*stats, weights []int, offset int) int {
+	best := -1
+	var bestCost float64
+
+	for i := range servers {
+		index := (offset + i) % len(servers)
+		if weights[index] <= 0 {
+			continue
+		}
+
+		cost := servers[index].cost(strategy, weights[index])
+		if best == -1 || cost < bestCost {
+			best = index
+			bestCost = cost
+		}
+	}
+
+	return best
+}
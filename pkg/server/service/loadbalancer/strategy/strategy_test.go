@@ -0,0 +1,240 @@
+package strategy
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+	"github.com/vulcand/oxy/v2/roundrobin"
+)
+
+func TestCheck(t *testing.T) {
+	assert.NoError(t, Check(""))
+	assert.NoError(t, Check(WRR))
+	assert.NoError(t, Check(LeastConn))
+	assert.NoError(t, Check(PeakEWMA))
+	assert.Error(t, Check("foo"))
+}
+
+func TestStats_observe(t *testing.T) {
+	now := time.Now()
+
+	var s stats
+	s.observe(10*time.Millisecond, now)
+	assert.Equal(t, float64(10*time.Millisecond), s.latency)
+
+	// A peak replaces the average.
+	s.observe(100*time.Millisecond, now)
+	assert.Equal(t, float64(100*time.Millisecond), s.latency)
+
+	// A lower latency decays the average, depending on the elapsed time.
+	s.observe(10*time.Millisecond, now.Add(decay))
+	assert.InDelta(t, float64(10*time.Millisecond)+float64(90*time.Millisecond)/2.718281828, s.latency, float64(time.Millisecond))
+}
+
+func TestStats_cost(t *testing.T) {
+	s := stats{inFlight: 1}
+	assert.Equal(t, 1.0, s.cost(LeastConn, 2))
+
+	// A server waiting for its first response is penalized.
+	assert.Equal(t, 2*penalty, s.cost(PeakEWMA, 1))
+
+	s.latency = float64(time.Millisecond)
+	assert.Equal(t, float64(time.Millisecond), s.cost(PeakEWMA, 2))
+}
+
+func TestBalancer_leastConn(t *testing.T) {
+	b, err := NewBalancer(http.NotFoundHandler(), LeastConn, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, b.UpsertServer(mustParseURL(t, "http://a"), roundrobin.Weight(1)))
+	require.NoError(t, b.UpsertServer(mustParseURL(t, "http://b"), roundrobin.Weight(3)))
+
+	counts := map[string]int{}
+	for i := 0; i < 8; i++ {
+		u, _ := b.acquire(nil)
+		require.NotNil(t, u)
+		counts[u.Host]++
+	}
+
+	assert.Equal(t, map[string]int{"a": 2, "b": 6}, counts)
+
+	// Once its requests are done, a is the least loaded server.
+	for i := 0; i < 2; i++ {
+		b.release(b.stats["http://a"], time.Millisecond)
+	}
+
+	u, _ := b.acquire(nil)
+	assert.Equal(t, "a", u.Host)
+}
+
+func TestBalancer_peakEWMA(t *testing.T) {
+	b, err := NewBalancer(http.NotFoundHandler(), PeakEWMA, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, b.UpsertServer(mustParseURL(t, "http://slow")))
+	require.NoError(t, b.UpsertServer(mustParseURL(t, "http://fast")))
+
+	b.release(b.serverStats("http://slow"), 100*time.Millisecond)
+	b.release(b.serverStats("http://fast"), 10*time.Millisecond)
+	b.stats["http://slow"].inFlight = 0
+	b.stats["http://fast"].inFlight = 0
+
+	for i := 0; i < 5; i++ {
+		u, _ := b.acquire(nil)
+		assert.Equal(t, "fast", u.Host)
+	}
+}
+
+func TestBalancer_ServeHTTP(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", req.URL.Host)
+	})
+
+	sticky := roundrobin.NewStickySession("test")
+	b, err := NewBalancer(next, LeastConn, sticky)
+	require.NoError(t, err)
+
+	require.NoError(t, b.UpsertServer(mustParseURL(t, "http://a")))
+	require.NoError(t, b.UpsertServer(mustParseURL(t, "http://b")))
+
+	recorder := httptest.NewRecorder()
+	b.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	server := recorder.Header().Get("server")
+	require.NotEmpty(t, server)
+
+	cookies := recorder.Result().Cookies()
+	require.Len(t, cookies, 1)
+
+	// The sticky session wins over the strategy.
+	b.stats["http://"+server].inFlight = 10
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookies[0])
+
+	recorder = httptest.NewRecorder()
+	b.ServeHTTP(recorder, req)
+	assert.Equal(t, server, recorder.Header().Get("server"))
+	assert.Equal(t, int64(10), b.stats["http://"+server].inFlight)
+
+	// Without any server, the request is rejected.
+	require.NoError(t, b.RemoveServer(mustParseURL(t, "http://a")))
+	require.NoError(t, b.RemoveServer(mustParseURL(t, "http://b")))
+
+	recorder = httptest.NewRecorder()
+	b.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func TestNewBalancer_wrr(t *testing.T) {
+	_, err := NewBalancer(http.NotFoundHandler(), WRR, nil)
+	assert.Error(t, err)
+
+	_, err = NewTCPBalancer(WRR)
+	assert.Error(t, err)
+}
+
+func TestTCPBalancer_leastConn(t *testing.T) {
+	b, err := NewTCPBalancer(LeastConn)
+	require.NoError(t, err)
+
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	a := &fakeHandler{name: "a"}
+	ramping := &fakeHandler{name: "ramping"}
+	b.AddServer(a)
+	// Halfway through its ramp-up, the server has half the weight of a.
+	b.AddSlowStartServer(ramping, now.Add(-time.Minute), 2*time.Minute)
+
+	counts := map[string]int{}
+	for i := 0; i < 6; i++ {
+		counts[b.acquire().Handler.(*fakeHandler).name]++
+	}
+
+	assert.Equal(t, map[string]int{"a": 4, "ramping": 2}, counts)
+}
+
+func TestTCPBalancer_peakEWMA(t *testing.T) {
+	b, err := NewTCPBalancer(PeakEWMA)
+	require.NoError(t, err)
+
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	server := &fakeHandler{
+		name: "a",
+		serve: func(conn tcp.WriteCloser) {
+			now = now.Add(20 * time.Millisecond)
+			_, _ = conn.Write([]byte("hello"))
+			_, _ = conn.Write([]byte("world"))
+			now = now.Add(time.Second)
+		},
+	}
+	b.AddServer(server)
+
+	conn := &fakeConn{}
+	b.ServeTCP(conn)
+
+	assert.Equal(t, "helloworld", string(conn.written))
+	assert.Equal(t, int64(0), b.servers[0].inFlight)
+	assert.Equal(t, float64(20*time.Millisecond), b.servers[0].latency)
+}
+
+func TestTCPBalancer_noServers(t *testing.T) {
+	b, err := NewTCPBalancer(LeastConn)
+	require.NoError(t, err)
+
+	conn := &fakeConn{}
+	b.ServeTCP(conn)
+
+	assert.True(t, conn.closed)
+}
+
+type fakeHandler struct {
+	name  string
+	serve func(conn tcp.WriteCloser)
+}
+
+func (h *fakeHandler) ServeTCP(conn tcp.WriteCloser) {
+	if h.serve != nil {
+		h.serve(conn)
+	}
+}
+
+type fakeConn struct {
+	net.Conn
+
+	written []byte
+	closed  bool
+}
+
+func (c *fakeConn) Write(p []byte) (int, error) {
+	c.written = append(c.written, p...)
+	return len(p), nil
+}
+
+func (c *fakeConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func (c *fakeConn) CloseWrite() error {
+	return nil
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+
+	return u
+}
@@ -0,0 +1,149 @@
+package strategy
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/slowstart"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+type tcpServer struct {
+	tcp.Handler
+	stats
+
+	// since and window define the ramp-up of a slow-start server.
+	since  time.Time
+	window time.Duration
+}
+
+// TCPBalancer is a TCP load balancer picking the servers according to a strategy.
+// With the PeakEWMA strategy, the latency of a connection is the time until the server sends its first byte.
+type TCPBalancer struct {
+	strategy string
+	now      func() time.Time
+
+	mu      sync.Mutex
+	servers []*tcpServer
+	offset  int
+}
+
+// NewTCPBalancer creates a new TCPBalancer with the given strategy.
+func NewTCPBalancer(strategy string) (*TCPBalancer, error) {
+	if err := Check(strategy); err != nil {
+		return nil, err
+	}
+
+	if strategy == "" || strategy == WRR {
+		return nil, errors.New("the weighted round-robin strategy is handled by the round-robin load balancer")
+	}
+
+	return &TCPBalancer{
+		strategy: strategy,
+		now:      time.Now,
+	}, nil
+}
+
+// ServeTCP forwards the connection to the right server.
+func (b *TCPBalancer) ServeTCP(conn tcp.WriteCloser) {
+	server := b.acquire()
+	if server == nil {
+		log.WithoutContext().Error("Error during load balancing: no servers in the pool")
+		conn.Close()
+		return
+	}
+
+	if b.strategy == PeakEWMA {
+		start := b.now()
+		conn = &firstByteConn{
+			WriteCloser: conn,
+			onFirstByte: func() { b.observe(server, b.now().Sub(start)) },
+		}
+	}
+
+	server.ServeTCP(conn)
+
+	b.release(server)
+}
+
+// AddServer appends a server to the existing list.
+func (b *TCPBalancer) AddServer(serverHandler tcp.Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.servers = append(b.servers, &tcpServer{Handler: serverHandler})
+}
+
+// AddSlowStartServer appends a server to the existing list,
+// with a weight ramped up over the given window, from the given time.
+func (b *TCPBalancer) AddSlowStartServer(serverHandler tcp.Handler, since time.Time, window time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.servers = append(b.servers, &tcpServer{Handler: serverHandler, since: since, window: window})
+}
+
+// acquire picks the server of the connection, and accounts the connection to it.
+func (b *TCPBalancer) acquire() *tcpServer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	candidates := make([]*stats, len(b.servers))
+	weights := make([]int, len(b.servers))
+	for i, s := range b.servers {
+		candidates[i] = &s.stats
+		weights[i] = slowstart.Steps
+		if s.window > 0 {
+			weights[i] = slowstart.Weight(s.since, s.window, now)
+		}
+	}
+
+	index := pick(b.strategy, candidates, weights, b.offset)
+	if index < 0 {
+		return nil
+	}
+	b.offset++
+
+	b.servers[index].inFlight++
+
+	return b.servers[index]
+}
+
+// release accounts the end of a connection to its server.
+func (b *TCPBalancer) release(server *tcpServer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	server.inFlight--
+}
+
+// observe records the time until the server sent its first byte on a connection.
+func (b *TCPBalancer) observe(server *tcpServer, latency time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	server.observe(latency, b.now())
+}
+
+// firstByteConn calls onFirstByte on the first write to the client connection.
+type firstByteConn struct {
+	tcp.WriteCloser
+
+	once        sync.Once
+	onFirstByte func()
+}
+
+func (c *firstByteConn) Write(p []byte) (int, error) {
+	c.once.Do(c.onFirstByte)
+
+	return c.WriteCloser.Write(p)
+}
+
+// NetConn returns the client connection.
+func (c *firstByteConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
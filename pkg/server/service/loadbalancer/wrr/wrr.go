@@ -5,10 +5,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"net/http"
+	"sort"
 	"sync"
 
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/jwtclaims"
 	"github.com/traefik/traefik/v2/pkg/log"
 )
 
@@ -33,6 +36,8 @@ type stickyCookie struct {
 type Balancer struct {
 	stickyCookie     *stickyCookie
 	wantsHealthCheck bool
+	// claim is the JWT claim whose value picks the child service.
+	claim string
 
 	mutex       sync.RWMutex
 	handlers    []*namedHandler
@@ -45,13 +50,18 @@ type Balancer struct {
 	// updaters is the list of hooks that are run (to update the Balancer
 	// parent(s)), whenever the Balancer status changes.
 	updaters []func(bool)
+	// disabled holds the child services removed from the rotation by SetWeight, keyed by name.
+	disabled map[string]*namedHandler
 }
 
 // New creates a new load balancer.
-func New(sticky *dynamic.Sticky, hc *dynamic.HealthCheck) *Balancer {
+// When claim is not empty, the child service of the requests carrying a JWT with this claim is picked from its value.
+func New(sticky *dynamic.Sticky, hc *dynamic.HealthCheck, claim string) *Balancer {
 	balancer := &Balancer{
 		status:           make(map[string]struct{}),
+		disabled:         make(map[string]*namedHandler),
 		wantsHealthCheck: hc != nil,
+		claim:            claim,
 	}
 	if sticky != nil && sticky.Cookie != nil {
 		balancer.stickyCookie = &stickyCookie{
@@ -153,7 +163,7 @@ func (b *Balancer) nextServer() (*namedHandler, error) {
 	if len(b.handlers) == 0 {
 		return nil, fmt.Errorf("no servers in the pool")
 	}
-	if len(b.status) == 0 {
+	if len(b.status) == 0 || !b.hasAvailable() {
 		return nil, errNoAvailableServer
 	}
 
@@ -176,6 +186,65 @@ func (b *Balancer) nextServer() (*namedHandler, error) {
 	return handler, nil
 }
 
+// claimServer returns the child service picked from the given claim value, among the ones in the rotation that are up,
+// in proportion to their weights.
+func (b *Balancer) claimServer(value string) (*namedHandler, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if len(b.handlers) == 0 {
+		return nil, fmt.Errorf("no servers in the pool")
+	}
+
+	var (
+		handlers []*namedHandler
+		total    float64
+	)
+	for _, handler := range b.handlers {
+		if _, ok := b.status[handler.name]; ok {
+			handlers = append(handlers, handler)
+			total += handler.weight
+		}
+	}
+
+	if len(handlers) == 0 {
+		return nil, errNoAvailableServer
+	}
+
+	// The heap order changes at each pick, the child services are sorted for the same value to give the same one.
+	sort.Slice(handlers, func(i, j int) bool { return handlers[i].name < handlers[j].name })
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(value))
+	point := float64(hash.Sum64()%1e6) / 1e6 * total
+
+	for _, handler := range handlers {
+		if point < handler.weight {
+			return handler, nil
+		}
+		point -= handler.weight
+	}
+
+	return handlers[len(handlers)-1], nil
+}
+
+// hasAvailable reports whether one of the child services in the rotation is up.
+// It must be called with the lock held.
+func (b *Balancer) hasAvailable() bool {
+	// Without disabled child services, all the ones with a status are in the rotation.
+	if len(b.disabled) == 0 {
+		return true
+	}
+
+	for _, handler := range b.handlers {
+		if _, ok := b.status[handler.name]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	if b.stickyCookie != nil {
 		cookie, err := req.Cookie(b.stickyCookie.name)
@@ -205,7 +274,7 @@ func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		}
 	}
 
-	server, err := b.nextServer()
+	server, err := b.pickServer(req)
 	if err != nil {
 		if errors.Is(err, errNoAvailableServer) {
 			http.Error(w, errNoAvailableServer.Error(), http.StatusServiceUnavailable)
@@ -223,6 +292,21 @@ func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	server.ServeHTTP(w, req)
 }
 
+// pickServer picks the child service from the claim value of the request when it has one,
+// and with the weighted round-robin otherwise.
+func (b *Balancer) pickServer(req *http.Request) (*namedHandler, error) {
+	if b.claim == "" {
+		return b.nextServer()
+	}
+
+	value, ok := jwtclaims.FromRequest(req).Value(b.claim)
+	if !ok {
+		return b.nextServer()
+	}
+
+	return b.claimServer(value)
+}
+
 // AddService adds a handler.
 // A handler with a non-positive weight is ignored.
 func (b *Balancer) AddService(name string, handler http.Handler, weight *int) {
@@ -243,3 +327,39 @@ func (b *Balancer) AddService(name string, handler http.Handler, weight *int) {
 	b.status[name] = struct{}{}
 	b.mutex.Unlock()
 }
+
+// SetWeight sets the weight of the given child service.
+// A child service with a non-positive weight is removed from the rotation, until its weight is positive again.
+// A child service ignored by AddService cannot be set a weight.
+func (b *Balancer) SetWeight(name string, weight int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for i, handler := range b.handlers {
+		if handler.name != name {
+			continue
+		}
+
+		if weight <= 0 {
+			heap.Remove(b, i)
+			b.disabled[name] = handler
+			return
+		}
+
+		handler.weight = float64(weight)
+		handler.deadline = b.curDeadline + 1/handler.weight
+		heap.Fix(b, i)
+		return
+	}
+
+	handler, ok := b.disabled[name]
+	if !ok || weight <= 0 {
+		return
+	}
+
+	delete(b.disabled, name)
+
+	handler.weight = float64(weight)
+	handler.deadline = b.curDeadline + 1/handler.weight
+	heap.Push(b, handler)
+}
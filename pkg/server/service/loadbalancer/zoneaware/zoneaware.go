@@ -0,0 +1,94 @@
+package zoneaware
+
+import (
+	"net/http"
+	"net/url"
+	"sync/atomic"
+
+	"github.com/traefik/traefik/v2/pkg/healthcheck"
+	"github.com/vulcand/oxy/v2/roundrobin"
+)
+
+// Balancer sends the requests to the servers of the local zone,
+// and spills them over to the servers of the other zones,
+// when the local servers are too few or too loaded.
+type Balancer struct {
+	local  healthcheck.BalancerHandler
+	remote healthcheck.BalancerHandler
+
+	isLocal     func(u *url.URL) bool
+	minServers  int
+	maxInFlight int64
+
+	// inFlight is the number of in-flight requests sent to the local servers.
+	inFlight int64
+}
+
+// NewBalancer creates a new Balancer.
+// The isLocal function tells whether a server belongs to the local zone,
+// and the servers are added to the local, or to the remote, load balancer accordingly.
+// The requests are sent to the remote servers when there are less than minServers local servers,
+// or when there are more than maxInFlight in-flight requests per local server, if maxInFlight is positive.
+func NewBalancer(local, remote healthcheck.BalancerHandler, isLocal func(u *url.URL) bool, minServers int, maxInFlight int64) *Balancer {
+	return &Balancer{
+		local:       local,
+		remote:      remote,
+		isLocal:     isLocal,
+		minServers:  minServers,
+		maxInFlight: maxInFlight,
+	}
+}
+
+func (b *Balancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !b.useLocal() {
+		b.remote.ServeHTTP(rw, req)
+		return
+	}
+
+	atomic.AddInt64(&b.inFlight, 1)
+	defer atomic.AddInt64(&b.inFlight, -1)
+
+	b.local.ServeHTTP(rw, req)
+}
+
+// Servers returns the servers of all the zones.
+func (b *Balancer) Servers() []*url.URL {
+	return append(b.local.Servers(), b.remote.Servers()...)
+}
+
+// UpsertServer adds the given server to the load balancer of its zone.
+func (b *Balancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	return b.balancer(u).UpsertServer(u, options...)
+}
+
+// RemoveServer removes the given server from the load balancer of its zone.
+func (b *Balancer) RemoveServer(u *url.URL) error {
+	return b.balancer(u).RemoveServer(u)
+}
+
+func (b *Balancer) balancer(u *url.URL) healthcheck.BalancerHandler {
+	if b.isLocal(u) {
+		return b.local
+	}
+
+	return b.remote
+}
+
+// useLocal tells whether the next request should be sent to the local servers.
+func (b *Balancer) useLocal() bool {
+	localServers := len(b.local.Servers())
+	if localServers == 0 {
+		return false
+	}
+
+	// There is no other zone to spill over to.
+	if len(b.remote.Servers()) == 0 {
+		return true
+	}
+
+	if localServers < b.minServers {
+		return false
+	}
+
+	return b.maxInFlight <= 0 || atomic.LoadInt64(&b.inFlight) < b.maxInFlight*int64(localServers)
+}
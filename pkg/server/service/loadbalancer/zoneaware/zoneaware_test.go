@@ -0,0 +1,132 @@
+package zoneaware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/v2/roundrobin"
+)
+
+func TestBalancer(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		localServers  []string
+		remoteServers []string
+		minServers    int
+		maxInFlight   int64
+		inFlight      int64
+		expected      string
+	}{
+		{
+			desc:          "local servers are preferred",
+			localServers:  []string{"http://local"},
+			remoteServers: []string{"http://remote"},
+			minServers:    1,
+			expected:      "local",
+		},
+		{
+			desc:          "too few local servers",
+			localServers:  []string{"http://local"},
+			remoteServers: []string{"http://remote"},
+			minServers:    2,
+			expected:      "remote",
+		},
+		{
+			desc:          "no local servers",
+			remoteServers: []string{"http://remote"},
+			minServers:    1,
+			expected:      "remote",
+		},
+		{
+			desc:         "no remote servers to spill over to",
+			localServers: []string{"http://local"},
+			minServers:   2,
+			expected:     "local",
+		},
+		{
+			desc:          "local servers overloaded",
+			localServers:  []string{"http://local1", "http://local2"},
+			remoteServers: []string{"http://remote"},
+			minServers:    1,
+			maxInFlight:   2,
+			inFlight:      4,
+			expected:      "remote",
+		},
+		{
+			desc:          "local servers not overloaded",
+			localServers:  []string{"http://local1", "http://local2"},
+			remoteServers: []string{"http://remote"},
+			minServers:    1,
+			maxInFlight:   2,
+			inFlight:      3,
+			expected:      "local1",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("server", req.URL.Host)
+			})
+
+			local, err := roundrobin.New(next)
+			require.NoError(t, err)
+			remote, err := roundrobin.New(next)
+			require.NoError(t, err)
+
+			locals := make(map[string]struct{})
+			for _, s := range test.localServers {
+				locals[s] = struct{}{}
+			}
+
+			isLocal := func(u *url.URL) bool {
+				_, ok := locals[u.String()]
+				return ok
+			}
+
+			balancer := NewBalancer(local, remote, isLocal, test.minServers, test.maxInFlight)
+			balancer.inFlight = test.inFlight
+
+			for _, s := range append(test.localServers, test.remoteServers...) {
+				u, err := url.Parse(s)
+				require.NoError(t, err)
+				require.NoError(t, balancer.UpsertServer(u))
+			}
+
+			assert.Len(t, local.Servers(), len(test.localServers))
+			assert.Len(t, remote.Servers(), len(test.remoteServers))
+			assert.Len(t, balancer.Servers(), len(test.localServers)+len(test.remoteServers))
+
+			recorder := httptest.NewRecorder()
+			balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, test.expected, recorder.Header().Get("server"))
+			assert.Equal(t, test.inFlight, balancer.inFlight)
+		})
+	}
+}
+
+func TestBalancer_RemoveServer(t *testing.T) {
+	local, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+	remote, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	localURL, err := url.Parse("http://local")
+	require.NoError(t, err)
+
+	balancer := NewBalancer(local, remote, func(u *url.URL) bool { return u.String() == localURL.String() }, 1, 0)
+
+	require.NoError(t, balancer.UpsertServer(localURL))
+	require.Len(t, local.Servers(), 1)
+
+	require.NoError(t, balancer.RemoveServer(localURL))
+	assert.Empty(t, local.Servers())
+}
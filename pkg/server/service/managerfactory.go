@@ -6,10 +6,19 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/traefik/traefik/v2/pkg/api"
 	"github.com/traefik/traefik/v2/pkg/api/dashboard"
+	"github.com/traefik/traefik/v2/pkg/bandwidth"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/connections"
+	"github.com/traefik/traefik/v2/pkg/conntrace"
+	"github.com/traefik/traefik/v2/pkg/maintenance"
 	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/overrides"
 	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/canary"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/slowstart"
+	"github.com/traefik/traefik/v2/pkg/snapshot"
+	"github.com/traefik/traefik/v2/pkg/tenant"
 )
 
 // ManagerFactory a factory of service manager.
@@ -26,19 +35,45 @@ type ManagerFactory struct {
 	acmeHTTPHandler  http.Handler
 
 	routinesPool *safe.Pool
+
+	// slowStart records when the servers were first seen, across the configurations.
+	slowStart *slowstart.Tracker
+
+	// canaries drives the rollouts of the canary services, across the configurations.
+	canaries *canary.Controller
+
+	// zone is the zone of the Traefik instance, for the zone-aware load balancing.
+	zone string
+}
+
+// ManagerFactoryOptions holds the stores exposed through the API, if it is enabled.
+type ManagerFactoryOptions struct {
+	TenantRollups *tenant.Rollups
+	Accountant    *bandwidth.Accountant
+	Maintenance   *maintenance.Flags
+	Overrides     *overrides.Store
+	Snapshots     *snapshot.Store
+	ConnTrace     *conntrace.Filters
+	Connections   *connections.Registry
 }
 
 // NewManagerFactory creates a new ManagerFactory.
-func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *safe.Pool, metricsRegistry metrics.Registry, roundTripperManager *RoundTripperManager, acmeHTTPHandler http.Handler) *ManagerFactory {
+func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *safe.Pool, metricsRegistry metrics.Registry, roundTripperManager *RoundTripperManager, acmeHTTPHandler http.Handler, opts ManagerFactoryOptions) *ManagerFactory {
 	factory := &ManagerFactory{
 		metricsRegistry:     metricsRegistry,
 		routinesPool:        routinesPool,
 		roundTripperManager: roundTripperManager,
 		acmeHTTPHandler:     acmeHTTPHandler,
+		slowStart:           slowstart.NewTracker(),
+		canaries:            canary.NewController(),
+	}
+
+	if staticConfiguration.Locality != nil {
+		factory.zone = staticConfiguration.Locality.Zone
 	}
 
 	if staticConfiguration.API != nil {
-		apiRouterBuilder := api.NewBuilder(staticConfiguration)
+		apiRouterBuilder := api.NewBuilder(staticConfiguration, opts.TenantRollups, opts.Accountant, opts.Maintenance, opts.Overrides, opts.Snapshots, opts.ConnTrace, opts.Connections)
 
 		if staticConfiguration.API.Dashboard {
 			factory.dashboardHandler = dashboard.Handler{}
@@ -71,8 +106,12 @@ func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *s
 }
 
 // Build creates a service manager.
-func (f *ManagerFactory) Build(configuration *runtime.Configuration) *InternalHandlers {
-	svcManager := NewManager(configuration.Services, f.metricsRegistry, f.routinesPool, f.roundTripperManager)
+// The TCP services and middlewares are the ones the WebSocket bridges forward the connections into.
+func (f *ManagerFactory) Build(configuration *runtime.Configuration, tcpServiceManager tcpServiceManager, tcpMiddlewaresBuilder tcpMiddlewareBuilder) *InternalHandlers {
+	f.slowStart.NextGeneration()
+	f.canaries.NextGeneration()
+
+	svcManager := NewManager(configuration.Services, f.metricsRegistry, f.routinesPool, f.roundTripperManager, f.slowStart, f.canaries, f.zone, tcpServiceManager, tcpMiddlewaresBuilder)
 
 	var apiHandler http.Handler
 	if f.api != nil {
@@ -15,6 +15,7 @@ import (
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/unixsocket"
 	"golang.org/x/net/http/httpguts"
 )
 
@@ -46,6 +47,10 @@ func buildProxy(passHostHeader *bool, responseForwarding *dynamic.ResponseForwar
 				}
 			}
 
+			// The path of a unix:// server URL is the path of its socket,
+			// which is carried by the host of the HTTP URL standing for it instead.
+			outReq.URL = unixsocket.ToHTTP(outReq.URL)
+
 			outReq.URL.Path = u.Path
 			outReq.URL.RawPath = u.RawPath
 			// If a plugin/middleware adds semicolons in query params, they should be urlEncoded.
@@ -59,6 +64,9 @@ func buildProxy(passHostHeader *bool, responseForwarding *dynamic.ResponseForwar
 			// Do not pass client Host header unless optsetter PassHostHeader is set.
 			if passHostHeader != nil && !*passHostHeader {
 				outReq.Host = outReq.URL.Host
+				if unixsocket.IsHTTPHost(outReq.URL.Host) {
+					outReq.Host = "localhost"
+				}
 			}
 
 			// Even if the websocket RFC says that headers should be case-insensitive,
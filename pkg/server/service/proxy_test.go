@@ -1,12 +1,22 @@
 package service
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
 )
 
@@ -35,3 +45,104 @@ func BenchmarkProxy(b *testing.B) {
 		handler.ServeHTTP(w, req)
 	}
 }
+
+func TestProxy_unixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	backend := &http.Server{Handler: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = fmt.Fprintf(rw, "%s %s", req.Host, req.URL.RequestURI())
+	})}
+	go func() { _ = backend.Serve(listener) }()
+	t.Cleanup(func() { _ = backend.Close() })
+
+	roundTripper, err := createRoundTripper(&dynamic.ServersTransport{}, false, nil, nil)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc           string
+		passHostHeader bool
+		expected       string
+	}{
+		{
+			desc:           "pass host header",
+			passHostHeader: true,
+			expected:       "example.com /foo?bar=baz",
+		},
+		{
+			desc:     "without host header",
+			expected: "localhost /foo?bar=baz",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			handler, err := buildProxy(Bool(test.passHostHeader), nil, roundTripper, newBufferPool())
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/foo?bar=baz", nil)
+			// The load balancer forwards the request to the URL of the server.
+			req.URL = &url.URL{Scheme: "unix", Path: path}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, test.expected, recorder.Body.String())
+		})
+	}
+}
+
+func TestProxy_earlyHints(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Add("Link", "</style.css>; rel=preload; as=style")
+		rw.WriteHeader(http.StatusEarlyHints)
+
+		_, _ = rw.Write([]byte("Hello"))
+	}))
+	t.Cleanup(backend.Close)
+
+	backendURL, err := url.Parse(backend.URL)
+	require.NoError(t, err)
+
+	roundTripper, err := createRoundTripper(&dynamic.ServersTransport{}, false, nil, nil)
+	require.NoError(t, err)
+
+	handler, err := buildProxy(Bool(true), nil, roundTripper, newBufferPool())
+	require.NoError(t, err)
+
+	frontend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// The load balancer forwards the request to the URL of the server.
+		req.URL.Scheme = backendURL.Scheme
+		req.URL.Host = backendURL.Host
+
+		handler.ServeHTTP(rw, req)
+	}))
+	t.Cleanup(frontend.Close)
+
+	var hints []string
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			assert.Equal(t, http.StatusEarlyHints, code)
+			hints = append(hints, header["Link"]...)
+			return nil
+		},
+	}
+
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), http.MethodGet, frontend.URL, nil)
+	require.NoError(t, err)
+
+	res, err := frontend.Client().Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = res.Body.Close() })
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "Hello", string(body))
+	assert.Equal(t, []string{"</style.css>; rel=preload; as=style"}, hints)
+}
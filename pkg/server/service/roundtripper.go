@@ -7,13 +7,18 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"reflect"
 	"sync"
 	"time"
 
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/dnsresolver"
+	"github.com/traefik/traefik/v2/pkg/egress"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/sockopt"
 	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
+	"github.com/traefik/traefik/v2/pkg/unixsocket"
 	"golang.org/x/net/http2"
 )
 
@@ -39,6 +44,46 @@ type RoundTripperManager struct {
 	rtLock        sync.RWMutex
 	roundTrippers map[string]http.RoundTripper
 	configs       map[string]*dynamic.ServersTransport
+
+	// serverNameRoundTrippers are the roundtrippers of the servers overriding the server name of their servers transport,
+	// keyed by servers transport name, then by server name.
+	serverNameRoundTrippers map[string]map[string]http.RoundTripper
+
+	// fips restricts the TLS connections to the servers to the FIPS-approved parameters.
+	fips bool
+
+	// resolver looks the hostnames of the servers up, instead of the resolver of the operating system.
+	resolver *dnsresolver.Resolver
+
+	// egressPolicy restricts the destinations the servers are allowed to be dialed at.
+	egressPolicy *egress.Policy
+}
+
+// SetFIPS enables the FIPS mode, restricting the TLS connections to the servers to the FIPS-approved parameters.
+// It applies to the roundtrippers created by the next updates.
+func (r *RoundTripperManager) SetFIPS(enabled bool) {
+	r.rtLock.Lock()
+	defer r.rtLock.Unlock()
+
+	r.fips = enabled
+}
+
+// SetResolver sets the resolver looking the hostnames of the servers up.
+// It applies to the roundtrippers created by the next updates.
+func (r *RoundTripperManager) SetResolver(resolver *dnsresolver.Resolver) {
+	r.rtLock.Lock()
+	defer r.rtLock.Unlock()
+
+	r.resolver = resolver
+}
+
+// SetEgressPolicy sets the policy restricting the destinations the servers are allowed to be dialed at.
+// It applies to the roundtrippers created by the next updates.
+func (r *RoundTripperManager) SetEgressPolicy(policy *egress.Policy) {
+	r.rtLock.Lock()
+	defer r.rtLock.Unlock()
+
+	r.egressPolicy = policy
 }
 
 // Update updates the roundtrippers configurations.
@@ -51,6 +96,7 @@ func (r *RoundTripperManager) Update(newConfigs map[string]*dynamic.ServersTrans
 		if !ok {
 			delete(r.configs, configName)
 			delete(r.roundTrippers, configName)
+			delete(r.serverNameRoundTrippers, configName)
 			continue
 		}
 
@@ -58,8 +104,10 @@ func (r *RoundTripperManager) Update(newConfigs map[string]*dynamic.ServersTrans
 			continue
 		}
 
+		delete(r.serverNameRoundTrippers, configName)
+
 		var err error
-		r.roundTrippers[configName], err = createRoundTripper(newConfig)
+		r.roundTrippers[configName], err = createRoundTripper(newConfig, r.fips, r.resolver, r.egressPolicy)
 		if err != nil {
 			log.WithoutContext().Errorf("Could not configure HTTP Transport %s, fallback on default transport: %v", configName, err)
 			r.roundTrippers[configName] = http.DefaultTransport
@@ -72,7 +120,7 @@ func (r *RoundTripperManager) Update(newConfigs map[string]*dynamic.ServersTrans
 		}
 
 		var err error
-		r.roundTrippers[newConfigName], err = createRoundTripper(newConfig)
+		r.roundTrippers[newConfigName], err = createRoundTripper(newConfig, r.fips, r.resolver, r.egressPolicy)
 		if err != nil {
 			log.WithoutContext().Errorf("Could not configure HTTP Transport %s, fallback on default transport: %v", newConfigName, err)
 			r.roundTrippers[newConfigName] = http.DefaultTransport
@@ -98,11 +146,52 @@ func (r *RoundTripperManager) Get(name string) (http.RoundTripper, error) {
 	return nil, fmt.Errorf("servers transport not found %s", name)
 }
 
+// GetWithServerName gets a roundtripper configured by the servers transport of the given name,
+// but contacting the servers with the given server name (SNI) instead of the one of the servers transport.
+func (r *RoundTripperManager) GetWithServerName(name, serverName string) (http.RoundTripper, error) {
+	if len(name) == 0 {
+		name = "default@internal"
+	}
+
+	r.rtLock.Lock()
+	defer r.rtLock.Unlock()
+
+	config, ok := r.configs[name]
+	if !ok {
+		return nil, fmt.Errorf("servers transport not found %s", name)
+	}
+
+	if rt, ok := r.serverNameRoundTrippers[name][serverName]; ok {
+		return rt, nil
+	}
+
+	cfg := *config
+	cfg.ServerName = serverName
+
+	rt, err := createRoundTripper(&cfg, r.fips, r.resolver, r.egressPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("creating HTTP transport %s with server name %s: %w", name, serverName, err)
+	}
+
+	if r.serverNameRoundTrippers == nil {
+		r.serverNameRoundTrippers = make(map[string]map[string]http.RoundTripper)
+	}
+	if r.serverNameRoundTrippers[name] == nil {
+		r.serverNameRoundTrippers[name] = make(map[string]http.RoundTripper)
+	}
+	r.serverNameRoundTrippers[name][serverName] = rt
+
+	return rt, nil
+}
+
 // createRoundTripper creates an http.RoundTripper configured with the Transport configuration settings.
 // For the settings that can't be configured in Traefik it uses the default http.Transport settings.
 // An exception to this is the MaxIdleConns setting as we only provide the option MaxIdleConnsPerHost in Traefik at this point in time.
 // Setting this value to the default of 100 could lead to confusing behavior and backwards compatibility issues.
-func createRoundTripper(cfg *dynamic.ServersTransport) (http.RoundTripper, error) {
+// In FIPS mode, the TLS connections to the servers are restricted to the FIPS-approved parameters.
+// The resolver, if any, looks the hostnames of the servers up,
+// and the egress policy, if any, restricts the destinations they are dialed at.
+func createRoundTripper(cfg *dynamic.ServersTransport, fips bool, resolver *dnsresolver.Resolver, egressPolicy *egress.Policy) (http.RoundTripper, error) {
 	if cfg == nil {
 		return nil, errors.New("no transport configuration given")
 	}
@@ -116,9 +205,40 @@ func createRoundTripper(cfg *dynamic.ServersTransport) (http.RoundTripper, error
 		dialer.Timeout = time.Duration(cfg.ForwardingTimeouts.DialTimeout)
 	}
 
+	var controls []sockopt.ControlFunc
+
+	if egressPolicy != nil {
+		controls = append(controls, egressPolicy.Control())
+	}
+
+	if cfg.ConnectionMarking != nil {
+		control, err := markingControl(cfg.ConnectionMarking)
+		if err != nil {
+			return nil, err
+		}
+		controls = append(controls, control)
+	}
+
+	if cfg.CongestionControl != "" {
+		controls = append(controls, sockopt.CongestionControl(cfg.CongestionControl))
+	}
+
+	if len(controls) > 0 {
+		dialer.Control = sockopt.Chain(controls...)
+	}
+
+	if cfg.MultipathTCP {
+		dialer.SetMultipathTCP(true)
+	}
+
 	transport := &http.Transport{
-		Proxy:                 http.ProxyFromEnvironment,
-		DialContext:           dialer.DialContext,
+		Proxy: func(req *http.Request) (*url.URL, error) {
+			if unixsocket.IsHTTPHost(req.URL.Host) {
+				return nil, nil
+			}
+			return http.ProxyFromEnvironment(req)
+		},
+		DialContext:           unixsocket.DialContext(resolver.DialContext(dialer.DialContext)),
 		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
 		IdleConnTimeout:       90 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
@@ -132,7 +252,7 @@ func createRoundTripper(cfg *dynamic.ServersTransport) (http.RoundTripper, error
 		transport.IdleConnTimeout = time.Duration(cfg.ForwardingTimeouts.IdleConnTimeout)
 	}
 
-	if cfg.InsecureSkipVerify || len(cfg.RootCAs) > 0 || len(cfg.ServerName) > 0 || len(cfg.Certificates) > 0 || cfg.PeerCertURI != "" {
+	if fips || cfg.InsecureSkipVerify || len(cfg.RootCAs) > 0 || len(cfg.ServerName) > 0 || len(cfg.Certificates) > 0 || cfg.PeerCertURI != "" {
 		transport.TLSClientConfig = &tls.Config{
 			ServerName:         cfg.ServerName,
 			InsecureSkipVerify: cfg.InsecureSkipVerify,
@@ -145,6 +265,10 @@ func createRoundTripper(cfg *dynamic.ServersTransport) (http.RoundTripper, error
 				return traefiktls.VerifyPeerCertificate(cfg.PeerCertURI, transport.TLSClientConfig, rawCerts)
 			}
 		}
+
+		if fips {
+			traefiktls.RestrictToFIPS(transport.TLSClientConfig)
+		}
 	}
 
 	// Return directly HTTP/1.1 transport when HTTP/2 is disabled
@@ -152,7 +276,7 @@ func createRoundTripper(cfg *dynamic.ServersTransport) (http.RoundTripper, error
 		return transport, nil
 	}
 
-	return newSmartRoundTripper(transport, cfg.ForwardingTimeouts)
+	return newSmartRoundTripper(transport, cfg.ForwardingTimeouts, cfg.DetectH2C)
 }
 
 func createRootCACertPool(rootCAs []traefiktls.FileOrContent) *x509.CertPool {
@@ -173,3 +297,26 @@ func createRootCACertPool(rootCAs []traefiktls.FileOrContent) *x509.CertPool {
 
 	return roots
 }
+
+// serversRoundTripper applies the overrides of the server name and of the Host header of the servers of a service,
+// keyed by the host of their URL.
+type serversRoundTripper struct {
+	next          http.RoundTripper
+	roundTrippers map[string]http.RoundTripper
+	hosts         map[string]string
+}
+
+func (s *serversRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if host, ok := s.hosts[req.URL.Host]; ok {
+		outReq := new(http.Request)
+		*outReq = *req
+		outReq.Host = host
+		req = outReq
+	}
+
+	if rt, ok := s.roundTrippers[req.URL.Host]; ok {
+		return rt.RoundTrip(req)
+	}
+
+	return s.next.RoundTrip(req)
+}
@@ -0,0 +1,161 @@
+//go:build linux
+// +build linux
+
+package service
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"golang.org/x/sys/unix"
+)
+
+// Flow label management options, from linux/in6.h, which are not defined by the unix package.
+const (
+	ipv6FlowlabelMgr = 32
+	ipv6FlowinfoSend = 33
+
+	ipv6FlActionGet  = 0
+	ipv6FlFlagCreate = 1
+	ipv6FlShareAny   = 255
+
+	maxFlowLabel = 0xfffff
+)
+
+// in6FlowlabelReq is the in6_flowlabel_req struct of linux/in6.h.
+type in6FlowlabelReq struct {
+	Dst     [16]byte
+	Label   uint32
+	Action  uint8
+	Share   uint8
+	Flags   uint16
+	Expires uint16
+	Linger  uint16
+	_       uint32
+}
+
+// markingControl returns the dialer control function setting the marks on the connections to the backend servers.
+func markingControl(marking *dynamic.ConnectionMarking) (func(network, address string, c syscall.RawConn) error, error) {
+	if marking.DSCP < 0 || marking.DSCP > 63 {
+		return nil, fmt.Errorf("invalid DSCP %d: must be between 0 and 63", marking.DSCP)
+	}
+
+	if marking.FlowLabel > maxFlowLabel {
+		return nil, fmt.Errorf("invalid flow label %d: must be between 1 and %d", marking.FlowLabel, maxFlowLabel)
+	}
+
+	return func(network, address string, c syscall.RawConn) error {
+		var errOpt error
+		err := c.Control(func(fd uintptr) {
+			errOpt = mark(int(fd), network, address, marking)
+		})
+		if err != nil {
+			return err
+		}
+
+		return errOpt
+	}, nil
+}
+
+func mark(fd int, network, address string, marking *dynamic.ConnectionMarking) error {
+	// The connections to the Unix domain sockets do not go through the network.
+	if network != "tcp4" && network != "tcp6" {
+		return nil
+	}
+
+	if marking.Mark != 0 {
+		if err := unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_MARK, int(marking.Mark)); err != nil {
+			return os.NewSyscallError("setsockopt", err)
+		}
+	}
+
+	if marking.DSCP != 0 {
+		// The DSCP is the 6 most significant bits of the TOS field and of the traffic class.
+		level, opt := unix.IPPROTO_IP, unix.IP_TOS
+		if network == "tcp6" {
+			level, opt = unix.IPPROTO_IPV6, unix.IPV6_TCLASS
+		}
+
+		if err := unix.SetsockoptInt(fd, level, opt, marking.DSCP<<2); err != nil {
+			return os.NewSyscallError("setsockopt", err)
+		}
+	}
+
+	if marking.FlowLabel != 0 && network == "tcp6" {
+		return connectWithFlowLabel(fd, address, marking.FlowLabel)
+	}
+
+	return nil
+}
+
+// connectWithFlowLabel connects the socket to the address with the flow label.
+// The flow label of a TCP connection is taken from the address it is connected to,
+// which the dialer of the net package always leaves empty,
+// so the socket is connected here instead, and the dialer then waits for the connection as if it had started it.
+func connectWithFlowLabel(fd int, address string, label uint32) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	sa := unix.RawSockaddrInet6{
+		Family:   unix.AF_INET6,
+		Addr:     addrPort.Addr().As16(),
+		Flowinfo: htonl(label),
+	}
+	binary.BigEndian.PutUint16((*[2]byte)(unsafe.Pointer(&sa.Port))[:], addrPort.Port())
+
+	if zone := addrPort.Addr().Zone(); zone != "" {
+		iface, err := net.InterfaceByName(zone)
+		if err != nil {
+			return err
+		}
+		sa.Scope_id = uint32(iface.Index)
+	}
+
+	// The flow label has to be registered before being sent, it is shared with the other sockets sending it.
+	req := in6FlowlabelReq{
+		Dst:    sa.Addr,
+		Label:  sa.Flowinfo,
+		Action: ipv6FlActionGet,
+		Share:  ipv6FlShareAny,
+		Flags:  ipv6FlFlagCreate,
+	}
+	if err := setsockopt(fd, unix.IPPROTO_IPV6, ipv6FlowlabelMgr, unsafe.Pointer(&req), unsafe.Sizeof(req)); err != nil {
+		return os.NewSyscallError("setsockopt", err)
+	}
+
+	if err := unix.SetsockoptInt(fd, unix.IPPROTO_IPV6, ipv6FlowinfoSend, 1); err != nil {
+		return os.NewSyscallError("setsockopt", err)
+	}
+
+	_, _, errno := unix.Syscall(unix.SYS_CONNECT, uintptr(fd), uintptr(unsafe.Pointer(&sa)), unsafe.Sizeof(sa))
+	if errno != 0 && errno != unix.EINPROGRESS {
+		return os.NewSyscallError("connect", errno)
+	}
+
+	return nil
+}
+
+func setsockopt(fd, level, opt int, value unsafe.Pointer, size uintptr) error {
+	_, _, errno := unix.Syscall6(unix.SYS_SETSOCKOPT, uintptr(fd), uintptr(level), uintptr(opt), uintptr(value), size, 0)
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+// htonl returns the value in network byte order.
+func htonl(v uint32) uint32 {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+
+	return *(*uint32)(unsafe.Pointer(&b))
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"golang.org/x/sys/unix"
+)
+
+// ipv6Flowinfo is the IPV6_FLOWINFO option of linux/in6.h, recording the flow labels of the received packets.
+const ipv6Flowinfo = 11
+
+func TestMarkingControl_invalid(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		marking *dynamic.ConnectionMarking
+	}{
+		{
+			desc:    "DSCP out of range",
+			marking: &dynamic.ConnectionMarking{DSCP: 64},
+		},
+		{
+			desc:    "negative DSCP",
+			marking: &dynamic.ConnectionMarking{DSCP: -1},
+		},
+		{
+			desc:    "flow label out of range",
+			marking: &dynamic.ConnectionMarking{FlowLabel: 0x100000},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := createRoundTripper(&dynamic.ServersTransport{ConnectionMarking: test.marking}, false, nil, nil)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestMarkingControl_tcp4(t *testing.T) {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	control, err := markingControl(&dynamic.ConnectionMarking{Mark: 42, DSCP: 46})
+	require.NoError(t, err)
+
+	dialer := net.Dialer{Control: control}
+	conn, err := dialer.Dial("tcp4", listener.Addr().String())
+	if errors.Is(err, syscall.EPERM) {
+		t.Skip("setting SO_MARK requires CAP_NET_ADMIN")
+	}
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	assert.Equal(t, 42, getsockoptInt(t, conn, unix.SOL_SOCKET, unix.SO_MARK))
+	assert.Equal(t, 46<<2, getsockoptInt(t, conn, unix.IPPROTO_IP, unix.IP_TOS))
+}
+
+func TestMarkingControl_tcp6(t *testing.T) {
+	listener, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skip("IPv6 is not available")
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	rawConn, err := listener.(*net.TCPListener).SyscallConn()
+	require.NoError(t, err)
+	require.NoError(t, rawConn.Control(func(fd uintptr) {
+		require.NoError(t, unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, ipv6Flowinfo, 1))
+	}))
+
+	control, err := markingControl(&dynamic.ConnectionMarking{DSCP: 10, FlowLabel: 0x12345})
+	require.NoError(t, err)
+
+	dialer := net.Dialer{Control: control}
+	conn, err := dialer.Dial("tcp6", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	assert.Equal(t, 10<<2, getsockoptInt(t, conn, unix.IPPROTO_IPV6, unix.IPV6_TCLASS))
+
+	server, err := listener.Accept()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = server.Close() })
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	_, err = server.Read(make([]byte, 4))
+	require.NoError(t, err)
+
+	// Reads the flow label of the peer (IPV6_FL_F_REMOTE).
+	req := in6FlowlabelReq{Flags: 8}
+	size := uint32(unsafe.Sizeof(req))
+	serverConn, err := server.(*net.TCPConn).SyscallConn()
+	require.NoError(t, err)
+	require.NoError(t, serverConn.Control(func(fd uintptr) {
+		_, _, errno := unix.Syscall6(unix.SYS_GETSOCKOPT, fd, unix.IPPROTO_IPV6, ipv6FlowlabelMgr, uintptr(unsafe.Pointer(&req)), uintptr(unsafe.Pointer(&size)), 0)
+		require.Zero(t, errno)
+	}))
+
+	assert.Equal(t, htonl(0x12345), req.Label)
+}
+
+func getsockoptInt(t *testing.T, conn net.Conn, level, opt int) int {
+	t.Helper()
+
+	rawConn, err := conn.(*net.TCPConn).SyscallConn()
+	require.NoError(t, err)
+
+	var value int
+	var errOpt error
+	require.NoError(t, rawConn.Control(func(fd uintptr) {
+		value, errOpt = unix.GetsockoptInt(int(fd), level, opt)
+	}))
+	require.NoError(t, errOpt)
+
+	return value
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+// +build !linux
+
+package service
+
+import (
+	"errors"
+	"syscall"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// markingControl fails, as the marks are set with Linux socket options.
+func markingControl(_ *dynamic.ConnectionMarking) (func(network, address string, c syscall.RawConn) error, error) {
+	return nil, errors.New("connection marking is only supported on Linux")
+}
@@ -8,11 +8,14 @@ import (
 	"net/http/httptest"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 func Int32(i int32) *int32 {
@@ -293,3 +296,72 @@ func TestDisableHTTP2(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectH2C(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		serverH2C     bool
+		expectedProto string
+	}{
+		{
+			desc:          "HTTP1 server",
+			expectedProto: "HTTP/1.1",
+		},
+		{
+			desc:          "h2c server",
+			serverH2C:     true,
+			expectedProto: "HTTP/2.0",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var handler http.Handler = http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+			})
+			if test.serverH2C {
+				handler = h2c.NewHandler(handler, &http2.Server{})
+			}
+
+			srv := httptest.NewServer(handler)
+			t.Cleanup(srv.Close)
+
+			rtManager := NewRoundTripperManager()
+			rtManager.Update(map[string]*dynamic.ServersTransport{
+				"test": {DetectH2C: true},
+			})
+
+			tr, err := rtManager.Get("test")
+			require.NoError(t, err)
+
+			client := http.Client{Transport: tr}
+
+			// The first request is sent over HTTP/1.1, while the server is probed.
+			resp, err := client.Get(srv.URL)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+
+			assert.Equal(t, "HTTP/1.1", resp.Proto)
+
+			assert.Eventually(t, func() bool {
+				resp, err := client.Get(srv.URL)
+				if err != nil {
+					return false
+				}
+				_ = resp.Body.Close()
+
+				return resp.Proto == test.expectedProto
+			}, 5*time.Second, 10*time.Millisecond)
+
+			// The probe of the HTTP1 servers is not retried before the detection interval.
+			resp, err = client.Get(srv.URL)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+
+			assert.Equal(t, test.expectedProto, resp.Proto)
+		})
+	}
+}
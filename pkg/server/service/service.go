@@ -21,12 +21,21 @@ import (
 	"github.com/traefik/traefik/v2/pkg/middlewares/emptybackendhandler"
 	metricsMiddle "github.com/traefik/traefik/v2/pkg/middlewares/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/pipelining"
+	"github.com/traefik/traefik/v2/pkg/middlewares/responsetimeout"
+	"github.com/traefik/traefik/v2/pkg/middlewares/serveridentity"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/server/cookie"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/canary"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/consistenthash"
 	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/failover"
 	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/mirror"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/slowstart"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/strategy"
 	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/wrr"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/zoneaware"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+	"github.com/traefik/traefik/v2/pkg/unixsocket"
 	"github.com/vulcand/oxy/v2/roundrobin"
 	"github.com/vulcand/oxy/v2/roundrobin/stickycookie"
 )
@@ -41,18 +50,32 @@ const defaultMaxBodySize int64 = -1
 // RoundTripperGetter is a roundtripper getter interface.
 type RoundTripperGetter interface {
 	Get(name string) (http.RoundTripper, error)
+	GetWithServerName(name, serverName string) (http.RoundTripper, error)
+}
+
+type tcpMiddlewareBuilder interface {
+	BuildChain(ctx context.Context, names []string) *tcp.Chain
+}
+
+type tcpServiceManager interface {
+	BuildTCP(rootCtx context.Context, serviceName string) (tcp.Handler, error)
 }
 
 // NewManager creates a new Manager.
-func NewManager(configs map[string]*runtime.ServiceInfo, metricsRegistry metrics.Registry, routinePool *safe.Pool, roundTripperManager RoundTripperGetter) *Manager {
+func NewManager(configs map[string]*runtime.ServiceInfo, metricsRegistry metrics.Registry, routinePool *safe.Pool, roundTripperManager RoundTripperGetter, slowStart *slowstart.Tracker, canaries *canary.Controller, zone string, tcpServiceManager tcpServiceManager, tcpMiddlewaresBuilder tcpMiddlewareBuilder) *Manager {
 	return &Manager{
-		routinePool:         routinePool,
-		metricsRegistry:     metricsRegistry,
-		bufferPool:          newBufferPool(),
-		roundTripperManager: roundTripperManager,
-		balancers:           make(map[string]healthcheck.Balancers),
-		configs:             configs,
-		rand:                rand.New(rand.NewSource(time.Now().UnixNano())),
+		tcpServiceManager:     tcpServiceManager,
+		tcpMiddlewaresBuilder: tcpMiddlewaresBuilder,
+		slowStart:             slowStart,
+		canaries:              canaries,
+		zone:                  zone,
+		routinePool:           routinePool,
+		metricsRegistry:       metricsRegistry,
+		bufferPool:            newBufferPool(),
+		roundTripperManager:   roundTripperManager,
+		balancers:             make(map[string]healthcheck.Balancers),
+		configs:               configs,
+		rand:                  rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
@@ -69,6 +92,13 @@ type Manager struct {
 	balancers map[string]healthcheck.Balancers
 	configs   map[string]*runtime.ServiceInfo
 	rand      *rand.Rand // For the initial shuffling of load-balancers.
+	slowStart *slowstart.Tracker
+	canaries  *canary.Controller
+	zone      string // The zone of the Traefik instance, for the zone-aware load balancing.
+
+	// The TCP services and middlewares, into which the WebSocket bridges forward the connections.
+	tcpServiceManager     tcpServiceManager
+	tcpMiddlewaresBuilder tcpMiddlewareBuilder
 }
 
 // BuildHTTP Creates a http.Handler for a service configuration.
@@ -127,6 +157,20 @@ func (m *Manager) BuildHTTP(rootCtx context.Context, serviceName string) (http.H
 			conf.AddError(err, true)
 			return nil, err
 		}
+	case conf.Subset != nil:
+		var err error
+		lb, err = m.getSubsetServiceHandler(ctx, serviceName, conf.Subset)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
+	case conf.WebSocketBridge != nil:
+		var err error
+		lb, err = m.getWebSocketBridgeServiceHandler(ctx, conf.WebSocketBridge)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
 	default:
 		sErr := fmt.Errorf("the service %q does not have any type defined", serviceName)
 		conf.AddError(sErr, true)
@@ -183,6 +227,65 @@ func (m *Manager) getFailoverServiceHandler(ctx context.Context, serviceName str
 	return f, nil
 }
 
+func (m *Manager) getSubsetServiceHandler(ctx context.Context, serviceName string, config *dynamic.Subset) (http.Handler, error) {
+	parentName := provider.GetQualifiedName(ctx, config.Service)
+
+	parent, ok := m.configs[parentName]
+	if !ok {
+		return nil, fmt.Errorf("the service %q does not exist", parentName)
+	}
+
+	if parent.LoadBalancer == nil {
+		return nil, fmt.Errorf("the service %q of the subset %q is not a load-balancer service", parentName, serviceName)
+	}
+
+	service := parent.LoadBalancer.DeepCopy()
+	service.Servers = nil
+	for _, server := range parent.LoadBalancer.Servers {
+		if matchLabels(server.Labels, config.Labels) {
+			service.Servers = append(service.Servers, *server.DeepCopy())
+		}
+	}
+
+	log.FromContext(ctx).Debugf("Subset of %d servers of the service %s", len(service.Servers), parentName)
+
+	// The configuration of the parent service is resolved in the context of its provider.
+	return m.getLoadBalancerServiceHandler(provider.AddInContext(ctx, parentName), serviceName, service)
+}
+
+func (m *Manager) getWebSocketBridgeServiceHandler(ctx context.Context, config *dynamic.WebSocketBridge) (http.Handler, error) {
+	if config.Service == "" {
+		return nil, errors.New("the TCP service of the WebSocket bridge is missing")
+	}
+
+	if m.tcpServiceManager == nil || m.tcpMiddlewaresBuilder == nil {
+		return nil, errors.New("the WebSocket bridges are not supported")
+	}
+
+	serviceHandler, err := m.tcpServiceManager.BuildTCP(ctx, config.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	handler, err := m.tcpMiddlewaresBuilder.BuildChain(ctx, config.Middlewares).Then(serviceHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return tcp.NewWebSocketHandler(handler, config.AllowedOrigins), nil
+}
+
+// matchLabels tells whether the labels have all the given selector labels, with the same values.
+func matchLabels(labels, selector map[string]string) bool {
+	for key, value := range selector {
+		if v, ok := labels[key]; !ok || v != value {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (m *Manager) getMirrorServiceHandler(ctx context.Context, config *dynamic.Mirroring) (http.Handler, error) {
 	serviceHandler, err := m.BuildHTTP(ctx, config.Service)
 	if err != nil {
@@ -214,14 +317,30 @@ func (m *Manager) getWRRServiceHandler(ctx context.Context, serviceName string,
 		config.Sticky.Cookie.Name = cookie.GetName(config.Sticky.Cookie.Name, serviceName)
 	}
 
-	balancer := wrr.New(config.Sticky, config.HealthCheck)
+	var rollout *canary.Rollout
+	if config.Canary != nil && m.canaries != nil {
+		if !hasWRRService(config.Services, config.Canary.Service) {
+			return nil, fmt.Errorf("canary service %q is not one of the services of %s", config.Canary.Service, serviceName)
+		}
+
+		rollout = m.canaries.Rollout(serviceName, *config.Canary)
+	}
+
+	balancer := wrr.New(config.Sticky, config.HealthCheck, config.Claim)
 	for _, service := range shuffle(config.Services, m.rand) {
 		serviceHandler, err := m.BuildHTTP(ctx, service.Name)
 		if err != nil {
 			return nil, err
 		}
 
-		balancer.AddService(service.Name, serviceHandler, service.Weight)
+		weight := service.Weight
+		if rollout != nil && service.Name == config.Canary.Service {
+			canaryWeight := rollout.Weight()
+			weight = &canaryWeight
+			serviceHandler = rollout.Wrap(serviceHandler)
+		}
+
+		balancer.AddService(service.Name, serviceHandler, weight)
 
 		if config.HealthCheck == nil {
 			continue
@@ -242,9 +361,23 @@ func (m *Manager) getWRRServiceHandler(ctx context.Context, serviceName string,
 		log.FromContext(ctx).Debugf("Child service %v will update parent %v on status change", childName, serviceName)
 	}
 
+	if rollout != nil {
+		rollout.Attach(balancer)
+	}
+
 	return balancer, nil
 }
 
+func hasWRRService(services []dynamic.WRRService, name string) bool {
+	for _, service := range services {
+		if service.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName string, service *dynamic.ServersLoadBalancer) (http.Handler, error) {
 	if service.PassHostHeader == nil {
 		defaultPassHostHeader := true
@@ -255,7 +388,7 @@ func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName
 		service.ServersTransport = provider.GetQualifiedName(ctx, service.ServersTransport)
 	}
 
-	roundTripper, err := m.roundTripperManager.Get(service.ServersTransport)
+	roundTripper, err := m.getServersRoundTripper(service, true)
 	if err != nil {
 		return nil, err
 	}
@@ -273,7 +406,21 @@ func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName
 		chain = chain.Append(metricsMiddle.WrapServiceHandler(ctx, m.metricsRegistry, serviceName))
 	}
 
-	handler, err := chain.Append(alHandler).Then(pipelining.New(ctx, fwd, "pipelining"))
+	chain = chain.Append(alHandler)
+
+	if service.ServerIdentity != nil {
+		chain = chain.Append(func(next http.Handler) (http.Handler, error) {
+			return serveridentity.New(ctx, next, *service.ServerIdentity, "server-identity"), nil
+		})
+	}
+
+	if service.ResponseTimeouts != nil {
+		chain = chain.Append(func(next http.Handler) (http.Handler, error) {
+			return responsetimeout.New(ctx, next, *service.ResponseTimeouts, "response-timeouts")
+		})
+	}
+
+	handler, err := chain.Then(pipelining.New(ctx, fwd, "pipelining"))
 	if err != nil {
 		return nil, err
 	}
@@ -290,6 +437,50 @@ func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName
 	return emptybackendhandler.New(balancer), nil
 }
 
+// getServersRoundTripper returns the roundtripper of the servers transport of the service,
+// applying the server name and the Host header overrides of its servers, if any.
+func (m *Manager) getServersRoundTripper(service *dynamic.ServersLoadBalancer, overrideHost bool) (http.RoundTripper, error) {
+	roundTripper, err := m.roundTripperManager.Get(service.ServersTransport)
+	if err != nil {
+		return nil, err
+	}
+
+	srt := &serversRoundTripper{
+		next:          roundTripper,
+		roundTrippers: make(map[string]http.RoundTripper),
+		hosts:         make(map[string]string),
+	}
+
+	for _, srv := range service.Servers {
+		if srv.ServerName == "" && (srv.Host == "" || !overrideHost) {
+			continue
+		}
+
+		u, err := url.Parse(srv.URL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing server URL %s: %w", srv.URL, err)
+		}
+		host := unixsocket.ToHTTP(u).Host
+
+		if srv.ServerName != "" {
+			srt.roundTrippers[host], err = m.roundTripperManager.GetWithServerName(service.ServersTransport, srv.ServerName)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if srv.Host != "" && overrideHost {
+			srt.hosts[host] = srv.Host
+		}
+	}
+
+	if len(srt.roundTrippers) == 0 && len(srt.hosts) == 0 {
+		return roundTripper, nil
+	}
+
+	return srt, nil
+}
+
 // LaunchHealthCheck launches the health checks.
 func (m *Manager) LaunchHealthCheck() {
 	backendConfigs := make(map[string]*healthcheck.BackendConfig)
@@ -304,7 +495,8 @@ func (m *Manager) LaunchHealthCheck() {
 		if hcOpts == nil {
 			continue
 		}
-		hcOpts.Transport, _ = m.roundTripperManager.Get(service.ServersTransport)
+		// The hostname of the health checks takes precedence over the Host header of the servers.
+		hcOpts.Transport, _ = m.getServersRoundTripper(service, service.HealthCheck.Hostname == "")
 		log.FromContext(ctx).Debugf("Setting up healthcheck for service %s with %s", serviceName, *hcOpts)
 
 		backendConfigs[serviceName] = healthcheck.NewBackendConfig(*hcOpts, serviceName)
@@ -376,6 +568,7 @@ func (m *Manager) getLoadBalancer(ctx context.Context, serviceName string, servi
 
 	var options []roundrobin.LBOption
 
+	var sticky *roundrobin.StickySession
 	var cookieName string
 	if service.Sticky != nil && service.Sticky.Cookie != nil {
 		cookieName = cookie.GetName(service.Sticky.Cookie.Name, serviceName)
@@ -392,17 +585,70 @@ func (m *Manager) getLoadBalancer(ctx context.Context, serviceName string, servi
 			return nil, err
 		}
 
-		options = append(options, roundrobin.EnableStickySession(roundrobin.NewStickySessionWithOptions(cookieName, opts).SetCookieValue(cv)))
+		sticky = roundrobin.NewStickySessionWithOptions(cookieName, opts).SetCookieValue(cv)
+		options = append(options, roundrobin.EnableStickySession(sticky))
 
 		logger.Debugf("Sticky session cookie name: %v", cookieName)
 	}
 
-	lb, err := roundrobin.New(fwd, options...)
+	if service.ConsistentHash != nil {
+		if service.Strategy != "" && service.Strategy != strategy.WRR {
+			return nil, fmt.Errorf("the %s load-balancing strategy cannot be used with consistent hashing", service.Strategy)
+		}
+
+		if sticky != nil {
+			logger.Warn("Sticky session ignored: the servers are picked by consistent hashing")
+		}
+
+		logger.Debug("Consistent hashing load balancing")
+	}
+
+	newBalancer := func() (healthcheck.BalancerHandler, error) {
+		switch {
+		case service.ConsistentHash != nil:
+			return consistenthash.NewBalancer(fwd, *service.ConsistentHash)
+		case service.Strategy == "", service.Strategy == strategy.WRR:
+			return roundrobin.New(fwd, options...)
+		default:
+			return strategy.NewBalancer(fwd, service.Strategy, sticky)
+		}
+	}
+
+	if service.Strategy != "" {
+		logger.Debugf("Load-balancing strategy: %s", service.Strategy)
+	}
+
+	var bh healthcheck.BalancerHandler
+	var err error
+	if service.ZoneAware != nil && m.zone != "" {
+		logger.Debugf("Zone-aware load balancing in zone: %s", m.zone)
+
+		bh, err = m.getZoneAwareBalancer(service, newBalancer)
+	} else {
+		if service.ZoneAware != nil {
+			logger.Warn("Zone-aware load balancing ignored: the zone of the Traefik instance is not configured")
+		}
+
+		bh, err = newBalancer()
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	lbsu := healthcheck.NewLBStatusUpdater(lb, m.configs[serviceName], service.HealthCheck)
+	if service.SlowStart != nil {
+		window := time.Duration(service.SlowStart.Window)
+		if window <= 0 {
+			window = slowstart.DefaultWindow
+		}
+
+		logger.Debugf("Slow start window: %s", window)
+
+		bh = slowstart.NewBalancer(bh, window, func(u *url.URL) time.Time {
+			return m.slowStart.Since(serviceName, u.String())
+		})
+	}
+
+	lbsu := healthcheck.NewLBStatusUpdater(bh, m.configs[serviceName], service.HealthCheck)
 	if err := m.upsertServers(ctx, lbsu, service.Servers); err != nil {
 		return nil, fmt.Errorf("error configuring load balancer for service %s: %w", serviceName, err)
 	}
@@ -410,6 +656,45 @@ func (m *Manager) getLoadBalancer(ctx context.Context, serviceName string, servi
 	return lbsu, nil
 }
 
+// getZoneAwareBalancer returns a load balancer preferring the servers of the zone of the Traefik instance,
+// each zone being balanced by a load balancer created by newBalancer.
+func (m *Manager) getZoneAwareBalancer(service *dynamic.ServersLoadBalancer, newBalancer func() (healthcheck.BalancerHandler, error)) (healthcheck.BalancerHandler, error) {
+	local, err := newBalancer()
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := newBalancer()
+	if err != nil {
+		return nil, err
+	}
+
+	localServers := make(map[string]struct{})
+	for _, srv := range service.Servers {
+		if srv.Zone != m.zone {
+			continue
+		}
+
+		u, err := url.Parse(srv.URL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing server URL %s: %w", srv.URL, err)
+		}
+		localServers[u.String()] = struct{}{}
+	}
+
+	minServers := service.ZoneAware.MinServers
+	if minServers <= 0 {
+		minServers = 1
+	}
+
+	isLocal := func(u *url.URL) bool {
+		_, ok := localServers[u.String()]
+		return ok
+	}
+
+	return zoneaware.NewBalancer(local, remote, isLocal, minServers, service.ZoneAware.MaxInFlight), nil
+}
+
 func (m *Manager) upsertServers(ctx context.Context, lb healthcheck.BalancerHandler, servers []dynamic.Server) error {
 	logger := log.FromContext(ctx)
 
@@ -9,12 +9,17 @@ import (
 	"net/textproto"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/canary"
+	"github.com/traefik/traefik/v2/pkg/tcp"
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
 )
 
@@ -63,6 +68,62 @@ func TestGetLoadBalancer(t *testing.T) {
 			fwd:         &MockForwarder{},
 			expectError: false,
 		},
+		{
+			desc:        "Succeeds when the strategy is leastconn",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy: "leastconn",
+				Servers: []dynamic.Server{
+					{
+						URL: "http://foo",
+					},
+				},
+			},
+			fwd:         &MockForwarder{},
+			expectError: false,
+		},
+		{
+			desc:        "Fails when the strategy is unknown",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy: "foo",
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
+		{
+			desc:        "Succeeds when consistent hashing is set",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				ConsistentHash: &dynamic.ConsistentHash{Header: "X-Key"},
+				Servers: []dynamic.Server{
+					{
+						URL: "http://foo",
+					},
+				},
+			},
+			fwd:         &MockForwarder{},
+			expectError: false,
+		},
+		{
+			desc:        "Fails when consistent hashing has no key",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				ConsistentHash: &dynamic.ConsistentHash{},
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
+		{
+			desc:        "Fails when consistent hashing is set with a strategy",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy:       "leastconn",
+				ConsistentHash: &dynamic.ConsistentHash{Header: "X-Key"},
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
 	}
 
 	for _, test := range testCases {
@@ -82,12 +143,39 @@ func TestGetLoadBalancer(t *testing.T) {
 	}
 }
 
+func TestGetLoadBalancer_zoneAware(t *testing.T) {
+	sm := NewManager(nil, nil, nil, nil, nil, nil, "zone-a", nil, nil)
+
+	fwd := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-From", req.URL.Host)
+	})
+
+	service := &dynamic.ServersLoadBalancer{
+		ZoneAware: &dynamic.ZoneAware{},
+		Servers: []dynamic.Server{
+			{URL: "http://remote", Zone: "zone-b"},
+			{URL: "http://local", Zone: "zone-a"},
+			{URL: "http://unknown"},
+		},
+	}
+
+	handler, err := sm.getLoadBalancer(context.Background(), "test", service, fwd)
+	require.NoError(t, err)
+	assert.Len(t, handler.Servers(), 3)
+
+	for i := 0; i < 3; i++ {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "local", recorder.Header().Get("X-From"))
+	}
+}
+
 func TestGetLoadBalancerServiceHandler(t *testing.T) {
 	sm := NewManager(nil, nil, nil, &RoundTripperManager{
 		roundTrippers: map[string]http.RoundTripper{
 			"default@internal": http.DefaultTransport,
 		},
-	})
+	}, nil, nil, "", nil, nil)
 
 	server1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("X-From", "first")
@@ -127,6 +215,7 @@ func TestGetLoadBalancerServiceHandler(t *testing.T) {
 		LoadBalanced   bool
 		SecureCookie   bool
 		HTTPOnlyCookie bool
+		ServedBy       string
 	}
 
 	testCases := []struct {
@@ -300,6 +389,25 @@ func TestGetLoadBalancerServiceHandler(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:        "Server identity in the response",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				ServerIdentity: &dynamic.ServerIdentity{},
+				Servers: []dynamic.Server{
+					{
+						URL: server1.URL,
+					},
+				},
+			},
+			expected: []ExpectedResult{
+				{
+					StatusCode: http.StatusOK,
+					XFrom:      "first",
+					ServedBy:   server1.URL,
+				},
+			},
+		},
 		{
 			desc:        "No user-agent",
 			serviceName: "test",
@@ -366,6 +474,8 @@ func TestGetLoadBalancerServiceHandler(t *testing.T) {
 					assert.Equal(t, expected.XFrom, recorder.Header().Get("X-From"))
 				}
 
+				assert.Equal(t, expected.ServedBy, recorder.Header().Get("X-Served-By"))
+
 				xFrom := recorder.Header().Get("X-From")
 				if prevXFrom != "" {
 					if expected.LoadBalanced {
@@ -394,7 +504,7 @@ func Test1xxResponses(t *testing.T) {
 		roundTrippers: map[string]http.RoundTripper{
 			"default@internal": http.DefaultTransport,
 		},
-	})
+	}, nil, nil, "", nil, nil)
 
 	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		h := w.Header()
@@ -478,6 +588,66 @@ func Test1xxResponses(t *testing.T) {
 	}
 }
 
+func TestGetLoadBalancerServiceHandler_serverOverrides(t *testing.T) {
+	rtManager := NewRoundTripperManager()
+	rtManager.Update(map[string]*dynamic.ServersTransport{
+		"default@internal": {InsecureSkipVerify: true},
+	})
+
+	sm := NewManager(nil, nil, nil, rtManager, nil, nil, "", nil, nil)
+
+	overridden := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Server-Name", r.TLS.ServerName)
+		w.Header().Set("X-Host", r.Host)
+	}))
+	t.Cleanup(overridden.Close)
+
+	notOverridden := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Server-Name", r.TLS.ServerName)
+		w.Header().Set("X-Host", r.Host)
+	}))
+	t.Cleanup(notOverridden.Close)
+
+	testCases := []struct {
+		desc               string
+		server             dynamic.Server
+		expectedServerName string
+		expectedHost       string
+	}{
+		{
+			desc:         "without overrides",
+			server:       dynamic.Server{URL: notOverridden.URL},
+			expectedHost: "callme",
+		},
+		{
+			desc:               "with overrides",
+			server:             dynamic.Server{URL: overridden.URL, ServerName: "sni.example.com", Host: "host.example.com"},
+			expectedServerName: "sni.example.com",
+			expectedHost:       "host.example.com",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := sm.getLoadBalancerServiceHandler(context.Background(), "foobar", &dynamic.ServersLoadBalancer{
+				Servers: []dynamic.Server{test.server},
+			})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://callme", nil)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, test.expectedServerName, recorder.Header().Get("X-Server-Name"))
+			assert.Equal(t, test.expectedHost, recorder.Header().Get("X-Host"))
+		})
+	}
+}
+
 func TestManager_Build(t *testing.T) {
 	testCases := []struct {
 		desc         string
@@ -530,7 +700,7 @@ func TestManager_Build(t *testing.T) {
 				roundTrippers: map[string]http.RoundTripper{
 					"default@internal": http.DefaultTransport,
 				},
-			})
+			}, nil, nil, "", nil, nil)
 
 			ctx := context.Background()
 			if len(test.providerName) > 0 {
@@ -543,6 +713,223 @@ func TestManager_Build(t *testing.T) {
 	}
 }
 
+func TestManager_Build_subset(t *testing.T) {
+	server1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-From", "v1")
+	}))
+	t.Cleanup(server1.Close)
+
+	server2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-From", "v2")
+	}))
+	t.Cleanup(server2.Close)
+
+	configs := map[string]*runtime.ServiceInfo{
+		"parent@provider-1": {
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{
+					Servers: []dynamic.Server{
+						{URL: server1.URL, Labels: map[string]string{"version": "v1"}},
+						{URL: server2.URL, Labels: map[string]string{"version": "v2", "gpu": "true"}},
+					},
+				},
+			},
+		},
+		"canary@provider-2": {
+			Service: &dynamic.Service{
+				Subset: &dynamic.Subset{
+					Service: "parent@provider-1",
+					Labels:  map[string]string{"version": "v2"},
+				},
+			},
+		},
+		"none@provider-2": {
+			Service: &dynamic.Service{
+				Subset: &dynamic.Subset{
+					Service: "parent@provider-1",
+					Labels:  map[string]string{"version": "v3"},
+				},
+			},
+		},
+		"weighted@provider-1": {
+			Service: &dynamic.Service{
+				Weighted: &dynamic.WeightedRoundRobin{},
+			},
+		},
+		"invalid@provider-2": {
+			Service: &dynamic.Service{
+				Subset: &dynamic.Subset{
+					Service: "weighted@provider-1",
+				},
+			},
+		},
+	}
+
+	manager := NewManager(configs, nil, nil, &RoundTripperManager{
+		roundTrippers: map[string]http.RoundTripper{
+			"default@internal": http.DefaultTransport,
+		},
+	}, nil, nil, "", nil, nil)
+
+	handler, err := manager.BuildHTTP(context.Background(), "canary@provider-2")
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "v2", recorder.Header().Get("X-From"))
+	}
+
+	handler, err = manager.BuildHTTP(context.Background(), "none@provider-2")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	_, err = manager.BuildHTTP(context.Background(), "invalid@provider-2")
+	assert.Error(t, err)
+
+	// The servers of the parent service are left untouched.
+	assert.Len(t, configs["parent@provider-1"].LoadBalancer.Servers, 2)
+}
+
+func TestManager_Build_canary(t *testing.T) {
+	newServer := func(name string) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("X-From", name)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	stable := newServer("stable")
+	canaryServer := newServer("canary")
+
+	stableWeight, canaryWeight := 3, 100
+
+	configs := map[string]*runtime.ServiceInfo{
+		"stable@file": {
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{Servers: []dynamic.Server{{URL: stable.URL}}},
+			},
+		},
+		"canary@file": {
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{Servers: []dynamic.Server{{URL: canaryServer.URL}}},
+			},
+		},
+		"weighted@file": {
+			Service: &dynamic.Service{
+				Weighted: &dynamic.WeightedRoundRobin{
+					Services: []dynamic.WRRService{
+						{Name: "stable", Weight: &stableWeight},
+						{Name: "canary", Weight: &canaryWeight},
+					},
+					Canary: &dynamic.Canary{Service: "canary", StepWeight: 1, MaxWeight: 4, Interval: ptypes.Duration(time.Hour)},
+				},
+			},
+		},
+		"invalid@file": {
+			Service: &dynamic.Service{
+				Weighted: &dynamic.WeightedRoundRobin{
+					Services: []dynamic.WRRService{{Name: "stable"}},
+					Canary:   &dynamic.Canary{Service: "canary"},
+				},
+			},
+		},
+	}
+
+	manager := NewManager(configs, nil, nil, &RoundTripperManager{
+		roundTrippers: map[string]http.RoundTripper{
+			"default@internal": http.DefaultTransport,
+		},
+	}, nil, canary.NewController(), "", nil, nil)
+
+	handler, err := manager.BuildHTTP(context.Background(), "weighted@file")
+	require.NoError(t, err)
+
+	// The canary service starts at the step weight, whatever its configured weight.
+	counts := map[string]int{}
+	for i := 0; i < 4; i++ {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		counts[recorder.Header().Get("X-From")]++
+	}
+
+	assert.Equal(t, map[string]int{"stable": 3, "canary": 1}, counts)
+
+	_, err = manager.BuildHTTP(context.Background(), "invalid@file")
+	assert.Error(t, err)
+}
+
+type echoTCPServiceManager struct {
+	services []string
+}
+
+func (m *echoTCPServiceManager) BuildTCP(ctx context.Context, serviceName string) (tcp.Handler, error) {
+	m.services = append(m.services, provider.GetQualifiedName(ctx, serviceName))
+
+	return tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		defer conn.Close()
+
+		_, _ = io.Copy(conn, conn)
+	}), nil
+}
+
+type tcpMiddlewaresBuilder struct{}
+
+func (tcpMiddlewaresBuilder) BuildChain(_ context.Context, _ []string) *tcp.Chain {
+	chain := tcp.NewChain()
+	return &chain
+}
+
+func TestManager_Build_webSocketBridge(t *testing.T) {
+	configs := map[string]*runtime.ServiceInfo{
+		"bridge@provider-1": {
+			Service: &dynamic.Service{
+				WebSocketBridge: &dynamic.WebSocketBridge{Service: "database"},
+			},
+		},
+		"invalid@provider-1": {
+			Service: &dynamic.Service{
+				WebSocketBridge: &dynamic.WebSocketBridge{},
+			},
+		},
+	}
+
+	tcpServices := &echoTCPServiceManager{}
+	manager := NewManager(configs, nil, nil, nil, nil, nil, "", tcpServices, tcpMiddlewaresBuilder{})
+
+	handler, err := manager.BuildHTTP(context.Background(), "bridge@provider-1")
+	require.NoError(t, err)
+
+	// The TCP service is resolved in the context of the provider of the bridge.
+	assert.Equal(t, []string{"database@provider-1"}, tcpServices.services)
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, []byte("ping")))
+
+	_, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(msg))
+
+	_, err = manager.BuildHTTP(context.Background(), "invalid@provider-1")
+	assert.Error(t, err)
+
+	// The bridges cannot be built without the TCP services.
+	manager = NewManager(configs, nil, nil, nil, nil, nil, "", nil, nil)
+
+	_, err = manager.BuildHTTP(context.Background(), "bridge@provider-1")
+	assert.Error(t, err)
+}
+
 func TestMultipleTypeOnBuildHTTP(t *testing.T) {
 	services := map[string]*runtime.ServiceInfo{
 		"test@file": {
@@ -557,7 +944,7 @@ func TestMultipleTypeOnBuildHTTP(t *testing.T) {
 		roundTrippers: map[string]http.RoundTripper{
 			"default@internal": http.DefaultTransport,
 		},
-	})
+	}, nil, nil, "", nil, nil)
 
 	_, err := manager.BuildHTTP(context.Background(), "test@file")
 	assert.Error(t, err, "cannot create service: multi-types service not supported, consider declaring two different pieces of service instead")
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"crypto/tls"
 	"net"
 	"net/http"
@@ -11,7 +12,7 @@ import (
 	"golang.org/x/net/http2"
 )
 
-func newSmartRoundTripper(transport *http.Transport, forwardingTimeouts *dynamic.ForwardingTimeouts) (http.RoundTripper, error) {
+func newSmartRoundTripper(transport *http.Transport, forwardingTimeouts *dynamic.ForwardingTimeouts, detectH2C bool) (http.RoundTripper, error) {
 	transportHTTP1 := transport.Clone()
 
 	transportHTTP2, err := http2.ConfigureTransports(transport)
@@ -24,10 +25,12 @@ func newSmartRoundTripper(transport *http.Transport, forwardingTimeouts *dynamic
 		transportHTTP2.PingTimeout = time.Duration(forwardingTimeouts.PingTimeout)
 	}
 
+	// The h2c connections are dialed as the other ones, for them to get the same dial timeout and connection marks.
+	dialContext := transport.DialContext
 	transportH2C := &h2cTransportWrapper{
 		Transport: &http2.Transport{
-			DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
-				return net.Dial(network, addr)
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				return dialContext(ctx, network, addr)
 			},
 			AllowHTTP: true,
 		},
@@ -40,10 +43,17 @@ func newSmartRoundTripper(transport *http.Transport, forwardingTimeouts *dynamic
 
 	transport.RegisterProtocol("h2c", transportH2C)
 
-	return &smartRoundTripper{
+	rt := &smartRoundTripper{
 		http2: transport,
 		http:  transportHTTP1,
-	}, nil
+	}
+
+	if detectH2C {
+		rt.h2c = transportH2C
+		rt.h2cDetector = newH2CDetector(dialContext)
+	}
+
+	return rt, nil
 }
 
 // smartRoundTripper implements RoundTrip while making sure that HTTP/2 is not used
@@ -51,6 +61,10 @@ func newSmartRoundTripper(transport *http.Transport, forwardingTimeouts *dynamic
 type smartRoundTripper struct {
 	http2 *http.Transport
 	http  *http.Transport
+
+	// h2c sends the requests with a plain HTTP URL over h2c, when h2cDetector detected that their server supports it.
+	h2c         http.RoundTripper
+	h2cDetector *h2cDetector
 }
 
 func (m *smartRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -59,5 +73,17 @@ func (m *smartRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 		return m.http.RoundTrip(req)
 	}
 
+	if m.h2cDetector != nil && req.URL.Scheme == "http" {
+		addr := h2cAddr(req.URL)
+		if m.h2cDetector.supports(addr) {
+			res, err := m.h2c.RoundTrip(req)
+			if err != nil {
+				m.h2cDetector.forget(addr)
+			}
+
+			return res, err
+		}
+	}
+
 	return m.http2.RoundTrip(req)
 }
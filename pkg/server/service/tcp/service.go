@@ -9,22 +9,36 @@ import (
 	"time"
 
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/dnsresolver"
+	"github.com/traefik/traefik/v2/pkg/egress"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/slowstart"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/strategy"
 	"github.com/traefik/traefik/v2/pkg/tcp"
+	"github.com/traefik/traefik/v2/pkg/unixsocket"
+	"github.com/traefik/traefik/v2/pkg/vsock"
 )
 
 // Manager is the TCPHandlers factory.
 type Manager struct {
-	configs map[string]*runtime.TCPServiceInfo
-	rand    *rand.Rand // For the initial shuffling of load-balancers.
+	configs   map[string]*runtime.TCPServiceInfo
+	rand      *rand.Rand // For the initial shuffling of load-balancers.
+	slowStart *slowstart.Tracker
+	resolver  *dnsresolver.Resolver
+	egress    *egress.Policy
 }
 
 // NewManager creates a new manager.
-func NewManager(conf *runtime.Configuration) *Manager {
+// The resolver, if any, looks the hostnames of the servers up,
+// and the egress policy, if any, restricts the destinations they are dialed at.
+func NewManager(conf *runtime.Configuration, slowStart *slowstart.Tracker, resolver *dnsresolver.Resolver, egressPolicy *egress.Policy) *Manager {
 	return &Manager{
-		configs: conf.TCPServices,
-		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		configs:   conf.TCPServices,
+		rand:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		slowStart: slowStart,
+		resolver:  resolver,
+		egress:    egressPolicy,
 	}
 }
 
@@ -48,7 +62,18 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 	logger := log.FromContext(ctx)
 	switch {
 	case conf.LoadBalancer != nil:
-		loadBalancer := tcp.NewWRRLoadBalancer()
+		var loadBalancer serversLoadBalancer
+		switch conf.LoadBalancer.Strategy {
+		case "", strategy.WRR:
+			loadBalancer = tcp.NewWRRLoadBalancer()
+		default:
+			lb, err := strategy.NewTCPBalancer(conf.LoadBalancer.Strategy)
+			if err != nil {
+				conf.AddError(err, true)
+				return nil, err
+			}
+			loadBalancer = lb
+		}
 
 		var sourceIPs []net.TCPAddr
 		for _, sourceIP := range conf.LoadBalancer.SourceIPs {
@@ -61,19 +86,43 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 		}
 		duration := time.Duration(*conf.LoadBalancer.TerminationDelay) * time.Millisecond
 
+		var slowStartWindow time.Duration
+		if conf.LoadBalancer.SlowStart != nil {
+			slowStartWindow = time.Duration(conf.LoadBalancer.SlowStart.Window)
+			if slowStartWindow <= 0 {
+				slowStartWindow = slowstart.DefaultWindow
+			}
+		}
+
 		for name, server := range shuffle(conf.LoadBalancer.Servers, m.rand) {
-			if _, _, err := net.SplitHostPort(server.Address); err != nil {
-				logger.Errorf("In service %q: %v", serviceQualifiedName, err)
-				continue
+			// The addresses of Unix domain sockets and VM sockets have no host and port, and are validated by the proxy.
+			_, isUnix := unixsocket.Path(server.Address)
+			_, isVsock, _ := vsock.ParseAddress(server.Address)
+			if !isUnix && !isVsock {
+				if _, _, err := net.SplitHostPort(server.Address); err != nil {
+					logger.Errorf("In service %q: %v", serviceQualifiedName, err)
+					continue
+				}
 			}
 
-			handler, err := tcp.NewProxy(server.Address, duration, conf.LoadBalancer.ProxyProtocol, sourceIPs)
+			handler, err := tcp.NewProxy(server.Address, duration, conf.LoadBalancer.ProxyProtocol, tcp.ProxyOptions{
+				SourceIPs:    sourceIPs,
+				FastPath:     conf.LoadBalancer.FastPath,
+				Transparent:  conf.LoadBalancer.Transparent,
+				MultipathTCP: conf.LoadBalancer.MultipathTCP,
+				Resolver:     m.resolver,
+				EgressPolicy: m.egress,
+			})
 			if err != nil {
 				logger.Errorf("In service %q server %q: %v", serviceQualifiedName, server.Address, err)
 				continue
 			}
 
-			loadBalancer.AddServer(handler)
+			if slowStartWindow > 0 {
+				loadBalancer.AddSlowStartServer(handler, m.slowStart.Since(serviceQualifiedName, server.Address), slowStartWindow)
+			} else {
+				loadBalancer.AddServer(handler)
+			}
 			logger.WithField(log.ServerName, name).Debugf("Creating TCP server %d at %s", name, server.Address)
 		}
 		return loadBalancer, nil
@@ -96,6 +145,13 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 	}
 }
 
+// serversLoadBalancer is a load balancer of the servers of a TCP service.
+type serversLoadBalancer interface {
+	tcp.Handler
+	AddServer(serverHandler tcp.Handler)
+	AddSlowStartServer(serverHandler tcp.Handler, since time.Time, window time.Duration)
+}
+
 func shuffle[T any](values []T, r *rand.Rand) []T {
 	shuffled := make([]T, len(values))
 	copy(shuffled, values)
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/egress"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	"github.com/traefik/traefik/v2/pkg/udp"
@@ -18,13 +19,16 @@ import (
 type Manager struct {
 	configs map[string]*runtime.UDPServiceInfo
 	rand    *rand.Rand // For the initial shuffling of load-balancers.
+	egress  *egress.Policy
 }
 
 // NewManager creates a new manager.
-func NewManager(conf *runtime.Configuration) *Manager {
+// The egress policy, if any, restricts the destinations the servers are dialed at.
+func NewManager(conf *runtime.Configuration, egressPolicy *egress.Policy) *Manager {
 	return &Manager{
 		configs: conf.UDPServices,
 		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		egress:  egressPolicy,
 	}
 }
 
@@ -56,7 +60,7 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 				continue
 			}
 
-			handler, err := udp.NewProxy(server.Address)
+			handler, err := udp.NewProxy(server.Address, m.egress)
 			if err != nil {
 				logger.Errorf("In udp service %q server %q: %v", serviceQualifiedName, server.Address, err)
 				continue
@@ -180,7 +180,7 @@ func TestManager_BuildUDP(t *testing.T) {
 
 			manager := NewManager(&runtime.Configuration{
 				UDPServices: test.configs,
-			})
+			}, nil)
 
 			ctx := context.Background()
 			if len(test.providerName) > 0 {
@@ -0,0 +1,240 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// Sources of the snapshots.
+const (
+	SourceProviders = "providers"
+	SourceImport    = "import"
+)
+
+// ErrNotFound is returned when pinning a snapshot that is not in the store.
+var ErrNotFound = errors.New("snapshot not found")
+
+// Info describes a snapshot kept in the store.
+type Info struct {
+	Hash      string    `json:"hash"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type snapshot struct {
+	Info
+	configuration *dynamic.Configuration
+}
+
+// Hash returns the hash addressing the given configuration,
+// i.e. the hex-encoded SHA-256 of its JSON representation.
+func Hash(conf dynamic.Configuration) (string, error) {
+	data, err := json.Marshal(conf)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Store holds the last applied dynamic configurations, and the imported ones, addressed by their hash.
+// It also holds the snapshot the proxy is pinned to, if any:
+// while pinned, the configuration updates from the providers are recorded but not applied.
+type Store struct {
+	mu           sync.RWMutex
+	maxSnapshots int
+	snapshots    map[string]*snapshot
+	// order holds the hashes of the snapshots, from the oldest to the most recent.
+	order   []string
+	pinned  string
+	current string
+
+	changes chan struct{}
+}
+
+// NewStore creates a new Store keeping at most maxSnapshots snapshots.
+func NewStore(maxSnapshots int) *Store {
+	if maxSnapshots < 1 {
+		maxSnapshots = 1
+	}
+
+	return &Store{
+		maxSnapshots: maxSnapshots,
+		snapshots:    make(map[string]*snapshot),
+		changes:      make(chan struct{}, 1),
+	}
+}
+
+// Add adds a snapshot of the given configuration, and returns its hash.
+// Adding a configuration already in the store only makes it the most recent one.
+func (s *Store) Add(conf dynamic.Configuration, source string) (string, error) {
+	if s == nil {
+		return "", nil
+	}
+
+	hash, err := Hash(conf)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.snapshots[hash]; ok {
+		s.remove(hash)
+	}
+
+	s.snapshots[hash] = &snapshot{
+		Info:          Info{Hash: hash, Source: source, CreatedAt: time.Now().UTC()},
+		configuration: conf.DeepCopy(),
+	}
+	s.order = append(s.order, hash)
+
+	// The oldest snapshots are evicted, except the pinned one.
+	for i := 0; len(s.order) > s.maxSnapshots && i < len(s.order); {
+		if s.order[i] == s.pinned {
+			i++
+			continue
+		}
+
+		delete(s.snapshots, s.order[i])
+		s.order = append(s.order[:i], s.order[i+1:]...)
+	}
+
+	return hash, nil
+}
+
+func (s *Store) remove(hash string) {
+	for i, h := range s.order {
+		if h == hash {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+
+	delete(s.snapshots, hash)
+}
+
+// Get returns a copy of the configuration of the given snapshot, if any.
+func (s *Store) Get(hash string) (dynamic.Configuration, bool) {
+	if s == nil {
+		return dynamic.Configuration{}, false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snap, ok := s.snapshots[hash]
+	if !ok {
+		return dynamic.Configuration{}, false
+	}
+
+	return *snap.configuration.DeepCopy(), true
+}
+
+// List returns the information of the snapshots, from the most recent to the oldest.
+func (s *Store) List() []Info {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	infos := make([]Info, 0, len(s.order))
+	for i := len(s.order) - 1; i >= 0; i-- {
+		infos = append(infos, s.snapshots[s.order[i]].Info)
+	}
+
+	return infos
+}
+
+// Pin pins the proxy to the given snapshot.
+func (s *Store) Pin(hash string) error {
+	if s == nil {
+		return ErrNotFound
+	}
+
+	s.mu.Lock()
+	if _, ok := s.snapshots[hash]; !ok {
+		s.mu.Unlock()
+		return ErrNotFound
+	}
+	s.pinned = hash
+	s.mu.Unlock()
+
+	s.notify()
+	return nil
+}
+
+// Unpin unpins the proxy, so that the configuration updates from the providers are applied again.
+func (s *Store) Unpin() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.pinned = ""
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+// Pinned returns the hash of the snapshot the proxy is pinned to, or an empty string if it is not pinned.
+func (s *Store) Pinned() string {
+	if s == nil {
+		return ""
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.pinned
+}
+
+// SetCurrent records the hash of the configuration currently applied.
+func (s *Store) SetCurrent(hash string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.current = hash
+}
+
+// Current returns the hash of the configuration currently applied.
+func (s *Store) Current() string {
+	if s == nil {
+		return ""
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.current
+}
+
+// Changes returns a channel receiving a value whenever the proxy is pinned or unpinned.
+func (s *Store) Changes() <-chan struct{} {
+	if s == nil {
+		return nil
+	}
+
+	return s.changes
+}
+
+func (s *Store) notify() {
+	select {
+	case s.changes <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,120 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestHash(t *testing.T) {
+	conf := dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"foo@file": {Rule: "Host(`foo.localhost`)", Service: "bar@file"},
+				"bar@file": {Rule: "Host(`bar.localhost`)", Service: "bar@file"},
+			},
+		},
+	}
+
+	hash, err := Hash(conf)
+	require.NoError(t, err)
+
+	// The hash of an exported, then imported, configuration is unchanged.
+	data, err := json.Marshal(conf)
+	require.NoError(t, err)
+
+	var imported dynamic.Configuration
+	require.NoError(t, json.Unmarshal(data, &imported))
+
+	importedHash, err := Hash(imported)
+	require.NoError(t, err)
+	assert.Equal(t, hash, importedHash)
+
+	conf.HTTP.Routers["foo@file"].Priority = 42
+	changedHash, err := Hash(conf)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash)
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore(2)
+
+	confs := make([]dynamic.Configuration, 3)
+	hashes := make([]string, 3)
+	for i := range confs {
+		confs[i] = dynamic.Configuration{
+			HTTP: &dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{"foo@file": {Priority: i}},
+			},
+		}
+
+		var err error
+		hashes[i], err = Hash(confs[i])
+		require.NoError(t, err)
+	}
+
+	hash, err := store.Add(confs[0], SourceProviders)
+	require.NoError(t, err)
+	assert.Equal(t, hashes[0], hash)
+
+	got, ok := store.Get(hashes[0])
+	require.True(t, ok)
+	assert.Equal(t, confs[0], got)
+
+	require.NoError(t, store.Pin(hashes[0]))
+	assert.Equal(t, hashes[0], store.Pinned())
+	<-store.Changes()
+
+	assert.ErrorIs(t, store.Pin("unknown"), ErrNotFound)
+
+	// The pinned snapshot is not evicted.
+	_, err = store.Add(confs[1], SourceProviders)
+	require.NoError(t, err)
+	_, err = store.Add(confs[2], SourceImport)
+	require.NoError(t, err)
+
+	var listed []string
+	for _, info := range store.List() {
+		listed = append(listed, info.Hash)
+	}
+	assert.Equal(t, []string{hashes[2], hashes[0]}, listed)
+
+	store.Unpin()
+	assert.Empty(t, store.Pinned())
+	<-store.Changes()
+
+	// Adding a configuration again makes it the most recent one.
+	_, err = store.Add(confs[0], SourceProviders)
+	require.NoError(t, err)
+	_, err = store.Add(confs[1], SourceProviders)
+	require.NoError(t, err)
+
+	listed = nil
+	for _, info := range store.List() {
+		listed = append(listed, info.Hash)
+	}
+	assert.Equal(t, []string{hashes[1], hashes[0]}, listed)
+}
+
+func TestStore_nil(t *testing.T) {
+	var store *Store
+
+	hash, err := store.Add(dynamic.Configuration{}, SourceProviders)
+	require.NoError(t, err)
+	assert.Empty(t, hash)
+
+	assert.ErrorIs(t, store.Pin("foo"), ErrNotFound)
+	store.Unpin()
+	store.SetCurrent("foo")
+
+	assert.Empty(t, store.Pinned())
+	assert.Empty(t, store.Current())
+	assert.Nil(t, store.List())
+	assert.Nil(t, store.Changes())
+
+	_, ok := store.Get("foo")
+	assert.False(t, ok)
+}
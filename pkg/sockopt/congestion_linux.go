@@ -0,0 +1,37 @@
+//go:build linux
+// +build linux
+
+package sockopt
+
+import (
+	"fmt"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// CongestionControl returns a control function setting the TCP congestion control algorithm (TCP_CONGESTION) of the socket,
+// such as bbr or cubic, which must be available in the kernel (net.ipv4.tcp_available_congestion_control).
+// The connections accepted by a listening socket inherit its algorithm.
+// The sockets of the other networks, such as the Unix domain sockets, are left as is.
+func CongestionControl(algorithm string) ControlFunc {
+	return func(network, _ string, c syscall.RawConn) error {
+		if network != "tcp" && network != "tcp4" && network != "tcp6" {
+			return nil
+		}
+
+		var errOpt error
+		err := c.Control(func(fd uintptr) {
+			errOpt = unix.SetsockoptString(int(fd), unix.IPPROTO_TCP, unix.TCP_CONGESTION, algorithm)
+		})
+		if err != nil {
+			return err
+		}
+
+		if errOpt != nil {
+			return fmt.Errorf("setting TCP congestion control %q: %w", algorithm, errOpt)
+		}
+
+		return nil
+	}
+}
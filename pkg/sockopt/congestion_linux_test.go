@@ -0,0 +1,60 @@
+package sockopt
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestCongestionControl(t *testing.T) {
+	lc := net.ListenConfig{Control: CongestionControl("reno")}
+	listener, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	dialer := net.Dialer{Control: CongestionControl("reno")}
+	conn, err := dialer.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	accepted, err := listener.Accept()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = accepted.Close() })
+
+	assert.Equal(t, "reno", congestionControl(t, conn))
+	assert.Equal(t, "reno", congestionControl(t, accepted))
+}
+
+func TestCongestionControl_unknown(t *testing.T) {
+	lc := net.ListenConfig{Control: CongestionControl("unknown")}
+	_, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	require.Error(t, err)
+}
+
+func TestCongestionControl_unix(t *testing.T) {
+	lc := net.ListenConfig{Control: CongestionControl("reno")}
+	listener, err := lc.Listen(context.Background(), "unix", "@traefik-test-congestion")
+	require.NoError(t, err)
+	_ = listener.Close()
+}
+
+func congestionControl(t *testing.T, conn net.Conn) string {
+	t.Helper()
+
+	rawConn, err := conn.(*net.TCPConn).SyscallConn()
+	require.NoError(t, err)
+
+	var algorithm string
+	var errOpt error
+	require.NoError(t, rawConn.Control(func(fd uintptr) {
+		algorithm, errOpt = unix.GetsockoptString(int(fd), unix.IPPROTO_TCP, unix.TCP_CONGESTION)
+	}))
+	require.NoError(t, errOpt)
+
+	return strings.TrimRight(algorithm, "\x00")
+}
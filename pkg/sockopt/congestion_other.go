@@ -0,0 +1,17 @@
+//go:build !linux
+// +build !linux
+
+package sockopt
+
+import (
+	"errors"
+	"syscall"
+)
+
+// CongestionControl returns a control function failing,
+// as the TCP congestion control algorithm of a socket can only be selected on Linux.
+func CongestionControl(_ string) ControlFunc {
+	return func(_, _ string, _ syscall.RawConn) error {
+		return errors.New("selecting the TCP congestion control is only supported on Linux")
+	}
+}
@@ -0,0 +1,25 @@
+package sockopt
+
+import "syscall"
+
+// ControlFunc is a function setting options on a socket before it is bound or connected,
+// as the Control function of net.Dialer and net.ListenConfig.
+type ControlFunc func(network, address string, c syscall.RawConn) error
+
+// Chain returns a control function calling the given ones in order, skipping the nil ones,
+// and stopping at the first error.
+func Chain(controls ...ControlFunc) ControlFunc {
+	return func(network, address string, c syscall.RawConn) error {
+		for _, control := range controls {
+			if control == nil {
+				continue
+			}
+
+			if err := control(network, address, c); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
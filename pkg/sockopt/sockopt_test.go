@@ -0,0 +1,25 @@
+package sockopt
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+	record := func(name string, err error) ControlFunc {
+		return func(_, _ string, _ syscall.RawConn) error {
+			calls = append(calls, name)
+			return err
+		}
+	}
+
+	err := Chain(record("first", nil), nil, record("second", errors.New("boom")), record("third", nil))("tcp", "", nil)
+	require.Error(t, err)
+
+	assert.Equal(t, []string{"first", "second"}, calls)
+}
@@ -0,0 +1,314 @@
+package strictparsing
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+// maxLineLength is the length kept of each line of the requests: the longer lines are truncated,
+// since only the names of the header fields, and the short values of the framing ones, are inspected.
+const maxLineLength = 4096
+
+// maxChunkSize is the size of the largest chunk net/http accepts.
+const maxChunkSize = 1<<63 - 1
+
+type inspectState int
+
+const (
+	// stateHead is the reading of the request line and the header fields.
+	stateHead inspectState = iota
+	// stateBody is the reading of a body delimited by its Content-Length.
+	stateBody
+	// stateChunkSize is the reading of the size line of a chunk.
+	stateChunkSize
+	// stateChunkData is the reading of the data of a chunk, followed by its CRLF.
+	stateChunkData
+	// stateTrailer is the reading of the trailer fields of a chunked body.
+	stateTrailer
+	// stateDone is the end of the inspection, when the connection does not carry HTTP/1 requests anymore,
+	// or when net/http is about to reject what it reads.
+	stateDone
+)
+
+// Conn inspects the framing of the HTTP/1 requests read from a connection, before net/http parses them,
+// since net/http silently drops the Content-Length header of the chunked requests, and the extensions of the chunks.
+// The reads fail from the line making a request ambiguous, so that net/http answers with a 400 Bad Request, and closes the connection.
+type Conn struct {
+	tcp.WriteCloser
+
+	parser  *Parser
+	tlsConn *tls.Conn
+
+	mu                 sync.Mutex
+	err                error
+	suspended          bool
+	readWhileSuspended bool
+
+	state     inspectState
+	remaining uint64
+
+	// line is the current line, truncated to maxLineLength, and lineLength its actual length.
+	line       []byte
+	lineLength int
+	lastCR     bool
+
+	// The framing headers of the current request.
+	sawRequestLine       bool
+	contentLengths       int
+	contentLength        uint64
+	invalidContentLength bool
+	transferEncoding     bool
+	chunked              bool
+}
+
+// Read reads from the connection, and fails from the line making a request ambiguous.
+func (c *Conn) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	err := c.err
+	c.mu.Unlock()
+
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := c.WriteCloser.Read(p)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.suspended {
+		c.readWhileSuspended = c.readWhileSuspended || n > 0
+		return n, err
+	}
+
+	valid, reason := c.inspect(p[:n])
+	if reason != "" {
+		c.parser.reject(reason)
+		c.err = fmt.Errorf("request rejected by the strict parsing: %s", reason)
+
+		// The data preceding the offending line, which may complete the previous requests, is still returned.
+		if valid > 0 {
+			return valid, nil
+		}
+
+		return 0, c.err
+	}
+
+	return n, err
+}
+
+// suspend stops the inspection, while the connection may be upgraded to another protocol.
+func (c *Conn) suspend() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.suspended = true
+	c.readWhileSuspended = false
+}
+
+// resume resumes the inspection, from the start of the next request if the connection was read while suspended.
+func (c *Conn) resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.suspended = false
+	if c.readWhileSuspended && c.state != stateDone {
+		c.state = stateHead
+		c.resetLine()
+		c.resetHead()
+	}
+}
+
+// inspect goes through the data read, and returns the reason why the request is rejected, if any,
+// along with the length of the data preceding the offending line.
+func (c *Conn) inspect(data []byte) (int, string) {
+	var offset, lineStart int
+
+	for offset < len(data) {
+		switch c.state {
+		case stateDone:
+			return len(data), ""
+
+		case stateBody, stateChunkData:
+			n := uint64(len(data) - offset)
+			if n > c.remaining {
+				n = c.remaining
+			}
+
+			c.remaining -= n
+			offset += int(n)
+
+			if c.remaining > 0 {
+				continue
+			}
+
+			if c.state == stateBody {
+				c.state = stateHead
+			} else {
+				c.state = stateChunkSize
+			}
+
+		default:
+			if c.lineLength == 0 {
+				lineStart = offset
+			}
+
+			i := bytes.IndexByte(data[offset:], '\n')
+			if i < 0 {
+				c.appendLine(data[offset:])
+				return len(data), ""
+			}
+
+			c.appendLine(data[offset : offset+i])
+			offset += i + 1
+
+			line, length := c.line, c.lineLength
+			if c.lastCR {
+				length--
+				line = bytes.TrimSuffix(line, []byte("\r"))
+			}
+
+			reason := c.endLine(line, length)
+			c.resetLine()
+
+			if reason != "" {
+				return lineStart, reason
+			}
+		}
+	}
+
+	return len(data), ""
+}
+
+func (c *Conn) appendLine(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+
+	c.lineLength += len(data)
+	c.lastCR = data[len(data)-1] == '\r'
+
+	if room := maxLineLength - len(c.line); room > 0 {
+		if room > len(data) {
+			room = len(data)
+		}
+		c.line = append(c.line, data[:room]...)
+	}
+}
+
+func (c *Conn) resetLine() {
+	c.line = c.line[:0]
+	c.lineLength = 0
+	c.lastCR = false
+}
+
+func (c *Conn) resetHead() {
+	c.sawRequestLine = false
+	c.contentLengths = 0
+	c.contentLength = 0
+	c.invalidContentLength = false
+	c.transferEncoding = false
+	c.chunked = false
+}
+
+func (c *Conn) endLine(line []byte, length int) string {
+	switch c.state {
+	case stateHead:
+		return c.endHeadLine(line)
+	case stateChunkSize:
+		return c.endChunkSizeLine(line, length)
+	case stateTrailer:
+		if len(line) == 0 {
+			c.state = stateHead
+		}
+	}
+
+	return ""
+}
+
+func (c *Conn) endHeadLine(line []byte) string {
+	if !c.sawRequestLine {
+		// The empty lines before the request line are ignored.
+		if len(line) == 0 {
+			return ""
+		}
+
+		c.sawRequestLine = true
+		if bytes.HasPrefix(line, []byte("PRI * HTTP/2.0")) {
+			// This is the preface of an unencrypted HTTP/2 connection.
+			c.state = stateDone
+		}
+
+		return ""
+	}
+
+	if len(line) > 0 {
+		name, value, found := bytes.Cut(line, []byte(":"))
+		if !found {
+			return ""
+		}
+
+		switch {
+		case bytes.EqualFold(name, []byte("Content-Length")):
+			c.contentLengths++
+
+			var err error
+			c.contentLength, err = strconv.ParseUint(string(bytes.TrimSpace(value)), 10, 63)
+			if err != nil {
+				c.invalidContentLength = true
+			}
+		case bytes.EqualFold(name, []byte("Transfer-Encoding")):
+			// The body is chunked only if chunked is the final coding.
+			codings := bytes.Split(value, []byte(","))
+			c.transferEncoding = true
+			c.chunked = bytes.EqualFold(bytes.TrimSpace(codings[len(codings)-1]), []byte("chunked"))
+		}
+
+		return ""
+	}
+
+	defer c.resetHead()
+
+	if c.parser.config.RejectAmbiguousFraming && (c.contentLengths > 1 || c.contentLengths > 0 && c.transferEncoding ||
+		c.invalidContentLength || c.transferEncoding && !c.chunked) {
+		return reasonAmbiguousFraming
+	}
+
+	switch {
+	case c.chunked:
+		c.state = stateChunkSize
+	case c.contentLength > 0:
+		c.state = stateBody
+		c.remaining = c.contentLength
+	}
+
+	return ""
+}
+
+func (c *Conn) endChunkSizeLine(line []byte, length int) string {
+	size, _, hasExtension := bytes.Cut(line, []byte(";"))
+
+	maxLength := c.parser.config.MaxChunkExtensionLength
+	if hasExtension && maxLength > 0 && length-len(size)-1 > maxLength {
+		return reasonChunkExtension
+	}
+
+	n, err := strconv.ParseUint(string(bytes.TrimRight(size, " \t")), 16, 64)
+	switch {
+	case err != nil || n > maxChunkSize-2:
+		// net/http rejects the chunk.
+		c.state = stateDone
+	case n == 0:
+		c.state = stateTrailer
+	default:
+		c.state = stateChunkData
+		c.remaining = n + 2
+	}
+
+	return ""
+}
@@ -0,0 +1,221 @@
+package strictparsing
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+func TestConn(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        static.StrictParsing
+		requests      string
+		expectedCodes []int
+	}{
+		{
+			desc:          "pipelined requests",
+			config:        static.StrictParsing{RejectAmbiguousFraming: true},
+			requests:      "POST / HTTP/1.1\r\nHost: foo\r\nContent-Length: 5\r\n\r\nhello" + "POST / HTTP/1.1\r\nHost: foo\r\nTransfer-Encoding: chunked\r\n\r\n5;foo=bar\r\nhello\r\n0\r\nFoo: bar\r\n\r\n" + "GET / HTTP/1.1\r\nHost: foo\r\n\r\n",
+			expectedCodes: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			desc:          "Content-Length and Transfer-Encoding",
+			config:        static.StrictParsing{RejectAmbiguousFraming: true},
+			requests:      "POST / HTTP/1.1\r\nHost: foo\r\nContent-Length: 4\r\nTransfer-Encoding: chunked\r\n\r\n0\r\n\r\n",
+			expectedCodes: []int{http.StatusBadRequest},
+		},
+		{
+			desc:          "Content-Length and Transfer-Encoding allowed",
+			config:        static.StrictParsing{MaxChunkExtensionLength: 8},
+			requests:      "POST / HTTP/1.1\r\nHost: foo\r\nContent-Length: 4\r\nTransfer-Encoding: chunked\r\n\r\n0\r\n\r\n",
+			expectedCodes: []int{http.StatusOK},
+		},
+		{
+			desc:          "several Content-Length",
+			config:        static.StrictParsing{RejectAmbiguousFraming: true},
+			requests:      "POST / HTTP/1.1\r\nHost: foo\r\nContent-Length: 5\r\ncontent-length: 5\r\n\r\nhello",
+			expectedCodes: []int{http.StatusBadRequest},
+		},
+		{
+			desc:          "invalid Content-Length",
+			config:        static.StrictParsing{RejectAmbiguousFraming: true},
+			requests:      "POST / HTTP/1.1\r\nHost: foo\r\nContent-Length: 5a\r\n\r\nhello",
+			expectedCodes: []int{http.StatusBadRequest},
+		},
+		{
+			desc:          "negative Content-Length",
+			config:        static.StrictParsing{RejectAmbiguousFraming: true},
+			requests:      "POST / HTTP/1.1\r\nHost: foo\r\nContent-Length: -1\r\n\r\n",
+			expectedCodes: []int{http.StatusBadRequest},
+		},
+		{
+			desc:          "Transfer-Encoding not ending with chunked",
+			config:        static.StrictParsing{RejectAmbiguousFraming: true},
+			requests:      "POST / HTTP/1.1\r\nHost: foo\r\nTransfer-Encoding: chunked, gzip\r\n\r\n0\r\n\r\n",
+			expectedCodes: []int{http.StatusBadRequest},
+		},
+		{
+			desc:          "Transfer-Encoding chunked in another case",
+			config:        static.StrictParsing{RejectAmbiguousFraming: true},
+			requests:      "POST / HTTP/1.1\r\nHost: foo\r\nTransfer-Encoding: Chunked \r\n\r\n0\r\n\r\n",
+			expectedCodes: []int{http.StatusOK},
+		},
+		{
+			desc:     "ambiguous request smuggled in a body",
+			config:   static.StrictParsing{RejectAmbiguousFraming: true},
+			requests: "POST / HTTP/1.1\r\nHost: foo\r\nContent-Length: 65\r\n\r\nGET / HTTP/1.1\r\nContent-Length: 1\r\nTransfer-Encoding: chunked\r\n\r\n" + "GET / HTTP/1.1\r\nHost: foo\r\n\r\n",
+			// The body of the first request is not inspected.
+			expectedCodes: []int{http.StatusOK, http.StatusOK},
+		},
+		{
+			desc:          "ambiguous request after a valid one",
+			config:        static.StrictParsing{RejectAmbiguousFraming: true},
+			requests:      "GET / HTTP/1.1\r\nHost: foo\r\n\r\n" + "POST / HTTP/1.1\r\nHost: foo\r\nTransfer-Encoding: chunked\r\nContent-Length: 4\r\n\r\n0\r\n\r\n",
+			expectedCodes: []int{http.StatusOK, http.StatusBadRequest},
+		},
+		{
+			desc:          "chunk extension too long",
+			config:        static.StrictParsing{MaxChunkExtensionLength: 8},
+			requests:      "POST / HTTP/1.1\r\nHost: foo\r\nTransfer-Encoding: chunked\r\n\r\n5;foo=barbaz\r\nhello\r\n0\r\n\r\n",
+			expectedCodes: []int{http.StatusBadRequest},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			parser, err := New(&test.config, nil)
+			require.NoError(t, err)
+
+			addr := startServer(t, parser)
+
+			conn, err := net.Dial("tcp", addr)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+			_, err = conn.Write([]byte(test.requests))
+			require.NoError(t, err)
+
+			var codes []int
+			br := bufio.NewReader(conn)
+			for {
+				resp, err := http.ReadResponse(br, nil)
+				if err != nil {
+					require.ErrorIs(t, err, io.EOF)
+					break
+				}
+
+				_, _ = io.Copy(io.Discard, resp.Body)
+				_ = resp.Body.Close()
+
+				codes = append(codes, resp.StatusCode)
+				if resp.Close || len(codes) == len(test.expectedCodes) {
+					break
+				}
+			}
+
+			assert.Equal(t, test.expectedCodes, codes)
+		})
+	}
+}
+
+func TestConn_TLS(t *testing.T) {
+	parser, err := New(&static.StrictParsing{RejectAmbiguousFraming: true}, nil)
+	require.NoError(t, err)
+
+	// The certificate of an httptest server is trusted by its client.
+	backend := httptest.NewTLSServer(nil)
+	backend.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &http.Server{
+		Handler: parser.WrapHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.TLS == nil {
+				rw.WriteHeader(http.StatusInternalServerError)
+			}
+		})),
+		ConnContext: ConnContext,
+	}
+
+	go func() {
+		err := server.Serve(&parsedListener{Listener: listener, parser: parser, tlsConfig: &tls.Config{
+			Certificates: backend.TLS.Certificates,
+			NextProtos:   []string{"http/1.1"},
+		}})
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			t.Log(err)
+		}
+	}()
+	t.Cleanup(func() { _ = server.Close() })
+
+	resp, err := backend.Client().Get("https://" + listener.Addr().String())
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+// startServer starts an HTTP server inspecting its connections with the parser,
+// which answers 400 Bad Request when the body of a request cannot be read.
+func startServer(t *testing.T, parser *Parser) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &http.Server{
+		Handler: parser.WrapHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if _, err := io.ReadAll(req.Body); err != nil {
+				rw.WriteHeader(http.StatusBadRequest)
+			}
+		})),
+		ConnContext: ConnContext,
+	}
+
+	go func() {
+		err := server.Serve(&parsedListener{Listener: listener, parser: parser})
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			t.Log(err)
+		}
+	}()
+	t.Cleanup(func() { _ = server.Close() })
+
+	return listener.Addr().String()
+}
+
+type parsedListener struct {
+	net.Listener
+
+	parser    *Parser
+	tlsConfig *tls.Config
+}
+
+func (l *parsedListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	if l.tlsConfig != nil {
+		return l.parser.WrapConn(tls.Server(conn, l.tlsConfig)), nil
+	}
+
+	return l.parser.WrapConn(conn.(*net.TCPConn)), nil
+}
@@ -0,0 +1,304 @@
+package strictparsing
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+// Reasons of the rejections, as recorded by the metrics.
+const (
+	reasonAmbiguousFraming = "ambiguous_framing"
+	reasonChunkExtension   = "chunk_extension"
+	reasonDuplicateHeader  = "duplicate_header"
+	reasonPath             = "path"
+)
+
+// singletonHeaders are the request headers which do not allow several values,
+// and whose duplicates could be read differently by the backends.
+var singletonHeaders = []string{
+	"Authorization",
+	"Content-Length",
+	"Content-Type",
+	"From",
+	"Host",
+	"If-Modified-Since",
+	"If-Unmodified-Since",
+	"Max-Forwards",
+	"Proxy-Authorization",
+	"Range",
+	"Referer",
+	"Transfer-Encoding",
+	"User-Agent",
+}
+
+type connKey struct{}
+
+// Parser enforces the strict parsing of the requests of an entry point,
+// rejecting the requests that the backends could parse differently than Traefik does.
+type Parser struct {
+	config   static.StrictParsing
+	rejected gokitmetrics.Counter
+}
+
+// New creates a new Parser, counting the rejected requests, by reason, with the given counter.
+func New(config *static.StrictParsing, rejected gokitmetrics.Counter) (*Parser, error) {
+	if rejected == nil {
+		rejected = discard.NewCounter()
+	}
+
+	switch config.DuplicateHeaders {
+	case "", static.DuplicateHeadersKeep, static.DuplicateHeadersReject, static.DuplicateHeadersMerge:
+	default:
+		return nil, fmt.Errorf("unknown duplicated headers mode %q", config.DuplicateHeaders)
+	}
+
+	switch config.PathNormalization {
+	case "", static.PathNormalizationNone, static.PathNormalizationDotSegments, static.PathNormalizationMergeSlashes, static.PathNormalizationReject:
+	default:
+		return nil, fmt.Errorf("unknown path normalization mode %q", config.PathNormalization)
+	}
+
+	return &Parser{config: *config, rejected: rejected}, nil
+}
+
+// WrapConn returns the connection, with the framing of its HTTP/1 requests inspected, when the framing checks are enabled.
+// The TLS connections are handshaked first, so that the HTTP/2 ones, whose framing does not rely on the headers, are left as is.
+// A nil parser returns the connection as is.
+func (p *Parser) WrapConn(conn tcp.WriteCloser) tcp.WriteCloser {
+	if p == nil || !p.config.RejectAmbiguousFraming && p.config.MaxChunkExtensionLength <= 0 {
+		return conn
+	}
+
+	tlsConn, ok := conn.(*tls.Conn)
+	if ok {
+		// A failed handshake returns the same error to net/http, which handles it as usual.
+		if err := tlsConn.Handshake(); err != nil || tlsConn.ConnectionState().NegotiatedProtocol == "h2" {
+			return conn
+		}
+	}
+
+	return &Conn{WriteCloser: conn, parser: p, tlsConn: tlsConn}
+}
+
+// ConnContext stores the inspected connection in the context of its requests, as http.Server.ConnContext.
+func ConnContext(ctx context.Context, conn net.Conn) context.Context {
+	if c, ok := conn.(*Conn); ok {
+		return context.WithValue(ctx, connKey{}, c)
+	}
+
+	return ctx
+}
+
+// WrapHandler returns the handler, with the headers and the path of the requests checked, and normalized, before it handles them.
+// A nil parser returns the handler as is.
+func (p *Parser) WrapHandler(next http.Handler) http.Handler {
+	if p == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		conn, _ := req.Context().Value(connKey{}).(*Conn)
+
+		// net/http only sets the TLS state of the requests read from a *tls.Conn.
+		if conn != nil && conn.tlsConn != nil && req.TLS == nil {
+			state := conn.tlsConn.ConnectionState()
+			req.TLS = &state
+		}
+
+		if reason := p.checkHeaders(req); reason != "" {
+			p.reject(reason)
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if reason := p.normalizePath(req); reason != "" {
+			p.reject(reason)
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// The upgraded connections do not carry HTTP/1 requests anymore,
+		// so they are not inspected until the request is handled, and they are closed or keep carrying HTTP/1 requests.
+		if conn != nil && isUpgrade(req) {
+			conn.suspend()
+			defer conn.resume()
+		}
+
+		next.ServeHTTP(rw, req)
+	})
+}
+
+func (p *Parser) reject(reason string) {
+	p.rejected.With("reason", reason).Add(1)
+	log.WithoutContext().Debugf("Rejecting request, by the strict parsing: %s", reason)
+}
+
+// checkHeaders rejects the requests duplicating the headers which allow a single value,
+// and merges the other duplicated headers into a single line, depending on the mode.
+func (p *Parser) checkHeaders(req *http.Request) string {
+	if p.config.DuplicateHeaders != static.DuplicateHeadersReject && p.config.DuplicateHeaders != static.DuplicateHeadersMerge {
+		return ""
+	}
+
+	for _, name := range singletonHeaders {
+		if len(req.Header[name]) > 1 {
+			return reasonDuplicateHeader
+		}
+	}
+
+	if p.config.DuplicateHeaders == static.DuplicateHeadersMerge {
+		for name, values := range req.Header {
+			if len(values) < 2 {
+				continue
+			}
+
+			sep := ", "
+			if name == "Cookie" {
+				sep = "; "
+			}
+
+			req.Header[name] = []string{strings.Join(values, sep)}
+		}
+	}
+
+	return ""
+}
+
+// normalizePath normalizes the path of the request, or rejects the request if its path is not normalized, depending on the mode.
+func (p *Parser) normalizePath(req *http.Request) string {
+	if p.config.PathNormalization == "" || p.config.PathNormalization == static.PathNormalizationNone {
+		return ""
+	}
+
+	escaped := req.URL.EscapedPath()
+	if !strings.HasPrefix(escaped, "/") {
+		return ""
+	}
+
+	normalized := normalizePath(escaped, p.config.PathNormalization != static.PathNormalizationDotSegments)
+	if normalized == escaped {
+		return ""
+	}
+
+	if p.config.PathNormalization == static.PathNormalizationReject {
+		return reasonPath
+	}
+
+	path, err := url.PathUnescape(normalized)
+	if err != nil {
+		return reasonPath
+	}
+
+	req.URL.Path = path
+	req.URL.RawPath = normalized
+	// Because the reverse proxy director is building the path from the request URI, it needs to be updated as well.
+	req.RequestURI = req.URL.RequestURI()
+
+	return ""
+}
+
+// normalizePath decodes the percent-encoded unreserved characters of the escaped path,
+// optionally merges its consecutive slashes, then removes its dot segments (RFC 3986, section 5.2.4).
+func normalizePath(escaped string, mergeSlashes bool) string {
+	path := decodeUnreserved(escaped)
+
+	if mergeSlashes {
+		for strings.Contains(path, "//") {
+			path = strings.ReplaceAll(path, "//", "/")
+		}
+	}
+
+	segments := strings.Split(path, "/")[1:]
+
+	var normalized []string
+	for i, segment := range segments {
+		last := i == len(segments)-1
+
+		switch segment {
+		case ".":
+		case "..":
+			if len(normalized) > 0 {
+				normalized = normalized[:len(normalized)-1]
+			}
+		default:
+			normalized = append(normalized, segment)
+			continue
+		}
+
+		// The path keeps ending with a slash when it ends with a dot segment.
+		if last {
+			normalized = append(normalized, "")
+		}
+	}
+
+	return "/" + strings.Join(normalized, "/")
+}
+
+// decodeUnreserved decodes the percent-encoded unreserved characters, such as the encoded dots.
+func decodeUnreserved(escaped string) string {
+	if !strings.Contains(escaped, "%") {
+		return escaped
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(escaped); i++ {
+		if escaped[i] == '%' && i+2 < len(escaped) {
+			if c, ok := unhex(escaped[i+1], escaped[i+2]); ok && isUnreserved(c) {
+				b.WriteByte(c)
+				i += 2
+				continue
+			}
+		}
+
+		b.WriteByte(escaped[i])
+	}
+
+	return b.String()
+}
+
+func unhex(hi, lo byte) (byte, bool) {
+	h, ok := unhexDigit(hi)
+	if !ok {
+		return 0, false
+	}
+
+	l, ok := unhexDigit(lo)
+	if !ok {
+		return 0, false
+	}
+
+	return h<<4 | l, true
+}
+
+func unhexDigit(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	default:
+		return 0, false
+	}
+}
+
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// isUpgrade returns whether the request may switch the connection to another protocol.
+func isUpgrade(req *http.Request) bool {
+	return req.Method == http.MethodConnect || req.Method == "PRI" || req.Header.Get("Upgrade") != ""
+}
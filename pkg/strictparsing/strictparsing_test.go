@@ -0,0 +1,179 @@
+package strictparsing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New(&static.StrictParsing{DuplicateHeaders: "foo"}, nil)
+	assert.Error(t, err)
+
+	_, err = New(&static.StrictParsing{PathNormalization: "foo"}, nil)
+	assert.Error(t, err)
+
+	_, err = New(&static.StrictParsing{DuplicateHeaders: static.DuplicateHeadersMerge, PathNormalization: static.PathNormalizationReject}, nil)
+	assert.NoError(t, err)
+}
+
+func TestParser_WrapHandler_duplicateHeaders(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		mode            string
+		header          http.Header
+		expectedCode    int
+		expectedHeaders http.Header
+	}{
+		{
+			desc:            "keep",
+			mode:            static.DuplicateHeadersKeep,
+			header:          http.Header{"Content-Type": {"text/plain", "application/json"}, "Accept": {"text/plain", "application/json"}},
+			expectedCode:    http.StatusOK,
+			expectedHeaders: http.Header{"Content-Type": {"text/plain", "application/json"}, "Accept": {"text/plain", "application/json"}},
+		},
+		{
+			desc:         "reject a duplicated singleton header",
+			mode:         static.DuplicateHeadersReject,
+			header:       http.Header{"Content-Type": {"text/plain", "application/json"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:            "reject keeps the other duplicated headers",
+			mode:            static.DuplicateHeadersReject,
+			header:          http.Header{"Accept": {"text/plain", "application/json"}},
+			expectedCode:    http.StatusOK,
+			expectedHeaders: http.Header{"Accept": {"text/plain", "application/json"}},
+		},
+		{
+			desc:         "merge rejects a duplicated singleton header",
+			mode:         static.DuplicateHeadersMerge,
+			header:       http.Header{"Authorization": {"Basic Zm9vOmJhcg==", "Bearer foo"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:            "merge",
+			mode:            static.DuplicateHeadersMerge,
+			header:          http.Header{"Accept": {"text/plain", "application/json"}, "Cookie": {"foo=bar", "bar=baz"}},
+			expectedCode:    http.StatusOK,
+			expectedHeaders: http.Header{"Accept": {"text/plain, application/json"}, "Cookie": {"foo=bar; bar=baz"}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			parser, err := New(&static.StrictParsing{DuplicateHeaders: test.mode}, nil)
+			require.NoError(t, err)
+
+			var header http.Header
+			handler := parser.WrapHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				header = req.Header
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header = test.header
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedCode, rw.Code)
+			assert.Equal(t, test.expectedHeaders, header)
+		})
+	}
+}
+
+func TestParser_WrapHandler_pathNormalization(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		mode               string
+		target             string
+		expectedCode       int
+		expectedPath       string
+		expectedRequestURI string
+	}{
+		{
+			desc:               "none",
+			mode:               static.PathNormalizationNone,
+			target:             "/foo//bar/../baz",
+			expectedCode:       http.StatusOK,
+			expectedPath:       "/foo//bar/../baz",
+			expectedRequestURI: "/foo//bar/../baz",
+		},
+		{
+			desc:               "dot segments",
+			mode:               static.PathNormalizationDotSegments,
+			target:             "/foo//bar/../baz?a=b",
+			expectedCode:       http.StatusOK,
+			expectedPath:       "/foo//baz",
+			expectedRequestURI: "/foo//baz?a=b",
+		},
+		{
+			desc:               "encoded dot segments",
+			mode:               static.PathNormalizationDotSegments,
+			target:             "/foo/%2e%2E/bar%2Fbaz",
+			expectedCode:       http.StatusOK,
+			expectedPath:       "/bar/baz",
+			expectedRequestURI: "/bar%2Fbaz",
+		},
+		{
+			desc:               "merge slashes",
+			mode:               static.PathNormalizationMergeSlashes,
+			target:             "//foo///bar/./baz/..",
+			expectedCode:       http.StatusOK,
+			expectedPath:       "/foo/bar/",
+			expectedRequestURI: "/foo/bar/",
+		},
+		{
+			desc:         "reject",
+			mode:         static.PathNormalizationReject,
+			target:       "/foo/../bar",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "reject keeps the normalized paths",
+			mode:               static.PathNormalizationReject,
+			target:             "/foo/bar%2F",
+			expectedCode:       http.StatusOK,
+			expectedPath:       "/foo/bar/",
+			expectedRequestURI: "/foo/bar%2F",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			parser, err := New(&static.StrictParsing{PathNormalization: test.mode}, nil)
+			require.NoError(t, err)
+
+			var path, requestURI string
+			handler := parser.WrapHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				path = req.URL.Path
+				requestURI = req.RequestURI
+			}))
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, test.target, nil))
+
+			assert.Equal(t, test.expectedCode, rw.Code)
+			assert.Equal(t, test.expectedPath, path)
+			assert.Equal(t, test.expectedRequestURI, requestURI)
+		})
+	}
+}
+
+func TestParser_nil(t *testing.T) {
+	var parser *Parser
+
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+	assert.NotNil(t, parser.WrapHandler(handler))
+	assert.Nil(t, parser.WrapConn(nil))
+}
@@ -0,0 +1,85 @@
+package tcp
+
+import (
+	"errors"
+	"io"
+	"net"
+	"syscall"
+)
+
+// CloseReason is the reason why a connection ended.
+type CloseReason string
+
+// Close reasons.
+const (
+	// CloseReasonClientEOF is when the client closed its side of the connection first.
+	CloseReasonClientEOF CloseReason = "client_eof"
+	// CloseReasonClientReset is when the client reset the connection.
+	CloseReasonClientReset CloseReason = "client_reset"
+	// CloseReasonBackendEOF is when the backend closed its side of the connection first.
+	CloseReasonBackendEOF CloseReason = "backend_eof"
+	// CloseReasonBackendReset is when the backend reset the connection.
+	CloseReasonBackendReset CloseReason = "backend_reset"
+	// CloseReasonBackendUnreachable is when no connection could be opened to the backend.
+	CloseReasonBackendUnreachable CloseReason = "backend_unreachable"
+	// CloseReasonNoRoute is when no router matched the connection.
+	CloseReasonNoRoute CloseReason = "no_route"
+	// CloseReasonPolicy is when a middleware closed the connection, such as a rejected client.
+	CloseReasonPolicy CloseReason = "policy"
+	// CloseReasonTimeout is when a deadline of the connection was exceeded.
+	CloseReasonTimeout CloseReason = "timeout"
+	// CloseReasonCanceled is when the connection was canceled, such as on the shutdown of its entry point.
+	CloseReasonCanceled CloseReason = "canceled"
+	// CloseReasonError is when the connection ended on any other error.
+	CloseReasonError CloseReason = "error"
+)
+
+// CloseReasonRecorder is implemented by the connections which record why they ended.
+type CloseReasonRecorder interface {
+	// SetCloseReason records the reason why the connection ended, unless a reason has already been recorded.
+	SetCloseReason(reason CloseReason)
+}
+
+// SetCloseReason records the reason why the connection ended on the first connection recording it,
+// looking through the wrappers exposing the connection they wrap with a NetConn method, as tls.Conn does.
+// The first reason recorded for a connection wins, as it is the cause of the following ones.
+func SetCloseReason(conn net.Conn, reason CloseReason) {
+	if recorder, ok := lookupConn[CloseReasonRecorder](conn); ok {
+		recorder.SetCloseReason(reason)
+	}
+}
+
+// copyCloseReason returns the reason why the connection ended,
+// given the result of the first of the copies between the client and the backend to end.
+func copyCloseReason(err error, fromClient bool) CloseReason {
+	var opErr *net.OpError
+	if err != nil && errors.As(err, &opErr) && opErr.Op == "write" {
+		// The peer written to is the one which failed.
+		fromClient = !fromClient
+	}
+
+	switch {
+	case err == nil || errors.Is(err, io.EOF):
+		if fromClient {
+			return CloseReasonClientEOF
+		}
+		return CloseReasonBackendEOF
+
+	case errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE):
+		if fromClient {
+			return CloseReasonClientReset
+		}
+		return CloseReasonBackendReset
+
+	case isTimeout(err):
+		return CloseReasonTimeout
+
+	default:
+		return CloseReasonError
+	}
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
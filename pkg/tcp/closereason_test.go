@@ -0,0 +1,127 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingConn struct {
+	net.Conn
+
+	reasons []CloseReason
+}
+
+func (c *recordingConn) SetCloseReason(reason CloseReason) {
+	c.reasons = append(c.reasons, reason)
+}
+
+type wrappingConn struct {
+	net.Conn
+}
+
+func (c *wrappingConn) NetConn() net.Conn {
+	return c.Conn
+}
+
+func TestSetCloseReason(t *testing.T) {
+	recorder := &recordingConn{}
+
+	testCases := []struct {
+		desc     string
+		conn     net.Conn
+		expected []CloseReason
+	}{
+		{
+			desc:     "recorder",
+			conn:     recorder,
+			expected: []CloseReason{CloseReasonPolicy},
+		},
+		{
+			desc:     "wrapped recorder",
+			conn:     &wrappingConn{Conn: &wrappingConn{Conn: recorder}},
+			expected: []CloseReason{CloseReasonPolicy},
+		},
+		{
+			desc:     "recorder wrapped in a TLS connection",
+			conn:     tls.Server(&wrappingConn{Conn: recorder}, &tls.Config{}),
+			expected: []CloseReason{CloseReasonPolicy},
+		},
+		{
+			desc: "no recorder",
+			conn: &wrappingConn{Conn: &net.TCPConn{}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			recorder.reasons = nil
+
+			SetCloseReason(test.conn, CloseReasonPolicy)
+
+			assert.Equal(t, test.expected, recorder.reasons)
+		})
+	}
+}
+
+func TestCopyCloseReason(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		err        error
+		fromClient bool
+		expected   CloseReason
+	}{
+		{
+			desc:       "client EOF",
+			fromClient: true,
+			expected:   CloseReasonClientEOF,
+		},
+		{
+			desc:     "backend EOF",
+			err:      io.EOF,
+			expected: CloseReasonBackendEOF,
+		},
+		{
+			desc:       "client reset on read",
+			err:        &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)},
+			fromClient: true,
+			expected:   CloseReasonClientReset,
+		},
+		{
+			desc:       "backend reset on write",
+			err:        &net.OpError{Op: "write", Err: os.NewSyscallError("write", syscall.EPIPE)},
+			fromClient: true,
+			expected:   CloseReasonBackendReset,
+		},
+		{
+			desc:     "client reset on write",
+			err:      &net.OpError{Op: "write", Err: os.NewSyscallError("write", syscall.ECONNRESET)},
+			expected: CloseReasonClientReset,
+		},
+		{
+			desc:       "timeout",
+			err:        &net.OpError{Op: "read", Err: os.ErrDeadlineExceeded},
+			fromClient: true,
+			expected:   CloseReasonTimeout,
+		},
+		{
+			desc:     "other error",
+			err:      io.ErrUnexpectedEOF,
+			expected: CloseReasonError,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, copyCloseReason(test.err, test.fromClient))
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package tcp
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// ConnectHandler tunnels the connections of the HTTP CONNECT requests into a TCP handler.
+type ConnectHandler struct {
+	next Handler
+}
+
+// NewConnectHandler creates a new ConnectHandler, forwarding the tunnels to next.
+func NewConnectHandler(next Handler) *ConnectHandler {
+	return &ConnectHandler{next: next}
+}
+
+func (h *ConnectHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodConnect {
+		rw.Header().Set("Allow", http.MethodConnect)
+		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	hijacker, ok := rw.(http.Hijacker)
+	if !ok || req.ProtoMajor != 1 {
+		http.Error(rw, "CONNECT tunnels are only supported over HTTP/1.1", http.StatusHTTPVersionNotSupported)
+		return
+	}
+
+	logger := log.FromContext(req.Context())
+
+	conn, bufRW, err := hijacker.Hijack()
+	if err != nil {
+		logger.Errorf("Error while hijacking the CONNECT request: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	// The deadlines set by the HTTP server on the connection would interrupt the tunnel.
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		logger.Debugf("Error while resetting the deadlines of the CONNECT tunnel: %v", err)
+	}
+
+	if _, err := bufRW.WriteString("HTTP/1.1 200 Connection Established\r\n\r\n"); err != nil {
+		logger.Debugf("Error while opening the CONNECT tunnel: %v", err)
+		_ = conn.Close()
+		return
+	}
+
+	if err := bufRW.Flush(); err != nil {
+		logger.Debugf("Error while opening the CONNECT tunnel: %v", err)
+		_ = conn.Close()
+		return
+	}
+
+	// The bytes sent by the client right after the request may already be buffered.
+	buffered, _ := bufRW.Reader.Peek(bufRW.Reader.Buffered())
+
+	h.next.ServeTCP(&tunnelConn{
+		WriteCloser: writeCloser(conn),
+		buffered:    append([]byte(nil), buffered...),
+	})
+}
+
+// tunnelConn is the connection of a CONNECT tunnel, reading first the bytes buffered by the HTTP server.
+type tunnelConn struct {
+	WriteCloser
+	buffered []byte
+}
+
+// Read reads the buffered bytes, and then the ones of the connection.
+func (c *tunnelConn) Read(p []byte) (int, error) {
+	if len(c.buffered) > 0 {
+		n := copy(p, c.buffered)
+		c.buffered = c.buffered[n:]
+		return n, nil
+	}
+
+	return c.WriteCloser.Read(p)
+}
+
+// Unwrap returns the underlying connection, along with the buffered bytes not consumed yet,
+// which are handed over to the caller.
+func (c *tunnelConn) Unwrap() (WriteCloser, []byte) {
+	buffered := c.buffered
+	c.buffered = nil
+
+	return c.WriteCloser, buffered
+}
+
+// writeCloser returns the given connection as a WriteCloser,
+// closing it entirely on CloseWrite when it cannot be half-closed.
+func writeCloser(conn net.Conn) WriteCloser {
+	if wc, ok := conn.(WriteCloser); ok {
+		return wc
+	}
+
+	return closeWriter{Conn: conn}
+}
+
+type closeWriter struct {
+	net.Conn
+}
+
+func (c closeWriter) CloseWrite() error {
+	return c.Conn.Close()
+}
@@ -0,0 +1,62 @@
+package tcp
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectHandler(t *testing.T) {
+	echo := HandlerFunc(func(conn WriteCloser) {
+		defer conn.Close()
+
+		_, _ = io.Copy(conn, conn)
+	})
+
+	server := httptest.NewServer(NewConnectHandler(echo))
+	t.Cleanup(server.Close)
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	// The first bytes of the tunnel are sent along with the request.
+	_, err = conn.Write([]byte("CONNECT backend:5432 HTTP/1.1\r\nHost: backend:5432\r\n\r\nping"))
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(conn)
+
+	resp, err := http.ReadResponse(reader, &http.Request{Method: http.MethodConnect})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(reader, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+
+	_, err = conn.Write([]byte("pong"))
+	require.NoError(t, err)
+
+	_, err = io.ReadFull(reader, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "pong", string(buf))
+}
+
+func TestConnectHandler_notConnect(t *testing.T) {
+	handler := NewConnectHandler(HandlerFunc(func(conn WriteCloser) {
+		t.Error("the tunnel should not be opened")
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+	assert.Equal(t, http.MethodConnect, recorder.Header().Get("Allow"))
+}
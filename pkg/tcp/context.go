@@ -0,0 +1,43 @@
+package tcp
+
+import (
+	"context"
+	"net"
+)
+
+// ContextConn is implemented by the connections carrying a context,
+// which is done when the connection is closed, or canceled, such as on the shutdown of its entry point.
+type ContextConn interface {
+	Context() context.Context
+}
+
+// ConnContext returns the context of the first connection carrying one,
+// looking through the wrappers exposing the connection they wrap with a NetConn method, as tls.Conn does.
+// It returns context.Background if no connection carries a context.
+func ConnContext(conn net.Conn) context.Context {
+	if contextConn, ok := lookupConn[ContextConn](conn); ok {
+		return contextConn.Context()
+	}
+
+	return context.Background()
+}
+
+// lookupConn returns the first connection implementing T,
+// looking through the wrappers exposing the connection they wrap with a NetConn method.
+func lookupConn[T any](conn net.Conn) (T, bool) {
+	for conn != nil {
+		if found, ok := conn.(T); ok {
+			return found, true
+		}
+
+		wrapper, ok := conn.(interface{ NetConn() net.Conn })
+		if !ok {
+			break
+		}
+
+		conn = wrapper.NetConn()
+	}
+
+	var zero T
+	return zero, false
+}
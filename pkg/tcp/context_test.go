@@ -0,0 +1,29 @@
+package tcp
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type contextConn struct {
+	net.Conn
+
+	ctx context.Context
+}
+
+func (c *contextConn) Context() context.Context {
+	return c.ctx
+}
+
+func TestConnContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn := &wrappingConn{Conn: &contextConn{ctx: ctx}}
+	assert.Equal(t, ctx, ConnContext(conn))
+
+	assert.Equal(t, context.Background(), ConnContext(&wrappingConn{Conn: &net.TCPConn{}}))
+}
@@ -0,0 +1,125 @@
+package tcp
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WithIdleTimeout returns the connection, closed on its reads and writes
+// when no byte was read from or written to it, in either direction, during the timeout.
+// The deadlines set explicitly on the connection, such as the termination delay of the proxy, still apply.
+func WithIdleTimeout(conn WriteCloser, timeout time.Duration) WriteCloser {
+	if timeout <= 0 {
+		return conn
+	}
+
+	c := &idleConn{WriteCloser: conn, timeout: timeout}
+	c.touch()
+
+	return c
+}
+
+// idleConn extends its read and write deadlines on every byte read or written.
+type idleConn struct {
+	WriteCloser
+
+	timeout time.Duration
+	// lastActivity is the time, in Unix nanoseconds, when a byte was last read or written.
+	lastActivity atomic.Int64
+
+	mu            sync.Mutex
+	readDeadline  time.Time
+	writeDeadline time.Time
+}
+
+func (c *idleConn) Read(p []byte) (int, error) {
+	for {
+		c.mu.Lock()
+		explicit := c.readDeadline
+		c.mu.Unlock()
+
+		idleDeadline := c.idleDeadline()
+		if err := c.WriteCloser.SetReadDeadline(earliest(idleDeadline, explicit)); err != nil {
+			return 0, err
+		}
+
+		n, err := c.WriteCloser.Read(p)
+		if n > 0 {
+			c.touch()
+		}
+
+		// The other direction may have been active while this read was blocked,
+		// in which case the connection is not idle, and the read goes on.
+		if n == 0 && isTimeout(err) && c.idleDeadline().After(time.Now()) && (explicit.IsZero() || explicit.After(time.Now())) {
+			continue
+		}
+
+		return n, err
+	}
+}
+
+func (c *idleConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	explicit := c.writeDeadline
+	c.mu.Unlock()
+
+	// A write blocked during the timeout is not making any progress, whatever the other direction does.
+	if err := c.WriteCloser.SetWriteDeadline(earliest(time.Now().Add(c.timeout), explicit)); err != nil {
+		return 0, err
+	}
+
+	n, err := c.WriteCloser.Write(p)
+	if n > 0 {
+		c.touch()
+	}
+
+	return n, err
+}
+
+func (c *idleConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+
+	return c.SetWriteDeadline(t)
+}
+
+func (c *idleConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.readDeadline = t
+	c.mu.Unlock()
+
+	return c.WriteCloser.SetReadDeadline(earliest(c.idleDeadline(), t))
+}
+
+func (c *idleConn) SetWriteDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.writeDeadline = t
+	c.mu.Unlock()
+
+	return c.WriteCloser.SetWriteDeadline(t)
+}
+
+// NetConn returns the underlying connection.
+func (c *idleConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+func (c *idleConn) touch() {
+	c.lastActivity.Store(time.Now().UnixNano())
+}
+
+func (c *idleConn) idleDeadline() time.Time {
+	return time.Unix(0, c.lastActivity.Load()).Add(c.timeout)
+}
+
+// earliest returns the earliest of the deadlines, the zero deadline meaning no deadline.
+func earliest(deadline, other time.Time) time.Time {
+	if other.IsZero() || deadline.Before(other) {
+		return deadline
+	}
+
+	return other
+}
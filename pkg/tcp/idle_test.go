@@ -0,0 +1,85 @@
+package tcp
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tcpPair(t *testing.T) (*net.TCPConn, *net.TCPConn) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	client, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	server, err := listener.Accept()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = server.Close() })
+
+	return client.(*net.TCPConn), server.(*net.TCPConn)
+}
+
+func TestWithIdleTimeout_idle(t *testing.T) {
+	_, server := tcpPair(t)
+
+	conn := WithIdleTimeout(server, 100*time.Millisecond)
+
+	start := time.Now()
+	_, err := conn.Read(make([]byte, 1))
+	require.Error(t, err)
+
+	assert.True(t, isTimeout(err))
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestWithIdleTimeout_activeOtherDirection(t *testing.T) {
+	client, server := tcpPair(t)
+
+	conn := WithIdleTimeout(server, 200*time.Millisecond)
+
+	readErr := make(chan error, 1)
+	go func() {
+		_, err := conn.Read(make([]byte, 1))
+		readErr <- err
+	}()
+
+	// Writing keeps the connection active, even though nothing is read.
+	go func() { _, _ = io.Copy(io.Discard, client) }()
+	for i := 0; i < 5; i++ {
+		_, err := conn.Write([]byte("ping"))
+		require.NoError(t, err)
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	select {
+	case err := <-readErr:
+		t.Fatalf("read ended while the connection was active: %v", err)
+	default:
+	}
+
+	err := <-readErr
+	assert.True(t, isTimeout(err))
+}
+
+func TestWithIdleTimeout_explicitDeadline(t *testing.T) {
+	_, server := tcpPair(t)
+
+	conn := WithIdleTimeout(server, time.Minute)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+
+	start := time.Now()
+	_, err := conn.Read(make([]byte, 1))
+	require.Error(t, err)
+
+	assert.True(t, isTimeout(err))
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
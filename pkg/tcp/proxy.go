@@ -1,6 +1,7 @@
 package tcp
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -11,29 +12,75 @@ import (
 
 	"github.com/pires/go-proxyproto"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/dnsresolver"
+	"github.com/traefik/traefik/v2/pkg/egress"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/sockopt"
+	"github.com/traefik/traefik/v2/pkg/unixsocket"
+	"github.com/traefik/traefik/v2/pkg/vsock"
 )
 
 // Proxy forwards a TCP request to a TCP service.
 type Proxy struct {
 	address          string
+	unixPath         string
+	vsockAddr        *vsock.Addr
 	tcpAddr          *net.TCPAddr
 	terminationDelay time.Duration
 	proxyProtocol    *dynamic.ProxyProtocol
 	sourceIPs        []net.TCPAddr
+	fastPath         bool
+	transparent      bool
+	multipathTCP     bool
+	resolver         *dnsresolver.Resolver
+	egressPolicy     *egress.Policy
+}
+
+// ProxyOptions holds the options of a Proxy.
+type ProxyOptions struct {
+	// SourceIPs are the local addresses the connections to the backend are opened from, one picked at random per connection.
+	SourceIPs []net.TCPAddr
+	// FastPath forwards the bytes between the TCP connections without going through the connection wrappers which can be bypassed,
+	// which lets the kernel forward them directly, with splice(2), on Linux.
+	// A connection going through a wrapper which does not implement Unwrapper,
+	// such as the one enforcing the TCP idle timeout of the entry point, is forwarded without the fast path.
+	FastPath bool
+	// Transparent opens the connections to the backend from the address of the client.
+	Transparent bool
+	// MultipathTCP opens the connections to the backend with Multipath TCP, when the kernel and the backend support it.
+	MultipathTCP bool
+	// Resolver, if any, looks the hostname of the address up instead of the resolver of the operating system.
+	Resolver *dnsresolver.Resolver
+	// EgressPolicy, if any, restricts the destinations the address is dialed at.
+	EgressPolicy *egress.Policy
 }
 
 // NewProxy creates a new Proxy.
-func NewProxy(address string, terminationDelay time.Duration, proxyProtocol *dynamic.ProxyProtocol, sourceIPs []net.TCPAddr) (*Proxy, error) {
+func NewProxy(address string, terminationDelay time.Duration, proxyProtocol *dynamic.ProxyProtocol, opts ProxyOptions) (*Proxy, error) {
 	if proxyProtocol != nil && (proxyProtocol.Version < 1 || proxyProtocol.Version > 2) {
 		return nil, fmt.Errorf("unknown proxyProtocol version: %d", proxyProtocol.Version)
 	}
 
+	if opts.Transparent && len(opts.SourceIPs) > 0 {
+		return nil, errors.New("the transparent mode and the source IPs are mutually exclusive")
+	}
+
+	unixPath, isUnix := unixsocket.Path(address)
+
+	vsockAddr, isVsock, err := vsock.ParseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Transparent && (isUnix || isVsock) {
+		return nil, errors.New("the transparent mode only applies to TCP addresses")
+	}
+
 	// Creates the tcpAddr only for IP based addresses,
 	// because there is no need to resolve the name on every new connection,
 	// and building it should happen once.
 	var tcpAddr *net.TCPAddr
-	if host, _, err := net.SplitHostPort(address); err == nil && net.ParseIP(host) != nil {
+	if host, _, err := net.SplitHostPort(address); !isUnix && !isVsock && err == nil && net.ParseIP(host) != nil {
 		tcpAddr, err = net.ResolveTCPAddr("tcp", address)
 		if err != nil {
 			return nil, err
@@ -42,10 +89,17 @@ func NewProxy(address string, terminationDelay time.Duration, proxyProtocol *dyn
 
 	return &Proxy{
 		address:          address,
+		unixPath:         unixPath,
+		vsockAddr:        vsockAddr,
 		tcpAddr:          tcpAddr,
 		terminationDelay: terminationDelay,
 		proxyProtocol:    proxyProtocol,
-		sourceIPs:        sourceIPs,
+		sourceIPs:        opts.SourceIPs,
+		fastPath:         opts.FastPath,
+		transparent:      opts.Transparent,
+		multipathTCP:     opts.MultipathTCP,
+		resolver:         opts.Resolver,
+		egressPolicy:     opts.EgressPolicy,
 	}, nil
 }
 
@@ -56,29 +110,61 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	// needed because of e.g. server.trackedConnection
 	defer conn.Close()
 
-	connBackend, err := p.dialBackend()
+	connBackend, err := p.dialBackend(conn.RemoteAddr())
 	if err != nil {
 		log.WithoutContext().Errorf("Error while dialing backend: %v", err)
+		SetCloseReason(conn, CloseReasonBackendUnreachable)
 		return
 	}
 
 	// maybe not needed, but just in case
 	defer connBackend.Close()
-	errChan := make(chan error)
+
+	// Closing both connections ends both copies, when the connection is canceled.
+	stop := context.AfterFunc(ConnContext(conn), func() {
+		SetCloseReason(conn, CloseReasonCanceled)
+		_ = connBackend.Close()
+		_ = conn.Close()
+	})
+	defer stop()
+
+	errChan := make(chan copyResult)
 
 	if p.proxyProtocol != nil && p.proxyProtocol.Version > 0 && p.proxyProtocol.Version < 3 {
 		header := proxyproto.HeaderProxyFromAddrs(byte(p.proxyProtocol.Version), conn.RemoteAddr(), conn.LocalAddr())
 		if _, err := header.WriteTo(connBackend); err != nil {
 			log.WithoutContext().Errorf("Error while writing TCP proxy protocol headers to backend connection: %v", err)
+			SetCloseReason(conn, CloseReasonError)
 			return
 		}
 	}
 
-	go p.connCopy(conn, connBackend, errChan)
-	go p.connCopy(connBackend, conn, errChan)
+	// The deferred Close still goes through the connection wrappers, which may need to know when the connection ends.
+	var client WriteCloser = conn
+	if p.fastPath {
+		var buffered []byte
+		client, buffered = unwrap(conn)
 
-	err = <-errChan
-	if err != nil {
+		if len(buffered) > 0 {
+			if _, err := connBackend.Write(buffered); err != nil {
+				log.WithoutContext().Errorf("Error while writing buffered bytes to backend connection: %v", err)
+				SetCloseReason(conn, CloseReasonError)
+				return
+			}
+		}
+
+		if _, ok := client.(*net.TCPConn); !ok {
+			log.WithoutContext().Debugf("Forwarding TCP connection from %s without the fast path, since it goes through a %T, which cannot be bypassed as it does not implement Unwrap", conn.RemoteAddr(), client)
+		}
+	}
+
+	go p.connCopy(client, connBackend, false, errChan)
+	go p.connCopy(connBackend, client, true, errChan)
+
+	result := <-errChan
+	SetCloseReason(conn, copyCloseReason(result.err, result.fromClient))
+
+	if err := result.err; err != nil {
 		// Treat connection reset error during a read operation with a lower log level.
 		// This allows to not report an RST packet sent by the peer as an error,
 		// as it is an abrupt but possible end for the TCP session
@@ -92,32 +178,100 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	<-errChan
 }
 
-func (p Proxy) dialBackend() (*net.TCPConn, error) {
+func (p Proxy) dialBackend(clientAddr net.Addr) (WriteCloser, error) {
+	if p.unixPath != "" {
+		conn, err := net.DialUnix("unix", nil, &net.UnixAddr{Name: p.unixPath, Net: "unix"})
+		if err != nil {
+			return nil, err
+		}
+
+		return conn, nil
+	}
+
+	if p.vsockAddr != nil {
+		return vsock.Dial(p.vsockAddr)
+	}
+
+	if p.transparent {
+		return p.dialTransparent(clientAddr)
+	}
+
 	var sourceIP *net.TCPAddr
 	if len(p.sourceIPs) > 0 {
 		sourceIP = &p.sourceIPs[rand.Intn(len(p.sourceIPs))]
 	}
 
 	// Dial using directly the TCPAddr for IP based addresses.
-	if p.tcpAddr != nil {
-		return net.DialTCP("tcp", sourceIP, p.tcpAddr)
+	// DialTCP does not support Multipath TCP, and the dialer does not look the IP addresses up.
+	if p.tcpAddr != nil && !p.multipathTCP {
+		if err := p.egressPolicy.Check("tcp", p.tcpAddr.String()); err != nil {
+			return nil, err
+		}
+
+		conn, err := net.DialTCP("tcp", sourceIP, p.tcpAddr)
+		if err != nil {
+			return nil, err
+		}
+
+		return conn, nil
 	}
 
 	log.WithoutContext().Debugf("Dial with lookup to address %s", p.address)
 
 	// Dial with DNS lookup for host based addresses.
-	dialer := net.Dialer{LocalAddr: sourceIP}
-	conn, err := dialer.Dial("tcp", p.address)
+	return p.dialTCP(net.Dialer{LocalAddr: sourceIP})
+}
+
+// dialTransparent dials the backend from the IP address of the client, on a port picked by the kernel,
+// for the backend to see the connection as coming from the client.
+func (p Proxy) dialTransparent(clientAddr net.Addr) (WriteCloser, error) {
+	tcpAddr, ok := clientAddr.(*net.TCPAddr)
+	if !ok {
+		return nil, fmt.Errorf("transparent mode: the address of the client %s is not a TCP address", clientAddr)
+	}
+
+	return p.dialTCP(net.Dialer{
+		LocalAddr: &net.TCPAddr{IP: tcpAddr.IP, Zone: tcpAddr.Zone},
+		Control:   transparentControl,
+	})
+}
+
+// dialTCP dials the backend with the dialer, enabling Multipath TCP if configured,
+// looking its hostname up with the resolver, and checking its address against the egress policy, if any.
+func (p Proxy) dialTCP(dialer net.Dialer) (WriteCloser, error) {
+	if p.multipathTCP {
+		dialer.SetMultipathTCP(true)
+	}
+
+	if p.egressPolicy != nil {
+		dialer.Control = sockopt.Chain(p.egressPolicy.Control(), dialer.Control)
+	}
+
+	conn, err := p.resolver.DialContext(dialer.DialContext)(context.Background(), "tcp", p.address)
 	if err != nil {
 		return nil, err
 	}
 
-	return conn.(*net.TCPConn), nil
+	tcpConn := conn.(*net.TCPConn)
+
+	if p.multipathTCP {
+		if mptcp, err := tcpConn.MultipathTCP(); err == nil {
+			log.WithoutContext().Debugf("Connection to %s uses multipath TCP: %t", p.address, mptcp)
+		}
+	}
+
+	return tcpConn, nil
+}
+
+// copyResult is the result of the copy from one side of a proxied connection to the other.
+type copyResult struct {
+	err        error
+	fromClient bool
 }
 
-func (p Proxy) connCopy(dst, src WriteCloser, errCh chan error) {
+func (p Proxy) connCopy(dst, src WriteCloser, fromClient bool, errCh chan copyResult) {
 	_, err := io.Copy(dst, src)
-	errCh <- err
+	errCh <- copyResult{err: err, fromClient: fromClient}
 
 	// Ends the connection with the dst connection peer.
 	// It corresponds to sending a FIN packet to gracefully end the TCP session.
@@ -0,0 +1,159 @@
+package tcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/tcp/testserver"
+)
+
+func TestProxy_abstractUnixSocket(t *testing.T) {
+	name := fmt.Sprintf("@traefik-test-%d", time.Now().UnixNano())
+
+	backendListener, err := net.Listen("unix", name)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backendListener.Close() })
+
+	go func() {
+		conn, err := backendListener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		_, _ = testserver.Replay(conn,
+			testserver.Expect("ping"),
+			testserver.Send("pong"),
+			testserver.CloseWrite(),
+		)
+	}()
+
+	proxy, err := NewProxy("unix://"+name, time.Second, nil, ProxyOptions{})
+	require.NoError(t, err)
+
+	client, server := tcpPair(t)
+	go proxy.ServeTCP(server)
+
+	received, err := testserver.Replay(client,
+		testserver.Send("ping"),
+		testserver.Expect("pong"),
+		testserver.ExpectEOF(),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "pong", string(received))
+}
+
+func TestProxy_invalidVsockAddress(t *testing.T) {
+	_, err := NewProxy("vsock://vm:1024", time.Second, nil, ProxyOptions{})
+	require.Error(t, err)
+}
+
+func TestProxy_transparent(t *testing.T) {
+	backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backendListener.Close() })
+
+	remoteAddrs := make(chan net.Addr, 1)
+	go func() {
+		conn, err := backendListener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		remoteAddrs <- conn.RemoteAddr()
+
+		_, _ = testserver.Replay(conn,
+			testserver.Expect("ping"),
+			testserver.Send("pong"),
+			testserver.CloseWrite(),
+		)
+	}()
+
+	proxy, err := NewProxy(backendListener.Addr().String(), time.Second, nil, ProxyOptions{Transparent: true})
+	require.NoError(t, err)
+
+	// The client connects from another address than the one of the proxy.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	probe := net.Dialer{LocalAddr: &net.TCPAddr{IP: net.ParseIP("127.0.0.2")}, Control: transparentControl}
+	conn, err := probe.Dial("tcp", listener.Addr().String())
+	if errors.Is(err, syscall.EPERM) {
+		t.Skip("the transparent mode requires CAP_NET_ADMIN")
+	}
+	require.NoError(t, err)
+	_ = conn.Close()
+
+	probed, err := listener.Accept()
+	require.NoError(t, err)
+	_ = probed.Close()
+
+	dialer := net.Dialer{LocalAddr: &net.TCPAddr{IP: net.ParseIP("127.0.0.2")}}
+	client, err := dialer.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	server, err := listener.Accept()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = server.Close() })
+
+	go proxy.ServeTCP(server.(*net.TCPConn))
+
+	received, err := testserver.Replay(client,
+		testserver.Send("ping"),
+		testserver.Expect("pong"),
+		testserver.ExpectEOF(),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "pong", string(received))
+
+	remoteAddr := <-remoteAddrs
+	assert.Equal(t, "127.0.0.2", remoteAddr.(*net.TCPAddr).IP.String())
+}
+
+func TestNewProxy_transparentInvalid(t *testing.T) {
+	_, err := NewProxy("127.0.0.1:80", time.Second, nil, ProxyOptions{SourceIPs: []net.TCPAddr{{IP: net.ParseIP("127.0.0.1")}}, Transparent: true})
+	require.Error(t, err)
+
+	_, err = NewProxy("unix:///var/run/app.sock", time.Second, nil, ProxyOptions{Transparent: true})
+	require.Error(t, err)
+}
+
+func TestProxy_multipathTCP(t *testing.T) {
+	if enabled, err := os.ReadFile("/proc/sys/net/mptcp/enabled"); err != nil || strings.TrimSpace(string(enabled)) != "1" {
+		t.Skip("Multipath TCP is not enabled in the kernel")
+	}
+
+	lc := net.ListenConfig{}
+	lc.SetMultipathTCP(true)
+
+	backendListener, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backendListener.Close() })
+
+	proxy, err := NewProxy(backendListener.Addr().String(), time.Second, nil, ProxyOptions{MultipathTCP: true})
+	require.NoError(t, err)
+
+	conn, err := proxy.dialBackend(nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	backendConn, err := backendListener.Accept()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backendConn.Close() })
+
+	mptcp, err := backendConn.(*net.TCPConn).MultipathTCP()
+	require.NoError(t, err)
+	assert.True(t, mptcp)
+}
@@ -2,10 +2,12 @@ package tcp
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -13,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/tcp/testserver"
 )
 
 func fakeRedis(t *testing.T, listener net.Listener) {
@@ -54,7 +57,7 @@ func TestCloseWrite(t *testing.T) {
 	_, port, err := net.SplitHostPort(backendListener.Addr().String())
 	require.NoError(t, err)
 
-	proxy, err := NewProxy(":"+port, 10*time.Millisecond, nil)
+	proxy, err := NewProxy(":"+port, 10*time.Millisecond, nil, ProxyOptions{})
 	require.NoError(t, err)
 
 	proxyListener, err := net.Listen("tcp", ":0")
@@ -88,6 +91,139 @@ func TestCloseWrite(t *testing.T) {
 	require.Equal(t, "PONG", buffer.String())
 }
 
+func TestProxy_halfClose(t *testing.T) {
+	// The backend keeps on writing after the client has closed its write side of the connection.
+	backend := testserver.Start(t,
+		testserver.Expect("ping"),
+		testserver.ExpectEOF(),
+		testserver.Send("pong"),
+		testserver.Sleep(10*time.Millisecond),
+		testserver.Send("pong"),
+		testserver.CloseWrite(),
+	)
+
+	// The termination delay bounds how long the backend can keep on writing.
+	proxy, err := NewProxy(backend.Addr(), time.Second, nil, ProxyOptions{})
+	require.NoError(t, err)
+
+	proxyListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = proxyListener.Close() })
+
+	go func() {
+		conn, err := proxyListener.Accept()
+		if err != nil {
+			return
+		}
+		proxy.ServeTCP(conn.(*net.TCPConn))
+	}()
+
+	conn, err := net.Dial("tcp", proxyListener.Addr().String())
+	require.NoError(t, err)
+
+	received, err := testserver.Replay(conn,
+		testserver.Send("ping"),
+		testserver.CloseWrite(),
+		testserver.Expect("pongpong"),
+		testserver.ExpectEOF(),
+		testserver.Close(),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "pongpong", string(received))
+
+	require.NoError(t, backend.Wait())
+}
+
+type canceledConn struct {
+	*net.TCPConn
+
+	ctx    context.Context
+	reason CloseReason
+}
+
+func (c *canceledConn) Context() context.Context {
+	return c.ctx
+}
+
+func (c *canceledConn) SetCloseReason(reason CloseReason) {
+	if c.reason == "" {
+		c.reason = reason
+	}
+}
+
+func TestProxy_canceled(t *testing.T) {
+	// The backend never ends the connection on its own.
+	backend := testserver.Start(t,
+		testserver.Expect("ping"),
+		testserver.ExpectEOF(),
+	)
+
+	// Without termination delay, only the cancellation ends the connection.
+	proxy, err := NewProxy(backend.Addr(), -1, nil, ProxyOptions{})
+	require.NoError(t, err)
+
+	client, server := tcpPair(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	conn := &canceledConn{TCPConn: server, ctx: ctx}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		proxy.ServeTCP(conn)
+	}()
+
+	_, err = client.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the proxy did not end the canceled connection")
+	}
+
+	assert.Equal(t, CloseReasonCanceled, conn.reason)
+}
+
+func TestProxy_unixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backend.sock")
+
+	backendListener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backendListener.Close() })
+
+	go func() {
+		conn, err := backendListener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		_, _ = testserver.Replay(conn,
+			testserver.Expect("ping"),
+			testserver.Send("pong"),
+			testserver.CloseWrite(),
+		)
+	}()
+
+	proxy, err := NewProxy("unix://"+path, time.Second, nil, ProxyOptions{})
+	require.NoError(t, err)
+
+	client, server := tcpPair(t)
+	go proxy.ServeTCP(server)
+
+	received, err := testserver.Replay(client,
+		testserver.Send("ping"),
+		testserver.Expect("pong"),
+		testserver.ExpectEOF(),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "pong", string(received))
+}
+
 func TestProxyProtocol(t *testing.T) {
 	testCases := []struct {
 		desc    string
@@ -133,7 +269,7 @@ func TestProxyProtocol(t *testing.T) {
 			_, port, err := net.SplitHostPort(proxyBackendListener.Addr().String())
 			require.NoError(t, err)
 
-			proxy, err := NewProxy(":"+port, 10*time.Millisecond, &dynamic.ProxyProtocol{Version: test.version})
+			proxy, err := NewProxy(":"+port, 10*time.Millisecond, &dynamic.ProxyProtocol{Version: test.version}, ProxyOptions{})
 			require.NoError(t, err)
 
 			proxyListener, err := net.Listen("tcp", ":0")
@@ -198,12 +334,12 @@ func TestLookupAddress(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			proxy, err := NewProxy(test.address, 10*time.Millisecond, nil)
+			proxy, err := NewProxy(test.address, 10*time.Millisecond, nil, ProxyOptions{})
 			require.NoError(t, err)
 
 			test.expectRefresh(t, proxy.tcpAddr)
 
-			conn, err := proxy.dialBackend()
+			conn, err := proxy.dialBackend(nil)
 			require.NoError(t, err)
 
 			test.expectAddr(t, test.address, conn.RemoteAddr().String())
@@ -0,0 +1,252 @@
+package testserver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// DefaultReadTimeout is how long an Expect or ExpectEOF step waits for the bytes, or the EOF, by default.
+const DefaultReadTimeout = 5 * time.Second
+
+type stepKind int
+
+const (
+	stepSend stepKind = iota
+	stepExpect
+	stepExpectEOF
+	stepSleep
+	stepCloseWrite
+	stepClose
+)
+
+// Step is a step of a scripted byte exchange.
+type Step struct {
+	kind    stepKind
+	data    []byte
+	timeout time.Duration
+}
+
+func (s Step) String() string {
+	switch s.kind {
+	case stepSend:
+		return fmt.Sprintf("Send(%q)", s.data)
+	case stepExpect:
+		return fmt.Sprintf("Expect(%q)", s.data)
+	case stepExpectEOF:
+		return "ExpectEOF()"
+	case stepSleep:
+		return fmt.Sprintf("Sleep(%s)", s.timeout)
+	case stepCloseWrite:
+		return "CloseWrite()"
+	case stepClose:
+		return "Close()"
+	default:
+		return "unknown step"
+	}
+}
+
+// Send writes data to the connection.
+func Send(data string) Step {
+	return Step{kind: stepSend, data: []byte(data)}
+}
+
+// Expect reads len(data) bytes from the connection, and checks that they are data.
+func Expect(data string) Step {
+	return ExpectWithin(data, DefaultReadTimeout)
+}
+
+// ExpectWithin reads len(data) bytes from the connection within timeout, and checks that they are data.
+func ExpectWithin(data string, timeout time.Duration) Step {
+	return Step{kind: stepExpect, data: []byte(data), timeout: timeout}
+}
+
+// ExpectEOF checks that the peer has closed its write side of the connection, without sending more bytes.
+func ExpectEOF() Step {
+	return Step{kind: stepExpectEOF, timeout: DefaultReadTimeout}
+}
+
+// Sleep waits for the given duration.
+func Sleep(d time.Duration) Step {
+	return Step{kind: stepSleep, timeout: d}
+}
+
+// CloseWrite closes the write side of the connection (half-close).
+func CloseWrite() Step {
+	return Step{kind: stepCloseWrite}
+}
+
+// Close closes the connection.
+func Close() Step {
+	return Step{kind: stepClose}
+}
+
+// Replay replays the script on the given connection, and returns all the bytes read from it.
+// It stops at the first step that fails.
+func Replay(conn net.Conn, script ...Step) ([]byte, error) {
+	var received []byte
+	for i, step := range script {
+		data, err := replayStep(conn, step)
+		received = append(received, data...)
+		if err != nil {
+			return received, fmt.Errorf("step %d %s: %w", i, step, err)
+		}
+	}
+
+	return received, nil
+}
+
+func replayStep(conn net.Conn, step Step) ([]byte, error) {
+	switch step.kind {
+	case stepSend:
+		_, err := conn.Write(step.data)
+		return nil, err
+
+	case stepExpect:
+		if err := conn.SetReadDeadline(time.Now().Add(step.timeout)); err != nil {
+			return nil, err
+		}
+
+		data := make([]byte, len(step.data))
+		n, err := io.ReadFull(conn, data)
+		if err != nil {
+			return data[:n], fmt.Errorf("read %q: %w", data[:n], err)
+		}
+
+		if !bytes.Equal(data, step.data) {
+			return data, fmt.Errorf("unexpected bytes %q", data)
+		}
+		return data, nil
+
+	case stepExpectEOF:
+		if err := conn.SetReadDeadline(time.Now().Add(step.timeout)); err != nil {
+			return nil, err
+		}
+
+		data, err := io.ReadAll(conn)
+		if err != nil {
+			return data, err
+		}
+
+		if len(data) > 0 {
+			return data, fmt.Errorf("unexpected bytes %q before EOF", data)
+		}
+		return nil, nil
+
+	case stepSleep:
+		time.Sleep(step.timeout)
+		return nil, nil
+
+	case stepCloseWrite:
+		writeCloser, ok := conn.(interface{ CloseWrite() error })
+		if !ok {
+			return nil, fmt.Errorf("%T does not support half-close", conn)
+		}
+		return nil, writeCloser.CloseWrite()
+
+	case stepClose:
+		return nil, conn.Close()
+
+	default:
+		return nil, errors.New("unknown step")
+	}
+}
+
+// Server is a TCP server replaying a script on each connection it accepts,
+// and recording the bytes it receives.
+type Server struct {
+	listener net.Listener
+	script   []Step
+
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	received [][]byte
+	errs     []error
+}
+
+// Start starts a server replaying the script, which is closed at the end of the test.
+func Start(t *testing.T, script ...Step) *Server {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{listener: listener, script: script}
+	t.Cleanup(s.Close)
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s
+}
+
+// Addr returns the address of the server.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		index := len(s.received)
+		s.received = append(s.received, nil)
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer func() { _ = conn.Close() }()
+
+			received, err := Replay(conn, s.script...)
+
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			s.received[index] = received
+			if err != nil {
+				s.errs = append(s.errs, fmt.Errorf("connection %d: %w", index, err))
+			}
+		}()
+	}
+}
+
+// Close stops the server, and waits for the connections being replayed.
+func (s *Server) Close() {
+	_ = s.listener.Close()
+	s.wg.Wait()
+}
+
+// Wait closes the server, and returns the errors of the replays of all the connections.
+func (s *Server) Wait() error {
+	s.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return errors.Join(s.errs...)
+}
+
+// Received returns the bytes received on each connection, in the order they were accepted.
+func (s *Server) Received() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	received := make([][]byte, len(s.received))
+	copy(received, s.received)
+
+	return received
+}
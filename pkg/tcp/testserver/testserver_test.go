@@ -0,0 +1,66 @@
+package testserver
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	server := Start(t,
+		Expect("ping"),
+		ExpectEOF(),
+		Sleep(10*time.Millisecond),
+		Send("pong"),
+		CloseWrite(),
+	)
+
+	conn, err := net.Dial("tcp", server.Addr())
+	require.NoError(t, err)
+
+	received, err := Replay(conn,
+		Send("ping"),
+		CloseWrite(),
+		Expect("pong"),
+		ExpectEOF(),
+		Close(),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "pong", string(received))
+
+	require.NoError(t, server.Wait())
+	assert.Equal(t, [][]byte{[]byte("ping")}, server.Received())
+}
+
+func TestServer_unexpected(t *testing.T) {
+	server := Start(t, Expect("ping"), Send("pong"))
+
+	conn, err := net.Dial("tcp", server.Addr())
+	require.NoError(t, err)
+
+	_, err = Replay(conn, Send("pong"), CloseWrite(), ExpectEOF(), Close())
+	require.NoError(t, err)
+
+	err = server.Wait()
+	require.Error(t, err)
+	assert.Equal(t, `connection 0: step 0 Expect("ping"): unexpected bytes "pong"`, err.Error())
+	assert.Equal(t, [][]byte{[]byte("pong")}, server.Received())
+}
+
+func TestReplay_timeout(t *testing.T) {
+	server := Start(t, Sleep(100*time.Millisecond), Send("late"))
+
+	conn, err := net.Dial("tcp", server.Addr())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_, err = Replay(conn, ExpectWithin("late", 10*time.Millisecond))
+	require.Error(t, err)
+
+	var netErr net.Error
+	require.ErrorAs(t, err, &netErr)
+	assert.True(t, netErr.Timeout())
+}
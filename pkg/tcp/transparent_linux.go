@@ -0,0 +1,38 @@
+//go:build linux
+// +build linux
+
+package tcp
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// transparentControl lets a socket be bound to a non-local address, such as the one of a client,
+// without reserving a port before the connection picks one, so that all the ports are usable for every client address.
+// It requires the CAP_NET_ADMIN capability.
+func transparentControl(network, _ string, c syscall.RawConn) error {
+	level, opt := unix.SOL_IP, unix.IP_TRANSPARENT
+	if network == "tcp6" {
+		level, opt = unix.SOL_IPV6, unix.IPV6_TRANSPARENT
+	}
+
+	var errOpt error
+	err := c.Control(func(fd uintptr) {
+		if errOpt = unix.SetsockoptInt(int(fd), level, opt, 1); errOpt != nil {
+			errOpt = os.NewSyscallError("setsockopt", errOpt)
+			return
+		}
+
+		if errOpt = unix.SetsockoptInt(int(fd), unix.SOL_IP, unix.IP_BIND_ADDRESS_NO_PORT, 1); errOpt != nil {
+			errOpt = os.NewSyscallError("setsockopt", errOpt)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return errOpt
+}
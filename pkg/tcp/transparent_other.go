@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package tcp
+
+import (
+	"errors"
+	"syscall"
+)
+
+// transparentControl fails, as binding a socket to a non-local address relies on IP_TRANSPARENT.
+func transparentControl(_, _ string, _ syscall.RawConn) error {
+	return errors.New("the transparent mode is only supported on Linux")
+}
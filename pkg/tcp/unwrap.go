@@ -0,0 +1,26 @@
+package tcp
+
+// Unwrapper is implemented by the connection wrappers which neither transform nor observe the bytes going through them,
+// and which can therefore be bypassed by the forwarding fast path of the proxy.
+type Unwrapper interface {
+	// Unwrap returns the wrapped connection,
+	// along with the bytes already read from it and not consumed yet, which the caller becomes responsible for.
+	Unwrap() (WriteCloser, []byte)
+}
+
+// unwrap bypasses the wrappers of conn implementing Unwrapper,
+// and returns the innermost connection reached, along with the bytes read ahead by the bypassed wrappers.
+func unwrap(conn WriteCloser) (WriteCloser, []byte) {
+	var buffered []byte
+	for {
+		u, ok := conn.(Unwrapper)
+		if !ok {
+			return conn, buffered
+		}
+
+		inner, b := u.Unwrap()
+		// The bytes read ahead by an outer wrapper were read from the inner connection before the ones it holds.
+		buffered = append(buffered, b...)
+		conn = inner
+	}
+}
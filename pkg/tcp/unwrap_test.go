@@ -0,0 +1,111 @@
+package tcp
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// peekedConn is a wrapper which can be bypassed, holding bytes already read from the wrapped connection.
+type peekedConn struct {
+	WriteCloser
+
+	peeked []byte
+}
+
+func (c *peekedConn) Unwrap() (WriteCloser, []byte) {
+	peeked := c.peeked
+	c.peeked = nil
+
+	return c.WriteCloser, peeked
+}
+
+// countingConn is a wrapper which cannot be bypassed, since it observes the bytes.
+type countingConn struct {
+	WriteCloser
+
+	read int
+}
+
+func (c *countingConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+	c.read += n
+	return n, err
+}
+
+func TestUnwrap(t *testing.T) {
+	raw := &fakeConn{}
+
+	conn, buffered := unwrap(&peekedConn{WriteCloser: &peekedConn{WriteCloser: raw, peeked: []byte("bar")}, peeked: []byte("foo")})
+	assert.Equal(t, raw, conn)
+	assert.Equal(t, "foobar", string(buffered))
+
+	counting := &countingConn{WriteCloser: raw}
+	conn, buffered = unwrap(&peekedConn{WriteCloser: counting, peeked: []byte("foo")})
+	assert.Equal(t, counting, conn)
+	assert.Equal(t, "foo", string(buffered))
+}
+
+func TestProxy_fastPath(t *testing.T) {
+	backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backendListener.Close() })
+
+	// The backend echoes the received bytes, once the client is done sending.
+	go func() {
+		conn, err := backendListener.Accept()
+		if err != nil {
+			return
+		}
+
+		data, err := io.ReadAll(conn)
+		if err != nil {
+			_ = conn.Close()
+			return
+		}
+
+		_, _ = conn.Write(data)
+		_ = conn.Close()
+	}()
+
+	proxy, err := NewProxy(backendListener.Addr().String(), 10*time.Millisecond, nil, ProxyOptions{FastPath: true})
+	require.NoError(t, err)
+
+	proxyListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = proxyListener.Close() })
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		conn, err := proxyListener.Accept()
+		if err != nil {
+			return
+		}
+
+		// The bytes peeked before the proxy gets the connection are forwarded first.
+		proxy.ServeTCP(&peekedConn{WriteCloser: conn.(*net.TCPConn), peeked: []byte("ping ")})
+	}()
+
+	conn, err := net.Dial("tcp", proxyListener.Addr().String())
+	require.NoError(t, err)
+
+	_, err = conn.Write([]byte("pong"))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.(*net.TCPConn).CloseWrite())
+
+	var buffer bytes.Buffer
+	_, err = io.Copy(&buffer, conn)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ping pong", buffer.String())
+
+	<-done
+}
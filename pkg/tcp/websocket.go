@@ -0,0 +1,139 @@
+package tcp
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// WebSocketHandler bridges the WebSocket connections to a TCP handler.
+// The data messages received from the clients are forwarded as a stream,
+// and the bytes sent back are delivered to the clients as binary messages.
+type WebSocketHandler struct {
+	next     Handler
+	upgrader websocket.Upgrader
+}
+
+// NewWebSocketHandler creates a new WebSocketHandler, forwarding the connections to next.
+// The connections are only accepted from the allowed origins, "*" allowing any origin,
+// or from the same origin as the host of the request when none is given.
+func NewWebSocketHandler(next Handler, allowedOrigins []string) *WebSocketHandler {
+	h := &WebSocketHandler{next: next}
+
+	if len(allowedOrigins) > 0 {
+		h.upgrader.CheckOrigin = func(req *http.Request) bool {
+			origin := req.Header.Get("Origin")
+			if origin == "" {
+				return true
+			}
+
+			for _, allowed := range allowedOrigins {
+				if allowed == "*" || strings.EqualFold(allowed, origin) {
+					return true
+				}
+			}
+
+			return false
+		}
+	}
+
+	return h
+}
+
+func (h *WebSocketHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(req.Context())
+
+	// On failure, the upgrader has already replied to the client.
+	conn, err := h.upgrader.Upgrade(rw, req, nil)
+	if err != nil {
+		logger.Debugf("Error while upgrading the WebSocket connection: %v", err)
+		return
+	}
+
+	// The deadlines set by the HTTP server on the connection would interrupt the bridge.
+	if err := conn.UnderlyingConn().SetDeadline(time.Time{}); err != nil {
+		logger.Debugf("Error while resetting the deadlines of the WebSocket connection: %v", err)
+	}
+
+	h.next.ServeTCP(&webSocketConn{Conn: conn})
+}
+
+// webSocketConn exposes a WebSocket connection as a stream.
+type webSocketConn struct {
+	*websocket.Conn
+
+	// reader is the reader of the message being read.
+	reader io.Reader
+
+	closeOnce sync.Once
+}
+
+// Read reads the data messages received from the client, one after the other.
+func (c *webSocketConn) Read(p []byte) (int, error) {
+	for {
+		if c.reader == nil {
+			_, reader, err := c.NextReader()
+			if err != nil {
+				var closeErr *websocket.CloseError
+				if errors.As(err, &closeErr) {
+					return 0, io.EOF
+				}
+
+				return 0, err
+			}
+
+			c.reader = reader
+		}
+
+		n, err := c.reader.Read(p)
+		if errors.Is(err, io.EOF) {
+			c.reader = nil
+			if n == 0 {
+				continue
+			}
+
+			err = nil
+		}
+
+		return n, err
+	}
+}
+
+// Write sends p to the client as a binary message.
+func (c *webSocketConn) Write(p []byte) (int, error) {
+	if err := c.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// CloseWrite sends a close message to the client.
+func (c *webSocketConn) CloseWrite() error {
+	var err error
+	c.closeOnce.Do(func() {
+		msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+		err = c.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+	})
+
+	if errors.Is(err, websocket.ErrCloseSent) {
+		return nil
+	}
+
+	return err
+}
+
+// SetDeadline sets the read and write deadlines of the connection.
+func (c *webSocketConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+
+	return c.SetWriteDeadline(t)
+}
@@ -0,0 +1,109 @@
+package tcp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSocketHandler(t *testing.T) {
+	echo := HandlerFunc(func(conn WriteCloser) {
+		defer conn.Close()
+
+		_, _ = io.Copy(conn, conn)
+		_ = conn.CloseWrite()
+	})
+
+	server := httptest.NewServer(NewWebSocketHandler(echo, nil))
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, []byte("ping")))
+
+	msgType, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, websocket.BinaryMessage, msgType)
+	assert.Equal(t, "ping", string(msg))
+
+	// The text messages are forwarded as well.
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("pong")))
+
+	msgType, msg, err = conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, websocket.BinaryMessage, msgType)
+	assert.Equal(t, "pong", string(msg))
+
+	// Closing the connection ends the stream, and the close is sent back.
+	require.NoError(t, conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")))
+
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), "unexpected error: %v", err)
+}
+
+func TestWebSocketHandler_allowedOrigins(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		allowedOrigins []string
+		origin         string
+		expected       bool
+	}{
+		{
+			desc:     "other origin by default",
+			origin:   "http://127.0.0.1",
+			expected: false,
+		},
+		{
+			desc:           "allowed origin",
+			allowedOrigins: []string{"https://example.com"},
+			origin:         "https://example.com",
+			expected:       true,
+		},
+		{
+			desc:           "not allowed origin",
+			allowedOrigins: []string{"https://example.com"},
+			origin:         "https://example.org",
+			expected:       false,
+		},
+		{
+			desc:           "any origin",
+			allowedOrigins: []string{"*"},
+			origin:         "https://example.org",
+			expected:       true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewWebSocketHandler(HandlerFunc(func(conn WriteCloser) {
+				_ = conn.Close()
+			}), test.allowedOrigins)
+
+			server := httptest.NewServer(handler)
+			t.Cleanup(server.Close)
+
+			header := http.Header{"Origin": []string{test.origin}}
+
+			conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), header)
+			if !test.expected {
+				require.Error(t, err)
+				assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+				return
+			}
+
+			require.NoError(t, err)
+			_ = conn.Close()
+		})
+	}
+}
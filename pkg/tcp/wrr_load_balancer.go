@@ -2,14 +2,38 @@ package tcp
 
 import (
 	"fmt"
+	"math"
 	"sync"
+	"time"
 
 	"github.com/traefik/traefik/v2/pkg/log"
 )
 
+// slowStartSteps is the number of steps of the ramp-up of a slow-start server.
+// All the weights are multiplied by it, so that a ramping server can get a fraction of the share of a static one.
+const slowStartSteps = 10
+
 type server struct {
 	Handler
 	weight int
+
+	// since and window define the ramp-up of a slow-start server.
+	since  time.Time
+	window time.Duration
+}
+
+// currentWeight returns the weight of the server at the given time.
+func (s server) currentWeight(now time.Time) int {
+	if s.window <= 0 {
+		return s.weight * slowStartSteps
+	}
+
+	elapsed := now.Sub(s.since)
+	if elapsed >= s.window {
+		return s.weight * slowStartSteps
+	}
+
+	return max(1, int(math.Ceil(float64(s.weight*slowStartSteps)*float64(elapsed)/float64(s.window))))
 }
 
 // WRRLoadBalancer is a naive RoundRobin load balancer for TCP services.
@@ -18,12 +42,14 @@ type WRRLoadBalancer struct {
 	lock          sync.Mutex
 	currentWeight int
 	index         int
+	now           func() time.Time
 }
 
 // NewWRRLoadBalancer creates a new WRRLoadBalancer.
 func NewWRRLoadBalancer() *WRRLoadBalancer {
 	return &WRRLoadBalancer{
 		index: -1,
+		now:   time.Now,
 	}
 }
 
@@ -60,23 +86,32 @@ func (b *WRRLoadBalancer) AddWeightServer(serverHandler Handler, weight *int) {
 	b.servers = append(b.servers, server{Handler: serverHandler, weight: w})
 }
 
-func (b *WRRLoadBalancer) maxWeight() int {
+// AddSlowStartServer appends a server to the existing list,
+// with a weight ramped up over the given window, from the given time.
+func (b *WRRLoadBalancer) AddSlowStartServer(serverHandler Handler, since time.Time, window time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.servers = append(b.servers, server{Handler: serverHandler, weight: 1, since: since, window: window})
+}
+
+func maxWeight(weights []int) int {
 	max := -1
-	for _, s := range b.servers {
-		if s.weight > max {
-			max = s.weight
+	for _, w := range weights {
+		if w > max {
+			max = w
 		}
 	}
 	return max
 }
 
-func (b *WRRLoadBalancer) weightGcd() int {
+func weightGcd(weights []int) int {
 	divisor := -1
-	for _, s := range b.servers {
+	for _, w := range weights {
 		if divisor == -1 {
-			divisor = s.weight
+			divisor = w
 		} else {
-			divisor = gcd(divisor, s.weight)
+			divisor = gcd(divisor, w)
 		}
 	}
 	return divisor
@@ -98,14 +133,20 @@ func (b *WRRLoadBalancer) next() (Handler, error) {
 	// it calculates the GCD  and subtracts it on every iteration, what interleaves servers
 	// and allows us not to build an iterator every time we readjust weights
 
+	now := b.now()
+	weights := make([]int, len(b.servers))
+	for i, s := range b.servers {
+		weights[i] = s.currentWeight(now)
+	}
+
 	// Maximum weight across all enabled servers
-	max := b.maxWeight()
+	max := maxWeight(weights)
 	if max == 0 {
 		return nil, fmt.Errorf("all servers have 0 weight")
 	}
 
 	// GCD across all enabled servers
-	gcd := b.weightGcd()
+	gcd := weightGcd(weights)
 
 	for {
 		b.index = (b.index + 1) % len(b.servers)
@@ -115,9 +156,8 @@ func (b *WRRLoadBalancer) next() (Handler, error) {
 				b.currentWeight = max
 			}
 		}
-		srv := b.servers[b.index]
-		if srv.weight >= b.currentWeight {
-			return srv, nil
+		if weights[b.index] >= b.currentWeight {
+			return b.servers[b.index], nil
 		}
 	}
 }
@@ -144,3 +144,36 @@ func TestLoadBalancing(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadBalancing_slowStart(t *testing.T) {
+	now := time.Now()
+
+	balancer := NewWRRLoadBalancer()
+	balancer.now = func() time.Time { return now }
+
+	balancer.AddServer(HandlerFunc(func(conn WriteCloser) {
+		_, err := conn.Write([]byte("h1"))
+		require.NoError(t, err)
+	}))
+	balancer.AddSlowStartServer(HandlerFunc(func(conn WriteCloser) {
+		_, err := conn.Write([]byte("h2"))
+		require.NoError(t, err)
+	}), now.Add(-2*time.Second), 10*time.Second)
+
+	conn := &fakeConn{writeCall: make(map[string]int)}
+	for i := 0; i < 60; i++ {
+		balancer.ServeTCP(conn)
+	}
+
+	// h2 is at 20% of its ramp-up.
+	assert.Equal(t, map[string]int{"h1": 50, "h2": 10}, conn.writeCall)
+
+	now = now.Add(8 * time.Second)
+
+	conn = &fakeConn{writeCall: make(map[string]int)}
+	for i := 0; i < 60; i++ {
+		balancer.ServeTCP(conn)
+	}
+
+	assert.Equal(t, map[string]int{"h1": 30, "h2": 30}, conn.writeCall)
+}
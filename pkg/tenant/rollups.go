@@ -0,0 +1,222 @@
+package tenant
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+const (
+	protoHTTP = "http"
+	protoTCP  = "tcp"
+)
+
+// windowSize is the number of one second buckets used to compute the request and error rates.
+const windowSize = 60
+
+// Summary is the aggregated view of the traffic handled on behalf of a tenant.
+type Summary struct {
+	Name              string  `json:"name"`
+	Requests          uint64  `json:"requests"`
+	ServerErrors      uint64  `json:"serverErrors"`
+	RequestRate       float64 `json:"requestRate"`
+	ErrorRate         float64 `json:"errorRate"`
+	RequestBytes      int64   `json:"requestBytes"`
+	ResponseBytes     int64   `json:"responseBytes"`
+	ActiveConnections int64   `json:"activeConnections"`
+	TotalConnections  uint64  `json:"totalConnections"`
+}
+
+type bucket struct {
+	second   int64
+	requests uint64
+	errors   uint64
+}
+
+type stats struct {
+	requests          uint64
+	serverErrors      uint64
+	requestBytes      int64
+	responseBytes     int64
+	activeConnections int64
+	totalConnections  uint64
+	window            [windowSize]bucket
+}
+
+// Rollups aggregates per tenant traffic statistics,
+// and forwards them to the metrics registry.
+type Rollups struct {
+	registry metrics.Registry
+
+	mu      sync.Mutex
+	tenants map[string]*stats
+
+	// now is used to shift the clock in tests.
+	now func() time.Time
+}
+
+// NewRollups creates a new Rollups.
+func NewRollups(registry metrics.Registry) *Rollups {
+	if registry == nil {
+		registry = metrics.NewVoidRegistry()
+	}
+
+	return &Rollups{
+		registry: registry,
+		tenants:  make(map[string]*stats),
+		now:      time.Now,
+	}
+}
+
+// ObserveRequest records a completed HTTP request for the given tenant.
+func (r *Rollups) ObserveRequest(tenant string, code int, requestBytes, responseBytes int64) {
+	if r == nil || tenant == "" {
+		return
+	}
+
+	serverError := code >= 500
+
+	r.mu.Lock()
+	s := r.get(tenant)
+	s.requests++
+	if serverError {
+		s.serverErrors++
+	}
+	s.requestBytes += requestBytes
+	s.responseBytes += responseBytes
+
+	b := s.bucket(r.now().Unix())
+	b.requests++
+	if serverError {
+		b.errors++
+	}
+	r.mu.Unlock()
+
+	r.registry.TenantReqsCounter().With("tenant", tenant, "code", strconv.Itoa(code)).Add(1)
+	r.registry.TenantReqsBytesCounter().With("tenant", tenant, "protocol", protoHTTP).Add(float64(requestBytes))
+	r.registry.TenantRespsBytesCounter().With("tenant", tenant, "protocol", protoHTTP).Add(float64(responseBytes))
+}
+
+// ConnectionOpened records a new TCP connection for the given tenant.
+func (r *Rollups) ConnectionOpened(tenant string) {
+	if r == nil || tenant == "" {
+		return
+	}
+
+	r.mu.Lock()
+	s := r.get(tenant)
+	s.activeConnections++
+	s.totalConnections++
+	r.mu.Unlock()
+
+	r.registry.TenantOpenConnsGauge().With("tenant", tenant, "protocol", protoTCP).Add(1)
+}
+
+// ConnectionClosed records the end of a TCP connection for the given tenant,
+// along with the number of bytes received from and sent to the client.
+func (r *Rollups) ConnectionClosed(tenant string, requestBytes, responseBytes int64) {
+	if r == nil || tenant == "" {
+		return
+	}
+
+	r.mu.Lock()
+	s := r.get(tenant)
+	s.activeConnections--
+	s.requestBytes += requestBytes
+	s.responseBytes += responseBytes
+	r.mu.Unlock()
+
+	r.registry.TenantOpenConnsGauge().With("tenant", tenant, "protocol", protoTCP).Add(-1)
+	r.registry.TenantReqsBytesCounter().With("tenant", tenant, "protocol", protoTCP).Add(float64(requestBytes))
+	r.registry.TenantRespsBytesCounter().With("tenant", tenant, "protocol", protoTCP).Add(float64(responseBytes))
+}
+
+// Summary returns the summary of the given tenant.
+func (r *Rollups) Summary(tenant string) (Summary, bool) {
+	if r == nil {
+		return Summary{}, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.tenants[tenant]
+	if !ok {
+		return Summary{}, false
+	}
+
+	return s.summary(tenant, r.now().Unix()), true
+}
+
+// Summaries returns the summaries of all the known tenants, sorted by name.
+func (r *Rollups) Summaries() []Summary {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now().Unix()
+
+	summaries := make([]Summary, 0, len(r.tenants))
+	for name, s := range r.tenants {
+		summaries = append(summaries, s.summary(name, now))
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+
+	return summaries
+}
+
+// get must be called with the lock held.
+func (r *Rollups) get(tenant string) *stats {
+	s, ok := r.tenants[tenant]
+	if !ok {
+		s = &stats{}
+		r.tenants[tenant] = s
+	}
+
+	return s
+}
+
+func (s *stats) bucket(second int64) *bucket {
+	b := &s.window[second%windowSize]
+	if b.second != second {
+		*b = bucket{second: second}
+	}
+
+	return b
+}
+
+func (s *stats) summary(name string, now int64) Summary {
+	var requests, errors uint64
+	for _, b := range s.window {
+		if now-b.second < windowSize {
+			requests += b.requests
+			errors += b.errors
+		}
+	}
+
+	summary := Summary{
+		Name:              name,
+		Requests:          s.requests,
+		ServerErrors:      s.serverErrors,
+		RequestRate:       float64(requests) / windowSize,
+		RequestBytes:      s.requestBytes,
+		ResponseBytes:     s.responseBytes,
+		ActiveConnections: s.activeConnections,
+		TotalConnections:  s.totalConnections,
+	}
+
+	if requests > 0 {
+		summary.ErrorRate = float64(errors) / float64(requests)
+	}
+
+	return summary
+}
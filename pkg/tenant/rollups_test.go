@@ -0,0 +1,117 @@
+package tenant
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollups_ObserveRequest(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	rollups := NewRollups(nil)
+	rollups.now = func() time.Time { return now }
+
+	rollups.ObserveRequest("acme", http.StatusOK, 10, 100)
+	rollups.ObserveRequest("acme", http.StatusBadGateway, 20, 200)
+	rollups.ObserveRequest("acme", http.StatusNotFound, 30, 300)
+	rollups.ObserveRequest("", http.StatusOK, 1, 1)
+
+	summary, ok := rollups.Summary("acme")
+	require.True(t, ok)
+
+	assert.Equal(t, Summary{
+		Name:          "acme",
+		Requests:      3,
+		ServerErrors:  1,
+		RequestRate:   3.0 / windowSize,
+		ErrorRate:     1.0 / 3.0,
+		RequestBytes:  60,
+		ResponseBytes: 600,
+	}, summary)
+
+	_, ok = rollups.Summary("")
+	assert.False(t, ok)
+}
+
+func TestRollups_slidingWindow(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	rollups := NewRollups(nil)
+	rollups.now = func() time.Time { return now }
+
+	rollups.ObserveRequest("acme", http.StatusInternalServerError, 0, 0)
+
+	now = now.Add(30 * time.Second)
+	rollups.ObserveRequest("acme", http.StatusOK, 0, 0)
+
+	summary, ok := rollups.Summary("acme")
+	require.True(t, ok)
+	assert.Equal(t, 2.0/windowSize, summary.RequestRate)
+	assert.Equal(t, 0.5, summary.ErrorRate)
+
+	// The first request is now out of the window.
+	now = now.Add(45 * time.Second)
+
+	summary, ok = rollups.Summary("acme")
+	require.True(t, ok)
+	assert.Equal(t, 1.0/windowSize, summary.RequestRate)
+	assert.Equal(t, 0.0, summary.ErrorRate)
+	assert.Equal(t, uint64(2), summary.Requests)
+	assert.Equal(t, uint64(1), summary.ServerErrors)
+
+	// The bucket of the first request is reused.
+	now = now.Add(45 * time.Second)
+	rollups.ObserveRequest("acme", http.StatusOK, 0, 0)
+
+	summary, ok = rollups.Summary("acme")
+	require.True(t, ok)
+	assert.Equal(t, 1.0/windowSize, summary.RequestRate)
+	assert.Equal(t, uint64(3), summary.Requests)
+}
+
+func TestRollups_Connections(t *testing.T) {
+	rollups := NewRollups(nil)
+
+	rollups.ConnectionOpened("acme")
+	rollups.ConnectionOpened("acme")
+	rollups.ConnectionClosed("acme", 5, 50)
+
+	summary, ok := rollups.Summary("acme")
+	require.True(t, ok)
+
+	assert.Equal(t, Summary{
+		Name:              "acme",
+		RequestBytes:      5,
+		ResponseBytes:     50,
+		ActiveConnections: 1,
+		TotalConnections:  2,
+	}, summary)
+}
+
+func TestRollups_Summaries(t *testing.T) {
+	rollups := NewRollups(nil)
+
+	rollups.ObserveRequest("foo", http.StatusOK, 0, 0)
+	rollups.ConnectionOpened("bar")
+
+	summaries := rollups.Summaries()
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "bar", summaries[0].Name)
+	assert.Equal(t, "foo", summaries[1].Name)
+}
+
+func TestRollups_nil(t *testing.T) {
+	var rollups *Rollups
+
+	rollups.ObserveRequest("acme", http.StatusOK, 0, 0)
+	rollups.ConnectionOpened("acme")
+	rollups.ConnectionClosed("acme", 0, 0)
+
+	_, ok := rollups.Summary("acme")
+	assert.False(t, ok)
+	assert.Nil(t, rollups.Summaries())
+}
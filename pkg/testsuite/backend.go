@@ -0,0 +1,105 @@
+package testsuite
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// Backend is a stub backend, answering either HTTP requests or TCP connections.
+type Backend struct {
+	HTTP *HTTPBackend `yaml:"http"`
+	TCP  *TCPBackend  `yaml:"tcp"`
+}
+
+// HTTPBackend is a stub HTTP backend, answering all the requests with the same response.
+// It also adds the X-Stub-Backend and X-Stub-Path headers to the response,
+// to assert on the backend, and the path, a request was forwarded to.
+type HTTPBackend struct {
+	Status  int               `yaml:"status"`
+	Headers map[string]string `yaml:"headers"`
+	Body    string            `yaml:"body"`
+}
+
+// TCPBackend is a stub TCP backend.
+// It writes Reply once it has read the first bytes of a connection, then closes it,
+// or, without Reply, echoes all the bytes it reads.
+type TCPBackend struct {
+	Reply string `yaml:"reply"`
+}
+
+type runningBackend struct {
+	addr  string
+	close func()
+}
+
+func startBackend(name string, backend Backend) (*runningBackend, error) {
+	if (backend.HTTP == nil) == (backend.TCP == nil) {
+		return nil, errors.New("exactly one of http or tcp must be set")
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	if backend.HTTP != nil {
+		server := &http.Server{Handler: httpStub(name, *backend.HTTP)}
+		go func() {
+			if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.WithoutContext().Errorf("Stub backend %s: %v", name, err)
+			}
+		}()
+
+		return &runningBackend{addr: listener.Addr().String(), close: func() { _ = server.Close() }}, nil
+	}
+
+	go serveTCPStub(listener, *backend.TCP)
+
+	return &runningBackend{addr: listener.Addr().String(), close: func() { _ = listener.Close() }}, nil
+}
+
+func httpStub(name string, backend HTTPBackend) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		for key, value := range backend.Headers {
+			rw.Header().Set(key, value)
+		}
+		rw.Header().Set("X-Stub-Backend", name)
+		rw.Header().Set("X-Stub-Path", req.URL.Path)
+
+		status := backend.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		rw.WriteHeader(status)
+
+		_, _ = io.WriteString(rw, backend.Body)
+	})
+}
+
+func serveTCPStub(listener net.Listener, backend TCPBackend) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer func() { _ = conn.Close() }()
+
+			if backend.Reply == "" {
+				_, _ = io.Copy(conn, conn)
+				return
+			}
+
+			buf := make([]byte, 1024)
+			if _, err := conn.Read(buf); err != nil {
+				return
+			}
+			_, _ = io.WriteString(conn, backend.Reply)
+		}()
+	}
+}
@@ -0,0 +1,158 @@
+package testsuite
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const testTimeout = 10 * time.Second
+
+// Result is the outcome of a test.
+type Result struct {
+	Name string
+	Err  error
+}
+
+// Run runs the tests of the suite against its entry points.
+func (s *Suite) Run(ctx context.Context) []Result {
+	var results []Result
+	for _, test := range s.Tests {
+		address := s.EntryPoints[test.EntryPoint].Address
+		if i := strings.Index(address, "/"); i >= 0 {
+			address = address[:i]
+		}
+
+		var err error
+		if test.HTTP != nil {
+			err = runHTTP(ctx, address, *test.HTTP, test.Expect)
+		} else {
+			err = runTCP(ctx, address, *test.TCP, test.Expect)
+		}
+
+		results = append(results, Result{Name: test.Name, Err: err})
+	}
+
+	return results
+}
+
+func runHTTP(ctx context.Context, address string, request HTTPRequest, expect Expect) error {
+	method := request.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, testTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, "http://"+dialAddress(address)+request.Path, strings.NewReader(request.Body))
+	if err != nil {
+		return err
+	}
+
+	req.Host = request.Host
+	for key, value := range request.Headers {
+		req.Header.Set(key, value)
+	}
+
+	client := &http.Client{
+		// The redirections are asserted on, not followed.
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var body io.Reader = resp.Body
+	// The transport only decompresses the responses when it asked for the compression itself.
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return fmt.Errorf("invalid gzip response body: %w", err)
+		}
+		body = gzipReader
+	}
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	if expect.Status != 0 && resp.StatusCode != expect.Status {
+		failures = append(failures, fmt.Sprintf("status: expected %d, got %d", expect.Status, resp.StatusCode))
+	}
+
+	for key, value := range expect.Headers {
+		if got := resp.Header.Get(key); got != value {
+			failures = append(failures, fmt.Sprintf("header %s: expected %q, got %q", key, value, got))
+		}
+	}
+
+	if expect.Body != "" && string(content) != expect.Body {
+		failures = append(failures, fmt.Sprintf("body: expected %q, got %q", expect.Body, content))
+	}
+
+	return failuresError(failures)
+}
+
+func runTCP(ctx context.Context, address string, exchange TCPExchange, expect Expect) error {
+	ctx, cancel := context.WithTimeout(ctx, testTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", dialAddress(address))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if _, err = io.WriteString(conn, exchange.Send); err != nil {
+		return err
+	}
+
+	if err = conn.(*net.TCPConn).CloseWrite(); err != nil {
+		return err
+	}
+
+	received, err := io.ReadAll(conn)
+	if err != nil {
+		return err
+	}
+
+	if expect.Receive != "" && string(received) != expect.Receive {
+		return failuresError([]string{fmt.Sprintf("received: expected %q, got %q", expect.Receive, received)})
+	}
+
+	return nil
+}
+
+// dialAddress returns the address to dial to reach an entry point listening on the given address.
+func dialAddress(address string) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host != "" {
+		return address
+	}
+
+	return net.JoinHostPort("127.0.0.1", port)
+}
+
+func failuresError(failures []string) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%s", strings.Join(failures, ", "))
+}
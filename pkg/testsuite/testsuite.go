@@ -0,0 +1,197 @@
+package testsuite
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"gopkg.in/yaml.v3"
+)
+
+// Suite is a declarative test suite of a routing configuration.
+// Its entry points and configuration are served by an in-process Traefik,
+// in front of stub backends, and its tests assert the outcome of requests sent to the entry points.
+type Suite struct {
+	EntryPoints   map[string]EntryPoint  `yaml:"entryPoints"`
+	Backends      map[string]Backend     `yaml:"backends"`
+	Configuration map[string]interface{} `yaml:"configuration"`
+	Tests         []Test                 `yaml:"tests"`
+
+	running map[string]*runningBackend
+}
+
+// EntryPoint is an entry point of the in-process Traefik.
+type EntryPoint struct {
+	// Address is the address of the entry point, with its protocol, as in the static configuration.
+	Address string `yaml:"address"`
+}
+
+// Test is a request sent to an entry point, and its expected outcome.
+type Test struct {
+	Name       string       `yaml:"name"`
+	EntryPoint string       `yaml:"entryPoint"`
+	HTTP       *HTTPRequest `yaml:"http"`
+	TCP        *TCPExchange `yaml:"tcp"`
+	Expect     Expect       `yaml:"expect"`
+}
+
+// HTTPRequest is an HTTP request sent to an entry point.
+type HTTPRequest struct {
+	Method  string            `yaml:"method"`
+	Path    string            `yaml:"path"`
+	Host    string            `yaml:"host"`
+	Headers map[string]string `yaml:"headers"`
+	Body    string            `yaml:"body"`
+}
+
+// TCPExchange is a TCP byte exchange with an entry point:
+// the bytes are sent, then the write side of the connection is closed, and all the bytes received are read.
+type TCPExchange struct {
+	Send string `yaml:"send"`
+}
+
+// Expect is the expected outcome of a test.
+// The zero values are not checked.
+type Expect struct {
+	Status  int               `yaml:"status"`
+	Headers map[string]string `yaml:"headers"`
+	Body    string            `yaml:"body"`
+	Receive string            `yaml:"receive"`
+}
+
+// Load reads the suite at the given path, starts its backends,
+// and renders its configuration with the addresses of the backends (`{{ backend "name" }}`).
+func Load(path string) (*Suite, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// The backends are declared in the suite itself,
+	// so a first pass reads them, before rendering the suite with their actual addresses.
+	var declared Suite
+	if err = parse(content, &declared, func(string) string { return "" }); err != nil {
+		return nil, err
+	}
+
+	running := make(map[string]*runningBackend)
+	closeAll := func() {
+		for _, backend := range running {
+			backend.close()
+		}
+	}
+
+	for name, backend := range declared.Backends {
+		running[name], err = startBackend(name, backend)
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("backend %s: %w", name, err)
+		}
+	}
+
+	suite := &Suite{running: running}
+	err = parse(content, suite, func(name string) string {
+		if backend, ok := running[name]; ok {
+			return backend.addr
+		}
+		return ""
+	})
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+
+	if err = suite.validate(); err != nil {
+		closeAll()
+		return nil, err
+	}
+
+	return suite, nil
+}
+
+func parse(content []byte, suite *Suite, backend func(name string) string) error {
+	tmpl, err := template.New("suite").Funcs(template.FuncMap{"backend": backend}).Parse(string(content))
+	if err != nil {
+		return fmt.Errorf("invalid suite template: %w", err)
+	}
+
+	var rendered bytes.Buffer
+	if err = tmpl.Execute(&rendered, nil); err != nil {
+		return fmt.Errorf("invalid suite template: %w", err)
+	}
+
+	if err = yaml.Unmarshal(rendered.Bytes(), suite); err != nil {
+		return fmt.Errorf("invalid suite: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Suite) validate() error {
+	for i, test := range s.Tests {
+		if test.Name == "" {
+			s.Tests[i].Name = fmt.Sprintf("test %d", i)
+		}
+
+		if _, ok := s.EntryPoints[test.EntryPoint]; !ok {
+			return fmt.Errorf("%s: unknown entry point %q", s.Tests[i].Name, test.EntryPoint)
+		}
+
+		if (test.HTTP == nil) == (test.TCP == nil) {
+			return fmt.Errorf("%s: exactly one of http or tcp must be set", s.Tests[i].Name)
+		}
+	}
+
+	return nil
+}
+
+// DynamicConfiguration returns the dynamic configuration of the suite, in the file provider YAML format.
+func (s *Suite) DynamicConfiguration() ([]byte, error) {
+	return yaml.Marshal(s.Configuration)
+}
+
+// Close stops the backends of the suite.
+func (s *Suite) Close() {
+	for _, backend := range s.running {
+		backend.close()
+	}
+}
+
+// Ready reports whether the given configuration, applied by the in-process Traefik,
+// includes both the internal configuration, and the configuration of the suite, served by the file provider.
+func Ready(conf dynamic.Configuration) bool {
+	if conf.HTTP == nil || conf.HTTP.ServersTransports["default@internal"] == nil {
+		return false
+	}
+
+	fromFile := func(name string) bool {
+		return strings.HasSuffix(name, "@file")
+	}
+
+	for name := range conf.HTTP.Services {
+		if fromFile(name) {
+			return true
+		}
+	}
+
+	if conf.TCP != nil {
+		for name := range conf.TCP.Services {
+			if fromFile(name) {
+				return true
+			}
+		}
+	}
+
+	if conf.UDP != nil {
+		for name := range conf.UDP.Services {
+			if fromFile(name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
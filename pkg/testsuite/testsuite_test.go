@@ -0,0 +1,58 @@
+package testsuite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestSuite(t *testing.T) {
+	suite, err := Load("./fixtures/suite.yml")
+	require.NoError(t, err)
+	t.Cleanup(suite.Close)
+
+	content, err := suite.DynamicConfiguration()
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "url: http://"+suite.running["whoami"].addr)
+
+	results := suite.Run(context.Background())
+	require.Len(t, results, 5)
+
+	assert.Equal(t, "http", results[0].Name)
+	assert.NoError(t, results[0].Err)
+
+	assert.Equal(t, "http mismatch", results[1].Name)
+	require.Error(t, results[1].Err)
+	assert.Equal(t, `status: expected 200, got 201, body: expected "bye", got "hello"`, results[1].Err.Error())
+
+	assert.Equal(t, "tcp echo", results[2].Name)
+	assert.NoError(t, results[2].Err)
+
+	assert.Equal(t, "tcp reply", results[3].Name)
+	assert.NoError(t, results[3].Err)
+
+	assert.Equal(t, "tcp any reply", results[4].Name)
+	assert.NoError(t, results[4].Err)
+}
+
+func TestReady(t *testing.T) {
+	internal := func() *dynamic.HTTPConfiguration {
+		return &dynamic.HTTPConfiguration{
+			Services:          map[string]*dynamic.Service{"api@internal": {}},
+			ServersTransports: map[string]*dynamic.ServersTransport{"default@internal": {}},
+		}
+	}
+
+	assert.False(t, Ready(dynamic.Configuration{}))
+	assert.False(t, Ready(dynamic.Configuration{HTTP: internal()}))
+	assert.False(t, Ready(dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{Services: map[string]*dynamic.Service{"whoami@file": {}}},
+	}))
+	assert.True(t, Ready(dynamic.Configuration{
+		HTTP: internal(),
+		TCP:  &dynamic.TCPConfiguration{Services: map[string]*dynamic.TCPService{"echo@file": {}}},
+	}))
+}
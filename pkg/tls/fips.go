@@ -0,0 +1,113 @@
+package tls
+
+import (
+	"crypto/tls"
+	"fmt"
+	"reflect"
+)
+
+// FIPS-approved TLS parameters, as allowed by crypto/tls/fipsonly:
+// TLS 1.2 and 1.3, the AES-GCM cipher suites with an ECDHE key exchange, and the NIST P curves.
+// The TLS 1.3 cipher suites are not configurable, the Go TLS stack restricting them to AES-GCM in a FIPS build.
+var (
+	fipsVersions = []string{`VersionTLS12`, `VersionTLS13`}
+
+	fipsCipherSuites = []string{
+		`TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256`,
+		`TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384`,
+		`TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256`,
+		`TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384`,
+	}
+
+	fipsCurves = []string{`CurveP256`, `CurveP384`, `CurveP521`}
+)
+
+// fipsOptions returns the given TLS options restricted to the FIPS-approved parameters,
+// along with the descriptions of the non-compliant options which were gated.
+// The options left to their default values are restricted without being reported as gated.
+func fipsOptions(option Options) (Options, []string) {
+	var gated []string
+
+	if !contains(fipsVersions, option.MinVersion) {
+		if option.MinVersion != "" {
+			gated = append(gated, fmt.Sprintf("minVersion %s", option.MinVersion))
+		}
+		option.MinVersion = fipsVersions[0]
+	}
+
+	// The maximum version defaults to the latest one, which is approved.
+	if option.MaxVersion != "" && !contains(fipsVersions, option.MaxVersion) {
+		gated = append(gated, fmt.Sprintf("maxVersion %s", option.MaxVersion))
+		option.MaxVersion = ""
+	}
+
+	if option.CipherSuites == nil || reflect.DeepEqual(option.CipherSuites, DefaultTLSOptions.CipherSuites) {
+		option.CipherSuites = fipsCipherSuites
+	} else {
+		var cipherSuites []string
+		for _, cipherSuite := range option.CipherSuites {
+			if !contains(fipsCipherSuites, cipherSuite) {
+				gated = append(gated, fmt.Sprintf("cipher suite %s", cipherSuite))
+				continue
+			}
+			cipherSuites = append(cipherSuites, cipherSuite)
+		}
+
+		if len(cipherSuites) == 0 {
+			cipherSuites = fipsCipherSuites
+		}
+		option.CipherSuites = cipherSuites
+	}
+
+	if option.CurvePreferences == nil {
+		option.CurvePreferences = fipsCurves
+	} else {
+		var curves []string
+		for _, curve := range option.CurvePreferences {
+			curveID, ok := CurveIDs[curve]
+			if !ok || curveID == tls.X25519 {
+				gated = append(gated, fmt.Sprintf("curve %s", curve))
+				continue
+			}
+			curves = append(curves, curve)
+		}
+
+		if len(curves) == 0 {
+			curves = fipsCurves
+		}
+		option.CurvePreferences = curves
+	}
+
+	return option, gated
+}
+
+// RestrictToFIPS restricts the given client TLS configuration to the FIPS-approved parameters.
+// A minimum version above TLS 1.2 is kept.
+func RestrictToFIPS(conf *tls.Config) {
+	if conf.MinVersion < tls.VersionTLS12 {
+		conf.MinVersion = tls.VersionTLS12
+	}
+
+	if conf.MaxVersion != 0 && conf.MaxVersion < tls.VersionTLS12 {
+		conf.MaxVersion = 0
+	}
+
+	conf.CipherSuites = make([]uint16, 0, len(fipsCipherSuites))
+	for _, cipherSuite := range fipsCipherSuites {
+		conf.CipherSuites = append(conf.CipherSuites, CipherSuites[cipherSuite])
+	}
+
+	conf.CurvePreferences = make([]tls.CurveID, 0, len(fipsCurves))
+	for _, curve := range fipsCurves {
+		conf.CurvePreferences = append(conf.CurvePreferences, CurveIDs[curve])
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
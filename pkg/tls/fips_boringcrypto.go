@@ -0,0 +1,11 @@
+//go:build boringcrypto
+// +build boringcrypto
+
+package tls
+
+// crypto/tls/fipsonly restricts all the TLS configurations of the process to the FIPS-approved parameters.
+import _ "crypto/tls/fipsonly"
+
+// FIPSBuild reports whether Traefik is built with the FIPS 140 validated BoringCrypto module (GOEXPERIMENT=boringcrypto),
+// in which case the FIPS mode is always enabled.
+const FIPSBuild = true
@@ -0,0 +1,8 @@
+//go:build !boringcrypto
+// +build !boringcrypto
+
+package tls
+
+// FIPSBuild reports whether Traefik is built with the FIPS 140 validated BoringCrypto module (GOEXPERIMENT=boringcrypto),
+// in which case the FIPS mode is always enabled.
+const FIPSBuild = false
@@ -0,0 +1,135 @@
+package tls
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_fipsOptions(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		option        Options
+		expected      Options
+		expectedGated []string
+	}{
+		{
+			desc:   "default options",
+			option: DefaultTLSOptions,
+			expected: Options{
+				ALPNProtocols:    DefaultTLSOptions.ALPNProtocols,
+				MinVersion:       "VersionTLS12",
+				CipherSuites:     fipsCipherSuites,
+				CurvePreferences: fipsCurves,
+			},
+		},
+		{
+			desc: "non-compliant options",
+			option: Options{
+				MinVersion:       "VersionTLS10",
+				MaxVersion:       "VersionTLS11",
+				CipherSuites:     []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305"},
+				CurvePreferences: []string{"X25519", "secp384r1"},
+				SniStrict:        true,
+			},
+			expected: Options{
+				MinVersion:       "VersionTLS12",
+				CipherSuites:     []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+				CurvePreferences: []string{"secp384r1"},
+				SniStrict:        true,
+			},
+			expectedGated: []string{
+				"minVersion VersionTLS10",
+				"maxVersion VersionTLS11",
+				"cipher suite TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305",
+				"curve X25519",
+			},
+		},
+		{
+			desc: "no compliant cipher suite",
+			option: Options{
+				CipherSuites: []string{"TLS_RSA_WITH_AES_128_CBC_SHA"},
+			},
+			expected: Options{
+				MinVersion:       "VersionTLS12",
+				CipherSuites:     fipsCipherSuites,
+				CurvePreferences: fipsCurves,
+			},
+			expectedGated: []string{"cipher suite TLS_RSA_WITH_AES_128_CBC_SHA"},
+		},
+		{
+			desc: "TLS 1.3 only",
+			option: Options{
+				MinVersion: "VersionTLS13",
+				MaxVersion: "VersionTLS13",
+			},
+			expected: Options{
+				MinVersion:       "VersionTLS13",
+				MaxVersion:       "VersionTLS13",
+				CipherSuites:     fipsCipherSuites,
+				CurvePreferences: fipsCurves,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			option, gated := fipsOptions(test.option)
+			assert.Equal(t, test.expected, option)
+			assert.Equal(t, test.expectedGated, gated)
+		})
+	}
+}
+
+func TestManager_Get_FIPS(t *testing.T) {
+	tlsManager := NewManager()
+	tlsManager.SetFIPS(true)
+
+	tlsManager.UpdateConfigs(context.Background(), nil, map[string]Options{
+		"foo": {
+			MinVersion:       "VersionTLS13",
+			CurvePreferences: []string{"X25519"},
+		},
+	}, nil)
+
+	config, err := tlsManager.Get("default", "foo")
+	require.NoError(t, err)
+
+	assert.Equal(t, uint16(tls.VersionTLS13), config.MinVersion)
+	assert.Equal(t, uint16(0), config.MaxVersion)
+	assert.Equal(t, []tls.CurveID{tls.CurveP256, tls.CurveP384, tls.CurveP521}, config.CurvePreferences)
+	assert.Equal(t, []uint16{
+		tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+		tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	}, config.CipherSuites)
+}
+
+func TestRestrictToFIPS(t *testing.T) {
+	config := &tls.Config{
+		MinVersion:       tls.VersionTLS10,
+		CurvePreferences: []tls.CurveID{tls.X25519},
+	}
+
+	RestrictToFIPS(config)
+
+	assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+	assert.Equal(t, uint16(0), config.MaxVersion)
+	assert.Len(t, config.CipherSuites, 4)
+	assert.Equal(t, []tls.CurveID{tls.CurveP256, tls.CurveP384, tls.CurveP521}, config.CurvePreferences)
+}
+
+func TestRestrictToFIPS_TLS13(t *testing.T) {
+	config := &tls.Config{MinVersion: tls.VersionTLS13}
+
+	RestrictToFIPS(config)
+
+	assert.Equal(t, uint16(tls.VersionTLS13), config.MinVersion)
+}
@@ -50,6 +50,9 @@ type Manager struct {
 	stores       map[string]*CertificateStore
 	configs      map[string]Options
 	certs        []*CertAndStores
+
+	// fips restricts the TLS options to the FIPS-approved parameters.
+	fips bool
 }
 
 // NewManager creates a new Manager.
@@ -62,6 +65,14 @@ func NewManager() *Manager {
 	}
 }
 
+// SetFIPS enables the FIPS mode, restricting the TLS options to the FIPS-approved parameters.
+func (m *Manager) SetFIPS(enabled bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.fips = enabled
+}
+
 // UpdateConfigs updates the TLS* configuration options.
 // It initializes the default TLS store, and the TLS store for the ACME challenges.
 func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, configs map[string]Options, certs []*CertAndStores) {
@@ -72,6 +83,14 @@ func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, co
 	m.storesConfig = stores
 	m.certs = certs
 
+	if m.fips {
+		for name, config := range configs {
+			if _, gated := fipsOptions(config); len(gated) > 0 {
+				log.FromContext(ctx).Warnf("FIPS mode: gated the non-compliant parameters of the TLS options %s: %s", name, strings.Join(gated, ", "))
+			}
+		}
+	}
+
 	if m.storesConfig == nil {
 		m.storesConfig = make(map[string]Store)
 	}
@@ -165,6 +184,11 @@ func (m *Manager) Get(storeName, configName string) (*tls.Config, error) {
 	}
 
 	sniStrict = config.SniStrict
+
+	if m.fips {
+		config, _ = fipsOptions(config)
+	}
+
 	tlsConfig, err := buildTLSConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("building TLS config: %w", err)
@@ -1,6 +1,10 @@
 package types
 
-import "github.com/traefik/paerser/types"
+import (
+	"time"
+
+	"github.com/traefik/paerser/types"
+)
 
 const (
 	// AccessLogKeep is the keep string value.
@@ -34,11 +38,12 @@ func (l *TraefikLog) SetDefaults() {
 
 // AccessLog holds the configuration settings for the access logger (middlewares/accesslog).
 type AccessLog struct {
-	FilePath      string            `description:"Access log file path. Stdout is used when omitted or empty." json:"filePath,omitempty" toml:"filePath,omitempty" yaml:"filePath,omitempty"`
-	Format        string            `description:"Access log format: json | common" json:"format,omitempty" toml:"format,omitempty" yaml:"format,omitempty" export:"true"`
-	Filters       *AccessLogFilters `description:"Access log filters, used to keep only specific access logs." json:"filters,omitempty" toml:"filters,omitempty" yaml:"filters,omitempty" export:"true"`
-	Fields        *AccessLogFields  `description:"AccessLogFields." json:"fields,omitempty" toml:"fields,omitempty" yaml:"fields,omitempty" export:"true"`
-	BufferingSize int64             `description:"Number of access log lines to process in a buffered way." json:"bufferingSize,omitempty" toml:"bufferingSize,omitempty" yaml:"bufferingSize,omitempty" export:"true"`
+	FilePath      string              `description:"Access log file path. Stdout is used when omitted or empty." json:"filePath,omitempty" toml:"filePath,omitempty" yaml:"filePath,omitempty"`
+	Format        string              `description:"Access log format: json | common" json:"format,omitempty" toml:"format,omitempty" yaml:"format,omitempty" export:"true"`
+	Filters       *AccessLogFilters   `description:"Access log filters, used to keep only specific access logs." json:"filters,omitempty" toml:"filters,omitempty" yaml:"filters,omitempty" export:"true"`
+	Fields        *AccessLogFields    `description:"AccessLogFields." json:"fields,omitempty" toml:"fields,omitempty" yaml:"fields,omitempty" export:"true"`
+	BufferingSize int64               `description:"Number of access log lines to process in a buffered way." json:"bufferingSize,omitempty" toml:"bufferingSize,omitempty" yaml:"bufferingSize,omitempty" export:"true"`
+	Integrity     *AccessLogIntegrity `description:"Access log integrity, chaining the access logs with hashes and signed checkpoints." json:"integrity,omitempty" toml:"integrity,omitempty" yaml:"integrity,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -57,6 +62,17 @@ type AccessLogFilters struct {
 	MinDuration   types.Duration `description:"Keep access logs when request took longer than the specified duration." json:"minDuration,omitempty" toml:"minDuration,omitempty" yaml:"minDuration,omitempty" export:"true"`
 }
 
+// AccessLogIntegrity holds the access log integrity configuration.
+type AccessLogIntegrity struct {
+	KeyFile            string         `description:"Path to the PEM encoded Ed25519 private key signing the checkpoints. No checkpoints are written when omitted." json:"keyFile,omitempty" toml:"keyFile,omitempty" yaml:"keyFile,omitempty"`
+	CheckpointInterval types.Duration `description:"Interval between the signed checkpoints." json:"checkpointInterval,omitempty" toml:"checkpointInterval,omitempty" yaml:"checkpointInterval,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (i *AccessLogIntegrity) SetDefaults() {
+	i.CheckpointInterval = types.Duration(time.Hour)
+}
+
 // FieldHeaders holds configuration for access log headers.
 type FieldHeaders struct {
 	DefaultMode string            `description:"Default mode for fields: keep | drop | redact" json:"defaultMode,omitempty" toml:"defaultMode,omitempty" yaml:"defaultMode,omitempty" export:"true"`
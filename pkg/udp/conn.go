@@ -2,6 +2,7 @@ package udp
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"sync"
@@ -30,10 +31,34 @@ type Listener struct {
 	// timeout defines how long to wait on an idle session,
 	// before releasing its related resources.
 	timeout time.Duration
+
+	// pktInfoConn, when set, reads the datagrams along with their destination address,
+	// in order to reply from it.
+	pktInfoConn pktInfoConn
+
+	// sourceAddr is the local address from which the datagrams are sent to the backends.
+	sourceAddr *net.UDPAddr
+}
+
+// ListenOptions holds the options of a listener controlling the addresses used to exchange the datagrams.
+type ListenOptions struct {
+	// SourceAddr is the local address from which the datagrams are sent to the backends.
+	// When nil, the address is chosen by the system.
+	SourceAddr *net.UDPAddr
+
+	// ReplyFromDestination sends the replies to a client from the address its datagrams were received on,
+	// rather than from the address chosen by the routing table,
+	// which matters for a listener bound to a wildcard address on a multi-homed host.
+	ReplyFromDestination bool
 }
 
 // Listen creates a new listener.
 func Listen(network string, laddr *net.UDPAddr, timeout time.Duration) (*Listener, error) {
+	return ListenWithOptions(network, laddr, timeout, ListenOptions{})
+}
+
+// ListenWithOptions creates a new listener with the given options.
+func ListenWithOptions(network string, laddr *net.UDPAddr, timeout time.Duration, opts ListenOptions) (*Listener, error) {
 	if timeout <= 0 {
 		return nil, errors.New("timeout should be greater than zero")
 	}
@@ -44,11 +69,20 @@ func Listen(network string, laddr *net.UDPAddr, timeout time.Duration) (*Listene
 	}
 
 	l := &Listener{
-		pConn:     conn,
-		acceptCh:  make(chan *Conn),
-		conns:     make(map[string]*Conn),
-		accepting: true,
-		timeout:   timeout,
+		pConn:      conn,
+		acceptCh:   make(chan *Conn),
+		conns:      make(map[string]*Conn),
+		accepting:  true,
+		timeout:    timeout,
+		sourceAddr: opts.SourceAddr,
+	}
+
+	if opts.ReplyFromDestination {
+		l.pktInfoConn, err = newPktInfoConn(conn)
+		if err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("enabling the reception of the destination addresses: %w", err)
+		}
 	}
 
 	go l.readLoop()
@@ -138,11 +172,11 @@ func (l *Listener) readLoop() {
 		// before c.msgs is emptied via Read()
 		buf := make([]byte, maxDatagramSize)
 
-		n, raddr, err := l.pConn.ReadFrom(buf)
+		n, dst, raddr, err := l.readFrom(buf)
 		if err != nil {
 			return
 		}
-		conn, err := l.getConn(raddr)
+		conn, err := l.getConn(raddr, dst)
 		if err != nil {
 			continue
 		}
@@ -155,9 +189,19 @@ func (l *Listener) readLoop() {
 	}
 }
 
+// readFrom reads a datagram, along with its destination address if the listener replies from it.
+func (l *Listener) readFrom(buf []byte) (int, net.IP, net.Addr, error) {
+	if l.pktInfoConn != nil {
+		return l.pktInfoConn.readFrom(buf)
+	}
+
+	n, raddr, err := l.pConn.ReadFrom(buf)
+	return n, nil, raddr, err
+}
+
 // getConn returns the ongoing session with raddr if it exists, or creates a new
-// one otherwise.
-func (l *Listener) getConn(raddr net.Addr) (*Conn, error) {
+// one otherwise, replying from dst if it is set.
+func (l *Listener) getConn(raddr net.Addr, dst net.IP) (*Conn, error) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -169,7 +213,7 @@ func (l *Listener) getConn(raddr net.Addr) (*Conn, error) {
 	if !l.accepting {
 		return nil, errClosedListener
 	}
-	conn = l.newConn(raddr)
+	conn = l.newConn(raddr, dst)
 	l.conns[raddr.String()] = conn
 	l.acceptCh <- conn
 	go conn.readLoop()
@@ -177,10 +221,11 @@ func (l *Listener) getConn(raddr net.Addr) (*Conn, error) {
 	return conn, nil
 }
 
-func (l *Listener) newConn(rAddr net.Addr) *Conn {
+func (l *Listener) newConn(rAddr net.Addr, lIP net.IP) *Conn {
 	return &Conn{
 		listener:  l,
 		rAddr:     rAddr,
+		lIP:       lIP,
 		receiveCh: make(chan []byte),
 		readCh:    make(chan []byte),
 		sizeCh:    make(chan int),
@@ -193,6 +238,8 @@ func (l *Listener) newConn(rAddr net.Addr) *Conn {
 type Conn struct {
 	listener *Listener
 	rAddr    net.Addr
+	// lIP is the address the replies are sent from, when the listener replies from the destination address.
+	lIP net.IP
 
 	receiveCh chan []byte // to receive the data from the listener's readLoop
 	readCh    chan []byte // to receive the buffer into which we should Read
@@ -205,6 +252,10 @@ type Conn struct {
 	timeout  time.Duration // for timeouts
 	doneOnce sync.Once
 	doneCh   chan struct{}
+
+	// filter is called on each datagram read from the client,
+	// the datagrams for which it returns false being discarded.
+	filter func(datagram []byte) bool
 }
 
 // readLoop waits for data to come from the listener's readLoop.
@@ -252,10 +303,31 @@ func (c *Conn) readLoop() {
 	}
 }
 
+// SetReadFilter sets the function called on each datagram read from the client,
+// the datagrams for which it returns false being discarded.
+// It must be called before the connection is read.
+func (c *Conn) SetReadFilter(filter func(datagram []byte) bool) {
+	c.filter = filter
+}
+
+// RemoteAddr returns the address of the client.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.rAddr
+}
+
 // Read reads up to len(p) bytes into p from the connection.
 // Each call corresponds to at most one datagram.
 // If p is smaller than the datagram, the extra bytes will be discarded.
 func (c *Conn) Read(p []byte) (int, error) {
+	for {
+		n, err := c.read(p)
+		if err != nil || c.filter == nil || c.filter(p[:n]) {
+			return n, err
+		}
+	}
+}
+
+func (c *Conn) read(p []byte) (int, error) {
 	select {
 	case c.readCh <- p:
 		n := <-c.sizeCh
@@ -277,6 +349,10 @@ func (c *Conn) Write(p []byte) (n int, err error) {
 	c.lastActivity = time.Now()
 	c.muActivity.Unlock()
 
+	if c.listener.pktInfoConn != nil {
+		return c.listener.pktInfoConn.writeTo(p, c.lIP, c.rAddr)
+	}
+
 	return c.listener.pConn.WriteTo(p, c.rAddr)
 }
 
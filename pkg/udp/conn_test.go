@@ -399,3 +399,48 @@ func requireEcho(t *testing.T, data string, conn io.ReadWriter, timeout time.Dur
 		t.Fatalf("Timeout during echo for: %s", data)
 	}
 }
+
+func TestListenWithOptions_replyFromDestination(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the whole 127.0.0.0/8 range is only routed to the loopback interface on Linux")
+	}
+
+	addr, err := net.ResolveUDPAddr("udp4", "0.0.0.0:0")
+	require.NoError(t, err)
+
+	ln, err := ListenWithOptions("udp4", addr, 3*time.Second, ListenOptions{ReplyFromDestination: true})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+
+		b := make([]byte, 2048)
+		n, err := conn.Read(b)
+		if err != nil {
+			return
+		}
+		_, _ = conn.Write(b[:n])
+	}()
+
+	client, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	// The client sends to a local address other than the one the system would pick to reply from.
+	dst := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 2), Port: ln.Addr().(*net.UDPAddr).Port}
+	_, err = client.WriteTo([]byte("TEST"), dst)
+	require.NoError(t, err)
+
+	require.NoError(t, client.SetReadDeadline(time.Now().Add(3*time.Second)))
+
+	b := make([]byte, 2048)
+	n, from, err := client.ReadFromUDP(b)
+	require.NoError(t, err)
+
+	assert.Equal(t, "TEST", string(b[:n]))
+	assert.True(t, dst.IP.Equal(from.IP), "reply sent from %s", from)
+}
@@ -0,0 +1,73 @@
+package udp
+
+import (
+	"net"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// pktInfoConn reads and writes the datagrams along with the local address they are received on, or sent from.
+type pktInfoConn interface {
+	readFrom(b []byte) (int, net.IP, net.Addr, error)
+	writeTo(b []byte, src net.IP, dst net.Addr) (int, error)
+}
+
+// newPktInfoConn enables the reception of the destination address of the datagrams on the given connection.
+func newPktInfoConn(conn *net.UDPConn) (pktInfoConn, error) {
+	if addr, ok := conn.LocalAddr().(*net.UDPAddr); ok && addr.IP.To4() != nil {
+		pConn := ipv4.NewPacketConn(conn)
+		if err := pConn.SetControlMessage(ipv4.FlagDst, true); err != nil {
+			return nil, err
+		}
+		return &pktInfoConn4{pConn: pConn}, nil
+	}
+
+	// The IPv6 packet information also covers the IPv4 datagrams received on a dual-stack socket,
+	// as IPv4-mapped addresses.
+	pConn := ipv6.NewPacketConn(conn)
+	if err := pConn.SetControlMessage(ipv6.FlagDst, true); err != nil {
+		return nil, err
+	}
+	return &pktInfoConn6{pConn: pConn}, nil
+}
+
+type pktInfoConn4 struct {
+	pConn *ipv4.PacketConn
+}
+
+func (c *pktInfoConn4) readFrom(b []byte) (int, net.IP, net.Addr, error) {
+	n, cm, src, err := c.pConn.ReadFrom(b)
+	if err != nil || cm == nil {
+		return n, nil, src, err
+	}
+	return n, cm.Dst, src, nil
+}
+
+func (c *pktInfoConn4) writeTo(b []byte, src net.IP, dst net.Addr) (int, error) {
+	var cm *ipv4.ControlMessage
+	if src != nil {
+		cm = &ipv4.ControlMessage{Src: src}
+	}
+	return c.pConn.WriteTo(b, cm, dst)
+}
+
+type pktInfoConn6 struct {
+	pConn *ipv6.PacketConn
+}
+
+func (c *pktInfoConn6) readFrom(b []byte) (int, net.IP, net.Addr, error) {
+	n, cm, src, err := c.pConn.ReadFrom(b)
+	if err != nil || cm == nil {
+		return n, nil, src, err
+	}
+	return n, cm.Dst, src, nil
+}
+
+func (c *pktInfoConn6) writeTo(b []byte, src net.IP, dst net.Addr) (int, error) {
+	var cm *ipv6.ControlMessage
+	if src != nil {
+		cm = &ipv6.ControlMessage{Src: src}
+	}
+	return c.pConn.WriteTo(b, cm, dst)
+}
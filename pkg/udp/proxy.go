@@ -4,6 +4,7 @@ import (
 	"io"
 	"net"
 
+	"github.com/traefik/traefik/v2/pkg/egress"
 	"github.com/traefik/traefik/v2/pkg/log"
 )
 
@@ -11,11 +12,14 @@ import (
 type Proxy struct {
 	// TODO: maybe optimize by pre-resolving it at proxy creation time
 	target string
+
+	egressPolicy *egress.Policy
 }
 
 // NewProxy creates a new Proxy.
-func NewProxy(address string) (*Proxy, error) {
-	return &Proxy{target: address}, nil
+// The egress policy, if any, restricts the destinations the address is dialed at.
+func NewProxy(address string, egressPolicy *egress.Policy) (*Proxy, error) {
+	return &Proxy{target: address, egressPolicy: egressPolicy}, nil
 }
 
 // ServeUDP implements the Handler interface.
@@ -25,7 +29,13 @@ func (p *Proxy) ServeUDP(conn *Conn) {
 	// needed because of e.g. server.trackedConnection
 	defer conn.Close()
 
-	connBackend, err := net.Dial("udp", p.target)
+	// The source address of the listener, if any, is set as the local address of the backend connection.
+	dialer := net.Dialer{Control: p.egressPolicy.Control()}
+	if conn.listener.sourceAddr != nil {
+		dialer.LocalAddr = conn.listener.sourceAddr
+	}
+
+	connBackend, err := dialer.Dial("udp", p.target)
 	if err != nil {
 		log.WithoutContext().Errorf("Error while dialing backend: %v", err)
 		return
@@ -24,7 +24,7 @@ func TestProxy_ServeUDP(t *testing.T) {
 		}
 	}))
 
-	proxy, err := NewProxy(backendAddr)
+	proxy, err := NewProxy(backendAddr, nil)
 	require.NoError(t, err)
 
 	proxyAddr := ":8080"
@@ -66,7 +66,7 @@ func TestProxy_ServeUDP_MaxDataSize(t *testing.T) {
 		require.NoError(t, err)
 	}))
 
-	proxy, err := NewProxy(backendAddr)
+	proxy, err := NewProxy(backendAddr, nil)
 	require.NoError(t, err)
 
 	proxyAddr := ":8082"
@@ -109,3 +109,58 @@ func newServer(t *testing.T, addr string, handler Handler) {
 		go handler.ServeUDP(conn)
 	}
 }
+
+func TestProxy_ServeUDP_SourceAddr(t *testing.T) {
+	// Reserves a port to send the datagrams to the backend from.
+	reserved, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	sourceAddr := reserved.LocalAddr().(*net.UDPAddr)
+	require.NoError(t, reserved.Close())
+
+	backendLn, err := Listen("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}, 3*time.Second)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backendLn.Close() })
+
+	go func() {
+		conn, err := backendLn.Accept()
+		if err != nil {
+			return
+		}
+
+		b := make([]byte, 2048)
+		if _, err := conn.Read(b); err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte(conn.RemoteAddr().String()))
+	}()
+
+	proxy, err := NewProxy(backendLn.Addr().String(), nil)
+	require.NoError(t, err)
+
+	ln, err := ListenWithOptions("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}, 3*time.Second, ListenOptions{SourceAddr: sourceAddr})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		proxy.ServeUDP(conn)
+	}()
+
+	udpConn, err := net.Dial("udp", ln.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = udpConn.Close() })
+
+	_, err = udpConn.Write([]byte("DATAWRITE"))
+	require.NoError(t, err)
+
+	require.NoError(t, udpConn.SetReadDeadline(time.Now().Add(3*time.Second)))
+
+	b := make([]byte, 2048)
+	n, err := udpConn.Read(b)
+	require.NoError(t, err)
+
+	assert.Equal(t, sourceAddr.String(), string(b[:n]))
+}
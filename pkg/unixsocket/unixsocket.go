@@ -0,0 +1,123 @@
+package unixsocket
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// Scheme is the scheme of the addresses of Unix domain sockets, such as unix:///var/run/app.sock.
+const Scheme = "unix"
+
+const prefix = Scheme + "://"
+
+// hostSuffix is the suffix of the hosts standing for a socket in the HTTP URLs built by ToHTTP.
+const hostSuffix = ".unix.localhost"
+
+// Path returns the path of the socket of a unix:// address, and whether the address is a unix:// one.
+func Path(address string) (string, bool) {
+	if !strings.HasPrefix(address, prefix) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(address, prefix), true
+}
+
+// ToHTTP returns the HTTP URL standing for the socket of a unix:// server URL,
+// so that the connections to the socket are pooled by the HTTP clients as the ones to any other server,
+// and so that the path of the socket is kept apart from the path of the requests.
+// Its host is dialed by the dial functions returned by DialContext.
+// The other URLs are returned as is.
+func ToHTTP(u *url.URL) *url.URL {
+	if u.Scheme != Scheme {
+		return u
+	}
+
+	return &url.URL{Scheme: "http", Host: hex.EncodeToString([]byte(u.Path)) + hostSuffix}
+}
+
+// FromHTTP returns the unix:// server URL of an HTTP URL built by ToHTTP.
+// The other URLs are returned as is.
+func FromHTTP(u *url.URL) *url.URL {
+	path, ok := hostPath(u.Host)
+	if !ok {
+		return u
+	}
+
+	return &url.URL{Scheme: Scheme, Path: path}
+}
+
+// IsHTTPHost reports whether the host, with or without a port, is the host of an HTTP URL built by ToHTTP.
+func IsHTTPHost(host string) bool {
+	_, ok := hostPath(host)
+	return ok
+}
+
+// hostPath returns the path of the socket the host of an HTTP URL built by ToHTTP stands for.
+func hostPath(host string) (string, bool) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	if !strings.HasSuffix(host, hostSuffix) {
+		return "", false
+	}
+
+	path, err := hex.DecodeString(strings.TrimSuffix(host, hostSuffix))
+	if err != nil {
+		return "", false
+	}
+
+	return string(path), true
+}
+
+// DialFunc is a function dialing an address on a network, as net.Dialer.DialContext.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// DialContext returns a dial function dialing the socket of the hosts of the HTTP URLs built by ToHTTP,
+// and dialing the other addresses with dial.
+func DialContext(dial DialFunc) DialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if path, ok := hostPath(addr); ok {
+			return dial(ctx, "unix", path)
+		}
+
+		return dial(ctx, network, addr)
+	}
+}
+
+// RemoveStale removes the socket at the given path, if nothing is listening on it anymore,
+// as the socket of a process which did not exit cleanly,
+// so that it can be listened on again.
+func RemoveStale(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if info.Mode().Type() != fs.ModeSocket {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("%s is already listened on", path)
+	}
+
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return err
+	}
+
+	return os.Remove(path)
+}
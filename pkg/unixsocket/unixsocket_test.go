@@ -0,0 +1,85 @@
+package unixsocket
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPath(t *testing.T) {
+	path, ok := Path("unix:///var/run/app.sock")
+	assert.True(t, ok)
+	assert.Equal(t, "/var/run/app.sock", path)
+
+	_, ok = Path("127.0.0.1:8080")
+	assert.False(t, ok)
+}
+
+func TestToHTTP(t *testing.T) {
+	u, err := url.Parse("unix:///var/run/app.sock")
+	require.NoError(t, err)
+
+	httpURL := ToHTTP(u)
+	assert.Equal(t, "http", httpURL.Scheme)
+	assert.True(t, IsHTTPHost(httpURL.Host))
+	assert.True(t, IsHTTPHost(httpURL.Host+":80"))
+	assert.Equal(t, u, FromHTTP(httpURL))
+
+	other, err := url.Parse("http://127.0.0.1:8080")
+	require.NoError(t, err)
+
+	assert.Same(t, other, ToHTTP(other))
+	assert.Same(t, other, FromHTTP(other))
+	assert.False(t, IsHTTPHost(other.Host))
+}
+
+func TestDialContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	var dialed []string
+	dial := DialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = append(dialed, network+" "+addr)
+		return nil, nil
+	})
+
+	_, _ = dial(context.Background(), "tcp", ToHTTP(&url.URL{Scheme: Scheme, Path: path}).Host+":80")
+	_, _ = dial(context.Background(), "tcp", "127.0.0.1:8080")
+
+	assert.Equal(t, []string{"unix " + path, "tcp 127.0.0.1:8080"}, dialed)
+}
+
+func TestRemoveStale(t *testing.T) {
+	dir := t.TempDir()
+
+	// A missing socket is nothing to remove.
+	require.NoError(t, RemoveStale(filepath.Join(dir, "missing.sock")))
+
+	file := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(file, nil, 0o600))
+	assert.Error(t, RemoveStale(file))
+
+	path := filepath.Join(dir, "app.sock")
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	// The socket is listened on.
+	assert.Error(t, RemoveStale(path))
+
+	// The socket is left behind, as by a process which did not exit cleanly.
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, listener.Close())
+
+	require.NoError(t, RemoveStale(path))
+	_, err = os.Lstat(path)
+	assert.True(t, os.IsNotExist(err))
+}
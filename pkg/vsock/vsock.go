@@ -0,0 +1,70 @@
+package vsock
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Scheme is the scheme of the addresses of VM sockets (AF_VSOCK), such as vsock://3:1024,
+// 3 being the context ID (CID) of the virtual machine, and 1024 the port listened on in it.
+const Scheme = "vsock"
+
+const prefix = Scheme + "://"
+
+// Conn is a connection to a VM socket.
+type Conn interface {
+	net.Conn
+
+	// CloseWrite shuts down the writing side of the connection.
+	CloseWrite() error
+}
+
+// Addr is the address of a VM socket.
+type Addr struct {
+	CID  uint32
+	Port uint32
+}
+
+// Network returns the name of the network of the address.
+func (a *Addr) Network() string {
+	return Scheme
+}
+
+// String returns the address as cid:port.
+func (a *Addr) String() string {
+	return fmt.Sprintf("%d:%d", a.CID, a.Port)
+}
+
+// ParseAddress returns the address of the socket of a vsock:// address, and whether the address is a vsock:// one.
+func ParseAddress(address string) (*Addr, bool, error) {
+	if !strings.HasPrefix(address, prefix) {
+		return nil, false, nil
+	}
+
+	cid, port, ok := strings.Cut(strings.TrimPrefix(address, prefix), ":")
+	if !ok {
+		return nil, true, fmt.Errorf("missing port in address %q", address)
+	}
+
+	addr := &Addr{}
+
+	id, err := strconv.ParseUint(cid, 10, 32)
+	if err != nil {
+		return nil, true, fmt.Errorf("invalid context ID in address %q: %w", address, err)
+	}
+	addr.CID = uint32(id)
+
+	p, err := strconv.ParseUint(port, 10, 32)
+	if err != nil {
+		return nil, true, fmt.Errorf("invalid port in address %q: %w", address, err)
+	}
+	addr.Port = uint32(p)
+
+	return addr, true, nil
+}
+
+// errUnsupported is returned when dialing a VM socket on a platform without AF_VSOCK.
+var errUnsupported = errors.New("VM sockets are only supported on Linux")
@@ -0,0 +1,182 @@
+//go:build linux
+// +build linux
+
+package vsock
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Dial connects to the VM socket at the given address.
+// The socket is non-blocking, so that its reads, writes and deadlines go through the runtime network poller,
+// as the ones of the net package connections.
+func Dial(addr *Addr) (Conn, error) {
+	fd, err := unix.Socket(unix.AF_VSOCK, unix.SOCK_STREAM|unix.SOCK_NONBLOCK|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, dialError(addr, os.NewSyscallError("socket", err))
+	}
+
+	// The file owns the descriptor from now on.
+	file := os.NewFile(uintptr(fd), "vsock:"+addr.String())
+
+	c, err := connect(file, addr)
+	if err != nil {
+		_ = file.Close()
+		return nil, dialError(addr, err)
+	}
+
+	return c, nil
+}
+
+func connect(file *os.File, addr *Addr) (*conn, error) {
+	rawConn, err := file.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	var connectErr error
+	err = rawConn.Control(func(fd uintptr) {
+		connectErr = unix.Connect(int(fd), &unix.SockaddrVM{CID: addr.CID, Port: addr.Port})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if errors.Is(connectErr, unix.EINPROGRESS) {
+		// Waits for the socket to be writable, which is when the connection is established or failed.
+		connectErr = nil
+		err = rawConn.Write(func(fd uintptr) bool {
+			if _, err := unix.Getpeername(int(fd)); err == nil {
+				return true
+			}
+
+			soErr, err := unix.GetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_ERROR)
+			if err != nil {
+				connectErr = err
+				return true
+			}
+			if soErr != 0 {
+				connectErr = syscall.Errno(soErr)
+				return true
+			}
+
+			return false
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if connectErr != nil {
+		return nil, os.NewSyscallError("connect", connectErr)
+	}
+
+	c := &conn{file: file, rawConn: rawConn, remote: addr}
+
+	err = rawConn.Control(func(fd uintptr) {
+		if sa, err := unix.Getsockname(int(fd)); err == nil {
+			if vm, ok := sa.(*unix.SockaddrVM); ok {
+				c.local = &Addr{CID: vm.CID, Port: vm.Port}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// conn is a connection to a VM socket,
+// the errors of which are net.OpError ones, as the ones of the net package connections.
+type conn struct {
+	file    *os.File
+	rawConn syscall.RawConn
+	local   *Addr
+	remote  *Addr
+}
+
+func (c *conn) Read(p []byte) (int, error) {
+	n, err := c.file.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return n, c.opError("read", err)
+	}
+
+	return n, err
+}
+
+func (c *conn) Write(p []byte) (int, error) {
+	n, err := c.file.Write(p)
+	if err != nil {
+		return n, c.opError("write", err)
+	}
+
+	return n, nil
+}
+
+func (c *conn) Close() error {
+	if err := c.file.Close(); err != nil {
+		return c.opError("close", err)
+	}
+
+	return nil
+}
+
+func (c *conn) CloseWrite() error {
+	var shutdownErr error
+	err := c.rawConn.Control(func(fd uintptr) {
+		shutdownErr = unix.Shutdown(int(fd), unix.SHUT_WR)
+	})
+	if err == nil && shutdownErr != nil {
+		err = os.NewSyscallError("shutdown", shutdownErr)
+	}
+	if err != nil {
+		return c.opError("close", err)
+	}
+
+	return nil
+}
+
+func (c *conn) LocalAddr() net.Addr {
+	if c.local == nil {
+		return nil
+	}
+
+	return c.local
+}
+
+func (c *conn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+func (c *conn) SetDeadline(t time.Time) error {
+	return c.file.SetDeadline(t)
+}
+
+func (c *conn) SetReadDeadline(t time.Time) error {
+	return c.file.SetReadDeadline(t)
+}
+
+func (c *conn) SetWriteDeadline(t time.Time) error {
+	return c.file.SetWriteDeadline(t)
+}
+
+func (c *conn) opError(op string, err error) error {
+	// Unwraps the os.PathError of the file, which does not say anything more than the connection.
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		err = pathErr.Err
+	}
+
+	return &net.OpError{Op: op, Net: Scheme, Source: c.LocalAddr(), Addr: c.remote, Err: err}
+}
+
+func dialError(addr *Addr, err error) error {
+	return &net.OpError{Op: "dial", Net: Scheme, Addr: addr, Err: err}
+}
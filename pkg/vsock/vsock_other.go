@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package vsock
+
+import "net"
+
+// Dial connects to the VM socket at the given address.
+func Dial(addr *Addr) (Conn, error) {
+	return nil, &net.OpError{Op: "dial", Net: Scheme, Addr: addr, Err: errUnsupported}
+}
@@ -0,0 +1,75 @@
+package vsock
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddress(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		address     string
+		expected    *Addr
+		expectVsock bool
+		expectErr   bool
+	}{
+		{
+			desc:        "vsock address",
+			address:     "vsock://3:1024",
+			expected:    &Addr{CID: 3, Port: 1024},
+			expectVsock: true,
+		},
+		{
+			desc:        "any port",
+			address:     "vsock://2:4294967295",
+			expected:    &Addr{CID: 2, Port: 4294967295},
+			expectVsock: true,
+		},
+		{
+			desc:    "TCP address",
+			address: "127.0.0.1:80",
+		},
+		{
+			desc:    "Unix domain socket address",
+			address: "unix:///var/run/app.sock",
+		},
+		{
+			desc:        "missing port",
+			address:     "vsock://3",
+			expectVsock: true,
+			expectErr:   true,
+		},
+		{
+			desc:        "invalid context ID",
+			address:     "vsock://vm:1024",
+			expectVsock: true,
+			expectErr:   true,
+		},
+		{
+			desc:        "out of range port",
+			address:     "vsock://3:4294967296",
+			expectVsock: true,
+			expectErr:   true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			addr, isVsock, err := ParseAddress(test.address)
+			assert.Equal(t, test.expectVsock, isVsock)
+
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, addr)
+		})
+	}
+}